@@ -0,0 +1,182 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errNoMetadata        = errors.New("expected metadata in archive but doesn't exist")
+	errNoArchiveReceipts = errors.New("the archive has no receipts, it has to be restored by the node " +
+		"with the restore flag")
+	errDivergingArchive  = errors.New("the archive diverges from the local chain")
+	errIncompleteArchive = errors.New("the archive doesn't reach its latest block")
+)
+
+// ChainArchiveStats is the number of the entries written to or read from a chain archive
+type ChainArchiveStats struct {
+	Blocks     uint64
+	StateNodes uint64
+	Code       uint64
+}
+
+// ExportChain writes the canonical blocks between from and to included, from the storage of a stopped
+// node, in the archive format restored by the node. The receipts of the blocks, and the state of the
+// latest one read from the given state storage, are written if requested. An archive with the receipts
+// can be imported offline by ImportChain
+func ExportChain(
+	db storage.Storage,
+	st itrie.Storage,
+	writer io.Writer,
+	from, to uint64,
+	withReceipts, withState bool,
+) (*ChainArchiveStats, error) {
+	latest, _, err := blockchain.ReadCanonicalBlock(db, to)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{
+		Latest:     to,
+		LatestHash: latest.Hash(),
+		Receipts:   withReceipts,
+		State:      withState,
+	}
+
+	if _, err := writer.Write(metadata.MarshalRLP()); err != nil {
+		return nil, err
+	}
+
+	stats := &ChainArchiveStats{}
+
+	for number := from; number <= to; number++ {
+		block, receipts, err := blockchain.ReadCanonicalBlock(db, number)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := writer.Write(block.MarshalRLP()); err != nil {
+			return nil, err
+		}
+
+		if withReceipts {
+			if _, err := writer.Write(types.Receipts(receipts).MarshalStoreRLPTo(nil)); err != nil {
+				return nil, err
+			}
+		}
+
+		stats.Blocks++
+	}
+
+	if !withState {
+		return stats, nil
+	}
+
+	err = itrie.WalkState(st, latest.Header.StateRoot, func(entry *itrie.StateEntry) error {
+		if entry.Code {
+			stats.Code++
+		} else {
+			stats.StateNodes++
+		}
+
+		_, err := writer.Write(types.MarshalRLPTo((*stateEntry)(entry).MarshalRLPWith, nil))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export the state of block %d: %w", to, err)
+	}
+
+	return stats, nil
+}
+
+// ImportChain writes the blocks of an archive with receipts to the storage of a stopped node, and the
+// state of the latest one to the given state storage if the archive has it. The blocks already in the
+// local chain are skipped, the following ones are written on top of its head without being executed.
+// The node re-executes the blocks whose state is missing on startup
+func ImportChain(db storage.Storage, st itrie.Storage, reader io.Reader) (*ChainArchiveStats, error) {
+	blockStream := newBlockStream(reader)
+
+	metadata, err := blockStream.getMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		return nil, errNoMetadata
+	}
+
+	if !metadata.Receipts {
+		return nil, errNoArchiveReceipts
+	}
+
+	head, ok := db.ReadHeadNumber()
+	if !ok {
+		return nil, errors.New("no head found in the local chain")
+	}
+
+	stats := &ChainArchiveStats{}
+
+	for {
+		block, receipts, err := blockStream.nextBlockWithReceipts()
+		if err != nil {
+			return nil, err
+		}
+
+		if block == nil {
+			break
+		}
+
+		if block.Number() <= head {
+			// the blocks of the local chain are skipped, the archive has to extend it
+			if hash, ok := db.ReadCanonicalHash(block.Number()); !ok || hash != block.Hash() {
+				return nil, fmt.Errorf("%w: block %d", errDivergingArchive, block.Number())
+			}
+
+			continue
+		}
+
+		if err := blockchain.ImportBlock(db, block, receipts); err != nil {
+			return nil, fmt.Errorf("failed to import block %d: %w", block.Number(), err)
+		}
+
+		stats.Blocks++
+	}
+
+	if hash, ok := db.ReadCanonicalHash(metadata.Latest); !ok || hash != metadata.LatestHash {
+		return nil, fmt.Errorf("%w: block %d", errIncompleteArchive, metadata.Latest)
+	}
+
+	if !metadata.State {
+		return stats, nil
+	}
+
+	for {
+		entry, err := blockStream.nextStateEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		if entry == nil {
+			break
+		}
+
+		if err := itrie.WriteStateEntry(st, entry); err != nil {
+			return nil, err
+		}
+
+		if entry.Code {
+			stats.Code++
+		} else {
+			stats.StateNodes++
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestChainStorage returns a storage with the given chain, written without the state
+func newTestChainStorage(t *testing.T, headers []*types.Header) storage.Storage {
+	t.Helper()
+
+	db, err := memory.NewMemoryStorage(nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.WriteCanonicalHeader(headers[0], big.NewInt(0)))
+
+	for _, header := range headers[1:] {
+		assert.NoError(t, blockchain.ImportBlock(db, &types.Block{Header: header}, []*types.Receipt{}))
+	}
+
+	return db
+}
+
+func TestExportImportChain(t *testing.T) {
+	t.Parallel()
+
+	addr := types.StringToAddress("1")
+
+	// the state of the latest block
+	srcState := itrie.NewMemoryStorage()
+
+	txn := state.NewTxn(itrie.NewState(srcState), itrie.NewState(srcState).NewSnapshot())
+	txn.AddBalance(addr, big.NewInt(1))
+	txn.SetCode(addr, []byte{0x1})
+
+	_, root := txn.Commit(false)
+
+	headers := blockchain.NewTestHeaders(4)
+	headers[3].StateRoot = types.BytesToHash(root)
+	headers[3].ComputeHash()
+
+	src := newTestChainStorage(t, headers)
+
+	var buf bytes.Buffer
+
+	stats, err := ExportChain(src, srcState, &buf, 0, 3, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), stats.Blocks)
+	assert.NotZero(t, stats.StateNodes)
+	assert.Equal(t, uint64(1), stats.Code)
+
+	// the local chain has the first blocks already
+	dst := newTestChainStorage(t, headers[:2])
+	dstState := itrie.NewMemoryStorage()
+
+	imported, err := ImportChain(dst, dstState, bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), imported.Blocks)
+	assert.Equal(t, stats.StateNodes, imported.StateNodes)
+	assert.Equal(t, stats.Code, imported.Code)
+
+	head, ok := dst.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[3].Hash, head)
+
+	snap, err := itrie.NewState(dstState).NewSnapshotAt(headers[3].StateRoot)
+	assert.NoError(t, err)
+
+	txn = state.NewTxn(itrie.NewState(dstState), snap)
+	assert.Equal(t, big.NewInt(1), txn.GetBalance(addr))
+	assert.Equal(t, []byte{0x1}, txn.GetCode(addr))
+
+	// the node restores the blocks of the archive, skipping the receipts and the state
+	blockStream := newBlockStream(bytes.NewReader(buf.Bytes()))
+
+	metadata, err := blockStream.getMetadata()
+	assert.NoError(t, err)
+	assert.True(t, metadata.Receipts)
+	assert.True(t, metadata.State)
+
+	for _, header := range headers {
+		block, err := blockStream.nextBlock()
+		assert.NoError(t, err)
+		assert.Equal(t, header.Hash, block.Hash())
+	}
+
+	block, err := blockStream.nextBlock()
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+}
+
+func TestImportChain_Errors(t *testing.T) {
+	t.Parallel()
+
+	headers := blockchain.NewTestHeaders(3)
+	src := newTestChainStorage(t, headers)
+
+	export := func(withReceipts bool) []byte {
+		var buf bytes.Buffer
+
+		_, err := ExportChain(src, nil, &buf, 0, 2, withReceipts, false)
+		assert.NoError(t, err)
+
+		return buf.Bytes()
+	}
+
+	// the blocks without receipts have to be executed by the node
+	dst := newTestChainStorage(t, headers[:1])
+
+	_, err := ImportChain(dst, nil, bytes.NewReader(export(false)))
+	assert.ErrorIs(t, err, errNoArchiveReceipts)
+
+	// another chain isn't imported
+	other := newTestChainStorage(t, blockchain.NewTestHeadersWithSeed(nil, 2, 1))
+
+	_, err = ImportChain(other, nil, bytes.NewReader(export(true)))
+	assert.ErrorIs(t, err, errDivergingArchive)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -17,6 +18,8 @@ const (
 	restore = "restore"
 )
 
+var errMissingReceipts = errors.New("the receipts of the block are missing")
+
 type blockchainInterface interface {
 	SubscribeEvents() blockchain.Subscription
 	Genesis() types.Hash
@@ -48,7 +51,7 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 	}
 
 	if metadata == nil {
-		return errors.New("expected metadata in archive but doesn't exist")
+		return errNoMetadata
 	}
 
 	// check whether the local chain has the latest block already
@@ -152,6 +155,11 @@ func consumeCommonBlocks(
 type blockStream struct {
 	input  io.Reader
 	buffer []byte
+
+	// metadata is the metadata read from the stream, once read
+	metadata *Metadata
+	// latestRead is set once the latest block of the metadata is read
+	latestRead bool
 }
 
 func newBlockStream(input io.Reader) *blockStream {
@@ -172,26 +180,83 @@ func (b *blockStream) getMetadata() (*Metadata, error) {
 		return nil, nil
 	}
 
-	return b.parseMetadata(size)
+	metadata, err := b.parseMetadata(size)
+	if err != nil {
+		return nil, err
+	}
+
+	b.metadata = metadata
+
+	return metadata, nil
 }
 
-// nextBlock consumes some bytes from input and returns parsed block
+// nextBlock consumes some bytes from input and returns parsed block, its receipts are skipped
 func (b *blockStream) nextBlock() (*types.Block, error) {
+	block, _, err := b.nextBlockWithReceipts()
+
+	return block, err
+}
+
+// nextBlockWithReceipts consumes some bytes from input and returns parsed block, with its receipts
+// if the archive has them. No block is returned past the latest one, the state may follow it
+func (b *blockStream) nextBlockWithReceipts() (*types.Block, []*types.Receipt, error) {
+	if b.latestRead {
+		return nil, nil, nil
+	}
+
 	size, err := b.loadRLPArray()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if size == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	block, err := b.parseBlock(size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if b.metadata == nil {
+		return block, nil, nil
+	}
+
+	b.latestRead = block.Number() >= b.metadata.Latest
+
+	if !b.metadata.Receipts {
+		return block, nil, nil
+	}
+
+	if size, err = b.loadRLPArray(); err != nil {
+		return nil, nil, err
+	}
+
+	if size == 0 {
+		return nil, nil, fmt.Errorf("%w: block %d", errMissingReceipts, block.Number())
+	}
+
+	receipts, err := b.parseReceipts(size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, receipts, nil
+}
+
+// nextStateEntry consumes some bytes from input and returns parsed state entry,
+// the state follows the blocks
+func (b *blockStream) nextStateEntry() (*itrie.StateEntry, error) {
+	size, err := b.loadRLPArray()
 	if err != nil {
 		return nil, err
 	}
 
-	return block, nil
+	if size == 0 {
+		return nil, nil
+	}
+
+	return b.parseStateEntry(size)
 }
 
 // loadRLPArray loads RLP encoded array from input to buffer
@@ -296,6 +361,30 @@ func (b *blockStream) parseBlock(size uint64) (*types.Block, error) {
 	return block, nil
 }
 
+// parseReceipts parses RLP encoded receipts in buffer
+func (b *blockStream) parseReceipts(size uint64) ([]*types.Receipt, error) {
+	data := b.buffer[:size]
+	receipts := types.Receipts{}
+
+	if err := receipts.UnmarshalStoreRLP(data); err != nil {
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// parseStateEntry parses RLP encoded state entry in buffer
+func (b *blockStream) parseStateEntry(size uint64) (*itrie.StateEntry, error) {
+	data := b.buffer[:size]
+	entry := &stateEntry{}
+
+	if err := entry.UnmarshalRLP(data); err != nil {
+		return nil, err
+	}
+
+	return (*itrie.StateEntry)(entry), nil
+}
+
 // reserveCap makes sure the internal buffer has given size
 func (b *blockStream) reserveCap(size uint64) {
 	if diff := int64(size) - int64(cap(b.buffer)); diff > 0 {
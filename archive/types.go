@@ -3,6 +3,7 @@ package archive
 import (
 	"fmt"
 
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -11,6 +12,11 @@ import (
 type Metadata struct {
 	Latest     uint64
 	LatestHash types.Hash
+
+	// Receipts is set if the receipts of each block follow it
+	Receipts bool
+	// State is set if the state of the latest block follows the blocks
+	State bool
 }
 
 // MarshalRLP returns RLP encoded bytes
@@ -29,6 +35,11 @@ func (m *Metadata) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	vv.Set(arena.NewUint(m.Latest))
 	vv.Set(arena.NewBytes(m.LatestHash.Bytes()))
+	// the archives without flags are encoded like the previous versions
+	if m.Receipts || m.State {
+		vv.Set(arena.NewBool(m.Receipts))
+		vv.Set(arena.NewBool(m.State))
+	}
 
 	return vv
 }
@@ -57,5 +68,63 @@ func (m *Metadata) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 		return err
 	}
 
+	// the backups of the previous versions have no flags
+	if len(elems) < 4 {
+		return nil
+	}
+
+	if m.Receipts, err = elems[2].GetBool(); err != nil {
+		return err
+	}
+
+	if m.State, err = elems[3].GetBool(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// stateEntry is a trie node or a contract code of the state following the blocks
+type stateEntry itrie.StateEntry
+
+// MarshalRLPWith appends own field into arena for encode
+func (e *stateEntry) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBool(e.Code))
+	vv.Set(arena.NewBytes(e.Hash.Bytes()))
+	vv.Set(arena.NewBytes(e.Value))
+
+	return vv
+}
+
+// UnmarshalRLP unmarshals and sets the fields from RLP encoded bytes
+func (e *stateEntry) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(e.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom sets the fields from parsed RLP encoded value
+func (e *stateEntry) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 3 {
+		return fmt.Errorf("incorrect number of elements to decode state entry, expected 3 but found %d", len(elems))
+	}
+
+	if e.Code, err = elems[0].GetBool(); err != nil {
+		return err
+	}
+
+	if err = elems[1].GetHash(e.Hash[:]); err != nil {
+		return err
+	}
+
+	if e.Value, err = elems[2].GetBytes(nil); err != nil {
+		return err
+	}
+
 	return nil
 }
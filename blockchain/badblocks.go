@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// maxBadBlocks is the number of rejected blocks kept in the bad block journal, the oldest being dropped
+const maxBadBlocks = 32
+
+// recordBadBlock persists the block rejected by the verification or the execution along with the reason,
+// so that the consensus failures can be diagnosed after the fact. The blocks not following a local block
+// aren't recorded, as they aren't checked
+func (b *Blockchain) recordBadBlock(block *types.Block, reason error) {
+	if block == nil || block.Header == nil || errors.Is(reason, ErrParentNotFound) {
+		return
+	}
+
+	b.badBlocksLock.Lock()
+	defer b.badBlocksLock.Unlock()
+
+	hashes, err := b.db.ReadBadBlockHashes()
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		b.logger.Error("failed to read the bad block journal", "err", err)
+
+		return
+	}
+
+	hash := block.Hash()
+
+	for _, h := range hashes {
+		if h == hash {
+			// the block was already rejected
+			return
+		}
+	}
+
+	if err := writeBadBlock(b.db, hashes, &storage.BadBlock{
+		Block:  block,
+		Reason: reason.Error(),
+		Time:   uint64(time.Now().Unix()),
+	}); err != nil {
+		b.logger.Error("failed to write the bad block", "number", block.Number(), "hash", hash, "err", err)
+
+		return
+	}
+
+	b.logger.Warn("recorded bad block", "number", block.Number(), "hash", hash, "reason", reason)
+}
+
+// writeBadBlock appends the block to the journal with the given hashes, dropping the oldest blocks
+// over the maximum, in a single batch
+func writeBadBlock(db storage.Storage, hashes []types.Hash, bad *storage.BadBlock) error {
+	batch := db.NewWriteBatch()
+
+	hashes = append(hashes, bad.Block.Hash())
+
+	for len(hashes) > maxBadBlocks {
+		if err := batch.DeleteBadBlock(hashes[0]); err != nil {
+			return err
+		}
+
+		hashes = hashes[1:]
+	}
+
+	if err := batch.WriteBadBlock(bad); err != nil {
+		return err
+	}
+
+	if err := batch.WriteBadBlockHashes(hashes); err != nil {
+		return err
+	}
+
+	return batch.Write()
+}
+
+// BadBlocks returns the blocks rejected by the verification or the execution, the latest first
+func (b *Blockchain) BadBlocks() ([]*storage.BadBlock, error) {
+	b.badBlocksLock.Lock()
+	defer b.badBlocksLock.Unlock()
+
+	return ReadBadBlocks(b.db)
+}
+
+// ReadBadBlocks reads the bad block journal of the storage, the latest block first
+func ReadBadBlocks(db storage.Storage) ([]*storage.BadBlock, error) {
+	hashes, err := db.ReadBadBlockHashes()
+	if errors.Is(err, storage.ErrNotFound) {
+		return []*storage.BadBlock{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	bads := make([]*storage.BadBlock, 0, len(hashes))
+
+	for i := len(hashes) - 1; i >= 0; i-- {
+		bad, err := db.ReadBadBlock(hashes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		bads = append(bads, bad)
+	}
+
+	return bads, nil
+}
@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchain_BadBlocks(t *testing.T) {
+	t.Parallel()
+
+	b := NewTestBlockchain(t, nil)
+	verifier, _ := b.consensus.(*MockVerifier)
+
+	errInvalidSeal := errors.New("invalid seal")
+
+	verifier.HookVerifyHeader(func(header *types.Header) error {
+		return errInvalidSeal
+	})
+
+	newBlock := func(seed byte) *types.Block {
+		header := &types.Header{
+			Number:     1,
+			ParentHash: b.Header().Hash,
+			ExtraData:  []byte{seed},
+		}
+		header.ComputeHash()
+
+		return &types.Block{Header: header}
+	}
+
+	block := newBlock(0)
+
+	assert.ErrorIs(t, b.VerifyFinalizedBlock(block), errInvalidSeal)
+
+	// the block is recorded once
+	assert.Error(t, b.VerifyFinalizedBlock(block))
+
+	bads, err := b.BadBlocks()
+	assert.NoError(t, err)
+
+	if assert.Len(t, bads, 1) {
+		assert.Equal(t, block.Hash(), bads[0].Block.Hash())
+		assert.Contains(t, bads[0].Reason, errInvalidSeal.Error())
+		assert.NotZero(t, bads[0].Time)
+	}
+
+	// the oldest blocks are dropped, the latest is returned first
+	for seed := 1; seed <= maxBadBlocks; seed++ {
+		assert.Error(t, b.VerifyFinalizedBlock(newBlock(byte(seed))))
+	}
+
+	bads, err = b.BadBlocks()
+	assert.NoError(t, err)
+	assert.Len(t, bads, maxBadBlocks)
+	assert.Equal(t, newBlock(maxBadBlocks).Hash(), bads[0].Block.Hash())
+	assert.Equal(t, newBlock(1).Hash(), bads[maxBadBlocks-1].Block.Hash())
+
+	// the blocks not following a local block aren't recorded
+	verifier.HookVerifyHeader(func(header *types.Header) error {
+		return nil
+	})
+
+	orphan := &types.Block{Header: &types.Header{Number: 2, ParentHash: types.StringToHash("1")}}
+	orphan.Header.ComputeHash()
+
+	assert.ErrorIs(t, b.VerifyFinalizedBlock(orphan), ErrParentNotFound)
+
+	bads, err = b.BadBlocks()
+	assert.NoError(t, err)
+	assert.Equal(t, newBlock(maxBadBlocks).Hash(), bads[0].Block.Hash())
+}
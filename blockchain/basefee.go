@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// ErrInvalidBaseFee is returned when the base fee of a block isn't the one derived from its parent
+var ErrInvalidBaseFee = errors.New("invalid block base fee")
+
+// isLondon returns whether the block with the given number is from the London fork
+func (b *Blockchain) isLondon(number uint64) bool {
+	forks := b.Config().Forks
+
+	return forks != nil && forks.IsLondon(number)
+}
+
+// CalculateBaseFee returns the base fee of the next block after parent, zero before the London fork
+func (b *Blockchain) CalculateBaseFee(parent *types.Header) uint64 {
+	if !b.isLondon(parent.Number + 1) {
+		return 0
+	}
+
+	if !b.isLondon(parent.Number) {
+		// the fork block
+		return chain.InitialBaseFee
+	}
+
+	return calculateBaseFee(parent)
+}
+
+// calculateBaseFee derives the base fee from the one of the parent block (EIP-1559),
+// raising it while the parent uses more than its gas target, half its gas limit, and lowering it while it uses less.
+// The base fee moves by at most 1/8 from a block to the next
+func calculateBaseFee(parent *types.Header) uint64 {
+	target := parent.GasLimit / chain.ElasticityMultiplier
+	if target == 0 || parent.GasUsed == target {
+		return parent.BaseFee
+	}
+
+	parentBaseFee := new(big.Int).SetUint64(parent.BaseFee)
+
+	if parent.GasUsed > target {
+		delta := new(big.Int).SetUint64(parent.GasUsed - target)
+		delta.Mul(delta, parentBaseFee)
+		delta.Div(delta, new(big.Int).SetUint64(target))
+		delta.Div(delta, big.NewInt(chain.BaseFeeChangeDenom))
+
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+
+		return parentBaseFee.Add(parentBaseFee, delta).Uint64()
+	}
+
+	delta := new(big.Int).SetUint64(target - parent.GasUsed)
+	delta.Mul(delta, parentBaseFee)
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(chain.BaseFeeChangeDenom))
+
+	if delta.Cmp(parentBaseFee) >= 0 {
+		return 0
+	}
+
+	return parentBaseFee.Sub(parentBaseFee, delta).Uint64()
+}
+
+// SetBaseFee sets the base fee of the header following the given parent, and whether it is
+// from the London fork, so that a zero base fee is still encoded from the fork
+func (b *Blockchain) SetBaseFee(header *types.Header, parent *types.Header) {
+	header.BaseFee = b.CalculateBaseFee(parent)
+	header.London = b.isLondon(header.Number)
+}
+
+// verifyBaseFee checks that the base fee of the header is the one derived from its parent
+func (b *Blockchain) verifyBaseFee(header *types.Header, parent *types.Header) error {
+	if london := b.isLondon(header.Number); header.London != london {
+		return fmt.Errorf("%w, london fork %t, want %t", ErrInvalidBaseFee, header.London, london)
+	}
+
+	if expected := b.CalculateBaseFee(parent); header.BaseFee != expected {
+		return fmt.Errorf("%w, have %d, want %d", ErrInvalidBaseFee, header.BaseFee, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateBaseFee(t *testing.T) {
+	t.Parallel()
+
+	const (
+		gasLimit = 20000000
+		baseFee  = chain.InitialBaseFee
+	)
+
+	b := &Blockchain{
+		config: &chain.Chain{
+			Params: &chain.Params{
+				Forks: &chain.Forks{London: chain.NewFork(5)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		parent          *types.Header
+		expectedBaseFee uint64
+	}{
+		{
+			name:            "should have no base fee before the fork",
+			parent:          &types.Header{Number: 3, GasLimit: gasLimit},
+			expectedBaseFee: 0,
+		},
+		{
+			name:            "should start at the initial base fee at the fork",
+			parent:          &types.Header{Number: 4, GasLimit: gasLimit},
+			expectedBaseFee: chain.InitialBaseFee,
+		},
+		{
+			name:            "should not alter the base fee when the parent uses its gas target",
+			parent:          &types.Header{Number: 5, GasLimit: gasLimit, GasUsed: gasLimit / 2, BaseFee: baseFee},
+			expectedBaseFee: baseFee,
+		},
+		{
+			name:            "should raise the base fee by 1/8 when the parent is full",
+			parent:          &types.Header{Number: 5, GasLimit: gasLimit, GasUsed: gasLimit, BaseFee: baseFee},
+			expectedBaseFee: baseFee + baseFee/8,
+		},
+		{
+			name:            "should lower the base fee by 1/8 when the parent is empty",
+			parent:          &types.Header{Number: 5, GasLimit: gasLimit, BaseFee: baseFee},
+			expectedBaseFee: baseFee - baseFee/8,
+		},
+		{
+			name:            "should raise the base fee by at least 1",
+			parent:          &types.Header{Number: 5, GasLimit: gasLimit, GasUsed: gasLimit/2 + 1, BaseFee: 7},
+			expectedBaseFee: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expectedBaseFee, b.CalculateBaseFee(tt.parent))
+		})
+	}
+}
+
+func TestVerifyBaseFee(t *testing.T) {
+	t.Parallel()
+
+	b := &Blockchain{
+		config: &chain.Chain{
+			Params: &chain.Params{
+				Forks: &chain.Forks{London: chain.NewFork(0)},
+			},
+		},
+	}
+
+	// the parent is empty, the base fee decreasing
+	parent := &types.Header{Number: 1, GasLimit: 20000000, BaseFee: chain.InitialBaseFee}
+	header := &types.Header{Number: 2, BaseFee: chain.InitialBaseFee - chain.InitialBaseFee/8, London: true}
+
+	assert.NoError(t, b.verifyBaseFee(header, parent))
+
+	header.BaseFee = chain.InitialBaseFee
+	assert.ErrorIs(t, b.verifyBaseFee(header, parent), ErrInvalidBaseFee)
+
+	// the headers of the fork encode their base fee
+	header = &types.Header{Number: 2}
+	b.SetBaseFee(header, parent)
+
+	assert.True(t, header.London)
+	assert.NoError(t, b.verifyBaseFee(header, parent))
+
+	header.London = false
+	assert.ErrorIs(t, b.verifyBaseFee(header, parent), ErrInvalidBaseFee)
+}
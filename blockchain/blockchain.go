@@ -3,17 +3,19 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
-	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-edge/chain"
-	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/0xPolygon/polygon-edge/types/buildroot"
 
@@ -38,6 +40,8 @@ var (
 	ErrInvalidStateRoot     = errors.New("invalid block state root")
 	ErrInvalidGasUsed       = errors.New("invalid block gas used")
 	ErrInvalidReceiptsRoot  = errors.New("invalid block receipts root")
+	ErrInvalidDiscardRange  = errors.New("invalid first block to discard")
+	ErrInvalidTxSignature   = errors.New("invalid transaction signature")
 )
 
 // Blockchain is a blockchain reference
@@ -65,14 +69,53 @@ type Blockchain struct {
 	// any new fields from being added
 	receiptsCache *lru.Cache // LRU cache for the block receipts
 
+	opcodeStatsCache *lru.Cache // LRU cache for the opcode statistics of the executed blocks
+	sendersCache     *lru.Cache // LRU cache for the recovered transaction senders, by transaction hash
+	stateDiffsCache  *lru.Cache // LRU cache for the accounts modified by the executed blocks
+	feesCache        *lru.Cache // LRU cache for the fees paid in the executed blocks
+
 	currentHeader     atomic.Value // The current header
 	currentDifficulty atomic.Value // The current difficulty of the chain (total difficulty)
 
+	finalizedHeader atomic.Value // The latest header finalized by the consensus engine
+	safeHeader      atomic.Value // The latest header marked as safe by the consensus engine
+	finalityLock    sync.Mutex   // Serializes the updates of the finalized and the safe headers
+
 	stream *eventStream // Event subscriptions
+	bus    *EventBus    // Typed event subscriptions
+
+	// busLock serializes the publishing of the typed events, and guards the finality tracking
+	busLock       sync.Mutex
+	nextFinalized uint64 // The number of the next header to publish as finalized
+	finalizedInit bool   // Whether the finality is tracked, from the first head deep enough
 
 	gpAverage *gasPriceAverage // A reference to the average gas price
 
+	gasTarget gasTargetOverride // The block gas target set at runtime, if any
+
+	chainStats *chainStats // Rolling aggregates of the recent blocks
+
+	// Number of recent blocks whose bodies and receipts are kept, all are kept if zero
+	retainedBlocks uint64
+
+	// Number of recent blocks kept in the key-value storage, the older ones being
+	// moved to the freezer. No block is frozen if zero
+	freezeThreshold uint64
+
+	// Number of recent blocks whose transactions are looked up by hash, all are if zero
+	txLookupLimit uint64
+
+	futureBlocks *futureBlockQueue // The finalized blocks waiting for their time to be written
+
+	metrics *Metrics
+
 	writeLock sync.Mutex
+
+	// badBlocksLock serializes the updates of the bad block journal
+	badBlocksLock sync.Mutex
+
+	blockHooks     []BlockHook  // The hooks notified of the executed and written blocks
+	blockHooksLock sync.RWMutex // Lock for the block hooks
 }
 
 // gasPriceAverage keeps track of the average gas price (rolling average)
@@ -182,20 +225,26 @@ func (b *Blockchain) GetAvgGasPrice() *big.Int {
 func NewBlockchain(
 	logger hclog.Logger,
 	dataDir string,
+	engine storage.Engine,
 	config *chain.Chain,
 	consensus Verifier,
 	executor Executor,
+	metrics *Metrics,
 ) (*Blockchain, error) {
 	b := &Blockchain{
 		logger:    logger.Named("blockchain"),
 		config:    config,
 		consensus: consensus,
 		executor:  executor,
+		metrics:   metrics,
 		stream:    &eventStream{},
+		bus:       NewEventBus(logger.Named("eventbus")),
 		gpAverage: &gasPriceAverage{
 			price: big.NewInt(0),
 			count: big.NewInt(0),
 		},
+		chainStats:   newChainStats(),
+		futureBlocks: newFutureBlockQueue(),
 	}
 
 	var (
@@ -208,12 +257,12 @@ func NewBlockchain(
 			return nil, err
 		}
 	} else {
-		if db, err = leveldb.NewLevelDBStorage(
-			filepath.Join(dataDir, "blockchain"),
-			logger,
-		); err != nil {
-			return nil, err
+		kv, openErr := OpenDatabase(filepath.Join(dataDir, "blockchain"), engine)
+		if openErr != nil {
+			return nil, openErr
 		}
+
+		db = storage.NewKeyValueStorage(logger.Named(engine.String()), kv)
 	}
 
 	b.db = db
@@ -247,6 +296,26 @@ func (b *Blockchain) initCaches(size int) error {
 		return fmt.Errorf("unable to create receipts cache, %w", err)
 	}
 
+	b.opcodeStatsCache, err = lru.New(size)
+	if err != nil {
+		return fmt.Errorf("unable to create opcode statistics cache, %w", err)
+	}
+
+	b.stateDiffsCache, err = lru.New(size)
+	if err != nil {
+		return fmt.Errorf("unable to create state diffs cache, %w", err)
+	}
+
+	b.feesCache, err = lru.New(size)
+	if err != nil {
+		return fmt.Errorf("unable to create fees cache, %w", err)
+	}
+
+	b.sendersCache, err = lru.New(sendersCacheSize)
+	if err != nil {
+		return fmt.Errorf("unable to create senders cache, %w", err)
+	}
+
 	return nil
 }
 
@@ -267,14 +336,9 @@ func (b *Blockchain) ComputeGenesis() error {
 			return fmt.Errorf("genesis file does not match current genesis")
 		}
 
-		header, ok := b.GetHeaderByHash(head)
-		if !ok {
-			return fmt.Errorf("failed to get header with hash %s", head.String())
-		}
-
-		diff, ok := b.GetTD(head)
-		if !ok {
-			return fmt.Errorf("failed to read difficulty")
+		header, diff, err := b.recoverHead(head)
+		if err != nil {
+			return err
 		}
 
 		b.logger.Info(
@@ -286,6 +350,7 @@ func (b *Blockchain) ComputeGenesis() error {
 		)
 
 		b.setCurrentHeader(header, diff)
+		b.loadChainStats(header)
 	} else {
 		// empty storage, write the genesis
 		if err := b.writeGenesis(b.config.Genesis); err != nil {
@@ -298,6 +363,93 @@ func (b *Blockchain) ComputeGenesis() error {
 	return nil
 }
 
+// recoverHead returns the latest block of the chain ending at the stored head whose header, total
+// difficulty and canonical number are all written, and rolls the stored head back to it if needed.
+// The blocks are written at once, but the ones written by the previous versions, one entry after
+// the other, may be half-written after a crash. The canonical numbers above the head are removed
+func (b *Blockchain) recoverHead(head types.Hash) (*types.Header, *big.Int, error) {
+	var (
+		hash = head
+		top  uint64
+	)
+
+	for {
+		header, ok := b.readHeader(hash)
+		if !ok {
+			return nil, nil, fmt.Errorf("failed to get header with hash %s", hash.String())
+		}
+
+		if hash == head {
+			top = header.Number
+		}
+
+		diff, diffOk := b.readTotalDifficulty(hash)
+		canonical, canonicalOk := b.db.ReadCanonicalHash(header.Number)
+
+		if diffOk && canonicalOk && canonical == hash {
+			if err := b.repairHead(header, top, hash != head); err != nil {
+				return nil, nil, err
+			}
+
+			return header, diff, nil
+		}
+
+		if header.Number == 0 {
+			return nil, nil, fmt.Errorf("failed to read difficulty")
+		}
+
+		b.logger.Warn("half-written block found", "number", header.Number, "hash", hash)
+
+		hash = header.ParentHash
+	}
+}
+
+// repairHead writes the header as the head if it isn't the stored one, or if the stored number
+// doesn't match it, and removes the canonical numbers above it, all at once. The canonical numbers
+// may be missing up to the top one written, the number of the stored head or the stored number
+func (b *Blockchain) repairHead(header *types.Header, top uint64, rolledBack bool) error {
+	batch := b.db.NewWriteBatch()
+
+	number, ok := b.db.ReadHeadNumber()
+	if ok && number > top {
+		top = number
+	}
+
+	if rolledBack || !ok || number != header.Number {
+		if err := batch.WriteHeadHash(header.Hash); err != nil {
+			return err
+		}
+
+		if err := batch.WriteHeadNumber(header.Number); err != nil {
+			return err
+		}
+	}
+
+	for n := header.Number + 1; ; n++ {
+		if _, ok := b.db.ReadCanonicalHash(n); !ok {
+			if n > top {
+				break
+			}
+
+			continue
+		}
+
+		if err := batch.DeleteCanonicalHash(n); err != nil {
+			return err
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to repair the head: %w", err)
+	}
+
+	if rolledBack {
+		b.logger.Warn("rolled the head back to the latest complete block", "number", header.Number, "hash", header.Hash)
+	}
+
+	return nil
+}
+
 func (b *Blockchain) GetConsensus() Verifier {
 	return b.consensus
 }
@@ -307,6 +459,37 @@ func (b *Blockchain) SetConsensus(c Verifier) {
 	b.consensus = c
 }
 
+// SetRetainedBlocks sets the number of recent blocks whose bodies and receipts are kept,
+// they are deleted when a block leaves the window. Zero keeps all of them
+func (b *Blockchain) SetRetainedBlocks(n uint64) {
+	b.retainedBlocks = n
+}
+
+// SetTxLookupLimit sets the number of recent blocks whose transactions are looked up by hash,
+// the lookups are removed when a block leaves the window. Zero keeps all of them
+func (b *Blockchain) SetTxLookupLimit(n uint64) {
+	b.txLookupLimit = n
+}
+
+// SetStorageCompression sets the compression of the bodies and the receipts written to the storage.
+// The ones already written are read whatever their compression
+func (b *Blockchain) SetStorageCompression(compression storage.Compression) {
+	b.db.SetCompression(compression)
+}
+
+// SetCompactReceipts sets whether the receipts are written to the storage in the compact form.
+// The ones already written are read whatever their form
+func (b *Blockchain) SetCompactReceipts(compact bool) {
+	b.db.SetCompactReceipts(compact)
+}
+
+// SetFreezer sets the freezer the canonical blocks older than the given number of recent blocks
+// are moved to. The blocks already written are migrated gradually, as the new ones are written
+func (b *Blockchain) SetFreezer(freezer *storage.Freezer, threshold uint64) {
+	b.db.SetFreezer(freezer)
+	b.freezeThreshold = threshold
+}
+
 // setCurrentHeader sets the current header
 func (b *Blockchain) setCurrentHeader(h *types.Header, diff *big.Int) {
 	// Update the header (atomic)
@@ -363,47 +546,6 @@ func (b *Blockchain) Genesis() types.Hash {
 	return b.genesis
 }
 
-// CalculateGasLimit returns the gas limit of the next block after parent
-func (b *Blockchain) CalculateGasLimit(number uint64) (uint64, error) {
-	parent, ok := b.GetHeaderByNumber(number - 1)
-	if !ok {
-		return 0, fmt.Errorf("parent of block %d not found", number)
-	}
-
-	return b.calculateGasLimit(parent.GasLimit), nil
-}
-
-// calculateGasLimit calculates gas limit in reference to the block gas target
-func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
-	// The gas limit cannot move more than 1/1024 * parentGasLimit
-	// in either direction per block
-	blockGasTarget := b.Config().BlockGasTarget
-
-	// Check if the gas limit target has been set
-	if blockGasTarget == 0 {
-		// The gas limit target has not been set,
-		// so it should use the parent gas limit
-		return parentGasLimit
-	}
-
-	// Check if the gas limit is already at the target
-	if parentGasLimit == blockGasTarget {
-		// The gas limit is already at the target, no need to move it
-		return blockGasTarget
-	}
-
-	delta := parentGasLimit * 1 / BlockGasTargetDivisor
-	if parentGasLimit < blockGasTarget {
-		// The gas limit is lower than the gas target, so it should
-		// increase towards the target
-		return common.Min(blockGasTarget, parentGasLimit+delta)
-	}
-
-	// The gas limit is higher than the gas target, so it should
-	// decrease towards the target
-	return common.Max(blockGasTarget, common.Max(parentGasLimit-delta, 0))
-}
-
 // writeGenesis wrapper for the genesis write function
 func (b *Blockchain) writeGenesis(genesis *chain.Genesis) error {
 	header := genesis.GenesisHeader()
@@ -435,6 +577,7 @@ func (b *Blockchain) writeGenesisImpl(header *types.Header) error {
 	event := &Event{}
 	event.AddNewHeader(header)
 	b.stream.push(event)
+	b.publishBusEvents(event)
 
 	return nil
 }
@@ -453,20 +596,22 @@ func (b *Blockchain) GetChainTD() (*big.Int, bool) {
 	return b.GetTD(header.Hash)
 }
 
-// GetTD returns the difficulty for the header hash
+// GetTD returns the total difficulty of the header with the given hash. It is served by the difficulty
+// cache or the persisted index, and recovered from the closest indexed ancestor if it is missing
 func (b *Blockchain) GetTD(hash types.Hash) (*big.Int, bool) {
 	return b.readTotalDifficulty(hash)
 }
 
-// writeCanonicalHeader writes the new header
-func (b *Blockchain) writeCanonicalHeader(event *Event, h *types.Header) error {
+// writeCanonicalHeader writes the new header to the given storage. The head of the chain
+// is set to the header once the writes are committed, by commitWrites
+func (b *Blockchain) writeCanonicalHeader(db storage.Storage, event *Event, h *types.Header) error {
 	parentTD, ok := b.readTotalDifficulty(h.ParentHash)
 	if !ok {
 		return fmt.Errorf("parent difficulty not found")
 	}
 
 	newTD := big.NewInt(0).Add(parentTD, new(big.Int).SetUint64(h.Difficulty))
-	if err := b.db.WriteCanonicalHeader(h, newTD); err != nil {
+	if err := db.WriteCanonicalHeader(h, newTD); err != nil {
 		return err
 	}
 
@@ -474,25 +619,51 @@ func (b *Blockchain) writeCanonicalHeader(event *Event, h *types.Header) error {
 	event.AddNewHeader(h)
 	event.SetDifficulty(newTD)
 
-	b.setCurrentHeader(h, newTD)
+	return nil
+}
+
+// commitWrites applies the writes of the batch at once, then moves the head of the chain
+// to the one of the event, so that the new head is only visible once its data is written
+func (b *Blockchain) commitWrites(batch storage.WriteBatch, event *Event) error {
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	if event.Type == EventHead || event.Type == EventReorg {
+		b.setCurrentHeader(event.NewChain[0], event.Difficulty)
+	}
 
 	return nil
 }
 
 // advanceHead Sets the passed in header as the new head of the chain
 func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
+	newTD, err := b.writeHead(b.db, newHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the blockchain reference
+	b.setCurrentHeader(newHeader, newTD)
+
+	return newTD, nil
+}
+
+// writeHead writes the passed in header as the head of the chain to the given storage,
+// and returns its total difficulty
+func (b *Blockchain) writeHead(db storage.Storage, newHeader *types.Header) (*big.Int, error) {
 	// Write the current head hash into storage
-	if err := b.db.WriteHeadHash(newHeader.Hash); err != nil {
+	if err := db.WriteHeadHash(newHeader.Hash); err != nil {
 		return nil, err
 	}
 
 	// Write the current head number into storage
-	if err := b.db.WriteHeadNumber(newHeader.Number); err != nil {
+	if err := db.WriteHeadNumber(newHeader.Number); err != nil {
 		return nil, err
 	}
 
 	// Matches the current head number with the current hash
-	if err := b.db.WriteCanonicalHash(newHeader.Number, newHeader.Hash); err != nil {
+	if err := db.WriteCanonicalHash(newHeader.Number, newHeader.Hash); err != nil {
 		return nil, err
 	}
 
@@ -510,12 +681,11 @@ func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
 
 	// Calculate the new total difficulty
 	newTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(newHeader.Difficulty))
-	if err := b.db.WriteTotalDifficulty(newHeader.Hash, newTD); err != nil {
+	if err := db.WriteTotalDifficulty(newHeader.Hash, newTD); err != nil {
 		return nil, err
 	}
 
-	// Update the blockchain reference
-	b.setCurrentHeader(newHeader, newTD)
+	b.difficultyCache.Add(newHeader.Hash, newTD)
 
 	return newTD, nil
 }
@@ -565,6 +735,13 @@ func (b *Blockchain) readHeader(hash types.Hash) (*types.Header, bool) {
 // readBody reads the block's body, using the block hash
 func (b *Blockchain) readBody(hash types.Hash) (*types.Body, bool) {
 	bb, err := b.db.ReadBody(hash)
+	if errors.Is(err, storage.ErrNotFound) {
+		// not synced in the light mode, or pruned in the gateway mode
+		b.logger.Debug("body not found", "hash", hash)
+
+		return nil, false
+	}
+
 	if err != nil {
 		b.logger.Error("failed to read body", "err", err)
 
@@ -591,7 +768,8 @@ func (b *Blockchain) readTotalDifficulty(headerHash types.Hash) (*big.Int, bool)
 	// Miss, read the difficulty from the DB
 	dbDifficulty, ok := b.db.ReadTotalDifficulty(headerHash)
 	if !ok {
-		return nil, false
+		// the difficulty isn't indexed, e.g. for a header imported by an older version
+		return b.recoverTotalDifficulty(headerHash)
 	}
 
 	// Update the difficulty cache
@@ -648,7 +826,13 @@ func (b *Blockchain) WriteHeadersWithBodies(headers []*types.Header) error {
 	// Write the actual headers
 	for _, h := range headers {
 		event := &Event{}
-		if err := b.writeHeaderImpl(event, h); err != nil {
+		batch := b.db.NewWriteBatch()
+
+		if err := b.writeHeaderImpl(batch, event, h); err != nil {
+			return err
+		}
+
+		if err := b.commitWrites(batch, event); err != nil {
 			return err
 		}
 
@@ -661,28 +845,55 @@ func (b *Blockchain) WriteHeadersWithBodies(headers []*types.Header) error {
 
 // VerifyPotentialBlock does the minimal block verification without consulting the
 // consensus layer. Should only be used if consensus checks are done
-// outside the method call
+// outside the method call. The rejected block is recorded in the bad block journal
 func (b *Blockchain) VerifyPotentialBlock(block *types.Block) error {
 	// Do just the initial block verification
-	return b.verifyBlock(block)
+	if err := b.verifyBlock(block); err != nil {
+		b.recordBadBlock(block, err)
+
+		return err
+	}
+
+	return nil
 }
 
 // VerifyFinalizedBlock verifies that the block is valid by performing a series of checks.
-// It is assumed that the block status is sealed (committed)
+// It is assumed that the block status is sealed (committed).
+// The rejected block is recorded in the bad block journal
 func (b *Blockchain) VerifyFinalizedBlock(block *types.Block) error {
 	// Make sure the consensus layer verifies this block header
 	if err := b.consensus.VerifyHeader(block.Header); err != nil {
-		return fmt.Errorf("failed to verify the header: %w", err)
+		err = fmt.Errorf("failed to verify the header: %w", err)
+		b.recordBadBlock(block, err)
+
+		return err
+	}
+
+	// the block isn't recorded as bad, it is accepted once the local clock catches up
+	if err := b.verifyBlockTime(block.Header); err != nil {
+		return err
 	}
 
 	// Do the initial block verification
 	if err := b.verifyBlock(block); err != nil {
+		b.recordBadBlock(block, err)
+
 		return err
 	}
 
 	return nil
 }
 
+// VerifyFinalizedHeader verifies a sealed header without its block body, for the light sync.
+// The consensus layer verifies the header, which has to be in line with its locally saved parent
+func (b *Blockchain) VerifyFinalizedHeader(header *types.Header) error {
+	if err := b.consensus.VerifyHeader(header); err != nil {
+		return fmt.Errorf("failed to verify the header: %w", err)
+	}
+
+	return b.verifyBlockParent(&types.Block{Header: header})
+}
+
 // verifyBlock does the base (common) block verification steps by
 // verifying the block body as well as the parent information
 func (b *Blockchain) verifyBlock(block *types.Block) error {
@@ -752,7 +963,8 @@ func (b *Blockchain) verifyBlockParent(childBlock *types.Block) error {
 		return fmt.Errorf("invalid gas limit, %w", gasLimitErr)
 	}
 
-	return nil
+	// Make sure the base fee follows the one of the parent
+	return b.verifyBaseFee(childBlock.Header, parent)
 }
 
 // verifyBlockBody verifies that the block body is valid. This means checking:
@@ -838,6 +1050,13 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 		return nil, err
 	}
 
+	// the senders are recovered in parallel, rather than one by one by the state transition
+	if err := b.RecoverSenders(block); err != nil {
+		return nil, err
+	}
+
+	b.runBeforeBlockHooks(block)
+
 	txn, err := b.executor.ProcessBlock(parent.StateRoot, block, blockCreator)
 	if err != nil {
 		return nil, err
@@ -847,10 +1066,16 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 		return nil, err
 	}
 
-	_, root := txn.Commit()
+	_, root, diff := txn.CommitWithDiff()
 
 	// Append the receipts to the receipts cache
 	b.receiptsCache.Add(header.Hash, txn.Receipts())
+	b.stateDiffsCache.Add(header.Hash, diff)
+	b.feesCache.Add(header.Hash, txn.Fees())
+
+	if stats := txn.OpcodeStats(); stats != nil {
+		b.opcodeStatsCache.Add(header.Hash, stats)
+	}
 
 	return &BlockResult{
 		Root:     root,
@@ -859,13 +1084,190 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 	}, nil
 }
 
+// RecoverState re-executes the blocks on top of the latest block whose state is available,
+// up to the head of the chain. The state of the latest blocks is missing if the node stopped
+// before writing the state committed in memory to disk.
+// It returns the number of re-executed blocks
+func (b *Blockchain) RecoverState(hasState func(root types.Hash) bool) (uint64, error) {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	head := b.Header()
+
+	// the genesis state is written on every start
+	number := head.Number
+	for ; number > 0; number-- {
+		header, ok := b.GetHeaderByNumber(number)
+		if !ok {
+			return 0, fmt.Errorf("header %d not found", number)
+		}
+
+		if hasState(header.StateRoot) {
+			break
+		}
+	}
+
+	if number == head.Number {
+		return 0, nil
+	}
+
+	b.logger.Info("recovering state", "from", number+1, "to", head.Number)
+
+	for n := number + 1; n <= head.Number; n++ {
+		block, ok := b.GetBlockByNumber(n, true)
+		if !ok {
+			return 0, fmt.Errorf("block %d not found", n)
+		}
+
+		result, err := b.executeBlockTransactions(block)
+		if err != nil {
+			return 0, fmt.Errorf("unable to re-execute block %d, %w", n, err)
+		}
+
+		if result.Root != block.Header.StateRoot {
+			return 0, fmt.Errorf("unable to re-execute block %d, %w", n, ErrInvalidStateRoot)
+		}
+	}
+
+	return head.Number - number, nil
+}
+
+// RewindTo unwinds the canonical chain to the block with the given number, so that the blocks
+// of another branch can be written on top of it. The unwound blocks are kept as a fork,
+// and a reorg event is emitted with the unwound headers as the old chain
+func (b *Blockchain) RewindTo(number uint64, source string) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	return b.rewindTo(number, source)
+}
+
+// DiscardBlocks unwinds the canonical chain to the parent of the block with the given number,
+// and deletes the bodies and the receipts of the unwound blocks, so that they are downloaded
+// and verified again by the syncer. It returns the number of discarded blocks
+func (b *Blockchain) DiscardBlocks(from uint64, source string) (uint64, error) {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	head := b.Header()
+
+	if from == 0 || from > head.Number {
+		return 0, fmt.Errorf("%w: %d, the head is %d", ErrInvalidDiscardRange, from, head.Number)
+	}
+
+	// the freezer is append-only
+	if frozen := b.db.Frozen(); from < frozen {
+		return 0, fmt.Errorf("%w: %d, the blocks below %d are frozen", ErrInvalidDiscardRange, from, frozen)
+	}
+
+	hashes := make([]types.Hash, 0, head.Number-from+1)
+
+	for n := from; n <= head.Number; n++ {
+		hash, ok := b.db.ReadCanonicalHash(n)
+		if !ok {
+			return 0, fmt.Errorf("canonical hash %d not found", n)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if err := b.rewindTo(from-1, source); err != nil {
+		return 0, err
+	}
+
+	if err := storage.TruncateBloomSections(b.db, from); err != nil {
+		return 0, fmt.Errorf("failed to truncate the bloom bits index: %w", err)
+	}
+
+	for _, hash := range hashes {
+		if err := b.db.DeleteBody(hash); err != nil {
+			return 0, fmt.Errorf("failed to delete the body of %s: %w", hash, err)
+		}
+
+		if err := b.db.DeleteReceipts(hash); err != nil {
+			return 0, fmt.Errorf("failed to delete the receipts of %s: %w", hash, err)
+		}
+
+		b.headersCache.Remove(hash)
+		b.receiptsCache.Remove(hash)
+		b.stateDiffsCache.Remove(hash)
+		b.feesCache.Remove(hash)
+	}
+
+	b.logger.Info("discarded blocks", "from", from, "to", head.Number)
+
+	return uint64(len(hashes)), nil
+}
+
+// rewindTo unwinds the canonical chain to the block with the given number.
+// It must be called with the write lock held
+func (b *Blockchain) rewindTo(number uint64, source string) error {
+	head := b.Header()
+	if number >= head.Number {
+		return nil
+	}
+
+	target, ok := b.GetHeaderByNumber(number)
+	if !ok {
+		return fmt.Errorf("header %d not found", number)
+	}
+
+	evnt := &Event{Source: source}
+
+	for n := number + 1; n <= head.Number; n++ {
+		header, ok := b.GetHeaderByNumber(n)
+		if !ok {
+			return fmt.Errorf("header %d not found", n)
+		}
+
+		evnt.AddOldHeader(header)
+	}
+
+	batch := b.db.NewWriteBatch()
+
+	if err := b.writeFork(batch, head); err != nil {
+		return fmt.Errorf("failed to write the old header as fork: %w", err)
+	}
+
+	diff, err := b.writeHead(batch, target)
+	if err != nil {
+		return err
+	}
+
+	// Remove the unwound blocks from the canonical chain numbers
+	for n := head.Number; n > number; n-- {
+		if err := batch.DeleteCanonicalHash(n); err != nil {
+			return err
+		}
+	}
+
+	evnt.AddNewHeader(target)
+	evnt.Type = EventReorg
+	evnt.SetDifficulty(diff)
+
+	if err := b.commitWrites(batch, evnt); err != nil {
+		return err
+	}
+
+	b.dispatchEvent(evnt)
+
+	b.rewindFinality(target)
+	b.chainStats.rewind(number)
+
+	b.logger.Info("rewound chain", "from", head.Number, "to", number, "hash", target.Hash)
+
+	return nil
+}
+
 // WriteBlock writes a single block to the local blockchain.
-// It doesn't do any kind of verification, only commits the block to the DB
+// It doesn't do any kind of verification, only commits the block to the DB.
+// A block which isn't on top of the head is stored as a side chain block, and the chain
+// is reorganized if its branch is heavier than the canonical one
 func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 	b.writeLock.Lock()
 	defer b.writeLock.Unlock()
 
-	if block.Number() <= b.Header().Number {
+	if b.isKnownBlock(block.Header) {
 		b.logger.Info("block already inserted", "block", block.Number(), "source", source)
 
 		return nil
@@ -873,13 +1275,17 @@ func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 
 	header := block.Header
 
-	if err := b.writeBody(block); err != nil {
+	// the header, the body, the receipts and the txn lookups of the block are written at once,
+	// so that a crash never leaves the block half-written
+	batch := b.db.NewWriteBatch()
+
+	if err := b.writeBody(batch, block); err != nil {
 		return err
 	}
 
 	// Write the header to the chain
 	evnt := &Event{Source: source}
-	if err := b.writeHeaderImpl(evnt, header); err != nil {
+	if err := b.writeHeaderImpl(batch, evnt, header); err != nil {
 		return err
 	}
 
@@ -889,22 +1295,100 @@ func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 		return receiptsErr
 	}
 
-	// write the receipts, do it only after the header has been written.
-	// Otherwise, a client might ask for a header once the receipt is valid,
-	// but before it is written into the storage
-	if err := b.db.WriteReceipts(block.Hash(), blockReceipts); err != nil {
+	if err := batch.WriteReceipts(block.Hash(), blockReceipts); err != nil {
 		return err
 	}
 
+	if err := b.writeFeeLedgerEntry(batch, header); err != nil {
+		return err
+	}
+
+	if evnt.Type == EventFork {
+		if err := b.commitWrites(batch, evnt); err != nil {
+			b.headersCache.Remove(header.Hash)
+			b.difficultyCache.Remove(header.Hash)
+
+			return err
+		}
+
+		// the block lost against the canonical chain, e.g. a late proposal
+		b.metrics.StaleBlocks.Add(1)
+		b.dispatchEvent(evnt)
+		b.runAfterBlockHooks(block, blockReceipts, evnt)
+
+		b.logger.Info("side chain block", "number", header.Number, "hash", header.Hash, "source", source)
+
+		return nil
+	}
+
+	// the blocks joining the canonical chain, oldest first
+	blocks := b.canonicalBlocks(evnt, block)
+
+	if err := b.updateTxLookups(batch, evnt, blocks); err != nil {
+		return err
+	}
+
+	// the block becomes visible, as the head of the chain, once its data is written
+	if err := b.commitWrites(batch, evnt); err != nil {
+		b.headersCache.Remove(header.Hash)
+		b.difficultyCache.Remove(header.Hash)
+
+		return err
+	}
+
+	// the lookups are removed before the bodies they are read from are pruned
+	if err := b.unindexTxLookups(b.Header().Number); err != nil {
+		// the lookups are removed with the next blocks
+		b.logger.Error("failed to remove the txn lookups", "head", b.Header().Number, "err", err)
+	}
+
+	if err := b.pruneBlock(b.Header().Number); err != nil {
+		// the block is written anyway, the body and the receipts of the pruned block are only kept longer
+		b.logger.Error("failed to prune block", "head", b.Header().Number, "err", err)
+	}
+
+	if err := b.freezeBlocks(b.Header().Number); err != nil {
+		// the blocks not frozen are kept in the key-value storage, and frozen with the next blocks
+		b.logger.Error("failed to freeze blocks", "head", b.Header().Number, "err", err)
+	}
+
+	if err := b.indexBloomBits(b.Header().Number); err != nil {
+		// the section is indexed again with the next block, the log queries scan its blocks meanwhile
+		b.logger.Error("failed to index the logs blooms", "head", b.Header().Number, "err", err)
+	}
+
+	headers := make([]*types.Header, len(blocks))
+	for i, canonical := range blocks {
+		headers[i] = canonical.Header
+	}
+
 	// update snapshot
-	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
+	if err := b.consensus.ProcessHeaders(headers); err != nil {
 		return err
 	}
 
 	b.dispatchEvent(evnt)
+	b.runAfterBlockHooks(block, blockReceipts, evnt)
+
+	if evnt.Type == EventReorg {
+		b.logger.Info(
+			"chain reorganized",
+			"removed", len(evnt.OldChain),
+			"added", len(evnt.NewChain),
+			"head", b.Header().Number,
+			"source", source,
+		)
+	}
 
-	// Update the average gas price
-	b.updateGasPriceAvgWithBlock(block)
+	for _, canonical := range blocks {
+		// Update the average gas price
+		b.updateGasPriceAvgWithBlock(canonical)
+
+		b.chainStats.addBlock(canonical.Header, len(canonical.Transactions))
+		b.chainStats.addActiveAddresses(canonical)
+	}
+
+	b.updateOpcodeMetrics(header.Hash)
 
 	logArgs := []interface{}{
 		"number", header.Number,
@@ -923,6 +1407,102 @@ func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 	return nil
 }
 
+// WriteFinalizedHeader writes a single header without its block body, for the light sync.
+// The block isn't executed, so its state and receipts aren't available.
+// It doesn't do any kind of verification, the header is expected to be verified by VerifyFinalizedHeader
+func (b *Blockchain) WriteFinalizedHeader(header *types.Header, source string) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if header.Number <= b.Header().Number {
+		b.logger.Info("header already inserted", "block", header.Number, "source", source)
+
+		return nil
+	}
+
+	evnt := &Event{Source: source}
+	batch := b.db.NewWriteBatch()
+
+	if err := b.writeHeaderImpl(batch, evnt, header); err != nil {
+		return err
+	}
+
+	if err := b.commitWrites(batch, evnt); err != nil {
+		b.headersCache.Remove(header.Hash)
+		b.difficultyCache.Remove(header.Hash)
+
+		return err
+	}
+
+	// update snapshot
+	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
+		return err
+	}
+
+	b.dispatchEvent(evnt)
+
+	b.logger.Debug("new header", "number", header.Number, "hash", header.Hash, "parent", header.ParentHash)
+
+	return nil
+}
+
+// WriteFinalizedBlockWithReceipts writes a block along with its receipts for the light sync.
+// The block isn't executed, so its state isn't available, but its body and its receipts are served.
+// They are checked against the header, which is expected to be verified by VerifyFinalizedHeader
+func (b *Blockchain) WriteFinalizedBlockWithReceipts(block *types.Block, receipts []*types.Receipt, source string) error {
+	if err := verifyImportedBlock(block, receipts); err != nil {
+		return err
+	}
+
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	header := block.Header
+
+	if header.Number <= b.Header().Number {
+		b.logger.Info("block already inserted", "block", header.Number, "source", source)
+
+		return nil
+	}
+
+	evnt := &Event{Source: source}
+	batch := b.db.NewWriteBatch()
+
+	if err := b.writeBody(batch, block); err != nil {
+		return err
+	}
+
+	if err := b.writeHeaderImpl(batch, evnt, header); err != nil {
+		return err
+	}
+
+	if err := batch.WriteReceipts(block.Hash(), receipts); err != nil {
+		return err
+	}
+
+	if err := b.updateTxLookups(batch, evnt, b.canonicalBlocks(evnt, block)); err != nil {
+		return err
+	}
+
+	if err := b.commitWrites(batch, evnt); err != nil {
+		b.headersCache.Remove(header.Hash)
+		b.difficultyCache.Remove(header.Hash)
+
+		return err
+	}
+
+	// update snapshot
+	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
+		return err
+	}
+
+	b.dispatchEvent(evnt)
+
+	b.logger.Debug("new block with receipts", "number", header.Number, "hash", header.Hash, "source", source)
+
+	return nil
+}
+
 // extractBlockReceipts extracts the receipts from the passed in block
 func (b *Blockchain) extractBlockReceipts(block *types.Block) ([]*types.Receipt, error) {
 	// Check the cache for the block receipts
@@ -946,6 +1526,67 @@ func (b *Blockchain) extractBlockReceipts(block *types.Block) ([]*types.Receipt,
 	return extractedReceipts, nil
 }
 
+// GetOpcodeStats returns the statistics of the opcodes executed by the block with the given hash.
+// They are only available for the recently executed blocks, if the executor collects them
+func (b *Blockchain) GetOpcodeStats(hash types.Hash) (*runtime.OpcodeStats, bool) {
+	stats, ok := b.opcodeStatsCache.Get(hash)
+	if !ok {
+		return nil, false
+	}
+
+	opcodeStats, ok := stats.(*runtime.OpcodeStats)
+
+	return opcodeStats, ok
+}
+
+// updateOpcodeMetrics adds the opcodes executed by the written block to the metrics
+func (b *Blockchain) updateOpcodeMetrics(hash types.Hash) {
+	stats, ok := b.GetOpcodeStats(hash)
+	if !ok {
+		return
+	}
+
+	for op, stat := range stats {
+		if stat.Count == 0 {
+			continue
+		}
+
+		name := evm.OpCode(op).String()
+
+		b.metrics.OpcodeExecutions.With("opcode", name).Add(float64(stat.Count))
+		b.metrics.OpcodeGas.With("opcode", name).Add(float64(stat.Gas))
+	}
+}
+
+// GetChainStats returns the rolling aggregates of the recent blocks
+func (b *Blockchain) GetChainStats() *ChainStats {
+	return b.chainStats.get()
+}
+
+// loadChainStats fills the window of the chain statistics with the blocks up to the given head.
+// The senders aren't stored with the blocks, so the active addresses are only counted for the new blocks
+func (b *Blockchain) loadChainStats(head *types.Header) {
+	from := uint64(1)
+	if head.Number > chainStatsWindow {
+		from = head.Number - chainStatsWindow + 1
+	}
+
+	for n := from; n <= head.Number; n++ {
+		header, ok := b.GetHeaderByNumber(n)
+		if !ok {
+			continue
+		}
+
+		// the bodies aren't stored by the light nodes
+		body, err := b.db.ReadBody(header.Hash)
+		if err != nil {
+			continue
+		}
+
+		b.chainStats.addBlock(header, len(body.Transactions))
+	}
+}
+
 // updateGasPriceAvgWithBlock extracts the gas price information from the
 // block, and updates the average gas price for the chain accordingly
 func (b *Blockchain) updateGasPriceAvgWithBlock(block *types.Block) {
@@ -957,34 +1598,228 @@ func (b *Blockchain) updateGasPriceAvgWithBlock(block *types.Block) {
 
 	gasPrices := make([]*big.Int, len(block.Transactions))
 	for i, transaction := range block.Transactions {
-		gasPrices[i] = transaction.GasPrice
+		if transaction.Type == types.DynamicFeeTx {
+			// the price paid in the block, rather than the fee cap
+			gasPrices[i] = transaction.EffectiveGasPrice(block.Header.BaseFee)
+		} else {
+			gasPrices[i] = transaction.GasPrice
+		}
 	}
 
 	b.updateGasPriceAvg(gasPrices)
 }
 
-// writeBody writes the block body to the DB.
-// Additionally, it also updates the txn lookup, for txnHash -> block lookups
-func (b *Blockchain) writeBody(block *types.Block) error {
+// isKnownBlock checks if the block is part of the canonical chain,
+// or is a side chain block whose body is stored
+func (b *Blockchain) isKnownBlock(header *types.Header) bool {
+	if header.Number <= b.Header().Number {
+		if hash, ok := b.db.ReadCanonicalHash(header.Number); ok && hash == header.Hash {
+			return true
+		}
+	}
+
+	if _, ok := b.readHeader(header.Hash); !ok {
+		return false
+	}
+
+	_, ok := b.readBody(header.Hash)
+
+	return ok
+}
+
+// writeBody writes the block body to the given storage. The txn lookups are written
+// once the block joins the canonical chain, by updateTxLookups
+func (b *Blockchain) writeBody(db storage.Storage, block *types.Block) error {
 	body := block.Body()
 
 	// Write the full body (txns + receipts)
-	if err := b.db.WriteBody(block.Header.Hash, body); err != nil {
-		return err
+	return db.WriteBody(block.Header.Hash, body)
+}
+
+// canonicalBlocks returns the blocks joining the canonical chain with the event, oldest first.
+// The written block is the head of the new chain, the others are side chain blocks written before.
+// The blocks whose body isn't stored are skipped
+func (b *Blockchain) canonicalBlocks(evnt *Event, block *types.Block) []*types.Block {
+	blocks := make([]*types.Block, 0, len(evnt.NewChain))
+
+	for i := len(evnt.NewChain) - 1; i >= 0; i-- {
+		header := evnt.NewChain[i]
+
+		if header.Hash == block.Hash() {
+			blocks = append(blocks, block)
+
+			continue
+		}
+
+		body, ok := b.readBody(header.Hash)
+		if !ok {
+			continue
+		}
+
+		blocks = append(blocks, &types.Block{
+			Header:       header,
+			Transactions: body.Transactions,
+			Uncles:       body.Uncles,
+		})
 	}
 
-	// Write txn lookups (txHash -> block)
-	for _, txn := range block.Transactions {
-		if err := b.db.WriteTxLookup(txn.Hash, block.Hash()); err != nil {
-			return err
+	return blocks
+}
+
+// updateTxLookups removes the txn lookups of the blocks leaving the canonical chain,
+// and writes the ones of the blocks joining it, for txnHash -> block lookups
+func (b *Blockchain) updateTxLookups(db storage.Storage, evnt *Event, blocks []*types.Block) error {
+	for _, header := range evnt.OldChain {
+		body, ok := b.readBody(header.Hash)
+		if !ok {
+			continue
+		}
+
+		for _, txn := range body.Transactions {
+			if err := db.DeleteTxLookup(txn.Hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the transactions included again by the new chain point to their new block
+	for _, block := range blocks {
+		for i, txn := range block.Transactions {
+			if err := db.WriteTxLookup(txn.Hash, &storage.TxLookupEntry{
+				BlockHash:   block.Hash(),
+				BlockNumber: block.Number(),
+				Index:       uint64(i),
+				Positioned:  true,
+			}); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-// ReadTxLookup returns the block hash using the transaction hash
-func (b *Blockchain) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+// pruneBlock deletes the body and the receipts of the canonical block leaving the retention window
+// with the given head. The header and the transaction lookups are kept, the genesis is never pruned
+func (b *Blockchain) pruneBlock(head uint64) error {
+	if b.retainedBlocks == 0 || head <= b.retainedBlocks {
+		return nil
+	}
+
+	hash, ok := b.db.ReadCanonicalHash(head - b.retainedBlocks)
+	if !ok {
+		return nil
+	}
+
+	return deleteBlockData(b.db, hash)
+}
+
+// maxFrozenBlocks is the maximum number of blocks moved to the freezer with a block, so that
+// the migration of the blocks already written doesn't stall the chain
+const maxFrozenBlocks = 1000
+
+// freezeBlocks moves the canonical blocks leaving the freeze threshold with the given head to the freezer
+func (b *Blockchain) freezeBlocks(head uint64) error {
+	if b.freezeThreshold == 0 || head <= b.freezeThreshold {
+		return nil
+	}
+
+	limit := head - b.freezeThreshold
+	if frozen := b.db.Frozen(); limit > frozen+maxFrozenBlocks {
+		limit = frozen + maxFrozenBlocks
+	}
+
+	_, err := b.db.FreezeBlocks(limit)
+
+	return err
+}
+
+// bloomConfirmations is the number of blocks a section of the bloom bits index is built behind the head,
+// so that it isn't reorganized
+const bloomConfirmations = 64
+
+// indexBloomBits indexes the logs blooms of the section completed with the given head, if any.
+// A single section is indexed per block, so that indexing an existing chain doesn't stall it
+func (b *Blockchain) indexBloomBits(head uint64) error {
+	if head < bloomConfirmations {
+		return nil
+	}
+
+	_, err := storage.IndexBloomSections(b.db, head-bloomConfirmations, 1)
+
+	return err
+}
+
+// IndexBloomBits indexes the logs blooms of all the sections of the canonical chain not indexed yet,
+// from the storage of a stopped node, and returns the number of sections indexed
+func IndexBloomBits(db storage.Storage) (uint64, error) {
+	head, ok := db.ReadHeadNumber()
+	if !ok || head < bloomConfirmations {
+		return 0, nil
+	}
+
+	return storage.IndexBloomSections(db, head-bloomConfirmations, math.MaxUint64)
+}
+
+// BloomIndexed returns the number of the first block not covered by the bloom bits index
+func (b *Blockchain) BloomIndexed() uint64 {
+	return b.db.ReadBloomSections() * storage.BloomSectionSize
+}
+
+// BloomCandidates returns the numbers of the blocks between from and to included, and covered by
+// the bloom bits index, whose logs bloom may match all the groups. A group matches if any of its
+// values, an address or a topic, is in the bloom
+func (b *Blockchain) BloomCandidates(from, to uint64, groups [][][]byte) []uint64 {
+	return storage.MatchBloomBits(b.db, from, to, groups)
+}
+
+// maxUnindexedBlocks is the maximum number of blocks whose txn lookups are removed per written block,
+// so that limiting the lookups of an existing chain doesn't stall it
+const maxUnindexedBlocks = 1000
+
+// unindexTxLookups removes the txn lookups of the canonical blocks leaving the lookup limit with the given head.
+// The lookups of the blocks whose body is pruned already are left
+func (b *Blockchain) unindexTxLookups(head uint64) error {
+	if b.txLookupLimit == 0 || head < b.txLookupLimit {
+		return nil
+	}
+
+	tail := b.db.ReadTxLookupTail()
+
+	// the first block whose lookups are kept
+	limit := head - b.txLookupLimit + 1
+	if limit > tail+maxUnindexedBlocks {
+		limit = tail + maxUnindexedBlocks
+	}
+
+	if limit <= tail {
+		return nil
+	}
+
+	for number := tail; number < limit; number++ {
+		hash, ok := b.db.ReadCanonicalHash(number)
+		if !ok {
+			return fmt.Errorf("canonical hash %d not found", number)
+		}
+
+		body, ok := b.readBody(hash)
+		if !ok {
+			continue
+		}
+
+		for _, txn := range body.Transactions {
+			if err := b.db.DeleteTxLookup(txn.Hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return b.db.WriteTxLookupTail(limit)
+}
+
+// ReadTxLookup returns the position of the transaction in the canonical chain,
+// the transactions of the blocks leaving the lookup limit aren't found
+func (b *Blockchain) ReadTxLookup(hash types.Hash) (*storage.TxLookupEntry, bool) {
 	v, ok := b.db.ReadTxLookup(hash)
 
 	return v, ok
@@ -1064,20 +1899,39 @@ func (b *Blockchain) GetHashByNumber(blockNumber uint64) types.Hash {
 
 // dispatchEvent pushes a new event to the stream
 func (b *Blockchain) dispatchEvent(evnt *Event) {
+	b.updateMetrics(evnt)
 	b.stream.push(evnt)
+	b.publishBusEvents(evnt)
+}
+
+// updateMetrics records the chain health metrics of the event
+func (b *Blockchain) updateMetrics(evnt *Event) {
+	switch evnt.Type {
+	case EventFork:
+		b.metrics.OrphanedBlocks.Add(float64(len(evnt.OldChain)))
+
+		return
+	case EventReorg:
+		b.metrics.OrphanedBlocks.Add(float64(len(evnt.OldChain)))
+		b.metrics.ReorgDepth.Observe(float64(len(evnt.OldChain)))
+	}
+
+	// the timestamp of the header is the time the block was proposed
+	head := b.Header()
+	b.metrics.BlockPropagationDelay.Set(time.Since(time.Unix(int64(head.Timestamp), 0)).Seconds())
 }
 
-// writeHeaderImpl writes a block and the data, assumes the genesis is already set
-func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
+// writeHeaderImpl writes a block and the data to the given storage, assumes the genesis is already set
+func (b *Blockchain) writeHeaderImpl(db storage.Storage, evnt *Event, header *types.Header) error {
 	currentHeader := b.Header()
 
 	// Write the data
 	if header.ParentHash == currentHeader.Hash {
 		// Fast path to save the new canonical header
-		return b.writeCanonicalHeader(evnt, header)
+		return b.writeCanonicalHeader(db, evnt, header)
 	}
 
-	if err := b.db.WriteHeader(header); err != nil {
+	if err := db.WriteHeader(header); err != nil {
 		return err
 	}
 
@@ -1096,24 +1950,20 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		)
 	}
 
+	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
+
 	// Write the difficulty
-	if err := b.db.WriteTotalDifficulty(
-		header.Hash,
-		big.NewInt(0).Add(
-			parentTD,
-			big.NewInt(0).SetUint64(header.Difficulty),
-		),
-	); err != nil {
+	if err := db.WriteTotalDifficulty(header.Hash, incomingTD); err != nil {
 		return err
 	}
 
-	// Update the headers cache
+	// Update the headers and the difficulty caches
 	b.headersCache.Add(header.Hash, header)
+	b.difficultyCache.Add(header.Hash, incomingTD)
 
-	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
 	if incomingTD.Cmp(currentTD) > 0 {
 		// new block has higher difficulty, reorg the chain
-		if err := b.handleReorg(evnt, currentHeader, header); err != nil {
+		if err := b.handleReorg(db, evnt, currentHeader, header); err != nil {
 			return err
 		}
 	} else {
@@ -1121,7 +1971,7 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		evnt.AddOldHeader(header)
 		evnt.Type = EventFork
 
-		if err := b.writeFork(header); err != nil {
+		if err := b.writeFork(db, header); err != nil {
 			return err
 		}
 	}
@@ -1129,9 +1979,9 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 	return nil
 }
 
-// writeFork writes the new header forks to the DB
-func (b *Blockchain) writeFork(header *types.Header) error {
-	forks, err := b.db.ReadForks()
+// writeFork writes the new header forks to the given storage
+func (b *Blockchain) writeFork(db storage.Storage, header *types.Header) error {
+	forks, err := db.ReadForks()
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			forks = []types.Hash{}
@@ -1149,15 +1999,16 @@ func (b *Blockchain) writeFork(header *types.Header) error {
 	}
 
 	newForks = append(newForks, header.Hash)
-	if err := b.db.WriteForks(newForks); err != nil {
+	if err := db.WriteForks(newForks); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// handleReorg handles a reorganization event
+// handleReorg handles a reorganization event, writing the new canonical chain to the given storage
 func (b *Blockchain) handleReorg(
+	db storage.Storage,
 	evnt *Event,
 	oldHeader *types.Header,
 	newHeader *types.Header,
@@ -1202,6 +2053,11 @@ func (b *Blockchain) handleReorg(
 		}
 
 		oldChain = append(oldChain, oldHeader)
+
+		// the headers of the new branch down to the common ancestor
+		if oldHeader.Hash != newHeader.Hash {
+			newChain = append(newChain, newHeader)
+		}
 	}
 
 	for _, b := range oldChain[:len(oldChain)-1] {
@@ -1215,18 +2071,25 @@ func (b *Blockchain) handleReorg(
 		evnt.AddNewHeader(b)
 	}
 
-	if err := b.writeFork(oldChainHead); err != nil {
+	if err := b.writeFork(db, oldChainHead); err != nil {
 		return fmt.Errorf("failed to write the old header as fork: %w", err)
 	}
 
 	// Update canonical chain numbers
 	for _, h := range newChain {
-		if err := b.db.WriteCanonicalHash(h.Number, h.Hash); err != nil {
+		if err := db.WriteCanonicalHash(h.Number, h.Hash); err != nil {
 			return err
 		}
 	}
 
-	diff, err := b.advanceHead(newChainHead)
+	// Remove the numbers of the old chain above the new head, if it was longer
+	for n := oldChainHead.Number; n > newChainHead.Number; n-- {
+		if err := db.DeleteCanonicalHash(n); err != nil {
+			return err
+		}
+	}
+
+	diff, err := b.writeHead(db, newChainHead)
 	if err != nil {
 		return err
 	}
@@ -1288,5 +2151,8 @@ func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Blo
 
 // Close closes the DB connection
 func (b *Blockchain) Close() error {
+	b.closeFutureBlocks()
+	b.bus.Close()
+
 	return b.db.Close()
 }
@@ -8,13 +8,18 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
 )
 
 func TestGenesis(t *testing.T) {
@@ -561,7 +566,7 @@ func TestBlockchainWriteBody(t *testing.T) {
 	}
 	block.Header.ComputeHash()
 
-	if err := b.writeBody(block); err != nil {
+	if err := b.writeBody(b.db, block); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -998,3 +1003,762 @@ func TestBlockchain_VerifyBlockBody(t *testing.T) {
 		assert.ErrorIs(t, blockchain.verifyBlockBody(block), errUnableToExecute)
 	})
 }
+
+func TestBlockchain_RecoverState(t *testing.T) {
+	t.Parallel()
+
+	// the blocks are empty, so all of them have the same state root, except for the bad block
+	newChain := func(t *testing.T, badBlock uint64) *Blockchain {
+		t.Helper()
+
+		headers := NewTestHeaders(1)
+
+		for i := 1; i < 5; i++ {
+			header := &types.Header{
+				Number:       uint64(i),
+				ParentHash:   headers[i-1].Hash,
+				StateRoot:    types.EmptyRootHash,
+				TxRoot:       types.EmptyRootHash,
+				Sha3Uncles:   types.EmptyUncleHash,
+				ReceiptsRoot: types.EmptyRootHash,
+				Difficulty:   uint64(i),
+			}
+
+			if header.Number == badBlock {
+				header.StateRoot = types.StringToHash("1")
+			}
+
+			header.ComputeHash()
+
+			headers = append(headers, header)
+		}
+
+		b := NewTestBlockchain(t, headers)
+
+		executor, ok := b.executor.(*state.Executor)
+		assert.True(t, ok)
+
+		executor.GetHash = b.GetHashHelper
+
+		for _, header := range headers[1:] {
+			assert.NoError(t, b.writeBody(b.db, &types.Block{Header: header}))
+		}
+
+		return b
+	}
+
+	// the state of the blocks above the given number is missing
+	hasStateAbove := func(b *Blockchain, number uint64) func(types.Hash) bool {
+		next := b.Header().Number
+
+		return func(types.Hash) bool {
+			found := next <= number
+			next--
+
+			return found
+		}
+	}
+
+	t.Run("should re-execute the blocks with missing state", func(t *testing.T) {
+		t.Parallel()
+
+		b := newChain(t, 0)
+
+		recovered, err := b.RecoverState(hasStateAbove(b, 2))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), recovered)
+
+		for _, number := range []uint64{3, 4} {
+			header, _ := b.GetHeaderByNumber(number)
+
+			_, ok := b.receiptsCache.Get(header.Hash)
+			assert.True(t, ok)
+		}
+	})
+
+	t.Run("should do nothing if the state of the head is available", func(t *testing.T) {
+		t.Parallel()
+
+		b := newChain(t, 0)
+
+		recovered, err := b.RecoverState(hasStateAbove(b, 4))
+		assert.NoError(t, err)
+		assert.Zero(t, recovered)
+	})
+
+	t.Run("should fail if the re-executed state doesn't match", func(t *testing.T) {
+		t.Parallel()
+
+		b := newChain(t, 4)
+
+		_, err := b.RecoverState(hasStateAbove(b, 2))
+		assert.ErrorIs(t, err, ErrInvalidStateRoot)
+	})
+}
+
+func TestBlockchain_RewindTo(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	sub := b.SubscribeEvents()
+	defer sub.Close()
+
+	// the head can't be rewound forward
+	assert.NoError(t, b.RewindTo(12, "test"))
+	assert.Equal(t, headers[9].Hash, b.Header().Hash)
+
+	assert.NoError(t, b.RewindTo(5, "test"))
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	td, ok := b.GetChainTD()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(15), td.Uint64())
+
+	// the unwound blocks leave the canonical chain, and the old head is kept as fork
+	for n := uint64(6); n < 10; n++ {
+		_, ok := b.GetHeaderByNumber(n)
+		assert.False(t, ok)
+	}
+
+	forks, err := b.GetForks()
+	assert.NoError(t, err)
+	assert.Contains(t, forks, headers[9].Hash)
+
+	evnt := sub.GetEvent()
+	assert.Equal(t, EventReorg, evnt.Type)
+	assert.Equal(t, "test", evnt.Source)
+	hashes := func(headers []*types.Header) []types.Hash {
+		res := make([]types.Hash, len(headers))
+		for i, header := range headers {
+			res[i] = header.Hash
+		}
+
+		return res
+	}
+
+	assert.Equal(t, hashes(headers[6:]), hashes(evnt.OldChain))
+	assert.Equal(t, hashes(headers[5:6]), hashes(evnt.NewChain))
+
+	// another branch is written on top of the fork point, even if it is lighter
+	branch := AppendNewTestheadersWithSeed(headers[:6], 3, 1)
+	assert.NoError(t, b.WriteHeaders(branch[6:]))
+
+	assert.Equal(t, branch[8].Hash, b.Header().Hash)
+
+	header, ok := b.GetHeaderByNumber(7)
+	assert.True(t, ok)
+	assert.Equal(t, branch[7].Hash, header.Hash)
+}
+
+type mockCounter struct{ value float64 }
+
+func (c *mockCounter) With(...string) metrics.Counter {
+	return c
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.value += delta
+}
+
+// mockLabeledCounter records the values of the counter by label value
+type mockLabeledCounter struct {
+	values map[string]float64
+	label  string
+}
+
+func (c *mockLabeledCounter) With(labelValues ...string) metrics.Counter {
+	return &mockLabeledCounter{
+		values: c.values,
+		label:  labelValues[len(labelValues)-1],
+	}
+}
+
+func (c *mockLabeledCounter) Add(delta float64) {
+	c.values[c.label] += delta
+}
+
+type mockGauge struct{ value float64 }
+
+func (g *mockGauge) With(...string) metrics.Gauge {
+	return g
+}
+
+func (g *mockGauge) Set(value float64) {
+	g.value = value
+}
+
+func (g *mockGauge) Add(delta float64) {
+	g.value += delta
+}
+
+type mockHistogram struct{ values []float64 }
+
+func (h *mockHistogram) With(...string) metrics.Histogram {
+	return h
+}
+
+func (h *mockHistogram) Observe(value float64) {
+	h.values = append(h.values, value)
+}
+
+func TestBlockchain_Metrics(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	var (
+		orphaned    = &mockCounter{}
+		stale       = &mockCounter{}
+		reorgDepth  = &mockHistogram{}
+		propagation = &mockGauge{}
+	)
+
+	b.metrics = &Metrics{
+		OrphanedBlocks:        orphaned,
+		StaleBlocks:           stale,
+		ReorgDepth:            reorgDepth,
+		BlockPropagationDelay: propagation,
+	}
+
+	// a lighter branch is written as a fork
+	fork := AppendNewTestheadersWithSeed(headers[:6], 1, 1)
+	assert.NoError(t, b.WriteHeaders(fork[6:]))
+
+	assert.Equal(t, float64(1), orphaned.value)
+	assert.Empty(t, reorgDepth.values)
+
+	// the unwound blocks are orphaned
+	assert.NoError(t, b.RewindTo(5, "test"))
+
+	assert.Equal(t, float64(5), orphaned.value)
+	assert.Equal(t, []float64{4}, reorgDepth.values)
+
+	// the timestamp of the test headers is far in the past
+	assert.Greater(t, propagation.value, float64(0))
+
+	// a block of a written height is stale, unless it is the canonical block
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: headers[3]}, "test"))
+	assert.Equal(t, float64(0), stale.value)
+
+	other := AppendNewTestheadersWithSeed(headers[:3], 1, 2)
+	b.receiptsCache.Add(other[3].Hash, []*types.Receipt{})
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: other[3]}, "test"))
+	assert.Equal(t, float64(1), stale.value)
+}
+
+func TestBlockchain_OpcodeMetrics(t *testing.T) {
+	t.Parallel()
+
+	b := NewTestBlockchain(t, NewTestHeaders(10))
+
+	var (
+		executions = &mockLabeledCounter{values: map[string]float64{}}
+		gas        = &mockLabeledCounter{values: map[string]float64{}}
+	)
+
+	b.metrics = NilMetrics()
+	b.metrics.OpcodeExecutions = executions
+	b.metrics.OpcodeGas = gas
+
+	hash := types.StringToHash("1")
+
+	// the stats aren't collected for the block
+	_, ok := b.GetOpcodeStats(hash)
+	assert.False(t, ok)
+
+	b.updateOpcodeMetrics(hash)
+	assert.Empty(t, executions.values)
+
+	stats := &runtime.OpcodeStats{}
+	stats.Record(byte(evm.ADD), 3)
+	stats.Record(byte(evm.ADD), 3)
+	stats.Record(byte(evm.SSTORE), 20000)
+
+	b.opcodeStatsCache.Add(hash, stats)
+
+	res, ok := b.GetOpcodeStats(hash)
+	assert.True(t, ok)
+	assert.Equal(t, stats, res)
+
+	b.updateOpcodeMetrics(hash)
+
+	assert.Equal(t, map[string]float64{"ADD": 2, "SSTORE": 1}, executions.values)
+	assert.Equal(t, map[string]float64{"ADD": 6, "SSTORE": 20000}, gas.values)
+}
+
+func TestBlockchain_FinalizedHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(4)
+	b := NewTestBlockchain(t, headers[:2])
+
+	verifier, ok := b.consensus.(*MockVerifier)
+	assert.True(t, ok)
+
+	var processed []*types.Header
+
+	verifier.HookProcessHeaders(func(headers []*types.Header) error {
+		processed = append(processed, headers...)
+
+		return nil
+	})
+
+	// the parent of the header isn't written yet
+	assert.ErrorIs(t, b.VerifyFinalizedHeader(headers[3]), ErrParentNotFound)
+
+	assert.NoError(t, b.VerifyFinalizedHeader(headers[2]))
+	assert.NoError(t, b.WriteFinalizedHeader(headers[2], "test"))
+
+	assert.Equal(t, headers[2].Hash, b.Header().Hash)
+	assert.Equal(t, []*types.Header{headers[2]}, processed)
+
+	// the consensus rejects the header
+	errInvalidSeal := errors.New("invalid seal")
+
+	verifier.HookVerifyHeader(func(*types.Header) error {
+		return errInvalidSeal
+	})
+
+	assert.ErrorIs(t, b.VerifyFinalizedHeader(headers[3]), errInvalidSeal)
+
+	// a header already written is skipped
+	assert.NoError(t, b.WriteFinalizedHeader(headers[2], "test"))
+	assert.Len(t, processed, 1)
+}
+
+func TestBlockchain_WriteFinalizedBlockWithReceipts(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(2)
+	b := NewTestBlockchain(t, headers)
+
+	txn := &types.Transaction{Nonce: 1, GasPrice: big.NewInt(1), Value: big.NewInt(1)}
+	txn.ComputeHash()
+
+	receipt := &types.Receipt{CumulativeGasUsed: 21000, GasUsed: 21000, TxHash: txn.Hash}
+	receipt.SetStatus(types.ReceiptSuccess)
+
+	header := headers[1].Copy()
+	header.Number = 2
+	header.ParentHash = headers[1].Hash
+	header.TxRoot = buildroot.CalculateTransactionsRoot([]*types.Transaction{txn})
+	header.ReceiptsRoot = buildroot.CalculateReceiptsRoot([]*types.Receipt{receipt})
+	header.ComputeHash()
+
+	block := &types.Block{Header: header, Transactions: []*types.Transaction{txn}}
+
+	// the receipts have to match the header
+	assert.ErrorIs(t, b.WriteFinalizedBlockWithReceipts(block, []*types.Receipt{}, "test"), ErrInvalidReceiptsSize)
+	assert.ErrorIs(t,
+		b.WriteFinalizedBlockWithReceipts(block, []*types.Receipt{{TxHash: txn.Hash}}, "test"),
+		ErrInvalidReceiptsRoot,
+	)
+	assert.Equal(t, headers[1].Hash, b.Header().Hash)
+
+	assert.NoError(t, b.WriteFinalizedBlockWithReceipts(block, []*types.Receipt{receipt}, "test"))
+	assert.Equal(t, header.Hash, b.Header().Hash)
+
+	// the block is served without being executed
+	body, ok := b.GetBodyByHash(header.Hash)
+	assert.True(t, ok)
+	assert.Len(t, body.Transactions, 1)
+
+	receipts, err := b.GetReceiptsByHash(header.Hash)
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, txn.Hash, receipts[0].TxHash)
+
+	entry, ok := b.ReadTxLookup(txn.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, header.Hash, entry.BlockHash)
+
+	// a block already written is skipped
+	assert.NoError(t, b.WriteFinalizedBlockWithReceipts(block, []*types.Receipt{receipt}, "test"))
+}
+
+func TestBlockchain_RetainedBlocks(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers[:2])
+	b.SetRetainedBlocks(3)
+
+	for _, header := range headers[2:] {
+		// the receipts of the verification phase
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header}, "test"))
+	}
+
+	// the blocks older than the window keep their headers only
+	for _, header := range headers[2:] {
+		retained := header.Number > 4
+
+		_, ok := b.GetBodyByHash(header.Hash)
+		assert.Equal(t, retained, ok, header.Number)
+
+		_, err := b.GetReceiptsByHash(header.Hash)
+		assert.Equal(t, retained, err == nil, header.Number)
+
+		_, ok = b.GetHeaderByHash(header.Hash)
+		assert.True(t, ok)
+	}
+}
+
+func TestBlockchain_TxLookupLimit(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers[:2])
+	b.SetTxLookupLimit(3)
+
+	txs := make([]*types.Transaction, len(headers))
+
+	for _, header := range headers[2:] {
+		txn := &types.Transaction{Nonce: header.Number, GasPrice: big.NewInt(1), Value: big.NewInt(1)}
+		txn.ComputeHash()
+		txs[header.Number] = txn
+
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header, Transactions: []*types.Transaction{txn}}, "test"))
+	}
+
+	// the transactions of the blocks older than the limit aren't looked up anymore
+	assert.Equal(t, uint64(5), b.db.ReadTxLookupTail())
+
+	for _, header := range headers[2:] {
+		indexed := header.Number >= 5
+
+		entry, ok := b.ReadTxLookup(txs[header.Number].Hash)
+		assert.Equal(t, indexed, ok, header.Number)
+
+		if indexed {
+			assert.Equal(t, header.Hash, entry.BlockHash)
+			assert.Equal(t, header.Number, entry.BlockNumber)
+			assert.Zero(t, entry.Index)
+			assert.True(t, entry.Positioned)
+		}
+
+		// the blocks keep their bodies
+		_, ok = b.GetBodyByHash(header.Hash)
+		assert.True(t, ok)
+	}
+}
+
+func TestBlockchain_RecoverHead(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(6)
+	b := NewTestBlockchain(t, headers[:4])
+
+	// the head is consistent
+	header, diff, err := b.recoverHead(headers[3].Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, headers[3].Hash, header.Hash)
+	assert.Equal(t, big.NewInt(6), diff)
+
+	// a block written one entry after the other, interrupted before its total difficulty,
+	// and a canonical number left above the head
+	assert.NoError(t, b.db.WriteHeader(headers[4]))
+	assert.NoError(t, b.db.WriteHeadHash(headers[4].Hash))
+	assert.NoError(t, b.db.WriteCanonicalHash(5, headers[5].Hash))
+
+	header, diff, err = b.recoverHead(headers[4].Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, headers[3].Hash, header.Hash)
+	assert.Equal(t, big.NewInt(6), diff)
+
+	head, ok := b.db.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[3].Hash, head)
+
+	number, ok := b.db.ReadHeadNumber()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), number)
+
+	_, ok = b.db.ReadCanonicalHash(5)
+	assert.False(t, ok)
+
+	// the block is written again on top of the recovered head
+	b.receiptsCache.Add(headers[4].Hash, []*types.Receipt{})
+
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: headers[4]}, "test"))
+	assert.Equal(t, headers[4].Hash, b.Header().Hash)
+}
+
+func TestBlockchain_FreezeBlocks(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers[:2])
+
+	freezer, err := storage.NewFreezer(t.TempDir())
+	assert.NoError(t, err)
+
+	b.SetFreezer(freezer, 3)
+
+	for _, header := range headers[2:] {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header}, "test"))
+	}
+
+	// the blocks older than the threshold are moved to the freezer, the genesis included
+	assert.Equal(t, uint64(4), b.db.Frozen())
+
+	for _, header := range headers {
+		b.headersCache.Remove(header.Hash)
+		b.receiptsCache.Remove(header.Hash)
+
+		found, ok := b.GetHeaderByHash(header.Hash)
+		assert.True(t, ok, header.Number)
+		assert.Equal(t, header.Hash, found.Hash)
+
+		if header.Number < 2 {
+			continue
+		}
+
+		_, ok = b.GetBodyByHash(header.Hash)
+		assert.True(t, ok, header.Number)
+
+		receipts, err := b.GetReceiptsByHash(header.Hash)
+		assert.NoError(t, err, header.Number)
+		assert.Equal(t, header.Number, receipts[0].CumulativeGasUsed)
+	}
+
+	// the frozen blocks can't be discarded
+	_, err = b.DiscardBlocks(3, "test")
+	assert.ErrorIs(t, err, ErrInvalidDiscardRange)
+}
+
+func TestPruneBlocks(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers[:2])
+
+	for _, header := range headers[2:] {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header}, "test"))
+	}
+
+	// the blocks are pruned down to the first one without a body
+	pruned, err := PruneBlocks(b.db, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), pruned)
+
+	for _, header := range headers[2:] {
+		_, err := b.db.ReadBody(header.Hash)
+		assert.Equal(t, header.Number > 4, err == nil, header.Number)
+
+		_, err = b.db.ReadReceipts(header.Hash)
+		assert.Equal(t, header.Number > 4, err == nil, header.Number)
+	}
+
+	pruned, err = PruneBlocks(b.db, 3)
+	assert.NoError(t, err)
+	assert.Zero(t, pruned)
+}
+
+func TestRetainedStateRoots(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers)
+
+	// the state of the head isn't written yet
+	checked := 0
+	roots, err := RetainedStateRoots(b.db, 3, func(types.Hash) bool {
+		checked++
+
+		return checked > 1
+	})
+	assert.NoError(t, err)
+	assert.Len(t, roots, 3)
+	assert.Equal(t, 4, checked)
+
+	// the test chain has no genesis header, the walk stops at the genesis
+	assert.NoError(t, b.db.WriteHeader(headers[0]))
+
+	_, err = RetainedStateRoots(b.db, 3, func(types.Hash) bool {
+		return false
+	})
+	assert.ErrorIs(t, err, errNoRetainedState)
+}
+
+func TestBlockchain_DiscardBlocks(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(8)
+	b := NewTestBlockchain(t, headers[:2])
+
+	for _, header := range headers[2:] {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header}, "test"))
+	}
+
+	_, err := b.DiscardBlocks(0, "test")
+	assert.ErrorIs(t, err, ErrInvalidDiscardRange)
+
+	_, err = b.DiscardBlocks(8, "test")
+	assert.ErrorIs(t, err, ErrInvalidDiscardRange)
+
+	discarded, err := b.DiscardBlocks(5, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), discarded)
+	assert.Equal(t, headers[4].Hash, b.Header().Hash)
+
+	// the discarded blocks lose their bodies and receipts, the others keep them
+	for _, header := range headers[2:] {
+		kept := header.Number < 5
+
+		_, ok := b.GetBodyByHash(header.Hash)
+		assert.Equal(t, kept, ok, header.Number)
+
+		_, err := b.GetReceiptsByHash(header.Hash)
+		assert.Equal(t, kept, err == nil, header.Number)
+	}
+
+	// the discarded blocks can be written again
+	for _, header := range headers[5:] {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{{CumulativeGasUsed: header.Number}})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header}, "test"))
+	}
+
+	assert.Equal(t, headers[7].Hash, b.Header().Hash)
+
+	_, ok := b.GetBodyByHash(headers[7].Hash)
+	assert.True(t, ok)
+}
+
+func TestBlockchain_SideChainReorg(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(6)
+	b := NewTestBlockchain(t, headers[:3])
+
+	txs := make([]*types.Transaction, 4)
+	for i := range txs {
+		txs[i] = &types.Transaction{Nonce: uint64(i), GasPrice: big.NewInt(1), Value: big.NewInt(1)}
+		txs[i].ComputeHash()
+	}
+
+	writeBlock := func(header *types.Header, txs ...*types.Transaction) {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{})
+
+		assert.NoError(t, b.WriteBlock(&types.Block{Header: header, Transactions: txs}, "test"))
+	}
+
+	writeBlock(headers[3], txs[0])
+	writeBlock(headers[4], txs[1])
+	writeBlock(headers[5])
+
+	// a branch of the same weight is stored aside
+	side := AppendNewTestheadersWithSeed(headers[:3], 4, 1)
+
+	writeBlock(side[3], txs[0])
+	writeBlock(side[4])
+	writeBlock(side[5], txs[2])
+
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	body, ok := b.GetBodyByHash(side[5].Hash)
+	assert.True(t, ok)
+	assert.Len(t, body.Transactions, 1)
+
+	_, ok = b.db.ReadTxLookup(txs[2].Hash)
+	assert.False(t, ok)
+
+	// the side branch becomes heavier
+	sub := b.SubscribeEvents()
+	defer sub.Close()
+
+	writeBlock(side[6], txs[3])
+
+	evnt := sub.GetEvent()
+	assert.Equal(t, EventReorg, evnt.Type)
+	assert.Equal(t, side[6].Hash, b.Header().Hash)
+
+	hashes := func(headers []*types.Header) []types.Hash {
+		res := make([]types.Hash, len(headers))
+		for i, header := range headers {
+			res[i] = header.Hash
+		}
+
+		return res
+	}
+
+	assert.ElementsMatch(t, hashes(headers[3:]), hashes(evnt.OldChain))
+	assert.ElementsMatch(t, hashes(side[3:]), hashes(evnt.NewChain))
+
+	for _, header := range side[3:] {
+		canonical, ok := b.GetHeaderByNumber(header.Number)
+		assert.True(t, ok)
+		assert.Equal(t, header.Hash, canonical.Hash)
+	}
+
+	// the txn lookups follow the canonical chain
+	lookups := map[types.Hash]types.Hash{
+		txs[0].Hash: side[3].Hash,
+		txs[2].Hash: side[5].Hash,
+		txs[3].Hash: side[6].Hash,
+	}
+
+	for txHash, blockHash := range lookups {
+		entry, ok := b.db.ReadTxLookup(txHash)
+		assert.True(t, ok)
+		assert.Equal(t, blockHash, entry.BlockHash)
+	}
+
+	_, ok = b.db.ReadTxLookup(txs[1].Hash)
+	assert.False(t, ok)
+
+	// the replaced blocks are kept as side chain blocks
+	_, ok = b.GetBodyByHash(headers[4].Hash)
+	assert.True(t, ok)
+}
+
+func TestBlockchain_RecoverSenders(t *testing.T) {
+	t.Parallel()
+
+	b := NewTestBlockchain(t, NewTestHeaders(2))
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	tx, err := crypto.NewEIP155Signer(0).SignTx(&types.Transaction{
+		Nonce:    1,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}, key)
+	assert.NoError(t, err)
+
+	block := &types.Block{
+		Header:       &types.Header{Number: 1},
+		Transactions: []*types.Transaction{tx},
+	}
+
+	assert.NoError(t, b.RecoverSenders(block))
+	assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), tx.From)
+
+	// a transaction signed for another chain has no valid sender
+	otherTx, err := crypto.NewEIP155Signer(100).SignTx(&types.Transaction{
+		Nonce:    1,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}, key)
+	assert.NoError(t, err)
+
+	block.Transactions = []*types.Transaction{otherTx}
+
+	assert.ErrorIs(t, b.RecoverSenders(block), ErrInvalidTxSignature)
+}
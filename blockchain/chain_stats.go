@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// chainStatsWindow is the number of recent blocks the rolling aggregates are computed over
+	chainStatsWindow = 256
+
+	// activeAddressesDays is the number of recent days the active addresses are counted for
+	activeAddressesDays = 7
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+// ChainStats are the rolling aggregates of the recent blocks
+type ChainStats struct {
+	// FromBlock and ToBlock are the first and the last block of the aggregated window
+	FromBlock uint64
+	ToBlock   uint64
+
+	// TPS is the number of transactions per second over the window
+	TPS float64
+
+	// AvgGasUsed is the average gas used per block
+	AvgGasUsed float64
+
+	// AvgBlockFullness is the average ratio of the gas used to the gas limit, between 0 and 1
+	AvgBlockFullness float64
+
+	// ActiveAddresses are the unique senders and recipients of each recent day, oldest first.
+	// They are only counted for the blocks written since the node started
+	ActiveAddresses []DailyActiveAddresses
+}
+
+// DailyActiveAddresses is the number of unique addresses active during a day
+type DailyActiveAddresses struct {
+	// Day is the unix timestamp of the start of the day (UTC)
+	Day   uint64
+	Count uint64
+}
+
+// blockStat is the data the aggregates keep about a block of the window
+type blockStat struct {
+	number    uint64
+	timestamp uint64
+	txs       uint64
+	gasUsed   uint64
+	fullness  float64
+}
+
+// chainStats maintains the rolling aggregates incrementally, as the blocks are written
+type chainStats struct {
+	sync.RWMutex
+
+	// blocks of the window, oldest first
+	blocks []blockStat
+
+	// sums over the window
+	txs      uint64
+	gasUsed  uint64
+	fullness float64
+
+	// unique active addresses, indexed by day
+	activeAddresses map[uint64]map[types.Address]struct{}
+}
+
+func newChainStats() *chainStats {
+	return &chainStats{
+		blocks:          make([]blockStat, 0, chainStatsWindow),
+		activeAddresses: make(map[uint64]map[types.Address]struct{}),
+	}
+}
+
+// addBlock adds the block to the window, evicting the oldest block if the window is full.
+// The blocks of the window at the same or a higher height, replaced by a reorg, are removed
+func (s *chainStats) addBlock(header *types.Header, numTxs int) {
+	s.Lock()
+	defer s.Unlock()
+
+	if header.Number > 0 {
+		s.removeAbove(header.Number - 1)
+	}
+
+	stat := blockStat{
+		number:    header.Number,
+		timestamp: header.Timestamp,
+		txs:       uint64(numTxs),
+		gasUsed:   header.GasUsed,
+	}
+
+	if header.GasLimit > 0 {
+		stat.fullness = float64(header.GasUsed) / float64(header.GasLimit)
+	}
+
+	if len(s.blocks) == chainStatsWindow {
+		s.removeOldest()
+	}
+
+	s.blocks = append(s.blocks, stat)
+
+	s.txs += stat.txs
+	s.gasUsed += stat.gasUsed
+	s.fullness += stat.fullness
+}
+
+// addActiveAddresses adds the senders and the recipients of the block transactions
+// to the active addresses of the block day
+func (s *chainStats) addActiveAddresses(block *types.Block) {
+	s.Lock()
+	defer s.Unlock()
+
+	day := block.Header.Timestamp / secondsPerDay * secondsPerDay
+
+	addresses, ok := s.activeAddresses[day]
+	if !ok {
+		addresses = make(map[types.Address]struct{})
+		s.activeAddresses[day] = addresses
+
+		// keep the most recent days only
+		for d := range s.activeAddresses {
+			if d+activeAddressesDays*secondsPerDay <= day {
+				delete(s.activeAddresses, d)
+			}
+		}
+	}
+
+	for _, tx := range block.Transactions {
+		if tx.From != types.ZeroAddress {
+			addresses[tx.From] = struct{}{}
+		}
+
+		if tx.To != nil {
+			addresses[*tx.To] = struct{}{}
+		}
+	}
+}
+
+// rewind removes the blocks above the given number from the window
+func (s *chainStats) rewind(number uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.removeAbove(number)
+}
+
+// removeAbove removes the blocks above the given number from the window
+func (s *chainStats) removeAbove(number uint64) {
+	for len(s.blocks) > 0 && s.blocks[len(s.blocks)-1].number > number {
+		last := s.blocks[len(s.blocks)-1]
+
+		s.txs -= last.txs
+		s.gasUsed -= last.gasUsed
+		s.fullness -= last.fullness
+
+		s.blocks = s.blocks[:len(s.blocks)-1]
+	}
+}
+
+// removeOldest evicts the oldest block of the window
+func (s *chainStats) removeOldest() {
+	oldest := s.blocks[0]
+
+	s.txs -= oldest.txs
+	s.gasUsed -= oldest.gasUsed
+	s.fullness -= oldest.fullness
+
+	s.blocks = append(s.blocks[:0], s.blocks[1:]...)
+}
+
+// get returns the aggregates of the current window
+func (s *chainStats) get() *ChainStats {
+	s.RLock()
+	defer s.RUnlock()
+
+	res := &ChainStats{
+		ActiveAddresses: make([]DailyActiveAddresses, 0, len(s.activeAddresses)),
+	}
+
+	for day, addresses := range s.activeAddresses {
+		res.ActiveAddresses = append(res.ActiveAddresses, DailyActiveAddresses{
+			Day:   day,
+			Count: uint64(len(addresses)),
+		})
+	}
+
+	sort.Slice(res.ActiveAddresses, func(i, j int) bool {
+		return res.ActiveAddresses[i].Day < res.ActiveAddresses[j].Day
+	})
+
+	if len(s.blocks) == 0 {
+		return res
+	}
+
+	first, last := s.blocks[0], s.blocks[len(s.blocks)-1]
+
+	res.FromBlock = first.number
+	res.ToBlock = last.number
+	res.AvgGasUsed = float64(s.gasUsed) / float64(len(s.blocks))
+	res.AvgBlockFullness = s.fullness / float64(len(s.blocks))
+
+	// the transactions of the first block were produced before the window started
+	if last.timestamp > first.timestamp {
+		res.TPS = float64(s.txs-first.txs) / float64(last.timestamp-first.timestamp)
+	}
+
+	return res
+}
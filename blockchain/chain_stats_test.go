@@ -0,0 +1,113 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainStats(t *testing.T) {
+	t.Parallel()
+
+	newHeader := func(number, timestamp, gasUsed uint64) *types.Header {
+		return &types.Header{
+			Number:    number,
+			Timestamp: timestamp,
+			GasUsed:   gasUsed,
+			GasLimit:  100,
+		}
+	}
+
+	t.Run("should aggregate the window", func(t *testing.T) {
+		t.Parallel()
+
+		s := newChainStats()
+
+		s.addBlock(newHeader(1, 10, 20), 2)
+		s.addBlock(newHeader(2, 12, 40), 4)
+		s.addBlock(newHeader(3, 14, 60), 6)
+
+		res := s.get()
+
+		assert.Equal(t, uint64(1), res.FromBlock)
+		assert.Equal(t, uint64(3), res.ToBlock)
+		assert.Equal(t, 2.5, res.TPS)
+		assert.Equal(t, float64(40), res.AvgGasUsed)
+		assert.InDelta(t, 0.4, res.AvgBlockFullness, 1e-9)
+	})
+
+	t.Run("should evict the oldest blocks", func(t *testing.T) {
+		t.Parallel()
+
+		s := newChainStats()
+
+		for n := uint64(1); n <= chainStatsWindow+10; n++ {
+			s.addBlock(newHeader(n, n, n), 1)
+		}
+
+		res := s.get()
+
+		assert.Equal(t, uint64(11), res.FromBlock)
+		assert.Equal(t, uint64(chainStatsWindow+10), res.ToBlock)
+		assert.Equal(t, float64(1), res.TPS)
+		assert.Equal(t, float64(11+chainStatsWindow+10)/2, res.AvgGasUsed)
+	})
+
+	t.Run("should remove the rewound and the reorganized blocks", func(t *testing.T) {
+		t.Parallel()
+
+		s := newChainStats()
+
+		s.addBlock(newHeader(1, 10, 10), 1)
+		s.addBlock(newHeader(2, 11, 20), 1)
+		s.addBlock(newHeader(3, 12, 30), 1)
+
+		// block 3 is replaced
+		s.addBlock(newHeader(3, 12, 60), 1)
+
+		res := s.get()
+		assert.Equal(t, uint64(3), res.ToBlock)
+		assert.Equal(t, float64(30), res.AvgGasUsed)
+
+		s.rewind(1)
+
+		res = s.get()
+		assert.Equal(t, uint64(1), res.ToBlock)
+		assert.Equal(t, float64(10), res.AvgGasUsed)
+		assert.Equal(t, float64(0), res.TPS)
+	})
+
+	t.Run("should count the unique active addresses per day", func(t *testing.T) {
+		t.Parallel()
+
+		s := newChainStats()
+
+		newBlock := func(timestamp uint64, from, to types.Address) *types.Block {
+			return &types.Block{
+				Header: &types.Header{Timestamp: timestamp},
+				Transactions: []*types.Transaction{
+					{From: from, To: &to},
+				},
+			}
+		}
+
+		addr1, addr2, addr3 := types.Address{0x1}, types.Address{0x2}, types.Address{0x3}
+
+		s.addActiveAddresses(newBlock(10, addr1, addr2))
+		s.addActiveAddresses(newBlock(20, addr2, addr1))
+		s.addActiveAddresses(newBlock(secondsPerDay+10, addr1, addr3))
+
+		assert.Equal(t, []DailyActiveAddresses{
+			{Day: 0, Count: 2},
+			{Day: secondsPerDay, Count: 2},
+		}, s.get().ActiveAddresses)
+
+		// the first day is out of the counted days
+		s.addActiveAddresses(newBlock(activeAddressesDays*secondsPerDay, addr1, addr2))
+
+		res := s.get().ActiveAddresses
+		assert.Len(t, res, 2)
+		assert.Equal(t, uint64(secondsPerDay), res[0].Day)
+	})
+}
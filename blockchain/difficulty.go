@@ -0,0 +1,76 @@
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// recoverTotalDifficulty computes the total difficulty of the header missing from the difficulty index,
+// from its closest indexed ancestor. The difficulties of the walked headers are indexed and cached,
+// so that the ancestors aren't walked again by the fork choice or the peer status
+func (b *Blockchain) recoverTotalDifficulty(hash types.Hash) (*big.Int, bool) {
+	var (
+		missing  = []*types.Header{}
+		parentTD = big.NewInt(0)
+	)
+
+	for current := hash; ; {
+		if td, ok := b.difficultyCache.Get(current); ok {
+			parentTD, _ = td.(*big.Int)
+
+			break
+		}
+
+		if td, ok := b.db.ReadTotalDifficulty(current); ok {
+			parentTD = td
+
+			break
+		}
+
+		header, ok := b.readHeader(current)
+		if !ok {
+			return nil, false
+		}
+
+		missing = append(missing, header)
+
+		if header.Number == 0 {
+			// the genesis difficulty isn't indexed either
+			break
+		}
+
+		current = header.ParentHash
+	}
+
+	if parentTD == nil {
+		return nil, false
+	}
+
+	batch := b.db.NewWriteBatch()
+	td := new(big.Int).Set(parentTD)
+
+	// the missing headers are walked from the child to the ancestors
+	for i := len(missing) - 1; i >= 0; i-- {
+		header := missing[i]
+
+		td = new(big.Int).Add(td, new(big.Int).SetUint64(header.Difficulty))
+
+		if err := batch.WriteTotalDifficulty(header.Hash, td); err != nil {
+			b.logger.Error("failed to index the total difficulty", "hash", header.Hash, "err", err)
+
+			return td, true
+		}
+
+		b.difficultyCache.Add(header.Hash, td)
+	}
+
+	if err := batch.Write(); err != nil {
+		// the difficulties are recovered again after a restart
+		b.logger.Error("failed to index the recovered total difficulties", "hash", hash, "err", err)
+	}
+
+	b.logger.Debug("recovered total difficulty", "hash", hash, "walked headers", len(missing))
+
+	return td, true
+}
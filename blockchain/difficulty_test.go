@@ -0,0 +1,37 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchain_RecoverTotalDifficulty(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(5)
+	b := NewTestBlockchain(t, headers)
+
+	// the headers of the difficulties 5 to 7 are written without their difficulty
+	missing := AppendNewTestHeaders(headers, 3)[5:]
+	for _, header := range missing {
+		assert.NoError(t, b.db.WriteHeader(header))
+	}
+
+	td, ok := b.GetTD(missing[2].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10+5+6+7), td.Uint64())
+
+	// the walked headers are indexed
+	for i, expected := range []uint64{15, 21, 28} {
+		td, ok := b.db.ReadTotalDifficulty(missing[i].Hash)
+		if assert.True(t, ok) {
+			assert.Equal(t, expected, td.Uint64())
+		}
+	}
+
+	// the unknown headers have no difficulty
+	_, ok = b.GetTD(types.StringToHash("unknown"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,82 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/pebble"
+	"github.com/hashicorp/go-hclog"
+)
+
+var ErrEngineMismatch = errors.New("database written by another engine")
+
+// OpenDatabase opens the blockchain database at the given path with the given engine, creating it
+// if needed. A database written by the other engine isn't opened, it has to be migrated first
+func OpenDatabase(path string, engine storage.Engine) (storage.Database, error) {
+	detected, ok, err := storage.DetectEngine(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && detected != engine {
+		return nil, fmt.Errorf("%w: %s is written by %s, not %s", ErrEngineMismatch, path, detected, engine)
+	}
+
+	switch engine {
+	case storage.EngineLevelDB:
+		return leveldb.OpenDatabase(path)
+	case storage.EnginePebble:
+		return pebble.OpenDatabase(path)
+	default:
+		return nil, fmt.Errorf("%w: %s", storage.ErrUnknownEngine, engine)
+	}
+}
+
+var errNoDatabase = errors.New("no blockchain database found")
+
+// OpenExistingDatabase opens the blockchain database at the given path with the engine it is written by
+func OpenExistingDatabase(path string) (storage.Database, storage.Engine, error) {
+	engine, ok, err := storage.DetectEngine(path)
+	if err != nil {
+		return nil, engine, err
+	}
+
+	if !ok {
+		return nil, engine, fmt.Errorf("%w: %s", errNoDatabase, path)
+	}
+
+	db, err := OpenDatabase(path, engine)
+
+	return db, engine, err
+}
+
+// ancientFolder is the folder of the freezer in the blockchain folder
+const ancientFolder = "ancient"
+
+// OpenExistingStorage opens the blockchain storage at the given path of a stopped node,
+// the blocks moved to its freezer, if it has one, are read from it
+func OpenExistingStorage(path string, logger hclog.Logger) (storage.Storage, error) {
+	kv, engine, err := OpenExistingDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := storage.NewKeyValueStorage(logger.Named(engine.String()), kv)
+
+	if info, statErr := os.Stat(filepath.Join(path, ancientFolder)); statErr == nil && info.IsDir() {
+		freezer, freezerErr := storage.NewFreezer(filepath.Join(path, ancientFolder))
+		if freezerErr != nil {
+			_ = db.Close()
+
+			return nil, freezerErr
+		}
+
+		db.SetFreezer(freezer)
+	}
+
+	return db, nil
+}
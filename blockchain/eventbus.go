@@ -0,0 +1,393 @@
+package blockchain
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// Topic is the type of the chain events a subscription of the event bus receives
+type Topic int
+
+const (
+	TopicNewHeads  Topic = iota // The headers joining the canonical chain, oldest first
+	TopicReorgs                 // The chain reorganizations
+	TopicFinalized              // The canonical headers reaching the finality depth
+	TopicLogs                   // The receipts of the blocks joining or leaving the canonical chain
+)
+
+// String returns the name of the topic
+func (t Topic) String() string {
+	switch t {
+	case TopicNewHeads:
+		return "newHeads"
+	case TopicReorgs:
+		return "reorgs"
+	case TopicFinalized:
+		return "finalized"
+	case TopicLogs:
+		return "logs"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultBusBuffer is the number of events queued for a subscriber by default
+const DefaultBusBuffer = 256
+
+// FinalityDepth is the number of blocks a canonical block is behind the head
+// when it is published as finalized, the chain isn't expected to be reorganized deeper
+const FinalityDepth = 64
+
+// HeadEvent is the event of a header joining the canonical chain
+type HeadEvent struct {
+	Header *types.Header
+
+	// Difficulty is the total difficulty of the header, nil if it isn't known
+	Difficulty *big.Int
+
+	// Source is the source that wrote the block
+	Source string
+}
+
+// LogsEvent is the event of the receipts of a block joining the canonical chain,
+// or leaving it if Removed is set. The receipts have their transaction hash
+type LogsEvent struct {
+	Header   *types.Header
+	Receipts []*types.Receipt
+	Removed  bool
+}
+
+// BusEvent is an event of the event bus, its payload is the field of its topic
+type BusEvent struct {
+	Topic Topic
+
+	Head      *HeadEvent    // TopicNewHeads
+	Reorg     *Event        // TopicReorgs, an event of the EventReorg type
+	Finalized *types.Header // TopicFinalized
+	Logs      *LogsEvent    // TopicLogs
+
+	// chainType is the type of the chain event the event is published for
+	chainType EventType
+}
+
+// chainEventType returns the type of the chain event the event is published for
+func (e *BusEvent) chainEventType() EventType {
+	if e.Topic == TopicReorgs && e.Reorg != nil {
+		return e.Reorg.Type
+	}
+
+	return e.chainType
+}
+
+// BusSubscription is a subscription to one or more topics of the event bus.
+// The events are queued in a buffered channel, in the order they are published.
+// When a slow subscriber fills its queue, its oldest event is dropped for the new one
+type BusSubscription struct {
+	bus     *EventBus
+	topics  map[Topic]bool
+	eventCh chan *BusEvent
+
+	// filter narrows the events down before they are queued, guarded by the bus lock
+	filter *EventFilter
+
+	// dropped is the number of events dropped for the subscriber, guarded by the bus lock
+	dropped uint64
+	// lagging is set while the events are dropped, so that a lag is logged once
+	lagging bool
+	closed  bool
+}
+
+// EventCh returns the channel of the events, closed once the subscription is cancelled
+func (s *BusSubscription) EventCh() <-chan *BusEvent {
+	return s.eventCh
+}
+
+// Dropped returns the number of events dropped because the subscriber was too slow
+func (s *BusSubscription) Dropped() uint64 {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+
+	return s.dropped
+}
+
+// SetFilter replaces the filter of the subscription, the events queued already aren't filtered again
+func (s *BusSubscription) SetFilter(filter *EventFilter) {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+
+	s.filter = filter
+}
+
+// Unsubscribe cancels the subscription and closes its channel
+func (s *BusSubscription) Unsubscribe() {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+
+	s.bus.remove(s)
+}
+
+// push queues the event, dropping the oldest queued events if the queue is full.
+// It doesn't block, as the publisher is the only sender and holds the bus lock
+func (s *BusSubscription) push(evnt *BusEvent) (dropped uint64) {
+	for {
+		select {
+		case s.eventCh <- evnt:
+			return dropped
+		default:
+		}
+
+		select {
+		case <-s.eventCh:
+			dropped++
+		default:
+		}
+	}
+}
+
+// EventBus dispatches the typed chain events to the subscribers of their topics
+type EventBus struct {
+	lock          sync.Mutex
+	logger        hclog.Logger
+	subscriptions map[*BusSubscription]struct{}
+}
+
+// NewEventBus creates a new event bus without subscribers
+func NewEventBus(logger hclog.Logger) *EventBus {
+	return &EventBus{
+		logger:        logger,
+		subscriptions: make(map[*BusSubscription]struct{}),
+	}
+}
+
+// Subscribe subscribes to the events of the given topics, up to buffer events are queued
+func (e *EventBus) Subscribe(buffer int, topics ...Topic) *BusSubscription {
+	return e.SubscribeFiltered(buffer, nil, topics...)
+}
+
+// SubscribeFiltered subscribes to the events of the given topics matching the filter,
+// up to buffer events are queued. All the events of the topics are received if the filter is nil
+func (e *EventBus) SubscribeFiltered(buffer int, filter *EventFilter, topics ...Topic) *BusSubscription {
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	sub := &BusSubscription{
+		bus:     e,
+		topics:  make(map[Topic]bool, len(topics)),
+		eventCh: make(chan *BusEvent, buffer),
+		filter:  filter,
+	}
+
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+// HasSubscribers returns whether the topic has a subscriber, so that the events
+// which are expensive to build are only built if they are received
+func (e *EventBus) HasSubscribers(topic Topic) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for sub := range e.subscriptions {
+		if sub.topics[topic] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Publish queues the event for the subscribers of its topic, it never blocks on a slow subscriber.
+// The subscribers with a filter receive the event narrowed down to it, or nothing
+func (e *EventBus) Publish(evnt *BusEvent) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for sub := range e.subscriptions {
+		if !sub.topics[evnt.Topic] {
+			continue
+		}
+
+		filtered := sub.filter.apply(evnt)
+		if filtered == nil {
+			continue
+		}
+
+		dropped := sub.push(filtered)
+		if dropped == 0 {
+			sub.lagging = false
+
+			continue
+		}
+
+		sub.dropped += dropped
+
+		if !sub.lagging {
+			sub.lagging = true
+
+			e.logger.Warn("slow event subscriber, dropping its oldest events", "topic", evnt.Topic)
+		}
+	}
+}
+
+// Close cancels all the subscriptions
+func (e *EventBus) Close() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for sub := range e.subscriptions {
+		e.remove(sub)
+	}
+}
+
+// remove cancels the subscription, the bus lock is held by the caller
+func (e *EventBus) remove(sub *BusSubscription) {
+	if sub.closed {
+		return
+	}
+
+	sub.closed = true
+
+	delete(e.subscriptions, sub)
+	close(sub.eventCh)
+}
+
+// SubscribeBus subscribes to the typed chain events of the given topics, up to buffer events are queued.
+// The subscription is cancelled by its Unsubscribe
+func (b *Blockchain) SubscribeBus(buffer int, topics ...Topic) *BusSubscription {
+	return b.bus.Subscribe(buffer, topics...)
+}
+
+// SubscribeFiltered subscribes to the typed chain events of the given topics matching the filter,
+// so that the events of no interest are neither queued nor copied for the subscriber
+func (b *Blockchain) SubscribeFiltered(buffer int, filter *EventFilter, topics ...Topic) *BusSubscription {
+	return b.bus.SubscribeFiltered(buffer, filter, topics...)
+}
+
+// publishBusEvents publishes the typed events of a chain event: the reorg and the removed logs first,
+// then each new header with its logs, oldest first, and the headers reaching the finality depth.
+// The side chain blocks aren't published
+func (b *Blockchain) publishBusEvents(evnt *Event) {
+	b.busLock.Lock()
+	defer b.busLock.Unlock()
+
+	if evnt.Type == EventFork || len(evnt.NewChain) == 0 {
+		return
+	}
+
+	if evnt.Type == EventReorg {
+		b.bus.Publish(&BusEvent{Topic: TopicReorgs, Reorg: evnt})
+
+		for _, header := range evnt.OldChain {
+			b.publishLogs(header, true, evnt.Type)
+		}
+	}
+
+	publishHeads := b.bus.HasSubscribers(TopicNewHeads)
+
+	// the new chain of an event starts with its head
+	for i := len(evnt.NewChain) - 1; i >= 0; i-- {
+		header := evnt.NewChain[i]
+
+		if publishHeads {
+			difficulty := evnt.Difficulty
+			if i > 0 {
+				difficulty, _ = b.readTotalDifficulty(header.Hash)
+			}
+
+			b.bus.Publish(&BusEvent{
+				Topic: TopicNewHeads,
+				Head: &HeadEvent{
+					Header:     header,
+					Difficulty: difficulty,
+					Source:     evnt.Source,
+				},
+				chainType: evnt.Type,
+			})
+		}
+
+		b.publishLogs(header, false, evnt.Type)
+	}
+
+	b.publishFinalized(evnt.NewChain[0].Number)
+}
+
+// publishLogs publishes the receipts of the block for the chain event of the given type,
+// if it has any and the logs have a subscriber
+func (b *Blockchain) publishLogs(header *types.Header, removed bool, typ EventType) {
+	if !b.bus.HasSubscribers(TopicLogs) {
+		return
+	}
+
+	receipts, err := b.GetReceiptsByHash(header.Hash)
+	if err != nil || len(receipts) == 0 {
+		// the light nodes don't have the receipts
+		return
+	}
+
+	var body *types.Body
+
+	for i, receipt := range receipts {
+		if receipt.TxHash != types.ZeroHash {
+			continue
+		}
+
+		if body == nil {
+			var ok bool
+
+			if body, ok = b.readBody(header.Hash); !ok {
+				return
+			}
+		}
+
+		if i < len(body.Transactions) {
+			receipt.TxHash = body.Transactions[i].Hash
+		}
+	}
+
+	b.bus.Publish(&BusEvent{
+		Topic: TopicLogs,
+		Logs: &LogsEvent{
+			Header:   header,
+			Receipts: receipts,
+			Removed:  removed,
+		},
+		chainType: typ,
+	})
+}
+
+// publishFinalized publishes the canonical headers reaching the finality depth with the given head.
+// The blocks already final when the first event is published aren't published, and a missing header
+// is skipped rather than holding back the following ones
+func (b *Blockchain) publishFinalized(head uint64) {
+	if head < FinalityDepth {
+		return
+	}
+
+	if !b.finalizedInit {
+		b.finalizedInit = true
+		b.nextFinalized = head - FinalityDepth
+	}
+
+	for ; b.nextFinalized+FinalityDepth <= head; b.nextFinalized++ {
+		header, ok := b.GetHeaderByNumber(b.nextFinalized)
+		if !ok {
+			b.logger.Warn("finalized header not found", "number", b.nextFinalized)
+
+			continue
+		}
+
+		b.bus.Publish(&BusEvent{Topic: TopicFinalized, Finalized: header})
+	}
+}
@@ -0,0 +1,203 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHeadBusEvent(number uint64) *BusEvent {
+	return &BusEvent{
+		Topic: TopicNewHeads,
+		Head:  &HeadEvent{Header: &types.Header{Number: number}},
+	}
+}
+
+func TestEventBus_Topics(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus(hclog.NewNullLogger())
+
+	heads := bus.Subscribe(DefaultBusBuffer, TopicNewHeads)
+	all := bus.Subscribe(DefaultBusBuffer, TopicNewHeads, TopicReorgs)
+
+	assert.True(t, bus.HasSubscribers(TopicReorgs))
+	assert.False(t, bus.HasSubscribers(TopicLogs))
+
+	bus.Publish(&BusEvent{Topic: TopicReorgs, Reorg: &Event{Type: EventReorg}})
+	bus.Publish(newHeadBusEvent(1))
+
+	assert.Len(t, heads.EventCh(), 1)
+	assert.Equal(t, TopicNewHeads, (<-heads.EventCh()).Topic)
+
+	// the events of the topics are received in the order they are published
+	assert.Len(t, all.EventCh(), 2)
+	assert.Equal(t, TopicReorgs, (<-all.EventCh()).Topic)
+	assert.Equal(t, TopicNewHeads, (<-all.EventCh()).Topic)
+
+	heads.Unsubscribe()
+
+	_, ok := <-heads.EventCh()
+	assert.False(t, ok)
+
+	// the cancelled subscription doesn't receive the events anymore
+	bus.Publish(newHeadBusEvent(2))
+	assert.Len(t, all.EventCh(), 1)
+
+	bus.Close()
+
+	// the closed bus cancels all the subscriptions
+	all.Unsubscribe()
+	assert.False(t, bus.HasSubscribers(TopicNewHeads))
+}
+
+func TestEventBus_SlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus(hclog.NewNullLogger())
+	sub := bus.Subscribe(2, TopicNewHeads)
+
+	for number := uint64(1); number <= 5; number++ {
+		bus.Publish(newHeadBusEvent(number))
+	}
+
+	// the oldest events are dropped for the latest ones
+	assert.Equal(t, uint64(3), sub.Dropped())
+	assert.Equal(t, uint64(4), (<-sub.EventCh()).Head.Header.Number)
+	assert.Equal(t, uint64(5), (<-sub.EventCh()).Head.Header.Number)
+}
+
+// nextBusEvent returns the next queued event of the subscription, it fails the test
+// if none is queued or if it isn't of the given topic
+func nextBusEvent(t *testing.T, sub *BusSubscription, topic Topic) *BusEvent {
+	t.Helper()
+
+	select {
+	case evnt := <-sub.EventCh():
+		if evnt.Topic != topic {
+			t.Fatalf("expected a %s event, got a %s one", topic, evnt.Topic)
+		}
+
+		return evnt
+	default:
+		t.Fatalf("no %s event queued", topic)
+	}
+
+	return nil
+}
+
+func TestBlockchain_PublishBusEvents(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(FinalityDepth + 3)
+	b := NewTestBlockchain(t, headers[:FinalityDepth+1])
+
+	sub := b.SubscribeBus(DefaultBusBuffer, TopicNewHeads, TopicReorgs, TopicFinalized)
+	defer sub.Unsubscribe()
+
+	// the finality is tracked from the first head deep enough, the genesis
+	assert.NoError(t, b.WriteHeaders(headers[FinalityDepth+1:]))
+
+	for _, header := range headers[FinalityDepth+1:] {
+		evnt := nextBusEvent(t, sub, TopicNewHeads)
+		assert.Equal(t, header.Hash, evnt.Head.Header.Hash)
+
+		td, ok := b.GetTD(header.Hash)
+		assert.True(t, ok)
+		assert.Equal(t, td, evnt.Head.Difficulty)
+
+		evnt = nextBusEvent(t, sub, TopicFinalized)
+		assert.Equal(t, header.Number-FinalityDepth, evnt.Finalized.Number)
+	}
+
+	// the rewind is a reorg to the new head
+	assert.NoError(t, b.RewindTo(FinalityDepth, "test"))
+
+	evnt := nextBusEvent(t, sub, TopicReorgs)
+	assert.Len(t, evnt.Reorg.OldChain, 2)
+
+	evnt = nextBusEvent(t, sub, TopicNewHeads)
+	assert.Equal(t, headers[FinalityDepth].Hash, evnt.Head.Header.Hash)
+	assert.Equal(t, "test", evnt.Head.Source)
+
+	assert.Len(t, sub.EventCh(), 0)
+}
+
+func TestEventBus_Filter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr1  = types.StringToAddress("1")
+		addr2  = types.StringToAddress("2")
+		topic1 = types.StringToHash("1")
+		topic2 = types.StringToHash("2")
+	)
+
+	logsEvent := &BusEvent{
+		Topic: TopicLogs,
+		Logs: &LogsEvent{
+			Header: &types.Header{Number: 1},
+			Receipts: []*types.Receipt{
+				{Logs: []*types.Log{{Address: addr1, Topics: []types.Hash{topic1}}}},
+				{Logs: []*types.Log{
+					{Address: addr2, Topics: []types.Hash{topic1}},
+					{Address: addr1, Topics: []types.Hash{topic2}},
+				}},
+			},
+		},
+		chainType: EventHead,
+	}
+
+	bus := NewEventBus(hclog.NewNullLogger())
+
+	all := bus.Subscribe(DefaultBusBuffer, TopicLogs, TopicReorgs)
+	reorgs := bus.SubscribeFiltered(
+		DefaultBusBuffer,
+		&EventFilter{Types: []EventType{EventReorg}},
+		TopicLogs,
+		TopicReorgs,
+	)
+	byAddress := bus.SubscribeFiltered(DefaultBusBuffer, &EventFilter{Addresses: []types.Address{addr1}}, TopicLogs)
+	byTopic := bus.SubscribeFiltered(DefaultBusBuffer, &EventFilter{Topics: [][]types.Hash{{topic2}}}, TopicLogs)
+	none := bus.SubscribeFiltered(DefaultBusBuffer, &EventFilter{Addresses: []types.Address{{0x3}}}, TopicLogs)
+
+	bus.Publish(logsEvent)
+	bus.Publish(&BusEvent{Topic: TopicReorgs, Reorg: &Event{Type: EventReorg}})
+
+	// the unfiltered subscriber receives the event itself
+	assert.Len(t, all.EventCh(), 2)
+	assert.Same(t, logsEvent, <-all.EventCh())
+
+	// the logs of a head event aren't received by the reorg subscriber
+	assert.Len(t, reorgs.EventCh(), 1)
+	assert.Equal(t, TopicReorgs, (<-reorgs.EventCh()).Topic)
+
+	// the receipts keep their position, with the matching logs only
+	if assert.Len(t, byAddress.EventCh(), 1) {
+		receipts := (<-byAddress.EventCh()).Logs.Receipts
+
+		assert.Len(t, receipts, 2)
+		assert.Equal(t, logsEvent.Logs.Receipts[0], receipts[0])
+		assert.Equal(t, []*types.Log{logsEvent.Logs.Receipts[1].Logs[1]}, receipts[1].Logs)
+	}
+
+	if assert.Len(t, byTopic.EventCh(), 1) {
+		receipts := (<-byTopic.EventCh()).Logs.Receipts
+
+		assert.Empty(t, receipts[0].Logs)
+		assert.Equal(t, []*types.Log{logsEvent.Logs.Receipts[1].Logs[1]}, receipts[1].Logs)
+	}
+
+	// the event without matching log isn't queued
+	assert.Len(t, none.EventCh(), 0)
+
+	// the filter is replaced for the next events
+	none.SetFilter(nil)
+	bus.Publish(logsEvent)
+	assert.Len(t, none.EventCh(), 1)
+
+	// the published event isn't modified
+	assert.Len(t, logsEvent.Logs.Receipts[1].Logs, 2)
+}
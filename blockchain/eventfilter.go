@@ -0,0 +1,160 @@
+package blockchain
+
+import (
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// EventFilter narrows the events of a subscription down where they are published, so that a subscriber
+// interested in a few event types or contracts doesn't receive, nor hold, the events of the whole chain.
+// An empty field matches anything
+type EventFilter struct {
+	// Types are the types of the chain events received. The typed events are matched
+	// with the chain event they are published for
+	Types []EventType
+
+	// Addresses are the contracts whose logs are received, only the typed logs events are filtered
+	Addresses []types.Address
+
+	// Topics are the topics of the received logs by position, an empty position matching any topic.
+	// Only the typed logs events are filtered
+	Topics [][]types.Hash
+}
+
+// matchType returns whether the filter matches the chain event type, a nil filter matches all
+func (f *EventFilter) matchType(typ EventType) bool {
+	if f == nil || len(f.Types) == 0 {
+		return true
+	}
+
+	for _, t := range f.Types {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchLog returns whether the filter matches the address and the topics of the log
+func (f *EventFilter) matchLog(log *types.Log) bool {
+	if len(f.Addresses) > 0 {
+		match := false
+
+		for _, addr := range f.Addresses {
+			if addr == log.Address {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, sub := range f.Topics {
+		match := len(sub) == 0
+
+		for _, topic := range sub {
+			if log.Topics[i] == topic {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterLogs returns the logs event with the matching logs only, the event itself if all match,
+// or nil if none does. The receipts keep their position, the ones with filtered out logs are copied
+func (f *EventFilter) filterLogs(evnt *LogsEvent) *LogsEvent {
+	if f == nil || (len(f.Addresses) == 0 && len(f.Topics) == 0) {
+		return evnt
+	}
+
+	var (
+		receipts = make([]*types.Receipt, len(evnt.Receipts))
+		matched  = 0
+		all      = true
+	)
+
+	for i, receipt := range evnt.Receipts {
+		logs := make([]*types.Log, 0, len(receipt.Logs))
+
+		for _, log := range receipt.Logs {
+			if f.matchLog(log) {
+				logs = append(logs, log)
+			}
+		}
+
+		matched += len(logs)
+
+		if len(logs) == len(receipt.Logs) {
+			receipts[i] = receipt
+
+			continue
+		}
+
+		all = false
+
+		filtered := *receipt
+		filtered.Logs = logs
+
+		receipts[i] = &filtered
+	}
+
+	if matched == 0 {
+		return nil
+	}
+
+	if all {
+		return evnt
+	}
+
+	return &LogsEvent{
+		Header:   evnt.Header,
+		Receipts: receipts,
+		Removed:  evnt.Removed,
+	}
+}
+
+// apply returns the bus event as received by a subscriber with the filter, nil if it isn't received
+func (f *EventFilter) apply(evnt *BusEvent) *BusEvent {
+	if f == nil {
+		return evnt
+	}
+
+	if !f.matchType(evnt.chainEventType()) {
+		return nil
+	}
+
+	if evnt.Topic != TopicLogs || evnt.Logs == nil {
+		return evnt
+	}
+
+	logs := f.filterLogs(evnt.Logs)
+	if logs == nil {
+		return nil
+	}
+
+	if logs == evnt.Logs {
+		return evnt
+	}
+
+	return &BusEvent{
+		Topic:     evnt.Topic,
+		Logs:      logs,
+		chainType: evnt.chainType,
+	}
+}
@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrFeesNotTracked = errors.New("fees not tracked for the block range")
+
+	errInvalidFeesRange = errors.New("invalid block range")
+)
+
+// writeFeeLedgerEntry writes the fee accounting of the block, on top of the one of its parent.
+// The fees of a block are only known if it was executed by the node, the ledger restarts
+// from the next executed block otherwise
+func (b *Blockchain) writeFeeLedgerEntry(batch storage.WriteBatch, header *types.Header) error {
+	cached, ok := b.feesCache.Get(header.Hash)
+	if !ok {
+		return nil
+	}
+
+	fees, ok := cached.(*types.Fees)
+	if !ok {
+		return errors.New("invalid type assertion for fees")
+	}
+
+	entry := &storage.FeeLedgerEntry{
+		Since:      header.Number,
+		Block:      fees,
+		Cumulative: fees.Copy(),
+	}
+
+	if parent, err := batch.ReadFeeLedgerEntry(header.ParentHash); err == nil {
+		entry.Since = parent.Since
+		entry.Cumulative.Add(parent.Cumulative)
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	} else if header.Number == 1 {
+		// no fees are paid in the genesis block
+		entry.Since = 0
+	}
+
+	return batch.WriteFeeLedgerEntry(header.Hash, entry)
+}
+
+// GetFees returns the fees paid in the canonical blocks of the given range, inclusive
+func (b *Blockchain) GetFees(from, to uint64) (*types.Fees, error) {
+	if from > to || to > b.Header().Number {
+		return nil, errInvalidFeesRange
+	}
+
+	if to == 0 {
+		return types.NewFees(), nil
+	}
+
+	entry, err := b.readCanonicalFeeLedgerEntry(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if from < entry.Since {
+		return nil, fmt.Errorf("%w: tracked since block %d", ErrFeesNotTracked, entry.Since)
+	}
+
+	fees := entry.Cumulative.Copy()
+
+	// the genesis has no ledger entry, as it pays no fees
+	if from > entry.Since && from > 1 {
+		// the fees of the range are the difference of the cumulative fees at its bounds
+		prev, err := b.readCanonicalFeeLedgerEntry(from - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		fees.Sub(prev.Cumulative)
+	}
+
+	return fees, nil
+}
+
+// readCanonicalFeeLedgerEntry reads the fee accounting of the canonical block with the given number
+func (b *Blockchain) readCanonicalFeeLedgerEntry(number uint64) (*storage.FeeLedgerEntry, error) {
+	hash, ok := b.db.ReadCanonicalHash(number)
+	if !ok {
+		return nil, ErrNoBlock
+	}
+
+	entry, err := b.db.ReadFeeLedgerEntry(hash)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrFeesNotTracked
+	}
+
+	return entry, err
+}
@@ -0,0 +1,60 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBlockFees(number uint64) *types.Fees {
+	return &types.Fees{
+		Collected:        big.NewInt(int64(100 * number)),
+		Validators:       big.NewInt(int64(50 * number)),
+		ContractCreators: big.NewInt(int64(10 * number)),
+	}
+}
+
+func TestGetFees(t *testing.T) {
+	t.Parallel()
+
+	b := newTestWrittenBlockchain(t, NewTestHeaders(6), func(b *Blockchain, header *types.Header) {
+		// the fees of the third block aren't known, e.g. it was written without being executed
+		if header.Number != 3 {
+			b.feesCache.Add(header.Hash, testBlockFees(header.Number))
+		}
+	})
+
+	fees, err := b.GetFees(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(300), fees.Collected)
+	assert.Equal(t, big.NewInt(150), fees.Validators)
+	assert.Equal(t, big.NewInt(30), fees.ContractCreators)
+	assert.Equal(t, big.NewInt(120), fees.Burned())
+
+	fees, err = b.GetFees(2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), fees.Collected)
+
+	// the ledger restarts after the block with unknown fees
+	fees, err = b.GetFees(4, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(900), fees.Collected)
+
+	fees, err = b.GetFees(5, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(500), fees.Collected)
+
+	_, err = b.GetFees(2, 4)
+	assert.ErrorIs(t, err, ErrFeesNotTracked)
+
+	_, err = b.GetFees(3, 3)
+	assert.ErrorIs(t, err, ErrFeesNotTracked)
+
+	_, err = b.GetFees(2, 1)
+	assert.ErrorIs(t, err, errInvalidFeesRange)
+
+	_, err = b.GetFees(1, 6)
+	assert.ErrorIs(t, err, errInvalidFeesRange)
+}
@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var ErrNotCanonical = errors.New("header is not canonical")
+
+// SetFinalizedHeader marks the canonical header as finalized, the chain is never reorganized below it.
+// It is driven by the consensus engine, according to its finality rule. The safe header is moved along
+// if it is behind, and an older header than the finalized one is ignored
+func (b *Blockchain) SetFinalizedHeader(header *types.Header) error {
+	b.finalityLock.Lock()
+	defer b.finalityLock.Unlock()
+
+	if err := b.checkCanonical(header); err != nil {
+		return err
+	}
+
+	if finalized := b.FinalizedHeader(); finalized != nil && finalized.Number >= header.Number {
+		return nil
+	}
+
+	b.finalizedHeader.Store(header.Copy())
+
+	if safe := b.SafeHeader(); safe == nil || safe.Number < header.Number {
+		b.safeHeader.Store(header.Copy())
+	}
+
+	return nil
+}
+
+// SetSafeHeader marks the canonical header as safe, unlikely to be reorganized although not finalized yet.
+// It is driven by the consensus engine, and an older header than the safe one is ignored
+func (b *Blockchain) SetSafeHeader(header *types.Header) error {
+	b.finalityLock.Lock()
+	defer b.finalityLock.Unlock()
+
+	if err := b.checkCanonical(header); err != nil {
+		return err
+	}
+
+	if safe := b.SafeHeader(); safe != nil && safe.Number >= header.Number {
+		return nil
+	}
+
+	b.safeHeader.Store(header.Copy())
+
+	return nil
+}
+
+// FinalizedHeader returns the latest finalized header, nil if the consensus engine hasn't finalized any
+func (b *Blockchain) FinalizedHeader() *types.Header {
+	header, ok := b.finalizedHeader.Load().(*types.Header)
+	if !ok {
+		return nil
+	}
+
+	return header
+}
+
+// SafeHeader returns the latest safe header, nil if the consensus engine hasn't marked any as safe
+func (b *Blockchain) SafeHeader() *types.Header {
+	header, ok := b.safeHeader.Load().(*types.Header)
+	if !ok {
+		return nil
+	}
+
+	return header
+}
+
+// checkCanonical checks that the header is the canonical header of its number
+func (b *Blockchain) checkCanonical(header *types.Header) error {
+	canonical, ok := b.GetHeaderByNumber(header.Number)
+	if !ok || canonical.Hash != header.Hash {
+		return fmt.Errorf("%w: %d (%s)", ErrNotCanonical, header.Number, header.Hash)
+	}
+
+	return nil
+}
+
+// rewindFinality moves the finalized and the safe headers back to the given head,
+// when the chain is unwound below them
+func (b *Blockchain) rewindFinality(head *types.Header) {
+	b.finalityLock.Lock()
+	defer b.finalityLock.Unlock()
+
+	if finalized := b.FinalizedHeader(); finalized != nil && finalized.Number > head.Number {
+		b.logger.Warn("unwinding the finalized chain", "finalized", finalized.Number, "head", head.Number)
+
+		b.finalizedHeader.Store(head.Copy())
+	}
+
+	if safe := b.SafeHeader(); safe != nil && safe.Number > head.Number {
+		b.safeHeader.Store(head.Copy())
+	}
+}
@@ -0,0 +1,52 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchain_Finality(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	// nothing is finalized until the consensus engine does
+	assert.Nil(t, b.FinalizedHeader())
+	assert.Nil(t, b.SafeHeader())
+
+	assert.NoError(t, b.SetSafeHeader(headers[6]))
+	assert.Nil(t, b.FinalizedHeader())
+	assert.Equal(t, headers[6].Hash, b.SafeHeader().Hash)
+
+	// the safe header ahead of the finalized one is kept
+	assert.NoError(t, b.SetFinalizedHeader(headers[4]))
+	assert.Equal(t, headers[4].Hash, b.FinalizedHeader().Hash)
+	assert.Equal(t, headers[6].Hash, b.SafeHeader().Hash)
+
+	// the safe header behind the finalized one is moved along
+	assert.NoError(t, b.SetFinalizedHeader(headers[8]))
+	assert.Equal(t, headers[8].Hash, b.FinalizedHeader().Hash)
+	assert.Equal(t, headers[8].Hash, b.SafeHeader().Hash)
+
+	// the older headers are ignored
+	assert.NoError(t, b.SetFinalizedHeader(headers[2]))
+	assert.NoError(t, b.SetSafeHeader(headers[2]))
+	assert.Equal(t, headers[8].Hash, b.FinalizedHeader().Hash)
+	assert.Equal(t, headers[8].Hash, b.SafeHeader().Hash)
+
+	// the headers off the canonical chain are rejected
+	fork := headers[9].Copy()
+	fork.ExtraData = []byte{1}
+	fork.ComputeHash()
+
+	assert.ErrorIs(t, b.SetFinalizedHeader(fork), ErrNotCanonical)
+	assert.ErrorIs(t, b.SetSafeHeader(&types.Header{Number: 20}), ErrNotCanonical)
+
+	// the unwound chain takes the finalized and the safe headers back
+	assert.NoError(t, b.RewindTo(5, "test"))
+	assert.Equal(t, headers[5].Hash, b.FinalizedHeader().Hash)
+	assert.Equal(t, headers[5].Hash, b.SafeHeader().Hash)
+}
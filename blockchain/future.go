@@ -0,0 +1,241 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// DefaultFutureBlockTolerance is the default time a finalized block can be ahead of the local clock,
+	// the blocks further in the future are rejected
+	DefaultFutureBlockTolerance = 30 * time.Second
+
+	// maxFutureBlocks is the maximum number of blocks waiting for their time to be written
+	maxFutureBlocks = 32
+)
+
+var (
+	ErrFutureBlock      = errors.New("block timestamp too far in the future")
+	ErrFutureBlocksFull = errors.New("future block queue is full")
+
+	errFutureBlocksClosed = errors.New("future block queue closed")
+)
+
+// futureBlock is a finalized block waiting for its timestamp to be written
+type futureBlock struct {
+	block  *types.Block
+	source string
+
+	// done is closed once the block is written, err being the error of the write
+	done chan struct{}
+	err  error
+}
+
+// futureBlockQueue holds the finalized blocks whose timestamp is slightly ahead of the local clock,
+// e.g. because of a clock skew between the validators and the node. They are written when their time
+// arrives, rather than being rejected or being written before the time they claim
+type futureBlockQueue struct {
+	lock sync.Mutex
+
+	// tolerance is the time a block can be ahead of the local clock to be queued
+	tolerance time.Duration
+
+	blocks map[types.Hash]*futureBlock
+	timer  *time.Timer
+	closed bool
+
+	// now returns the current time
+	now func() time.Time
+}
+
+func newFutureBlockQueue() *futureBlockQueue {
+	return &futureBlockQueue{
+		tolerance: DefaultFutureBlockTolerance,
+		blocks:    make(map[types.Hash]*futureBlock),
+		now:       time.Now,
+	}
+}
+
+// ahead returns how long the timestamp of the header is ahead of the local clock, zero if it isn't
+func (q *futureBlockQueue) ahead(header *types.Header) time.Duration {
+	ahead := time.Unix(int64(header.Timestamp), 0).Sub(q.now())
+	if ahead < 0 {
+		return 0
+	}
+
+	return ahead
+}
+
+// SetFutureBlockTolerance sets the time a finalized block can be ahead of the local clock.
+// Such a block is written when its time arrives, the blocks further in the future are rejected
+func (b *Blockchain) SetFutureBlockTolerance(tolerance time.Duration) {
+	b.futureBlocks.lock.Lock()
+	defer b.futureBlocks.lock.Unlock()
+
+	b.futureBlocks.tolerance = tolerance
+}
+
+// verifyBlockTime rejects the blocks too far ahead of the local clock
+func (b *Blockchain) verifyBlockTime(header *types.Header) error {
+	b.futureBlocks.lock.Lock()
+	defer b.futureBlocks.lock.Unlock()
+
+	if ahead := b.futureBlocks.ahead(header); ahead > b.futureBlocks.tolerance {
+		return fmt.Errorf("%w: %s ahead of the local clock", ErrFutureBlock, ahead)
+	}
+
+	return nil
+}
+
+// WriteFinalizedBlock writes a block verified by VerifyFinalizedBlock. A block whose timestamp is ahead
+// of the local clock is queued, and written when its time arrives. It waits until the block is written,
+// the block staying queued if the context is canceled first
+func (b *Blockchain) WriteFinalizedBlock(ctx context.Context, block *types.Block, source string) error {
+	queued, err := b.queueFutureBlock(block, source)
+	if err != nil {
+		return err
+	}
+
+	if queued == nil {
+		return b.WriteBlock(block, source)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-queued.done:
+		return queued.err
+	}
+}
+
+// queueFutureBlock queues the block if its timestamp is ahead of the local clock, it returns nil otherwise
+func (b *Blockchain) queueFutureBlock(block *types.Block, source string) (*futureBlock, error) {
+	q := b.futureBlocks
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	ahead := q.ahead(block.Header)
+	if ahead == 0 {
+		return nil, nil
+	}
+
+	if q.closed {
+		return nil, errFutureBlocksClosed
+	}
+
+	if queued, ok := q.blocks[block.Hash()]; ok {
+		return queued, nil
+	}
+
+	if ahead > q.tolerance {
+		return nil, fmt.Errorf("%w: %s ahead of the local clock", ErrFutureBlock, ahead)
+	}
+
+	if len(q.blocks) >= maxFutureBlocks {
+		return nil, ErrFutureBlocksFull
+	}
+
+	queued := &futureBlock{
+		block:  block,
+		source: source,
+		done:   make(chan struct{}),
+	}
+
+	q.blocks[block.Hash()] = queued
+	b.metrics.FutureBlocks.Set(float64(len(q.blocks)))
+
+	b.logger.Info("queued future block", "number", block.Number(), "hash", block.Hash(), "ahead", ahead)
+
+	b.scheduleFutureBlocks()
+
+	return queued, nil
+}
+
+// scheduleFutureBlocks arms the timer for the earliest queued block.
+// It must be called with the lock of the queue held
+func (b *Blockchain) scheduleFutureBlocks() {
+	q := b.futureBlocks
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	if q.closed || len(q.blocks) == 0 {
+		return
+	}
+
+	next := time.Duration(math.MaxInt64)
+
+	for _, queued := range q.blocks {
+		if ahead := q.ahead(queued.block.Header); ahead < next {
+			next = ahead
+		}
+	}
+
+	q.timer = time.AfterFunc(next, b.writeFutureBlocks)
+}
+
+// writeFutureBlocks writes the queued blocks whose time arrived, by number, and arms the timer for the next ones
+func (b *Blockchain) writeFutureBlocks() {
+	q := b.futureBlocks
+
+	q.lock.Lock()
+
+	due := make([]*futureBlock, 0, len(q.blocks))
+
+	for hash, queued := range q.blocks {
+		if q.ahead(queued.block.Header) == 0 {
+			due = append(due, queued)
+			delete(q.blocks, hash)
+		}
+	}
+
+	b.metrics.FutureBlocks.Set(float64(len(q.blocks)))
+	b.scheduleFutureBlocks()
+
+	q.lock.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].block.Number() < due[j].block.Number()
+	})
+
+	for _, queued := range due {
+		queued.err = b.WriteBlock(queued.block, queued.source)
+		if queued.err != nil {
+			b.logger.Error("failed to write future block", "number", queued.block.Number(), "err", queued.err)
+		}
+
+		close(queued.done)
+	}
+}
+
+// closeFutureBlocks drops the queued blocks, their writers get an error
+func (b *Blockchain) closeFutureBlocks() {
+	q := b.futureBlocks
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.closed = true
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	for hash, queued := range q.blocks {
+		queued.err = errFutureBlocksClosed
+		close(queued.done)
+
+		delete(q.blocks, hash)
+	}
+}
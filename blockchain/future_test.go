@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestFutureBlock returns the block following the genesis of the chain, with the given timestamp
+func newTestFutureBlock(t *testing.T, timestamp time.Time) (*Blockchain, *types.Block) {
+	t.Helper()
+
+	headers := NewTestHeaders(2)
+	b := newTestWrittenBlockchain(t, headers[:1], nil)
+
+	header := headers[1]
+	header.Timestamp = uint64(timestamp.Unix())
+	header.ComputeHash()
+
+	b.receiptsCache.Add(header.Hash, []*types.Receipt{})
+
+	return b, &types.Block{Header: header}
+}
+
+func TestWriteFinalizedBlock_Future(t *testing.T) {
+	t.Parallel()
+
+	b, block := newTestFutureBlock(t, time.Now().Add(2*time.Second))
+
+	assert.NoError(t, b.verifyBlockTime(block.Header))
+
+	// the block is written once its time arrives
+	assert.NoError(t, b.WriteFinalizedBlock(context.Background(), block, "test"))
+	assert.False(t, time.Now().Before(time.Unix(int64(block.Header.Timestamp), 0)))
+	assert.Equal(t, block.Hash(), b.Header().Hash)
+	assert.Empty(t, b.futureBlocks.blocks)
+}
+
+func TestWriteFinalizedBlock_TooFarAhead(t *testing.T) {
+	t.Parallel()
+
+	b, block := newTestFutureBlock(t, time.Now().Add(time.Minute))
+	b.SetFutureBlockTolerance(10 * time.Second)
+
+	assert.ErrorIs(t, b.verifyBlockTime(block.Header), ErrFutureBlock)
+	assert.ErrorIs(t, b.WriteFinalizedBlock(context.Background(), block, "test"), ErrFutureBlock)
+	assert.Equal(t, uint64(0), b.Header().Number)
+}
+
+func TestWriteFinalizedBlock_Canceled(t *testing.T) {
+	t.Parallel()
+
+	b, block := newTestFutureBlock(t, time.Now().Add(DefaultFutureBlockTolerance/2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the block stays queued
+	assert.ErrorIs(t, b.WriteFinalizedBlock(ctx, block, "test"), context.Canceled)
+	assert.Len(t, b.futureBlocks.blocks, 1)
+
+	queued := b.futureBlocks.blocks[block.Hash()]
+
+	assert.NoError(t, b.Close())
+
+	<-queued.done
+	assert.ErrorIs(t, queued.err, errFutureBlocksClosed)
+	assert.Empty(t, b.futureBlocks.blocks)
+}
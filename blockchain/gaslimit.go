@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// gasLimitElasticity is the ratio between the block gas target and the lowest gas limit of the elastic adjustment,
+// like the elasticity multiplier of EIP-1559 between the gas limit and the gas target of a block
+const gasLimitElasticity = 2
+
+// gasTargetOverride is the block gas target set at runtime by the operator,
+// overriding the one of the chain params once set
+type gasTargetOverride struct {
+	sync.RWMutex
+
+	set     bool
+	target  uint64
+	elastic bool
+}
+
+// SetBlockGasTarget sets the gas limit the next blocks move toward, 0 to keep the gas limit of the parent block.
+// With the elastic adjustment, the gas limit follows the usage of the blocks instead, moving toward the target
+// while they are more than half full, and toward half the target while they are less.
+// The target only drives the blocks built by the node, the other nodes verify the per-block bound only
+func (b *Blockchain) SetBlockGasTarget(target uint64, elastic bool) {
+	b.gasTarget.Lock()
+	defer b.gasTarget.Unlock()
+
+	b.gasTarget.set = true
+	b.gasTarget.target = target
+	b.gasTarget.elastic = elastic
+
+	b.logger.Info("block gas target set", "target", target, "elastic", elastic)
+}
+
+// BlockGasTarget returns the gas limit the next blocks move toward, and whether the adjustment is elastic
+func (b *Blockchain) BlockGasTarget() (uint64, bool) {
+	b.gasTarget.RLock()
+	defer b.gasTarget.RUnlock()
+
+	if b.gasTarget.set {
+		return b.gasTarget.target, b.gasTarget.elastic
+	}
+
+	params := b.Config()
+
+	return params.BlockGasTarget, params.BlockGasElastic
+}
+
+// CalculateGasLimit returns the gas limit of the next block after parent
+func (b *Blockchain) CalculateGasLimit(number uint64) (uint64, error) {
+	parent, ok := b.GetHeaderByNumber(number - 1)
+	if !ok {
+		return 0, fmt.Errorf("parent of block %d not found", number)
+	}
+
+	return b.calculateGasLimit(parent), nil
+}
+
+// calculateGasLimit calculates gas limit in reference to the block gas target
+func (b *Blockchain) calculateGasLimit(parent *types.Header) uint64 {
+	// The gas limit cannot move more than 1/1024 * parentGasLimit
+	// in either direction per block
+	blockGasTarget, elastic := b.BlockGasTarget()
+
+	// Check if the gas limit target has been set
+	if blockGasTarget == 0 {
+		// The gas limit target has not been set,
+		// so it should use the parent gas limit
+		return parent.GasLimit
+	}
+
+	if elastic {
+		blockGasTarget = elasticGasTarget(parent, blockGasTarget)
+	}
+
+	parentGasLimit := parent.GasLimit
+
+	// Check if the gas limit is already at the target
+	if parentGasLimit == blockGasTarget {
+		// The gas limit is already at the target, no need to move it
+		return blockGasTarget
+	}
+
+	delta := parentGasLimit * 1 / BlockGasTargetDivisor
+	if parentGasLimit < blockGasTarget {
+		// The gas limit is lower than the gas target, so it should
+		// increase towards the target
+		return common.Min(blockGasTarget, parentGasLimit+delta)
+	}
+
+	// The gas limit is higher than the gas target, so it should
+	// decrease towards the target
+	return common.Max(blockGasTarget, common.Max(parentGasLimit-delta, 0))
+}
+
+// elasticGasTarget returns the gas limit the block after parent moves toward with the elastic adjustment:
+// the target while the parent is more than half full, and a fraction of the target while it is less
+func elasticGasTarget(parent *types.Header, target uint64) uint64 {
+	floor := target / gasLimitElasticity
+
+	switch usage := parent.GasUsed * gasLimitElasticity; {
+	case usage > parent.GasLimit:
+		return target
+	case usage < parent.GasLimit:
+		return floor
+	default:
+		// exactly at the usage target, the gas limit only moves back within the bounds
+		return common.Min(target, common.Max(floor, parent.GasLimit))
+	}
+}
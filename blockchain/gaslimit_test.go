@@ -0,0 +1,113 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateGasLimit_Elastic(t *testing.T) {
+	t.Parallel()
+
+	const target = 20000000
+
+	tests := []struct {
+		name             string
+		parentGasLimit   uint64
+		parentGasUsed    uint64
+		expectedGasLimit uint64
+	}{
+		{
+			name:             "should increase toward the target when the parent is more than half full",
+			parentGasLimit:   15000000,
+			parentGasUsed:    10000000,
+			expectedGasLimit: 15000000 + 15000000/1024,
+		},
+		{
+			name:             "should decrease toward half the target when the parent is less than half full",
+			parentGasLimit:   15000000,
+			parentGasUsed:    1000000,
+			expectedGasLimit: 15000000 - 15000000/1024,
+		},
+		{
+			name:             "should not decrease below half the target",
+			parentGasLimit:   target / 2,
+			parentGasUsed:    0,
+			expectedGasLimit: target / 2,
+		},
+		{
+			name:             "should not increase above the target",
+			parentGasLimit:   target,
+			parentGasUsed:    target,
+			expectedGasLimit: target,
+		},
+		{
+			name:             "should not alter the gas limit when exactly half full",
+			parentGasLimit:   15000000,
+			parentGasUsed:    7500000,
+			expectedGasLimit: 15000000,
+		},
+		{
+			name:             "should decrease toward the target when exactly half full above it",
+			parentGasLimit:   target * 2,
+			parentGasUsed:    target,
+			expectedGasLimit: target*2 - target*2/1024,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := &Blockchain{
+				config: &chain.Chain{
+					Params: &chain.Params{
+						BlockGasTarget:  target,
+						BlockGasElastic: true,
+					},
+				},
+			}
+
+			nextGas := b.calculateGasLimit(&types.Header{
+				GasLimit: tt.parentGasLimit,
+				GasUsed:  tt.parentGasUsed,
+			})
+			assert.Equal(t, tt.expectedGasLimit, nextGas)
+		})
+	}
+}
+
+func TestSetBlockGasTarget(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewMockBlockchain(nil)
+	assert.NoError(t, err)
+
+	b.config.Params = &chain.Params{
+		BlockGasTarget: 25000000,
+	}
+
+	parent := &types.Header{GasLimit: 20000000}
+
+	// the target of the chain params is used until the target is set
+	target, elastic := b.BlockGasTarget()
+	assert.Equal(t, uint64(25000000), target)
+	assert.False(t, elastic)
+	assert.Equal(t, uint64(20000000+20000000/1024), b.calculateGasLimit(parent))
+
+	b.SetBlockGasTarget(15000000, false)
+
+	target, elastic = b.BlockGasTarget()
+	assert.Equal(t, uint64(15000000), target)
+	assert.False(t, elastic)
+	assert.Equal(t, uint64(20000000-20000000/1024), b.calculateGasLimit(parent))
+
+	// the parent gas limit is kept without target, even if the chain params set one
+	b.SetBlockGasTarget(0, false)
+
+	assert.Equal(t, parent.GasLimit, b.calculateGasLimit(parent))
+}
@@ -0,0 +1,78 @@
+package blockchain
+
+import (
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// BlockHook is notified of the blocks executed and written by the blockchain, so that the indexers
+// and the bridges embedded in the node consume the canonical updates without polling the JSON-RPC.
+// The hooks are called synchronously while the block is written, so they must return quickly
+// and must not write to the blockchain
+type BlockHook interface {
+	// BeforeBlock is called before the transactions of the block are executed. A block may be executed
+	// without being written, e.g. a proposal that isn't sealed, or re-executed to recover the state
+	BeforeBlock(block *types.Block)
+
+	// AfterBlock is called once the block is written
+	AfterBlock(written *WrittenBlock)
+}
+
+// WrittenBlock is a block written by the blockchain, with the result of its execution
+type WrittenBlock struct {
+	Block    *types.Block
+	Receipts []*types.Receipt
+
+	// StateDiff are the accounts modified by the block, with their modified storage slots.
+	// It is nil if the execution result of the block isn't cached anymore
+	StateDiff []*state.Object
+
+	// Event is the chain update caused by the block. The block is on a side chain
+	// for an EventFork, and the headers of OldChain are removed by an EventReorg
+	Event *Event
+}
+
+// RegisterBlockHook registers the hook notified of the executed and written blocks
+func (b *Blockchain) RegisterBlockHook(hook BlockHook) {
+	b.blockHooksLock.Lock()
+	defer b.blockHooksLock.Unlock()
+
+	b.blockHooks = append(b.blockHooks, hook)
+}
+
+// getBlockHooks returns the registered block hooks
+func (b *Blockchain) getBlockHooks() []BlockHook {
+	b.blockHooksLock.RLock()
+	defer b.blockHooksLock.RUnlock()
+
+	return b.blockHooks
+}
+
+// runBeforeBlockHooks notifies the hooks of the block about to be executed
+func (b *Blockchain) runBeforeBlockHooks(block *types.Block) {
+	for _, hook := range b.getBlockHooks() {
+		hook.BeforeBlock(block)
+	}
+}
+
+// runAfterBlockHooks notifies the hooks of the written block
+func (b *Blockchain) runAfterBlockHooks(block *types.Block, receipts []*types.Receipt, evnt *Event) {
+	hooks := b.getBlockHooks()
+	if len(hooks) == 0 {
+		return
+	}
+
+	written := &WrittenBlock{
+		Block:    block,
+		Receipts: receipts,
+		Event:    evnt,
+	}
+
+	if diff, ok := b.stateDiffsCache.Get(block.Hash()); ok {
+		written.StateDiff, _ = diff.([]*state.Object)
+	}
+
+	for _, hook := range hooks {
+		hook.AfterBlock(written)
+	}
+}
@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBlockHook struct {
+	before []*types.Block
+	after  []*WrittenBlock
+}
+
+func (m *mockBlockHook) BeforeBlock(block *types.Block) {
+	m.before = append(m.before, block)
+}
+
+func (m *mockBlockHook) AfterBlock(written *WrittenBlock) {
+	m.after = append(m.after, written)
+}
+
+func TestBlockchain_BlockHook_AfterBlock(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(5)
+	b := NewTestBlockchain(t, headers[:3])
+
+	hook := &mockBlockHook{}
+	b.RegisterBlockHook(hook)
+
+	// the execution result of the verification phase
+	receipts := []*types.Receipt{{CumulativeGasUsed: 1}}
+	diff := []*state.Object{{Address: types.StringToAddress("1"), Nonce: 1}}
+
+	b.receiptsCache.Add(headers[3].Hash, receipts)
+	b.stateDiffsCache.Add(headers[3].Hash, diff)
+
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: headers[3]}, "test"))
+
+	if assert.Len(t, hook.after, 1) {
+		written := hook.after[0]
+
+		assert.Equal(t, headers[3].Hash, written.Block.Hash())
+		assert.Equal(t, receipts, written.Receipts)
+		assert.Equal(t, diff, written.StateDiff)
+		assert.Equal(t, EventHead, written.Event.Type)
+	}
+
+	// a side chain block is written with a fork event
+	other := AppendNewTestheadersWithSeed(headers[:3], 1, 2)
+	b.receiptsCache.Add(other[3].Hash, []*types.Receipt{})
+
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: other[3]}, "test"))
+
+	if assert.Len(t, hook.after, 2) {
+		assert.Equal(t, EventFork, hook.after[1].Event.Type)
+		assert.Nil(t, hook.after[1].StateDiff)
+	}
+
+	// the hook isn't called again for a known block
+	assert.NoError(t, b.WriteBlock(&types.Block{Header: headers[3]}, "test"))
+	assert.Len(t, hook.after, 2)
+	assert.Empty(t, hook.before)
+}
+
+func TestBlockchain_BlockHook_BeforeBlock(t *testing.T) {
+	t.Parallel()
+
+	errExecution := errors.New("execution failed")
+
+	b, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+		StorageCallback: func(storage *storage.MockStorage) {
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return &types.Header{}, nil
+			})
+		},
+		ExecutorCallback: func(executor *mockExecutor) {
+			executor.HookProcessBlock(func(types.Hash, *types.Block, types.Address) (*state.Transition, error) {
+				return nil, errExecution
+			})
+		},
+	})
+	assert.NoError(t, err)
+
+	hook := &mockBlockHook{}
+	b.RegisterBlockHook(hook)
+
+	block := &types.Block{Header: &types.Header{Number: 1}}
+
+	// the hook is called before the execution, whatever its result
+	_, err = b.executeBlockTransactions(block)
+	assert.ErrorIs(t, err, errExecution)
+
+	assert.Equal(t, []*types.Block{block}, hook.before)
+	assert.Empty(t, hook.after)
+}
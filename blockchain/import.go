@@ -0,0 +1,159 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+)
+
+var (
+	ErrImportNotHead        = errors.New("the parent of the imported block isn't the head")
+	errNoHead               = errors.New("no head found")
+	errInvalidReceiptTxHash = errors.New("invalid receipt transaction hash")
+)
+
+// ReadCanonicalBlock reads the canonical block with the given number and its receipts from the storage.
+// The genesis has no stored body. The blocks whose body or receipts are pruned can't be read
+func ReadCanonicalBlock(db storage.Storage, number uint64) (*types.Block, []*types.Receipt, error) {
+	hash, ok := db.ReadCanonicalHash(number)
+	if !ok {
+		return nil, nil, fmt.Errorf("canonical hash not found for block %d", number)
+	}
+
+	header, err := db.ReadHeader(hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the header %d: %w", number, err)
+	}
+
+	block := &types.Block{Header: header}
+
+	if number == 0 {
+		return block, []*types.Receipt{}, nil
+	}
+
+	body, err := db.ReadBody(hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the body %d: %w", number, err)
+	}
+
+	block.Transactions = body.Transactions
+	block.Uncles = body.Uncles
+
+	receipts, err := db.ReadReceipts(hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the receipts %d: %w", number, err)
+	}
+
+	return block, receipts, nil
+}
+
+// ImportBlock writes the block and its receipts on top of the head of the storage of a stopped node,
+// without executing it. The block is checked against the head and its receipts, but its seal isn't
+// verified, so the blocks have to come from a trusted source. The state of the block isn't written,
+// the node re-executes the blocks missing their state on startup
+func ImportBlock(db storage.Storage, block *types.Block, receipts []*types.Receipt) error {
+	head, ok := db.ReadHeadHash()
+	if !ok {
+		return errNoHead
+	}
+
+	if block.ParentHash() != head {
+		return fmt.Errorf("%w: block %d", ErrImportNotHead, block.Number())
+	}
+
+	parent, err := db.ReadHeader(head)
+	if err != nil {
+		return err
+	}
+
+	if block.Number() != parent.Number+1 {
+		return ErrInvalidBlockSequence
+	}
+
+	if err := verifyImportedBlock(block, receipts); err != nil {
+		return err
+	}
+
+	parentTD, ok := db.ReadTotalDifficulty(head)
+	if !ok {
+		return fmt.Errorf("parent difficulty not found")
+	}
+
+	hash := block.Hash()
+
+	// the data of the block and the new head are written at once
+	batch := db.NewWriteBatch()
+
+	if err := batch.WriteBody(hash, block.Body()); err != nil {
+		return err
+	}
+
+	if err := batch.WriteReceipts(hash, receipts); err != nil {
+		return err
+	}
+
+	for i, txn := range block.Transactions {
+		if err := batch.WriteTxLookup(txn.Hash, &storage.TxLookupEntry{
+			BlockHash:   hash,
+			BlockNumber: block.Number(),
+			Index:       uint64(i),
+			Positioned:  true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	td := new(big.Int).Add(parentTD, new(big.Int).SetUint64(block.Header.Difficulty))
+
+	if err := batch.WriteCanonicalHeader(block.Header, td); err != nil {
+		return err
+	}
+
+	return batch.Write()
+}
+
+// verifyImportedBlock checks the roots of the block against its body and its receipts
+func verifyImportedBlock(block *types.Block, receipts []*types.Receipt) error {
+	if err := verifyBodyRoots(block); err != nil {
+		return err
+	}
+
+	return verifyReceiptsRoot(block, receipts)
+}
+
+// verifyBodyRoots checks the uncles and the transactions roots of the block against its body
+func verifyBodyRoots(block *types.Block) error {
+	if hash := buildroot.CalculateUncleRoot(block.Uncles); hash != block.Header.Sha3Uncles {
+		return ErrInvalidSha3Uncles
+	}
+
+	if hash := buildroot.CalculateTransactionsRoot(block.Transactions); hash != block.Header.TxRoot {
+		return ErrInvalidTxRoot
+	}
+
+	return nil
+}
+
+// verifyReceiptsRoot checks the receipts root of the block against its receipts,
+// and the receipts against the transactions of the block
+func verifyReceiptsRoot(block *types.Block, receipts []*types.Receipt) error {
+	if len(receipts) != len(block.Transactions) {
+		return ErrInvalidReceiptsSize
+	}
+
+	if hash := buildroot.CalculateReceiptsRoot(receipts); hash != block.Header.ReceiptsRoot {
+		return ErrInvalidReceiptsRoot
+	}
+
+	for i, receipt := range receipts {
+		if receipt.TxHash != block.Transactions[i].Hash {
+			return fmt.Errorf("%w: receipt %d of block %d", errInvalidReceiptTxHash, i, block.Number())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBlock(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(5)
+
+	src := newTestWrittenBlockchain(t, headers, nil)
+	dst := NewTestBlockchain(t, headers[:1])
+
+	// the genesis is read without a body
+	genesis, receipts, err := ReadCanonicalBlock(src.db, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, headers[0].Hash, genesis.Hash())
+	assert.Empty(t, receipts)
+
+	// a block not following the head isn't imported
+	block, receipts, err := ReadCanonicalBlock(src.db, 2)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, ImportBlock(dst.db, block, receipts), ErrImportNotHead)
+
+	for _, header := range headers[1:] {
+		block, receipts, err = ReadCanonicalBlock(src.db, header.Number)
+		assert.NoError(t, err)
+
+		assert.NoError(t, ImportBlock(dst.db, block, receipts))
+	}
+
+	head, ok := dst.db.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[4].Hash, head)
+
+	for _, header := range headers {
+		hash, ok := dst.db.ReadCanonicalHash(header.Number)
+		assert.True(t, ok)
+		assert.Equal(t, header.Hash, hash)
+
+		td, ok := dst.db.ReadTotalDifficulty(header.Hash)
+		assert.True(t, ok)
+
+		srcTD, _ := src.db.ReadTotalDifficulty(header.Hash)
+		assert.Equal(t, srcTD, td)
+	}
+
+	// the receipts have to match the block
+	next := AppendNewTestHeaders(headers, 1)[5]
+
+	err = ImportBlock(dst.db, &types.Block{Header: next}, []*types.Receipt{{}})
+	assert.ErrorIs(t, err, ErrInvalidReceiptsSize)
+}
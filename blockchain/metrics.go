@@ -0,0 +1,101 @@
+package blockchain
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the blockchain metrics
+type Metrics struct {
+	// Blocks which didn't end up in the canonical chain,
+	// either written as a fork or unwound by a reorg
+	OrphanedBlocks metrics.Counter
+	// Blocks received for an already written height, with another hash than the canonical block
+	StaleBlocks metrics.Counter
+	// Number of canonical blocks unwound by a reorg
+	ReorgDepth metrics.Histogram
+
+	// Time between the timestamp of the head block and its local write in seconds
+	BlockPropagationDelay metrics.Gauge
+	// Number of finalized blocks ahead of the local clock, waiting for their time to be written
+	FutureBlocks metrics.Gauge
+
+	// Executions of each opcode by the written blocks, labeled by opcode
+	OpcodeExecutions metrics.Counter
+	// Gas used by each opcode in the written blocks, labeled by opcode
+	OpcodeGas metrics.Counter
+}
+
+// GetPrometheusMetrics return the blockchain metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	opcodeLabels := append(labels, "opcode")
+
+	return &Metrics{
+		OrphanedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "orphaned_blocks",
+			Help:      "Number of blocks which didn't end up in the canonical chain.",
+		}, labels).With(labelsWithValues...),
+		StaleBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "stale_blocks",
+			Help:      "Number of blocks received for an already written height.",
+		}, labels).With(labelsWithValues...),
+		ReorgDepth: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "reorg_depth",
+			Help:      "Number of canonical blocks unwound by a reorg.",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024},
+		}, labels).With(labelsWithValues...),
+
+		BlockPropagationDelay: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "block_propagation_delay",
+			Help:      "Time between the timestamp of the head block and its local write in seconds.",
+		}, labels).With(labelsWithValues...),
+		FutureBlocks: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "future_blocks",
+			Help:      "Number of finalized blocks ahead of the local clock, waiting for their time to be written.",
+		}, labels).With(labelsWithValues...),
+
+		OpcodeExecutions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "opcode_executions",
+			Help:      "Number of executions of each opcode by the written blocks.",
+		}, opcodeLabels).With(labelsWithValues...),
+		OpcodeGas: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "opcode_gas",
+			Help:      "Gas used by each opcode in the written blocks.",
+		}, opcodeLabels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational blockchain metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		OrphanedBlocks:        discard.NewCounter(),
+		StaleBlocks:           discard.NewCounter(),
+		ReorgDepth:            discard.NewHistogram(),
+		BlockPropagationDelay: discard.NewGauge(),
+		FutureBlocks:          discard.NewGauge(),
+		OpcodeExecutions:      discard.NewCounter(),
+		OpcodeGas:             discard.NewCounter(),
+	}
+}
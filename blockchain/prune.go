@@ -0,0 +1,89 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var errNoRetainedState = errors.New("no state found for the recent blocks")
+
+// PruneBlocks deletes the bodies and the receipts of the canonical blocks older than the given
+// number of recent blocks, from the storage of a stopped node. The blocks are pruned down to the
+// first one already pruned, and their number is returned. The headers and the transaction lookups
+// are kept, the genesis is never pruned
+func PruneBlocks(db storage.Storage, retained uint64) (uint64, error) {
+	head, ok := db.ReadHeadNumber()
+	if !ok || retained == 0 || head <= retained {
+		return 0, nil
+	}
+
+	pruned := uint64(0)
+
+	for number := head - retained; number > 0; number-- {
+		hash, ok := db.ReadCanonicalHash(number)
+		if !ok {
+			return pruned, fmt.Errorf("canonical hash not found for block %d", number)
+		}
+
+		if _, err := db.ReadBody(hash); errors.Is(err, storage.ErrNotFound) {
+			break
+		}
+
+		if err := deleteBlockData(db, hash); err != nil {
+			return pruned, err
+		}
+
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// RetainedStateRoots returns the state roots of the given number of recent canonical blocks whose
+// state is available. The blocks whose state isn't written to disk yet are skipped
+func RetainedStateRoots(db storage.Storage, retained uint64, hasState func(types.Hash) bool) ([]types.Hash, error) {
+	head, ok := db.ReadHeadNumber()
+	if !ok {
+		return nil, errNoRetainedState
+	}
+
+	roots := []types.Hash{}
+
+	for number := head; uint64(len(roots)) < retained; number-- {
+		hash, ok := db.ReadCanonicalHash(number)
+		if !ok {
+			return nil, fmt.Errorf("canonical hash not found for block %d", number)
+		}
+
+		header, err := db.ReadHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasState(header.StateRoot) {
+			roots = append(roots, header.StateRoot)
+		}
+
+		if number == 0 {
+			break
+		}
+	}
+
+	if len(roots) == 0 {
+		return nil, errNoRetainedState
+	}
+
+	return roots, nil
+}
+
+// deleteBlockData deletes the body and the receipts of the block with the given hash
+func deleteBlockData(db storage.Storage, hash types.Hash) error {
+	if err := db.DeleteBody(hash); err != nil {
+		return err
+	}
+
+	return db.DeleteReceipts(hash)
+}
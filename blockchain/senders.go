@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// sendersCacheSize is the number of recovered transaction senders cached by transaction hash
+	sendersCacheSize = 4096
+
+	// minSendersPerWorker is the minimum number of senders recovered by a worker,
+	// so that the workers of a small block don't cost more than the recovery itself
+	minSendersPerWorker = 4
+)
+
+// RecoverSenders recovers the senders of the block transactions from their signatures in a pool of workers,
+// so that the block execution doesn't recover them one by one. The recovered senders are cached by transaction
+// hash, for the blocks verified more than once. It only depends on the block, so the senders of several blocks
+// can be recovered concurrently
+func (b *Blockchain) RecoverSenders(block *types.Block) error {
+	pending := make([]*types.Transaction, 0, len(block.Transactions))
+
+	for _, tx := range block.Transactions {
+		if tx.From != types.ZeroAddress {
+			continue
+		}
+
+		if from, ok := b.cachedSender(tx); ok {
+			tx.From = from
+
+			continue
+		}
+
+		pending = append(pending, tx)
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var (
+		signer = crypto.NewSigner(b.config.Params.Forks.At(block.Number()), uint64(b.config.Params.ChainID))
+		errs   = make([]error, len(pending))
+		jobs   = make(chan int, len(pending))
+		wg     sync.WaitGroup
+	)
+
+	for i := range pending {
+		jobs <- i
+	}
+
+	close(jobs)
+
+	workers := runtime.NumCPU()
+	if max := (len(pending) + minSendersPerWorker - 1) / minSendersPerWorker; workers > max {
+		workers = max
+	}
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobs {
+				tx := pending[idx]
+
+				from, err := signer.Sender(tx)
+				if err != nil {
+					errs[idx] = fmt.Errorf("%w: transaction %s, %v", ErrInvalidTxSignature, tx.Hash, err)
+
+					continue
+				}
+
+				tx.From = from
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// the error of the first invalid transaction is returned, whatever the worker recovering it
+	for idx, err := range errs {
+		if err != nil {
+			return err
+		}
+
+		b.cacheSender(pending[idx])
+	}
+
+	return nil
+}
+
+// cachedSender returns the sender of the transaction recovered before, if it is still cached
+func (b *Blockchain) cachedSender(tx *types.Transaction) (types.Address, bool) {
+	if b.sendersCache == nil || tx.Hash == types.ZeroHash {
+		return types.ZeroAddress, false
+	}
+
+	from, ok := b.sendersCache.Get(tx.Hash)
+	if !ok {
+		return types.ZeroAddress, false
+	}
+
+	addr, ok := from.(types.Address)
+
+	return addr, ok
+}
+
+// cacheSender caches the recovered sender of the transaction by its hash
+func (b *Blockchain) cacheSender(tx *types.Transaction) {
+	if b.sendersCache == nil || tx.Hash == types.ZeroHash {
+		return
+	}
+
+	b.sendersCache.Add(tx.Hash, tx.From)
+}
@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchain_RecoverSenders_Parallel(t *testing.T) {
+	t.Parallel()
+
+	b := NewTestBlockchain(t, NewTestHeaders(2))
+
+	var (
+		signer  = crypto.NewEIP155Signer(0)
+		txs     = make([]*types.Transaction, 4*minSendersPerWorker)
+		senders = make([]types.Address, len(txs))
+	)
+
+	for i := range txs {
+		key, err := crypto.GenerateKey()
+		assert.NoError(t, err)
+
+		tx, err := signer.SignTx(&types.Transaction{
+			Nonce:    uint64(i),
+			Value:    big.NewInt(1),
+			GasPrice: big.NewInt(1),
+			Gas:      21000,
+		}, key)
+		assert.NoError(t, err)
+
+		txs[i] = tx.ComputeHash()
+		senders[i] = crypto.PubKeyToAddress(&key.PublicKey)
+	}
+
+	block := &types.Block{
+		Header:       &types.Header{Number: 1},
+		Transactions: txs,
+	}
+
+	assert.NoError(t, b.RecoverSenders(block))
+
+	for i, tx := range txs {
+		assert.Equal(t, senders[i], tx.From)
+		assert.True(t, b.sendersCache.Contains(tx.Hash))
+	}
+
+	// the senders of the same transactions are taken from the cache
+	for _, tx := range txs {
+		tx.From = types.ZeroAddress
+	}
+
+	b.sendersCache.Add(txs[0].Hash, types.StringToAddress("cached"))
+
+	assert.NoError(t, b.RecoverSenders(block))
+	assert.Equal(t, types.StringToAddress("cached"), txs[0].From)
+
+	for i, tx := range txs[1:] {
+		assert.Equal(t, senders[i+1], tx.From)
+	}
+}
+
+func TestBlockchain_RecoverSenders_InvalidSignature(t *testing.T) {
+	t.Parallel()
+
+	b := NewTestBlockchain(t, NewTestHeaders(2))
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	txs := make([]*types.Transaction, 4*minSendersPerWorker)
+
+	for i := range txs {
+		// the transactions signed for another chain have no valid sender
+		chainID := uint64(0)
+		if i >= len(txs)/2 {
+			chainID = 100
+		}
+
+		tx, err := crypto.NewEIP155Signer(chainID).SignTx(&types.Transaction{
+			Nonce:    uint64(i),
+			Value:    big.NewInt(1),
+			GasPrice: big.NewInt(1),
+			Gas:      21000,
+		}, key)
+		assert.NoError(t, err)
+
+		txs[i] = tx.ComputeHash()
+	}
+
+	block := &types.Block{
+		Header:       &types.Header{Number: 1},
+		Transactions: txs,
+	}
+
+	err = b.RecoverSenders(block)
+	assert.ErrorIs(t, err, ErrInvalidTxSignature)
+	assert.Contains(t, err.Error(), txs[len(txs)/2].Hash.String())
+
+	// the senders recovered before the invalid transaction are cached
+	assert.True(t, b.sendersCache.Contains(txs[0].Hash))
+	assert.False(t, b.sendersCache.Contains(txs[len(txs)-1].Hash))
+}
@@ -0,0 +1,137 @@
+package storage
+
+// WriteBatch is a storage whose writes are buffered, and applied at once by Write.
+// Its reads see the buffered writes
+type WriteBatch interface {
+	Storage
+
+	// Write applies the buffered writes atomically if the database supports it, and resets the batch
+	Write() error
+}
+
+// pendingWrite is a buffered write of a batch, a set or a delete
+type pendingWrite struct {
+	value   []byte
+	deleted bool
+}
+
+// batchKV buffers the writes to the kv storage. The reads see the buffered writes
+type batchKV struct {
+	db      KV
+	pending map[string]pendingWrite
+}
+
+func newBatchKV(db KV) *batchKV {
+	return &batchKV{
+		db:      db,
+		pending: make(map[string]pendingWrite),
+	}
+}
+
+// Set buffers the key-value pair
+func (b *batchKV) Set(p []byte, v []byte) error {
+	b.pending[string(p)] = pendingWrite{value: append([]byte{}, v...)}
+
+	return nil
+}
+
+// Get retrieves the buffered value of the key, or the stored one if there is none
+func (b *batchKV) Get(p []byte) ([]byte, bool, error) {
+	if write, ok := b.pending[string(p)]; ok {
+		if write.deleted {
+			return nil, false, nil
+		}
+
+		return write.value, true, nil
+	}
+
+	return b.db.Get(p)
+}
+
+// Delete buffers the removal of the key
+func (b *batchKV) Delete(p []byte) error {
+	b.pending[string(p)] = pendingWrite{deleted: true}
+
+	return nil
+}
+
+// Close doesn't close the underlying storage, which the batch doesn't own
+func (b *batchKV) Close() error {
+	return nil
+}
+
+// write applies the buffered writes in a single database batch. The kv storages which
+// aren't databases, the in memory one, get the writes one by one
+func (b *batchKV) write() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	if db, ok := b.db.(Database); ok {
+		batch := db.NewBatch()
+
+		for key, write := range b.pending {
+			if write.deleted {
+				batch.Delete([]byte(key))
+			} else {
+				batch.Set([]byte(key), write.value)
+			}
+		}
+
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	} else {
+		for key, write := range b.pending {
+			var err error
+
+			if write.deleted {
+				err = b.db.Delete([]byte(key))
+			} else {
+				err = b.db.Set([]byte(key), write.value)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	b.pending = make(map[string]pendingWrite)
+
+	return nil
+}
+
+// keyValueBatch is a key-value storage writing to a batch
+type keyValueBatch struct {
+	*KeyValueStorage
+
+	kv *batchKV
+}
+
+// NewWriteBatch returns a storage buffering its writes, so that the writes of a block
+// are applied at once and a crash never leaves it half-written
+func (s *KeyValueStorage) NewWriteBatch() WriteBatch {
+	kv := newBatchKV(s.db)
+
+	return &keyValueBatch{
+		KeyValueStorage: &KeyValueStorage{
+			logger:          s.logger,
+			db:              kv,
+			compression:     s.compression,
+			compactReceipts: s.compactReceipts,
+			freezer:         s.freezer,
+		},
+		kv: kv,
+	}
+}
+
+// Write applies the buffered writes
+func (b *keyValueBatch) Write() error {
+	return b.kv.write()
+}
+
+// Close doesn't close the storage nor the freezer, which the batch shares with its storage
+func (b *keyValueBatch) Close() error {
+	return nil
+}
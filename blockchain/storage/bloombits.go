@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// BloomSectionSize is the number of blocks of a section of the bloom bits index.
+// For each bit of the logs bloom, a section keeps a bitset with the blocks setting it
+const BloomSectionSize = 4096
+
+// bloomBitsetLength is the length in bytes of the bitset of a bloom bit in a section
+const bloomBitsetLength = BloomSectionSize / 8
+
+// IndexBloomSections indexes the logs blooms of the canonical blocks by sections, up to the
+// given number included, and at most limit sections at once. The bitsets of the bits not set
+// by any block of a section aren't written. It returns the number of sections indexed
+func IndexBloomSections(s Storage, number uint64, limit uint64) (uint64, error) {
+	indexed := uint64(0)
+
+	for sections := s.ReadBloomSections(); indexed < limit; sections++ {
+		// the section has to be complete
+		if (sections+1)*BloomSectionSize-1 > number {
+			break
+		}
+
+		if err := indexBloomSection(s, sections); err != nil {
+			return indexed, err
+		}
+
+		if err := s.WriteBloomSections(sections + 1); err != nil {
+			return indexed, err
+		}
+
+		indexed++
+	}
+
+	return indexed, nil
+}
+
+// indexBloomSection writes the bloom bits of the blocks of the section
+func indexBloomSection(s Storage, section uint64) error {
+	bitsets := make([][]byte, types.BloomBitLength)
+
+	for i := uint64(0); i < BloomSectionSize; i++ {
+		number := section*BloomSectionSize + i
+
+		hash, ok := s.ReadCanonicalHash(number)
+		if !ok {
+			return fmt.Errorf("canonical hash %d not found", number)
+		}
+
+		header, err := s.ReadHeader(hash)
+		if err != nil {
+			return fmt.Errorf("failed to read the header %d: %w", number, err)
+		}
+
+		for bit := uint(0); bit < types.BloomBitLength; bit++ {
+			if !header.LogsBloom.HasBit(bit) {
+				continue
+			}
+
+			if bitsets[bit] == nil {
+				bitsets[bit] = make([]byte, bloomBitsetLength)
+			}
+
+			bitsets[bit][i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	for bit, bitset := range bitsets {
+		if bitset == nil {
+			// the bitset left by a truncated section is cleared
+			if _, ok := s.ReadBloomBits(uint(bit), section); !ok {
+				continue
+			}
+
+			bitset = make([]byte, bloomBitsetLength)
+		}
+
+		if err := s.WriteBloomBits(uint(bit), section, bitset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TruncateBloomSections drops the sections of the bloom bits index including
+// the given block number or following it, e.g. once the block is discarded
+func TruncateBloomSections(s Storage, number uint64) error {
+	if sections := number / BloomSectionSize; sections < s.ReadBloomSections() {
+		// the bitsets left are overwritten once the sections are indexed again
+		return s.WriteBloomSections(sections)
+	}
+
+	return nil
+}
+
+// MatchBloomBits returns the numbers of the blocks between from and to included, and covered by
+// the bloom bits index, whose logs bloom may match all the groups. A group matches if any of its
+// values is in the bloom, an empty group matches any bloom
+func MatchBloomBits(s Storage, from, to uint64, groups [][][]byte) []uint64 {
+	if indexed := s.ReadBloomSections() * BloomSectionSize; to >= indexed {
+		if indexed == 0 {
+			return nil
+		}
+
+		to = indexed - 1
+	}
+
+	numbers := make([]uint64, 0)
+
+	for section := from / BloomSectionSize; from <= to && section <= to/BloomSectionSize; section++ {
+		matches := matchBloomSection(s, section, groups)
+
+		for i := uint64(0); i < BloomSectionSize; i++ {
+			number := section*BloomSectionSize + i
+
+			if number < from || number > to || matches[i/8]&(1<<(7-i%8)) == 0 {
+				continue
+			}
+
+			numbers = append(numbers, number)
+		}
+	}
+
+	return numbers
+}
+
+// matchBloomSection returns the bitset of the blocks of the section whose logs bloom matches the groups
+func matchBloomSection(s Storage, section uint64, groups [][][]byte) []byte {
+	bitsets := make(map[uint][]byte)
+
+	readBitset := func(bit uint) []byte {
+		if bitset, ok := bitsets[bit]; ok {
+			return bitset
+		}
+
+		bitset, ok := s.ReadBloomBits(bit, section)
+		if !ok {
+			// no block of the section sets the bit
+			bitset = make([]byte, bloomBitsetLength)
+		}
+
+		bitsets[bit] = bitset
+
+		return bitset
+	}
+
+	matches := filledBitset()
+
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		groupMatches := make([]byte, bloomBitsetLength)
+
+		for _, value := range group {
+			valueMatches := filledBitset()
+
+			for _, bit := range types.BloomBits(value) {
+				bitset := readBitset(bit)
+
+				for i := range valueMatches {
+					valueMatches[i] &= bitset[i]
+				}
+			}
+
+			for i := range groupMatches {
+				groupMatches[i] |= valueMatches[i]
+			}
+		}
+
+		for i := range matches {
+			matches[i] &= groupMatches[i]
+		}
+	}
+
+	return matches
+}
+
+// filledBitset returns a bitset with all the blocks of a section
+func filledBitset() []byte {
+	bitset := make([]byte, bloomBitsetLength)
+
+	for i := range bitset {
+		bitset[i] = 0xff
+	}
+
+	return bitset
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression of the bodies and the receipts at rest.
+// A compressed entry starts with its compression as format byte. The RLP lists
+// start with a byte of at least 0xc0, so the entries written uncompressed, before
+// the compression was introduced, are told apart and still read
+type Compression byte
+
+const (
+	CompressionNone Compression = iota
+	CompressionSnappy
+	CompressionZstd
+)
+
+// rlpListPrefix is the lowest first byte of an encoded RLP list
+const rlpListPrefix = 0xc0
+
+// maxDecompressedSize is the maximum size of a decompressed entry,
+// it prevents a corrupted entry from exhausting the memory
+const maxDecompressedSize = 256 * 1024 * 1024
+
+var compressionNames = map[Compression]string{
+	CompressionNone:   "none",
+	CompressionSnappy: "snappy",
+	CompressionZstd:   "zstd",
+}
+
+var (
+	// the zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+)
+
+var (
+	ErrUnknownCompression = errors.New("unknown compression")
+	errEntryTooLarge      = fmt.Errorf("decompressed entry exceeds %d bytes", maxDecompressedSize)
+)
+
+func (c Compression) String() string {
+	if name, ok := compressionNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Compression(%d)", byte(c))
+}
+
+// ParseCompression returns the compression of the given name, one of none, snappy and zstd
+func ParseCompression(name string) (Compression, error) {
+	for compression, compressionName := range compressionNames {
+		if compressionName == name {
+			return compression, nil
+		}
+	}
+
+	return CompressionNone, fmt.Errorf("%w: %s", ErrUnknownCompression, name)
+}
+
+// compress compresses the encoded entry, prefixed with the format byte.
+// The uncompressed entries are written as is
+func compress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionSnappy:
+		return append([]byte{byte(compression)}, snappy.Encode(nil, data)...), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(data, []byte{byte(compression)}), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, compression)
+	}
+}
+
+// decompress returns the encoded entry, whatever the compression it was written with
+func decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] >= rlpListPrefix {
+		return data, nil
+	}
+
+	switch compression := Compression(data[0]); compression {
+	case CompressionSnappy:
+		size, err := snappy.DecodedLen(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		if size > maxDecompressedSize {
+			return nil, errEntryTooLarge
+		}
+
+		return snappy.Decode(nil, data[1:])
+	case CompressionZstd:
+		return zstdDecoder.DecodeAll(data[1:], nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, compression)
+	}
+}
+
+// Recompress rewrites a stored body or receipts entry with the given compression.
+// It returns false if the entry is already written with it
+func Recompress(data []byte, compression Compression) ([]byte, bool, error) {
+	if entryCompression(data) == compression {
+		return data, false, nil
+	}
+
+	decompressed, err := decompress(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	compressed, err := compress(compression, decompressed)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return compressed, true, nil
+}
+
+// entryCompression returns the compression a stored entry is written with
+func entryCompression(data []byte) Compression {
+	if len(data) == 0 || data[0] >= rlpListPrefix {
+		return CompressionNone
+	}
+
+	return Compression(data[0])
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompression(t *testing.T) {
+	t.Parallel()
+
+	for _, compression := range []Compression{CompressionNone, CompressionSnappy, CompressionZstd} {
+		parsed, err := ParseCompression(compression.String())
+		assert.NoError(t, err)
+		assert.Equal(t, compression, parsed)
+	}
+
+	_, err := ParseCompression("gzip")
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestRecompress(t *testing.T) {
+	t.Parallel()
+
+	// an RLP list, as the entries written before the compression
+	legacy := append([]byte{0xf9, 0x04, 0x00}, bytes.Repeat([]byte{0x1}, 1024)...)
+
+	decompressed, err := decompress(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, decompressed)
+
+	for _, compression := range []Compression{CompressionSnappy, CompressionZstd} {
+		compressed, changed, err := Recompress(legacy, compression)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, byte(compression), compressed[0])
+		assert.Less(t, len(compressed), len(legacy))
+
+		// already compressed with it
+		_, changed, err = Recompress(compressed, compression)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		decompressed, changed, err := Recompress(compressed, CompressionNone)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, legacy, decompressed)
+	}
+
+	_, err = decompress([]byte{0x7f, 0x1})
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Engine is the database engine of the blockchain storage
+type Engine byte
+
+const (
+	EngineLevelDB Engine = iota
+	EnginePebble
+)
+
+var engineNames = map[Engine]string{
+	EngineLevelDB: "leveldb",
+	EnginePebble:  "pebble",
+}
+
+var ErrUnknownEngine = errors.New("unknown database engine")
+
+func (e Engine) String() string {
+	if name, ok := engineNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Engine(%d)", byte(e))
+}
+
+// ParseEngine returns the database engine of the given name, leveldb or pebble
+func ParseEngine(name string) (Engine, error) {
+	for engine, engineName := range engineNames {
+		if engineName == name {
+			return engine, nil
+		}
+	}
+
+	return EngineLevelDB, fmt.Errorf("%w: %s", ErrUnknownEngine, name)
+}
+
+// DetectEngine returns the engine of the database at the given path, and false if there is none.
+// Both engines keep a CURRENT file, only pebble writes OPTIONS files
+func DetectEngine(path string) (Engine, bool, error) {
+	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
+		if os.IsNotExist(err) {
+			return EngineLevelDB, false, nil
+		}
+
+		return EngineLevelDB, false, err
+	}
+
+	options, err := filepath.Glob(filepath.Join(path, "OPTIONS-*"))
+	if err != nil {
+		return EngineLevelDB, false, err
+	}
+
+	if len(options) > 0 {
+		return EnginePebble, true, nil
+	}
+
+	return EngineLevelDB, true, nil
+}
+
+// Database is a key-value database engine backing the blockchain storage
+type Database interface {
+	KV
+
+	// Iterate calls fn with the entries whose key starts with the prefix, in key order.
+	// The key and the value are only valid during the call
+	Iterate(prefix []byte, fn func(key, value []byte) error) error
+	// NewBatch creates a batch of writes applied at once
+	NewBatch() Batch
+	// Compact compacts the whole database, reclaiming the space of the deleted entries
+	Compact() error
+}
+
+// Batch is a batch of database writes
+type Batch interface {
+	// Set adds the key-value pair to the batch, the slices are copied
+	Set(k, v []byte)
+	// Delete adds the removal of the key to the batch, the slice is copied
+	Delete(k []byte)
+	// Len returns the number of writes in the batch
+	Len() int
+	// Write applies the writes of the batch to the database, and resets it
+	Write() error
+}
+
+// batchSize is the number of entries written at once by RecompressDatabase and CopyDatabase
+const batchSize = 1000
+
+// RecompressDatabase rewrites the bodies and the receipts of the database with the given compression,
+// and returns the number of entries rewritten
+func RecompressDatabase(db Database, compression Compression) (uint64, error) {
+	rewritten := uint64(0)
+	batch := db.NewBatch()
+
+	for _, prefix := range [][]byte{BODY, RECEIPTS} {
+		prefix := prefix
+
+		err := db.Iterate(prefix, func(key, value []byte) error {
+			// the entries keyed by a block hash
+			if len(key) != len(prefix)+types.HashLength {
+				return nil
+			}
+
+			data, changed, err := Recompress(value, compression)
+			if err != nil {
+				return fmt.Errorf("failed to recompress the entry %x: %w", key, err)
+			}
+
+			if !changed {
+				return nil
+			}
+
+			batch.Set(key, data)
+			rewritten++
+
+			if batch.Len() >= batchSize {
+				return batch.Write()
+			}
+
+			return nil
+		})
+		if err != nil {
+			return rewritten, err
+		}
+	}
+
+	return rewritten, batch.Write()
+}
+
+// CopyDatabase copies all the entries of the source database to the destination,
+// and returns the number of entries copied
+func CopyDatabase(src, dst Database) (uint64, error) {
+	copied := uint64(0)
+	batch := dst.NewBatch()
+
+	err := src.Iterate(nil, func(key, value []byte) error {
+		batch.Set(key, value)
+		copied++
+
+		if batch.Len() >= batchSize {
+			return batch.Write()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return copied, err
+	}
+
+	return copied, batch.Write()
+}
@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Freezer tables, one per kind of block data
+const (
+	freezerHeaders  = "headers"
+	freezerBodies   = "bodies"
+	freezerReceipts = "receipts"
+)
+
+// freezerIndexEntrySize is the size of an index entry, the end offset of the item in the data file
+const freezerIndexEntrySize = 8
+
+var freezerTables = []string{freezerHeaders, freezerBodies, freezerReceipts}
+
+var (
+	ErrFreezerOutOfOrder = errors.New("block not appended in order to the freezer")
+	errFreezerClosed     = errors.New("freezer closed")
+)
+
+// Freezer is an append-only flat-file store for the finalized canonical blocks.
+// The headers, the bodies and the receipts of the blocks are stored by number
+// in a table each, starting with the genesis. A table is made of a data file with
+// the items one after another, and of an index file with the end offset of each item.
+// The entries are stored as written to the key-value storage, an empty entry being missing
+type Freezer struct {
+	lock sync.RWMutex
+
+	tables map[string]*freezerTable
+	frozen uint64
+	closed bool
+}
+
+// freezerTable is a table of the freezer
+type freezerTable struct {
+	index *os.File
+	data  *os.File
+
+	// size is the size of the data file
+	size uint64
+}
+
+// NewFreezer opens the freezer in the given directory, creating it if needed.
+// The items of a table appended after the last ones of the other tables,
+// by an interrupted append, are dropped
+func NewFreezer(path string) (*Freezer, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	f := &Freezer{
+		tables: make(map[string]*freezerTable, len(freezerTables)),
+	}
+
+	for i, name := range freezerTables {
+		table, items, err := openFreezerTable(path, name)
+		if err != nil {
+			_ = f.closeTables()
+
+			return nil, fmt.Errorf("failed to open the freezer table %s: %w", name, err)
+		}
+
+		f.tables[name] = table
+
+		if i == 0 || items < f.frozen {
+			f.frozen = items
+		}
+	}
+
+	for name, table := range f.tables {
+		if err := table.truncate(f.frozen); err != nil {
+			_ = f.closeTables()
+
+			return nil, fmt.Errorf("failed to repair the freezer table %s: %w", name, err)
+		}
+	}
+
+	return f, nil
+}
+
+// Frozen returns the number of blocks in the freezer, the number of the next one to append
+func (f *Freezer) Frozen() uint64 {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.frozen
+}
+
+// Append appends the header, the body and the receipts entries of the next block to the freezer
+func (f *Freezer) Append(number uint64, header, body, receipts []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return errFreezerClosed
+	}
+
+	if number != f.frozen {
+		return fmt.Errorf("%w: expected block %d, got %d", ErrFreezerOutOfOrder, f.frozen, number)
+	}
+
+	items := map[string][]byte{
+		freezerHeaders:  header,
+		freezerBodies:   body,
+		freezerReceipts: receipts,
+	}
+
+	for _, name := range freezerTables {
+		if err := f.tables[name].append(number, items[name]); err != nil {
+			// drop the items of the block already appended to the other tables
+			for _, table := range f.tables {
+				_ = table.truncate(f.frozen)
+			}
+
+			return err
+		}
+	}
+
+	f.frozen++
+
+	return nil
+}
+
+// retrieve returns the entry of the block with the given number from the table,
+// or ErrNotFound if the block isn't frozen or its entry is missing
+func (f *Freezer) retrieve(name string, number uint64) ([]byte, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.closed {
+		return nil, errFreezerClosed
+	}
+
+	if number >= f.frozen {
+		return nil, ErrNotFound
+	}
+
+	data, err := f.tables[name].retrieve(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return data, nil
+}
+
+// Sync flushes the freezer tables to disk
+func (f *Freezer) Sync() error {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if f.closed {
+		return errFreezerClosed
+	}
+
+	for _, table := range f.tables {
+		if err := table.data.Sync(); err != nil {
+			return err
+		}
+
+		if err := table.index.Sync(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the freezer tables
+func (f *Freezer) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return nil
+	}
+
+	f.closed = true
+
+	return f.closeTables()
+}
+
+func (f *Freezer) closeTables() error {
+	var firstErr error
+
+	for _, table := range f.tables {
+		for _, file := range []*os.File{table.data, table.index} {
+			if err := file.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// openFreezerTable opens the table with the given name, and returns its number of items.
+// A partially written index entry, or an item whose data isn't fully written, is dropped
+func openFreezerTable(path, name string) (*freezerTable, uint64, error) {
+	index, err := os.OpenFile(filepath.Join(path, name+".idx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := os.OpenFile(filepath.Join(path, name+".dat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		_ = index.Close()
+
+		return nil, 0, err
+	}
+
+	table := &freezerTable{index: index, data: data}
+
+	items, err := table.items()
+	if err != nil {
+		_ = index.Close()
+		_ = data.Close()
+
+		return nil, 0, err
+	}
+
+	return table, items, nil
+}
+
+// items returns the number of items fully written to the table
+func (t *freezerTable) items() (uint64, error) {
+	indexInfo, err := t.index.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	dataInfo, err := t.data.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	items := uint64(indexInfo.Size()) / freezerIndexEntrySize
+
+	for ; items > 0; items-- {
+		end, err := t.offset(items)
+		if err != nil {
+			return 0, err
+		}
+
+		if end <= uint64(dataInfo.Size()) {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// offset returns the end offset of the items up to the given number of items
+func (t *freezerTable) offset(items uint64) (uint64, error) {
+	if items == 0 {
+		return 0, nil
+	}
+
+	buf := make([]byte, freezerIndexEntrySize)
+	if _, err := t.index.ReadAt(buf, int64((items-1)*freezerIndexEntrySize)); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// truncate drops the items of the table after the given number of items
+func (t *freezerTable) truncate(items uint64) error {
+	end, err := t.offset(items)
+	if err != nil {
+		return err
+	}
+
+	if err := t.index.Truncate(int64(items * freezerIndexEntrySize)); err != nil {
+		return err
+	}
+
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+
+	t.size = end
+
+	return nil
+}
+
+// append writes the item with the given number, the data first so that
+// an interrupted append leaves no index entry pointing past the data
+func (t *freezerTable) append(number uint64, item []byte) error {
+	if _, err := t.data.WriteAt(item, int64(t.size)); err != nil {
+		return err
+	}
+
+	end := t.size + uint64(len(item))
+
+	entry := make([]byte, freezerIndexEntrySize)
+	binary.BigEndian.PutUint64(entry, end)
+
+	if _, err := t.index.WriteAt(entry, int64(number*freezerIndexEntrySize)); err != nil {
+		return err
+	}
+
+	t.size = end
+
+	return nil
+}
+
+// retrieve reads the item with the given number
+func (t *freezerTable) retrieve(number uint64) ([]byte, error) {
+	start, err := t.offset(number)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := t.offset(number + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if end < start {
+		return nil, fmt.Errorf("corrupted freezer index for block %d", number)
+	}
+
+	item := make([]byte, end-start)
+	if _, err := t.data.ReadAt(item, int64(start)); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezer_AppendAndReopen(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	f, err := NewFreezer(path)
+	assert.NoError(t, err)
+
+	for i := uint64(0); i < 3; i++ {
+		assert.NoError(t, f.Append(i, []byte{0xc0, byte(i)}, []byte{byte(i), 0x1}, []byte{}))
+	}
+
+	assert.ErrorIs(t, f.Append(5, []byte{0xc0}, nil, nil), ErrFreezerOutOfOrder)
+	assert.NoError(t, f.Close())
+
+	f, err = NewFreezer(path)
+	assert.NoError(t, err)
+
+	defer f.Close()
+
+	assert.Equal(t, uint64(3), f.Frozen())
+
+	header, err := f.retrieve(freezerHeaders, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xc0, 0x1}, header)
+
+	body, err := f.retrieve(freezerBodies, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x2, 0x1}, body)
+
+	// the empty entries are missing
+	_, err = f.retrieve(freezerReceipts, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = f.retrieve(freezerHeaders, 3)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFreezer_RepairInterruptedAppend(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	f, err := NewFreezer(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, f.Append(0, []byte{0xc0}, []byte{0x1}, []byte{0x2}))
+	assert.NoError(t, f.Append(1, []byte{0xc1}, []byte{0x3}, []byte{0x4}))
+	assert.NoError(t, f.Close())
+
+	// the receipts of the last block are partially written
+	receipts := filepath.Join(path, freezerReceipts+".dat")
+	assert.NoError(t, os.Truncate(receipts, 1))
+
+	f, err = NewFreezer(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(1), f.Frozen())
+
+	_, err = f.retrieve(freezerHeaders, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the block is appended again
+	assert.NoError(t, f.Append(1, []byte{0xc2}, []byte{0x5}, []byte{0x6}))
+
+	header, err := f.retrieve(freezerHeaders, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xc2}, header)
+
+	assert.NoError(t, f.Close())
+}
@@ -3,6 +3,7 @@ package storage
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -39,6 +40,18 @@ var (
 
 	// TX_LOOKUP_PREFIX is the prefix for transaction lookups
 	TX_LOOKUP_PREFIX = []byte("l")
+
+	// FROZEN is the prefix for the numbers of the blocks moved to the freezer
+	FROZEN = []byte("z")
+
+	// BLOOM_BITS is the prefix for the bloom bits index sections
+	BLOOM_BITS = []byte("x")
+
+	// BAD_BLOCKS is the prefix for the blocks rejected by the verification or the execution
+	BAD_BLOCKS = []byte("k")
+
+	// FEE_LEDGER is the prefix for the fee accounting of the executed blocks
+	FEE_LEDGER = []byte("g")
 )
 
 // Sub-prefixes
@@ -46,6 +59,9 @@ var (
 	HASH   = []byte("hash")
 	NUMBER = []byte("number")
 	EMPTY  = []byte("empty")
+	BLOOM  = []byte("bloom")
+	TXTAIL = []byte("txtail")
+	BADS   = []byte("bads")
 )
 
 // KV is a key value storage interface.
@@ -55,6 +71,7 @@ type KV interface {
 	Close() error
 	Set(p []byte, v []byte) error
 	Get(p []byte) ([]byte, bool, error)
+	Delete(p []byte) error
 }
 
 // KeyValueStorage is a generic storage for kv databases
@@ -62,12 +79,38 @@ type KeyValueStorage struct {
 	logger hclog.Logger
 	db     KV
 	Db     KV
+
+	// compression is the compression of the bodies and the receipts written
+	compression Compression
+
+	// compactReceipts writes the receipts in the compact form
+	compactReceipts bool
+
+	// freezer is the store of the finalized blocks, if any
+	freezer *Freezer
 }
 
 func NewKeyValueStorage(logger hclog.Logger, db KV) Storage {
 	return &KeyValueStorage{logger: logger, db: db}
 }
 
+// SetCompression sets the compression of the bodies and the receipts written.
+// The entries already written are read whatever their compression
+func (s *KeyValueStorage) SetCompression(compression Compression) {
+	s.compression = compression
+}
+
+// SetCompactReceipts sets whether the receipts are written in the compact form, omitting the fields
+// recomputed on read and compressing the log data. The receipts are read whatever their form
+func (s *KeyValueStorage) SetCompactReceipts(compact bool) {
+	s.compactReceipts = compact
+}
+
+// SetFreezer sets the freezer the finalized blocks are moved to, and read from once frozen
+func (s *KeyValueStorage) SetFreezer(freezer *Freezer) {
+	s.freezer = freezer
+}
+
 func (s *KeyValueStorage) encodeUint(n uint64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b[:], n)
@@ -96,6 +139,11 @@ func (s *KeyValueStorage) WriteCanonicalHash(n uint64, hash types.Hash) error {
 	return s.set(CANONICAL, s.encodeUint(n), hash.Bytes())
 }
 
+// DeleteCanonicalHash removes the hash of a number block from the canonical chain
+func (s *KeyValueStorage) DeleteCanonicalHash(n uint64) error {
+	return s.delete(CANONICAL, s.encodeUint(n))
+}
+
 // HEAD //
 
 // ReadHeadHash returns the hash of the head
@@ -176,7 +224,7 @@ func (s *KeyValueStorage) WriteHeader(h *types.Header) error {
 // ReadHeader reads the header
 func (s *KeyValueStorage) ReadHeader(hash types.Hash) (*types.Header, error) {
 	header := &types.Header{}
-	err := s.readRLP(HEADER, hash.Bytes(), header)
+	err := s.readBlockRLP(HEADER, freezerHeaders, hash, header)
 
 	return header, err
 }
@@ -210,17 +258,22 @@ func (s *KeyValueStorage) WriteCanonicalHeader(h *types.Header, diff *big.Int) e
 
 // WriteBody writes the body
 func (s *KeyValueStorage) WriteBody(hash types.Hash, body *types.Body) error {
-	return s.writeRLP(BODY, hash.Bytes(), body)
+	return s.writeCompressedRLP(BODY, hash.Bytes(), body)
 }
 
 // ReadBody reads the body
 func (s *KeyValueStorage) ReadBody(hash types.Hash) (*types.Body, error) {
 	body := &types.Body{}
-	err := s.readRLP(BODY, hash.Bytes(), body)
+	err := s.readBlockRLP(BODY, freezerBodies, hash, body)
 
 	return body, err
 }
 
+// DeleteBody removes the body
+func (s *KeyValueStorage) DeleteBody(hash types.Hash) error {
+	return s.delete(BODY, hash.Bytes())
+}
+
 // SNAPSHOTS //
 
 // WriteSnapshot writes the snapshot to the DB
@@ -244,73 +297,386 @@ func (s *KeyValueStorage) ReadSnapshot(hash types.Hash) ([]byte, bool) {
 func (s *KeyValueStorage) WriteReceipts(hash types.Hash, receipts []*types.Receipt) error {
 	rr := types.Receipts(receipts)
 
-	return s.writeRLP(RECEIPTS, hash.Bytes(), &rr)
+	if !s.compactReceipts || !canCompactReceipts(rr) {
+		return s.writeCompressedRLP(RECEIPTS, hash.Bytes(), &rr)
+	}
+
+	data, err := compress(s.compression, marshalCompactReceipts(rr))
+	if err != nil {
+		return err
+	}
+
+	return s.set(RECEIPTS, hash.Bytes(), data)
 }
 
-// ReadReceipts reads the receipts
+// ReadReceipts reads the receipts, written in the compact or the legacy form
 func (s *KeyValueStorage) ReadReceipts(hash types.Hash) ([]*types.Receipt, error) {
-	receipts := &types.Receipts{}
-	err := s.readRLP(RECEIPTS, hash.Bytes(), receipts)
+	data, err := s.readBlockData(RECEIPTS, freezerReceipts, hash)
+	if err != nil {
+		return types.Receipts{}, err
+	}
+
+	return unmarshalReceipts(data)
+}
 
-	return *receipts, err
+// DeleteReceipts removes the receipts
+func (s *KeyValueStorage) DeleteReceipts(hash types.Hash) error {
+	return s.delete(RECEIPTS, hash.Bytes())
 }
 
 // TX LOOKUP //
 
-// WriteTxLookup maps the transaction hash to the block hash
-func (s *KeyValueStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash) error {
+// WriteTxLookup maps the transaction hash to its position in the canonical chain
+func (s *KeyValueStorage) WriteTxLookup(hash types.Hash, entry *TxLookupEntry) error {
 	ar := &fastrlp.Arena{}
-	vr := ar.NewBytes(blockHash.Bytes())
+
+	vr := ar.NewArray()
+	vr.Set(ar.NewBytes(entry.BlockHash.Bytes()))
+	vr.Set(ar.NewUint(entry.BlockNumber))
+	vr.Set(ar.NewUint(entry.Index))
 
 	return s.write2(TX_LOOKUP_PREFIX, hash.Bytes(), vr)
 }
 
-// ReadTxLookup reads the block hash using the transaction hash
-func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+// ReadTxLookup reads the position of the transaction in the canonical chain.
+// The entries written before the position was kept only have the block hash
+func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (*TxLookupEntry, bool) {
 	parser := &fastrlp.Parser{}
 
 	v := s.read2(TX_LOOKUP_PREFIX, hash.Bytes(), parser)
 	if v == nil {
-		return types.Hash{}, false
+		return nil, false
+	}
+
+	entry := &TxLookupEntry{}
+
+	if v.Type() == fastrlp.TypeBytes {
+		if err := v.GetHash(entry.BlockHash[:]); err != nil {
+			return nil, false
+		}
+
+		return entry, true
+	}
+
+	elems, err := v.GetElems()
+	if err != nil || len(elems) != 3 {
+		return nil, false
+	}
+
+	if err := elems[0].GetHash(entry.BlockHash[:]); err != nil {
+		return nil, false
+	}
+
+	if entry.BlockNumber, err = elems[1].GetUint64(); err != nil {
+		return nil, false
+	}
+
+	if entry.Index, err = elems[2].GetUint64(); err != nil {
+		return nil, false
+	}
+
+	entry.Positioned = true
+
+	return entry, true
+}
+
+// DeleteTxLookup removes the block hash of the transaction
+func (s *KeyValueStorage) DeleteTxLookup(hash types.Hash) error {
+	return s.delete(TX_LOOKUP_PREFIX, hash.Bytes())
+}
+
+// WriteTxLookupTail writes the number of the first canonical block whose transaction lookups are kept
+func (s *KeyValueStorage) WriteTxLookupTail(number uint64) error {
+	return s.set(HEAD, TXTAIL, s.encodeUint(number))
+}
+
+// ReadTxLookupTail reads the number of the first canonical block whose transaction lookups are kept,
+// zero if all of them are
+func (s *KeyValueStorage) ReadTxLookupTail() uint64 {
+	data, ok := s.get(HEAD, TXTAIL)
+	if !ok {
+		return 0
+	}
+
+	return s.decodeUint(data)
+}
+
+// BLOOM BITS //
+
+// WriteBloomBits writes the bloom bit of the blocks of the section
+func (s *KeyValueStorage) WriteBloomBits(bit uint, section uint64, bits []byte) error {
+	return s.set(BLOOM_BITS, bloomBitsKey(bit, section), bits)
+}
+
+// ReadBloomBits reads the bloom bit of the blocks of the section
+func (s *KeyValueStorage) ReadBloomBits(bit uint, section uint64) ([]byte, bool) {
+	return s.get(BLOOM_BITS, bloomBitsKey(bit, section))
+}
+
+// WriteBloomSections writes the number of sections of the bloom bits index
+func (s *KeyValueStorage) WriteBloomSections(sections uint64) error {
+	return s.set(HEAD, BLOOM, s.encodeUint(sections))
+}
+
+// ReadBloomSections reads the number of sections of the bloom bits index, zero if not indexed
+func (s *KeyValueStorage) ReadBloomSections() uint64 {
+	data, ok := s.get(HEAD, BLOOM)
+	if !ok {
+		return 0
+	}
+
+	return s.decodeUint(data)
+}
+
+// BAD BLOCKS //
+
+// WriteBadBlock writes the rejected block along with the reason
+func (s *KeyValueStorage) WriteBadBlock(bad *BadBlock) error {
+	return s.writeRLP(BAD_BLOCKS, bad.Block.Hash().Bytes(), bad)
+}
+
+// ReadBadBlock reads the rejected block with the given hash
+func (s *KeyValueStorage) ReadBadBlock(hash types.Hash) (*BadBlock, error) {
+	bad := &BadBlock{}
+	err := s.readRLP(BAD_BLOCKS, hash.Bytes(), bad)
+
+	return bad, err
+}
+
+// DeleteBadBlock removes the rejected block with the given hash
+func (s *KeyValueStorage) DeleteBadBlock(hash types.Hash) error {
+	return s.delete(BAD_BLOCKS, hash.Bytes())
+}
+
+// WriteBadBlockHashes writes the hashes of the rejected blocks kept, the oldest first
+func (s *KeyValueStorage) WriteBadBlockHashes(hashes []types.Hash) error {
+	h := Forks(hashes)
+
+	return s.writeRLP(HEAD, BADS, &h)
+}
+
+// ReadBadBlockHashes reads the hashes of the rejected blocks kept, the oldest first
+func (s *KeyValueStorage) ReadBadBlockHashes() ([]types.Hash, error) {
+	hashes := &Forks{}
+	err := s.readRLP(HEAD, BADS, hashes)
+
+	return *hashes, err
+}
+
+// FEE LEDGER //
+
+// WriteFeeLedgerEntry writes the fee accounting of the block with the given hash
+func (s *KeyValueStorage) WriteFeeLedgerEntry(hash types.Hash, entry *FeeLedgerEntry) error {
+	return s.writeRLP(FEE_LEDGER, hash.Bytes(), entry)
+}
+
+// ReadFeeLedgerEntry reads the fee accounting of the block with the given hash
+func (s *KeyValueStorage) ReadFeeLedgerEntry(hash types.Hash) (*FeeLedgerEntry, error) {
+	entry := &FeeLedgerEntry{}
+	err := s.readRLP(FEE_LEDGER, hash.Bytes(), entry)
+
+	return entry, err
+}
+
+// bloomBitsKey returns the key of the bloom bit of a section, the bit followed by the section
+func bloomBitsKey(bit uint, section uint64) []byte {
+	key := make([]byte, 10)
+	binary.BigEndian.PutUint16(key[:2], uint16(bit))
+	binary.BigEndian.PutUint64(key[2:], section)
+
+	return key
+}
+
+// FREEZER //
+
+// Frozen returns the number of blocks moved to the freezer, zero without freezer
+func (s *KeyValueStorage) Frozen() uint64 {
+	if s.freezer == nil {
+		return 0
+	}
+
+	return s.freezer.Frozen()
+}
+
+// FreezeBlocks moves the headers, the bodies and the receipts of the canonical blocks below
+// the given number, and not frozen yet, to the freezer. The blocks are appended in order from
+// the genesis, and are removed from the key-value storage once the freezer is synced to disk.
+// The canonical hashes, the difficulties and the transaction lookups are kept.
+// It returns the number of blocks frozen
+func (s *KeyValueStorage) FreezeBlocks(limit uint64) (uint64, error) {
+	if s.freezer == nil {
+		return 0, errNoFreezer
 	}
 
-	blockHash := []byte{}
-	blockHash, err := v.GetBytes(blockHash[:0], 32)
+	hashes := []types.Hash{}
+
+	var freezeErr error
 
+	for number := s.freezer.Frozen(); number < limit; number++ {
+		hash, err := s.freezeBlock(number)
+		if err != nil {
+			// the blocks already appended are removed anyway
+			freezeErr = fmt.Errorf("failed to freeze block %d: %w", number, err)
+
+			break
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if len(hashes) == 0 {
+		return 0, freezeErr
+	}
+
+	if err := s.freezer.Sync(); err != nil {
+		return 0, err
+	}
+
+	for _, hash := range hashes {
+		for _, p := range [][]byte{HEADER, BODY, RECEIPTS} {
+			if err := s.delete(p, hash.Bytes()); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return uint64(len(hashes)), freezeErr
+}
+
+// freezeBlock appends the canonical block with the given number to the freezer, and returns its hash
+func (s *KeyValueStorage) freezeBlock(number uint64) (types.Hash, error) {
+	hash, ok := s.ReadCanonicalHash(number)
+	if !ok {
+		return types.Hash{}, fmt.Errorf("canonical hash not found")
+	}
+
+	header, err := s.read(HEADER, hash.Bytes())
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	// the body and the receipts of a pruned block are missing
+	body, err := s.readOptional(BODY, hash.Bytes())
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	receipts, err := s.readOptional(RECEIPTS, hash.Bytes())
 	if err != nil {
-		panic(err)
+		return types.Hash{}, err
+	}
+
+	// the number is written first, so that the entries are found once removed
+	if err := s.set(FROZEN, hash.Bytes(), s.encodeUint(number)); err != nil {
+		return types.Hash{}, err
 	}
 
-	return types.BytesToHash(blockHash), true
+	if err := s.freezer.Append(number, header, body, receipts); err != nil {
+		return types.Hash{}, err
+	}
+
+	return hash, nil
+}
+
+var errNoFreezer = errors.New("no freezer set")
+
+// readFrozen reads the entry of the frozen block with the given hash from the freezer table
+func (s *KeyValueStorage) readFrozen(table string, hash types.Hash) ([]byte, error) {
+	data, ok := s.get(FROZEN, hash.Bytes())
+	if !ok || len(data) != 8 {
+		return nil, ErrNotFound
+	}
+
+	return s.freezer.retrieve(table, s.decodeUint(data))
 }
 
 // WRITE OPERATIONS //
 
 func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
-	var data []byte
-	if obj, ok := raw.(types.RLPStoreMarshaler); ok {
-		data = obj.MarshalStoreRLPTo(nil)
-	} else {
-		data = raw.MarshalRLPTo(nil)
+	return s.set(p, k, marshalRLP(raw))
+}
+
+// writeCompressedRLP writes the object with the compression of the storage
+func (s *KeyValueStorage) writeCompressedRLP(p, k []byte, raw types.RLPMarshaler) error {
+	data, err := compress(s.compression, marshalRLP(raw))
+	if err != nil {
+		return err
 	}
 
 	return s.set(p, k, data)
 }
 
+func marshalRLP(raw types.RLPMarshaler) []byte {
+	if obj, ok := raw.(types.RLPStoreMarshaler); ok {
+		return obj.MarshalStoreRLPTo(nil)
+	}
+
+	return raw.MarshalRLPTo(nil)
+}
+
 var ErrNotFound = fmt.Errorf("not found")
 
 func (s *KeyValueStorage) readRLP(p, k []byte, raw types.RLPUnmarshaler) error {
+	data, err := s.read(p, k)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalRLP(data, raw)
+}
+
+// readBlockRLP reads the header, the body or the receipts of the block, from the freezer once
+// the block is frozen. The bodies and the receipts are written with any compression, the headers
+// uncompressed, as RLP lists
+func (s *KeyValueStorage) readBlockRLP(p []byte, table string, hash types.Hash, raw types.RLPUnmarshaler) error {
+	data, err := s.readBlockData(p, table, hash)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalRLP(data, raw)
+}
+
+// readBlockData reads the decompressed entry of the block, from the freezer once the block is frozen
+func (s *KeyValueStorage) readBlockData(p []byte, table string, hash types.Hash) ([]byte, error) {
+	data, err := s.read(p, hash.Bytes())
+	if errors.Is(err, ErrNotFound) && s.freezer != nil {
+		data, err = s.readFrozen(table, hash)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decompress(data)
+}
+
+// readOptional reads the entry, empty if it is missing
+func (s *KeyValueStorage) readOptional(p, k []byte) ([]byte, error) {
+	data, err := s.read(p, k)
+	if errors.Is(err, ErrNotFound) {
+		return []byte{}, nil
+	}
+
+	return data, err
+}
+
+func (s *KeyValueStorage) read(p, k []byte) ([]byte, error) {
 	p = append(p, k...)
 	data, ok, err := s.db.Get(p)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !ok {
-		return ErrNotFound
+		return nil, ErrNotFound
 	}
 
+	return data, nil
+}
+
+func unmarshalRLP(data []byte, raw types.RLPUnmarshaler) error {
 	if obj, ok := raw.(types.RLPStoreUnmarshaler); ok {
 		// decode in the store format
 		if err := obj.UnmarshalStoreRLP(data); err != nil {
@@ -352,6 +718,12 @@ func (s *KeyValueStorage) set(p []byte, k []byte, v []byte) error {
 	return s.db.Set(p, v)
 }
 
+func (s *KeyValueStorage) delete(p []byte, k []byte) error {
+	p = append(p, k...)
+
+	return s.db.Delete(p)
+}
+
 func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
 	p = append(p, k...)
 	data, ok, err := s.db.Get(p)
@@ -363,7 +735,15 @@ func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
 	return data, ok
 }
 
-// Close closes the connection with the db
+// Close closes the connection with the db, and the freezer
 func (s *KeyValueStorage) Close() error {
+	if s.freezer != nil {
+		if err := s.freezer.Close(); err != nil {
+			_ = s.db.Close()
+
+			return err
+		}
+	}
+
 	return s.db.Close()
 }
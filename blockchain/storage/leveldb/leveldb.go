@@ -6,6 +6,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/hashicorp/go-hclog"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // Factory creates a leveldb storage
@@ -25,14 +26,22 @@ func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storag
 
 // NewLevelDBStorage creates the new storage reference with leveldb
 func NewLevelDBStorage(path string, logger hclog.Logger) (storage.Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := OpenDatabase(path)
 	if err != nil {
 		return nil, err
 	}
 
-	kv := &levelDBKV{db}
+	return storage.NewKeyValueStorage(logger.Named("leveldb"), db), nil
+}
+
+// OpenDatabase opens the leveldb database at the given path, creating it if needed
+func OpenDatabase(path string) (storage.Database, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	return storage.NewKeyValueStorage(logger.Named("leveldb"), kv), nil
+	return &levelDBKV{db}, nil
 }
 
 // levelDBKV is the leveldb implementation of the kv storage
@@ -59,7 +68,68 @@ func (l *levelDBKV) Get(p []byte) ([]byte, bool, error) {
 	return data, true, nil
 }
 
+// Delete removes the key-value pair from leveldb storage
+func (l *levelDBKV) Delete(p []byte) error {
+	return l.db.Delete(p, nil)
+}
+
+// Iterate calls fn with the entries whose key starts with the prefix, in key order
+func (l *levelDBKV) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	iter := l.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+// NewBatch creates a batch of writes applied at once
+func (l *levelDBKV) NewBatch() storage.Batch {
+	return &levelDBBatch{db: l.db, batch: &leveldb.Batch{}}
+}
+
+// Compact compacts the whole leveldb storage
+func (l *levelDBKV) Compact() error {
+	return l.db.CompactRange(util.Range{})
+}
+
 // Close closes the leveldb storage instance
 func (l *levelDBKV) Close() error {
 	return l.db.Close()
 }
+
+// levelDBBatch is a batch of leveldb writes
+type levelDBBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+}
+
+// Set adds the key-value pair to the batch
+func (b *levelDBBatch) Set(k, v []byte) {
+	b.batch.Put(k, v)
+}
+
+// Delete adds the removal of the key to the batch
+func (b *levelDBBatch) Delete(k []byte) {
+	b.batch.Delete(k)
+}
+
+// Len returns the number of writes in the batch
+func (b *levelDBBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Write applies the batch, and resets it
+func (b *levelDBBatch) Write() error {
+	if err := b.db.Write(b.batch, nil); err != nil {
+		return err
+	}
+
+	b.batch.Reset()
+
+	return nil
+}
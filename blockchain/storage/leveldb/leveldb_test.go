@@ -1,12 +1,16 @@
 package leveldb
 
 import (
+	"bytes"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
 )
 
 func newStorage(t *testing.T) (storage.Storage, func()) {
@@ -38,3 +42,60 @@ func newStorage(t *testing.T) (storage.Storage, func()) {
 func TestStorage(t *testing.T) {
 	storage.TestStorage(t, newStorage)
 }
+
+func TestRecompress(t *testing.T) {
+	path, err := ioutil.TempDir("/tmp", "minimal_storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(path)
+
+	s, err := NewLevelDBStorage(path, hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	txn := &types.Transaction{
+		GasPrice: big.NewInt(1),
+		Input:    bytes.Repeat([]byte{0x1}, 1024),
+		V:        big.NewInt(1),
+	}
+	txn.ComputeHash()
+
+	hash := types.StringToHash("1")
+
+	assert.NoError(t, s.WriteHeader(&types.Header{Hash: hash}))
+	assert.NoError(t, s.WriteBody(hash, &types.Body{Transactions: []*types.Transaction{txn}}))
+	assert.NoError(t, s.WriteReceipts(hash, []*types.Receipt{{TxHash: txn.Hash}}))
+	assert.NoError(t, s.Close())
+
+	db, err := OpenDatabase(path)
+	assert.NoError(t, err)
+
+	// the body and the receipts are rewritten, not the header
+	rewritten, err := storage.RecompressDatabase(db, storage.CompressionZstd)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), rewritten)
+
+	rewritten, err = storage.RecompressDatabase(db, storage.CompressionZstd)
+	assert.NoError(t, err)
+	assert.Zero(t, rewritten)
+
+	assert.NoError(t, db.Compact())
+	assert.NoError(t, db.Close())
+
+	s, err = NewLevelDBStorage(path, hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	defer s.Close()
+
+	body, err := s.ReadBody(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash, body.Transactions[0].Hash)
+
+	receipts, err := s.ReadReceipts(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash, receipts[0].TxHash)
+
+	_, err = s.ReadHeader(hash)
+	assert.NoError(t, err)
+}
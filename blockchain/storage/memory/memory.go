@@ -33,6 +33,12 @@ func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
 	return v, true, nil
 }
 
+func (m *memoryKV) Delete(p []byte) error {
+	delete(m.db, hex.EncodeToHex(p))
+
+	return nil
+}
+
 func (m *memoryKV) Close() error {
 	return nil
 }
@@ -0,0 +1,180 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/cockroachdb/pebble"
+	"github.com/hashicorp/go-hclog"
+)
+
+// Factory creates a pebble storage
+func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storage, error) {
+	path, ok := config["path"]
+	if !ok {
+		return nil, fmt.Errorf("path not found")
+	}
+
+	pathStr, ok := path.(string)
+	if !ok {
+		return nil, fmt.Errorf("path is not a string")
+	}
+
+	return NewPebbleStorage(pathStr, logger)
+}
+
+// NewPebbleStorage creates the new storage reference with pebble
+func NewPebbleStorage(path string, logger hclog.Logger) (storage.Storage, error) {
+	db, err := OpenDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.NewKeyValueStorage(logger.Named("pebble"), db), nil
+}
+
+// OpenDatabase opens the pebble database at the given path, creating it if needed
+func OpenDatabase(path string) (storage.Database, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pebbleKV{db}, nil
+}
+
+// pebbleKV is the pebble implementation of the kv storage
+type pebbleKV struct {
+	db *pebble.DB
+}
+
+// Set sets the key-value pair in pebble storage. The write isn't synced, as the leveldb writes
+func (p *pebbleKV) Set(k []byte, v []byte) error {
+	return p.db.Set(k, v, pebble.NoSync)
+}
+
+// Get retrieves the key-value pair in pebble storage
+func (p *pebbleKV) Get(k []byte) ([]byte, bool, error) {
+	data, closer, err := p.db.Get(k)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	defer closer.Close()
+
+	// the value is only valid until the closer is closed
+	return append([]byte{}, data...), true, nil
+}
+
+// Delete removes the key-value pair from pebble storage
+func (p *pebbleKV) Delete(k []byte) error {
+	return p.db.Delete(k, pebble.NoSync)
+}
+
+// Iterate calls fn with the entries whose key starts with the prefix, in key order
+func (p *pebbleKV) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	iter := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			_ = iter.Close()
+
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// NewBatch creates a batch of writes applied at once
+func (p *pebbleKV) NewBatch() storage.Batch {
+	return &pebbleBatch{batch: p.db.NewBatch()}
+}
+
+// Compact compacts the whole pebble storage, between its first and its last keys
+func (p *pebbleKV) Compact() error {
+	iter := p.db.NewIter(nil)
+
+	var first, last []byte
+
+	if iter.First() {
+		first = append([]byte{}, iter.Key()...)
+	}
+
+	if iter.Last() {
+		last = append([]byte{}, iter.Key()...)
+	}
+
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if first == nil {
+		return nil
+	}
+
+	// the end of the range is exclusive
+	return p.db.Compact(first, append(last, 0x0), true)
+}
+
+// Close closes the pebble storage instance
+func (p *pebbleKV) Close() error {
+	return p.db.Close()
+}
+
+// prefixUpperBound returns the lowest key greater than all the keys starting with the prefix,
+// or nil if there is none
+func prefixUpperBound(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
+
+// pebbleBatch is a batch of pebble writes
+type pebbleBatch struct {
+	batch *pebble.Batch
+}
+
+// Set adds the key-value pair to the batch
+func (b *pebbleBatch) Set(k, v []byte) {
+	// the batch copies the key and the value, it never fails
+	_ = b.batch.Set(k, v, nil)
+}
+
+// Delete adds the removal of the key to the batch
+func (b *pebbleBatch) Delete(k []byte) {
+	// the batch copies the key, it never fails
+	_ = b.batch.Delete(k, nil)
+}
+
+// Len returns the number of writes in the batch
+func (b *pebbleBatch) Len() int {
+	return int(b.batch.Count())
+}
+
+// Write applies the batch, and resets it
+func (b *pebbleBatch) Write() error {
+	if err := b.batch.Commit(pebble.NoSync); err != nil {
+		return err
+	}
+
+	b.batch.Reset()
+
+	return nil
+}
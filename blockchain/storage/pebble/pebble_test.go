@@ -0,0 +1,88 @@
+package pebble
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStorage(t *testing.T) (storage.Storage, func()) {
+	t.Helper()
+
+	s, err := NewPebbleStorage(t.TempDir(), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closeFn := func() {
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s, closeFn
+}
+
+func TestStorage(t *testing.T) {
+	storage.TestStorage(t, newStorage)
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []byte("i"), prefixUpperBound([]byte("h")))
+	assert.Equal(t, []byte{0x1, 0x3}, prefixUpperBound([]byte{0x1, 0x2, 0xff}))
+	assert.Nil(t, prefixUpperBound([]byte{0xff, 0xff}))
+	assert.Nil(t, prefixUpperBound(nil))
+}
+
+func TestCopyDatabase(t *testing.T) {
+	t.Parallel()
+
+	levelPath, pebblePath := t.TempDir(), t.TempDir()
+
+	src, err := leveldb.OpenDatabase(levelPath)
+	assert.NoError(t, err)
+
+	header := &types.Header{Number: 1, ExtraData: []byte{}}
+	header.ComputeHash()
+
+	s := storage.NewKeyValueStorage(hclog.NewNullLogger(), src)
+	assert.NoError(t, s.WriteCanonicalHeader(header, big.NewInt(1)))
+
+	dst, err := OpenDatabase(pebblePath)
+	assert.NoError(t, err)
+
+	copied, err := storage.CopyDatabase(src, dst)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), copied)
+
+	assert.NoError(t, src.Close())
+
+	engine, ok, err := storage.DetectEngine(pebblePath)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, storage.EnginePebble, engine)
+
+	engine, ok, err = storage.DetectEngine(levelPath)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, storage.EngineLevelDB, engine)
+
+	// the copied chain is read from pebble
+	s = storage.NewKeyValueStorage(hclog.NewNullLogger(), dst)
+	defer s.Close()
+
+	hash, ok := s.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, header.Hash, hash)
+
+	found, err := s.ReadHeader(header.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, found.Hash)
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/golang/snappy"
+	"github.com/umbracle/fastrlp"
+)
+
+// The compact receipts omit the fields recomputed on read: the bloom of each receipt, derived from
+// its logs, and the gas used, the difference of the cumulative gas used of consecutive receipts.
+// They are written as [version, [receipt...]], a legacy receipts entry being a list of lists, so
+// both are told apart and read. The receipts whose omitted fields can't be recomputed exactly
+// are written in the legacy form
+const compactReceiptsVersion = 1
+
+// the encodings of the log data of the compact receipts
+const (
+	logDataRaw uint64 = iota
+	logDataSnappy
+)
+
+// minLogDataCompression is the size from which the log data is compressed
+const minLogDataCompression = 64
+
+var errInvalidCompactReceipts = errors.New("invalid compact receipts")
+
+// canCompactReceipts returns whether the omitted fields of the receipts are recomputed exactly on read
+func canCompactReceipts(receipts types.Receipts) bool {
+	cumulativeGasUsed := uint64(0)
+
+	for _, receipt := range receipts {
+		if receipt.CumulativeGasUsed < cumulativeGasUsed ||
+			receipt.GasUsed != receipt.CumulativeGasUsed-cumulativeGasUsed {
+			return false
+		}
+
+		if receipt.LogsBloom != types.CreateBloom([]*types.Receipt{receipt}) {
+			return false
+		}
+
+		cumulativeGasUsed = receipt.CumulativeGasUsed
+	}
+
+	return true
+}
+
+// marshalCompactReceipts encodes the receipts in the compact form
+func marshalCompactReceipts(receipts types.Receipts) []byte {
+	ar := &fastrlp.Arena{}
+
+	list := ar.NewArray()
+
+	for _, receipt := range receipts {
+		list.Set(marshalCompactReceipt(ar, receipt))
+	}
+
+	v := ar.NewArray()
+	v.Set(ar.NewUint(compactReceiptsVersion))
+	v.Set(list)
+
+	return v.MarshalTo(nil)
+}
+
+func marshalCompactReceipt(ar *fastrlp.Arena, receipt *types.Receipt) *fastrlp.Value {
+	v := ar.NewArray()
+
+	if receipt.Status != nil {
+		v.Set(ar.NewUint(uint64(*receipt.Status)))
+	} else {
+		v.Set(ar.NewBytes(receipt.Root[:]))
+	}
+
+	v.Set(ar.NewUint(receipt.CumulativeGasUsed))
+
+	logs := ar.NewArray()
+	for _, log := range receipt.Logs {
+		logs.Set(marshalCompactLog(ar, log))
+	}
+
+	v.Set(logs)
+
+	if receipt.ContractAddress == nil {
+		v.Set(ar.NewNull())
+	} else {
+		v.Set(ar.NewBytes(receipt.ContractAddress.Bytes()))
+	}
+
+	v.Set(ar.NewBytes(receipt.TxHash.Bytes()))
+
+	return v
+}
+
+func marshalCompactLog(ar *fastrlp.Arena, log *types.Log) *fastrlp.Value {
+	v := ar.NewArray()
+	v.Set(ar.NewBytes(log.Address.Bytes()))
+
+	topics := ar.NewArray()
+	for _, topic := range log.Topics {
+		topics.Set(ar.NewBytes(topic.Bytes()))
+	}
+
+	v.Set(topics)
+
+	// the data is compressed only if it gets smaller
+	encoding, data := logDataRaw, log.Data
+
+	if len(log.Data) >= minLogDataCompression {
+		if compressed := snappy.Encode(nil, log.Data); len(compressed) < len(log.Data) {
+			encoding, data = logDataSnappy, compressed
+		}
+	}
+
+	v.Set(ar.NewUint(encoding))
+	v.Set(ar.NewCopyBytes(data))
+
+	return v
+}
+
+// isCompactReceipts returns whether the decoded receipts entry is in the compact form
+func isCompactReceipts(v *fastrlp.Value) bool {
+	return v.Type() == fastrlp.TypeArray && v.Elems() == 2 && v.Get(0).Type() == fastrlp.TypeBytes
+}
+
+// unmarshalReceipts decodes a receipts entry, in the compact or the legacy form
+func unmarshalReceipts(data []byte) (types.Receipts, error) {
+	p := &fastrlp.Parser{}
+
+	v, err := p.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := types.Receipts{}
+
+	if !isCompactReceipts(v) {
+		if err := receipts.UnmarshalStoreRLPFrom(p, v); err != nil {
+			return nil, err
+		}
+
+		return receipts, nil
+	}
+
+	version, err := v.Get(0).GetUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	if version != compactReceiptsVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", errInvalidCompactReceipts, version)
+	}
+
+	elems, err := v.Get(1).GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	cumulativeGasUsed := uint64(0)
+
+	for _, elem := range elems {
+		receipt, err := unmarshalCompactReceipt(elem)
+		if err != nil {
+			return nil, err
+		}
+
+		if receipt.CumulativeGasUsed < cumulativeGasUsed {
+			return nil, fmt.Errorf("%w: decreasing cumulative gas used", errInvalidCompactReceipts)
+		}
+
+		receipt.GasUsed = receipt.CumulativeGasUsed - cumulativeGasUsed
+		receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
+		cumulativeGasUsed = receipt.CumulativeGasUsed
+
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+func unmarshalCompactReceipt(v *fastrlp.Value) (*types.Receipt, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elems) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 receipt elements but found %d", errInvalidCompactReceipts, len(elems))
+	}
+
+	receipt := &types.Receipt{}
+
+	// root or status
+	buf, err := elems[0].Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(buf) {
+	case types.HashLength:
+		copy(receipt.Root[:], buf)
+	case 1:
+		receipt.SetStatus(types.ReceiptStatus(buf[0]))
+	default:
+		receipt.SetStatus(0)
+	}
+
+	if receipt.CumulativeGasUsed, err = elems[1].GetUint64(); err != nil {
+		return nil, err
+	}
+
+	logElems, err := elems[2].GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, logElem := range logElems {
+		log, err := unmarshalCompactLog(logElem)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.Logs = append(receipt.Logs, log)
+	}
+
+	// contract address
+	if buf, err = elems[3].Bytes(); err != nil {
+		return nil, err
+	}
+
+	if len(buf) == types.AddressLength {
+		receipt.SetContractAddress(types.BytesToAddress(buf))
+	}
+
+	if buf, err = elems[4].Bytes(); err != nil {
+		return nil, err
+	}
+
+	receipt.TxHash = types.BytesToHash(buf)
+
+	return receipt, nil
+}
+
+func unmarshalCompactLog(v *fastrlp.Value) (*types.Log, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elems) != 4 {
+		return nil, fmt.Errorf("%w: expected 4 log elements but found %d", errInvalidCompactReceipts, len(elems))
+	}
+
+	log := &types.Log{}
+
+	if err := elems[0].GetAddr(log.Address[:]); err != nil {
+		return nil, err
+	}
+
+	topicElems, err := elems[1].GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Topics = make([]types.Hash, len(topicElems))
+
+	for i, topic := range topicElems {
+		if err := topic.GetHash(log.Topics[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	encoding, err := elems[2].GetUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := elems[3].Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case logDataRaw:
+		log.Data = append(log.Data[:0], data...)
+	case logDataSnappy:
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if size > maxDecompressedSize {
+			return nil, errEntryTooLarge
+		}
+
+		if log.Data, err = snappy.Decode(nil, data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown log data encoding %d", errInvalidCompactReceipts, encoding)
+	}
+
+	return log, nil
+}
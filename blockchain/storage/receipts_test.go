@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+func TestCompactReceipts(t *testing.T) {
+	t.Parallel()
+
+	receipts := newCompactableReceipts()
+	assert.True(t, canCompactReceipts(receipts))
+
+	compact := marshalCompactReceipts(receipts)
+	legacy := receipts.MarshalStoreRLPTo(nil)
+
+	// the blooms are dropped and the repeated log data is compressed
+	assert.Less(t, len(compact), len(legacy)/4)
+
+	// both forms are read
+	for _, data := range [][]byte{compact, legacy} {
+		decoded, err := unmarshalReceipts(data)
+		assert.NoError(t, err)
+		assert.Equal(t, receipts, decoded)
+	}
+}
+
+func TestCompactReceipts_Empty(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range [][]byte{marshalCompactReceipts(types.Receipts{}), types.Receipts{}.MarshalStoreRLPTo(nil)} {
+		decoded, err := unmarshalReceipts(data)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, 0)
+	}
+}
+
+func TestCanCompactReceipts(t *testing.T) {
+	t.Parallel()
+
+	// a gas used not matching the cumulative gas used
+	receipts := newCompactableReceipts()
+	receipts[1].GasUsed++
+
+	assert.False(t, canCompactReceipts(receipts))
+
+	// a bloom not matching the logs
+	receipts = newCompactableReceipts()
+	receipts[0].LogsBloom = types.Bloom{0x1}
+
+	assert.False(t, canCompactReceipts(receipts))
+}
+
+func TestUnmarshalReceipts_UnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	ar := &fastrlp.Arena{}
+
+	v := ar.NewArray()
+	v.Set(ar.NewUint(compactReceiptsVersion + 1))
+	v.Set(ar.NewArray())
+
+	_, err := unmarshalReceipts(v.MarshalTo(nil))
+	assert.ErrorIs(t, err, errInvalidCompactReceipts)
+}
@@ -11,6 +11,7 @@ import (
 type Storage interface {
 	ReadCanonicalHash(n uint64) (types.Hash, bool)
 	WriteCanonicalHash(n uint64, hash types.Hash) error
+	DeleteCanonicalHash(n uint64) error
 
 	ReadHeadHash() (types.Hash, bool)
 	ReadHeadNumber() (uint64, bool)
@@ -30,18 +31,58 @@ type Storage interface {
 
 	WriteBody(hash types.Hash, body *types.Body) error
 	ReadBody(hash types.Hash) (*types.Body, error)
+	DeleteBody(hash types.Hash) error
 
 	WriteSnapshot(hash types.Hash, blob []byte) error
 	ReadSnapshot(hash types.Hash) ([]byte, bool)
 
 	WriteReceipts(hash types.Hash, receipts []*types.Receipt) error
 	ReadReceipts(hash types.Hash) ([]*types.Receipt, error)
+	DeleteReceipts(hash types.Hash) error
 
-	WriteTxLookup(hash types.Hash, blockHash types.Hash) error
-	ReadTxLookup(hash types.Hash) (types.Hash, bool)
+	WriteTxLookup(hash types.Hash, entry *TxLookupEntry) error
+	ReadTxLookup(hash types.Hash) (*TxLookupEntry, bool)
+	DeleteTxLookup(hash types.Hash) error
+	WriteTxLookupTail(number uint64) error
+	ReadTxLookupTail() uint64
+
+	WriteBloomBits(bit uint, section uint64, bits []byte) error
+	ReadBloomBits(bit uint, section uint64) ([]byte, bool)
+	WriteBloomSections(sections uint64) error
+	ReadBloomSections() uint64
+
+	WriteBadBlock(bad *BadBlock) error
+	ReadBadBlock(hash types.Hash) (*BadBlock, error)
+	DeleteBadBlock(hash types.Hash) error
+	WriteBadBlockHashes(hashes []types.Hash) error
+	ReadBadBlockHashes() ([]types.Hash, error)
+
+	WriteFeeLedgerEntry(hash types.Hash, entry *FeeLedgerEntry) error
+	ReadFeeLedgerEntry(hash types.Hash) (*FeeLedgerEntry, error)
+
+	// NewWriteBatch returns a storage buffering its writes, which are applied at once by its Write
+	NewWriteBatch() WriteBatch
+
+	SetCompression(compression Compression)
+	SetCompactReceipts(compact bool)
+
+	SetFreezer(freezer *Freezer)
+	Frozen() uint64
+	FreezeBlocks(limit uint64) (uint64, error)
 
 	Close() error
 }
 
+// TxLookupEntry is the position of a transaction in the canonical chain
+type TxLookupEntry struct {
+	BlockHash   types.Hash
+	BlockNumber uint64
+	Index       uint64
+
+	// Positioned is false for the entries written before the position was kept,
+	// which only have the block hash
+	Positioned bool
+}
+
 // Factory is a factory method to create a blockchain storage
 type Factory func(config map[string]interface{}, logger hclog.Logger) (Storage, error)
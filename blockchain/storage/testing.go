@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"math/big"
 	"reflect"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
 )
 
 type PlaceholderStorage func(t *testing.T) (Storage, func())
@@ -48,6 +50,30 @@ func TestStorage(t *testing.T, m PlaceholderStorage) {
 	t.Run("", func(t *testing.T) {
 		testReceipts(t, m)
 	})
+	t.Run("", func(t *testing.T) {
+		testCompression(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testCompactReceipts(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testFreezer(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testBloomBits(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testTxLookup(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testWriteBatch(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testBadBlocks(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testFeeLedger(t, m)
+	})
 }
 
 func testCanonicalChain(t *testing.T, m PlaceholderStorage) {
@@ -101,6 +127,19 @@ func testCanonicalChain(t *testing.T, m PlaceholderStorage) {
 			t.Fatal("not match")
 		}
 	}
+
+	// the canonical hash of number 2 is removed, number 1 is kept
+	if err := s.DeleteCanonicalHash(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := s.ReadCanonicalHash(2); ok {
+		t.Fatal("deleted canonical hash found")
+	}
+
+	if _, ok := s.ReadCanonicalHash(1); !ok {
+		t.Fatal("not found")
+	}
 }
 
 func testDifficulty(t *testing.T, m PlaceholderStorage) {
@@ -315,6 +354,11 @@ func testBody(t *testing.T, m PlaceholderStorage) {
 			t.Fatal("tx not correct")
 		}
 	}
+
+	assert.NoError(t, s.DeleteBody(header.Hash))
+
+	_, err = s.ReadBody(header.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
 }
 
 func testReceipts(t *testing.T, m PlaceholderStorage) {
@@ -391,6 +435,11 @@ func testReceipts(t *testing.T, m PlaceholderStorage) {
 	}
 
 	assert.True(t, reflect.DeepEqual(receipts, found))
+
+	assert.NoError(t, s.DeleteReceipts(h.Hash))
+
+	_, err = s.ReadReceipts(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
 }
 
 func testWriteCanonicalHeader(t *testing.T, m PlaceholderStorage) {
@@ -446,10 +495,399 @@ func testWriteCanonicalHeader(t *testing.T, m PlaceholderStorage) {
 	}
 }
 
+func testCompression(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	compressions := []Compression{CompressionNone, CompressionSnappy, CompressionZstd}
+
+	// the entries are written with each compression in turn
+	for i, compression := range compressions {
+		s.SetCompression(compression)
+
+		txn := &types.Transaction{
+			Nonce:    uint64(i),
+			GasPrice: big.NewInt(1),
+			Input:    bytes.Repeat([]byte{0x1}, 1024),
+			V:        big.NewInt(1),
+		}
+		txn.ComputeHash()
+
+		hash := types.BytesToHash([]byte{byte(i + 1)})
+
+		assert.NoError(t, s.WriteBody(hash, &types.Body{Transactions: []*types.Transaction{txn}}))
+		assert.NoError(t, s.WriteReceipts(hash, []*types.Receipt{{CumulativeGasUsed: uint64(i), TxHash: txn.Hash}}))
+	}
+
+	// they are read whatever the current compression
+	for i := range compressions {
+		hash := types.BytesToHash([]byte{byte(i + 1)})
+
+		body, err := s.ReadBody(hash)
+		assert.NoError(t, err)
+		assert.Len(t, body.Transactions, 1)
+		assert.Equal(t, uint64(i), body.Transactions[0].Nonce)
+
+		receipts, err := s.ReadReceipts(hash)
+		assert.NoError(t, err)
+		assert.Len(t, receipts, 1)
+		assert.Equal(t, uint64(i), receipts[0].CumulativeGasUsed)
+		assert.Equal(t, body.Transactions[0].Hash, receipts[0].TxHash)
+	}
+}
+
+func testCompactReceipts(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	s.SetCompression(CompressionSnappy)
+
+	legacyHash, compactHash := types.StringToHash("1"), types.StringToHash("2")
+
+	assert.NoError(t, s.WriteReceipts(legacyHash, newCompactableReceipts()))
+
+	s.SetCompactReceipts(true)
+
+	assert.NoError(t, s.WriteReceipts(compactHash, newCompactableReceipts()))
+
+	// the receipts are read whatever their form
+	for _, hash := range []types.Hash{legacyHash, compactHash} {
+		receipts, err := s.ReadReceipts(hash)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Receipt(newCompactableReceipts()), receipts)
+	}
+}
+
+// newCompactableReceipts returns receipts whose bloom and gas used are the ones recomputed on read
+func newCompactableReceipts() types.Receipts {
+	receipts := types.Receipts{
+		{
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            types.StringToHash("1"),
+		},
+		{
+			CumulativeGasUsed: 71000,
+			GasUsed:           50000,
+			TxHash:            types.StringToHash("2"),
+			ContractAddress:   &types.Address{0x1},
+			Logs: []*types.Log{
+				{
+					Address: types.StringToAddress("2"),
+					Topics:  []types.Hash{types.StringToHash("3"), types.StringToHash("4")},
+					Data:    bytes.Repeat([]byte{0x1}, 1024),
+				},
+				{
+					Address: types.StringToAddress("3"),
+					Topics:  []types.Hash{},
+					Data:    []byte{0x1, 0x2},
+				},
+			},
+		},
+		{
+			Root:              types.StringToHash("5"),
+			CumulativeGasUsed: 71000,
+			TxHash:            types.StringToHash("6"),
+		},
+	}
+
+	receipts[0].SetStatus(types.ReceiptSuccess)
+	receipts[1].SetStatus(types.ReceiptFailed)
+
+	for _, receipt := range receipts {
+		receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
+	}
+
+	return receipts
+}
+
+func testFreezer(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	freezer, err := NewFreezer(t.TempDir())
+	assert.NoError(t, err)
+
+	s.SetFreezer(freezer)
+	s.SetCompression(CompressionSnappy)
+
+	headers := make([]*types.Header, 4)
+
+	for i := range headers {
+		headers[i] = &types.Header{Number: uint64(i), ExtraData: []byte{byte(i)}}
+		headers[i].ComputeHash()
+
+		assert.NoError(t, s.WriteHeader(headers[i]))
+		assert.NoError(t, s.WriteCanonicalHash(headers[i].Number, headers[i].Hash))
+
+		// the body and the receipts of the first block are pruned
+		if i == 0 {
+			continue
+		}
+
+		txn := &types.Transaction{Nonce: uint64(i), GasPrice: big.NewInt(1), V: big.NewInt(1)}
+		txn.ComputeHash()
+
+		assert.NoError(t, s.WriteBody(headers[i].Hash, &types.Body{Transactions: []*types.Transaction{txn}}))
+		assert.NoError(t, s.WriteReceipts(headers[i].Hash, []*types.Receipt{{CumulativeGasUsed: uint64(i)}}))
+	}
+
+	frozen, err := s.FreezeBlocks(3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), frozen)
+	assert.Equal(t, uint64(3), s.Frozen())
+
+	// the frozen blocks are read from the freezer, the others from the key-value storage
+	for i, header := range headers {
+		found, err := s.ReadHeader(header.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, header.Hash, found.Hash)
+
+		body, err := s.ReadBody(header.Hash)
+		receipts, receiptsErr := s.ReadReceipts(header.Hash)
+
+		if i == 0 {
+			assert.ErrorIs(t, err, ErrNotFound)
+			assert.ErrorIs(t, receiptsErr, ErrNotFound)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Len(t, body.Transactions, 1)
+		assert.Equal(t, uint64(i), body.Transactions[0].Nonce)
+
+		assert.NoError(t, receiptsErr)
+		assert.Len(t, receipts, 1)
+		assert.Equal(t, uint64(i), receipts[0].CumulativeGasUsed)
+	}
+
+	// the blocks already frozen are skipped
+	frozen, err = s.FreezeBlocks(3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), frozen)
+}
+
+func testBloomBits(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	// the blocks 10 and 4100 have a log of addr1, the block 20 a log of addr2
+	logs := map[uint64]types.Address{10: addr1, 20: addr2, BloomSectionSize + 4: addr1}
+
+	for n := uint64(0); n < BloomSectionSize+10; n++ {
+		header := &types.Header{Number: n}
+
+		if addr, ok := logs[n]; ok {
+			header.LogsBloom = types.CreateBloom([]*types.Receipt{{Logs: []*types.Log{{Address: addr}}}})
+		}
+
+		header.ComputeHash()
+
+		assert.NoError(t, s.WriteHeader(header))
+		assert.NoError(t, s.WriteCanonicalHash(n, header.Hash))
+	}
+
+	// only the complete sections are indexed
+	indexed, err := IndexBloomSections(s, BloomSectionSize+9, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), indexed)
+	assert.Equal(t, uint64(1), s.ReadBloomSections())
+
+	assert.Equal(t, []uint64{10}, MatchBloomBits(s, 0, BloomSectionSize+9, [][][]byte{{addr1.Bytes()}}))
+	assert.Equal(t, []uint64{20}, MatchBloomBits(s, 0, BloomSectionSize+9, [][][]byte{{addr2.Bytes()}}))
+	assert.Equal(t, []uint64{10, 20}, MatchBloomBits(s, 0, 100, [][][]byte{{addr1.Bytes(), addr2.Bytes()}}))
+	assert.Equal(t, []uint64{}, MatchBloomBits(s, 11, 19, [][][]byte{{addr1.Bytes(), addr2.Bytes()}}))
+	assert.Len(t, MatchBloomBits(s, 5, 14, nil), 10)
+
+	// the discarded blocks are indexed again
+	assert.NoError(t, TruncateBloomSections(s, 100))
+	assert.Equal(t, uint64(0), s.ReadBloomSections())
+	assert.Nil(t, MatchBloomBits(s, 0, 100, nil))
+}
+
+func testTxLookup(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	entry := &TxLookupEntry{BlockHash: hash1, BlockNumber: 10, Index: 2, Positioned: true}
+
+	assert.NoError(t, s.WriteTxLookup(hash2, entry))
+
+	found, ok := s.ReadTxLookup(hash2)
+	assert.True(t, ok)
+	assert.Equal(t, entry, found)
+
+	// the entries written before the position was kept only have the block hash
+	kv, ok := s.(*KeyValueStorage)
+	if ok {
+		ar := &fastrlp.Arena{}
+		assert.NoError(t, kv.write2(TX_LOOKUP_PREFIX, hash1.Bytes(), ar.NewBytes(hash2.Bytes())))
+
+		found, ok = s.ReadTxLookup(hash1)
+		assert.True(t, ok)
+		assert.Equal(t, &TxLookupEntry{BlockHash: hash2}, found)
+	}
+
+	assert.NoError(t, s.DeleteTxLookup(hash2))
+
+	_, ok = s.ReadTxLookup(hash2)
+	assert.False(t, ok)
+
+	assert.Equal(t, uint64(0), s.ReadTxLookupTail())
+	assert.NoError(t, s.WriteTxLookupTail(5))
+	assert.Equal(t, uint64(5), s.ReadTxLookupTail())
+}
+
+func testWriteBatch(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	header := &types.Header{Number: 1, ExtraData: []byte{}}
+	header.ComputeHash()
+
+	assert.NoError(t, s.WriteCanonicalHash(2, hash2))
+
+	batch := s.NewWriteBatch()
+
+	assert.NoError(t, batch.WriteCanonicalHeader(header, big.NewInt(1)))
+	assert.NoError(t, batch.DeleteCanonicalHash(2))
+
+	// the batch reads its own writes
+	head, ok := batch.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, header.Hash, head)
+
+	_, ok = batch.ReadCanonicalHash(2)
+	assert.False(t, ok)
+
+	// the storage doesn't see them until the batch is written
+	_, ok = s.ReadHeadHash()
+	assert.False(t, ok)
+
+	_, ok = s.ReadCanonicalHash(2)
+	assert.True(t, ok)
+
+	assert.NoError(t, batch.Write())
+
+	head, ok = s.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, header.Hash, head)
+
+	number, ok := s.ReadHeadNumber()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), number)
+
+	found, err := s.ReadHeader(header.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, found.Hash)
+
+	_, ok = s.ReadCanonicalHash(2)
+	assert.False(t, ok)
+
+	// closing the batch leaves the storage open
+	assert.NoError(t, batch.Close())
+
+	_, ok = s.ReadHeadHash()
+	assert.True(t, ok)
+}
+
+func testBadBlocks(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	_, err := s.ReadBadBlockHashes()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	header := &types.Header{Number: 5, ExtraData: []byte{}}
+	header.ComputeHash()
+
+	bad := &BadBlock{
+		Block: &types.Block{
+			Header:       header,
+			Transactions: []*types.Transaction{},
+			Uncles:       []*types.Header{},
+		},
+		Reason: "invalid state root",
+		Time:   100,
+	}
+
+	assert.NoError(t, s.WriteBadBlock(bad))
+	assert.NoError(t, s.WriteBadBlockHashes([]types.Hash{header.Hash}))
+
+	found, err := s.ReadBadBlock(header.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, header.Hash, found.Block.Hash())
+	assert.Equal(t, bad.Reason, found.Reason)
+	assert.Equal(t, bad.Time, found.Time)
+
+	hashes, err := s.ReadBadBlockHashes()
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Hash{header.Hash}, hashes)
+
+	assert.NoError(t, s.DeleteBadBlock(header.Hash))
+
+	_, err = s.ReadBadBlock(header.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func testFeeLedger(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	hash := types.StringToHash("1")
+
+	_, err := s.ReadFeeLedgerEntry(hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	entry := &FeeLedgerEntry{
+		Since: 3,
+		Block: &types.Fees{
+			Collected:        big.NewInt(300),
+			Validators:       big.NewInt(200),
+			ContractCreators: big.NewInt(100),
+		},
+		Cumulative: &types.Fees{
+			Collected:        big.NewInt(1000),
+			Validators:       big.NewInt(800),
+			ContractCreators: big.NewInt(0),
+		},
+	}
+
+	assert.NoError(t, s.WriteFeeLedgerEntry(hash, entry))
+
+	found, err := s.ReadFeeLedgerEntry(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, entry.Since, found.Since)
+
+	for _, fees := range [][2]*types.Fees{{entry.Block, found.Block}, {entry.Cumulative, found.Cumulative}} {
+		assert.Equal(t, 0, fees[0].Collected.Cmp(fees[1].Collected))
+		assert.Equal(t, 0, fees[0].Validators.Cmp(fees[1].Validators))
+		assert.Equal(t, 0, fees[0].ContractCreators.Cmp(fees[1].ContractCreators))
+	}
+}
+
 // Storage delegators
 
 type readCanonicalHashDelegate func(uint64) (types.Hash, bool)
 type writeCanonicalHashDelegate func(uint64, types.Hash) error
+type deleteCanonicalHashDelegate func(uint64) error
 type readHeadHashDelegate func() (types.Hash, bool)
 type readHeadNumberDelegate func() (uint64, bool)
 type writeHeadHashDelegate func(types.Hash) error
@@ -463,17 +901,39 @@ type readHeaderDelegate func(types.Hash) (*types.Header, error)
 type writeCanonicalHeaderDelegate func(*types.Header, *big.Int) error
 type writeBodyDelegate func(types.Hash, *types.Body) error
 type readBodyDelegate func(types.Hash) (*types.Body, error)
+type deleteBodyDelegate func(types.Hash) error
 type writeSnapshotDelegate func(types.Hash, []byte) error
 type readSnapshotDelegate func(types.Hash) ([]byte, bool)
 type writeReceiptsDelegate func(types.Hash, []*types.Receipt) error
 type readReceiptsDelegate func(types.Hash) ([]*types.Receipt, error)
-type writeTxLookupDelegate func(types.Hash, types.Hash) error
-type readTxLookupDelegate func(types.Hash) (types.Hash, bool)
+type deleteReceiptsDelegate func(types.Hash) error
+type writeTxLookupDelegate func(types.Hash, *TxLookupEntry) error
+type readTxLookupDelegate func(types.Hash) (*TxLookupEntry, bool)
+type deleteTxLookupDelegate func(types.Hash) error
+type writeTxLookupTailDelegate func(uint64) error
+type readTxLookupTailDelegate func() uint64
+type writeBloomBitsDelegate func(uint, uint64, []byte) error
+type readBloomBitsDelegate func(uint, uint64) ([]byte, bool)
+type writeBloomSectionsDelegate func(uint64) error
+type readBloomSectionsDelegate func() uint64
+type writeBadBlockDelegate func(*BadBlock) error
+type readBadBlockDelegate func(types.Hash) (*BadBlock, error)
+type deleteBadBlockDelegate func(types.Hash) error
+type writeBadBlockHashesDelegate func([]types.Hash) error
+type readBadBlockHashesDelegate func() ([]types.Hash, error)
+type writeFeeLedgerEntryDelegate func(types.Hash, *FeeLedgerEntry) error
+type readFeeLedgerEntryDelegate func(types.Hash) (*FeeLedgerEntry, error)
+type setCompressionDelegate func(Compression)
+type setCompactReceiptsDelegate func(bool)
+type setFreezerDelegate func(*Freezer)
+type frozenDelegate func() uint64
+type freezeBlocksDelegate func(uint64) (uint64, error)
 type closeDelegate func() error
 
 type MockStorage struct {
 	readCanonicalHashFn    readCanonicalHashDelegate
 	writeCanonicalHashFn   writeCanonicalHashDelegate
+	deleteCanonicalHashFn  deleteCanonicalHashDelegate
 	readHeadHashFn         readHeadHashDelegate
 	readHeadNumberFn       readHeadNumberDelegate
 	writeHeadHashFn        writeHeadHashDelegate
@@ -487,12 +947,33 @@ type MockStorage struct {
 	writeCanonicalHeaderFn writeCanonicalHeaderDelegate
 	writeBodyFn            writeBodyDelegate
 	readBodyFn             readBodyDelegate
+	deleteBodyFn           deleteBodyDelegate
 	writeSnapshotFn        writeSnapshotDelegate
 	readSnapshotFn         readSnapshotDelegate
 	writeReceiptsFn        writeReceiptsDelegate
 	readReceiptsFn         readReceiptsDelegate
+	deleteReceiptsFn       deleteReceiptsDelegate
 	writeTxLookupFn        writeTxLookupDelegate
 	readTxLookupFn         readTxLookupDelegate
+	deleteTxLookupFn       deleteTxLookupDelegate
+	writeTxLookupTailFn    writeTxLookupTailDelegate
+	readTxLookupTailFn     readTxLookupTailDelegate
+	writeBloomBitsFn       writeBloomBitsDelegate
+	readBloomBitsFn        readBloomBitsDelegate
+	writeBloomSectionsFn   writeBloomSectionsDelegate
+	readBloomSectionsFn    readBloomSectionsDelegate
+	writeBadBlockFn        writeBadBlockDelegate
+	readBadBlockFn         readBadBlockDelegate
+	deleteBadBlockFn       deleteBadBlockDelegate
+	writeBadBlockHashesFn  writeBadBlockHashesDelegate
+	readBadBlockHashesFn   readBadBlockHashesDelegate
+	writeFeeLedgerEntryFn  writeFeeLedgerEntryDelegate
+	readFeeLedgerEntryFn   readFeeLedgerEntryDelegate
+	setCompressionFn       setCompressionDelegate
+	setCompactReceiptsFn   setCompactReceiptsDelegate
+	setFreezerFn           setFreezerDelegate
+	frozenFn               frozenDelegate
+	freezeBlocksFn         freezeBlocksDelegate
 	closeFn                closeDelegate
 }
 
@@ -524,6 +1005,18 @@ func (m *MockStorage) HookWriteCanonicalHash(fn writeCanonicalHashDelegate) {
 	m.writeCanonicalHashFn = fn
 }
 
+func (m *MockStorage) DeleteCanonicalHash(n uint64) error {
+	if m.deleteCanonicalHashFn != nil {
+		return m.deleteCanonicalHashFn(n)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteCanonicalHash(fn deleteCanonicalHashDelegate) {
+	m.deleteCanonicalHashFn = fn
+}
+
 func (m *MockStorage) ReadHeadHash() (types.Hash, bool) {
 	if m.readHeadHashFn != nil {
 		return m.readHeadHashFn()
@@ -680,6 +1173,18 @@ func (m *MockStorage) HookReadBody(fn readBodyDelegate) {
 	m.readBodyFn = fn
 }
 
+func (m *MockStorage) DeleteBody(hash types.Hash) error {
+	if m.deleteBodyFn != nil {
+		return m.deleteBodyFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteBody(fn deleteBodyDelegate) {
+	m.deleteBodyFn = fn
+}
+
 func (m *MockStorage) WriteSnapshot(hash types.Hash, blob []byte) error {
 	if m.writeSnapshotFn != nil {
 		return m.writeSnapshotFn(hash, blob)
@@ -728,9 +1233,21 @@ func (m *MockStorage) HookReadReceipts(fn readReceiptsDelegate) {
 	m.readReceiptsFn = fn
 }
 
-func (m *MockStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash) error {
+func (m *MockStorage) DeleteReceipts(hash types.Hash) error {
+	if m.deleteReceiptsFn != nil {
+		return m.deleteReceiptsFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteReceipts(fn deleteReceiptsDelegate) {
+	m.deleteReceiptsFn = fn
+}
+
+func (m *MockStorage) WriteTxLookup(hash types.Hash, entry *TxLookupEntry) error {
 	if m.writeTxLookupFn != nil {
-		return m.writeTxLookupFn(hash, blockHash)
+		return m.writeTxLookupFn(hash, entry)
 	}
 
 	return nil
@@ -740,18 +1257,253 @@ func (m *MockStorage) HookWriteTxLookup(fn writeTxLookupDelegate) {
 	m.writeTxLookupFn = fn
 }
 
-func (m *MockStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+func (m *MockStorage) ReadTxLookup(hash types.Hash) (*TxLookupEntry, bool) {
 	if m.readTxLookupFn != nil {
 		return m.readTxLookupFn(hash)
 	}
 
-	return types.Hash{}, true
+	return &TxLookupEntry{}, true
 }
 
 func (m *MockStorage) HookReadTxLookup(fn readTxLookupDelegate) {
 	m.readTxLookupFn = fn
 }
 
+func (m *MockStorage) DeleteTxLookup(hash types.Hash) error {
+	if m.deleteTxLookupFn != nil {
+		return m.deleteTxLookupFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteTxLookup(fn deleteTxLookupDelegate) {
+	m.deleteTxLookupFn = fn
+}
+
+func (m *MockStorage) WriteTxLookupTail(number uint64) error {
+	if m.writeTxLookupTailFn != nil {
+		return m.writeTxLookupTailFn(number)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteTxLookupTail(fn writeTxLookupTailDelegate) {
+	m.writeTxLookupTailFn = fn
+}
+
+func (m *MockStorage) ReadTxLookupTail() uint64 {
+	if m.readTxLookupTailFn != nil {
+		return m.readTxLookupTailFn()
+	}
+
+	return 0
+}
+
+func (m *MockStorage) HookReadTxLookupTail(fn readTxLookupTailDelegate) {
+	m.readTxLookupTailFn = fn
+}
+
+func (m *MockStorage) WriteBloomBits(bit uint, section uint64, bits []byte) error {
+	if m.writeBloomBitsFn != nil {
+		return m.writeBloomBitsFn(bit, section, bits)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomBits(fn writeBloomBitsDelegate) {
+	m.writeBloomBitsFn = fn
+}
+
+func (m *MockStorage) ReadBloomBits(bit uint, section uint64) ([]byte, bool) {
+	if m.readBloomBitsFn != nil {
+		return m.readBloomBitsFn(bit, section)
+	}
+
+	return nil, false
+}
+
+func (m *MockStorage) HookReadBloomBits(fn readBloomBitsDelegate) {
+	m.readBloomBitsFn = fn
+}
+
+func (m *MockStorage) WriteBloomSections(sections uint64) error {
+	if m.writeBloomSectionsFn != nil {
+		return m.writeBloomSectionsFn(sections)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomSections(fn writeBloomSectionsDelegate) {
+	m.writeBloomSectionsFn = fn
+}
+
+func (m *MockStorage) ReadBloomSections() uint64 {
+	if m.readBloomSectionsFn != nil {
+		return m.readBloomSectionsFn()
+	}
+
+	return 0
+}
+
+func (m *MockStorage) HookReadBloomSections(fn readBloomSectionsDelegate) {
+	m.readBloomSectionsFn = fn
+}
+
+func (m *MockStorage) WriteBadBlock(bad *BadBlock) error {
+	if m.writeBadBlockFn != nil {
+		return m.writeBadBlockFn(bad)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBadBlock(fn writeBadBlockDelegate) {
+	m.writeBadBlockFn = fn
+}
+
+func (m *MockStorage) ReadBadBlock(hash types.Hash) (*BadBlock, error) {
+	if m.readBadBlockFn != nil {
+		return m.readBadBlockFn(hash)
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *MockStorage) HookReadBadBlock(fn readBadBlockDelegate) {
+	m.readBadBlockFn = fn
+}
+
+func (m *MockStorage) DeleteBadBlock(hash types.Hash) error {
+	if m.deleteBadBlockFn != nil {
+		return m.deleteBadBlockFn(hash)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookDeleteBadBlock(fn deleteBadBlockDelegate) {
+	m.deleteBadBlockFn = fn
+}
+
+func (m *MockStorage) WriteBadBlockHashes(hashes []types.Hash) error {
+	if m.writeBadBlockHashesFn != nil {
+		return m.writeBadBlockHashesFn(hashes)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBadBlockHashes(fn writeBadBlockHashesDelegate) {
+	m.writeBadBlockHashesFn = fn
+}
+
+func (m *MockStorage) ReadBadBlockHashes() ([]types.Hash, error) {
+	if m.readBadBlockHashesFn != nil {
+		return m.readBadBlockHashesFn()
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *MockStorage) HookReadBadBlockHashes(fn readBadBlockHashesDelegate) {
+	m.readBadBlockHashesFn = fn
+}
+
+func (m *MockStorage) WriteFeeLedgerEntry(hash types.Hash, entry *FeeLedgerEntry) error {
+	if m.writeFeeLedgerEntryFn != nil {
+		return m.writeFeeLedgerEntryFn(hash, entry)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteFeeLedgerEntry(fn writeFeeLedgerEntryDelegate) {
+	m.writeFeeLedgerEntryFn = fn
+}
+
+func (m *MockStorage) ReadFeeLedgerEntry(hash types.Hash) (*FeeLedgerEntry, error) {
+	if m.readFeeLedgerEntryFn != nil {
+		return m.readFeeLedgerEntryFn(hash)
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *MockStorage) HookReadFeeLedgerEntry(fn readFeeLedgerEntryDelegate) {
+	m.readFeeLedgerEntryFn = fn
+}
+
+// mockWriteBatch passes the writes to the hooks of the mock storage right away
+type mockWriteBatch struct {
+	*MockStorage
+}
+
+func (b *mockWriteBatch) Write() error {
+	return nil
+}
+
+func (m *MockStorage) NewWriteBatch() WriteBatch {
+	return &mockWriteBatch{m}
+}
+
+func (m *MockStorage) SetCompression(compression Compression) {
+	if m.setCompressionFn != nil {
+		m.setCompressionFn(compression)
+	}
+}
+
+func (m *MockStorage) HookSetCompression(fn setCompressionDelegate) {
+	m.setCompressionFn = fn
+}
+
+func (m *MockStorage) SetCompactReceipts(compact bool) {
+	if m.setCompactReceiptsFn != nil {
+		m.setCompactReceiptsFn(compact)
+	}
+}
+
+func (m *MockStorage) HookSetCompactReceipts(fn setCompactReceiptsDelegate) {
+	m.setCompactReceiptsFn = fn
+}
+
+func (m *MockStorage) SetFreezer(freezer *Freezer) {
+	if m.setFreezerFn != nil {
+		m.setFreezerFn(freezer)
+	}
+}
+
+func (m *MockStorage) HookSetFreezer(fn setFreezerDelegate) {
+	m.setFreezerFn = fn
+}
+
+func (m *MockStorage) Frozen() uint64 {
+	if m.frozenFn != nil {
+		return m.frozenFn()
+	}
+
+	return 0
+}
+
+func (m *MockStorage) HookFrozen(fn frozenDelegate) {
+	m.frozenFn = fn
+}
+
+func (m *MockStorage) FreezeBlocks(limit uint64) (uint64, error) {
+	if m.freezeBlocksFn != nil {
+		return m.freezeBlocksFn(limit)
+	}
+
+	return 0, nil
+}
+
+func (m *MockStorage) HookFreezeBlocks(fn freezeBlocksDelegate) {
+	m.freezeBlocksFn = fn
+}
+
 func (m *MockStorage) Close() error {
 	if m.closeFn != nil {
 		return m.closeFn()
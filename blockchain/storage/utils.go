@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -52,3 +55,145 @@ func (f *Forks) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	return nil
 }
+
+// BadBlock is a block rejected by the verification or the execution, along with the reason
+type BadBlock struct {
+	Block  *types.Block
+	Reason string
+	// Time is the unix time in seconds the block was rejected at
+	Time uint64
+}
+
+// MarshalRLPTo is a wrapper function for calling the type marshal implementation
+func (b *BadBlock) MarshalRLPTo(dst []byte) []byte {
+	return types.MarshalRLPTo(b.MarshalRLPWith, dst)
+}
+
+// MarshalRLPWith is the actual RLP marshal implementation for the type
+func (b *BadBlock) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	vr := ar.NewArray()
+	vr.Set(b.Block.MarshalRLPWith(ar))
+	vr.Set(ar.NewString(b.Reason))
+	vr.Set(ar.NewUint(b.Time))
+
+	return vr
+}
+
+// UnmarshalRLP is a wrapper function for calling the type unmarshal implementation
+func (b *BadBlock) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(b.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom is the actual RLP unmarshal implementation for the type
+func (b *BadBlock) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) != 3 {
+		return fmt.Errorf("incorrect number of elements to decode bad block, expected 3 but found %d", len(elems))
+	}
+
+	b.Block = &types.Block{}
+	if err := b.Block.UnmarshalRLPFrom(p, elems[0]); err != nil {
+		return err
+	}
+
+	if b.Reason, err = elems[1].GetString(); err != nil {
+		return err
+	}
+
+	if b.Time, err = elems[2].GetUint64(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FeeLedgerEntry is the fee accounting of an executed block: the fees paid by its transactions,
+// and the cumulative fees of the chain up to it. The fees of the blocks written without being
+// executed aren't known, so the cumulative fees are counted from the first block following them
+type FeeLedgerEntry struct {
+	// Since is the number of the first block the cumulative fees are counted from
+	Since uint64
+
+	Block      *types.Fees
+	Cumulative *types.Fees
+}
+
+// MarshalRLPTo is a wrapper function for calling the type marshal implementation
+func (e *FeeLedgerEntry) MarshalRLPTo(dst []byte) []byte {
+	return types.MarshalRLPTo(e.MarshalRLPWith, dst)
+}
+
+// MarshalRLPWith is the actual RLP marshal implementation for the type
+func (e *FeeLedgerEntry) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	vr := ar.NewArray()
+	vr.Set(ar.NewUint(e.Since))
+	vr.Set(marshalFees(ar, e.Block))
+	vr.Set(marshalFees(ar, e.Cumulative))
+
+	return vr
+}
+
+func marshalFees(ar *fastrlp.Arena, fees *types.Fees) *fastrlp.Value {
+	vr := ar.NewArray()
+	vr.Set(ar.NewBigInt(fees.Collected))
+	vr.Set(ar.NewBigInt(fees.Validators))
+	vr.Set(ar.NewBigInt(fees.ContractCreators))
+
+	return vr
+}
+
+// UnmarshalRLP is a wrapper function for calling the type unmarshal implementation
+func (e *FeeLedgerEntry) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(e.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom is the actual RLP unmarshal implementation for the type
+func (e *FeeLedgerEntry) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) != 3 {
+		return fmt.Errorf("incorrect number of elements to decode fee ledger entry, expected 3 but found %d", len(elems))
+	}
+
+	if e.Since, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+
+	if e.Block, err = unmarshalFees(elems[1]); err != nil {
+		return err
+	}
+
+	if e.Cumulative, err = unmarshalFees(elems[2]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func unmarshalFees(v *fastrlp.Value) (*types.Fees, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elems) != 3 {
+		return nil, fmt.Errorf("incorrect number of elements to decode fees, expected 3 but found %d", len(elems))
+	}
+
+	fees := types.NewFees()
+
+	for i, value := range []*big.Int{fees.Collected, fees.Validators, fees.ContractCreators} {
+		if err := elems[i].GetBigInt(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return fees, nil
+}
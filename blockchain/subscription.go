@@ -153,7 +153,8 @@ func (e *Event) AddOldHeader(oldHeader *types.Header) {
 	e.OldChain = append(e.OldChain, header)
 }
 
-// SubscribeEvents returns a blockchain event subscription
+// SubscribeEvents returns a subscription to all the blockchain events.
+// The typed events, of the chosen topics, are received with SubscribeBus
 func (b *Blockchain) SubscribeEvents() Subscription {
 	return b.stream.subscribe()
 }
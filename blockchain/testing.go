@@ -114,13 +114,19 @@ func NewTestBlockchain(t *testing.T, headers []*types.Header) *Blockchain {
 	}
 
 	if headers != nil {
-		if _, err := b.advanceHead(headers[0]); err != nil {
+		if err := b.db.WriteHeader(headers[0]); err != nil {
 			t.Fatal(err)
 		}
 
-		if err := b.WriteHeaders(headers[1:]); err != nil {
+		if _, err := b.advanceHead(headers[0]); err != nil {
 			t.Fatal(err)
 		}
+
+		if len(headers) > 1 {
+			if err := b.WriteHeaders(headers[1:]); err != nil {
+				t.Fatal(err)
+			}
+		}
 	}
 
 	// TODO, find a way to add the snapshot, this will fail until that is fixed.
@@ -128,6 +134,32 @@ func NewTestBlockchain(t *testing.T, headers []*types.Header) *Blockchain {
 	return b
 }
 
+// newTestWrittenBlockchain creates a chain from the first header, and writes the following ones
+// as blocks without transactions. The prepare callback, if set, runs before each block is written
+func newTestWrittenBlockchain(
+	t *testing.T,
+	headers []*types.Header,
+	prepare func(b *Blockchain, header *types.Header),
+) *Blockchain {
+	t.Helper()
+
+	b := NewTestBlockchain(t, headers[:1])
+
+	for _, header := range headers[1:] {
+		b.receiptsCache.Add(header.Hash, []*types.Receipt{})
+
+		if prepare != nil {
+			prepare(b, header)
+		}
+
+		if err := b.WriteBlock(&types.Block{Header: header}, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return b
+}
+
 type TestCallbackType string
 
 const (
@@ -206,10 +238,13 @@ func NewMockBlockchain(
 		executor:  executor,
 		config:    config,
 		stream:    &eventStream{},
+		bus:       NewEventBus(hclog.NewNullLogger()),
 		gpAverage: &gasPriceAverage{
 			price: big.NewInt(0),
 			count: big.NewInt(0),
 		},
+		metrics:      NilMetrics(),
+		futureBlocks: newFutureBlockQueue(),
 	}
 
 	if err := blockchain.initCaches(10); err != nil {
@@ -330,7 +365,15 @@ func newBlockChain(config *chain.Chain, executor Executor) (*Blockchain, error)
 		executor = &mockExecutor{}
 	}
 
-	b, err := NewBlockchain(hclog.NewNullLogger(), "", config, &MockVerifier{}, executor)
+	b, err := NewBlockchain(
+		hclog.NewNullLogger(),
+		"",
+		storage.EngineLevelDB,
+		config,
+		&MockVerifier{},
+		executor,
+		NilMetrics(),
+	)
 	if err != nil {
 		return nil, err
 	}
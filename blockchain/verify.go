@@ -0,0 +1,339 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrParentStateNotFound = errors.New("the state of the parent block isn't available")
+
+	errInvalidVerifyRange = errors.New("invalid range of blocks to verify")
+	errCanonicalHash      = errors.New("canonical hash not found")
+	errHeaderNotFound     = errors.New("header not found")
+	errHeaderHash         = errors.New("the header doesn't hash to its canonical hash")
+	errHeaderNumber       = errors.New("the header number doesn't match its canonical number")
+	errBrokenChain        = errors.New("the parent hash isn't the hash of the previous canonical block")
+	errBodyNotFound       = errors.New("the body is missing while the receipts are kept")
+	errReceiptsNotFound   = errors.New("the receipts are missing while the body is kept")
+	errBrokenTxLookup     = errors.New("the transaction lookup doesn't point to the block")
+)
+
+// BlockReexecutor re-executes the block on the state of its parent, without writing the resulting state,
+// and returns its state root and receipts. It returns ErrParentStateNotFound if the state isn't available
+type BlockReexecutor func(parent *types.Header, block *types.Block) (types.Hash, []*types.Receipt, error)
+
+// ChainVerifyConfig is the configuration of VerifyChain
+type ChainVerifyConfig struct {
+	// From and To are the numbers of the first and the last verified canonical blocks
+	From uint64
+	To   uint64
+
+	// Reexecute re-executes the sampled blocks, none are if nil
+	Reexecute BlockReexecutor
+
+	// ReexecuteInterval is the interval between the re-executed blocks, none are if zero
+	ReexecuteInterval uint64
+
+	// Repair rewrites the broken entries which are recomputed locally,
+	// the transaction lookups and the receipts recovered by re-executing their block
+	Repair bool
+}
+
+// ChainIssue is a broken entry of the chain found by VerifyChain
+type ChainIssue struct {
+	Number   uint64
+	Hash     types.Hash
+	Err      error
+	Repaired bool
+}
+
+// ChainVerifyReport is the outcome of VerifyChain
+type ChainVerifyReport struct {
+	// Verified is the number of verified blocks
+	Verified uint64
+
+	// Pruned is the number of verified blocks whose body and receipts are pruned
+	Pruned uint64
+
+	// Reexecuted is the number of re-executed blocks, and ReexecuteSkipped the number
+	// of sampled blocks which weren't re-executed as the state of their parent isn't available
+	Reexecuted       uint64
+	ReexecuteSkipped uint64
+
+	Issues []*ChainIssue
+
+	// ResyncFrom is the number of the first block with an issue which isn't repaired locally,
+	// nil if there is none. The node recovers by syncing the blocks from it again
+	ResyncFrom *uint64
+}
+
+func (r *ChainVerifyReport) addIssue(number uint64, hash types.Hash, err error, repaired bool) {
+	r.Issues = append(r.Issues, &ChainIssue{
+		Number:   number,
+		Hash:     hash,
+		Err:      err,
+		Repaired: repaired,
+	})
+
+	if !repaired && r.ResyncFrom == nil {
+		r.ResyncFrom = &number
+	}
+}
+
+// VerifyChain checks the canonical blocks of the storage of a stopped node in the given range:
+// the continuity of the header chain, the roots of the bodies and the receipts, and the transaction
+// lookups, re-executing the sampled blocks if configured. The broken transaction lookups and receipts are
+// rewritten if repairing, the other issues are reported for the blocks to be synced again
+func VerifyChain(db storage.Storage, config *ChainVerifyConfig) (*ChainVerifyReport, error) {
+	head, ok := db.ReadHeadNumber()
+	if !ok {
+		return nil, errNoHead
+	}
+
+	if config.From > config.To || config.To > head {
+		return nil, fmt.Errorf("%w: %d to %d, the head is %d", errInvalidVerifyRange, config.From, config.To, head)
+	}
+
+	report := &ChainVerifyReport{}
+	txLookupTail := db.ReadTxLookupTail()
+
+	// the hash of the previous canonical block the parent hash of the header is checked against
+	var prevHash *types.Hash
+
+	if config.From > 0 {
+		if hash, ok := db.ReadCanonicalHash(config.From - 1); ok {
+			prevHash = &hash
+		}
+	}
+
+	for number := config.From; number <= config.To; number++ {
+		hash, ok := db.ReadCanonicalHash(number)
+		if !ok {
+			report.addIssue(number, types.ZeroHash, errCanonicalHash, false)
+
+			prevHash = nil
+
+			continue
+		}
+
+		report.Verified++
+
+		header, err := verifyCanonicalHeader(db, number, hash, prevHash)
+
+		prevHash = &hash
+
+		if err != nil {
+			report.addIssue(number, hash, err, false)
+
+			continue
+		}
+
+		// the genesis has no stored body
+		if number == 0 {
+			continue
+		}
+
+		if err := verifyCanonicalBlock(db, header, config, report, number >= txLookupTail); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// verifyCanonicalHeader reads the canonical header, and checks its hash, its number and its parent hash
+func verifyCanonicalHeader(
+	db storage.Storage,
+	number uint64,
+	hash types.Hash,
+	prevHash *types.Hash,
+) (*types.Header, error) {
+	header, err := db.ReadHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errHeaderNotFound, err)
+	}
+
+	if header.Hash != hash {
+		return nil, errHeaderHash
+	}
+
+	if header.Number != number {
+		return nil, errHeaderNumber
+	}
+
+	if prevHash != nil && header.ParentHash != *prevHash {
+		return nil, errBrokenChain
+	}
+
+	return header, nil
+}
+
+// verifyCanonicalBlock checks the body, the receipts and the transaction lookups of the block with
+// a valid header, and re-executes it if sampled or if its receipts are broken and repaired.
+// It returns an error only if a repair fails to be written
+func verifyCanonicalBlock(
+	db storage.Storage,
+	header *types.Header,
+	config *ChainVerifyConfig,
+	report *ChainVerifyReport,
+	hasTxLookups bool,
+) error {
+	body, bodyErr := db.ReadBody(header.Hash)
+	receipts, receiptsErr := db.ReadReceipts(header.Hash)
+
+	bodyPruned := errors.Is(bodyErr, storage.ErrNotFound)
+	receiptsPruned := errors.Is(receiptsErr, storage.ErrNotFound)
+
+	if bodyPruned && receiptsPruned {
+		report.Pruned++
+
+		return nil
+	}
+
+	if bodyPruned {
+		report.addIssue(header.Number, header.Hash, errBodyNotFound, false)
+
+		return nil
+	}
+
+	if bodyErr != nil {
+		report.addIssue(header.Number, header.Hash, bodyErr, false)
+
+		return nil
+	}
+
+	block := &types.Block{
+		Header:       header,
+		Transactions: body.Transactions,
+		Uncles:       body.Uncles,
+	}
+
+	if err := verifyBodyRoots(block); err != nil {
+		report.addIssue(header.Number, header.Hash, err, false)
+
+		return nil
+	}
+
+	if hasTxLookups {
+		if err := verifyTxLookups(db, block, config.Repair, report); err != nil {
+			return err
+		}
+	}
+
+	receiptsErr = verifyStoredReceipts(block, receipts, receiptsErr, receiptsPruned)
+
+	sampled := config.Reexecute != nil && config.ReexecuteInterval > 0 &&
+		header.Number%config.ReexecuteInterval == 0
+
+	// the broken receipts are recovered by re-executing the block
+	if !sampled && (receiptsErr == nil || !config.Repair || config.Reexecute == nil) {
+		if receiptsErr != nil {
+			report.addIssue(header.Number, header.Hash, receiptsErr, false)
+		}
+
+		return nil
+	}
+
+	return reexecuteCanonicalBlock(db, block, receiptsErr, config, report)
+}
+
+// verifyStoredReceipts returns the issue of the stored receipts of the block, if any
+func verifyStoredReceipts(block *types.Block, receipts []*types.Receipt, readErr error, pruned bool) error {
+	switch {
+	case pruned:
+		return errReceiptsNotFound
+	case readErr != nil:
+		return readErr
+	default:
+		return verifyReceiptsRoot(block, receipts)
+	}
+}
+
+// verifyTxLookups checks that the transaction lookups point to the block, rewriting the broken ones if repairing
+func verifyTxLookups(db storage.Storage, block *types.Block, repair bool, report *ChainVerifyReport) error {
+	for i, txn := range block.Transactions {
+		entry, ok := db.ReadTxLookup(txn.Hash)
+		if ok && entry.BlockHash == block.Hash() && (!entry.Positioned || entry.Index == uint64(i)) {
+			continue
+		}
+
+		if repair {
+			if err := db.WriteTxLookup(txn.Hash, &storage.TxLookupEntry{
+				BlockHash:   block.Hash(),
+				BlockNumber: block.Number(),
+				Index:       uint64(i),
+				Positioned:  true,
+			}); err != nil {
+				return fmt.Errorf("failed to repair the transaction lookup %s: %w", txn.Hash, err)
+			}
+		}
+
+		report.addIssue(block.Number(), block.Hash(), fmt.Errorf("%w: transaction %s", errBrokenTxLookup, txn.Hash), repair)
+	}
+
+	return nil
+}
+
+// reexecuteCanonicalBlock re-executes the block and checks its state root and receipts root.
+// The broken stored receipts are rewritten with the re-executed ones if repairing and they match the header
+func reexecuteCanonicalBlock(
+	db storage.Storage,
+	block *types.Block,
+	receiptsErr error,
+	config *ChainVerifyConfig,
+	report *ChainVerifyReport,
+) error {
+	header := block.Header
+
+	parent, err := db.ReadHeader(header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("failed to read the parent of block %d: %w", header.Number, err)
+	}
+
+	root, receipts, err := config.Reexecute(parent, block)
+
+	switch {
+	case errors.Is(err, ErrParentStateNotFound):
+		report.ReexecuteSkipped++
+
+		if receiptsErr != nil {
+			report.addIssue(header.Number, header.Hash, receiptsErr, false)
+		}
+
+		return nil
+	case err != nil:
+		report.addIssue(header.Number, header.Hash, fmt.Errorf("failed to re-execute: %w", err), false)
+
+		return nil
+	}
+
+	report.Reexecuted++
+
+	if root != header.StateRoot {
+		report.addIssue(header.Number, header.Hash, ErrInvalidStateRoot, false)
+	}
+
+	reexecutedErr := verifyReceiptsRoot(block, receipts)
+	if reexecutedErr != nil {
+		report.addIssue(header.Number, header.Hash, fmt.Errorf("re-executed: %w", reexecutedErr), false)
+	}
+
+	if receiptsErr == nil {
+		return nil
+	}
+
+	repaired := config.Repair && reexecutedErr == nil
+	if repaired {
+		if err := db.WriteReceipts(header.Hash, receipts); err != nil {
+			return fmt.Errorf("failed to repair the receipts of block %d: %w", header.Number, err)
+		}
+	}
+
+	report.addIssue(header.Number, header.Hash, receiptsErr, repaired)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVerifiedChain(t *testing.T, n int) (*Blockchain, []*types.Header) {
+	t.Helper()
+
+	headers := NewTestHeaders(n)
+
+	return newTestWrittenBlockchain(t, headers, nil), headers
+}
+
+func TestVerifyChain(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestVerifiedChain(t, 5)
+
+	report, err := VerifyChain(b.db, &ChainVerifyConfig{From: 0, To: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), report.Verified)
+	assert.Empty(t, report.Issues)
+	assert.Nil(t, report.ResyncFrom)
+
+	// the range has to end at the head at most
+	_, err = VerifyChain(b.db, &ChainVerifyConfig{From: 0, To: 5})
+	assert.ErrorIs(t, err, errInvalidVerifyRange)
+}
+
+func TestVerifyChain_BrokenEntries(t *testing.T) {
+	t.Parallel()
+
+	b, headers := newTestVerifiedChain(t, 6)
+
+	// receipts not matching the block
+	assert.NoError(t, b.db.WriteReceipts(headers[2].Hash, []*types.Receipt{{}}))
+
+	// a canonical hash pointing to a header of another chain
+	assert.NoError(t, b.db.WriteCanonicalHash(4, headers[1].Hash))
+
+	report, err := VerifyChain(b.db, &ChainVerifyConfig{From: 1, To: 5})
+	assert.NoError(t, err)
+	assert.Len(t, report.Issues, 3)
+
+	assert.Equal(t, uint64(2), report.Issues[0].Number)
+	assert.ErrorIs(t, report.Issues[0].Err, ErrInvalidReceiptsSize)
+
+	assert.Equal(t, uint64(4), report.Issues[1].Number)
+	assert.ErrorIs(t, report.Issues[1].Err, errHeaderNumber)
+
+	// the next block doesn't follow the broken canonical hash
+	assert.Equal(t, uint64(5), report.Issues[2].Number)
+	assert.ErrorIs(t, report.Issues[2].Err, errBrokenChain)
+
+	assert.Equal(t, uint64(2), *report.ResyncFrom)
+}
+
+func TestVerifyChain_Reexecute(t *testing.T) {
+	t.Parallel()
+
+	b, headers := newTestVerifiedChain(t, 5)
+
+	// receipts not matching the block
+	assert.NoError(t, b.db.WriteReceipts(headers[3].Hash, []*types.Receipt{{}}))
+
+	reexecuted := []uint64{}
+	reexecute := func(parent *types.Header, block *types.Block) (types.Hash, []*types.Receipt, error) {
+		// the state of the third block isn't kept
+		if parent.Number == 3 {
+			return types.ZeroHash, nil, ErrParentStateNotFound
+		}
+
+		reexecuted = append(reexecuted, block.Number())
+
+		return block.Header.StateRoot, []*types.Receipt{}, nil
+	}
+
+	report, err := VerifyChain(b.db, &ChainVerifyConfig{
+		From:              0,
+		To:                4,
+		Reexecute:         reexecute,
+		ReexecuteInterval: 2,
+		Repair:            true,
+	})
+	assert.NoError(t, err)
+
+	// the sampled blocks and the block with broken receipts are re-executed,
+	// unless the state of their parent isn't kept
+	assert.Equal(t, []uint64{2, 3}, reexecuted)
+	assert.Equal(t, uint64(2), report.Reexecuted)
+	assert.Equal(t, uint64(1), report.ReexecuteSkipped)
+
+	assert.Len(t, report.Issues, 1)
+	assert.Equal(t, uint64(3), report.Issues[0].Number)
+	assert.True(t, report.Issues[0].Repaired)
+	assert.Nil(t, report.ResyncFrom)
+
+	receipts, err := b.db.ReadReceipts(headers[3].Hash)
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+
+	// a failed re-execution is reported
+	report, err = VerifyChain(b.db, &ChainVerifyConfig{
+		From: 1,
+		To:   1,
+		Reexecute: func(*types.Header, *types.Block) (types.Hash, []*types.Receipt, error) {
+			return types.ZeroHash, nil, errors.New("failed")
+		},
+		ReexecuteInterval: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Issues, 1)
+	assert.Equal(t, uint64(1), *report.ResyncFrom)
+}
@@ -41,6 +41,9 @@ type Genesis struct {
 	Coinbase   types.Address                     `json:"coinbase"`
 	Alloc      map[types.Address]*GenesisAccount `json:"alloc,omitempty"`
 
+	// AllocSource streams the allocations instead of Alloc, when they are too many to be held in memory
+	AllocSource GenesisAllocSource `json:"-"`
+
 	// Override
 	StateRoot types.Hash
 
@@ -0,0 +1,203 @@
+package chain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// GenesisAllocSource streams the genesis allocations, so that a genesis with millions of allocations
+// (e.g. the airdrop of a token) isn't held in memory. The allocations are streamed in the file order
+type GenesisAllocSource interface {
+	// Len returns the number of allocations
+	Len() int
+
+	// ForEach calls the function with the allocations one by one, until it returns an error
+	ForEach(fn func(addr types.Address, account *GenesisAccount) error) error
+}
+
+// genesisAllocFile streams the genesis allocations from a chain configuration file
+type genesisAllocFile struct {
+	path  string
+	count int
+}
+
+func (f *genesisAllocFile) Len() int {
+	return f.count
+}
+
+func (f *genesisAllocFile) ForEach(fn func(addr types.Address, account *GenesisAccount) error) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, _, err = decodeChainStream(bufio.NewReader(file), fn)
+
+	return err
+}
+
+// ImportStreamingAlloc imports a chain like Import, except that the genesis allocations of a chain file
+// aren't decoded in the genesis, they are streamed from the file by its AllocSource instead
+func ImportStreamingAlloc(chain string) (*Chain, error) {
+	c, err := ImportFromName(chain)
+	if err == nil {
+		return c, nil
+	}
+
+	return ImportFromFileStreamingAlloc(chain)
+}
+
+// ImportFromFileStreamingAlloc imports a chain from a filepath, with its genesis allocations streamed from the file
+func ImportFromFileStreamingAlloc(filename string) (*Chain, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	// the allocations are only counted, and checked to be decodable
+	chain, count, err := decodeChainStream(bufio.NewReader(file), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if engines := chain.Params.Engine; len(engines) != 1 {
+		return nil, fmt.Errorf("expected one consensus engine but found %d", len(engines))
+	}
+
+	chain.Genesis.AllocSource = &genesisAllocFile{
+		path:  filename,
+		count: count,
+	}
+
+	return chain, nil
+}
+
+// decodeChainStream decodes the chain configuration without its genesis allocations, which are decoded
+// one at a time and passed to the given function if any. It returns the number of allocations
+func decodeChainStream(r io.Reader, onAccount func(types.Address, *GenesisAccount) error) (*Chain, int, error) {
+	dec := json.NewDecoder(r)
+
+	var (
+		fields = map[string]json.RawMessage{}
+		count  = 0
+	)
+
+	err := decodeObject(dec, func(key string) error {
+		if !strings.EqualFold(key, "genesis") {
+			return decodeRaw(dec, fields, key)
+		}
+
+		genesisFields := map[string]json.RawMessage{}
+
+		if err := decodeObject(dec, func(key string) error {
+			if !strings.EqualFold(key, "alloc") {
+				return decodeRaw(dec, genesisFields, key)
+			}
+
+			return decodeObject(dec, func(key string) error {
+				account := &GenesisAccount{}
+				if err := dec.Decode(account); err != nil {
+					return fmt.Errorf("alloc %s: %w", key, err)
+				}
+
+				count++
+
+				if onAccount == nil {
+					return nil
+				}
+
+				return onAccount(types.StringToAddress(key), account)
+			})
+		}); err != nil {
+			return err
+		}
+
+		genesis, err := json.Marshal(genesisFields)
+		if err != nil {
+			return err
+		}
+
+		fields[key] = genesis
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var chain *Chain
+	if err := json.Unmarshal(data, &chain); err != nil {
+		return nil, 0, err
+	}
+
+	if chain == nil || chain.Genesis == nil || chain.Params == nil {
+		return nil, 0, fmt.Errorf("chain configuration without genesis or params")
+	}
+
+	return chain, count, nil
+}
+
+// decodeObject decodes a JSON object member by member, the given function decoding the value of each
+// member from the decoder. A null value is decoded as an empty object
+func decodeObject(dec *json.Decoder, onMember func(key string) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object but found %v", token)
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected an object key but found %v", token)
+		}
+
+		if err := onMember(key); err != nil {
+			return err
+		}
+	}
+
+	// the closing delimiter
+	_, err = dec.Token()
+
+	return err
+}
+
+// decodeRaw decodes the next value as is into the fields
+func decodeRaw(dec *json.Decoder, fields map[string]json.RawMessage, key string) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	fields[key] = raw
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package chain
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+func TestImportStreamingAlloc(t *testing.T) {
+	t.Parallel()
+
+	// the streamed allocations match the ones decoded in memory
+	files, err := ioutil.ReadDir("./chains")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range files {
+		path := filepath.Join("./chains", f.Name())
+
+		expected, err := ImportFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c, err := ImportFromFileStreamingAlloc(path)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", path, err)
+		}
+
+		if c.Genesis.Alloc != nil || c.Genesis.AllocSource == nil {
+			t.Fatalf("Expected the allocations of %s to be streamed", path)
+		}
+
+		if c.Genesis.AllocSource.Len() != len(expected.Genesis.Alloc) {
+			t.Fatalf("Expected %d allocations in %s but found %d",
+				len(expected.Genesis.Alloc), path, c.Genesis.AllocSource.Len())
+		}
+
+		alloc := map[types.Address]*GenesisAccount{}
+		if err := c.Genesis.AllocSource.ForEach(func(addr types.Address, account *GenesisAccount) error {
+			alloc[addr] = account
+
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(expected.Genesis.Alloc) == 0 {
+			expected.Genesis.Alloc = map[types.Address]*GenesisAccount{}
+		}
+
+		if !reflect.DeepEqual(alloc, expected.Genesis.Alloc) {
+			t.Fatalf("Streamed allocations of %s don't match", path)
+		}
+
+		// the rest of the configuration is decoded as by the import
+		c.Genesis.AllocSource = nil
+		expected.Genesis.Alloc = nil
+
+		if !reflect.DeepEqual(c, expected) {
+			t.Fatalf("Streamed configuration of %s doesn't match", path)
+		}
+	}
+}
+
+func TestGenesisAllocFile_ForEachError(t *testing.T) {
+	t.Parallel()
+
+	c, err := ImportFromFileStreamingAlloc("./chains/goerli.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	err = c.Genesis.AllocSource.ForEach(func(types.Address, *GenesisAccount) error {
+		calls++
+
+		return errStop
+	})
+
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Fatalf("Expected the streaming to stop at the first error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestImportFromFileStreamingAlloc_InvalidAlloc(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	content := `{
+		"params": {"engine": {"pow": {}}},
+		"genesis": {
+			"alloc": {
+				"0x0000000000000000000000000000000000000001": {"balance": "0xzz"}
+			}
+		}
+	}`
+
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ImportFromFileStreamingAlloc(path); err == nil {
+		t.Fatal("Expected an error for the invalid allocation")
+	}
+}
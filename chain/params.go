@@ -10,6 +10,10 @@ type Params struct {
 	ChainID        int                    `json:"chainID"`
 	Engine         map[string]interface{} `json:"engine"`
 	BlockGasTarget uint64                 `json:"blockGasTarget"`
+
+	// BlockGasElastic makes the gas limit follow the usage of the blocks,
+	// between half the block gas target and the block gas target
+	BlockGasElastic bool `json:"blockGasElastic,omitempty"`
 }
 
 func (p *Params) GetEngine() string {
@@ -31,6 +35,8 @@ type Forks struct {
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+	EIP1153        *Fork `json:"EIP1153,omitempty"`
+	London         *Fork `json:"london,omitempty"`
 }
 
 func (f *Forks) active(ff *Fork, block uint64) bool {
@@ -69,6 +75,14 @@ func (f *Forks) IsEIP155(block uint64) bool {
 	return f.active(f.EIP155, block)
 }
 
+func (f *Forks) IsEIP1153(block uint64) bool {
+	return f.active(f.EIP1153, block)
+}
+
+func (f *Forks) IsLondon(block uint64) bool {
+	return f.active(f.London, block)
+}
+
 func (f *Forks) At(block uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
@@ -79,9 +93,23 @@ func (f *Forks) At(block uint64) ForksInTime {
 		EIP150:         f.active(f.EIP150, block),
 		EIP158:         f.active(f.EIP158, block),
 		EIP155:         f.active(f.EIP155, block),
+		EIP1153:        f.active(f.EIP1153, block),
+		London:         f.active(f.London, block),
 	}
 }
 
+// The EIP-1559 base fee parameters, applying from the London fork
+const (
+	// InitialBaseFee is the base fee of the London fork block
+	InitialBaseFee = 1000000000
+
+	// BaseFeeChangeDenom bounds the change of the base fee from a block to the next
+	BaseFeeChangeDenom = 8
+
+	// ElasticityMultiplier bounds the gas limit of a block to the gas target times the multiplier
+	ElasticityMultiplier = 2
+)
+
 type Fork uint64
 
 func NewFork(n uint64) *Fork {
@@ -106,7 +134,9 @@ type ForksInTime struct {
 	Istanbul,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	EIP1153,
+	London bool
 }
 
 var AllForksEnabled = &Forks{
@@ -118,4 +148,5 @@ var AllForksEnabled = &Forks{
 	Constantinople: NewFork(0),
 	Petersburg:     NewFork(0),
 	Istanbul:       NewFork(0),
+	EIP1153:        NewFork(0),
 }
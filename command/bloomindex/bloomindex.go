@@ -0,0 +1,43 @@
+package bloomindex
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	bloomIndexCmd := &cobra.Command{
+		Use: "bloom-index",
+		Short: "Builds the bloom bits index of the logs in the data directory of a stopped node, " +
+			"for the blocks written before the index was maintained",
+		Run: runCommand,
+	}
+
+	setFlags(bloomIndexCmd)
+	helper.SetRequiredFlags(bloomIndexCmd, params.getRequiredFlags())
+
+	return bloomIndexCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.index(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,60 @@
+package bloomindex
+
+import (
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag = "data-dir"
+)
+
+const (
+	blockchainFolder = "blockchain"
+)
+
+var (
+	params = &bloomIndexParams{}
+)
+
+type bloomIndexParams struct {
+	dataDir string
+
+	sections uint64
+	blocks   uint64
+}
+
+func (p *bloomIndexParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+	}
+}
+
+// index builds the sections of the bloom bits index not built yet.
+// The headers of the frozen blocks are read from the freezer
+func (p *bloomIndexParams) index() error {
+	db, err := blockchain.OpenExistingStorage(filepath.Join(p.dataDir, blockchainFolder), hclog.NewNullLogger())
+	if err != nil {
+		return err
+	}
+
+	p.sections, err = blockchain.IndexBloomBits(db)
+	p.blocks = db.ReadBloomSections() * storage.BloomSectionSize
+
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (p *bloomIndexParams) getResult() command.CommandResult {
+	return &BloomIndexResult{
+		Sections: p.sections,
+		Indexed:  p.blocks,
+	}
+}
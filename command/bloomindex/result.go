@@ -0,0 +1,27 @@
+package bloomindex
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type BloomIndexResult struct {
+	Sections uint64 `json:"sections"`
+	Indexed  uint64 `json:"indexed_blocks"`
+}
+
+func (r *BloomIndexResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[BLOOM INDEX]\n")
+	buffer.WriteString("Built the bloom bits index successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Sections built|%d", r.Sections),
+		fmt.Sprintf("Indexed blocks|%d", r.Indexed),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,65 @@
+package badblocks
+
+import (
+	"encoding/json"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "bad-blocks",
+		Short: "Dumps the blocks rejected by the verification or the execution, the latest first, " +
+			"along with the reason. The JSON output includes the blocks and their RLP encoding",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+}
+
+func runPreRun(cmd *cobra.Command, _ []string) error {
+	_, err := helper.ParseJSONRPCAddress(helper.GetJSONRPCAddress(cmd))
+
+	return err
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := jsonrpc.NewClient(helper.GetJSONRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer client.Close()
+
+	result := BadBlocksResult{}
+	if err := client.Call("debug_getBadBlocks", &result); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	for _, bad := range result {
+		header := struct {
+			Number ethgo.ArgUint64 `json:"number"`
+		}{}
+
+		if err := json.Unmarshal(bad.Block, &header); err != nil {
+			outputter.SetError(err)
+
+			return
+		}
+
+		bad.Number = header.Number
+	}
+
+	outputter.SetCommandResult(result)
+}
@@ -0,0 +1,50 @@
+package badblocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/umbracle/ethgo"
+)
+
+type BadBlock struct {
+	Hash   ethgo.Hash      `json:"hash"`
+	Number ethgo.ArgUint64 `json:"number"`
+	Reason string          `json:"reason"`
+	Time   ethgo.ArgUint64 `json:"time"`
+	Block  json.RawMessage `json:"block"`
+	RLP    string          `json:"rlp"`
+}
+
+type BadBlocksResult []*BadBlock
+
+func (r BadBlocksResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[BAD BLOCKS]\n")
+
+	if len(r) == 0 {
+		buffer.WriteString("No bad blocks\n")
+
+		return buffer.String()
+	}
+
+	for i, bad := range r {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Number|%d", bad.Number),
+			fmt.Sprintf("Hash|%s", bad.Hash),
+			fmt.Sprintf("Rejected At|%s", time.Unix(int64(bad.Time), 0).UTC().Format(time.RFC3339)),
+			fmt.Sprintf("Reason|%s", bad.Reason),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,42 @@
+package chain
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/chain/badblocks"
+	chainexport "github.com/0xPolygon/polygon-edge/command/chain/export"
+	"github.com/0xPolygon/polygon-edge/command/chain/fees"
+	chainimport "github.com/0xPolygon/polygon-edge/command/chain/import"
+	"github.com/0xPolygon/polygon-edge/command/chain/stats"
+	"github.com/0xPolygon/polygon-edge/command/chain/verify"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	chainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Top level command for querying, exporting, importing and verifying the chain. Only accepts subcommands.",
+	}
+
+	helper.RegisterJSONRPCFlag(chainCmd)
+
+	registerSubcommands(chainCmd)
+
+	return chainCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// chain stats
+		stats.GetCommand(),
+		// chain fees
+		fees.GetCommand(),
+		// chain bad-blocks
+		badblocks.GetCommand(),
+		// chain export
+		chainexport.GetCommand(),
+		// chain import
+		chainimport.GetCommand(),
+		// chain verify
+		verify.GetCommand(),
+	)
+}
@@ -0,0 +1,86 @@
+package chainexport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use: "export",
+		Short: "Exports the canonical blocks of the data directory of a stopped node to a file, optionally " +
+			"with their receipts and the state of the last block. The file can be restored by a node with " +
+			"the restore flag, or imported offline with the chain import command if it has the receipts",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(exportCmd)
+	helper.SetRequiredFlags(exportCmd, params.getRequiredFlags())
+
+	return exportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.out,
+		outFlag,
+		"",
+		"the path of the exported file, which must not exist",
+	)
+
+	cmd.Flags().StringVar(
+		&params.fromRaw,
+		fromFlag,
+		"0",
+		"the number of the first exported block",
+	)
+
+	cmd.Flags().StringVar(
+		&params.toRaw,
+		toFlag,
+		"",
+		"the number of the last exported block, the head if not set",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.receipts,
+		receiptsFlag,
+		false,
+		"export the receipts of the blocks, for the file to be imported offline",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.state,
+		stateFlag,
+		false,
+		"export the state of the last block with the receipts, so that the importing node "+
+			"doesn't execute the blocks",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.export(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,181 @@
+package chainexport
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/command"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag  = "data-dir"
+	outFlag      = "out"
+	fromFlag     = "from"
+	toFlag       = "to"
+	receiptsFlag = "receipts"
+	stateFlag    = "state"
+)
+
+const (
+	blockchainFolder = "blockchain"
+	stateFolder      = "trie"
+)
+
+var (
+	params = &exportParams{}
+)
+
+var (
+	errDecodeRange   = errors.New("unable to decode range value")
+	errInvalidRange  = errors.New(`invalid "to" value; must be >= "from"`)
+	errBeyondHead    = errors.New(`invalid "to" value; must not be beyond the head`)
+	errStateReceipts = errors.New("the state is only exported with the receipts")
+)
+
+type exportParams struct {
+	dataDir  string
+	out      string
+	receipts bool
+	state    bool
+
+	fromRaw string
+	toRaw   string
+
+	from uint64
+	to   *uint64
+
+	stats *archive.ChainArchiveStats
+}
+
+func (p *exportParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		outFlag,
+	}
+}
+
+func (p *exportParams) validateFlags() error {
+	var parseErr error
+
+	if p.from, parseErr = types.ParseUint64orHex(&p.fromRaw); parseErr != nil {
+		return errDecodeRange
+	}
+
+	if p.toRaw != "" {
+		var parsedTo uint64
+
+		if parsedTo, parseErr = types.ParseUint64orHex(&p.toRaw); parseErr != nil {
+			return errDecodeRange
+		}
+
+		if p.from > parsedTo {
+			return errInvalidRange
+		}
+
+		p.to = &parsedTo
+	}
+
+	// the archive is imported with its receipts, the node executes the blocks of the others
+	if p.state && !p.receipts {
+		return errStateReceipts
+	}
+
+	return nil
+}
+
+// export writes the blocks of the data directory of a stopped node to the out file,
+// which is removed if the export fails
+func (p *exportParams) export() error {
+	logger := hclog.NewNullLogger()
+
+	db, err := blockchain.OpenExistingStorage(filepath.Join(p.dataDir, blockchainFolder), logger)
+	if err != nil {
+		return err
+	}
+
+	var st itrie.Storage
+
+	if p.state {
+		if st, err = itrie.NewLevelDBStorage(filepath.Join(p.dataDir, stateFolder), logger); err != nil {
+			_ = db.Close()
+
+			return err
+		}
+	}
+
+	err = p.exportStorages(db, st)
+
+	if st != nil {
+		if closeErr := st.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (p *exportParams) exportStorages(db storage.Storage, st itrie.Storage) error {
+	head, ok := db.ReadHeadNumber()
+	if !ok {
+		return errors.New("no head found in the data directory")
+	}
+
+	if p.to == nil {
+		p.to = &head
+	}
+
+	if *p.to > head {
+		return errBeyondHead
+	}
+
+	if p.from > *p.to {
+		return errInvalidRange
+	}
+
+	// always create new file, throw error if the file exists
+	fs, err := os.OpenFile(p.out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(fs)
+
+	p.stats, err = archive.ExportChain(db, st, writer, p.from, *p.to, p.receipts, p.state)
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := fs.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(p.out)
+	}
+
+	return err
+}
+
+func (p *exportParams) getResult() command.CommandResult {
+	return &ChainExportResult{
+		Out:        p.out,
+		From:       p.from,
+		To:         *p.to,
+		Blocks:     p.stats.Blocks,
+		Receipts:   p.receipts,
+		StateNodes: p.stats.StateNodes,
+		Code:       p.stats.Code,
+	}
+}
@@ -0,0 +1,36 @@
+package chainexport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ChainExportResult struct {
+	Out        string `json:"out"`
+	From       uint64 `json:"from"`
+	To         uint64 `json:"to"`
+	Blocks     uint64 `json:"blocks"`
+	Receipts   bool   `json:"receipts"`
+	StateNodes uint64 `json:"stateNodes"`
+	Code       uint64 `json:"code"`
+}
+
+func (r *ChainExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN EXPORT]\n")
+	buffer.WriteString("Exported the chain successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.Out),
+		fmt.Sprintf("Blocks|%d - %d", r.From, r.To),
+		fmt.Sprintf("Exported blocks|%d", r.Blocks),
+		fmt.Sprintf("Receipts|%t", r.Receipts),
+		fmt.Sprintf("State nodes|%d", r.StateNodes),
+		fmt.Sprintf("Contract code|%d", r.Code),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
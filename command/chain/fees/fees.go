@@ -0,0 +1,112 @@
+package fees
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+const (
+	fromFlag = "from"
+	toFlag   = "to"
+)
+
+const latestBlock = "latest"
+
+var (
+	params = &feesParams{}
+)
+
+type feesParams struct {
+	from  uint64
+	toRaw string
+}
+
+func GetCommand() *cobra.Command {
+	feesCmd := &cobra.Command{
+		Use: "fees",
+		Short: "Returns the fees paid in a range of blocks: the fees collected from the senders, the ones credited " +
+			"to the validators and to the creators of the called contracts, and the ones burned. The fees are only " +
+			"tracked for the blocks executed by the node",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(feesCmd)
+
+	return feesCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&params.from,
+		fromFlag,
+		0,
+		"the number of the first block of the range",
+	)
+
+	cmd.Flags().StringVar(
+		&params.toRaw,
+		toFlag,
+		latestBlock,
+		"the number of the last block of the range, or latest",
+	)
+}
+
+func runPreRun(cmd *cobra.Command, _ []string) error {
+	if _, err := helper.ParseJSONRPCAddress(helper.GetJSONRPCAddress(cmd)); err != nil {
+		return err
+	}
+
+	if params.toRaw != latestBlock {
+		if _, err := strconv.ParseUint(params.toRaw, 10, 64); err != nil {
+			return fmt.Errorf("invalid %s: %w", toFlag, err)
+		}
+	}
+
+	return nil
+}
+
+// toBlockNumber returns the last block of the range as a JSON-RPC block number
+func (p *feesParams) toBlockNumber() string {
+	if p.toRaw == latestBlock {
+		return latestBlock
+	}
+
+	to, _ := strconv.ParseUint(p.toRaw, 10, 64)
+
+	return fmt.Sprintf("0x%x", to)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := jsonrpc.NewClient(helper.GetJSONRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer client.Close()
+
+	result := &ChainFeesResult{}
+	if err := client.Call(
+		"edge_getFees",
+		result,
+		fmt.Sprintf("0x%x", params.from),
+		params.toBlockNumber(),
+	); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(result)
+}
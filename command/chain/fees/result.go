@@ -0,0 +1,35 @@
+package fees
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/umbracle/ethgo"
+)
+
+type ChainFeesResult struct {
+	FromBlock        ethgo.ArgUint64 `json:"fromBlock"`
+	ToBlock          ethgo.ArgUint64 `json:"toBlock"`
+	Collected        ethgo.ArgBig    `json:"collected"`
+	Validators       ethgo.ArgBig    `json:"validators"`
+	ContractCreators ethgo.ArgBig    `json:"contractCreators"`
+	Burned           ethgo.ArgBig    `json:"burned"`
+}
+
+func (r *ChainFeesResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN FEES]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Blocks|%d - %d", r.FromBlock, r.ToBlock),
+		fmt.Sprintf("Collected|%s", (*big.Int)(&r.Collected)),
+		fmt.Sprintf("Validators|%s", (*big.Int)(&r.Validators)),
+		fmt.Sprintf("Contract Creators|%s", (*big.Int)(&r.ContractCreators)),
+		fmt.Sprintf("Burned|%s", (*big.Int)(&r.Burned)),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
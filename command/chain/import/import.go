@@ -0,0 +1,53 @@
+package chainimport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use: "import",
+		Short: "Imports a file exported with the receipts into the data directory of a stopped node, " +
+			"which has to hold the genesis at least. The blocks extending the local chain are written " +
+			"without being executed nor having their seals verified, so the file has to come from a trusted " +
+			"source. The node executes the blocks whose state isn't imported on startup",
+		Args: cobra.NoArgs,
+		Run:  runCommand,
+	}
+
+	setFlags(importCmd)
+	helper.SetRequiredFlags(importCmd, params.getRequiredFlags())
+
+	return importCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.file,
+		fileFlag,
+		"",
+		"the path of the exported file",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.importChain(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
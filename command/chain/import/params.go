@@ -0,0 +1,88 @@
+package chainimport
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/command"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag = "data-dir"
+	fileFlag    = "file"
+)
+
+const (
+	blockchainFolder = "blockchain"
+	stateFolder      = "trie"
+)
+
+var (
+	params = &importParams{}
+)
+
+type importParams struct {
+	dataDir string
+	file    string
+
+	head  uint64
+	stats *archive.ChainArchiveStats
+}
+
+func (p *importParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		fileFlag,
+	}
+}
+
+// importChain imports the exported file into the data directory of a stopped node
+func (p *importParams) importChain() error {
+	logger := hclog.NewNullLogger()
+
+	fs, err := os.Open(p.file)
+	if err != nil {
+		return err
+	}
+
+	defer fs.Close()
+
+	db, err := blockchain.OpenExistingStorage(filepath.Join(p.dataDir, blockchainFolder), logger)
+	if err != nil {
+		return err
+	}
+
+	st, err := itrie.NewLevelDBStorage(filepath.Join(p.dataDir, stateFolder), logger)
+	if err != nil {
+		_ = db.Close()
+
+		return err
+	}
+
+	p.stats, err = archive.ImportChain(db, st, fs)
+	p.head, _ = db.ReadHeadNumber()
+
+	if closeErr := st.Close(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (p *importParams) getResult() command.CommandResult {
+	return &ChainImportResult{
+		DataDir:    p.dataDir,
+		Blocks:     p.stats.Blocks,
+		Head:       p.head,
+		StateNodes: p.stats.StateNodes,
+		Code:       p.stats.Code,
+	}
+}
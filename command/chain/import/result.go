@@ -0,0 +1,33 @@
+package chainimport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ChainImportResult struct {
+	DataDir    string `json:"dataDir"`
+	Blocks     uint64 `json:"blocks"`
+	Head       uint64 `json:"head"`
+	StateNodes uint64 `json:"stateNodes"`
+	Code       uint64 `json:"code"`
+}
+
+func (r *ChainImportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN IMPORT]\n")
+	buffer.WriteString("Imported the chain successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Data directory|%s", r.DataDir),
+		fmt.Sprintf("Imported blocks|%d", r.Blocks),
+		fmt.Sprintf("Head|%d", r.Head),
+		fmt.Sprintf("State nodes|%d", r.StateNodes),
+		fmt.Sprintf("Contract code|%d", r.Code),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
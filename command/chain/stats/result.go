@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/umbracle/ethgo"
+)
+
+type DailyActiveAddresses struct {
+	Day   ethgo.ArgUint64 `json:"day"`
+	Count ethgo.ArgUint64 `json:"count"`
+}
+
+type ChainStatsResult struct {
+	FromBlock        ethgo.ArgUint64        `json:"fromBlock"`
+	ToBlock          ethgo.ArgUint64        `json:"toBlock"`
+	TPS              float64                `json:"tps"`
+	AvgGasUsed       float64                `json:"avgGasUsed"`
+	AvgBlockFullness float64                `json:"avgBlockFullness"`
+	ActiveAddresses  []DailyActiveAddresses `json:"activeAddresses"`
+}
+
+func (r *ChainStatsResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN STATS]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Blocks|%d - %d", r.FromBlock, r.ToBlock),
+		fmt.Sprintf("Transactions Per Second|%.2f", r.TPS),
+		fmt.Sprintf("Average Gas Used|%.0f", r.AvgGasUsed),
+		fmt.Sprintf("Average Block Fullness|%.2f%%", r.AvgBlockFullness*100),
+	}))
+	buffer.WriteString("\n")
+
+	buffer.WriteString("\n[ACTIVE ADDRESSES]\n")
+
+	days := make([]string, len(r.ActiveAddresses)+1)
+	days[0] = "Day|Unique Addresses"
+
+	for i, day := range r.ActiveAddresses {
+		days[i+1] = fmt.Sprintf(
+			"%s|%d",
+			time.Unix(int64(day.Day), 0).UTC().Format("2006-01-02"),
+			day.Count,
+		)
+	}
+
+	buffer.WriteString(helper.FormatList(days))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
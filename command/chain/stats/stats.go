@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "stats",
+		Short: "Returns the rolling aggregates of the recent blocks: the transactions per second, " +
+			"the average gas used and block fullness, and the unique active addresses per day",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+}
+
+func runPreRun(cmd *cobra.Command, _ []string) error {
+	_, err := helper.ParseJSONRPCAddress(helper.GetJSONRPCAddress(cmd))
+
+	return err
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := jsonrpc.NewClient(helper.GetJSONRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer client.Close()
+
+	result := &ChainStatsResult{}
+	if err := client.Call("edge_getChainStats", result); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(result)
+}
@@ -0,0 +1,210 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag   = "data-dir"
+	chainFlag     = "chain"
+	fromFlag      = "from"
+	toFlag        = "to"
+	reexecuteFlag = "reexecute"
+	repairFlag    = "repair"
+)
+
+const (
+	blockchainFolder = "blockchain"
+	stateFolder      = "trie"
+)
+
+const ibftEngine = "ibft"
+
+var (
+	params = &verifyParams{}
+)
+
+var (
+	errNoHead = errors.New("no head found in the data directory")
+)
+
+type verifyParams struct {
+	dataDir           string
+	genesisPath       string
+	from              uint64
+	toRaw             string
+	reexecuteInterval uint64
+	repair            bool
+
+	to     uint64
+	report *blockchain.ChainVerifyReport
+}
+
+func (p *verifyParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+	}
+}
+
+// verifyChain verifies the chain data in the data directory of a stopped node
+func (p *verifyParams) verifyChain() error {
+	logger := hclog.NewNullLogger()
+
+	db, err := blockchain.OpenExistingStorage(filepath.Join(p.dataDir, blockchainFolder), logger)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	if err := p.initTo(db); err != nil {
+		return err
+	}
+
+	config := &blockchain.ChainVerifyConfig{
+		From:              p.from,
+		To:                p.to,
+		ReexecuteInterval: p.reexecuteInterval,
+		Repair:            p.repair,
+	}
+
+	if p.reexecuteInterval > 0 {
+		cc, err := chain.Import(p.genesisPath)
+		if err != nil {
+			return fmt.Errorf("failed to load the genesis file %s: %w", p.genesisPath, err)
+		}
+
+		st, err := itrie.NewLevelDBStorage(filepath.Join(p.dataDir, stateFolder), logger)
+		if err != nil {
+			return err
+		}
+
+		defer st.Close()
+
+		config.Reexecute = newBlockReexecutor(db, st, cc.Params, logger)
+	}
+
+	p.report, err = blockchain.VerifyChain(db, config)
+
+	return err
+}
+
+// initTo sets the number of the last verified block, the head if not given
+func (p *verifyParams) initTo(db storage.Storage) error {
+	if p.toRaw != "" {
+		to, err := strconv.ParseUint(p.toRaw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", toFlag, err)
+		}
+
+		p.to = to
+
+		return nil
+	}
+
+	head, ok := db.ReadHeadNumber()
+	if !ok {
+		return errNoHead
+	}
+
+	p.to = head
+
+	return nil
+}
+
+// newBlockReexecutor returns the re-executor of the blocks on the local state. Each block is executed
+// by a new executor whose state writes are buffered in memory and dropped, so the local state isn't modified
+func newBlockReexecutor(
+	db storage.Storage,
+	st itrie.Storage,
+	chainParams *chain.Params,
+	logger hclog.Logger,
+) blockchain.BlockReexecutor {
+	getHash := func(_ *types.Header) state.GetHashByNumber {
+		return func(number uint64) types.Hash {
+			hash, _ := db.ReadCanonicalHash(number)
+
+			return hash
+		}
+	}
+
+	getBlockCreator := func(header *types.Header) (types.Address, error) {
+		return header.Miner, nil
+	}
+
+	if chainParams.GetEngine() == ibftEngine {
+		getBlockCreator = ibft.RecoverProposer
+	}
+
+	return func(parent *types.Header, block *types.Block) (types.Hash, []*types.Receipt, error) {
+		if parent.StateRoot != types.EmptyRootHash {
+			_, ok, err := itrie.GetNode(parent.StateRoot.Bytes(), st)
+			if err != nil {
+				return types.ZeroHash, nil, err
+			}
+
+			if !ok {
+				return types.ZeroHash, nil, blockchain.ErrParentStateNotFound
+			}
+		}
+
+		blockCreator, err := getBlockCreator(block.Header)
+		if err != nil {
+			return types.ZeroHash, nil, err
+		}
+
+		executor := state.NewExecutor(chainParams, itrie.NewState(itrie.NewBufferedStorage(st)), logger)
+		executor.SetRuntime(precompiled.NewPrecompiled())
+		executor.SetRuntime(evm.NewEVM())
+		executor.GetHash = getHash
+
+		txn, err := executor.ProcessBlock(parent.StateRoot, block, blockCreator)
+		if err != nil {
+			return types.ZeroHash, nil, err
+		}
+
+		_, root := txn.Commit()
+
+		return root, txn.Receipts(), nil
+	}
+}
+
+func (p *verifyParams) getResult() command.CommandResult {
+	result := &ChainVerifyResult{
+		DataDir:          p.dataDir,
+		From:             p.from,
+		To:               p.to,
+		Verified:         p.report.Verified,
+		Pruned:           p.report.Pruned,
+		Reexecuted:       p.report.Reexecuted,
+		ReexecuteSkipped: p.report.ReexecuteSkipped,
+		Issues:           make([]*ChainIssue, 0, len(p.report.Issues)),
+		ResyncFrom:       p.report.ResyncFrom,
+	}
+
+	for _, issue := range p.report.Issues {
+		result.Issues = append(result.Issues, &ChainIssue{
+			Number:   issue.Number,
+			Hash:     issue.Hash.String(),
+			Error:    issue.Err.Error(),
+			Repaired: issue.Repaired,
+		})
+	}
+
+	return result
+}
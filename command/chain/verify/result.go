@@ -0,0 +1,67 @@
+package verify
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ChainIssue struct {
+	Number   uint64 `json:"number"`
+	Hash     string `json:"hash"`
+	Error    string `json:"error"`
+	Repaired bool   `json:"repaired"`
+}
+
+type ChainVerifyResult struct {
+	DataDir          string        `json:"dataDir"`
+	From             uint64        `json:"from"`
+	To               uint64        `json:"to"`
+	Verified         uint64        `json:"verified"`
+	Pruned           uint64        `json:"pruned"`
+	Reexecuted       uint64        `json:"reexecuted"`
+	ReexecuteSkipped uint64        `json:"reexecuteSkipped"`
+	Issues           []*ChainIssue `json:"issues"`
+	ResyncFrom       *uint64       `json:"resyncFrom,omitempty"`
+}
+
+func (r *ChainVerifyResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN VERIFY]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Data directory|%s", r.DataDir),
+		fmt.Sprintf("Range|%d to %d", r.From, r.To),
+		fmt.Sprintf("Verified blocks|%d", r.Verified),
+		fmt.Sprintf("Pruned blocks|%d", r.Pruned),
+		fmt.Sprintf("Re-executed blocks|%d", r.Reexecuted),
+		fmt.Sprintf("Skipped re-executions|%d", r.ReexecuteSkipped),
+		fmt.Sprintf("Issues|%d", len(r.Issues)),
+	}))
+	buffer.WriteString("\n")
+
+	if len(r.Issues) > 0 {
+		buffer.WriteString("\n[ISSUES]\n")
+
+		rows := make([]string, 0, len(r.Issues)+1)
+		rows = append(rows, "Number|Hash|Repaired|Error")
+
+		for _, issue := range r.Issues {
+			rows = append(rows, fmt.Sprintf("%d|%s|%t|%s", issue.Number, issue.Hash, issue.Repaired, issue.Error))
+		}
+
+		buffer.WriteString(helper.FormatList(rows))
+		buffer.WriteString("\n")
+	}
+
+	if r.ResyncFrom != nil {
+		buffer.WriteString(fmt.Sprintf(
+			"\nThe chain data is broken from block %d, start the node and run: server resync --from %d\n",
+			*r.ResyncFrom,
+			*r.ResyncFrom,
+		))
+	}
+
+	return buffer.String()
+}
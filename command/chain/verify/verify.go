@@ -0,0 +1,85 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use: "verify",
+		Short: "Verifies the integrity of the chain data of a stopped node: the continuity of the header chain, " +
+			"the transactions and receipts roots, and the transaction lookups. The sampled blocks are re-executed " +
+			"on the state of their parent, if kept. The broken transaction lookups, and the broken receipts of " +
+			"the re-executed blocks, are rewritten if repairing. The other issues are fixed by resyncing the chain " +
+			"from the first broken block",
+		Args: cobra.NoArgs,
+		Run:  runCommand,
+	}
+
+	setFlags(verifyCmd)
+	helper.SetRequiredFlags(verifyCmd, params.getRequiredFlags())
+
+	return verifyCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		"the genesis file of the chain, used to re-execute the blocks",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.from,
+		fromFlag,
+		0,
+		"the number of the first verified block",
+	)
+
+	cmd.Flags().StringVar(
+		&params.toRaw,
+		toFlag,
+		"",
+		"the number of the last verified block, the head if not set",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.reexecuteInterval,
+		reexecuteFlag,
+		0,
+		"the interval between the re-executed blocks, none are if 0. The staking contract "+
+			"deployment of the PoS fork block isn't replayed, that block is reported if sampled",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.repair,
+		repairFlag,
+		false,
+		"rewrite the broken transaction lookups, and the broken receipts recovered by re-executing their block",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.verifyChain(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
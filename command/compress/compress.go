@@ -0,0 +1,55 @@
+package compress
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	compressCmd := &cobra.Command{
+		Use: "compress",
+		Short: "Rewrites the block bodies and receipts in the data directory of a stopped node " +
+			"with the given compression, then compacts the blockchain database",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(compressCmd)
+	helper.SetRequiredFlags(compressCmd, params.getRequiredFlags())
+
+	return compressCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawCompression,
+		compressionFlag,
+		"",
+		"the compression of the block bodies and receipts, one of none, snappy and zstd",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.compress(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
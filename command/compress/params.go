@@ -0,0 +1,70 @@
+package compress
+
+import (
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/command"
+)
+
+const (
+	dataDirFlag     = "data-dir"
+	compressionFlag = "compression"
+)
+
+const blockchainFolder = "blockchain"
+
+var (
+	params = &compressParams{}
+)
+
+type compressParams struct {
+	dataDir        string
+	rawCompression string
+
+	compression storage.Compression
+	rewritten   uint64
+}
+
+func (p *compressParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		compressionFlag,
+	}
+}
+
+func (p *compressParams) validateFlags() error {
+	var err error
+
+	p.compression, err = storage.ParseCompression(p.rawCompression)
+
+	return err
+}
+
+func (p *compressParams) compress() error {
+	db, _, err := blockchain.OpenExistingDatabase(filepath.Join(p.dataDir, blockchainFolder))
+	if err != nil {
+		return err
+	}
+
+	p.rewritten, err = storage.RecompressDatabase(db, p.compression)
+
+	// the space of the rewritten entries is reclaimed
+	if err == nil {
+		err = db.Compact()
+	}
+
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (p *compressParams) getResult() command.CommandResult {
+	return &CompressResult{
+		Compression: p.compression.String(),
+		Rewritten:   p.rewritten,
+	}
+}
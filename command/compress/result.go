@@ -0,0 +1,27 @@
+package compress
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type CompressResult struct {
+	Compression string `json:"compression"`
+	Rewritten   uint64 `json:"rewritten"`
+}
+
+func (r *CompressResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[COMPRESS]\n")
+	buffer.WriteString("Rewrote the block bodies and receipts successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Compression|%s", r.Compression),
+		fmt.Sprintf("Rewritten entries|%d", r.Rewritten),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,128 @@
+package call
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+func GetCommand() *cobra.Command {
+	callCmd := &cobra.Command{
+		Use: "call [method arguments...]",
+		Short: "Calls a method of a deployed contract using its ABI. Arrays and tuples are passed as JSON. " +
+			"The return values are decoded, unless the call is sent as a transaction",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(callCmd)
+	helper.SetRequiredFlags(callCmd, params.getRequiredFlags())
+
+	return callCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.abiPath,
+		abiFlag,
+		"",
+		"the path to the contract ABI, or to the contract JSON artifact containing it",
+	)
+
+	cmd.Flags().StringVar(
+		&params.toRaw,
+		toFlag,
+		"",
+		"the address of the contract",
+	)
+
+	cmd.Flags().StringVar(
+		&params.methodName,
+		methodFlag,
+		"",
+		"the name of the method to call",
+	)
+
+	cmd.Flags().StringVar(
+		&params.fromRaw,
+		fromFlag,
+		"",
+		"the address the call is made from. Ignored when sending a transaction",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.send,
+		sendFlag,
+		false,
+		"send the call as a signed transaction instead of executing it with eth_call",
+	)
+
+	cmd.Flags().StringVar(
+		&params.keyFile,
+		contractHelper.KeyFileFlag,
+		"",
+		"the path to the file containing the hex encoded private key used to sign the transaction",
+	)
+
+	cmd.Flags().StringVar(
+		&params.valueRaw,
+		contractHelper.ValueFlag,
+		"0",
+		"the value sent with the transaction in wei",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.gasLimit,
+		contractHelper.GasLimitFlag,
+		0,
+		"the gas limit of the transaction. If omitted, the gas limit is estimated",
+	)
+}
+
+func runPreRun(cmd *cobra.Command, args []string) error {
+	if err := params.validateFlags(); err != nil {
+		return err
+	}
+
+	if err := params.initRawParams(args); err != nil {
+		return err
+	}
+
+	if _, err := helper.ParseJSONRPCAddress(
+		helper.GetJSONRPCAddress(cmd),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := jsonrpc.NewClient(helper.GetJSONRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer client.Close()
+
+	if params.send {
+		err = params.sendTransaction(client)
+	} else {
+		err = params.callContract(client)
+	}
+
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,137 @@
+package call
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/ethgo/abi"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+const (
+	abiFlag    = "abi"
+	toFlag     = "to"
+	methodFlag = "method"
+	fromFlag   = "from"
+	sendFlag   = "send"
+)
+
+var (
+	errInvalidAddress = errors.New("invalid address format")
+	errInvalidValue   = errors.New("invalid value")
+	errKeyRequired    = fmt.Errorf("sending a transaction requires the --%s flag", contractHelper.KeyFileFlag)
+)
+
+var (
+	params = &callParams{}
+)
+
+type callParams struct {
+	abiPath    string
+	toRaw      string
+	fromRaw    string
+	methodName string
+	keyFile    string
+	valueRaw   string
+	gasLimit   uint64
+	send       bool
+
+	artifact *contractHelper.ContractArtifact
+	to       types.Address
+	from     types.Address
+	value    *big.Int
+	method   *abi.Method
+	input    []byte
+
+	outputs []contractHelper.DecodedOutput
+	receipt *contractHelper.ReceiptResult
+}
+
+func (p *callParams) getRequiredFlags() []string {
+	return []string{
+		abiFlag,
+		toFlag,
+		methodFlag,
+	}
+}
+
+func (p *callParams) validateFlags() error {
+	if p.send && p.keyFile == "" {
+		return errKeyRequired
+	}
+
+	return nil
+}
+
+func (p *callParams) initRawParams(args []string) error {
+	if err := p.to.UnmarshalText([]byte(p.toRaw)); err != nil {
+		return errInvalidAddress
+	}
+
+	if p.fromRaw != "" {
+		if err := p.from.UnmarshalText([]byte(p.fromRaw)); err != nil {
+			return errInvalidAddress
+		}
+	}
+
+	var err error
+
+	if p.value, err = types.ParseUint256orHex(&p.valueRaw); err != nil {
+		return errInvalidValue
+	}
+
+	if p.artifact, err = contractHelper.ReadContractArtifact(p.abiPath); err != nil {
+		return err
+	}
+
+	if p.method, p.input, err = p.artifact.EncodeCall(p.methodName, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *callParams) callContract(client *jsonrpc.Client) error {
+	returnData, err := contractHelper.Call(client, p.from, p.to, p.input)
+	if err != nil {
+		return err
+	}
+
+	p.outputs, err = contractHelper.DecodeOutputs(p.method, returnData)
+
+	return err
+}
+
+func (p *callParams) sendTransaction(client *jsonrpc.Client) error {
+	key, err := contractHelper.ReadPrivateKey(p.keyFile)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := contractHelper.SendTransaction(client, key, &contractHelper.TxnParams{
+		To:             &p.to,
+		Input:          p.input,
+		Value:          p.value,
+		GasLimit:       p.gasLimit,
+		ReceiptTimeout: contractHelper.ReceiptTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.receipt = contractHelper.NewReceiptResult(receipt)
+
+	return nil
+}
+
+func (p *callParams) getResult() command.CommandResult {
+	return &ContractCallResult{
+		Method:  p.method.Sig(),
+		Outputs: p.outputs,
+		Receipt: p.receipt,
+	}
+}
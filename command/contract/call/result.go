@@ -0,0 +1,50 @@
+package call
+
+import (
+	"bytes"
+	"fmt"
+
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ContractCallResult struct {
+	Method  string                         `json:"method"`
+	Outputs []contractHelper.DecodedOutput `json:"outputs,omitempty"`
+	Receipt *contractHelper.ReceiptResult  `json:"receipt,omitempty"`
+}
+
+func (r *ContractCallResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CONTRACT CALL]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Method|%s", r.Method),
+	}))
+	buffer.WriteString("\n")
+
+	if r.Receipt != nil {
+		buffer.WriteString("\n[TRANSACTION]\n")
+		buffer.WriteString(helper.FormatKV(r.Receipt.KV()))
+		buffer.WriteString("\n")
+
+		return buffer.String()
+	}
+
+	if len(r.Outputs) == 0 {
+		buffer.WriteString("\nNo return values\n")
+
+		return buffer.String()
+	}
+
+	outputs := make([]string, len(r.Outputs))
+	for i, output := range r.Outputs {
+		outputs[i] = fmt.Sprintf("%s (%s)|%s", output.Name, output.Type, output.Value)
+	}
+
+	buffer.WriteString("\n[RETURN VALUES]\n")
+	buffer.WriteString(helper.FormatKV(outputs))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
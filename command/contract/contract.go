@@ -0,0 +1,30 @@
+package contract
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/contract/call"
+	"github.com/0xPolygon/polygon-edge/command/contract/deploy"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	contractCmd := &cobra.Command{
+		Use:   "contract",
+		Short: "Top level command for deploying and interacting with smart contracts. Only accepts subcommands.",
+	}
+
+	helper.RegisterJSONRPCFlag(contractCmd)
+
+	registerSubcommands(contractCmd)
+
+	return contractCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// contract call
+		call.GetCommand(),
+		// contract deploy
+		deploy.GetCommand(),
+	)
+}
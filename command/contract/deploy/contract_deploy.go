@@ -0,0 +1,90 @@
+package deploy
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+func GetCommand() *cobra.Command {
+	deployCmd := &cobra.Command{
+		Use: "deploy [constructor arguments...]",
+		Short: "Deploys a contract from its JSON artifact, encoding the constructor arguments using the ABI. " +
+			"Arrays and tuples are passed as JSON",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(deployCmd)
+	helper.SetRequiredFlags(deployCmd, params.getRequiredFlags())
+
+	return deployCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.artifactPath,
+		artifactFlag,
+		"",
+		"the path to the contract JSON artifact containing the ABI and the bytecode",
+	)
+
+	cmd.Flags().StringVar(
+		&params.keyFile,
+		contractHelper.KeyFileFlag,
+		"",
+		"the path to the file containing the hex encoded private key used to sign the transaction",
+	)
+
+	cmd.Flags().StringVar(
+		&params.valueRaw,
+		contractHelper.ValueFlag,
+		"0",
+		"the value sent with the transaction in wei",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.gasLimit,
+		contractHelper.GasLimitFlag,
+		0,
+		"the gas limit of the transaction. If omitted, the gas limit is estimated",
+	)
+}
+
+func runPreRun(cmd *cobra.Command, args []string) error {
+	if err := params.initRawParams(args); err != nil {
+		return err
+	}
+
+	if _, err := helper.ParseJSONRPCAddress(
+		helper.GetJSONRPCAddress(cmd),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := jsonrpc.NewClient(helper.GetJSONRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer client.Close()
+
+	if err := params.deployContract(client); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,91 @@
+package deploy
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+const (
+	artifactFlag = "artifact"
+)
+
+var (
+	errInvalidValue = errors.New("invalid value")
+)
+
+var (
+	params = &deployParams{}
+)
+
+type deployParams struct {
+	artifactPath string
+	keyFile      string
+	valueRaw     string
+	gasLimit     uint64
+
+	artifact *contractHelper.ContractArtifact
+	value    *big.Int
+	input    []byte
+
+	contractAddress types.Address
+	receipt         *contractHelper.ReceiptResult
+}
+
+func (p *deployParams) getRequiredFlags() []string {
+	return []string{
+		artifactFlag,
+		contractHelper.KeyFileFlag,
+	}
+}
+
+func (p *deployParams) initRawParams(args []string) error {
+	var err error
+
+	if p.value, err = types.ParseUint256orHex(&p.valueRaw); err != nil {
+		return errInvalidValue
+	}
+
+	if p.artifact, err = contractHelper.ReadContractArtifact(p.artifactPath); err != nil {
+		return err
+	}
+
+	if p.input, err = p.artifact.EncodeDeployment(args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *deployParams) deployContract(client *jsonrpc.Client) error {
+	key, err := contractHelper.ReadPrivateKey(p.keyFile)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := contractHelper.SendTransaction(client, key, &contractHelper.TxnParams{
+		Input:          p.input,
+		Value:          p.value,
+		GasLimit:       p.gasLimit,
+		ReceiptTimeout: contractHelper.ReceiptTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.contractAddress = types.Address(receipt.ContractAddress)
+	p.receipt = contractHelper.NewReceiptResult(receipt)
+
+	return nil
+}
+
+func (p *deployParams) getResult() command.CommandResult {
+	return &ContractDeployResult{
+		ContractAddress: p.contractAddress.String(),
+		Receipt:         p.receipt,
+	}
+}
@@ -0,0 +1,27 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+
+	contractHelper "github.com/0xPolygon/polygon-edge/command/contract/helper"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ContractDeployResult struct {
+	ContractAddress string                        `json:"contract_address"`
+	Receipt         *contractHelper.ReceiptResult `json:"receipt"`
+}
+
+func (r *ContractDeployResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CONTRACT DEPLOY]\n")
+	buffer.WriteString(helper.FormatKV(append(
+		[]string{fmt.Sprintf("Contract address|%s", r.ContractAddress)},
+		r.Receipt.KV()...,
+	)))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,345 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var (
+	errMissingBytecode = errors.New("contract artifact has no bytecode")
+)
+
+// ContractArtifact is a compiled contract, as produced by the common
+// toolchains (Truffle, Hardhat, solc --combined-json)
+type ContractArtifact struct {
+	ABI      *abi.ABI
+	Bytecode []byte
+}
+
+type rawArtifact struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bytecode string          `json:"bytecode"`
+}
+
+// ReadContractArtifact reads the ABI, and the bytecode if present, from the specified path.
+// The file can either be a plain ABI JSON array, or a JSON object with the abi and bytecode fields
+func ReadContractArtifact(path string) (*ContractArtifact, error) {
+	rawData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rawData = bytes.TrimSpace(rawData)
+
+	raw := rawArtifact{
+		ABI: rawData,
+	}
+
+	if len(rawData) > 0 && rawData[0] == '{' {
+		if err := json.Unmarshal(rawData, &raw); err != nil {
+			return nil, fmt.Errorf("unable to parse contract artifact: %w", err)
+		}
+	}
+
+	contractABI, err := abi.NewABI(string(raw.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse contract ABI: %w", err)
+	}
+
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(raw.Bytecode, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode bytecode: %w", err)
+	}
+
+	return &ContractArtifact{
+		ABI:      contractABI,
+		Bytecode: bytecode,
+	}, nil
+}
+
+// EncodeDeployment returns the bytecode followed by the encoded constructor arguments
+func (a *ContractArtifact) EncodeDeployment(args []string) ([]byte, error) {
+	if len(a.Bytecode) == 0 {
+		return nil, errMissingBytecode
+	}
+
+	input := append([]byte{}, a.Bytecode...)
+
+	if a.ABI.Constructor == nil {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("contract has no constructor, but %d arguments were given", len(args))
+		}
+
+		return input, nil
+	}
+
+	encodedArgs, err := EncodeArgs(a.ABI.Constructor.Inputs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(input, encodedArgs...), nil
+}
+
+// EncodeCall returns the method selector followed by the encoded arguments
+func (a *ContractArtifact) EncodeCall(methodName string, args []string) (*abi.Method, []byte, error) {
+	method := a.ABI.GetMethod(methodName)
+	if method == nil {
+		return nil, nil, fmt.Errorf("method %s not found in the ABI", methodName)
+	}
+
+	encodedArgs, err := EncodeArgs(method.Inputs, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return method, append(method.ID(), encodedArgs...), nil
+}
+
+// EncodeArgs encodes the command line arguments as the given tuple.
+// Scalar values are taken as is, while arrays and tuples are expected in JSON format
+func EncodeArgs(inputs *abi.Type, args []string) ([]byte, error) {
+	elems := inputs.TupleElems()
+	if len(elems) != len(args) {
+		return nil, fmt.Errorf("expected %d arguments, but %d were given", len(elems), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+
+	for i, elem := range elems {
+		val, err := parseArg(elem.Elem, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d (%s): %w", i, elem.Elem.String(), err)
+		}
+
+		values[i] = val
+	}
+
+	return inputs.Encode(values)
+}
+
+func parseArg(typ *abi.Type, raw string) (interface{}, error) {
+	switch typ.Kind() {
+	case abi.KindSlice, abi.KindArray, abi.KindTuple:
+		dec := json.NewDecoder(strings.NewReader(raw))
+		dec.UseNumber()
+
+		var val interface{}
+		if err := dec.Decode(&val); err != nil {
+			return nil, err
+		}
+
+		return convertValue(typ, val)
+
+	default:
+		return convertValue(typ, raw)
+	}
+}
+
+// convertValue converts a raw (string or decoded JSON) value into a value the ABI encoder accepts
+func convertValue(typ *abi.Type, val interface{}) (interface{}, error) {
+	switch typ.Kind() {
+	case abi.KindSlice, abi.KindArray, abi.KindTuple:
+		list, ok := val.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON array for %s", typ.String())
+		}
+
+		var elemTypes []*abi.Type
+
+		if typ.Kind() == abi.KindTuple {
+			for _, elem := range typ.TupleElems() {
+				elemTypes = append(elemTypes, elem.Elem)
+			}
+
+			if len(elemTypes) != len(list) {
+				return nil, fmt.Errorf("expected %d elements, but %d were given", len(elemTypes), len(list))
+			}
+		} else {
+			for range list {
+				elemTypes = append(elemTypes, typ.Elem())
+			}
+		}
+
+		res := make([]interface{}, len(list))
+
+		for i, item := range list {
+			elem, err := convertValue(elemTypes[i], item)
+			if err != nil {
+				return nil, err
+			}
+
+			res[i] = elem
+		}
+
+		if typ.Kind() != abi.KindArray {
+			return res, nil
+		}
+
+		// fixed size arrays have to be encoded from an array value
+		arr := reflect.New(reflect.ArrayOf(len(res), reflect.TypeOf(res).Elem())).Elem()
+		for i, elem := range res {
+			arr.Index(i).Set(reflect.ValueOf(elem))
+		}
+
+		return arr.Interface(), nil
+
+	case abi.KindBool:
+		switch v := val.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+
+	case abi.KindInt, abi.KindUInt:
+		var str string
+
+		switch v := val.(type) {
+		case string:
+			str = v
+		case json.Number:
+			str = v.String()
+		default:
+			return nil, fmt.Errorf("unexpected value %v for %s", val, typ.String())
+		}
+
+		num, ok := parseInteger(str)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %s for %s", str, typ.String())
+		}
+
+		if !fitsInteger(num, typ) {
+			return nil, fmt.Errorf("number %s out of range for %s", str, typ.String())
+		}
+
+		return num, nil
+
+	default:
+		switch v := val.(type) {
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected value %v for %s", val, typ.String())
+}
+
+// DecodedOutput is a single named return value of a method call
+type DecodedOutput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// DecodeOutputs decodes the return data of the method, in the order of the ABI outputs
+func DecodeOutputs(method *abi.Method, data []byte) ([]DecodedOutput, error) {
+	elems := method.Outputs.TupleElems()
+	if len(elems) == 0 {
+		return nil, nil
+	}
+
+	decoded, err := method.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode return value: %w", err)
+	}
+
+	res := make([]DecodedOutput, len(elems))
+
+	for i, elem := range elems {
+		name := elem.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+
+		res[i] = DecodedOutput{
+			Name:  name,
+			Type:  elem.Elem.String(),
+			Value: formatValue(decoded[name]),
+		}
+	}
+
+	return res, nil
+}
+
+func formatValue(val interface{}) string {
+	switch v := val.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// fixed size bytes
+			buf := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(buf), rv)
+
+			return "0x" + hex.EncodeToString(buf)
+		}
+
+		fallthrough
+
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items[i] = formatValue(rv.Index(i).Interface())
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// parseInteger parses a decimal or a 0x prefixed hex integer
+func parseInteger(str string) (*big.Int, bool) {
+	digits := strings.TrimPrefix(str, "-")
+
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		base, digits = 16, digits[2:]
+	}
+
+	num, ok := new(big.Int).SetString(digits, base)
+	if !ok || digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, false
+	}
+
+	if strings.HasPrefix(str, "-") {
+		num.Neg(num)
+	}
+
+	return num, true
+}
+
+// fitsInteger checks the integer against the sign and the bit size of the (u)int type
+func fitsInteger(num *big.Int, typ *abi.Type) bool {
+	if typ.Kind() == abi.KindUInt {
+		return num.Sign() >= 0 && num.BitLen() <= typ.Size()
+	}
+
+	// -2^(size-1) <= num < 2^(size-1)
+	if num.Sign() < 0 {
+		return new(big.Int).Not(num).BitLen() < typ.Size()
+	}
+
+	return num.BitLen() < typ.Size()
+}
@@ -0,0 +1,243 @@
+package helper
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+)
+
+func TestEncodeArgs(t *testing.T) {
+	t.Parallel()
+
+	addr := ethgo.HexToAddress("0x0000000000000000000000000000000000000abc")
+
+	testTable := []struct {
+		name     string
+		inputs   string
+		args     []string
+		expected []interface{}
+	}{
+		{
+			"scalar values",
+			"tuple(uint256,int64,address,bool,string)",
+			[]string{"1000", "-5", addr.String(), "true", "hello"},
+			[]interface{}{big.NewInt(1000), big.NewInt(-5), addr, true, "hello"},
+		},
+		{
+			"hex encoded integer",
+			"tuple(uint256)",
+			[]string{"0x10"},
+			[]interface{}{big.NewInt(16)},
+		},
+		{
+			"leading zero decimal integer",
+			"tuple(uint256)",
+			[]string{"010"},
+			[]interface{}{big.NewInt(10)},
+		},
+		{
+			"integer bounds",
+			"tuple(uint8,int8,int8)",
+			[]string{"255", "-128", "127"},
+			[]interface{}{big.NewInt(255), big.NewInt(-128), big.NewInt(127)},
+		},
+		{
+			"bool written as a number",
+			"tuple(bool)",
+			[]string{"0"},
+			[]interface{}{false},
+		},
+		{
+			"dynamic array",
+			"tuple(uint256[])",
+			[]string{"[1, \"2\", 3]"},
+			[]interface{}{[]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}},
+		},
+		{
+			"empty dynamic array",
+			"tuple(address[])",
+			[]string{"[]"},
+			[]interface{}{[]ethgo.Address{}},
+		},
+		{
+			"fixed array",
+			"tuple(bool[2])",
+			[]string{"[true, \"false\"]"},
+			[]interface{}{[2]bool{true, false}},
+		},
+		{
+			"tuple",
+			"tuple(tuple(address,uint256))",
+			[]string{"[\"" + addr.String() + "\", 7]"},
+			[]interface{}{[]interface{}{addr, big.NewInt(7)}},
+		},
+		{
+			"array of tuples",
+			"tuple(tuple(bool,string)[])",
+			[]string{"[[true, \"a\"], [false, \"b\"]]"},
+			[]interface{}{[]interface{}{
+				[]interface{}{true, "a"},
+				[]interface{}{false, "b"},
+			}},
+		},
+		{
+			"no arguments",
+			"tuple()",
+			[]string{},
+			[]interface{}{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			inputs := abi.MustNewType(testCase.inputs)
+
+			expected, err := inputs.Encode(testCase.expected)
+			assert.NoError(t, err)
+
+			encoded, err := EncodeArgs(inputs, testCase.args)
+			assert.NoError(t, err)
+
+			assert.Equal(t, expected, encoded)
+		})
+	}
+}
+
+func TestEncodeArgs_Malformed(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		inputs string
+		args   []string
+		err    string
+	}{
+		{
+			"too few arguments",
+			"tuple(uint256,bool)",
+			[]string{"1"},
+			"expected 2 arguments, but 1 were given",
+		},
+		{
+			"too many arguments",
+			"tuple()",
+			[]string{"1"},
+			"expected 0 arguments, but 1 were given",
+		},
+		{
+			"invalid bool",
+			"tuple(bool)",
+			[]string{"yes"},
+			"invalid argument 0 (bool)",
+		},
+		{
+			"invalid integer",
+			"tuple(uint256)",
+			[]string{"abc"},
+			"invalid number abc for uint256",
+		},
+		{
+			"single character integer",
+			"tuple(int8)",
+			[]string{"a"},
+			"invalid number a for int8",
+		},
+		{
+			"fractional integer",
+			"tuple(uint256[])",
+			[]string{"[1.5]"},
+			"invalid number 1.5 for uint256",
+		},
+		{
+			"binary integer",
+			"tuple(uint256)",
+			[]string{"0b1"},
+			"invalid number 0b1 for uint256",
+		},
+		{
+			"octal integer",
+			"tuple(uint256)",
+			[]string{"0o7"},
+			"invalid number 0o7 for uint256",
+		},
+		{
+			"integer with underscores",
+			"tuple(uint256)",
+			[]string{"1_000"},
+			"invalid number 1_000 for uint256",
+		},
+		{
+			"negative unsigned integer",
+			"tuple(uint256)",
+			[]string{"-1"},
+			"number -1 out of range for uint256",
+		},
+		{
+			"unsigned integer overflow",
+			"tuple(uint8)",
+			[]string{"256"},
+			"number 256 out of range for uint8",
+		},
+		{
+			"signed integer overflow",
+			"tuple(int8)",
+			[]string{"128"},
+			"number 128 out of range for int8",
+		},
+		{
+			"signed integer underflow",
+			"tuple(int8)",
+			[]string{"-129"},
+			"number -129 out of range for int8",
+		},
+		{
+			"malformed JSON",
+			"tuple(uint256[])",
+			[]string{"[1, 2"},
+			"invalid argument 0 (uint256[])",
+		},
+		{
+			"array given as a scalar",
+			"tuple(uint256[])",
+			[]string{"1"},
+			"expected a JSON array for uint256[]",
+		},
+		{
+			"wrong tuple size",
+			"tuple(tuple(bool,uint256))",
+			[]string{"[true]"},
+			"expected 2 elements, but 1 were given",
+		},
+		{
+			"nested array given as a scalar",
+			"tuple(uint256[][])",
+			[]string{"[1]"},
+			"expected a JSON array for uint256[]",
+		},
+		{
+			"non scalar element",
+			"tuple(bool[])",
+			[]string{"[{\"a\": 1}]"},
+			"unexpected value map[a:1] for bool",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := EncodeArgs(abi.MustNewType(testCase.inputs), testCase.args)
+			assert.Error(t, err)
+			assert.ErrorContains(t, err, testCase.err)
+		})
+	}
+}
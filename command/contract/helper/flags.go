@@ -0,0 +1,16 @@
+package helper
+
+import (
+	"time"
+)
+
+const (
+	KeyFileFlag  = "key-file"
+	ValueFlag    = "value"
+	GasLimitFlag = "gas-limit"
+)
+
+const (
+	// ReceiptTimeout is how long the commands wait for a sent transaction to be included
+	ReceiptTimeout = time.Minute
+)
@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+)
+
+// ReceiptResult is the outcome of a transaction sent by the contract commands
+type ReceiptResult struct {
+	TxHash      string `json:"tx_hash"`
+	BlockNumber uint64 `json:"block_number"`
+	GasUsed     uint64 `json:"gas_used"`
+	Success     bool   `json:"success"`
+}
+
+func NewReceiptResult(receipt *ethgo.Receipt) *ReceiptResult {
+	return &ReceiptResult{
+		TxHash:      receipt.TransactionHash.String(),
+		BlockNumber: receipt.BlockNumber,
+		GasUsed:     receipt.GasUsed,
+		Success:     receipt.Status == 1,
+	}
+}
+
+// KV returns the receipt fields, formatted for helper.FormatKV
+func (r *ReceiptResult) KV() []string {
+	return []string{
+		fmt.Sprintf("Transaction hash|%s", r.TxHash),
+		fmt.Sprintf("Block number|%d", r.BlockNumber),
+		fmt.Sprintf("Gas used|%d", r.GasUsed),
+		fmt.Sprintf("Success|%t", r.Success),
+	}
+}
@@ -0,0 +1,121 @@
+package helper
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+// TxnParams are the parameters of a transaction sent by the contract commands
+type TxnParams struct {
+	To             *types.Address
+	Input          []byte
+	Value          *big.Int
+	GasLimit       uint64
+	ReceiptTimeout time.Duration
+}
+
+// ReadPrivateKey reads the hex encoded private key from the specified file
+func ReadPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	rawKey, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the private key: %w", err)
+	}
+
+	return crypto.BytesToPrivateKey([]byte(strings.TrimPrefix(strings.TrimSpace(string(rawKey)), "0x")))
+}
+
+// Call executes the input against the contract with eth_call, at the latest block
+func Call(client *jsonrpc.Client, from types.Address, to types.Address, input []byte) ([]byte, error) {
+	res, err := client.Eth().Call(&ethgo.CallMsg{
+		From: ethgo.Address(from),
+		To:   (*ethgo.Address)(&to),
+		Data: input,
+	}, ethgo.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.ParseBytes(&res)
+}
+
+// SendTransaction signs the transaction with the key, sends it to the node
+// and waits for its receipt
+func SendTransaction(
+	client *jsonrpc.Client,
+	key *ecdsa.PrivateKey,
+	params *TxnParams,
+) (*ethgo.Receipt, error) {
+	from := crypto.PubKeyToAddress(&key.PublicKey)
+
+	chainID, err := client.Eth().ChainID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query the chain ID: %w", err)
+	}
+
+	nonce, err := client.Eth().GetNonce(ethgo.Address(from), ethgo.Pending)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query the nonce: %w", err)
+	}
+
+	gasPrice, err := client.Eth().GasPrice()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query the gas price: %w", err)
+	}
+
+	value := params.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	gasLimit := params.GasLimit
+	if gasLimit == 0 {
+		if gasLimit, err = client.Eth().EstimateGas(&ethgo.CallMsg{
+			From:     ethgo.Address(from),
+			To:       (*ethgo.Address)(params.To),
+			Data:     params.Input,
+			GasPrice: gasPrice,
+			Value:    value,
+		}); err != nil {
+			return nil, fmt.Errorf("unable to estimate gas: %w", err)
+		}
+	}
+
+	txn, err := crypto.NewEIP155Signer(chainID.Uint64()).SignTx(&types.Transaction{
+		Nonce:    nonce,
+		From:     from,
+		To:       params.To,
+		Input:    params.Input,
+		Value:    value,
+		Gas:      gasLimit,
+		GasPrice: new(big.Int).SetUint64(gasPrice),
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign the transaction: %w", err)
+	}
+
+	hash, err := client.Eth().SendRawTransaction(txn.MarshalRLP())
+	if err != nil {
+		return nil, fmt.Errorf("unable to send the transaction: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), params.ReceiptTimeout)
+	defer cancel()
+
+	receipt, err := tests.WaitForReceipt(ctx, client.Eth(), hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the receipt of transaction %s: %w", hash, err)
+	}
+
+	return receipt, nil
+}
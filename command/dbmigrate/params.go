@@ -0,0 +1,145 @@
+package dbmigrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/command"
+)
+
+const (
+	dataDirFlag  = "data-dir"
+	dbEngineFlag = "db.engine"
+)
+
+const (
+	blockchainFolder = "blockchain"
+	// ancientFolder is the folder of the freezer in the blockchain folder
+	ancientFolder = "ancient"
+)
+
+var (
+	params = &migrateParams{}
+)
+
+var (
+	errSameEngine   = errors.New("the blockchain database is already written by this engine")
+	errBackupExists = errors.New("the backup of the blockchain database already exists")
+)
+
+type migrateParams struct {
+	dataDir   string
+	rawEngine string
+
+	engine  storage.Engine
+	from    storage.Engine
+	entries uint64
+	backup  string
+}
+
+func (p *migrateParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		dbEngineFlag,
+	}
+}
+
+func (p *migrateParams) validateFlags() error {
+	var err error
+
+	p.engine, err = storage.ParseEngine(p.rawEngine)
+
+	return err
+}
+
+// migrate copies the blockchain database to a new one written by the target engine,
+// and swaps them once the copy completes. The freezer files are moved along
+func (p *migrateParams) migrate() error {
+	path := filepath.Join(p.dataDir, blockchainFolder)
+	target := path + ".migrating"
+
+	src, from, err := blockchain.OpenExistingDatabase(path)
+	if err != nil {
+		return err
+	}
+
+	p.from = from
+	p.backup = fmt.Sprintf("%s.%s-backup", path, from)
+
+	if err := p.checkPaths(target); err != nil {
+		_ = src.Close()
+
+		return err
+	}
+
+	dst, err := blockchain.OpenDatabase(target, p.engine)
+	if err != nil {
+		_ = src.Close()
+
+		return err
+	}
+
+	p.entries, err = storage.CopyDatabase(src, dst)
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return p.swap(path, target)
+}
+
+// checkPaths checks the database isn't converted to its own engine, and the backup path is free.
+// A database left by an interrupted migration is removed
+func (p *migrateParams) checkPaths(target string) error {
+	if p.from == p.engine {
+		return fmt.Errorf("%w: %s", errSameEngine, p.engine)
+	}
+
+	if _, err := os.Stat(p.backup); err == nil {
+		return fmt.Errorf("%w: %s", errBackupExists, p.backup)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.RemoveAll(target)
+}
+
+// swap moves the freezer to the converted database, and replaces the original database with it
+func (p *migrateParams) swap(path, target string) error {
+	ancient := filepath.Join(path, ancientFolder)
+
+	if _, err := os.Stat(ancient); err == nil {
+		if err := os.Rename(ancient, filepath.Join(target, ancientFolder)); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Rename(path, p.backup); err != nil {
+		return err
+	}
+
+	return os.Rename(target, path)
+}
+
+func (p *migrateParams) getResult() command.CommandResult {
+	return &MigrateResult{
+		From:    p.from.String(),
+		To:      p.engine.String(),
+		Entries: p.entries,
+		Backup:  p.backup,
+	}
+}
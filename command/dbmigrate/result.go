@@ -0,0 +1,31 @@
+package dbmigrate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type MigrateResult struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Entries uint64 `json:"entries"`
+	Backup  string `json:"backup"`
+}
+
+func (r *MigrateResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB MIGRATE]\n")
+	buffer.WriteString("Converted the blockchain database successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("From|%s", r.From),
+		fmt.Sprintf("To|%s", r.To),
+		fmt.Sprintf("Copied entries|%d", r.Entries),
+		fmt.Sprintf("Backup|%s", r.Backup),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
@@ -39,12 +40,19 @@ const (
 )
 
 // HandleSignals is a helper method for handling signals sent to the console
-// Like stop, error, etc.
+// Like stop, error, etc. SIGHUP is left to the closed instance if it handles it
 func HandleSignals(
 	closeFn func(),
 	outputter command.OutputFormatter,
+	handlesHangup bool,
 ) error {
-	signalCh := common.GetTerminationSignalCh()
+	var signalCh <-chan os.Signal
+
+	if handlesHangup {
+		signalCh = common.GetInterruptSignalCh()
+	} else {
+		signalCh = common.GetTerminationSignalCh()
+	}
 	sig := <-signalCh
 
 	closeMessage := fmt.Sprintf("\n[SIGNAL] Caught signal: %v\n", sig)
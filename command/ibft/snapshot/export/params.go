@@ -0,0 +1,63 @@
+package snapshotexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+const (
+	dataDirFlag = "data-dir"
+	outFlag     = "out"
+)
+
+var (
+	params = &exportParams{}
+)
+
+type exportParams struct {
+	dataDir string
+	out     string
+
+	export *ibft.SnapshotStoreExport
+}
+
+func (p *exportParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		outFlag,
+	}
+}
+
+func (p *exportParams) exportSnapshots() error {
+	export, err := ibft.ExportSnapshotStore(filepath.Join(p.dataDir, secrets.ConsensusFolderLocal))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(p.out, data, 0600); err != nil {
+		return fmt.Errorf("unable to write the snapshot file: %w", err)
+	}
+
+	p.export = export
+
+	return nil
+}
+
+func (p *exportParams) getResult() command.CommandResult {
+	return &SnapshotExportResult{
+		Out:       p.out,
+		LastBlock: p.export.LastBlock,
+		Snapshots: len(p.export.Snapshots),
+	}
+}
@@ -0,0 +1,29 @@
+package snapshotexport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SnapshotExportResult struct {
+	Out       string `json:"out"`
+	LastBlock uint64 `json:"lastBlock"`
+	Snapshots int    `json:"snapshots"`
+}
+
+func (r *SnapshotExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT SNAPSHOT EXPORT]\n")
+	buffer.WriteString("Exported snapshot store successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.Out),
+		fmt.Sprintf("Last block|%d", r.LastBlock),
+		fmt.Sprintf("Snapshots|%d", r.Snapshots),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
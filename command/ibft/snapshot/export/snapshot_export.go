@@ -0,0 +1,50 @@
+package snapshotexport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	snapshotExportCmd := &cobra.Command{
+		Use: "export",
+		Short: "Exports the IBFT snapshot store (validator sets and votes) from the data directory " +
+			"of a stopped node to a file",
+		Run: runCommand,
+	}
+
+	setFlags(snapshotExportCmd)
+	helper.SetRequiredFlags(snapshotExportCmd, params.getRequiredFlags())
+
+	return snapshotExportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.out,
+		outFlag,
+		"",
+		"the path of the exported snapshot file",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.exportSnapshots(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
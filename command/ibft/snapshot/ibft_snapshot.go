@@ -3,6 +3,8 @@ package snapshot
 import (
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	snapshotExport "github.com/0xPolygon/polygon-edge/command/ibft/snapshot/export"
+	snapshotImport "github.com/0xPolygon/polygon-edge/command/ibft/snapshot/import"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +17,20 @@ func GetCommand() *cobra.Command {
 
 	setFlags(ibftSnapshotCmd)
 
+	registerSubcommands(ibftSnapshotCmd)
+
 	return ibftSnapshotCmd
 }
 
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// ibft snapshot export
+		snapshotExport.GetCommand(),
+		// ibft snapshot import
+		snapshotImport.GetCommand(),
+	)
+}
+
 func setFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVar(
 		&params.blockNumber,
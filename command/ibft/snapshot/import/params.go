@@ -0,0 +1,69 @@
+package snapshotimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/secrets"
+)
+
+const (
+	dataDirFlag = "data-dir"
+	fileFlag    = "file"
+	forceFlag   = "force"
+)
+
+var (
+	params = &importParams{}
+)
+
+type importParams struct {
+	dataDir string
+	file    string
+	force   bool
+
+	export *ibft.SnapshotStoreExport
+}
+
+func (p *importParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		fileFlag,
+	}
+}
+
+func (p *importParams) importSnapshots() error {
+	data, err := ioutil.ReadFile(p.file)
+	if err != nil {
+		return fmt.Errorf("unable to read the snapshot file: %w", err)
+	}
+
+	export := &ibft.SnapshotStoreExport{}
+	if err := json.Unmarshal(data, export); err != nil {
+		return fmt.Errorf("unable to parse the snapshot file: %w", err)
+	}
+
+	if err := ibft.ImportSnapshotStore(
+		filepath.Join(p.dataDir, secrets.ConsensusFolderLocal),
+		export,
+		p.force,
+	); err != nil {
+		return err
+	}
+
+	p.export = export
+
+	return nil
+}
+
+func (p *importParams) getResult() command.CommandResult {
+	return &SnapshotImportResult{
+		DataDir:   p.dataDir,
+		LastBlock: p.export.LastBlock,
+		Snapshots: len(p.export.Snapshots),
+	}
+}
@@ -0,0 +1,29 @@
+package snapshotimport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SnapshotImportResult struct {
+	DataDir   string `json:"dataDir"`
+	LastBlock uint64 `json:"lastBlock"`
+	Snapshots int    `json:"snapshots"`
+}
+
+func (r *SnapshotImportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT SNAPSHOT IMPORT]\n")
+	buffer.WriteString("Imported snapshot store successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Data directory|%s", r.DataDir),
+		fmt.Sprintf("Last block|%d", r.LastBlock),
+		fmt.Sprintf("Snapshots|%d", r.Snapshots),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
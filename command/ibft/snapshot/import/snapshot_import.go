@@ -0,0 +1,57 @@
+package snapshotimport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	snapshotImportCmd := &cobra.Command{
+		Use: "import",
+		Short: "Imports an exported IBFT snapshot store into the data directory of a stopped node. " +
+			"The node processes the blocks after the last exported block on startup",
+		Run: runCommand,
+	}
+
+	setFlags(snapshotImportCmd)
+	helper.SetRequiredFlags(snapshotImportCmd, params.getRequiredFlags())
+
+	return snapshotImportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().StringVar(
+		&params.file,
+		fileFlag,
+		"",
+		"the path of the exported snapshot file",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.force,
+		forceFlag,
+		false,
+		"overwrite the existing snapshot store of the node",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.importSnapshots(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
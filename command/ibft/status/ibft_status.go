@@ -13,7 +13,7 @@ import (
 func GetCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "status",
-		Short: "Returns the current validator key of the IBFT client",
+		Short: "Returns the current validator key of the IBFT client and the reputation of its sync peers",
 		Run:   runCommand,
 	}
 }
@@ -29,9 +29,26 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		return
 	}
 
-	outputter.SetCommandResult(&IBFTStatusResult{
+	result := &IBFTStatusResult{
 		ValidatorKey: statusResponse.Key,
-	})
+		SyncPeers:    make([]SyncPeerScoreEntry, len(statusResponse.SyncPeers)),
+	}
+
+	for i, peer := range statusResponse.SyncPeers {
+		result.SyncPeers[i] = SyncPeerScoreEntry{
+			ID:             peer.Id,
+			Score:          peer.Score,
+			Timeouts:       peer.Timeouts,
+			InvalidBlocks:  peer.InvalidBlocks,
+			HashMismatches: peer.HashMismatches,
+			BannedUntil:    peer.BannedUntil,
+			LastErrorKind:  peer.LastErrorKind,
+			LastError:      peer.LastError,
+			LastErrorTime:  peer.LastErrorTime,
+		}
+	}
+
+	outputter.SetCommandResult(result)
 }
 
 func getIBFTStatus(grpcAddress string) (*ibftOp.IbftStatusResp, error) {
@@ -3,12 +3,26 @@ package status
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/command/helper"
 )
 
 type IBFTStatusResult struct {
-	ValidatorKey string `json:"validator_key"`
+	ValidatorKey string               `json:"validator_key"`
+	SyncPeers    []SyncPeerScoreEntry `json:"sync_peers"`
+}
+
+type SyncPeerScoreEntry struct {
+	ID             string `json:"id"`
+	Score          int64  `json:"score"`
+	Timeouts       uint64 `json:"timeouts"`
+	InvalidBlocks  uint64 `json:"invalid_blocks"`
+	HashMismatches uint64 `json:"hash_mismatches"`
+	BannedUntil    int64  `json:"banned_until,omitempty"`
+	LastErrorKind  string `json:"last_error_kind,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	LastErrorTime  int64  `json:"last_error_time,omitempty"`
 }
 
 func (r *IBFTStatusResult) GetOutput() string {
@@ -20,5 +34,39 @@ func (r *IBFTStatusResult) GetOutput() string {
 	}))
 	buffer.WriteString("\n")
 
+	if len(r.SyncPeers) == 0 {
+		return buffer.String()
+	}
+
+	rows := make([]string, len(r.SyncPeers)+1)
+	rows[0] = "ID|Score|Timeouts|Invalid Blocks|Hash Mismatches|Banned Until|Last Error"
+
+	for i, peer := range r.SyncPeers {
+		bannedUntil := "-"
+		if peer.BannedUntil != 0 {
+			bannedUntil = time.Unix(peer.BannedUntil, 0).Format(time.RFC3339)
+		}
+
+		// the kind only, the error message is in the JSON output
+		lastError := "-"
+		if peer.LastErrorKind != "" {
+			lastError = fmt.Sprintf("%s at %s", peer.LastErrorKind, time.Unix(peer.LastErrorTime, 0).Format(time.RFC3339))
+		}
+
+		rows[i+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%s|%s",
+			peer.ID,
+			peer.Score,
+			peer.Timeouts,
+			peer.InvalidBlocks,
+			peer.HashMismatches,
+			bannedUntil,
+			lastError,
+		)
+	}
+
+	buffer.WriteString("\n[SYNC PEERS]\n")
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
 	return buffer.String()
 }
@@ -0,0 +1,124 @@
+package loadtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	loadTestCmd := &cobra.Command{
+		Use: "loadtest",
+		Short: "Generates a transaction load against the network from funded test accounts, " +
+			"and reports the inclusion latency of the transactions",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(loadTestCmd)
+	helper.SetRequiredFlags(loadTestCmd, params.getRequiredFlags())
+
+	return loadTestCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&params.endpoints,
+		endpointFlag,
+		[]string{fmt.Sprintf("http://%s:%d", helper.LocalHostBinding, server.DefaultJSONRPCPort)},
+		"the JSON-RPC endpoints the transactions are sent to, each test account sending to one of them",
+	)
+
+	cmd.Flags().StringVar(
+		&params.funderRaw,
+		funderFlag,
+		"",
+		"the account funding the test accounts and deploying the contracts, its private key is read "+
+			"from the "+funderKeyEnvPrefix+"<address> environment variable",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.accounts,
+		accountsFlag,
+		10,
+		"the number of test accounts sending the transactions",
+	)
+
+	cmd.Flags().StringVar(
+		&params.fundRaw,
+		fundFlag,
+		"0xDE0B6B3A7640000",
+		"the value each test account is funded with in wei",
+	)
+	// override default value for help output
+	cmd.Flag(fundFlag).DefValue = "1000000000000000000"
+
+	cmd.Flags().Uint64Var(
+		&params.tps,
+		tpsFlag,
+		100,
+		"the target number of transactions sent per second",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.count,
+		countFlag,
+		1000,
+		"the number of transactions sent in total",
+	)
+
+	cmd.Flags().StringVar(
+		&params.mixRaw,
+		mixFlag,
+		string(transfer),
+		"the share of each kind of transaction [transfer, erc721, call], e.g. transfer=70,erc721=20,call=10",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.chainID,
+		chainIDFlag,
+		100,
+		"the network chain ID",
+	)
+
+	cmd.Flags().StringVar(
+		&params.gasPriceRaw,
+		gasPriceFlag,
+		"",
+		"the gas price of the transactions. If omitted, the gas price is fetched from the network",
+	)
+
+	cmd.Flags().DurationVar(
+		&params.maxWait,
+		maxWaitFlag,
+		2*time.Minute,
+		"the maximum time the transactions are waited for after the last one is sent",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	if err := params.validateFlags(); err != nil {
+		return err
+	}
+
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	loadTest := NewLoadTest(params.generateConfig())
+
+	if err := loadTest.Run(); err != nil {
+		outputter.SetError(fmt.Errorf("an error occurred while running the load test: %w", err))
+
+		return
+	}
+
+	outputter.SetCommandResult(newLoadTestResult(loadTest))
+}
@@ -0,0 +1,101 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TxKind is a kind of the transactions sent by the load test
+type TxKind string
+
+const (
+	transfer   TxKind = "transfer" // value transfers between the test accounts
+	erc721Mint TxKind = "erc721"   // mints of an ERC-721 token
+	call       TxKind = "call"     // calls of an ERC-20 contract, approving an allowance
+)
+
+var errInvalidMix = errors.New("invalid transaction mix, expected kind=weight pairs like transfer=70,erc721=20,call=10")
+
+// txMix is the share of each kind of transaction in the load
+type txMix struct {
+	kinds   []TxKind
+	weights []uint64
+	total   uint64
+}
+
+// parseTxMix parses a mix of comma separated kind=weight pairs.
+// A kind without a weight has a weight of one
+func parseTxMix(raw string) (*txMix, error) {
+	mix := &txMix{}
+	seen := make(map[TxKind]bool)
+
+	for _, pair := range strings.Split(raw, ",") {
+		kindRaw, weightRaw := strings.TrimSpace(pair), "1"
+
+		if i := strings.Index(pair, "="); i >= 0 {
+			kindRaw, weightRaw = strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		}
+
+		kind := TxKind(strings.ToLower(kindRaw))
+
+		switch kind {
+		case transfer, erc721Mint, call:
+		default:
+			return nil, fmt.Errorf("%w: unknown kind %q", errInvalidMix, kindRaw)
+		}
+
+		if seen[kind] {
+			return nil, fmt.Errorf("%w: duplicate kind %q", errInvalidMix, kindRaw)
+		}
+
+		weight, err := strconv.ParseUint(weightRaw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid weight %q", errInvalidMix, weightRaw)
+		}
+
+		if weight == 0 {
+			continue
+		}
+
+		seen[kind] = true
+
+		mix.kinds = append(mix.kinds, kind)
+		mix.weights = append(mix.weights, weight)
+		mix.total += weight
+	}
+
+	if mix.total == 0 {
+		return nil, errInvalidMix
+	}
+
+	return mix, nil
+}
+
+// has returns whether the mix has transactions of the kind
+func (m *txMix) has(kind TxKind) bool {
+	for _, k := range m.kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kind returns the kind of the i-th transaction of the load, so that
+// each run of total transactions has the share of each kind
+func (m *txMix) kind(i uint64) TxKind {
+	slot := i % m.total
+
+	for j, weight := range m.weights {
+		if slot < weight {
+			return m.kinds[j]
+		}
+
+		slot -= weight
+	}
+
+	return m.kinds[len(m.kinds)-1]
+}
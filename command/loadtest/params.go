@@ -0,0 +1,125 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	params = &loadTestParams{}
+)
+
+var (
+	errNoEndpoints = errors.New("at least one JSON-RPC endpoint is required")
+	errInvalidTPS  = errors.New("the target tps must be greater than zero")
+	errNoAccounts  = errors.New("at least one test account is required")
+	errNoCount     = errors.New("at least one transaction has to be sent")
+)
+
+const (
+	endpointFlag = "endpoint"
+	funderFlag   = "funder"
+	accountsFlag = "accounts"
+	fundFlag     = "fund"
+	tpsFlag      = "tps"
+	countFlag    = "count"
+	mixFlag      = "mix"
+	chainIDFlag  = "chain-id"
+	gasPriceFlag = "gas-price"
+	maxWaitFlag  = "max-wait"
+)
+
+type loadTestParams struct {
+	endpoints []string
+	accounts  uint64
+	tps       uint64
+	count     uint64
+	chainID   uint64
+	maxWait   time.Duration
+
+	funderRaw   string
+	fundRaw     string
+	mixRaw      string
+	gasPriceRaw string
+
+	funder   types.Address
+	fund     *big.Int
+	gasPrice *big.Int
+	mix      *txMix
+}
+
+func (p *loadTestParams) getRequiredFlags() []string {
+	return []string{
+		funderFlag,
+	}
+}
+
+func (p *loadTestParams) validateFlags() error {
+	if len(p.endpoints) == 0 {
+		return errNoEndpoints
+	}
+
+	for _, endpoint := range p.endpoints {
+		if _, err := url.ParseRequestURI(endpoint); err != nil {
+			return fmt.Errorf("invalid endpoint %s: %w", endpoint, err)
+		}
+	}
+
+	if p.tps == 0 {
+		return errInvalidTPS
+	}
+
+	if p.accounts == 0 {
+		return errNoAccounts
+	}
+
+	if p.count == 0 {
+		return errNoCount
+	}
+
+	return nil
+}
+
+func (p *loadTestParams) initRawParams() error {
+	var err error
+
+	if err = p.funder.UnmarshalText([]byte(p.funderRaw)); err != nil {
+		return fmt.Errorf("failed to decode funder address: %w", err)
+	}
+
+	if p.fund, err = types.ParseUint256orHex(&p.fundRaw); err != nil {
+		return fmt.Errorf("failed to decode the funded value: %w", err)
+	}
+
+	if p.gasPriceRaw != "" {
+		if p.gasPrice, err = types.ParseUint256orHex(&p.gasPriceRaw); err != nil {
+			return fmt.Errorf("failed to decode gas price to value: %w", err)
+		}
+	}
+
+	if p.mix, err = parseTxMix(p.mixRaw); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *loadTestParams) generateConfig() *Configuration {
+	return &Configuration{
+		Endpoints: p.endpoints,
+		Funder:    p.funder,
+		Accounts:  p.accounts,
+		Fund:      p.fund,
+		TPS:       p.tps,
+		Count:     p.count,
+		Mix:       p.mix,
+		ChainID:   p.chainID,
+		GasPrice:  p.gasPrice,
+		MaxWait:   p.maxWait,
+	}
+}
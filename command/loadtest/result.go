@@ -0,0 +1,177 @@
+package loadtest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+)
+
+const (
+	durationPrecision = 5
+)
+
+// LatencyPercentiles are the percentiles of the inclusion latency in seconds
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type TxKindResult struct {
+	Kind     TxKind             `json:"kind"`
+	Sent     uint64             `json:"sent"`
+	Failed   uint64             `json:"failed"`
+	Included uint64             `json:"included"`
+	Latency  LatencyPercentiles `json:"latency"`
+}
+
+type LoadTestResult struct {
+	Endpoints   []string `json:"endpoints"`
+	Accounts    uint64   `json:"accounts"`
+	TargetTPS   uint64   `json:"target_tps"`
+	SendTPS     float64  `json:"send_tps"`
+	IncludedTPS float64  `json:"included_tps"`
+
+	Sent        uint64 `json:"sent"`
+	Failed      uint64 `json:"failed"`
+	Included    uint64 `json:"included"`
+	NotIncluded uint64 `json:"not_included"`
+
+	// Duration is the time from the first sent transaction to the last inclusion, in seconds
+	Duration float64            `json:"duration"`
+	Latency  LatencyPercentiles `json:"latency"`
+	Kinds    []TxKindResult     `json:"kinds"`
+}
+
+func newLoadTestResult(l *LoadTest) *LoadTestResult {
+	res := &LoadTestResult{
+		Endpoints: l.cfg.Endpoints,
+		Accounts:  l.cfg.Accounts,
+		TargetTPS: l.cfg.TPS,
+		Duration:  common.ToFixedFloat(l.totalDuration.Seconds(), durationPrecision),
+		Kinds:     make([]TxKindResult, 0, len(l.cfg.Mix.kinds)),
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	all := make([]time.Duration, 0)
+
+	for _, kind := range l.cfg.Mix.kinds {
+		stats := l.stats[kind]
+
+		res.Kinds = append(res.Kinds, TxKindResult{
+			Kind:     kind,
+			Sent:     stats.sent,
+			Failed:   stats.failed,
+			Included: uint64(len(stats.latencies)),
+			Latency:  newLatencyPercentiles(stats.latencies),
+		})
+
+		res.Sent += stats.sent
+		res.Failed += stats.failed
+		res.Included += uint64(len(stats.latencies))
+
+		all = append(all, stats.latencies...)
+	}
+
+	res.NotIncluded = uint64(len(l.pending))
+	res.Latency = newLatencyPercentiles(all)
+
+	if l.sendDuration > 0 {
+		res.SendTPS = common.ToFixedFloat(float64(res.Sent)/l.sendDuration.Seconds(), durationPrecision)
+	}
+
+	if l.totalDuration > 0 {
+		res.IncludedTPS = common.ToFixedFloat(float64(res.Included)/l.totalDuration.Seconds(), durationPrecision)
+	}
+
+	return res
+}
+
+func newLatencyPercentiles(latencies []time.Duration) LatencyPercentiles {
+	if len(latencies) == 0 {
+		return LatencyPercentiles{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return LatencyPercentiles{
+		P50: percentile(sorted, 50),
+		P90: percentile(sorted, 90),
+		P99: percentile(sorted, 99),
+		Max: common.ToFixedFloat(sorted[len(sorted)-1].Seconds(), durationPrecision),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies in seconds
+func percentile(sorted []time.Duration, p int) float64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return common.ToFixedFloat(sorted[rank-1].Seconds(), durationPrecision)
+}
+
+func (r *LoadTestResult) writeLatency(buffer *bytes.Buffer, latency LatencyPercentiles) {
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Latency p50|%fs", latency.P50),
+		fmt.Sprintf("Latency p90|%fs", latency.P90),
+		fmt.Sprintf("Latency p99|%fs", latency.P99),
+		fmt.Sprintf("Latency max|%fs", latency.Max),
+	}))
+}
+
+func (r *LoadTestResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n=====[LOAD TEST RUN]=====\n")
+
+	buffer.WriteString("\n[RUN DATA]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Endpoints|%d", len(r.Endpoints)),
+		fmt.Sprintf("Test accounts|%d", r.Accounts),
+		fmt.Sprintf("Target TPS|%d", r.TargetTPS),
+		fmt.Sprintf("Send TPS|%f", r.SendTPS),
+		fmt.Sprintf("Included TPS|%f", r.IncludedTPS),
+		fmt.Sprintf("Duration|%fs", r.Duration),
+	}))
+
+	buffer.WriteString("\n\n[COUNT DATA]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Transactions sent|%d", r.Sent),
+		fmt.Sprintf("Transactions failed|%d", r.Failed),
+		fmt.Sprintf("Transactions included|%d", r.Included),
+		fmt.Sprintf("Transactions not included|%d", r.NotIncluded),
+	}))
+
+	buffer.WriteString("\n\n[LATENCY DATA]\n")
+	r.writeLatency(&buffer, r.Latency)
+
+	for _, kind := range r.Kinds {
+		buffer.WriteString(fmt.Sprintf("\n\n[%s TRANSACTIONS]\n", strings.ToUpper(string(kind.Kind))))
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Sent|%d", kind.Sent),
+			fmt.Sprintf("Failed|%d", kind.Failed),
+			fmt.Sprintf("Included|%d", kind.Included),
+		}))
+		buffer.WriteString("\n")
+		r.writeLatency(&buffer, kind.Latency)
+	}
+
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
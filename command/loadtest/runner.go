@@ -0,0 +1,604 @@
+package loadtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/loadbot"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+	"github.com/umbracle/ethgo/jsonrpc"
+)
+
+// funderKeyEnvPrefix is the prefix of the environment variable holding the private key
+// of the funder, followed by its address
+const funderKeyEnvPrefix = "LOADTEST_"
+
+const (
+	// blockPollInterval is the interval the new blocks are polled at, to find the included transactions
+	blockPollInterval = 250 * time.Millisecond
+
+	// gasMargin is the margin added to the gas estimates in percents,
+	// as the cost of the transactions changes with the state of the contracts
+	gasMargin = 20
+
+	transferGas = 21000
+
+	erc721TokenName   = "LoadTestNFT"
+	erc721TokenSymbol = "LTNFT"
+	erc721TokenURI    = "https://load-test.polygon-edge.io/token"
+
+	erc20TokenSupply = 1000000000
+	erc20TokenName   = "LoadTestToken"
+	erc20TokenSymbol = "LTT"
+)
+
+var errReverted = errors.New("transaction reverted")
+
+// Configuration is the configuration of a load test run
+type Configuration struct {
+	Endpoints []string
+	Funder    types.Address
+	Accounts  uint64
+	Fund      *big.Int
+	TPS       uint64
+	Count     uint64
+	Mix       *txMix
+	ChainID   uint64
+	GasPrice  *big.Int
+	MaxWait   time.Duration
+}
+
+// testAccount is an account sending a part of the load to one of the endpoints,
+// so that its transactions reach the network in the order of their nonces
+type testAccount struct {
+	// lock serializes the transactions of the account, the nonce is only used by a sent one
+	lock sync.Mutex
+
+	address types.Address
+	key     *ecdsa.PrivateKey
+	nonce   uint64
+	client  *jsonrpc.Client
+
+	// peer is the account receiving the transfers of the account
+	peer types.Address
+}
+
+// sentTxn is a sent transaction waiting for its inclusion
+type sentTxn struct {
+	kind   TxKind
+	sentAt time.Time
+}
+
+// kindStats are the outcomes of the transactions of a kind
+type kindStats struct {
+	sent      uint64
+	failed    uint64
+	latencies []time.Duration
+}
+
+// LoadTest sends a mix of transactions at a target rate from funded test accounts,
+// and measures the time the transactions take to be included in a block
+type LoadTest struct {
+	cfg *Configuration
+
+	clients  []*jsonrpc.Client
+	signer   *crypto.EIP155Signer
+	gasPrice *big.Int
+	accounts []*testAccount
+
+	// the contract, input and gas of each kind of transaction
+	contracts map[TxKind]types.Address
+	inputs    map[TxKind][]byte
+	gas       map[TxKind]uint64
+
+	// lock guards the pending transactions and the stats
+	lock    sync.Mutex
+	pending map[types.Hash]*sentTxn
+	stats   map[TxKind]*kindStats
+
+	// sendDuration is the time spent sending the load, totalDuration includes the wait for the inclusions
+	sendDuration  time.Duration
+	totalDuration time.Duration
+}
+
+func NewLoadTest(cfg *Configuration) *LoadTest {
+	stats := make(map[TxKind]*kindStats, len(cfg.Mix.kinds))
+	for _, kind := range cfg.Mix.kinds {
+		stats[kind] = &kindStats{}
+	}
+
+	return &LoadTest{
+		cfg:       cfg,
+		signer:    crypto.NewEIP155Signer(cfg.ChainID),
+		contracts: make(map[TxKind]types.Address),
+		inputs:    make(map[TxKind][]byte),
+		gas:       make(map[TxKind]uint64),
+		pending:   make(map[types.Hash]*sentTxn),
+		stats:     stats,
+	}
+}
+
+// Run sets the test accounts and the contracts up, then sends the load
+func (l *LoadTest) Run() error {
+	for _, endpoint := range l.cfg.Endpoints {
+		client, err := jsonrpc.NewClient(endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to create the JSON-RPC client of %s: %w", endpoint, err)
+		}
+
+		defer client.Close()
+
+		l.clients = append(l.clients, client)
+	}
+
+	funder, err := l.newFunder()
+	if err != nil {
+		return err
+	}
+
+	l.gasPrice = l.cfg.GasPrice
+	if l.gasPrice == nil {
+		gasPrice, err := l.clients[0].Eth().GasPrice()
+		if err != nil {
+			return fmt.Errorf("failed to query the gas price: %w", err)
+		}
+
+		l.gasPrice = new(big.Int).SetUint64(gasPrice)
+	}
+
+	if err := l.deployContracts(funder); err != nil {
+		return err
+	}
+
+	if err := l.fundAccounts(funder); err != nil {
+		return err
+	}
+
+	if err := l.estimateGas(); err != nil {
+		return err
+	}
+
+	return l.sendLoad()
+}
+
+// newFunder returns the funder account, whose key is read from the environment
+func (l *LoadTest) newFunder() (*testAccount, error) {
+	keyRaw := strings.TrimPrefix(os.Getenv(funderKeyEnvPrefix+l.cfg.Funder.String()), "0x")
+
+	key, err := crypto.BytesToPrivateKey([]byte(keyRaw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the funder private key: %w", err)
+	}
+
+	nonce, err := l.clients[0].Eth().GetNonce(ethgo.Address(l.cfg.Funder), ethgo.Latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the funder nonce: %w", err)
+	}
+
+	return &testAccount{
+		address: l.cfg.Funder,
+		key:     key,
+		nonce:   nonce,
+		client:  l.clients[0],
+	}, nil
+}
+
+// deployContracts deploys the contracts called by the mix
+func (l *LoadTest) deployContracts(funder *testAccount) error {
+	if l.cfg.Mix.has(erc721Mint) {
+		addr, contractABI, err := l.deploy(
+			funder,
+			loadbot.ERC721BIN,
+			loadbot.ERC721ABI,
+			[]interface{}{erc721TokenName, erc721TokenSymbol},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to deploy the ERC-721 contract: %w", err)
+		}
+
+		input, err := contractABI.Methods["createNFT"].Encode([]interface{}{erc721TokenURI})
+		if err != nil {
+			return fmt.Errorf("failed to encode the ERC-721 mint: %w", err)
+		}
+
+		l.contracts[erc721Mint] = addr
+		l.inputs[erc721Mint] = input
+	}
+
+	if l.cfg.Mix.has(call) {
+		addr, contractABI, err := l.deploy(
+			funder,
+			loadbot.ERC20BIN,
+			loadbot.ERC20ABI,
+			[]interface{}{big.NewInt(erc20TokenSupply), erc20TokenName, erc20TokenSymbol},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to deploy the ERC-20 contract: %w", err)
+		}
+
+		// the allowance is approved without any balance, so that each account can call it
+		input, err := contractABI.Methods["approve"].Encode(
+			[]interface{}{ethgo.Address(funder.address), big.NewInt(1)},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to encode the ERC-20 call: %w", err)
+		}
+
+		l.contracts[call] = addr
+		l.inputs[call] = input
+	}
+
+	return nil
+}
+
+// deploy deploys the contract from the funder, and returns its address and ABI
+func (l *LoadTest) deploy(
+	funder *testAccount,
+	bytecodeRaw string,
+	abiRaw string,
+	constructorArgs []interface{},
+) (types.Address, *abi.ABI, error) {
+	contractABI, err := abi.NewABI(abiRaw)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	bytecode, err := hex.DecodeString(bytecodeRaw)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	args, err := abi.Encode(constructorArgs, contractABI.Constructor.Inputs)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	input := append(bytecode, args...)
+
+	gas, err := l.estimate(funder.address, nil, big.NewInt(0), input)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	hash, err := l.send(funder, nil, big.NewInt(0), input, gas)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	receipt, err := l.waitForReceipt(hash)
+	if err != nil {
+		return types.ZeroAddress, nil, err
+	}
+
+	return types.Address(receipt.ContractAddress), contractABI, nil
+}
+
+// fundAccounts creates the test accounts and funds them from the funder,
+// each account sends to one of the endpoints
+func (l *LoadTest) fundAccounts(funder *testAccount) error {
+	hashes := make([]types.Hash, 0, l.cfg.Accounts)
+
+	for i := uint64(0); i < l.cfg.Accounts; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		account := &testAccount{
+			address: crypto.PubKeyToAddress(&key.PublicKey),
+			key:     key,
+			client:  l.clients[i%uint64(len(l.clients))],
+		}
+
+		hash, err := l.send(funder, &account.address, l.cfg.Fund, nil, transferGas)
+		if err != nil {
+			return fmt.Errorf("failed to fund test account %s: %w", account.address, err)
+		}
+
+		hashes = append(hashes, hash)
+		l.accounts = append(l.accounts, account)
+	}
+
+	// each account transfers to the next one
+	for i, account := range l.accounts {
+		account.peer = l.accounts[(i+1)%len(l.accounts)].address
+	}
+
+	for _, hash := range hashes {
+		if _, err := l.waitForReceipt(hash); err != nil {
+			return fmt.Errorf("failed to fund the test accounts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// estimateGas estimates the gas of each kind of transaction with the first test account
+func (l *LoadTest) estimateGas() error {
+	sample := l.accounts[0]
+
+	for _, kind := range l.cfg.Mix.kinds {
+		to, value, input := l.target(sample, kind)
+
+		gas, err := l.estimate(sample.address, to, value, input)
+		if err != nil {
+			return fmt.Errorf("failed to estimate the gas of the %s transactions: %w", kind, err)
+		}
+
+		l.gas[kind] = gas
+	}
+
+	return nil
+}
+
+// estimate returns the gas estimate of the transaction, with the margin
+func (l *LoadTest) estimate(from types.Address, to *types.Address, value *big.Int, input []byte) (uint64, error) {
+	gas, err := l.clients[0].Eth().EstimateGas(&ethgo.CallMsg{
+		From:     ethgo.Address(from),
+		To:       (*ethgo.Address)(to),
+		Data:     input,
+		GasPrice: l.gasPrice.Uint64(),
+		Value:    value,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return gas + gas*gasMargin/100, nil
+}
+
+// target returns the recipient, the value and the input of a transaction of the kind sent by the account
+func (l *LoadTest) target(account *testAccount, kind TxKind) (*types.Address, *big.Int, []byte) {
+	if kind == transfer {
+		return &account.peer, big.NewInt(1), nil
+	}
+
+	contract := l.contracts[kind]
+
+	return &contract, big.NewInt(0), l.inputs[kind]
+}
+
+// send signs and sends the transaction of the account to its endpoint
+func (l *LoadTest) send(
+	account *testAccount,
+	to *types.Address,
+	value *big.Int,
+	input []byte,
+	gas uint64,
+) (types.Hash, error) {
+	account.lock.Lock()
+	defer account.lock.Unlock()
+
+	txn, err := l.signTxn(account, to, value, input, gas)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	if _, err := account.client.Eth().SendRawTransaction(txn.MarshalRLP()); err != nil {
+		return types.ZeroHash, err
+	}
+
+	account.nonce++
+
+	return txn.Hash, nil
+}
+
+// signTxn signs the next transaction of the account, the account lock is held by the caller
+func (l *LoadTest) signTxn(
+	account *testAccount,
+	to *types.Address,
+	value *big.Int,
+	input []byte,
+	gas uint64,
+) (*types.Transaction, error) {
+	txn, err := l.signer.SignTx(&types.Transaction{
+		Nonce:    account.nonce,
+		GasPrice: l.gasPrice,
+		Gas:      gas,
+		To:       to,
+		Value:    value,
+		Input:    input,
+		V:        big.NewInt(1), // it is necessary to encode in rlp
+	}, account.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return txn.ComputeHash(), nil
+}
+
+// waitForReceipt waits for the receipt of a setup transaction, which has to succeed
+func (l *LoadTest) waitForReceipt(hash types.Hash) (*ethgo.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.cfg.MaxWait)
+	defer cancel()
+
+	receipt, err := tests.WaitForReceipt(ctx, l.clients[0].Eth(), ethgo.Hash(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt of %s: %w", hash, err)
+	}
+
+	if receipt.Status != 1 {
+		return nil, fmt.Errorf("%w: %s", errReverted, hash)
+	}
+
+	return receipt, nil
+}
+
+// sendLoad sends the transactions at the target rate, the accounts taking turns,
+// then waits for their inclusion up to the maximum wait
+func (l *LoadTest) sendLoad() error {
+	head, err := l.clients[0].Eth().BlockNumber()
+	if err != nil {
+		return fmt.Errorf("failed to query the latest block: %w", err)
+	}
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	watchDoneCh := make(chan struct{})
+
+	go func() {
+		defer close(watchDoneCh)
+
+		l.watchBlocks(watchCtx, head+1)
+	}()
+
+	ticker := time.NewTicker(time.Second / time.Duration(l.cfg.TPS))
+	defer ticker.Stop()
+
+	var (
+		start = time.Now()
+		wg    sync.WaitGroup
+	)
+
+	for i := uint64(0); i < l.cfg.Count; i++ {
+		<-ticker.C
+
+		wg.Add(1)
+
+		go func(account *testAccount, kind TxKind) {
+			defer wg.Done()
+
+			l.sendKind(account, kind)
+		}(l.accounts[i%uint64(len(l.accounts))], l.cfg.Mix.kind(i))
+	}
+
+	wg.Wait()
+
+	l.sendDuration = time.Since(start)
+
+	// wait for the inclusion of the pending transactions
+	deadline := time.After(l.cfg.MaxWait)
+
+	for l.hasPending() {
+		select {
+		case <-deadline:
+			stopWatch()
+			<-watchDoneCh
+
+			l.totalDuration = time.Since(start)
+
+			return nil
+		case <-time.After(blockPollInterval):
+		}
+	}
+
+	stopWatch()
+	<-watchDoneCh
+
+	l.totalDuration = time.Since(start)
+
+	return nil
+}
+
+// sendKind sends a transaction of the kind from the account, tracked from the moment it is sent
+func (l *LoadTest) sendKind(account *testAccount, kind TxKind) {
+	to, value, input := l.target(account, kind)
+
+	account.lock.Lock()
+	defer account.lock.Unlock()
+
+	txn, err := l.signTxn(account, to, value, input, l.gas[kind])
+	if err == nil {
+		// tracked before it is sent, so that its block is never seen first
+		l.track(txn.Hash, kind)
+
+		if _, err = account.client.Eth().SendRawTransaction(txn.MarshalRLP()); err != nil {
+			l.untrack(txn.Hash)
+		} else {
+			account.nonce++
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	stats := l.stats[kind]
+	stats.sent++
+
+	if err != nil {
+		stats.failed++
+	}
+}
+
+func (l *LoadTest) track(hash types.Hash, kind TxKind) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.pending[hash] = &sentTxn{
+		kind:   kind,
+		sentAt: time.Now(),
+	}
+}
+
+func (l *LoadTest) untrack(hash types.Hash) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	delete(l.pending, hash)
+}
+
+func (l *LoadTest) hasPending() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return len(l.pending) > 0
+}
+
+// watchBlocks polls the blocks from the given number, and records the inclusion
+// latency of the sent transactions they include, until the context is done
+func (l *LoadTest) watchBlocks(ctx context.Context, next uint64) {
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		latest, err := l.clients[0].Eth().BlockNumber()
+		if err != nil {
+			continue
+		}
+
+		for ; next <= latest; next++ {
+			block, err := l.clients[0].Eth().GetBlockByNumber(ethgo.BlockNumber(next), false)
+			if err != nil || block == nil {
+				// the block is fetched again with the next poll
+				break
+			}
+
+			l.markIncluded(block.TransactionsHashes, time.Now())
+		}
+	}
+}
+
+// markIncluded records the inclusion latency of the sent transactions of a block
+func (l *LoadTest) markIncluded(hashes []ethgo.Hash, includedAt time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, hash := range hashes {
+		txn, ok := l.pending[types.Hash(hash)]
+		if !ok {
+			continue
+		}
+
+		delete(l.pending, types.Hash(hash))
+
+		stats := l.stats[txn.kind]
+		stats.latencies = append(stats.latencies, includedAt.Sub(txn.sentAt))
+	}
+}
@@ -0,0 +1,68 @@
+package gossip
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	params = &gossipParams{}
+)
+
+type gossipParams struct {
+	stats *proto.GossipStatsResponse
+}
+
+func (p *gossipParams) initStats(grpcAddress string) error {
+	systemClient, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	stats, err := systemClient.GossipStats(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	p.stats = stats
+
+	return nil
+}
+
+func (p *gossipParams) getResult() command.CommandResult {
+	result := &PeersGossipResult{
+		Topics:        make([]TopicGossipStats, len(p.stats.Topics)),
+		IHaveReceived: p.stats.IhaveReceived,
+		IHaveSent:     p.stats.IhaveSent,
+		IWantReceived: p.stats.IwantReceived,
+		IWantSent:     p.stats.IwantSent,
+	}
+
+	for i, topic := range p.stats.Topics {
+		result.Topics[i] = TopicGossipStats{
+			Topic:          topic.Topic,
+			MeshPeers:      topic.MeshPeers,
+			Delivered:      topic.Delivered,
+			Duplicates:     topic.Duplicates,
+			DuplicateRatio: duplicateRatio(topic.Delivered, topic.Duplicates),
+			DeliveryP50:    topic.DeliveryP50Us,
+			DeliveryP90:    topic.DeliveryP90Us,
+			DeliveryMax:    topic.DeliveryMaxUs,
+		}
+	}
+
+	return result
+}
+
+// duplicateRatio returns the share of the duplicates in the received messages
+func duplicateRatio(delivered, duplicates uint64) float64 {
+	if delivered+duplicates == 0 {
+		return 0
+	}
+
+	return float64(duplicates) / float64(delivered+duplicates)
+}
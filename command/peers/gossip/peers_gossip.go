@@ -0,0 +1,29 @@
+package gossip
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "gossip",
+		Short: "Returns the gossipsub mesh statistics of the node: the mesh peers, the delivered and duplicate " +
+			"messages and the delivery latencies of each topic, and the IHAVE/IWANT gossip counts",
+		Run: runCommand,
+	}
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.initStats(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,73 @@
+package gossip
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PeersGossipResult struct {
+	Topics        []TopicGossipStats `json:"topics"`
+	IHaveReceived uint64             `json:"ihave_received"`
+	IHaveSent     uint64             `json:"ihave_sent"`
+	IWantReceived uint64             `json:"iwant_received"`
+	IWantSent     uint64             `json:"iwant_sent"`
+}
+
+// TopicGossipStats are the gossipsub statistics of a topic, the delivery latencies in microseconds
+type TopicGossipStats struct {
+	Topic          string  `json:"topic"`
+	MeshPeers      uint64  `json:"mesh_peers"`
+	Delivered      uint64  `json:"delivered"`
+	Duplicates     uint64  `json:"duplicates"`
+	DuplicateRatio float64 `json:"duplicate_ratio"`
+	DeliveryP50    uint64  `json:"delivery_p50_us"`
+	DeliveryP90    uint64  `json:"delivery_p90_us"`
+	DeliveryMax    uint64  `json:"delivery_max_us"`
+}
+
+func (r *PeersGossipResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[GOSSIP]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("IHAVE Received|%d", r.IHaveReceived),
+		fmt.Sprintf("IHAVE Sent|%d", r.IHaveSent),
+		fmt.Sprintf("IWANT Received|%d", r.IWantReceived),
+		fmt.Sprintf("IWANT Sent|%d", r.IWantSent),
+	}))
+	buffer.WriteString("\n\n[GOSSIP TOPICS]\n")
+
+	if len(r.Topics) == 0 {
+		buffer.WriteString("No topics joined\n")
+
+		return buffer.String()
+	}
+
+	rows := make([]string, 0, len(r.Topics)+1)
+	rows = append(rows, "Topic|Mesh Peers|Delivered|Duplicates|Duplicate Ratio|P50|P90|Max")
+
+	for _, topic := range r.Topics {
+		rows = append(rows, fmt.Sprintf("%s|%d|%d|%d|%.2f%%|%s|%s|%s",
+			topic.Topic,
+			topic.MeshPeers,
+			topic.Delivered,
+			topic.Duplicates,
+			100*topic.DuplicateRatio,
+			formatLatency(topic.DeliveryP50),
+			formatLatency(topic.DeliveryP90),
+			formatLatency(topic.DeliveryMax),
+		))
+	}
+
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
+
+// formatLatency formats a latency in microseconds as milliseconds
+func formatLatency(us uint64) string {
+	return fmt.Sprintf("%.2fms", float64(us)/1000)
+}
@@ -0,0 +1,76 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+var (
+	params = &latencyParams{}
+)
+
+const (
+	countFlag = "count"
+)
+
+type latencyParams struct {
+	count uint64
+
+	latencies []*proto.PeerLatency
+}
+
+func (p *latencyParams) initLatencies(grpcAddress string) error {
+	systemClient, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	resp, err := systemClient.PeersLatency(
+		context.Background(),
+		&proto.PeersLatencyRequest{
+			Count: p.count,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	p.latencies = resp.Peers
+
+	return nil
+}
+
+func (p *latencyParams) getResult() command.CommandResult {
+	result := &PeersLatencyResult{
+		Peers: make([]PeerLatencyEntry, len(p.latencies)),
+	}
+
+	for i, latency := range p.latencies {
+		result.Peers[i] = PeerLatencyEntry{
+			ID:     latency.Id,
+			Libp2p: toLatencyStats(latency.Libp2P),
+			Sync:   toLatencyStats(latency.Sync),
+		}
+	}
+
+	return result
+}
+
+func toLatencyStats(stats *proto.LatencyStats) LatencyStats {
+	if stats == nil {
+		return LatencyStats{}
+	}
+
+	return LatencyStats{
+		Received: stats.Received,
+		Failed:   stats.Failed,
+		P50:      stats.P50Us,
+		P90:      stats.P90Us,
+		P99:      stats.P99Us,
+		Max:      stats.MaxUs,
+		Error:    stats.Error,
+	}
+}
@@ -0,0 +1,42 @@
+package latency
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	peersLatencyCmd := &cobra.Command{
+		Use: "latency",
+		Short: "Pings the connected peers with the libp2p ping and the sync protocol status requests, " +
+			"and returns the round trip time percentiles of each peer",
+		Run: runCommand,
+	}
+
+	setFlags(peersLatencyCmd)
+
+	return peersLatencyCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&params.count,
+		countFlag,
+		10,
+		"the number of pings sent to each peer with each protocol",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.initLatencies(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
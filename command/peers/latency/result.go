@@ -0,0 +1,88 @@
+package latency
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PeersLatencyResult struct {
+	Peers []PeerLatencyEntry `json:"peers"`
+}
+
+type PeerLatencyEntry struct {
+	ID     string       `json:"id"`
+	Libp2p LatencyStats `json:"libp2p"`
+	Sync   LatencyStats `json:"sync"`
+}
+
+// LatencyStats are the round trip time percentiles of the pings of a protocol, in microseconds
+type LatencyStats struct {
+	Received uint64 `json:"received"`
+	Failed   uint64 `json:"failed"`
+	P50      uint64 `json:"p50_us"`
+	P90      uint64 `json:"p90_us"`
+	P99      uint64 `json:"p99_us"`
+	Max      uint64 `json:"max_us"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (r *PeersLatencyResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[PEERS LATENCY]\n")
+
+	if len(r.Peers) == 0 {
+		buffer.WriteString("No peers found\n")
+
+		return buffer.String()
+	}
+
+	rows := make([]string, 0, 2*len(r.Peers)+1)
+	rows = append(rows, "ID|Protocol|Received|Failed|P50|P90|P99|Max|Error")
+
+	for _, peer := range r.Peers {
+		rows = append(rows,
+			formatLatencyRow(peer.ID, "libp2p", peer.Libp2p),
+			formatLatencyRow(peer.ID, "sync", peer.Sync),
+		)
+	}
+
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
+
+func formatLatencyRow(id, protocol string, stats LatencyStats) string {
+	errMsg := "-"
+	if stats.Error != "" {
+		errMsg = stats.Error
+	}
+
+	// the percentiles are unknown if no ping was answered
+	percentiles := "-|-|-|-"
+	if stats.Received > 0 {
+		percentiles = fmt.Sprintf("%s|%s|%s|%s",
+			formatRTT(stats.P50),
+			formatRTT(stats.P90),
+			formatRTT(stats.P99),
+			formatRTT(stats.Max),
+		)
+	}
+
+	return fmt.Sprintf("%s|%s|%d|%d|%s|%s",
+		id,
+		protocol,
+		stats.Received,
+		stats.Failed,
+		percentiles,
+		errMsg,
+	)
+}
+
+// formatRTT formats a round trip time in microseconds as milliseconds
+func formatRTT(us uint64) string {
+	return fmt.Sprintf("%.2fms", float64(us)/1000)
+}
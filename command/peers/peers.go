@@ -3,6 +3,8 @@ package peers
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/peers/add"
+	"github.com/0xPolygon/polygon-edge/command/peers/gossip"
+	"github.com/0xPolygon/polygon-edge/command/peers/latency"
 	"github.com/0xPolygon/polygon-edge/command/peers/list"
 	"github.com/0xPolygon/polygon-edge/command/peers/status"
 	"github.com/spf13/cobra"
@@ -29,5 +31,9 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		list.GetCommand(),
 		// peers add
 		add.GetCommand(),
+		// peers latency
+		latency.GetCommand(),
+		// peers gossip
+		gossip.GetCommand(),
 	)
 }
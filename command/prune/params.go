@@ -0,0 +1,142 @@
+package prune
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/command"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag     = "data-dir"
+	pruneBlocksFlag = "prune.blocks"
+	pruneStateFlag  = "prune.state"
+)
+
+const (
+	blockchainFolder = "blockchain"
+	stateFolder      = "trie"
+)
+
+// defaultRetainedStates is the default number of recent blocks whose state is kept
+const defaultRetainedStates uint64 = 128
+
+var (
+	params = &pruneParams{}
+)
+
+var (
+	errInvalidRetainedStates = errors.New("the state of at least one block has to be retained")
+	errUnexpectedStorage     = errors.New("unexpected state storage")
+)
+
+type pruneParams struct {
+	dataDir        string
+	retainedBlocks uint64
+	retainedStates uint64
+
+	blocks uint64
+	roots  int
+	result *itrie.PruneResult
+}
+
+func (p *pruneParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+	}
+}
+
+func (p *pruneParams) validateFlags() error {
+	if p.retainedStates == 0 {
+		return errInvalidRetainedStates
+	}
+
+	return nil
+}
+
+func (p *pruneParams) prune() error {
+	logger := hclog.NewNullLogger()
+
+	kv, _, err := blockchain.OpenExistingDatabase(filepath.Join(p.dataDir, blockchainFolder))
+	if err != nil {
+		return err
+	}
+
+	db := storage.NewKeyValueStorage(logger, kv)
+
+	st, err := itrie.NewLevelDBStorage(filepath.Join(p.dataDir, stateFolder), logger)
+	if err != nil {
+		_ = db.Close()
+
+		return err
+	}
+
+	err = p.pruneStorages(db, st)
+
+	if closeErr := st.Close(); err == nil {
+		err = closeErr
+	}
+
+	// the space of the pruned blocks is reclaimed
+	if err == nil {
+		err = kv.Compact()
+	}
+
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (p *pruneParams) pruneStorages(db storage.Storage, st itrie.Storage) error {
+	kv, ok := st.(*itrie.KVStorage)
+	if !ok {
+		return errUnexpectedStorage
+	}
+
+	if p.retainedBlocks > 0 {
+		blocks, err := blockchain.PruneBlocks(db, p.retainedBlocks)
+		if err != nil {
+			return err
+		}
+
+		p.blocks = blocks
+	}
+
+	// the state of the blocks not flushed by the node is skipped, the empty state isn't stored
+	roots, err := blockchain.RetainedStateRoots(db, p.retainedStates, func(root types.Hash) bool {
+		if root == types.EmptyRootHash {
+			return true
+		}
+
+		_, ok, err := itrie.GetNode(root.Bytes(), st)
+
+		return ok && err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.roots = len(roots)
+
+	if p.result, err = kv.Prune(roots); err != nil {
+		return err
+	}
+
+	return kv.Compact()
+}
+
+func (p *pruneParams) getResult() command.CommandResult {
+	return &PruneResult{
+		Blocks:       p.blocks,
+		StateRoots:   p.roots,
+		StateNodes:   p.result.Nodes,
+		ContractCode: p.result.Code,
+	}
+}
@@ -0,0 +1,62 @@
+package prune
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	pruneCmd := &cobra.Command{
+		Use: "prune",
+		Short: "Deletes the state unreachable from the recent blocks and optionally the old block bodies " +
+			"and receipts from the data directory of a stopped node, then compacts its databases",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(pruneCmd)
+	helper.SetRequiredFlags(pruneCmd, params.getRequiredFlags())
+
+	return pruneCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.retainedBlocks,
+		pruneBlocksFlag,
+		0,
+		"the number of recent blocks whose bodies and receipts are kept. Zero keeps all the blocks",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.retainedStates,
+		pruneStateFlag,
+		defaultRetainedStates,
+		"the number of recent blocks whose state is kept",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.prune(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,31 @@
+package prune
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PruneResult struct {
+	Blocks       uint64 `json:"blocks"`
+	StateRoots   int    `json:"stateRoots"`
+	StateNodes   uint64 `json:"stateNodes"`
+	ContractCode uint64 `json:"contractCode"`
+}
+
+func (r *PruneResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[PRUNE]\n")
+	buffer.WriteString("Pruned the data directory successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Pruned blocks|%d", r.Blocks),
+		fmt.Sprintf("Retained state roots|%d", r.StateRoots),
+		fmt.Sprintf("Deleted state nodes|%d", r.StateNodes),
+		fmt.Sprintf("Deleted contract code|%d", r.ContractCode),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -5,16 +5,25 @@ import (
 	"os"
 
 	"github.com/0xPolygon/polygon-edge/command/backup"
+	"github.com/0xPolygon/polygon-edge/command/bloomindex"
+	"github.com/0xPolygon/polygon-edge/command/chain"
+	"github.com/0xPolygon/polygon-edge/command/compress"
+	"github.com/0xPolygon/polygon-edge/command/contract"
+	"github.com/0xPolygon/polygon-edge/command/dbmigrate"
 	"github.com/0xPolygon/polygon-edge/command/genesis"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/ibft"
 	"github.com/0xPolygon/polygon-edge/command/license"
 	"github.com/0xPolygon/polygon-edge/command/loadbot"
+	"github.com/0xPolygon/polygon-edge/command/loadtest"
 	"github.com/0xPolygon/polygon-edge/command/monitor"
 	"github.com/0xPolygon/polygon-edge/command/peers"
+	"github.com/0xPolygon/polygon-edge/command/prune"
 	"github.com/0xPolygon/polygon-edge/command/secrets"
 	"github.com/0xPolygon/polygon-edge/command/server"
+	"github.com/0xPolygon/polygon-edge/command/state"
 	"github.com/0xPolygon/polygon-edge/command/status"
+	"github.com/0xPolygon/polygon-edge/command/sync"
 	"github.com/0xPolygon/polygon-edge/command/txpool"
 	"github.com/0xPolygon/polygon-edge/command/version"
 	"github.com/spf13/cobra"
@@ -47,11 +56,20 @@ func (rc *RootCommand) registerSubCommands() {
 		peers.GetCommand(),
 		monitor.GetCommand(),
 		loadbot.GetCommand(),
+		loadtest.GetCommand(),
 		ibft.GetCommand(),
 		backup.GetCommand(),
 		genesis.GetCommand(),
 		server.GetCommand(),
 		license.GetCommand(),
+		contract.GetCommand(),
+		state.GetCommand(),
+		chain.GetCommand(),
+		sync.GetCommand(),
+		prune.GetCommand(),
+		compress.GetCommand(),
+		dbmigrate.GetCommand(),
+		bloomindex.GetCommand(),
 	)
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/secrets/helper"
 	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
@@ -24,7 +25,7 @@ var (
 var (
 	errInvalidConfig   = errors.New("invalid secrets configuration")
 	errInvalidParams   = errors.New("no config file or data directory passed in")
-	errUnsupportedType = errors.New("unsupported secrets manager")
+	errUnsupportedType = helper.ErrUnsupportedType
 )
 
 type initParams struct {
@@ -35,6 +36,8 @@ type initParams struct {
 	secretsConfig  *secrets.SecretsManagerConfig
 
 	validatorPrivateKey  *ecdsa.PrivateKey
+	blsPrivateKey        *crypto.BLSPrivateKey
+	blsProof             []byte
 	networkingPrivateKey libp2pCrypto.PrivKey
 
 	nodeID peer.ID
@@ -57,6 +60,10 @@ func (ip *initParams) initSecrets() error {
 		return err
 	}
 
+	if err := ip.initBLSKey(); err != nil {
+		return err
+	}
+
 	return ip.initNetworkingKey()
 }
 
@@ -77,32 +84,9 @@ func (ip *initParams) initFromConfig() error {
 		return err
 	}
 
-	var secretsManager secrets.SecretsManager
-
-	switch ip.secretsConfig.Type {
-	case secrets.HashicorpVault:
-		vault, err := helper.SetupHashicorpVault(ip.secretsConfig)
-		if err != nil {
-			return err
-		}
-
-		secretsManager = vault
-	case secrets.AWSSSM:
-		AWSSSM, err := helper.SetupAWSSSM(ip.secretsConfig)
-		if err != nil {
-			return err
-		}
-
-		secretsManager = AWSSSM
-	case secrets.GCPSSM:
-		GCPSSM, err := helper.SetupGCPSSM(ip.secretsConfig)
-		if err != nil {
-			return err
-		}
-
-		secretsManager = GCPSSM
-	default:
-		return errUnsupportedType
+	secretsManager, err := helper.InitCloudSecretsManager(ip.secretsConfig)
+	if err != nil {
+		return err
 	}
 
 	ip.secretsManager = secretsManager
@@ -147,6 +131,20 @@ func (ip *initParams) initValidatorKey() error {
 	return nil
 }
 
+func (ip *initParams) initBLSKey() error {
+	blsKey, err := helper.InitBLSValidatorKey(ip.secretsManager)
+	if err != nil {
+		return err
+	}
+
+	ip.blsPrivateKey = blsKey
+
+	// the proof of possession is registered with the BLS public key
+	ip.blsProof, err = blsKey.ProofOfPossession()
+
+	return err
+}
+
 func (ip *initParams) initNetworkingKey() error {
 	networkingKey, err := helper.InitNetworkingPrivateKey(ip.secretsManager)
 	if err != nil {
@@ -171,7 +169,9 @@ func (ip *initParams) initNodeID() error {
 
 func (ip *initParams) getResult() command.CommandResult {
 	return &SecretsInitResult{
-		Address: crypto.PubKeyToAddress(&ip.validatorPrivateKey.PublicKey),
-		NodeID:  ip.nodeID.String(),
+		Address:              crypto.PubKeyToAddress(&ip.validatorPrivateKey.PublicKey),
+		BLSPublicKey:         hex.EncodeToHex(ip.blsPrivateKey.PublicKey().Marshal()),
+		BLSProofOfPossession: hex.EncodeToHex(ip.blsProof),
+		NodeID:               ip.nodeID.String(),
 	}
 }
@@ -9,8 +9,10 @@ import (
 )
 
 type SecretsInitResult struct {
-	Address types.Address `json:"address"`
-	NodeID  string        `json:"node_id"`
+	Address              types.Address `json:"address"`
+	BLSPublicKey         string        `json:"bls_public_key"`
+	BLSProofOfPossession string        `json:"bls_proof_of_possession"`
+	NodeID               string        `json:"node_id"`
 }
 
 func (r *SecretsInitResult) GetOutput() string {
@@ -19,6 +21,8 @@ func (r *SecretsInitResult) GetOutput() string {
 	buffer.WriteString("\n[SECRETS INIT]\n")
 	buffer.WriteString(helper.FormatKV([]string{
 		fmt.Sprintf("Public key (address)|%s", r.Address),
+		fmt.Sprintf("BLS public key|%s", r.BLSPublicKey),
+		fmt.Sprintf("BLS proof of possession|%s", r.BLSProofOfPossession),
 		fmt.Sprintf("Node ID|%s", r.NodeID),
 	}))
 	buffer.WriteString("\n")
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/helper"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	dataDirFlag = "data-dir"
+	configFlag  = "config"
+)
+
+var (
+	params = &outputParams{}
+)
+
+var (
+	errInvalidConfig = errors.New("invalid secrets configuration")
+	errInvalidParams = errors.New("no config file or data directory passed in")
+)
+
+type outputParams struct {
+	dataDir    string
+	configPath string
+
+	secretsManager secrets.SecretsManager
+
+	result *SecretsOutputResult
+}
+
+func (op *outputParams) validateFlags() error {
+	if op.dataDir == "" && op.configPath == "" {
+		return errInvalidParams
+	}
+
+	return nil
+}
+
+func (op *outputParams) readSecrets() error {
+	if err := op.initSecretsManager(); err != nil {
+		return err
+	}
+
+	op.result = &SecretsOutputResult{}
+
+	if err := op.readValidatorKey(); err != nil {
+		return err
+	}
+
+	if err := op.readBLSKey(); err != nil {
+		return err
+	}
+
+	return op.readNetworkingKey()
+}
+
+func (op *outputParams) initSecretsManager() error {
+	if op.configPath == "" {
+		secretsManager, err := helper.LoadLocalSecretsManager(op.dataDir)
+		if err != nil {
+			return err
+		}
+
+		op.secretsManager = secretsManager
+
+		return nil
+	}
+
+	secretsConfig, readErr := secrets.ReadConfig(op.configPath)
+	if readErr != nil {
+		return errInvalidConfig
+	}
+
+	secretsManager, err := helper.InitCloudSecretsManager(secretsConfig)
+	if err != nil {
+		return err
+	}
+
+	op.secretsManager = secretsManager
+
+	return nil
+}
+
+func (op *outputParams) readValidatorKey() error {
+	validatorKey, err := crypto.ReadConsensusKey(op.secretsManager)
+	if err != nil {
+		return err
+	}
+
+	op.result.Address = crypto.PubKeyToAddress(&validatorKey.PublicKey).String()
+
+	return nil
+}
+
+func (op *outputParams) readBLSKey() error {
+	if !op.secretsManager.HasSecret(secrets.ValidatorBLSKey) {
+		// the secrets were initialized before BLS keys were supported
+		return nil
+	}
+
+	blsKey, err := crypto.ReadBLSKey(op.secretsManager)
+	if err != nil {
+		return err
+	}
+
+	proof, err := blsKey.ProofOfPossession()
+	if err != nil {
+		return err
+	}
+
+	op.result.BLSPublicKey = hex.EncodeToHex(blsKey.PublicKey().Marshal())
+	op.result.BLSProofOfPossession = hex.EncodeToHex(proof)
+
+	return nil
+}
+
+func (op *outputParams) readNetworkingKey() error {
+	networkingKey, err := network.ReadLibp2pKey(op.secretsManager)
+	if err != nil {
+		return err
+	}
+
+	nodeID, err := peer.IDFromPrivateKey(networkingKey)
+	if err != nil {
+		return err
+	}
+
+	op.result.NodeID = nodeID.String()
+
+	return nil
+}
+
+func (op *outputParams) getResult() command.CommandResult {
+	return op.result
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SecretsOutputResult struct {
+	Address              string `json:"address"`
+	BLSPublicKey         string `json:"bls_public_key"`
+	BLSProofOfPossession string `json:"bls_proof_of_possession"`
+	NodeID               string `json:"node_id"`
+}
+
+func (r *SecretsOutputResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	blsPublicKey, blsProof := r.BLSPublicKey, r.BLSProofOfPossession
+	if blsPublicKey == "" {
+		blsPublicKey, blsProof = "none", "none"
+	}
+
+	buffer.WriteString("\n[SECRETS OUTPUT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Public key (address)|%s", r.Address),
+		fmt.Sprintf("BLS public key|%s", blsPublicKey),
+		fmt.Sprintf("BLS proof of possession|%s", blsProof),
+		fmt.Sprintf("Node ID|%s", r.NodeID),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
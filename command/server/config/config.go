@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"gopkg.in/yaml.v3"
 
 	"github.com/hashicorp/hcl"
@@ -18,19 +24,38 @@ type Config struct {
 	SecretsConfigPath        string     `json:"secrets_config" yaml:"secrets_config"`
 	DataDir                  string     `json:"data_dir" yaml:"data_dir"`
 	BlockGasTarget           string     `json:"block_gas_target" yaml:"block_gas_target"`
+	BlockGasElastic          bool       `json:"block_gas_elastic" yaml:"block_gas_elastic"`
 	GRPCAddr                 string     `json:"grpc_addr" yaml:"grpc_addr"`
 	JSONRPCAddr              string     `json:"jsonrpc_addr" yaml:"jsonrpc_addr"`
 	Telemetry                *Telemetry `json:"telemetry" yaml:"telemetry"`
 	Network                  *Network   `json:"network" yaml:"network"`
 	ShouldSeal               bool       `json:"seal" yaml:"seal"`
 	TxPool                   *TxPool    `json:"tx_pool" yaml:"tx_pool"`
+	Syncer                   *Syncer    `json:"syncer" yaml:"syncer"`
 	LogLevel                 string     `json:"log_level" yaml:"log_level"`
 	RestoreFile              string     `json:"restore_file" yaml:"restore_file"`
 	BlockTime                uint64     `json:"block_time_s" yaml:"block_time_s"`
+	BlockDeadline            float64    `json:"block_deadline" yaml:"block_deadline"`
+	IBFTDryRun               bool       `json:"ibft_dry_run" yaml:"ibft_dry_run"`
+	IBFTBuilderKeys          []string   `json:"ibft_builder_keys" yaml:"ibft_builder_keys"`
+	IBFTBuilderTimeout       uint64     `json:"ibft_builder_timeout_ms" yaml:"ibft_builder_timeout_ms"`
 	Headers                  *Headers   `json:"headers" yaml:"headers"`
 	LogFilePath              string     `json:"log_to" yaml:"log_to"`
 	JSONRPCBatchRequestLimit uint64     `json:"json_rpc_batch_request_limit" yaml:"json_rpc_batch_request_limit"`
 	JSONRPCBlockRangeLimit   uint64     `json:"json_rpc_block_range_limit" yaml:"json_rpc_block_range_limit"`
+	JSONRPCAPIKeys           []string   `json:"json_rpc_api_keys" yaml:"json_rpc_api_keys"`
+	StateCommitInterval      uint64     `json:"state_commit_interval" yaml:"state_commit_interval"`
+	OpcodeStats              bool       `json:"opcode_stats" yaml:"opcode_stats"`
+	StatePrefetchWorkers     uint64     `json:"state_prefetch_workers" yaml:"state_prefetch_workers"`
+	RecordPreimages          bool       `json:"record_preimages" yaml:"record_preimages"`
+	StateSnapshot            bool       `json:"state_snapshot" yaml:"state_snapshot"`
+	PruneBlocks              uint64     `json:"prune_blocks" yaml:"prune_blocks"`
+	StorageCompression       string     `json:"storage_compression" yaml:"storage_compression"`
+	FreezerThreshold         uint64     `json:"freezer_threshold" yaml:"freezer_threshold"`
+	TxLookupLimit            uint64     `json:"tx_lookup_limit" yaml:"tx_lookup_limit"`
+	DBEngine                 string     `json:"db_engine" yaml:"db_engine"`
+	DBCompressReceipts       bool       `json:"db_compress_receipts" yaml:"db_compress_receipts"`
+	FutureBlockTolerance     uint64     `json:"future_block_tolerance_s" yaml:"future_block_tolerance_s"`
 }
 
 // Telemetry holds the config details for metric services.
@@ -47,12 +72,50 @@ type Network struct {
 	MaxPeers         int64  `json:"max_peers,omitempty" yaml:"max_peers,omitempty"`
 	MaxOutboundPeers int64  `json:"max_outbound_peers,omitempty" yaml:"max_outbound_peers,omitempty"`
 	MaxInboundPeers  int64  `json:"max_inbound_peers,omitempty" yaml:"max_inbound_peers,omitempty"`
+	TargetPeers      int64  `json:"target_peers,omitempty" yaml:"target_peers,omitempty"`
 }
 
 // TxPool defines the TxPool configuration params
 type TxPool struct {
-	PriceLimit uint64 `json:"price_limit" yaml:"price_limit"`
-	MaxSlots   uint64 `json:"max_slots" yaml:"max_slots"`
+	PriceLimit      uint64   `json:"price_limit" yaml:"price_limit"`
+	MaxSlots        uint64   `json:"max_slots" yaml:"max_slots"`
+	ExemptAddresses []string `json:"exempt_addresses" yaml:"exempt_addresses"`
+	LocalAddresses  []string `json:"local_addresses" yaml:"local_addresses"`
+	FutureTxTypes   []string `json:"future_tx_types" yaml:"future_tx_types"`
+	OperatorAccount string   `json:"operator_account,omitempty" yaml:"operator_account,omitempty"`
+	JournalPath     string   `json:"journal_path,omitempty" yaml:"journal_path,omitempty"`
+	JournalInterval uint64   `json:"journal_interval_s" yaml:"journal_interval_s"`
+	PriceBump       uint64   `json:"price_bump" yaml:"price_bump"`
+	MaxAccountTxs   uint64   `json:"max_account_txs" yaml:"max_account_txs"`
+	MaxEnqueued     uint64   `json:"max_enqueued" yaml:"max_enqueued"`
+}
+
+// Syncer defines the block syncer configuration params
+type Syncer struct {
+	BatchSize            uint64   `json:"batch_size" yaml:"batch_size"`
+	MaxPeers             uint64   `json:"max_peers" yaml:"max_peers"`
+	BlockTimeout         uint64   `json:"block_timeout_s" yaml:"block_timeout_s"`
+	MinBlockTimeout      uint64   `json:"min_block_timeout_s" yaml:"min_block_timeout_s"`
+	MaxBlockTimeout      uint64   `json:"max_block_timeout_s" yaml:"max_block_timeout_s"`
+	RequestRateLimit     float64  `json:"request_rate_limit" yaml:"request_rate_limit"`
+	RequestBurst         uint64   `json:"request_burst" yaml:"request_burst"`
+	MaxConcurrentStreams uint64   `json:"max_concurrent_streams" yaml:"max_concurrent_streams"`
+	StreamWriteTimeout   uint64   `json:"stream_write_timeout_s" yaml:"stream_write_timeout_s"`
+	Compression          string   `json:"compression" yaml:"compression"`
+	TrustedCheckpoints   []string `json:"trusted_checkpoints" yaml:"trusted_checkpoints"`
+	Mode                 string   `json:"mode" yaml:"mode"`
+	PrefetchCacheSize    uint64   `json:"prefetch_cache_size" yaml:"prefetch_cache_size"`
+	TrustedPeers         []string `json:"trusted_peers" yaml:"trusted_peers"`
+	MaxSessionBlocks     uint64   `json:"max_session_blocks" yaml:"max_session_blocks"`
+	PeerStatusTTL        uint64   `json:"peer_status_ttl_s" yaml:"peer_status_ttl_s"`
+	RetainedBlocks       uint64   `json:"retained_blocks" yaml:"retained_blocks"`
+	ArchivePeers         []string `json:"archive_peers" yaml:"archive_peers"`
+	VerifyWorkers        uint64   `json:"verify_workers" yaml:"verify_workers"`
+	KeepaliveInterval    uint64   `json:"keepalive_interval_s" yaml:"keepalive_interval_s"`
+	KeepaliveTimeout     uint64   `json:"keepalive_timeout_s" yaml:"keepalive_timeout_s"`
+	HedgeDelay           uint64   `json:"hedge_delay_ms" yaml:"hedge_delay_ms"`
+	EraSync              bool     `json:"era_sync" yaml:"era_sync"`
+	SyncReceipts         bool     `json:"sync_receipts" yaml:"sync_receipts"`
 }
 
 // Headers defines the HTTP response headers required to enable CORS.
@@ -68,11 +131,22 @@ const (
 	// timeout is calculated when IBFT timeout is not specified
 	BlockTimeMultiplierForTimeout uint64 = 5
 
+	// fraction of the block time the proposer executes transactions within,
+	// leaving the rest of it to seal and propose the block
+	DefaultBlockDeadline float64 = 0.75
+
 	// maximum length allowed for json_rpc batch requests
 	DefaultJSONRPCBatchRequestLimit uint64 = 20
 
 	// maximum block range allowed for json_rpc requests with fromBlock/toBlock values (e.g. eth_getLogs)
 	DefaultJSONRPCBlockRangeLimit uint64 = 1000
+
+	// number of blocks after which the state kept in memory is written to disk,
+	// the state is written on every block by default
+	DefaultStateCommitInterval uint64 = 1
+
+	// number of goroutines loading the accounts of a block from the state while it executes
+	DefaultStatePrefetchWorkers uint64 = 4
 )
 
 // DefaultConfig returns the default server configuration
@@ -96,18 +170,48 @@ func DefaultConfig() *Config {
 		Telemetry:  &Telemetry{},
 		ShouldSeal: true,
 		TxPool: &TxPool{
-			PriceLimit: 0,
-			MaxSlots:   4096,
+			PriceLimit:      0,
+			MaxSlots:        4096,
+			JournalInterval: uint64(txpool.DefaultJournalInterval / time.Second),
+			PriceBump:       txpool.DefaultPriceBump,
+			MaxAccountTxs:   txpool.DefaultMaxAccountTxs,
+			MaxEnqueued:     txpool.DefaultMaxEnqueued,
+		},
+		Syncer: &Syncer{
+			BatchSize:            syncer.DefaultBatchSize,
+			MaxPeers:             syncer.DefaultMaxPeers,
+			MaxBlockTimeout:      uint64(syncer.DefaultMaxBlockTimeout / time.Second),
+			RequestRateLimit:     syncer.DefaultRequestRateLimit,
+			RequestBurst:         syncer.DefaultRequestBurst,
+			MaxConcurrentStreams: syncer.DefaultMaxConcurrentStreams,
+			StreamWriteTimeout:   uint64(syncer.DefaultStreamWriteTimeout / time.Second),
+			Compression:          syncer.DefaultCompression,
+			Mode:                 syncer.ModeFull.String(),
+			PrefetchCacheSize:    syncer.DefaultPrefetchCacheSize,
+			PeerStatusTTL:        uint64(syncer.DefaultPeerStatusTTL / time.Second),
+			RetainedBlocks:       syncer.DefaultRetainedBlocks,
+			KeepaliveInterval:    uint64(syncer.DefaultKeepaliveInterval / time.Second),
+			KeepaliveTimeout:     uint64(syncer.DefaultKeepaliveTimeout / time.Second),
+			HedgeDelay:           uint64(syncer.DefaultHedgeDelay / time.Millisecond),
+			EraSync:              true,
 		},
-		LogLevel:    "INFO",
-		RestoreFile: "",
-		BlockTime:   DefaultBlockTime,
+		LogLevel:           "INFO",
+		RestoreFile:        "",
+		BlockTime:          DefaultBlockTime,
+		BlockDeadline:      DefaultBlockDeadline,
+		IBFTBuilderTimeout: uint64(ibft.DefaultBuilderTimeout / time.Millisecond),
 		Headers: &Headers{
 			AccessControlAllowOrigins: []string{"*"},
 		},
 		LogFilePath:              "",
 		JSONRPCBatchRequestLimit: DefaultJSONRPCBatchRequestLimit,
 		JSONRPCBlockRangeLimit:   DefaultJSONRPCBlockRangeLimit,
+		StateCommitInterval:      DefaultStateCommitInterval,
+		StatePrefetchWorkers:     DefaultStatePrefetchWorkers,
+		StateSnapshot:            true,
+		StorageCompression:       storage.CompressionNone.String(),
+		DBEngine:                 storage.EngineLevelDB.String(),
+		FutureBlockTolerance:     uint64(blockchain.DefaultFutureBlockTolerance / time.Second),
 	}
 }
 
@@ -0,0 +1,58 @@
+package gastarget
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	gasTargetCmd := &cobra.Command{
+		Use: "gas-target",
+		Short: "Returns or sets the gas limit target of the blocks built by a running node. " +
+			"The gas limit moves toward the target by 1/1024 of the parent gas limit per block",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(gasTargetCmd)
+
+	return gasTargetCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.rawTarget,
+		targetFlag,
+		"",
+		"the block gas target to set, 0 to keep the gas limit of the parent block. "+
+			"If omitted, the current target is returned",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.elastic,
+		elasticFlag,
+		false,
+		"make the gas limit follow the usage of the blocks, between half the target and the target",
+	)
+}
+
+func runPreRun(cmd *cobra.Command, _ []string) error {
+	params.set = cmd.Flags().Changed(targetFlag)
+
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.gasTarget(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,75 @@
+package gastarget
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+const (
+	targetFlag  = "target"
+	elasticFlag = "elastic"
+)
+
+var (
+	params = &gasTargetParams{}
+)
+
+type gasTargetParams struct {
+	rawTarget string
+	elastic   bool
+
+	// set is true when the target is set, the current one being only queried otherwise
+	set    bool
+	target uint64
+
+	resp *proto.BlockGasTargetResponse
+}
+
+func (p *gasTargetParams) initRawParams() error {
+	if !p.set {
+		return nil
+	}
+
+	var parseErr error
+
+	if p.target, parseErr = types.ParseUint64orHex(&p.rawTarget); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
+func (p *gasTargetParams) gasTarget(grpcAddress string) error {
+	client, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	if !p.set {
+		p.resp, err = client.GetBlockGasTarget(context.Background(), &empty.Empty{})
+
+		return err
+	}
+
+	p.resp, err = client.SetBlockGasTarget(context.Background(), &proto.SetBlockGasTargetRequest{
+		Target:  p.target,
+		Elastic: p.elastic,
+	})
+
+	return err
+}
+
+func (p *gasTargetParams) getResult() command.CommandResult {
+	return &GasTargetResult{
+		Set:          p.set,
+		Target:       p.resp.Target,
+		Elastic:      p.resp.Elastic,
+		GasLimit:     p.resp.GasLimit,
+		NextGasLimit: p.resp.NextGasLimit,
+	}
+}
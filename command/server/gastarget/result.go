@@ -0,0 +1,41 @@
+package gastarget
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type GasTargetResult struct {
+	Set          bool   `json:"set"`
+	Target       uint64 `json:"target"`
+	Elastic      bool   `json:"elastic"`
+	GasLimit     uint64 `json:"gas_limit"`
+	NextGasLimit uint64 `json:"next_gas_limit"`
+}
+
+func (r *GasTargetResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	target := "parent gas limit"
+	if r.Target != 0 {
+		target = fmt.Sprintf("%d", r.Target)
+	}
+
+	buffer.WriteString("\n[BLOCK GAS TARGET]\n")
+
+	if r.Set {
+		buffer.WriteString("Set the gas limit target of the next blocks:\n")
+	}
+
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Target|%s", target),
+		fmt.Sprintf("Elastic|%t", r.Elastic),
+		fmt.Sprintf("Head gas limit|%d", r.GasLimit),
+		fmt.Sprintf("Next gas limit|%d", r.NextGasLimit),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
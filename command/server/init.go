@@ -7,20 +7,42 @@ import (
 	"net"
 
 	"github.com/0xPolygon/polygon-edge/command/server/config"
+	"github.com/0xPolygon/polygon-edge/consensus"
 
 	"github.com/0xPolygon/polygon-edge/network/common"
 
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 )
 
 var (
-	errInvalidBlockTime       = errors.New("invalid block time specified")
-	errDataDirectoryUndefined = errors.New("data directory not defined")
+	errInvalidBlockTime        = errors.New("invalid block time specified")
+	errInvalidBlockDeadline    = errors.New("the block deadline has to be a fraction of the block time in (0, 1]")
+	errInvalidSyncBatchSize    = errors.New("invalid sync batch size specified")
+	errInvalidSyncMaxPeers     = errors.New("invalid sync max peers specified")
+	errInvalidSyncRateLimit    = errors.New("invalid sync rate limit specified")
+	errInvalidSyncMaxStreams   = errors.New("invalid sync max streams specified")
+	errInvalidSyncWriteTimeout = errors.New("invalid sync stream write timeout specified")
+	errLightSyncSealing        = errors.New("the light sync mode can't be used by a sealing or dev node")
+	errLightSyncPruning        = errors.New("the light sync mode keeps no blocks to prune")
+	errSyncReceiptsMode        = errors.New("only the light sync mode syncs the receipts, the other modes execute the blocks")
+	errInvalidRetainedBlocks   = errors.New("the gateway sync mode has to retain at least the state commit interval blocks")
+	errInvalidFreezeThreshold  = errors.New("the freezer threshold has to be above the retained blocks")
+	errInvalidCommitInterval   = errors.New("invalid state commit interval specified")
+	errInvalidTargetPeers      = errors.New("invalid target peers specified")
+	errInvalidExemptAddress    = errors.New("invalid txpool exempt address specified")
+	errInvalidLocalAddress     = errors.New("invalid txpool local address specified")
+	errInvalidOperatorAccount  = errors.New("invalid txpool operator account specified")
+	errDataDirectoryUndefined  = errors.New("data directory not defined")
 )
 
 func (p *serverParams) initConfigFromFile() error {
@@ -30,9 +52,37 @@ func (p *serverParams) initConfigFromFile() error {
 		return parseErr
 	}
 
+	// the JSON-RPC limits of the config file override the flags, as its other settings
+	p.jsonRPCBatchLengthLimit = p.rawConfig.JSONRPCBatchRequestLimit
+	p.jsonRPCBlockRangeLimit = p.rawConfig.JSONRPCBlockRangeLimit
+
 	return nil
 }
 
+// loadReloadableConfig reads the config file again, returning the part of it the server applies at runtime
+func (p *serverParams) loadReloadableConfig() (*server.ReloadableConfig, error) {
+	reloaded := &serverParams{
+		configPath: p.configPath,
+	}
+
+	if err := reloaded.initConfigFromFile(); err != nil {
+		return nil, err
+	}
+
+	reloaded.initPeerLimits()
+
+	return &server.ReloadableConfig{
+		LogLevel:                hclog.LevelFromString(reloaded.rawConfig.LogLevel),
+		JSONRPCBatchLengthLimit: reloaded.jsonRPCBatchLengthLimit,
+		JSONRPCBlockRangeLimit:  reloaded.jsonRPCBlockRangeLimit,
+		PriceLimit:              reloaded.rawConfig.TxPool.PriceLimit,
+		MaxInboundPeers:         reloaded.rawConfig.Network.MaxInboundPeers,
+		MaxOutboundPeers:        reloaded.rawConfig.Network.MaxOutboundPeers,
+		SyncRequestRateLimit:    reloaded.rawConfig.Syncer.RequestRateLimit,
+		SyncRequestBurst:        reloaded.rawConfig.Syncer.RequestBurst,
+	}, nil
+}
+
 func (p *serverParams) initRawParams() error {
 	if err := p.initBlockGasTarget(); err != nil {
 		return err
@@ -54,11 +104,56 @@ func (p *serverParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.initSyncerConfig(); err != nil {
+		return err
+	}
+
+	if err := p.initStateCommitInterval(); err != nil {
+		return err
+	}
+
+	if err := p.initTxPoolExemptAddresses(); err != nil {
+		return err
+	}
+
+	if err := p.initTxPoolLocalAddresses(); err != nil {
+		return err
+	}
+
+	if err := p.initTxPoolFutureTxTypes(); err != nil {
+		return err
+	}
+
+	if err := p.initTxPoolOperatorAccount(); err != nil {
+		return err
+	}
+
+	if err := p.initJSONRPCAPIKeys(); err != nil {
+		return err
+	}
+
+	if err := p.initIBFTBuilderKeys(); err != nil {
+		return err
+	}
+
+	if err := p.initStorageCompression(); err != nil {
+		return err
+	}
+
+	if err := p.initDBEngine(); err != nil {
+		return err
+	}
+
 	if p.isDevMode {
 		p.initDevMode()
 	}
 
 	p.initPeerLimits()
+
+	if err := p.initTargetPeers(); err != nil {
+		return err
+	}
+
 	p.initLogFileLocation()
 
 	return p.initAddresses()
@@ -69,6 +164,184 @@ func (p *serverParams) initBlockTime() error {
 		return errInvalidBlockTime
 	}
 
+	if p.rawConfig.BlockDeadline <= 0 || p.rawConfig.BlockDeadline > 1 {
+		return errInvalidBlockDeadline
+	}
+
+	return nil
+}
+
+func (p *serverParams) initSyncerConfig() error {
+	if p.rawConfig.Syncer.BatchSize < 1 {
+		return errInvalidSyncBatchSize
+	}
+
+	if p.rawConfig.Syncer.MaxPeers < 1 {
+		return errInvalidSyncMaxPeers
+	}
+
+	if p.rawConfig.Syncer.RequestRateLimit <= 0 || p.rawConfig.Syncer.RequestBurst < 1 {
+		return errInvalidSyncRateLimit
+	}
+
+	if p.rawConfig.Syncer.MaxConcurrentStreams < 1 {
+		return errInvalidSyncMaxStreams
+	}
+
+	if p.rawConfig.Syncer.StreamWriteTimeout < 1 {
+		return errInvalidSyncWriteTimeout
+	}
+
+	compression, err := syncer.ParseCompression(p.rawConfig.Syncer.Compression)
+	if err != nil {
+		return err
+	}
+
+	p.syncCompression = compression
+
+	if p.syncMode, err = syncer.ParseSyncMode(p.rawConfig.Syncer.Mode); err != nil {
+		return err
+	}
+
+	// a node pruning the blocks can't serve them to its peers, as the gateway nodes
+	if p.rawConfig.PruneBlocks > 0 {
+		if p.syncMode == syncer.ModeLight {
+			return errLightSyncPruning
+		}
+
+		p.syncMode = syncer.ModeGateway
+		p.rawConfig.Syncer.RetainedBlocks = p.rawConfig.PruneBlocks
+	}
+
+	// the blocks aren't executed in the light mode, so the node can't build on top of them
+	if p.syncMode == syncer.ModeLight && (p.rawConfig.ShouldSeal || p.isDevMode) {
+		return errLightSyncSealing
+	}
+
+	if p.rawConfig.Syncer.SyncReceipts && p.syncMode != syncer.ModeLight {
+		return errSyncReceiptsMode
+	}
+
+	if p.syncTrustedCheckpoints, err = syncer.ParseTrustedCheckpoints(p.rawConfig.Syncer.TrustedCheckpoints); err != nil {
+		return err
+	}
+
+	if p.syncTrustedPeers, err = syncer.ParseTrustedPeers(p.rawConfig.Syncer.TrustedPeers); err != nil {
+		return err
+	}
+
+	// the blocks whose state isn't written to disk are re-executed on restart, their bodies are needed
+	if p.syncMode == syncer.ModeGateway && p.rawConfig.Syncer.RetainedBlocks < p.rawConfig.StateCommitInterval {
+		return errInvalidRetainedBlocks
+	}
+
+	// the freezer is append-only, the blocks have to be pruned before they are frozen
+	if p.syncMode == syncer.ModeGateway && p.rawConfig.FreezerThreshold > 0 &&
+		p.rawConfig.FreezerThreshold <= p.rawConfig.Syncer.RetainedBlocks {
+		return errInvalidFreezeThreshold
+	}
+
+	if p.syncArchivePeers, err = syncer.ParseArchivePeers(p.rawConfig.Syncer.ArchivePeers); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *serverParams) initTxPoolExemptAddresses() error {
+	p.txPoolExemptAddresses = make([]types.Address, len(p.rawConfig.TxPool.ExemptAddresses))
+
+	for i, raw := range p.rawConfig.TxPool.ExemptAddresses {
+		if err := p.txPoolExemptAddresses[i].UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("%w: %s", errInvalidExemptAddress, raw)
+		}
+	}
+
+	return nil
+}
+
+func (p *serverParams) initTxPoolLocalAddresses() error {
+	p.txPoolLocalAddresses = make([]types.Address, len(p.rawConfig.TxPool.LocalAddresses))
+
+	for i, raw := range p.rawConfig.TxPool.LocalAddresses {
+		if err := p.txPoolLocalAddresses[i].UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("%w: %s", errInvalidLocalAddress, raw)
+		}
+	}
+
+	return nil
+}
+
+func (p *serverParams) initTxPoolFutureTxTypes() error {
+	futureTxTypes, err := txpool.ParseFutureTxTypes(p.rawConfig.TxPool.FutureTxTypes)
+	if err != nil {
+		return err
+	}
+
+	p.txPoolFutureTxTypes = futureTxTypes
+
+	return nil
+}
+
+func (p *serverParams) initTxPoolOperatorAccount() error {
+	raw := p.rawConfig.TxPool.OperatorAccount
+	if raw == "" {
+		return nil
+	}
+
+	account := types.Address{}
+	if err := account.UnmarshalText([]byte(raw)); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidOperatorAccount, raw)
+	}
+
+	p.txPoolOperatorAccount = &account
+
+	return nil
+}
+
+func (p *serverParams) initJSONRPCAPIKeys() error {
+	apiKeys, err := jsonrpc.ParseAPIKeys(p.rawConfig.JSONRPCAPIKeys)
+	if err != nil {
+		return err
+	}
+
+	p.jsonRPCAPIKeys = apiKeys
+
+	return nil
+}
+
+func (p *serverParams) initIBFTBuilderKeys() error {
+	builderKeys, err := consensus.ParseBuilderKeys(p.rawConfig.IBFTBuilderKeys)
+	if err != nil {
+		return err
+	}
+
+	p.ibftBuilderKeys = builderKeys
+
+	return nil
+}
+
+func (p *serverParams) initStorageCompression() error {
+	var err error
+
+	p.storageCompression, err = storage.ParseCompression(p.rawConfig.StorageCompression)
+
+	return err
+}
+
+func (p *serverParams) initDBEngine() error {
+	var err error
+
+	p.dbEngine, err = storage.ParseEngine(p.rawConfig.DBEngine)
+
+	return err
+}
+
+func (p *serverParams) initStateCommitInterval() error {
+	if p.rawConfig.StateCommitInterval < 1 {
+		return errInvalidCommitInterval
+	}
+
 	return nil
 }
 
@@ -117,12 +390,21 @@ func (p *serverParams) initSecretsConfig() error {
 func (p *serverParams) initGenesisConfig() error {
 	var parseErr error
 
-	if p.genesisConfig, parseErr = chain.Import(
+	if p.genesisConfig, parseErr = chain.ImportStreamingAlloc(
 		p.rawConfig.GenesisPath,
 	); parseErr != nil {
 		return parseErr
 	}
 
+	// the gas limit adjustment of the node overrides the one of the chain file
+	if p.blockGasTarget != 0 {
+		p.genesisConfig.Params.BlockGasTarget = p.blockGasTarget
+	}
+
+	if p.rawConfig.BlockGasElastic {
+		p.genesisConfig.Params.BlockGasElastic = true
+	}
+
 	return nil
 }
 
@@ -180,6 +462,15 @@ func (p *serverParams) initDefaultPeerLimits() {
 	p.rawConfig.Network.MaxOutboundPeers = defaultNetworkConfig.MaxOutboundPeers
 }
 
+func (p *serverParams) initTargetPeers() error {
+	if p.rawConfig.Network.TargetPeers < 0 ||
+		p.rawConfig.Network.TargetPeers > p.rawConfig.Network.MaxPeers {
+		return errInvalidTargetPeers
+	}
+
+	return nil
+}
+
 func (p *serverParams) initUsingPeerRange() {
 	defaultConfig := network.DefaultConfig()
 
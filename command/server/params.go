@@ -3,13 +3,23 @@ package server
 import (
 	"errors"
 	"net"
+	"path/filepath"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command/server/config"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	syncerProto "github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
 )
 
@@ -25,18 +35,70 @@ const (
 	maxPeersFlag                 = "max-peers"
 	maxInboundPeersFlag          = "max-inbound-peers"
 	maxOutboundPeersFlag         = "max-outbound-peers"
+	targetPeersFlag              = "target-peers"
 	priceLimitFlag               = "price-limit"
 	jsonRPCBatchRequestLimitFlag = "json-rpc-batch-request-limit"
 	jsonRPCBlockRangeLimitFlag   = "json-rpc-block-range-limit"
+	jsonRPCAPIKeyFlag            = "json-rpc-api-key"
 	maxSlotsFlag                 = "max-slots"
+	txPoolExemptFlag             = "txpool-exempt"
+	txPoolLocalFlag              = "txpool-local"
+	txPoolFutureTxTypeFlag       = "txpool-future-tx-type"
+	txPoolOperatorAccountFlag    = "txpool-operator-account"
+	txPoolJournalFlag            = "txpool-journal"
+	txPoolJournalIntervalFlag    = "txpool-journal-interval"
+	txPoolPriceBumpFlag          = "txpool-price-bump"
+	txPoolMaxAccountTxsFlag      = "txpool-max-account-txs"
+	txPoolMaxEnqueuedFlag        = "txpool-max-enqueued"
 	blockGasTargetFlag           = "block-gas-target"
+	blockGasElasticFlag          = "block-gas-elastic"
 	secretsConfigFlag            = "secrets-config"
 	restoreFlag                  = "restore"
 	blockTimeFlag                = "block-time"
+	blockDeadlineFlag            = "block-deadline"
+	ibftDryRunFlag               = "ibft-dry-run"
+	ibftBuilderKeyFlag           = "ibft-builder-key"
+	ibftBuilderTimeoutFlag       = "ibft-builder-timeout"
 	devIntervalFlag              = "dev-interval"
 	devFlag                      = "dev"
 	corsOriginFlag               = "access-control-allow-origins"
 	logFileLocationFlag          = "log-to"
+	syncBatchSizeFlag            = "sync-batch-size"
+	syncMaxPeersFlag             = "sync-max-peers"
+	syncBlockTimeoutFlag         = "sync-block-timeout"
+	syncMinBlockTimeoutFlag      = "sync-min-block-timeout"
+	syncMaxBlockTimeoutFlag      = "sync-max-block-timeout"
+	syncRateLimitFlag            = "sync-rate-limit"
+	syncRateBurstFlag            = "sync-rate-burst"
+	syncMaxStreamsFlag           = "sync-max-streams"
+	syncStreamWriteTimeoutFlag   = "sync-stream-write-timeout"
+	syncCompressionFlag          = "sync-compression"
+	syncCheckpointFlag           = "sync-checkpoint"
+	syncModeFlag                 = "sync-mode"
+	syncPrefetchCacheSizeFlag    = "sync-prefetch-cache-size"
+	syncTrustedPeersFlag         = "sync-trusted-peers"
+	syncMaxSessionBlocksFlag     = "sync-max-session-blocks"
+	syncPeerStatusTTLFlag        = "sync-peer-status-ttl"
+	syncRetainedBlocksFlag       = "sync-retained-blocks"
+	syncArchivePeersFlag         = "sync-archive-peers"
+	syncVerifyWorkersFlag        = "sync-verify-workers"
+	syncKeepaliveIntervalFlag    = "sync-keepalive-interval"
+	syncKeepaliveTimeoutFlag     = "sync-keepalive-timeout"
+	syncHedgeDelayFlag           = "sync-hedge-delay"
+	syncEraFlag                  = "sync-era"
+	syncReceiptsFlag             = "sync-receipts"
+	stateCommitIntervalFlag      = "state-commit-interval"
+	opcodeStatsFlag              = "opcode-stats"
+	statePrefetchWorkersFlag     = "state-prefetch-workers"
+	recordPreimagesFlag          = "record-preimages"
+	stateSnapshotFlag            = "state-snapshot"
+	pruneBlocksFlag              = "prune.blocks"
+	storageCompressionFlag       = "storage-compression"
+	freezerThresholdFlag         = "freezer-threshold"
+	txLookupLimitFlag            = "txlookuplimit"
+	dbEngineFlag                 = "db.engine"
+	dbCompressReceiptsFlag       = "db.compress-receipts"
+	futureBlockToleranceFlag     = "future-block-tolerance"
 )
 
 // Flags that are deprecated, but need to be preserved for
@@ -55,6 +117,7 @@ var (
 			Telemetry: &config.Telemetry{},
 			Network:   &config.Network{},
 			TxPool:    &config.TxPool{},
+			Syncer:    &config.Syncer{},
 		},
 	}
 )
@@ -82,9 +145,26 @@ type serverParams struct {
 
 	jsonRPCBatchLengthLimit uint64
 	jsonRPCBlockRangeLimit  uint64
+	jsonRPCAPIKeys          []*jsonrpc.APIKey
+
+	ibftBuilderKeys []*consensus.BuilderKey
+
+	storageCompression storage.Compression
+	dbEngine           storage.Engine
 
 	ibftBaseTimeoutLegacy uint64
 
+	syncCompression        syncerProto.Compression
+	syncTrustedCheckpoints []*syncer.TrustedCheckpoint
+	syncMode               syncer.SyncMode
+	syncTrustedPeers       []*peer.AddrInfo
+	syncArchivePeers       []*peer.AddrInfo
+
+	txPoolExemptAddresses []types.Address
+	txPoolLocalAddresses  []types.Address
+	txPoolFutureTxTypes   []*txpool.FutureTxType
+	txPoolOperatorAccount *types.Address
+
 	genesisConfig *chain.Chain
 	secretsConfig *secrets.SecretsManagerConfig
 
@@ -141,13 +221,14 @@ func (p *serverParams) setRawJSONRPCAddress(jsonRPCAddress string) {
 }
 
 func (p *serverParams) generateConfig() *server.Config {
-	return &server.Config{
+	config := &server.Config{
 		Chain: p.genesisConfig,
 		JSONRPC: &server.JSONRPC{
 			JSONRPCAddr:              p.jsonRPCAddress,
 			AccessControlAllowOrigin: p.corsAllowedOrigins,
 			BatchLengthLimit:         p.jsonRPCBatchLengthLimit,
 			BlockRangeLimit:          p.jsonRPCBlockRangeLimit,
+			APIKeys:                  p.jsonRPCAPIKeys,
 		},
 		GRPCAddr:   p.grpcAddress,
 		LibP2PAddr: p.libp2pAddress,
@@ -163,16 +244,84 @@ func (p *serverParams) generateConfig() *server.Config {
 			MaxPeers:         p.rawConfig.Network.MaxPeers,
 			MaxInboundPeers:  p.rawConfig.Network.MaxInboundPeers,
 			MaxOutboundPeers: p.rawConfig.Network.MaxOutboundPeers,
+			TargetPeers:      p.rawConfig.Network.TargetPeers,
 			Chain:            p.genesisConfig,
 		},
-		DataDir:        p.rawConfig.DataDir,
-		Seal:           p.rawConfig.ShouldSeal,
-		PriceLimit:     p.rawConfig.TxPool.PriceLimit,
-		MaxSlots:       p.rawConfig.TxPool.MaxSlots,
-		SecretsManager: p.secretsConfig,
-		RestoreFile:    p.getRestoreFilePath(),
-		BlockTime:      p.rawConfig.BlockTime,
-		LogLevel:       hclog.LevelFromString(p.rawConfig.LogLevel),
-		LogFilePath:    p.logFileLocation,
+		Syncer: &syncer.Config{
+			BatchSize:            p.rawConfig.Syncer.BatchSize,
+			MaxPeers:             p.rawConfig.Syncer.MaxPeers,
+			BlockTimeout:         time.Duration(p.rawConfig.Syncer.BlockTimeout) * time.Second,
+			MinBlockTimeout:      time.Duration(p.rawConfig.Syncer.MinBlockTimeout) * time.Second,
+			MaxBlockTimeout:      time.Duration(p.rawConfig.Syncer.MaxBlockTimeout) * time.Second,
+			RequestRateLimit:     p.rawConfig.Syncer.RequestRateLimit,
+			RequestBurst:         p.rawConfig.Syncer.RequestBurst,
+			MaxConcurrentStreams: p.rawConfig.Syncer.MaxConcurrentStreams,
+			StreamWriteTimeout:   time.Duration(p.rawConfig.Syncer.StreamWriteTimeout) * time.Second,
+			Compression:          p.syncCompression,
+			TrustedCheckpoints:   p.syncTrustedCheckpoints,
+			Mode:                 p.syncMode,
+			PrefetchCacheSize:    p.rawConfig.Syncer.PrefetchCacheSize,
+			TrustedPeers:         p.syncTrustedPeers,
+			MaxSessionBlocks:     p.rawConfig.Syncer.MaxSessionBlocks,
+			PeerStatusTTL:        time.Duration(p.rawConfig.Syncer.PeerStatusTTL) * time.Second,
+			RetainedBlocks:       p.rawConfig.Syncer.RetainedBlocks,
+			ArchivePeers:         p.syncArchivePeers,
+			VerifyWorkers:        p.rawConfig.Syncer.VerifyWorkers,
+			KeepaliveInterval:    time.Duration(p.rawConfig.Syncer.KeepaliveInterval) * time.Second,
+			KeepaliveTimeout:     time.Duration(p.rawConfig.Syncer.KeepaliveTimeout) * time.Second,
+			HedgeDelay:           time.Duration(p.rawConfig.Syncer.HedgeDelay) * time.Millisecond,
+			EraSync:              p.rawConfig.Syncer.EraSync,
+			SyncReceipts:         p.rawConfig.Syncer.SyncReceipts,
+			CheckpointPath: filepath.Join(
+				p.rawConfig.DataDir,
+				"blockchain",
+				syncer.CheckpointFileName,
+			),
+			ReputationPath: filepath.Join(
+				p.rawConfig.DataDir,
+				"blockchain",
+				syncer.ReputationFileName,
+			),
+		},
+		DataDir:               p.rawConfig.DataDir,
+		Seal:                  p.rawConfig.ShouldSeal,
+		PriceLimit:            p.rawConfig.TxPool.PriceLimit,
+		MaxSlots:              p.rawConfig.TxPool.MaxSlots,
+		ExemptAddresses:       p.txPoolExemptAddresses,
+		LocalAddresses:        p.txPoolLocalAddresses,
+		FutureTxTypes:         p.txPoolFutureTxTypes,
+		OperatorAccount:       p.txPoolOperatorAccount,
+		TxPoolJournalPath:     p.rawConfig.TxPool.JournalPath,
+		TxPoolJournalInterval: time.Duration(p.rawConfig.TxPool.JournalInterval) * time.Second,
+		TxPoolPriceBump:       p.rawConfig.TxPool.PriceBump,
+		TxPoolMaxAccountTxs:   p.rawConfig.TxPool.MaxAccountTxs,
+		TxPoolMaxEnqueued:     p.rawConfig.TxPool.MaxEnqueued,
+		SecretsManager:        p.secretsConfig,
+		RestoreFile:           p.getRestoreFilePath(),
+		BlockTime:             p.rawConfig.BlockTime,
+		BlockDeadline:         p.rawConfig.BlockDeadline,
+		IBFTDryRun:            p.rawConfig.IBFTDryRun,
+		IBFTBuilderKeys:       p.ibftBuilderKeys,
+		IBFTBuilderTimeout:    time.Duration(p.rawConfig.IBFTBuilderTimeout) * time.Millisecond,
+		StateCommitInterval:   p.rawConfig.StateCommitInterval,
+		OpcodeStats:           p.rawConfig.OpcodeStats,
+		StatePrefetchWorkers:  int(p.rawConfig.StatePrefetchWorkers),
+		RecordPreimages:       p.rawConfig.RecordPreimages,
+		StateSnapshot:         p.rawConfig.StateSnapshot,
+		StorageCompression:    p.storageCompression,
+		FreezerThreshold:      p.rawConfig.FreezerThreshold,
+		TxLookupLimit:         p.rawConfig.TxLookupLimit,
+		DBEngine:              p.dbEngine,
+		CompactReceipts:       p.rawConfig.DBCompressReceipts,
+		FutureBlockTolerance:  time.Duration(p.rawConfig.FutureBlockTolerance) * time.Second,
+		LogLevel:              hclog.LevelFromString(p.rawConfig.LogLevel),
+		LogFilePath:           p.logFileLocation,
+	}
+
+	// only the settings of a config file are reloaded
+	if p.configPath != "" {
+		config.LoadReloadableConfig = p.loadReloadableConfig
 	}
+
+	return config
 }
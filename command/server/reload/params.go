@@ -0,0 +1,45 @@
+package reload
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+var (
+	params = &reloadParams{}
+)
+
+type reloadParams struct {
+	resp *proto.ReloadConfigResponse
+}
+
+func (p *reloadParams) reload(grpcAddress string) error {
+	client, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	p.resp, err = client.ReloadConfig(context.Background(), &empty.Empty{})
+
+	return err
+}
+
+func (p *reloadParams) getResult() command.CommandResult {
+	result := &ReloadResult{
+		Changes: make([]ConfigChange, 0, len(p.resp.Changes)),
+	}
+
+	for _, change := range p.resp.Changes {
+		result.Changes = append(result.Changes, ConfigChange{
+			Key: change.Key,
+			Old: change.Old,
+			New: change.New,
+		})
+	}
+
+	return result
+}
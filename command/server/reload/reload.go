@@ -0,0 +1,33 @@
+package reload
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	reloadCmd := &cobra.Command{
+		Use: "reload",
+		Short: "Makes a running node read its config file again and apply the changed log level, " +
+			"JSON-RPC limits, txpool price limit, peer limits and sync request limits without a restart. " +
+			"Sending SIGHUP to the node does the same",
+		Args: cobra.NoArgs,
+		Run:  runCommand,
+	}
+
+	return reloadCmd
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.reload(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
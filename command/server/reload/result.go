@@ -0,0 +1,42 @@
+package reload
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ConfigChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type ReloadResult struct {
+	Changes []ConfigChange `json:"changes"`
+}
+
+func (r *ReloadResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CONFIG RELOAD]\n")
+
+	if len(r.Changes) == 0 {
+		buffer.WriteString("The config file has no changed setting to apply\n")
+
+		return buffer.String()
+	}
+
+	rows := make([]string, 0, len(r.Changes)+1)
+	rows = append(rows, "Setting|Old|New")
+
+	for _, change := range r.Changes {
+		rows = append(rows, fmt.Sprintf("%s|%s|%s", change.Key, change.Old, change.New))
+	}
+
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
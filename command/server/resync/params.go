@@ -0,0 +1,53 @@
+package resync
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+const (
+	fromFlag  = "from"
+	forceFlag = "force"
+)
+
+var (
+	params = &resyncParams{}
+)
+
+type resyncParams struct {
+	from  uint64
+	force bool
+
+	resp *proto.ResyncResponse
+}
+
+func (p *resyncParams) getRequiredFlags() []string {
+	return []string{
+		fromFlag,
+	}
+}
+
+func (p *resyncParams) resync(grpcAddress string) error {
+	client, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	p.resp, err = client.Resync(context.Background(), &proto.ResyncRequest{
+		From:  p.from,
+		Force: p.force,
+	})
+
+	return err
+}
+
+func (p *resyncParams) getResult() command.CommandResult {
+	return &ResyncResult{
+		From:      p.from,
+		Discarded: p.resp.Discarded,
+		Head:      p.resp.Head,
+	}
+}
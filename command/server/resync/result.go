@@ -0,0 +1,29 @@
+package resync
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ResyncResult struct {
+	From      uint64 `json:"from"`
+	Discarded uint64 `json:"discarded"`
+	Head      uint64 `json:"head"`
+}
+
+func (r *ResyncResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[RESYNC]\n")
+	buffer.WriteString("Discarded the blocks, they are synced again from the peers:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("From block|%d", r.From),
+		fmt.Sprintf("Discarded blocks|%d", r.Discarded),
+		fmt.Sprintf("Head|%d", r.Head),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,51 @@
+package resync
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	resyncCmd := &cobra.Command{
+		Use: "resync",
+		Short: "Discards the blocks of a running node from a number and syncs them again from the peers, " +
+			"verifying them. Useful after a disk corruption or a fixed verification bug",
+		Args: cobra.NoArgs,
+		Run:  runCommand,
+	}
+
+	setFlags(resyncCmd)
+	helper.SetRequiredFlags(resyncCmd, params.getRequiredFlags())
+
+	return resyncCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&params.from,
+		fromFlag,
+		0,
+		"the number of the first block to discard, the blocks from it to the head are synced again",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.force,
+		forceFlag,
+		false,
+		"resync even if the node is an active validator or has no sync peer",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.resync(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/server/config"
 	"github.com/0xPolygon/polygon-edge/command/server/export"
+	"github.com/0xPolygon/polygon-edge/command/server/gastarget"
+	"github.com/0xPolygon/polygon-edge/command/server/reload"
+	"github.com/0xPolygon/polygon-edge/command/server/resync"
 	"github.com/spf13/cobra"
 
 	"github.com/0xPolygon/polygon-edge/command/helper"
@@ -34,6 +37,12 @@ func registerSubcommands(baseCmd *cobra.Command) {
 	baseCmd.AddCommand(
 		// server export
 		export.GetCommand(),
+		// server resync
+		resync.GetCommand(),
+		// server gas-target
+		gastarget.GetCommand(),
+		// server reload
+		reload.GetCommand(),
 	)
 }
 
@@ -104,6 +113,13 @@ func setFlags(cmd *cobra.Command) {
 		"the target block gas limit for the chain. If omitted, the value of the parent block is used",
 	)
 
+	cmd.Flags().BoolVar(
+		&params.rawConfig.BlockGasElastic,
+		blockGasElasticFlag,
+		defaultConfig.BlockGasElastic,
+		"make the block gas limit follow the usage of the blocks, between half the block gas target and the target",
+	)
+
 	cmd.Flags().StringVar(
 		&params.rawConfig.SecretsConfigPath,
 		secretsConfigFlag,
@@ -162,6 +178,14 @@ func setFlags(cmd *cobra.Command) {
 	cmd.Flag(maxOutboundPeersFlag).DefValue = fmt.Sprintf("%d", defaultConfig.Network.MaxOutboundPeers)
 	cmd.MarkFlagsMutuallyExclusive(maxPeersFlag, maxOutboundPeersFlag)
 
+	cmd.Flags().Int64Var(
+		&params.rawConfig.Network.TargetPeers,
+		targetPeersFlag,
+		defaultConfig.Network.TargetPeers,
+		"the number of peers the client maintains by dialing discovered peers "+
+			"and pruning excess connections, the max number of peers if unset",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.TxPool.PriceLimit,
 		priceLimitFlag,
@@ -179,6 +203,79 @@ func setFlags(cmd *cobra.Command) {
 		"maximum slots in the pool",
 	)
 
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.TxPool.ExemptAddresses,
+		txPoolExemptFlag,
+		defaultConfig.TxPool.ExemptAddresses,
+		"the address of a system sender (bridge relayer, staking manager...) exempt from the price limit "+
+			"and the pool capacity, its transactions are included first in the blocks",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.TxPool.LocalAddresses,
+		txPoolLocalFlag,
+		defaultConfig.TxPool.LocalAddresses,
+		"the address of a sender whose transactions are local, as the ones submitted to the node: "+
+			"they are never evicted from the pool and are included in the blocks before the remote ones",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.TxPool.FutureTxTypes,
+		txPoolFutureTxTypeFlag,
+		defaultConfig.TxPool.FutureTxTypes,
+		"a transaction type activating at a fork, in the format <type>:<fork block>. Its transactions "+
+			"are held until the fork and then gossiped, instead of being rejected as unsupported",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.TxPool.OperatorAccount,
+		txPoolOperatorAccountFlag,
+		defaultConfig.TxPool.OperatorAccount,
+		"the account of the relayer embedded with the node. The nonces it reserves are persisted, "+
+			"and skipped by the pending nonce of the account until its transactions are written",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.TxPool.JournalPath,
+		txPoolJournalFlag,
+		defaultConfig.TxPool.JournalPath,
+		"the file the pending transactions are journaled to, and replayed from on start "+
+			"(default in the blockchain directory of the data dir)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.JournalInterval,
+		txPoolJournalIntervalFlag,
+		defaultConfig.TxPool.JournalInterval,
+		"the interval in seconds the pending transactions are journaled at, "+
+			"they are also journaled on shutdown. The journal is disabled if 0",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.PriceBump,
+		txPoolPriceBumpFlag,
+		defaultConfig.TxPool.PriceBump,
+		"the percentage a transaction replacing a pending one of the same sender and nonce "+
+			"has to raise the gas price by",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.MaxAccountTxs,
+		txPoolMaxAccountTxsFlag,
+		defaultConfig.TxPool.MaxAccountTxs,
+		"maximum pending transactions per account in the pool, unlimited if 0. "+
+			"When reached, the cheapest enqueued transaction of the account is evicted for a better paying one",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.MaxEnqueued,
+		txPoolMaxEnqueuedFlag,
+		defaultConfig.TxPool.MaxEnqueued,
+		"maximum enqueued (future nonce) transactions in the pool, unlimited if 0. "+
+			"When reached, or when the pool slots are full, the cheapest enqueued transaction is evicted "+
+			"for a better paying one",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.BlockTime,
 		blockTimeFlag,
@@ -186,6 +283,327 @@ func setFlags(cmd *cobra.Command) {
 		"minimum block time in seconds (at least 1s)",
 	)
 
+	cmd.Flags().Float64Var(
+		&params.rawConfig.BlockDeadline,
+		blockDeadlineFlag,
+		defaultConfig.BlockDeadline,
+		"the fraction of the block time the proposer executes transactions within, in (0, 1]. "+
+			"Past it, the block is proposed partially filled",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.IBFTDryRun,
+		ibftDryRunFlag,
+		false,
+		"follow and verify the IBFT consensus as a validator without signing nor gossiping any message, "+
+			"the messages the node would have sent are logged instead",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.IBFTBuilderKeys,
+		ibftBuilderKeyFlag,
+		defaultConfig.IBFTBuilderKeys,
+		"a key an external block builder authenticates with in the x-builder-key gRPC metadata, "+
+			"in the format <name>:<key>. The proposer executes the payload submitted by a builder for its blocks, "+
+			"and builds them itself if none is valid in time. If omitted, the external builders are disabled",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.IBFTBuilderTimeout,
+		ibftBuilderTimeoutFlag,
+		defaultConfig.IBFTBuilderTimeout,
+		"the time in milliseconds the proposer waits for an external builder payload before building the block itself",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.BatchSize,
+		syncBatchSizeFlag,
+		defaultConfig.Syncer.BatchSize,
+		"the number of block headers requested at once while syncing",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.MaxPeers,
+		syncMaxPeersFlag,
+		defaultConfig.Syncer.MaxPeers,
+		"the maximum number of peers the block bodies are fetched from concurrently while syncing",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.BlockTimeout,
+		syncBlockTimeoutFlag,
+		defaultConfig.Syncer.BlockTimeout,
+		"the timeout in seconds for receiving a block or a batch of headers or bodies while syncing. "+
+			"If omitted, 3 times the block time is used",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.MinBlockTimeout,
+		syncMinBlockTimeoutFlag,
+		defaultConfig.Syncer.MinBlockTimeout,
+		"the floor in seconds of the block timeout, which adapts to the size of the synced blocks "+
+			"and the throughput of the peer. If omitted, the block timeout is used",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.MaxBlockTimeout,
+		syncMaxBlockTimeoutFlag,
+		defaultConfig.Syncer.MaxBlockTimeout,
+		"the ceiling in seconds of the block timeout, which adapts to the size of the synced blocks "+
+			"and the throughput of the peer",
+	)
+
+	cmd.Flags().Float64Var(
+		&params.rawConfig.Syncer.RequestRateLimit,
+		syncRateLimitFlag,
+		defaultConfig.Syncer.RequestRateLimit,
+		"the number of requests per second a peer can send to the sync server",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.RequestBurst,
+		syncRateBurstFlag,
+		defaultConfig.Syncer.RequestBurst,
+		"the number of requests a peer can send at once to the sync server, above the rate limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.MaxConcurrentStreams,
+		syncMaxStreamsFlag,
+		defaultConfig.Syncer.MaxConcurrentStreams,
+		"the maximum number of block streams the sync server serves at once",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.StreamWriteTimeout,
+		syncStreamWriteTimeoutFlag,
+		defaultConfig.Syncer.StreamWriteTimeout,
+		"the time in seconds a peer has to read each block streamed by the sync server. "+
+			"The peers reading slower are dropped and refused for a while",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.Syncer.Compression,
+		syncCompressionFlag,
+		defaultConfig.Syncer.Compression,
+		"the compression requested for the blocks streamed while syncing (none, snappy or zstd). "+
+			"Peers without compression support send uncompressed blocks",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Syncer.TrustedCheckpoints,
+		syncCheckpointFlag,
+		defaultConfig.Syncer.TrustedCheckpoints,
+		"a trusted block the synced chain must include, in the format <number>:<hash>. "+
+			"The peers with another block at its height are rejected",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.Syncer.Mode,
+		syncModeFlag,
+		defaultConfig.Syncer.Mode,
+		"the sync mode (full, light or gateway). A light node syncs and verifies the headers only, "+
+			"it has no state and can't seal blocks. The consensus has to verify the headers "+
+			"without the state, which is the case of the PoA chains. A gateway node syncs the blocks "+
+			"but keeps the bodies and the receipts of the recent ones only. The light and the gateway nodes "+
+			"fetch the bodies and the receipts they don't store from the sync peers for the JSON-RPC queries",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.PrefetchCacheSize,
+		syncPrefetchCacheSizeFlag,
+		defaultConfig.Syncer.PrefetchCacheSize,
+		"the number of blocks prefetched from the best peer ahead of the local head, "+
+			"so that they are written without waiting for the network. 0 disables the prefetching",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Syncer.TrustedPeers,
+		syncTrustedPeersFlag,
+		defaultConfig.Syncer.TrustedPeers,
+		"the multiaddr of a peer the blocks are downloaded from in priority, including its peer ID. "+
+			"The node dials the trusted peers on start, and syncs from the other peers only "+
+			"if none of them is ahead",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.MaxSessionBlocks,
+		syncMaxSessionBlocksFlag,
+		defaultConfig.Syncer.MaxSessionBlocks,
+		"the maximum number of blocks written in a sync session before it yields, releasing its memory, "+
+			"and the sync resumes in a new session. 0 is unlimited",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.PeerStatusTTL,
+		syncPeerStatusTTLFlag,
+		defaultConfig.Syncer.PeerStatusTTL,
+		"the time in seconds after which the status of a sync peer not sending any is requested, "+
+			"the peer is not synced from until it sends a status again if it doesn't respond. 0 disables the check",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.RetainedBlocks,
+		syncRetainedBlocksFlag,
+		defaultConfig.Syncer.RetainedBlocks,
+		"the number of recent blocks whose bodies and receipts are kept in the gateway sync mode, "+
+			"at least the state commit interval",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Syncer.ArchivePeers,
+		syncArchivePeersFlag,
+		defaultConfig.Syncer.ArchivePeers,
+		"the multiaddr of an archive peer, including its peer ID, the bodies and the receipts not stored "+
+			"by a light or gateway node are fetched from in priority. The node dials the archive peers on start",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.VerifyWorkers,
+		syncVerifyWorkersFlag,
+		defaultConfig.Syncer.VerifyWorkers,
+		"the number of workers recovering the transaction senders of the synced blocks concurrently, "+
+			"ahead of their in-order verification. 0 uses one worker per CPU",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.KeepaliveInterval,
+		syncKeepaliveIntervalFlag,
+		defaultConfig.Syncer.KeepaliveInterval,
+		"the interval in seconds between the keepalive pings of the sync peers, the peers missing "+
+			"consecutive pings are removed as dead. 0 disables the pings",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.KeepaliveTimeout,
+		syncKeepaliveTimeoutFlag,
+		defaultConfig.Syncer.KeepaliveTimeout,
+		"the time in seconds a sync peer has to answer a keepalive ping",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Syncer.HedgeDelay,
+		syncHedgeDelayFlag,
+		defaultConfig.Syncer.HedgeDelay,
+		"the time in milliseconds the sync peer has to serve the blocks near the chain tip before they are "+
+			"also requested from a second peer, the first response being taken. 0 disables the hedging",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.Syncer.EraSync,
+		syncEraFlag,
+		defaultConfig.Syncer.EraSync,
+		"whether the complete eras of 8192 blocks ahead of the local head are synced as a single archive "+
+			"from the peers serving them, rather than block by block",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.Syncer.SyncReceipts,
+		syncReceiptsFlag,
+		defaultConfig.Syncer.SyncReceipts,
+		"whether the light sync mode syncs the bodies and the receipts of the blocks along with their headers, "+
+			"so that they are served without being fetched from the peers",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.StateCommitInterval,
+		stateCommitIntervalFlag,
+		defaultConfig.StateCommitInterval,
+		"the number of blocks after which the state kept in memory is written to disk. "+
+			"The state of the blocks not written to disk is recovered by re-executing them on restart",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.OpcodeStats,
+		opcodeStatsFlag,
+		defaultConfig.OpcodeStats,
+		"collect the number of executions and the gas of the opcodes run by each block, "+
+			"exposed by the metrics and the edge_getOpcodeStats endpoint",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.StatePrefetchWorkers,
+		statePrefetchWorkersFlag,
+		defaultConfig.StatePrefetchWorkers,
+		"the number of goroutines loading the accounts a block is sent from and to from the state "+
+			"while its transactions execute, hiding the disk reads. 0 disables the prefetching",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.RecordPreimages,
+		recordPreimagesFlag,
+		defaultConfig.RecordPreimages,
+		"record the addresses and the storage slots hashed to the keys of the committed state, "+
+			"resolved by the edge_getKeyPreimage endpoint",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.StateSnapshot,
+		stateSnapshotFlag,
+		defaultConfig.StateSnapshot,
+		"keep a flat snapshot of the accounts and the storage slots of the state, read by the execution "+
+			"and the JSON-RPC instead of the tries. It is generated in the background when missing",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.PruneBlocks,
+		pruneBlocksFlag,
+		defaultConfig.PruneBlocks,
+		"the number of recent blocks whose bodies and receipts are kept, the older ones being deleted. "+
+			"A node pruning the blocks can't serve them, so it syncs in the gateway mode. Zero keeps all the blocks",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.StorageCompression,
+		storageCompressionFlag,
+		defaultConfig.StorageCompression,
+		"the compression of the block bodies and receipts written to disk, one of none, snappy and zstd. "+
+			"The ones already written are read whatever their compression, "+
+			"and can be rewritten with the compress command",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.FreezerThreshold,
+		freezerThresholdFlag,
+		defaultConfig.FreezerThreshold,
+		"the number of recent blocks kept in leveldb, the headers, bodies and receipts of the older ones "+
+			"being moved to append-only flat files. The blocks already written are migrated gradually. "+
+			"Zero disables the freezer",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxLookupLimit,
+		txLookupLimitFlag,
+		defaultConfig.TxLookupLimit,
+		"the number of recent blocks whose transactions are looked up by hash, the lookups of the older ones "+
+			"being removed. Zero keeps the lookups of all the blocks",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.DBEngine,
+		dbEngineFlag,
+		defaultConfig.DBEngine,
+		"the database engine of the blockchain storage, one of leveldb and pebble. "+
+			"An existing data directory can be converted with the db-migrate command",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.DBCompressReceipts,
+		dbCompressReceiptsFlag,
+		defaultConfig.DBCompressReceipts,
+		"write the receipts in a compact form, without the blooms and the gas used recomputed on read, "+
+			"and with their log data compressed. The receipts already written are read whatever their form",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.FutureBlockTolerance,
+		futureBlockToleranceFlag,
+		defaultConfig.FutureBlockTolerance,
+		"the time in seconds a synced block can be ahead of the local clock, to handle a clock skew "+
+			"with the validators. Such a block is written when its time arrives, the blocks further "+
+			"in the future are rejected",
+	)
+
 	cmd.Flags().StringArrayVar(
 		&params.corsAllowedOrigins,
 		corsOriginFlag,
@@ -208,6 +626,15 @@ func setFlags(cmd *cobra.Command) {
 		"the max block range to be considered when executing json-rpc requests that consider fromBlock/toBlock values (e.g. eth_getLogs)",
 	)
 
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCAPIKeys,
+		jsonRPCAPIKeyFlag,
+		defaultConfig.JSONRPCAPIKeys,
+		"an API key the json-rpc requests must carry in the X-API-Key header or the apikey query parameter, "+
+			"in the format <name>:<key>[:<requests per second>[:<burst>]]. The requests are counted per key name "+
+			"in the metrics. If omitted, the requests aren't authenticated",
+	)
+
 	cmd.Flags().StringVar(
 		&params.rawConfig.LogFilePath,
 		logFileLocationFlag,
@@ -298,5 +725,6 @@ func runServerLoop(
 		return err
 	}
 
-	return helper.HandleSignals(serverInstance.Close, outputter)
+	// the server reloads its config file on SIGHUP
+	return helper.HandleSignals(serverInstance.Close, outputter, config.LoadReloadableConfig != nil)
 }
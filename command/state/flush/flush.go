@@ -0,0 +1,46 @@
+package flush
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flush",
+		Short: "Writes the state kept in memory to disk, when the state commit interval is greater than 1",
+		Args:  cobra.NoArgs,
+		Run:   runCommand,
+	}
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	resp, err := flushState(helper.GetGRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(&StateFlushResult{
+		BlockNumber: resp.Number,
+		Written:     resp.Written,
+	})
+}
+
+func flushState(grpcAddress string) (*proto.FlushStateResponse, error) {
+	client, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.FlushState(context.Background(), &empty.Empty{})
+}
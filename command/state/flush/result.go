@@ -0,0 +1,25 @@
+package flush
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type StateFlushResult struct {
+	BlockNumber uint64 `json:"block_number"`
+	Written     uint64 `json:"written"`
+}
+
+func (r *StateFlushResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[STATE FLUSH]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Persisted Up To Block|%d", r.BlockNumber),
+		fmt.Sprintf("Entries Written|%d", r.Written),
+	}))
+
+	return buffer.String()
+}
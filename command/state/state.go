@@ -0,0 +1,27 @@
+package state
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/state/flush"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	stateCmd := &cobra.Command{
+		Use:   "state",
+		Short: "Top level command for interacting with the world state. Only accepts subcommands.",
+	}
+
+	helper.RegisterGRPCAddressFlag(stateCmd)
+
+	registerSubcommands(stateCmd)
+
+	return stateCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// state flush
+		flush.GetCommand(),
+	)
+}
@@ -12,6 +12,31 @@ type StatusResult struct {
 	CurrentBlockNumber int64  `json:"current_block_number"`
 	CurrentBlockHash   string `json:"current_block_hash"`
 	LibP2PAddress      string `json:"libp2p_address"`
+
+	Snapshot SnapshotStatusResult `json:"state_snapshot"`
+}
+
+type SnapshotStatusResult struct {
+	Enabled    bool    `json:"enabled"`
+	Ready      bool    `json:"ready"`
+	Generating bool    `json:"generating"`
+	Progress   float64 `json:"progress"`
+	Accounts   uint64  `json:"accounts"`
+	Slots      uint64  `json:"slots"`
+	Root       string  `json:"root"`
+}
+
+func (r *SnapshotStatusResult) state() string {
+	switch {
+	case !r.Enabled:
+		return "disabled"
+	case r.Generating:
+		return fmt.Sprintf("generating (%.2f%%)", r.Progress)
+	case r.Ready:
+		return "ready"
+	default:
+		return "unavailable"
+	}
 }
 
 func (r *StatusResult) GetOutput() string {
@@ -25,5 +50,21 @@ func (r *StatusResult) GetOutput() string {
 		fmt.Sprintf("Libp2p Address|%s", r.LibP2PAddress),
 	}))
 
+	buffer.WriteString("\n\n[STATE SNAPSHOT]\n")
+
+	lines := []string{
+		fmt.Sprintf("State|%s", r.Snapshot.state()),
+	}
+
+	if r.Snapshot.Enabled {
+		lines = append(lines,
+			fmt.Sprintf("Root|%s", r.Snapshot.Root),
+			fmt.Sprintf("Generated Accounts|%d", r.Snapshot.Accounts),
+			fmt.Sprintf("Generated Storage Slots|%d", r.Snapshot.Slots),
+		)
+	}
+
+	buffer.WriteString(helper.FormatKV(lines))
+
 	return buffer.String()
 }
@@ -40,6 +40,15 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		CurrentBlockNumber: statusResponse.Current.Number,
 		CurrentBlockHash:   statusResponse.Current.Hash,
 		LibP2PAddress:      statusResponse.P2PAddr,
+		Snapshot: SnapshotStatusResult{
+			Enabled:    statusResponse.Snapshot.GetEnabled(),
+			Ready:      statusResponse.Snapshot.GetReady(),
+			Generating: statusResponse.Snapshot.GetGenerating(),
+			Progress:   statusResponse.Snapshot.GetProgress(),
+			Accounts:   statusResponse.Snapshot.GetAccounts(),
+			Slots:      statusResponse.Snapshot.GetSlots(),
+			Root:       statusResponse.Snapshot.GetRoot(),
+		},
 	})
 }
 
@@ -0,0 +1,27 @@
+package sync
+
+import (
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/sync/verify"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Top level command for interacting with the block sync. Only accepts subcommands.",
+	}
+
+	helper.RegisterGRPCAddressFlag(syncCmd)
+
+	registerSubcommands(syncCmd)
+
+	return syncCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// sync verify
+		verify.GetCommand(),
+	)
+}
@@ -0,0 +1,73 @@
+package verify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+const (
+	fromFlag = "from"
+	toFlag   = "to"
+	peerFlag = "peer"
+)
+
+var (
+	params = &verifyParams{}
+
+	errInvalidRange = errors.New("the last block must not be lower than the first one")
+)
+
+type verifyParams struct {
+	from   uint64
+	to     uint64
+	peerID string
+
+	resp *proto.SyncVerifyResponse
+}
+
+func (p *verifyParams) getRequiredFlags() []string {
+	return []string{
+		fromFlag,
+		toFlag,
+	}
+}
+
+func (p *verifyParams) validateFlags() error {
+	if p.to < p.from {
+		return errInvalidRange
+	}
+
+	return nil
+}
+
+func (p *verifyParams) verifyBlocks(grpcAddress string) error {
+	client, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	p.resp, err = client.SyncVerify(context.Background(), &proto.SyncVerifyRequest{
+		From:   p.from,
+		To:     p.to,
+		PeerId: p.peerID,
+	})
+
+	return err
+}
+
+func (p *verifyParams) getResult() command.CommandResult {
+	return &SyncVerifyResult{
+		PeerID:     p.resp.PeerId,
+		From:       p.from,
+		To:         p.to,
+		Verified:   p.resp.Verified,
+		Mismatches: p.resp.Mismatches,
+		ElapsedMs:  p.resp.ElapsedMs,
+		VerifyMs:   p.resp.VerifyMs,
+		Error:      p.resp.Error,
+	}
+}
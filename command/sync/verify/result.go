@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SyncVerifyResult struct {
+	PeerID     string   `json:"peer_id"`
+	From       uint64   `json:"from"`
+	To         uint64   `json:"to"`
+	Verified   uint64   `json:"verified"`
+	Mismatches []uint64 `json:"mismatches"`
+	ElapsedMs  uint64   `json:"elapsed_ms"`
+	VerifyMs   uint64   `json:"verify_ms"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// blocksPerSecond returns the verification throughput, excluding the download time
+func (r *SyncVerifyResult) blocksPerSecond() float64 {
+	if r.VerifyMs == 0 {
+		return 0
+	}
+
+	return float64(r.Verified) * 1000 / float64(r.VerifyMs)
+}
+
+func (r *SyncVerifyResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[SYNC VERIFY]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Peer ID|%s", r.PeerID),
+		fmt.Sprintf("Range|%d - %d", r.From, r.To),
+		fmt.Sprintf("Verified blocks|%d", r.Verified),
+		fmt.Sprintf("Blocks differing from the local ones|%d", len(r.Mismatches)),
+		fmt.Sprintf("Elapsed|%d ms", r.ElapsedMs),
+		fmt.Sprintf("Verification time|%d ms", r.VerifyMs),
+		fmt.Sprintf("Verification throughput|%.1f blocks/s", r.blocksPerSecond()),
+	}))
+	buffer.WriteString("\n")
+
+	if len(r.Mismatches) > 0 {
+		buffer.WriteString(fmt.Sprintf("\nDiffering blocks: %v\n", r.Mismatches))
+	}
+
+	if r.Error != "" {
+		buffer.WriteString(fmt.Sprintf("\nVerification stopped: %s\n", r.Error))
+	}
+
+	return buffer.String()
+}
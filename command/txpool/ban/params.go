@@ -0,0 +1,40 @@
+package ban
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	addressFlag = "address"
+	ttlFlag     = "ttl"
+	reasonFlag  = "reason"
+)
+
+var (
+	params = &banParams{}
+)
+
+var (
+	errNegativeTTL = errors.New("the ban duration can't be negative")
+)
+
+type banParams struct {
+	address string
+	ttl     time.Duration
+	reason  string
+}
+
+func (p *banParams) getRequiredFlags() []string {
+	return []string{
+		addressFlag,
+	}
+}
+
+func (p *banParams) validateFlags() error {
+	if p.ttl < 0 {
+		return errNegativeTTL
+	}
+
+	return nil
+}
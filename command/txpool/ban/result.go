@@ -0,0 +1,47 @@
+package ban
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
+)
+
+type SenderBanResult struct {
+	Address   string `json:"address"`
+	Reason    string `json:"reason"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+}
+
+func NewSenderBanResult(ban *txpoolOp.SenderBan) *SenderBanResult {
+	return &SenderBanResult{
+		Address:   ban.Address,
+		Reason:    ban.Reason,
+		ExpiresAt: ban.ExpiresAt,
+	}
+}
+
+// FormatExpiry returns the expiry time of the ban in a human readable form
+func FormatExpiry(expiresAt int64) string {
+	if expiresAt == 0 {
+		return "never"
+	}
+
+	return time.Unix(expiresAt, 0).UTC().Format(time.RFC3339)
+}
+
+func (r *SenderBanResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[TXPOOL BAN]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Sender|%s", r.Address),
+		fmt.Sprintf("Reason|%s", r.Reason),
+		fmt.Sprintf("Expires|%s", FormatExpiry(r.ExpiresAt)),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
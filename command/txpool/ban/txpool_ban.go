@@ -0,0 +1,78 @@
+package ban
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	txPoolBanCmd := &cobra.Command{
+		Use: "ban",
+		Short: "Bans a sender from the transaction pool, its transactions are rejected until the ban expires. " +
+			"The ban is persisted, and the transactions of the sender already in the pool are kept",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(txPoolBanCmd)
+	helper.SetRequiredFlags(txPoolBanCmd, params.getRequiredFlags())
+
+	return txPoolBanCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.address,
+		addressFlag,
+		"",
+		"the address of the banned sender",
+	)
+
+	cmd.Flags().DurationVar(
+		&params.ttl,
+		ttlFlag,
+		0,
+		"the duration of the ban, rounded to the second. The ban doesn't expire if 0",
+	)
+
+	cmd.Flags().StringVar(
+		&params.reason,
+		reasonFlag,
+		"",
+		"the reason of the ban, kept with it",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := helper.GetTxPoolClientConnection(helper.GetGRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	ban, err := client.BanSender(context.Background(), &txpoolOp.BanSenderReq{
+		Address: params.address,
+		Ttl:     uint64(params.ttl / time.Second),
+		Reason:  params.reason,
+	})
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(NewSenderBanResult(ban))
+}
@@ -0,0 +1,42 @@
+package bans
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/txpool/ban"
+)
+
+type SenderBansResult struct {
+	Bans []*ban.SenderBanResult `json:"bans"`
+}
+
+func (r *SenderBansResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[TXPOOL BANS]\n")
+
+	if len(r.Bans) == 0 {
+		buffer.WriteString("No banned sender\n")
+
+		return buffer.String()
+	}
+
+	rows := make([]string, 0, len(r.Bans)+1)
+	rows = append(rows, "Sender|Expires|Reason")
+
+	for _, senderBan := range r.Bans {
+		rows = append(rows, fmt.Sprintf(
+			"%s|%s|%s",
+			senderBan.Address,
+			ban.FormatExpiry(senderBan.ExpiresAt),
+			senderBan.Reason,
+		))
+	}
+
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
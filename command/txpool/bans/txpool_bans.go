@@ -0,0 +1,48 @@
+package bans
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/txpool/ban"
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bans",
+		Short: "Returns the active sender bans of the transaction pool",
+		Run:   runCommand,
+	}
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := helper.GetTxPoolClientConnection(helper.GetGRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	resp, err := client.ListSenderBans(context.Background(), &empty.Empty{})
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	result := &SenderBansResult{
+		Bans: make([]*ban.SenderBanResult, 0, len(resp.Bans)),
+	}
+
+	for _, senderBan := range resp.Bans {
+		result.Bans = append(result.Bans, ban.NewSenderBanResult(senderBan))
+	}
+
+	outputter.SetCommandResult(result)
+}
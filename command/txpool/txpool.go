@@ -2,8 +2,11 @@ package txpool
 
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/txpool/ban"
+	"github.com/0xPolygon/polygon-edge/command/txpool/bans"
 	"github.com/0xPolygon/polygon-edge/command/txpool/status"
 	"github.com/0xPolygon/polygon-edge/command/txpool/subscribe"
+	"github.com/0xPolygon/polygon-edge/command/txpool/unban"
 	"github.com/spf13/cobra"
 )
 
@@ -26,5 +29,11 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		status.GetCommand(),
 		// txpool subscribe
 		subscribe.GetCommand(),
+		// txpool ban
+		ban.GetCommand(),
+		// txpool unban
+		unban.GetCommand(),
+		// txpool bans
+		bans.GetCommand(),
 	)
 }
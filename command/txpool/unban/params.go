@@ -0,0 +1,19 @@
+package unban
+
+const (
+	addressFlag = "address"
+)
+
+var (
+	params = &unbanParams{}
+)
+
+type unbanParams struct {
+	address string
+}
+
+func (p *unbanParams) getRequiredFlags() []string {
+	return []string{
+		addressFlag,
+	}
+}
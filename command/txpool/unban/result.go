@@ -0,0 +1,19 @@
+package unban
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type SenderUnbanResult struct {
+	Address string `json:"address"`
+}
+
+func (r *SenderUnbanResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[TXPOOL UNBAN]\n")
+	buffer.WriteString(fmt.Sprintf("Lifted the ban of sender %s\n", r.Address))
+
+	return buffer.String()
+}
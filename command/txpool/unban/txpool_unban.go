@@ -0,0 +1,53 @@
+package unban
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	txPoolUnbanCmd := &cobra.Command{
+		Use:   "unban",
+		Short: "Lifts the ban of a sender from the transaction pool",
+		Run:   runCommand,
+	}
+
+	txPoolUnbanCmd.Flags().StringVar(
+		&params.address,
+		addressFlag,
+		"",
+		"the address of the banned sender",
+	)
+
+	helper.SetRequiredFlags(txPoolUnbanCmd, params.getRequiredFlags())
+
+	return txPoolUnbanCmd
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	client, err := helper.GetTxPoolClientConnection(helper.GetGRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	if _, err := client.UnbanSender(context.Background(), &txpoolOp.UnbanSenderReq{
+		Address: params.address,
+	}); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(&SenderUnbanResult{
+		Address: params.address,
+	})
+}
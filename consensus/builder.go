@@ -0,0 +1,49 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errInvalidBuilderKey   = errors.New("invalid builder key, expected <name>:<key>")
+	errDuplicateBuilderKey = errors.New("duplicate builder key")
+)
+
+// BuilderKey is a key an external block builder authenticates with. The name identifies the builder
+// in the logs, the key itself being a secret
+type BuilderKey struct {
+	Name string
+	Key  string
+}
+
+// ParseBuilderKeys parses the builder keys of the form <name>:<key>
+func ParseBuilderKeys(raw []string) ([]*BuilderKey, error) {
+	keys := make([]*BuilderKey, 0, len(raw))
+	names := make(map[string]bool, len(raw))
+	secrets := make(map[string]bool, len(raw))
+
+	for _, r := range raw {
+		parts := strings.Split(r, ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidBuilderKey, parts[0])
+		}
+
+		key := &BuilderKey{
+			Name: parts[0],
+			Key:  parts[1],
+		}
+
+		if names[key.Name] || secrets[key.Key] {
+			return nil, fmt.Errorf("%w: %s", errDuplicateBuilderKey, key.Name)
+		}
+
+		names[key.Name] = true
+		secrets[key.Key] = true
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
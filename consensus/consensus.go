@@ -3,6 +3,7 @@ package consensus
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
@@ -10,6 +11,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -72,6 +74,21 @@ type Params struct {
 	Metrics        *Metrics
 	SecretsManager secrets.SecretsManager
 	BlockTime      uint64
+	Syncer         *syncer.Config
+
+	// BlockDeadline is the fraction of the block time the proposer executes transactions within
+	BlockDeadline float64
+
+	// DryRun makes a validator follow and verify the consensus messages, without signing
+	// nor sending its own. The messages it would have sent are recorded instead
+	DryRun bool
+
+	// Builders are the keys of the external block builders allowed to submit the payloads of the blocks
+	// the node proposes, the node building its blocks itself if there is none
+	Builders []*BuilderKey
+
+	// BuilderTimeout is the time the proposer waits for a builder payload before building the block itself
+	BuilderTimeout time.Duration
 }
 
 // Factory is the factory function to create a discovery consensus
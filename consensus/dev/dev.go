@@ -66,6 +66,11 @@ func (d *Dev) Initialize() error {
 
 // Start starts the consensus mechanism
 func (d *Dev) Start() error {
+	// the blocks of the single sealer are never reorganized
+	if err := d.blockchain.SetFinalizedHeader(d.blockchain.Header()); err != nil {
+		return err
+	}
+
 	go d.run()
 
 	return nil
@@ -166,6 +171,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 	}
 
 	header.GasLimit = gasLimit
+	d.blockchain.SetBaseFee(header, parent)
 
 	miner, err := d.GetBlockCreator(header)
 	if err != nil {
@@ -204,6 +210,10 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		return err
 	}
 
+	if err := d.blockchain.SetFinalizedHeader(block.Header); err != nil {
+		return err
+	}
+
 	// after the block has been written we reset the txpool so that
 	// the old transactions are removed
 	d.txpool.ResetWithHeaders(block.Header)
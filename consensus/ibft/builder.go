@@ -0,0 +1,200 @@
+package ibft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// builderKeyMetadata is the gRPC metadata carrying the key of an external builder
+	builderKeyMetadata = "x-builder-key"
+
+	// DefaultBuilderTimeout is the default time the proposer waits for a builder payload
+	DefaultBuilderTimeout = 500 * time.Millisecond
+)
+
+var (
+	errBuildersDisabled    = grpcstatus.Error(codes.Unimplemented, "external block builders are disabled")
+	errBuilderKeyRequired  = grpcstatus.Error(codes.Unauthenticated, "missing or unknown builder key")
+	errStaleBuilderPayload = grpcstatus.Error(codes.FailedPrecondition, "payload isn't built on the head of the chain")
+)
+
+// builderPayload are the transactions of the next block, submitted by an external builder
+type builderPayload struct {
+	builder    string
+	parentHash types.Hash
+	txs        []*types.Transaction
+}
+
+// builderPayloads holds the payloads submitted by the external builders for the next block,
+// the latest submission on the head of the chain replacing the previous ones
+type builderPayloads struct {
+	lock sync.Mutex
+
+	keys    map[string]string // builder names by key
+	timeout time.Duration
+
+	payload *builderPayload
+	// notifyCh is closed, and replaced, on each submission
+	notifyCh chan struct{}
+}
+
+// newBuilderPayloads returns the payloads of the given builders, nil if there is none
+func newBuilderPayloads(keys []*consensus.BuilderKey, timeout time.Duration) *builderPayloads {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultBuilderTimeout
+	}
+
+	b := &builderPayloads{
+		keys:     make(map[string]string, len(keys)),
+		timeout:  timeout,
+		notifyCh: make(chan struct{}),
+	}
+
+	for _, key := range keys {
+		b.keys[key.Key] = key.Name
+	}
+
+	return b
+}
+
+// authenticate returns the name of the builder of the request, from the key in its metadata
+func (b *builderPayloads) authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errBuilderKeyRequired
+	}
+
+	for _, key := range md.Get(builderKeyMetadata) {
+		if name, ok := b.keys[key]; ok {
+			return name, nil
+		}
+	}
+
+	return "", errBuilderKeyRequired
+}
+
+// submit holds the payload if it is built on the given head
+func (b *builderPayloads) submit(payload *builderPayload, head types.Hash) error {
+	if payload.parentHash != head {
+		return errStaleBuilderPayload
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.payload = payload
+
+	close(b.notifyCh)
+	b.notifyCh = make(chan struct{})
+
+	return nil
+}
+
+// await returns the payload built on the given parent, waiting for it until the timeout.
+// It returns nil if no payload is submitted in time
+func (b *builderPayloads) await(parentHash types.Hash, closeCh <-chan struct{}) *builderPayload {
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+
+	for {
+		b.lock.Lock()
+		payload, notifyCh := b.payload, b.notifyCh
+		b.lock.Unlock()
+
+		if payload != nil && payload.parentHash == parentHash {
+			return payload
+		}
+
+		select {
+		case <-notifyCh:
+		case <-timer.C:
+			return nil
+		case <-closeCh:
+			return nil
+		}
+	}
+}
+
+// awaitBuilderPayload returns the payload of an external builder for the block on the given parent,
+// nil if the builders are disabled, the block has no transactions or no payload is submitted in time
+func (i *backendIBFT) awaitBuilderPayload(parent *types.Header) *builderPayload {
+	if i.builders == nil || !i.shouldWriteTransactions(parent.Number+1) {
+		return nil
+	}
+
+	return i.builders.await(parent.Hash, i.closeCh)
+}
+
+// writeBuilderPayload executes the transactions of the builder payload, in their order.
+// The whole payload is rejected if any of them fails to execute
+func (i *backendIBFT) writeBuilderPayload(
+	payload *builderPayload,
+	gasLimit uint64,
+	transition transitionInterface,
+) ([]*types.Transaction, error) {
+	for _, tx := range payload.txs {
+		if tx.ExceedsBlockGasLimit(gasLimit) {
+			return nil, fmt.Errorf("transaction %s exceeds the block gas limit", tx.Hash)
+		}
+
+		if err := transition.Write(tx); err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.Hash, err)
+		}
+	}
+
+	return payload.txs, nil
+}
+
+// SubmitPayload submits the transactions of the next block to build, from an external builder
+func (o *operator) SubmitPayload(ctx context.Context, req *proto.BuilderPayload) (*empty.Empty, error) {
+	builders := o.ibft.builders
+	if builders == nil {
+		return nil, errBuildersDisabled
+	}
+
+	name, err := builders.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &builderPayload{
+		builder: name,
+		txs:     make([]*types.Transaction, 0, len(req.Transactions)),
+	}
+
+	if err := payload.parentHash.UnmarshalText([]byte(req.ParentHash)); err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid parent hash: %v", err)
+	}
+
+	for index, raw := range req.Transactions {
+		tx := &types.Transaction{}
+		if err := tx.UnmarshalRLP(raw); err != nil {
+			return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid transaction %d: %v", index, err)
+		}
+
+		payload.txs = append(payload.txs, tx)
+	}
+
+	if err := builders.submit(payload, o.ibft.blockchain.Header().Hash); err != nil {
+		return nil, err
+	}
+
+	o.ibft.logger.Debug("builder payload submitted", "builder", name, "parent", payload.parentHash, "txs", len(payload.txs))
+
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,128 @@
+package ibft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestBuilderPayloads(timeout time.Duration) *builderPayloads {
+	return newBuilderPayloads([]*consensus.BuilderKey{{Name: "builder", Key: "secret"}}, timeout)
+}
+
+func TestBuilderPayloads_Disabled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newBuilderPayloads(nil, time.Second))
+
+	o := &operator{ibft: &backendIBFT{}}
+
+	_, err := o.SubmitPayload(context.Background(), &proto.BuilderPayload{})
+	assert.ErrorIs(t, err, errBuildersDisabled)
+}
+
+func TestBuilderPayloads_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBuilderPayloads(time.Second)
+
+	_, err := b.authenticate(context.Background())
+	assert.ErrorIs(t, err, errBuilderKeyRequired)
+
+	_, err = b.authenticate(metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs(builderKeyMetadata, "unknown"),
+	))
+	assert.ErrorIs(t, err, errBuilderKeyRequired)
+
+	name, err := b.authenticate(metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs(builderKeyMetadata, "secret"),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, "builder", name)
+}
+
+func TestBuilderPayloads_Await(t *testing.T) {
+	t.Parallel()
+
+	head := types.StringToHash("1")
+	b := newTestBuilderPayloads(time.Second)
+
+	// a payload on another block is rejected
+	assert.ErrorIs(t, b.submit(&builderPayload{parentHash: types.StringToHash("2")}, head), errStaleBuilderPayload)
+
+	payload := &builderPayload{builder: "builder", parentHash: head}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, b.submit(payload, head))
+	}()
+
+	assert.Equal(t, payload, b.await(head, nil))
+
+	// the payload is only used for the blocks on its parent
+	b.timeout = 10 * time.Millisecond
+	assert.Nil(t, b.await(types.StringToHash("2"), nil))
+}
+
+func TestBuilderPayloads_AwaitClosed(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBuilderPayloads(time.Minute)
+
+	closeCh := make(chan struct{})
+	close(closeCh)
+
+	assert.Nil(t, b.await(types.StringToHash("1"), closeCh))
+}
+
+// failingTransition fails to execute the transactions with the given nonce
+type failingTransition struct {
+	nonce   uint64
+	written int
+}
+
+func (t *failingTransition) Write(txn *types.Transaction) error {
+	if txn.Nonce == t.nonce {
+		return errors.New("nonce too low")
+	}
+
+	t.written++
+
+	return nil
+}
+
+func (t *failingTransition) WriteFailedReceipt(txn *types.Transaction) error {
+	return nil
+}
+
+func TestWriteBuilderPayload(t *testing.T) {
+	t.Parallel()
+
+	ibft := &backendIBFT{}
+	payload := &builderPayload{
+		txs: []*types.Transaction{
+			{Nonce: 1, Gas: 21000},
+			{Nonce: 2, Gas: 21000},
+		},
+	}
+
+	txs, err := ibft.writeBuilderPayload(payload, 100000, &failingTransition{})
+	assert.NoError(t, err)
+	assert.Equal(t, payload.txs, txs)
+
+	// the whole payload is rejected if any of its transactions fails
+	_, err = ibft.writeBuilderPayload(payload, 100000, &failingTransition{nonce: 2})
+	assert.Error(t, err)
+
+	_, err = ibft.writeBuilderPayload(payload, 20000, &failingTransition{})
+	assert.Error(t, err)
+}
@@ -76,6 +76,8 @@ func (i *backendIBFT) InsertBlock(
 		return
 	}
 
+	i.finalize(header)
+
 	if err := i.runHook(InsertBlockHook, header.Number, header.Number); err != nil {
 		i.logger.Error("cannot run hook", "name", string(InsertBlockHook), "err", err)
 
@@ -136,6 +138,7 @@ func (i *backendIBFT) buildBlock(snap *Snapshot, parent *types.Header) (*types.B
 	}
 
 	header.GasLimit = gasLimit
+	i.blockchain.SetBaseFee(header, parent)
 
 	if hookErr := i.runHook(CandidateVoteHook, header.Number, &candidateVoteHookParams{
 		header: header,
@@ -145,7 +148,8 @@ func (i *backendIBFT) buildBlock(snap *Snapshot, parent *types.Header) (*types.B
 	}
 
 	// set the timestamp
-	header.Timestamp = uint64(time.Now().Unix())
+	now := time.Now()
+	header.Timestamp = uint64(now.Unix())
 
 	// we need to include in the extra field the current set of validators
 	putIbftExtraValidators(header, snap.Set)
@@ -157,7 +161,34 @@ func (i *backendIBFT) buildBlock(snap *Snapshot, parent *types.Header) (*types.B
 	// If the mechanism is PoS -> build a regular block if it's not an end-of-epoch block
 	// If the mechanism is PoA -> always build a regular block, regardless of epoch
 
-	txs := i.writeTransactions(gasLimit, header.Number, transition)
+	var (
+		txs         []*types.Transaction
+		fromBuilder bool
+	)
+
+	if payload := i.awaitBuilderPayload(parent); payload != nil {
+		if txs, err = i.writeBuilderPayload(payload, gasLimit, transition); err == nil {
+			fromBuilder = true
+
+			i.metrics.BuilderBlocks.Add(1)
+			i.logger.Info("executed builder payload", "builder", payload.builder, "txs", len(txs))
+
+			// the block is proposed after the block time, as with the transactions of the pool
+			i.waitUntil(now.Add(i.blockTime))
+		} else {
+			i.metrics.RejectedBuilderPayloads.Add(1)
+			i.logger.Warn("rejected builder payload", "builder", payload.builder, "err", err)
+
+			// the payload was partially executed, the block is built from the pool on a fresh state
+			if transition, err = i.executor.BeginTxn(parent.StateRoot, header, i.validatorKeyAddr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !fromBuilder {
+		txs = i.writeTransactions(gasLimit, header.Number, transition)
+	}
 
 	if err := i.PreStateCommit(header, transition); err != nil {
 		return nil, err
@@ -191,6 +222,17 @@ func (i *backendIBFT) buildBlock(snap *Snapshot, parent *types.Header) (*types.B
 	return block, nil
 }
 
+// waitUntil waits until the given time, or until the node is closed
+func (i *backendIBFT) waitUntil(t time.Time) {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-i.closeCh:
+	}
+}
+
 type status uint8
 
 const (
@@ -221,8 +263,9 @@ func (i *backendIBFT) writeTransactions(
 	}
 
 	var (
-		blockTimer    = time.NewTimer(i.blockTime)
-		stopExecution = false
+		blockCh, stopBlockTimer       = i.startTimer(i.blockTime)
+		deadlineCh, stopDeadlineTimer = i.startTimer(i.blockDeadline)
+		deadlineBound                 = false
 
 		successful = 0
 		failed     = 0
@@ -230,7 +273,14 @@ func (i *backendIBFT) writeTransactions(
 	)
 
 	defer func() {
-		blockTimer.Stop()
+		stopBlockTimer()
+		stopDeadlineTimer()
+
+		i.metrics.BuiltBlocks.Add(1)
+
+		if deadlineBound {
+			i.metrics.DeadlineBoundBlocks.Add(1)
+		}
 
 		i.logger.Info(
 			"executed txs",
@@ -238,6 +288,7 @@ func (i *backendIBFT) writeTransactions(
 			"failed", failed,
 			"skipped", skipped,
 			"remaining", i.txpool.Length(),
+			"deadline_bound", deadlineBound,
 		)
 	}()
 
@@ -245,40 +296,59 @@ func (i *backendIBFT) writeTransactions(
 
 	for {
 		select {
-		case <-blockTimer.C:
+		case <-blockCh:
 			return
-		default:
-			if stopExecution {
-				// wait for the timer to expire
-				continue
-			}
+		case <-deadlineCh:
+			// the block is proposed partially filled,
+			// rather than running past the block time
+			deadlineBound = true
 
-			// execute transactions one by one
-			result, ok := i.writeTransaction(
-				i.txpool.Peek(),
-				transition,
-				gasLimit,
-			)
+			return
+		default:
+		}
 
-			if !ok {
-				stopExecution = true
+		// execute transactions one by one
+		result, ok := i.writeTransaction(
+			i.txpool.Peek(),
+			transition,
+			gasLimit,
+		)
 
-				continue
-			}
+		if !ok {
+			break
+		}
 
-			tx := result.tx
+		tx := result.tx
 
-			switch result.status {
-			case success:
-				executed = append(executed, tx)
-				successful++
-			case fail:
-				failed++
-			case skip:
-				skipped++
-			}
+		switch result.status {
+		case success:
+			executed = append(executed, tx)
+			successful++
+		case fail:
+			failed++
+		case skip:
+			skipped++
 		}
 	}
+
+	// the pool has been drained, wait for the block time
+	select {
+	case <-blockCh:
+	case <-i.closeCh:
+	}
+
+	return
+}
+
+// timerFunc starts a timer firing once after the given duration.
+// It returns the channel of the timer and the function stopping it
+type timerFunc func(time.Duration) (<-chan time.Time, func() bool)
+
+// startTimer starts a timer of the local clock
+func startTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+
+	return timer.C, timer.Stop
 }
 
 func (i *backendIBFT) writeTransaction(
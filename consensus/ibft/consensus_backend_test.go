@@ -0,0 +1,186 @@
+package ibft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTxPool is a pool of the given number of transactions
+type mockTxPool struct {
+	txs []*types.Transaction
+}
+
+func newMockTxPool(count int) *mockTxPool {
+	pool := &mockTxPool{}
+
+	for nonce := 0; nonce < count; nonce++ {
+		pool.txs = append(pool.txs, &types.Transaction{Nonce: uint64(nonce)})
+	}
+
+	return pool
+}
+
+func (p *mockTxPool) Prepare()       {}
+func (p *mockTxPool) Length() uint64 { return uint64(len(p.txs)) }
+
+func (p *mockTxPool) Peek() *types.Transaction {
+	if len(p.txs) == 0 {
+		return nil
+	}
+
+	return p.txs[0]
+}
+
+func (p *mockTxPool) Pop(tx *types.Transaction)                 { p.txs = p.txs[1:] }
+func (p *mockTxPool) Drop(tx *types.Transaction)                { p.txs = p.txs[1:] }
+func (p *mockTxPool) Demote(tx *types.Transaction)              { p.txs = p.txs[1:] }
+func (p *mockTxPool) ResetWithHeaders(headers ...*types.Header) {}
+
+// mockTransition executes the transactions, calling onWrite after each one
+type mockTransition struct {
+	written int
+	onWrite func(written int)
+}
+
+func (t *mockTransition) Write(txn *types.Transaction) error {
+	t.written++
+
+	if t.onWrite != nil {
+		t.onWrite(t.written)
+	}
+
+	return nil
+}
+
+func (t *mockTransition) WriteFailedReceipt(txn *types.Transaction) error {
+	return nil
+}
+
+// mockTimers are the block time and the block deadline timers, fired by the test
+type mockTimers struct {
+	blockCh    chan time.Time
+	deadlineCh chan time.Time
+}
+
+func newMockTimers() *mockTimers {
+	return &mockTimers{
+		blockCh:    make(chan time.Time),
+		deadlineCh: make(chan time.Time, 1),
+	}
+}
+
+// start returns the block timer for the block time, and the deadline timer otherwise
+func (m *mockTimers) start(d time.Duration) (<-chan time.Time, func() bool) {
+	stop := func() bool { return true }
+
+	if d == testBlockTime {
+		return m.blockCh, stop
+	}
+
+	return m.deadlineCh, stop
+}
+
+const (
+	testBlockTime     = 2 * time.Second
+	testBlockDeadline = time.Second
+)
+
+func TestWriteTransactions_Deadline(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name          string
+		txs           int
+		deadlineAfter int
+		executed      int
+	}{
+		{
+			"the pool is drained before the deadline",
+			5,
+			0,
+			5,
+		},
+		{
+			"the deadline cuts the execution",
+			1000,
+			10,
+			10,
+		},
+	}
+
+	for _, test := range testTable {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := newMockTxPool(test.txs)
+			timers := newMockTimers()
+
+			ibft := &backendIBFT{
+				logger:        hclog.NewNullLogger(),
+				txpool:        pool,
+				metrics:       consensus.NilMetrics(),
+				mechanisms:    []ConsensusMechanism{&PoAMechanism{}},
+				blockTime:     testBlockTime,
+				blockDeadline: testBlockDeadline,
+				startTimer:    timers.start,
+			}
+
+			drainedCh := make(chan struct{})
+
+			transition := &mockTransition{
+				onWrite: func(written int) {
+					if written == test.deadlineAfter {
+						timers.deadlineCh <- time.Time{}
+					}
+
+					if written == test.txs {
+						close(drainedCh)
+					}
+				},
+			}
+
+			executedCh := make(chan []*types.Transaction, 1)
+
+			go func() {
+				executedCh <- ibft.writeTransactions(1_000_000, 1, transition)
+			}()
+
+			var executed []*types.Transaction
+
+			if test.deadlineAfter > 0 {
+				// the block is proposed partially filled at the deadline
+				executed = <-executedCh
+				assert.Len(t, pool.txs, test.txs-test.executed)
+			} else {
+				// the block time is waited for once the pool is drained
+				<-drainedCh
+
+				select {
+				case <-executedCh:
+					t.Fatal("the block is proposed before the block time")
+				default:
+				}
+
+				timers.blockCh <- time.Time{}
+				executed = <-executedCh
+				assert.Empty(t, pool.txs)
+			}
+
+			assert.Len(t, executed, test.executed)
+		})
+	}
+}
+
+func TestBlockDeadline(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 2*time.Second, blockDeadline(&consensus.Params{BlockTime: 2}))
+	assert.Equal(t, 2*time.Second, blockDeadline(&consensus.Params{BlockTime: 2, BlockDeadline: 1}))
+	assert.Equal(t, 1500*time.Millisecond, blockDeadline(&consensus.Params{BlockTime: 2, BlockDeadline: 0.75}))
+}
@@ -0,0 +1,44 @@
+package ibft
+
+import (
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/hashicorp/go-hclog"
+)
+
+// dryRunTransport records the messages of a validator in the dry-run mode instead of gossiping them,
+// so that the validator key can be rehearsed on a live network without the risk of equivocating
+// with the validator actually sealing with the same key
+type dryRunTransport struct {
+	logger  hclog.Logger
+	metrics *consensus.Metrics
+}
+
+func (d *dryRunTransport) Multicast(msg *protoIBFT.Message) error {
+	d.metrics.DryRunMessages.Add(1)
+
+	d.logger.Info(
+		"dry run: message not sent",
+		"type", msg.Type.String(),
+		"height", msg.GetView().GetHeight(),
+		"round", msg.GetView().GetRound(),
+		"proposal", hex.EncodeToHex(messageProposalHash(msg)),
+	)
+
+	return nil
+}
+
+// messageProposalHash returns the hash of the proposal the message is about, nil for a round change
+func messageProposalHash(msg *protoIBFT.Message) []byte {
+	switch msg.Type {
+	case protoIBFT.MessageType_PREPREPARE:
+		return msg.GetPreprepareData().GetProposalHash()
+	case protoIBFT.MessageType_PREPARE:
+		return msg.GetPrepareData().GetProposalHash()
+	case protoIBFT.MessageType_COMMIT:
+		return msg.GetCommitData().GetProposalHash()
+	default:
+		return nil
+	}
+}
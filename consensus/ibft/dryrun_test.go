@@ -0,0 +1,90 @@
+package ibft
+
+import (
+	"testing"
+
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun_MessagesNotSigned(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	var (
+		view         = &protoIBFT.View{Height: 1, Round: 0}
+		proposalHash = []byte("proposal hash")
+	)
+
+	newBackend := func(dryRun bool) *backendIBFT {
+		return &backendIBFT{
+			logger:           hclog.NewNullLogger(),
+			validatorKey:     key,
+			validatorKeyAddr: crypto.PubKeyToAddress(&key.PublicKey),
+			dryRun:           dryRun,
+		}
+	}
+
+	// a sealing validator signs its messages and its committed seal
+	commit := newBackend(false).BuildCommitMessage(proposalHash, view)
+	if assert.NotNil(t, commit) {
+		assert.NotEmpty(t, commit.Signature)
+		assert.NotEmpty(t, commit.GetCommitData().CommittedSeal)
+	}
+
+	// in the dry-run mode, the same messages are built without any signature
+	dryRun := newBackend(true)
+
+	commit = dryRun.BuildCommitMessage(proposalHash, view)
+	if assert.NotNil(t, commit) {
+		assert.Empty(t, commit.Signature)
+		assert.Empty(t, commit.GetCommitData().CommittedSeal)
+		assert.Equal(t, proposalHash, commit.GetCommitData().ProposalHash)
+	}
+
+	prepare := dryRun.BuildPrepareMessage(proposalHash, view)
+	if assert.NotNil(t, prepare) {
+		assert.Empty(t, prepare.Signature)
+		assert.Equal(t, dryRun.ID(), prepare.From)
+	}
+}
+
+func TestDryRunTransport_Multicast(t *testing.T) {
+	t.Parallel()
+
+	transport := &dryRunTransport{
+		logger:  hclog.NewNullLogger(),
+		metrics: consensus.NilMetrics(),
+	}
+
+	assert.NoError(t, transport.Multicast(newPrePrepareMessage([]byte("proposal"))))
+	assert.NoError(t, transport.Multicast(&protoIBFT.Message{
+		View: &protoIBFT.View{Height: 1, Round: 1},
+		Type: protoIBFT.MessageType_ROUND_CHANGE,
+	}))
+}
+
+func TestMessageProposalHash(t *testing.T) {
+	t.Parallel()
+
+	proposalHash := []byte("proposal hash")
+
+	assert.Equal(t, proposalHash, messageProposalHash(&protoIBFT.Message{
+		Type: protoIBFT.MessageType_PREPARE,
+		Payload: &protoIBFT.Message_PrepareData{
+			PrepareData: &protoIBFT.PrepareMessage{ProposalHash: proposalHash},
+		},
+	}))
+	assert.Equal(t, proposalHash, messageProposalHash(&protoIBFT.Message{
+		Type: protoIBFT.MessageType_COMMIT,
+		Payload: &protoIBFT.Message_CommitData{
+			CommitData: &protoIBFT.CommitMessage{ProposalHash: proposalHash},
+		},
+	}))
+	assert.Nil(t, messageProposalHash(&protoIBFT.Message{Type: protoIBFT.MessageType_ROUND_CHANGE}))
+}
@@ -1,6 +1,7 @@
 package ibft
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
@@ -20,6 +21,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"google.golang.org/grpc"
 )
 
@@ -27,10 +29,16 @@ const (
 	DefaultEpochSize = 100000
 	IbftKeyName      = "validator.key"
 	ibftProto        = "/ibft/0.2"
+
+	// ibftCompressedProto is the topic of the snappy compressed proposals
+	ibftCompressedProto = "/ibft/0.2/snappy"
 )
 
 var (
 	ErrInvalidHookParam = errors.New("invalid IBFT hook param passed in")
+
+	errResyncActiveValidator = errors.New("the node is an active validator, resyncing may stall the consensus")
+	errResyncNoSyncPeer      = errors.New("no sync peer to download the discarded blocks from")
 )
 
 type txPoolInterface interface {
@@ -75,9 +83,14 @@ type backendIBFT struct {
 	epochSize          uint64
 	quorumSizeBlockNum uint64
 
-	blockTime time.Duration // Minimum block generation time in seconds
+	blockTime     time.Duration // Minimum block generation time in seconds
+	blockDeadline time.Duration // Time the proposer executes transactions within
+	startTimer    timerFunc     // Starts the block time and the block deadline timers
+
+	builders *builderPayloads // Payloads of the external block builders, nil if disabled
 
 	sealing bool // Flag indicating if the node is a sealer
+	dryRun  bool // Flag indicating if the validator follows the consensus without signing nor sending messages
 
 	closeCh chan struct{} // Channel for closing
 }
@@ -122,14 +135,19 @@ func Factory(params *consensus.Params) (consensus.Consensus, error) {
 		epochSize:          epochSize,
 		quorumSizeBlockNum: quorumSizeBlockNum,
 		sealing:            params.Seal,
+		dryRun:             params.DryRun,
 		metrics:            params.Metrics,
 		secretsManager:     params.SecretsManager,
 		blockTime:          time.Duration(params.BlockTime) * time.Second,
+		blockDeadline:      blockDeadline(params),
+		startTimer:         startTimer,
+		builders:           newBuilderPayloads(params.Builders, params.BuilderTimeout),
 		syncer: syncer.NewSyncer(
 			params.Logger,
 			params.Network,
 			params.Blockchain,
-			time.Duration(params.BlockTime)*3*time.Second),
+			syncerConfig(params),
+		),
 	}
 
 	// Initialize the mechanism
@@ -143,6 +161,33 @@ func Factory(params *consensus.Params) (consensus.Consensus, error) {
 	return p, nil
 }
 
+// blockDeadline returns the time the proposer executes transactions within,
+// the whole block time by default
+func blockDeadline(params *consensus.Params) time.Duration {
+	blockTime := time.Duration(params.BlockTime) * time.Second
+
+	if params.BlockDeadline <= 0 || params.BlockDeadline >= 1 {
+		return blockTime
+	}
+
+	return time.Duration(float64(blockTime) * params.BlockDeadline)
+}
+
+// syncerConfig returns the syncer config from the params,
+// the block timeout defaults to 3 block times
+func syncerConfig(params *consensus.Params) *syncer.Config {
+	config := &syncer.Config{}
+	if params.Syncer != nil {
+		*config = *params.Syncer
+	}
+
+	if config.BlockTimeout == 0 {
+		config.BlockTimeout = time.Duration(params.BlockTime) * 3 * time.Second
+	}
+
+	return config
+}
+
 // runHook runs a specified hook if it is present in the hook map
 func (i *backendIBFT) runHook(hookName HookType, height uint64, hookParam interface{}) error {
 	for _, mechanism := range i.mechanisms {
@@ -222,6 +267,7 @@ func (i *backendIBFT) startSyncing() {
 
 	if err := i.syncer.Sync(
 		func(block *types.Block) bool {
+			i.finalize(block.Header)
 			callInsertBlockHook(block.Number())
 			i.txpool.ResetWithHeaders(block.Header)
 
@@ -232,8 +278,19 @@ func (i *backendIBFT) startSyncing() {
 	}
 }
 
+// finalize marks the written block as finalized. A block sealed by a quorum of the validators
+// is final in IBFT, so every block of the chain is
+func (i *backendIBFT) finalize(header *types.Header) {
+	if err := i.blockchain.SetFinalizedHeader(header); err != nil {
+		i.logger.Error("cannot finalize block", "number", header.Number, "hash", header.Hash, "err", err)
+	}
+}
+
 // Start starts the IBFT consensus
 func (i *backendIBFT) Start() error {
+	// the head written before the restart was sealed
+	i.finalize(i.blockchain.Header())
+
 	// Start the syncer
 	if err := i.syncer.Start(); err != nil {
 		return err
@@ -253,6 +310,55 @@ func (i *backendIBFT) GetSyncProgression() *progress.Progression {
 	return i.syncer.GetSyncProgression()
 }
 
+// FetchBody fetches the body of the block of the given header from the sync peers,
+// for the light and the gateway nodes not storing all the bodies
+func (i *backendIBFT) FetchBody(ctx context.Context, header *types.Header) (*types.Body, error) {
+	return i.syncer.FetchBody(ctx, header)
+}
+
+// FetchReceipts fetches the receipts of the block of the given header from the sync peers,
+// for the light and the gateway nodes not storing all the receipts
+func (i *backendIBFT) FetchReceipts(ctx context.Context, header *types.Header) ([]*types.Receipt, error) {
+	return i.syncer.FetchReceipts(ctx, header)
+}
+
+// VerifyBlocks downloads the blocks in the given range from a sync peer and verifies them without writing them
+func (i *backendIBFT) VerifyBlocks(ctx context.Context, from, to uint64, peerID peer.ID) (*syncer.VerifyResult, error) {
+	return i.syncer.VerifyBlocks(ctx, from, to, peerID)
+}
+
+// SetSyncRequestLimits sets the rate and the burst of the requests each peer can send to the sync peer server
+func (i *backendIBFT) SetSyncRequestLimits(rateLimit float64, burst uint64) {
+	i.syncer.SetRequestLimits(rateLimit, burst)
+}
+
+// Resync discards the blocks from the given number and syncs them again from the peers, verifying them.
+// The syncer is stopped while the chain is unwound. Unless forced, it refuses to resync an active validator
+// or a node without sync peer. It returns the number of discarded blocks
+func (i *backendIBFT) Resync(from uint64, force bool) (uint64, error) {
+	if !force {
+		if i.isActiveValidator() {
+			return 0, errResyncActiveValidator
+		}
+
+		if !i.syncer.HasSyncPeer() {
+			return 0, errResyncNoSyncPeer
+		}
+	}
+
+	i.syncer.Stop()
+	defer i.syncer.Restart()
+
+	discarded, err := i.blockchain.DiscardBlocks(from, "resync")
+	if err != nil {
+		return 0, err
+	}
+
+	i.logger.Info("resyncing discarded blocks", "from", from, "blocks", discarded)
+
+	return discarded, nil
+}
+
 // GetIBFTForks returns IBFT fork configurations from chain config
 func GetIBFTForks(ibftConfig map[string]interface{}) ([]IBFTFork, error) {
 	// no fork, only specifying IBFT type in chain config
@@ -9,6 +9,11 @@ import (
 )
 
 func (i *backendIBFT) signMessage(msg *protoIBFT.Message) *protoIBFT.Message {
+	if i.dryRun {
+		// the message is only recorded, it isn't signed so that it can't be replayed
+		return msg
+	}
+
 	raw, err := proto.Marshal(msg)
 	if err != nil {
 		return nil
@@ -68,11 +73,16 @@ func (i *backendIBFT) BuildPrepareMessage(proposalHash []byte, view *protoIBFT.V
 }
 
 func (i *backendIBFT) BuildCommitMessage(proposalHash []byte, view *protoIBFT.View) *protoIBFT.Message {
-	seal, err := writeCommittedSeal(i.validatorKey, proposalHash)
-	if err != nil {
-		i.logger.Error("Unable to build commit message, %v", err)
+	var seal []byte
 
-		return nil
+	if !i.dryRun {
+		var err error
+
+		if seal, err = writeCommittedSeal(i.validatorKey, proposalHash); err != nil {
+			i.logger.Error("Unable to build commit message, %v", err)
+
+			return nil
+		}
 	}
 
 	msg := &protoIBFT.Message{
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/types"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
@@ -25,6 +26,30 @@ func (o *operator) Status(ctx context.Context, req *empty.Empty) (*proto.IbftSta
 		Key: o.ibft.validatorKeyAddr.String(),
 	}
 
+	if o.ibft.syncer != nil {
+		for _, score := range o.ibft.syncer.PeerScores() {
+			peerScore := &proto.SyncPeerScore{
+				Id:             score.ID.String(),
+				Score:          score.Score,
+				Timeouts:       score.Failures[syncer.FailureTimeout],
+				InvalidBlocks:  score.Failures[syncer.FailureInvalidBlock],
+				HashMismatches: score.Failures[syncer.FailureHashMismatch],
+			}
+
+			if !score.BannedUntil.IsZero() {
+				peerScore.BannedUntil = score.BannedUntil.Unix()
+			}
+
+			if score.LastError != nil {
+				peerScore.LastErrorKind = score.LastError.KindName()
+				peerScore.LastError = score.LastError.Error()
+				peerScore.LastErrorTime = score.LastErrorTime.Unix()
+			}
+
+			resp.SyncPeers = append(resp.SyncPeers, peerScore)
+		}
+	}
+
 	return resp, nil
 }
 
@@ -23,6 +23,7 @@ type IbftOperatorClient interface {
 	Propose(ctx context.Context, in *Candidate, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Candidates(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CandidatesResp, error)
 	Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IbftStatusResp, error)
+	SubmitPayload(ctx context.Context, in *BuilderPayload, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type ibftOperatorClient struct {
@@ -69,6 +70,15 @@ func (c *ibftOperatorClient) Status(ctx context.Context, in *emptypb.Empty, opts
 	return out, nil
 }
 
+func (c *ibftOperatorClient) SubmitPayload(ctx context.Context, in *BuilderPayload, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/SubmitPayload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IbftOperatorServer is the server API for IbftOperator service.
 // All implementations must embed UnimplementedIbftOperatorServer
 // for forward compatibility
@@ -77,6 +87,7 @@ type IbftOperatorServer interface {
 	Propose(context.Context, *Candidate) (*emptypb.Empty, error)
 	Candidates(context.Context, *emptypb.Empty) (*CandidatesResp, error)
 	Status(context.Context, *emptypb.Empty) (*IbftStatusResp, error)
+	SubmitPayload(context.Context, *BuilderPayload) (*emptypb.Empty, error)
 	mustEmbedUnimplementedIbftOperatorServer()
 }
 
@@ -96,6 +107,9 @@ func (UnimplementedIbftOperatorServer) Candidates(context.Context, *emptypb.Empt
 func (UnimplementedIbftOperatorServer) Status(context.Context, *emptypb.Empty) (*IbftStatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedIbftOperatorServer) SubmitPayload(context.Context, *BuilderPayload) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPayload not implemented")
+}
 func (UnimplementedIbftOperatorServer) mustEmbedUnimplementedIbftOperatorServer() {}
 
 // UnsafeIbftOperatorServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +195,24 @@ func _IbftOperator_Status_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IbftOperator_SubmitPayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuilderPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).SubmitPayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/SubmitPayload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).SubmitPayload(ctx, req.(*BuilderPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IbftOperator_ServiceDesc is the grpc.ServiceDesc for IbftOperator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -204,6 +236,10 @@ var IbftOperator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _IbftOperator_Status_Handler,
 		},
+		{
+			MethodName: "SubmitPayload",
+			Handler:    _IbftOperator_SubmitPayload_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ibft_operator.proto",
@@ -30,6 +30,12 @@ func ecrecoverImpl(sig, msg []byte) (types.Address, error) {
 	return crypto.PubKeyToAddress(pub), nil
 }
 
+// RecoverProposer recovers the address of the proposer of the block from its seal,
+// for the tools reading the chain without a running consensus
+func RecoverProposer(h *types.Header) (types.Address, error) {
+	return ecrecoverProposer(h)
+}
+
 func ecrecoverProposer(h *types.Header) (types.Address, error) {
 	// get the extra part that contains the seal
 	extra, err := getIbftExtra(h)
@@ -24,6 +24,13 @@ var (
 	errParentSnapshotNotFound = errors.New("parent snapshot not found")
 )
 
+const (
+	// snapshotsFileName is the name of the file the snapshots are saved to
+	snapshotsFileName = "snapshots"
+	// metadataFileName is the name of the file the snapshot metadata is saved to
+	metadataFileName = "metadata"
+)
+
 // setupSnapshot sets up the snapshot store for the IBFT object
 func (i *backendIBFT) setupSnapshot() error {
 	i.store = newSnapshotStore()
@@ -401,11 +408,11 @@ func newSnapshotStore() *snapshotStore {
 func (s *snapshotStore) loadFromPath(path string, l hclog.Logger) error {
 	// Load metadata
 	var meta *snapshotMetadata
-	if err := readDataStore(filepath.Join(path, "metadata"), &meta); err != nil {
+	if err := readDataStore(filepath.Join(path, metadataFileName), &meta); err != nil {
 		// if we can't read metadata file delete it
 		// and log the error that we've encountered
 		l.Error("Could not read metadata snapshot store file", "err", err.Error())
-		os.Remove(filepath.Join(path, "metadata"))
+		os.Remove(filepath.Join(path, metadataFileName))
 		l.Error("Removed invalid metadata snapshot store file")
 	}
 
@@ -415,11 +422,11 @@ func (s *snapshotStore) loadFromPath(path string, l hclog.Logger) error {
 
 	// Load snapshots
 	snaps := []*Snapshot{}
-	if err := readDataStore(filepath.Join(path, "snapshots"), &snaps); err != nil {
+	if err := readDataStore(filepath.Join(path, snapshotsFileName), &snaps); err != nil {
 		// if we can't read snapshot store file delete it
 		// and log the error that we've encountered
 		l.Error("Could not read snapshot store file", "err", err.Error())
-		os.Remove(filepath.Join(path, "snapshots"))
+		os.Remove(filepath.Join(path, snapshotsFileName))
 		l.Error("Removed invalid snapshot store file")
 	}
 
@@ -433,7 +440,7 @@ func (s *snapshotStore) loadFromPath(path string, l hclog.Logger) error {
 // saveToPath saves the snapshot store as a file to the specified path
 func (s *snapshotStore) saveToPath(path string) error {
 	// Write snapshots
-	if err := writeDataStore(filepath.Join(path, "snapshots"), s.list); err != nil {
+	if err := writeDataStore(filepath.Join(path, snapshotsFileName), s.list); err != nil {
 		return err
 	}
 
@@ -441,7 +448,7 @@ func (s *snapshotStore) saveToPath(path string) error {
 	meta := &snapshotMetadata{
 		LastBlock: s.lastNumber,
 	}
-	if err := writeDataStore(filepath.Join(path, "metadata"), meta); err != nil {
+	if err := writeDataStore(filepath.Join(path, metadataFileName), meta); err != nil {
 		return err
 	}
 
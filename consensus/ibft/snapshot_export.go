@@ -0,0 +1,115 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	errSnapshotStoreNotFound = errors.New("snapshot store not found")
+	errSnapshotStoreExists   = errors.New("snapshot store already exists")
+	errEmptySnapshotStore    = errors.New("snapshot store has no snapshots")
+)
+
+// SnapshotStoreExport is the portable form of the snapshot store,
+// containing the validator sets and votes of every stored epoch
+type SnapshotStoreExport struct {
+	// LastBlock is the latest block processed by the snapshot store
+	LastBlock uint64 `json:"lastBlock"`
+
+	// Snapshots are the stored snapshots, sorted by block number
+	Snapshots []*Snapshot `json:"snapshots"`
+}
+
+// validate checks that the export can be loaded as a snapshot store
+func (e *SnapshotStoreExport) validate() error {
+	if len(e.Snapshots) == 0 {
+		return errEmptySnapshotStore
+	}
+
+	for i, snap := range e.Snapshots {
+		if snap == nil {
+			return fmt.Errorf("snapshot %d is empty", i)
+		}
+
+		if len(snap.Set) == 0 {
+			return fmt.Errorf("snapshot at block %d has no validators", snap.Number)
+		}
+
+		if i > 0 && snap.Number <= e.Snapshots[i-1].Number {
+			return fmt.Errorf("snapshot at block %d is out of order", snap.Number)
+		}
+	}
+
+	if last := e.Snapshots[len(e.Snapshots)-1]; last.Number > e.LastBlock {
+		return fmt.Errorf(
+			"snapshot at block %d is past the last block %d",
+			last.Number,
+			e.LastBlock,
+		)
+	}
+
+	return nil
+}
+
+// ExportSnapshotStore reads the snapshot store saved in the given consensus directory.
+// Unlike the node on startup, it fails instead of discarding the files it can't read
+func ExportSnapshotStore(path string) (*SnapshotStoreExport, error) {
+	snapshotsPath := filepath.Join(path, snapshotsFileName)
+
+	if _, err := os.Stat(snapshotsPath); os.IsNotExist(err) {
+		return nil, errSnapshotStoreNotFound
+	}
+
+	export := &SnapshotStoreExport{}
+
+	if err := readDataStore(snapshotsPath, &export.Snapshots); err != nil {
+		return nil, fmt.Errorf("unable to read snapshots: %w", err)
+	}
+
+	var meta *snapshotMetadata
+	if err := readDataStore(filepath.Join(path, metadataFileName), &meta); err != nil {
+		return nil, fmt.Errorf("unable to read snapshot metadata: %w", err)
+	}
+
+	if meta == nil {
+		return nil, errMetadataNotFound
+	}
+
+	export.LastBlock = meta.LastBlock
+
+	if err := export.validate(); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ImportSnapshotStore saves the exported snapshot store into the given consensus directory.
+// An existing snapshot store is only overwritten if force is set
+func ImportSnapshotStore(path string, export *SnapshotStoreExport, force bool) error {
+	if err := export.validate(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(path, snapshotsFileName)); err == nil && !force {
+		return errSnapshotStoreExists
+	}
+
+	//nolint: gosec
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+
+	store := newSnapshotStore()
+
+	for _, snap := range export.Snapshots {
+		store.add(snap)
+	}
+
+	store.updateLastBlock(export.LastBlock)
+
+	return store.saveToPath(path)
+}
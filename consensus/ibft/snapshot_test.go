@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -877,3 +878,90 @@ func TestSnapshot_Store_Find(t *testing.T) {
 	check(21, 20)
 	check(1000, 100)
 }
+
+func TestSnapshot_Store_ExportImport(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	srcDir := getTempDir(t)
+	store := newSnapshotStore()
+
+	for i := 0; i < 3; i++ {
+		store.add(&Snapshot{
+			Number: uint64(i * 10),
+			Hash:   types.StringToHash(strconv.Itoa(i)).String(),
+			Votes: []*Vote{
+				{
+					Validator: pool.get("A").Address(),
+					Address:   pool.get("B").Address(),
+					Authorize: i%2 == 0,
+				},
+			},
+			Set: pool.ValidatorSet(),
+		})
+	}
+
+	store.updateLastBlock(25)
+	assert.NoError(t, store.saveToPath(srcDir))
+
+	export, err := ExportSnapshotStore(srcDir)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(25), export.LastBlock)
+	assert.Len(t, export.Snapshots, 3)
+
+	// import into a fresh consensus directory
+	dstDir := filepath.Join(getTempDir(t), "consensus")
+	assert.NoError(t, ImportSnapshotStore(dstDir, export, false))
+
+	imported := newSnapshotStore()
+	assert.NoError(t, imported.loadFromPath(dstDir, hclog.NewNullLogger()))
+	assert.Equal(t, uint64(25), imported.getLastBlock())
+	assert.Len(t, imported.list, 3)
+
+	for i, snap := range imported.list {
+		assert.Equal(t, store.list[i].Number, snap.Number)
+		assert.Equal(t, store.list[i].Hash, snap.Hash)
+		assert.True(t, store.list[i].Equal(snap))
+	}
+
+	// the existing store is only overwritten if forced
+	assert.ErrorIs(t, ImportSnapshotStore(dstDir, export, false), errSnapshotStoreExists)
+	assert.NoError(t, ImportSnapshotStore(dstDir, export, true))
+}
+
+func TestSnapshot_Store_ExportErrors(t *testing.T) {
+	t.Run("missing store", func(t *testing.T) {
+		_, err := ExportSnapshotStore(getTempDir(t))
+		assert.ErrorIs(t, err, errSnapshotStoreNotFound)
+	})
+
+	t.Run("corrupted store", func(t *testing.T) {
+		dir := getTempDir(t)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, snapshotsFileName), []byte("{"), 0600))
+
+		_, err := ExportSnapshotStore(dir)
+		assert.Error(t, err)
+
+		// the corrupted file is kept
+		_, err = os.Stat(filepath.Join(dir, snapshotsFileName))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid export", func(t *testing.T) {
+		pool := newTesterAccountPool(1)
+
+		invalid := []*SnapshotStoreExport{
+			{LastBlock: 10},
+			{LastBlock: 10, Snapshots: []*Snapshot{{Number: 1}}},
+			{LastBlock: 10, Snapshots: []*Snapshot{{Number: 11, Set: pool.ValidatorSet()}}},
+			{LastBlock: 10, Snapshots: []*Snapshot{
+				{Number: 5, Set: pool.ValidatorSet()},
+				{Number: 1, Set: pool.ValidatorSet()},
+			}},
+		}
+
+		for _, export := range invalid {
+			assert.Error(t, ImportSnapshotStore(getTempDir(t), export, false))
+		}
+	})
+}
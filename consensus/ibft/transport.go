@@ -1,25 +1,97 @@
 package ibft
 
 import (
-	"github.com/0xPolygon/go-ibft/messages/proto"
+	"fmt"
+
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/golang/snappy"
 	"github.com/libp2p/go-libp2p-core/peer"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
+// maxDecompressedMessageSize is the maximum size of a decompressed IBFT message,
+// it prevents a peer from exhausting the memory with a small compressed message
+const maxDecompressedMessageSize = 16 * 1024 * 1024
+
+var errMessageTooLarge = fmt.Errorf("decompressed message exceeds %d bytes", maxDecompressedMessageSize)
+
 type transport interface {
-	Multicast(msg *proto.Message) error
+	Multicast(msg *protoIBFT.Message) error
 }
 
+// gossipTransport gossips the IBFT messages. Proposals are compressed with snappy
+// and gossiped on a separate topic once all the peers of the IBFT topic are subscribed to it,
+// so nodes not supporting the compression keep receiving the proposals uncompressed
 type gossipTransport struct {
-	topic *network.Topic
+	topic           *network.Topic
+	compressedTopic *network.Topic
 }
 
-func (g *gossipTransport) Multicast(msg *proto.Message) error {
-	return g.topic.Publish(msg)
+func (g *gossipTransport) Multicast(msg *protoIBFT.Message) error {
+	if msg.Type != protoIBFT.MessageType_PREPREPARE || !g.peersSupportCompression() {
+		return g.topic.Publish(msg)
+	}
+
+	data, err := googleProto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return g.compressedTopic.Publish(&proto.CompressedMessage{
+		Data: snappy.Encode(nil, data),
+	})
+}
+
+// peersSupportCompression returns whether all the peers of the IBFT topic
+// are subscribed to the compressed topic
+func (g *gossipTransport) peersSupportCompression() bool {
+	compressedPeers := g.compressedTopic.ListPeers()
+	if len(compressedPeers) == 0 {
+		return false
+	}
+
+	supported := make(map[peer.ID]struct{}, len(compressedPeers))
+	for _, peerID := range compressedPeers {
+		supported[peerID] = struct{}{}
+	}
+
+	for _, peerID := range g.topic.ListPeers() {
+		if _, ok := supported[peerID]; !ok {
+			return false
+		}
+	}
+
+	return true
 }
 
-func (i *backendIBFT) Multicast(msg *proto.Message) {
+// decompressMessage decodes the IBFT message of the compressed message
+func decompressMessage(compressed *proto.CompressedMessage) (*protoIBFT.Message, error) {
+	size, err := snappy.DecodedLen(compressed.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxDecompressedMessageSize {
+		return nil, errMessageTooLarge
+	}
+
+	data, err := snappy.Decode(nil, compressed.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &protoIBFT.Message{}
+	if err := googleProto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func (i *backendIBFT) Multicast(msg *protoIBFT.Message) {
 	if err := i.transport.Multicast(msg); err != nil {
 		i.logger.Error("fail to gossip", "err", err)
 	}
@@ -28,7 +100,7 @@ func (i *backendIBFT) Multicast(msg *proto.Message) {
 // setupTransport sets up the gossip transport protocol
 func (i *backendIBFT) setupTransport() error {
 	// Define a new topic
-	topic, err := i.network.NewTopic(ibftProto, &proto.Message{})
+	topic, err := i.network.NewTopic(ibftProto, &protoIBFT.Message{})
 	if err != nil {
 		return err
 	}
@@ -36,34 +108,78 @@ func (i *backendIBFT) setupTransport() error {
 	// Subscribe to the newly created topic
 	if err := topic.Subscribe(
 		func(obj interface{}, _ peer.ID) {
-			msg, ok := obj.(*proto.Message)
+			msg, ok := obj.(*protoIBFT.Message)
 			if !ok {
 				i.logger.Error("invalid type assertion for message request")
 
 				return
 			}
 
-			if !i.isSealing() {
-				// if we are not sealing we do not care about the messages
-				// but we need to subscribe to propagate the messages
+			i.handleMessage(msg)
+		},
+	); err != nil {
+		return err
+	}
+
+	// Subscribing to the compressed topic advertises the support of the compression
+	compressedTopic, err := i.network.NewTopic(ibftCompressedProto, &proto.CompressedMessage{})
+	if err != nil {
+		return err
+	}
+
+	if err := compressedTopic.Subscribe(
+		func(obj interface{}, from peer.ID) {
+			compressed, ok := obj.(*proto.CompressedMessage)
+			if !ok {
+				i.logger.Error("invalid type assertion for compressed message request")
+
 				return
 			}
 
-			i.consensus.AddMessage(msg)
+			msg, err := decompressMessage(compressed)
+			if err != nil {
+				i.logger.Error("failed to decompress message", "peer", from, "err", err)
+
+				return
+			}
 
-			i.logger.Debug(
-				"validator message received",
-				"type", msg.Type.String(),
-				"height", msg.GetView().Height,
-				"round", msg.GetView().Round,
-				"addr", types.BytesToAddress(msg.From).String(),
-			)
+			i.handleMessage(msg)
 		},
 	); err != nil {
 		return err
 	}
 
-	i.transport = &gossipTransport{topic: topic}
+	i.transport = &gossipTransport{
+		topic:           topic,
+		compressedTopic: compressedTopic,
+	}
+
+	if i.dryRun {
+		// the topics are still subscribed to, to receive and verify the messages of the validators
+		i.transport = &dryRunTransport{
+			logger:  i.logger.Named("dry-run"),
+			metrics: i.metrics,
+		}
+	}
 
 	return nil
 }
+
+// handleMessage passes a gossiped IBFT message to the consensus
+func (i *backendIBFT) handleMessage(msg *protoIBFT.Message) {
+	if !i.isSealing() && !i.dryRun {
+		// if we are not sealing we do not care about the messages
+		// but we need to subscribe to propagate the messages
+		return
+	}
+
+	i.consensus.AddMessage(msg)
+
+	i.logger.Debug(
+		"validator message received",
+		"type", msg.Type.String(),
+		"height", msg.GetView().Height,
+		"round", msg.GetView().Round,
+		"addr", types.BytesToAddress(msg.From).String(),
+	)
+}
@@ -0,0 +1,191 @@
+package ibft
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	protoIBFT "github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func newPrePrepareMessage(proposal []byte) *protoIBFT.Message {
+	return &protoIBFT.Message{
+		View: &protoIBFT.View{Height: 1, Round: 0},
+		From: []byte("validator"),
+		Type: protoIBFT.MessageType_PREPREPARE,
+		Payload: &protoIBFT.Message_PreprepareData{
+			PreprepareData: &protoIBFT.PrePrepareMessage{
+				Proposal: proposal,
+			},
+		},
+	}
+}
+
+func TestDecompressMessage(t *testing.T) {
+	t.Parallel()
+
+	msg := newPrePrepareMessage(make([]byte, 4096))
+
+	data, err := googleProto.Marshal(msg)
+	assert.NoError(t, err)
+
+	compressed := &proto.CompressedMessage{Data: snappy.Encode(nil, data)}
+	assert.Less(t, len(compressed.Data), len(data))
+
+	decompressed, err := decompressMessage(compressed)
+	assert.NoError(t, err)
+	assert.True(t, googleProto.Equal(msg, decompressed))
+
+	// the decoded length is checked before decompressing
+	header := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, maxDecompressedMessageSize+1)
+
+	_, err = decompressMessage(&proto.CompressedMessage{Data: header[:n]})
+	assert.ErrorIs(t, err, errMessageTooLarge)
+
+	_, err = decompressMessage(&proto.CompressedMessage{Data: []byte{0xff}})
+	assert.Error(t, err)
+}
+
+// testTransportNode is a network server subscribed to the IBFT topics,
+// forwarding the received messages to the channel of their topic
+type testTransportNode struct {
+	server          *network.Server
+	topic           *network.Topic
+	compressedTopic *network.Topic
+
+	messageCh    chan *protoIBFT.Message
+	compressedCh chan *proto.CompressedMessage
+}
+
+func newTestTransportNode(t *testing.T, supportsCompression bool) *testTransportNode {
+	t.Helper()
+
+	server, err := network.CreateServer(&network.CreateServerParams{
+		ConfigCallback: func(c *network.Config) {
+			c.NoDiscover = true
+		},
+	})
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, server.Close())
+	})
+
+	node := &testTransportNode{
+		server:       server,
+		messageCh:    make(chan *protoIBFT.Message, 1),
+		compressedCh: make(chan *proto.CompressedMessage, 1),
+	}
+
+	node.topic, err = server.NewTopic(ibftProto, &protoIBFT.Message{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, node.topic.Subscribe(func(obj interface{}, _ peer.ID) {
+		msg, _ := obj.(*protoIBFT.Message)
+		node.messageCh <- msg
+	}))
+
+	if supportsCompression {
+		node.compressedTopic, err = server.NewTopic(ibftCompressedProto, &proto.CompressedMessage{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, node.compressedTopic.Subscribe(func(obj interface{}, _ peer.ID) {
+			msg, _ := obj.(*proto.CompressedMessage)
+			node.compressedCh <- msg
+		}))
+	}
+
+	return node
+}
+
+// waitForSubscribers waits until the topic has the given number of subscribed peers
+func waitForSubscribers(t *testing.T, topic *network.Topic, count int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return len(topic.ListPeers()) >= count
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestGossipTransport_Multicast(t *testing.T) {
+	t.Parallel()
+
+	t.Run("proposals are compressed when all peers support it", func(t *testing.T) {
+		t.Parallel()
+
+		proposer := newTestTransportNode(t, true)
+		validator := newTestTransportNode(t, true)
+
+		assert.NoError(t, network.JoinAndWait(proposer.server, validator.server, 0, 0))
+		waitForSubscribers(t, proposer.topic, 1)
+		waitForSubscribers(t, proposer.compressedTopic, 1)
+
+		transport := &gossipTransport{topic: proposer.topic, compressedTopic: proposer.compressedTopic}
+		assert.True(t, transport.peersSupportCompression())
+
+		proposal := newPrePrepareMessage(make([]byte, 4096))
+		assert.NoError(t, transport.Multicast(proposal))
+
+		select {
+		case compressed := <-validator.compressedCh:
+			msg, err := decompressMessage(compressed)
+			assert.NoError(t, err)
+			assert.True(t, googleProto.Equal(proposal, msg))
+		case <-validator.messageCh:
+			t.Fatal("proposal received uncompressed")
+		case <-time.After(10 * time.Second):
+			t.Fatal("proposal not received")
+		}
+
+		// other messages are not compressed
+		prepare := &protoIBFT.Message{
+			View: &protoIBFT.View{Height: 1},
+			Type: protoIBFT.MessageType_PREPARE,
+		}
+		assert.NoError(t, transport.Multicast(prepare))
+
+		select {
+		case msg := <-validator.messageCh:
+			assert.True(t, googleProto.Equal(prepare, msg))
+		case <-validator.compressedCh:
+			t.Fatal("prepare message received compressed")
+		case <-time.After(10 * time.Second):
+			t.Fatal("prepare message not received")
+		}
+	})
+
+	t.Run("proposals are not compressed with a legacy peer", func(t *testing.T) {
+		t.Parallel()
+
+		proposer := newTestTransportNode(t, true)
+		validator := newTestTransportNode(t, true)
+		legacy := newTestTransportNode(t, false)
+
+		assert.NoError(t, network.JoinAndWait(proposer.server, validator.server, 0, 0))
+		assert.NoError(t, network.JoinAndWait(proposer.server, legacy.server, 0, 0))
+		waitForSubscribers(t, proposer.topic, 2)
+		waitForSubscribers(t, proposer.compressedTopic, 1)
+
+		transport := &gossipTransport{topic: proposer.topic, compressedTopic: proposer.compressedTopic}
+		assert.False(t, transport.peersSupportCompression())
+
+		proposal := newPrePrepareMessage(make([]byte, 4096))
+		assert.NoError(t, transport.Multicast(proposal))
+
+		for _, node := range []*testTransportNode{validator, legacy} {
+			select {
+			case msg := <-node.messageCh:
+				assert.True(t, googleProto.Equal(proposal, msg))
+			case <-time.After(10 * time.Second):
+				t.Fatal("proposal not received")
+			}
+		}
+	})
+}
@@ -18,6 +18,17 @@ type Metrics struct {
 
 	// Time between current block and the previous block in seconds
 	BlockInterval metrics.Gauge
+
+	// No.of blocks built by the node
+	BuiltBlocks metrics.Counter
+	// No.of built blocks whose transactions were cut by the proposal deadline
+	DeadlineBoundBlocks metrics.Counter
+	// No.of consensus messages the node would have sent in the dry-run mode
+	DryRunMessages metrics.Counter
+	// No.of built blocks whose transactions were submitted by an external builder
+	BuilderBlocks metrics.Counter
+	// No.of builder payloads rejected by the local validation
+	RejectedBuilderPayloads metrics.Counter
 }
 
 // GetPrometheusMetrics return the consensus metrics instance
@@ -54,15 +65,50 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "block_interval",
 			Help:      "Time between current block and the previous block in seconds.",
 		}, labels).With(labelsWithValues...),
+		BuiltBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "built_blocks",
+			Help:      "Number of blocks built by the node.",
+		}, labels).With(labelsWithValues...),
+		DeadlineBoundBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "deadline_bound_blocks",
+			Help:      "Number of built blocks whose transactions were cut by the proposal deadline.",
+		}, labels).With(labelsWithValues...),
+		DryRunMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "dry_run_messages",
+			Help:      "Number of consensus messages the node would have sent, in the dry-run mode.",
+		}, labels).With(labelsWithValues...),
+		BuilderBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "builder_blocks",
+			Help:      "Number of built blocks whose transactions were submitted by an external builder.",
+		}, labels).With(labelsWithValues...),
+		RejectedBuilderPayloads: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "rejected_builder_payloads",
+			Help:      "Number of builder payloads rejected by the local validation.",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
 // NilMetrics will return the non operational metrics
 func NilMetrics() *Metrics {
 	return &Metrics{
-		Validators:    discard.NewGauge(),
-		Rounds:        discard.NewGauge(),
-		NumTxs:        discard.NewGauge(),
-		BlockInterval: discard.NewGauge(),
+		Validators:              discard.NewGauge(),
+		Rounds:                  discard.NewGauge(),
+		NumTxs:                  discard.NewGauge(),
+		BlockInterval:           discard.NewGauge(),
+		BuiltBlocks:             discard.NewCounter(),
+		DeadlineBoundBlocks:     discard.NewCounter(),
+		DryRunMessages:          discard.NewCounter(),
+		BuilderBlocks:           discard.NewCounter(),
+		RejectedBuilderPayloads: discard.NewCounter(),
 	}
 }
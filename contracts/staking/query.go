@@ -46,7 +46,7 @@ func DecodeValidators(method *abi.Method, returnValue []byte) ([]types.Address,
 }
 
 type TxQueryHandler interface {
-	Apply(*types.Transaction) (*runtime.ExecutionResult, error)
+	ApplyCall(msg *types.Transaction, keepState bool) (*runtime.ExecutionResult, error)
 	GetNonce(types.Address) uint64
 }
 
@@ -57,7 +57,8 @@ func QueryValidators(t TxQueryHandler, from types.Address) ([]types.Address, err
 	}
 
 	selector := method.ID()
-	res, err := t.Apply(&types.Transaction{
+	// the query is a simulated call, paying no base fee
+	res, err := t.ApplyCall(&types.Transaction{
 		From:     from,
 		To:       &AddrStakingContract,
 		Value:    big.NewInt(0),
@@ -65,7 +66,7 @@ func QueryValidators(t TxQueryHandler, from types.Address) ([]types.Address, err
 		GasPrice: big.NewInt(0),
 		Gas:      queryGasLimit,
 		Nonce:    t.GetNonce(from),
-	})
+	}, false)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,241 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/secrets"
+	bn256 "github.com/umbracle/go-eth-bn256"
+)
+
+// BLS signatures over the BN254 curve, with the signatures in G1
+// and the public keys in G2 so that they can be verified with the pairing precompile
+
+const (
+	BLSPrivateKeyLength = 32
+	BLSPublicKeyLength  = 128
+	BLSSignatureLength  = 64
+)
+
+var (
+	// blsFieldModulus is the modulus of the base field of G1
+	blsFieldModulus, _ = new(big.Int).SetString(
+		"21888242871839275222246405745257275088696311157297823662689037894645226208583",
+		10,
+	)
+
+	// blsSqrtExponent is (p+1)/4, the modulus is 3 mod 4 so a square root is a single exponentiation
+	blsSqrtExponent = new(big.Int).Rsh(new(big.Int).Add(blsFieldModulus, big1), 2)
+
+	blsCurveB = big.NewInt(3)
+
+	// blsG2Infinity is the encoding of the point at infinity of G2
+	blsG2Infinity = new(bn256.G2).ScalarBaseMult(new(big.Int)).Marshal()
+
+	// blsSignDomain and blsPossessionDomain separate the messages signed by a validator
+	// from its proof of possession, so that one can't be used as the other
+	blsSignDomain       = []byte("polygon-edge-bls-sign")
+	blsPossessionDomain = []byte("polygon-edge-bls-pop")
+)
+
+var (
+	errInvalidBLSPrivateKey = errors.New("invalid BLS private key")
+	errInvalidBLSPublicKey  = errors.New("invalid BLS public key")
+)
+
+// BLSPrivateKey is the private key of a BLS signer
+type BLSPrivateKey struct {
+	s *big.Int
+}
+
+// BLSPublicKey is the public key of a BLS signer
+type BLSPublicKey struct {
+	p *bn256.G2
+}
+
+// GenerateBLSKey returns a new random BLS private key
+func GenerateBLSKey() (*BLSPrivateKey, error) {
+	s, _, err := bn256.RandomG2(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BLSPrivateKey{s: s}, nil
+}
+
+// ParseBLSPrivateKey parses a raw BLS private key
+func ParseBLSPrivateKey(buf []byte) (*BLSPrivateKey, error) {
+	if len(buf) != BLSPrivateKeyLength {
+		return nil, fmt.Errorf("%w: invalid key length (%dB), should be %dB", errInvalidBLSPrivateKey, len(buf), BLSPrivateKeyLength)
+	}
+
+	s := new(big.Int).SetBytes(buf)
+	if s.Sign() == 0 || s.Cmp(bn256.Order) >= 0 {
+		return nil, errInvalidBLSPrivateKey
+	}
+
+	return &BLSPrivateKey{s: s}, nil
+}
+
+// Marshal returns the raw BLS private key
+func (k *BLSPrivateKey) Marshal() []byte {
+	buf := make([]byte, BLSPrivateKeyLength)
+
+	return k.s.FillBytes(buf)
+}
+
+// PublicKey returns the public key of the BLS private key
+func (k *BLSPrivateKey) PublicKey() *BLSPublicKey {
+	return &BLSPublicKey{p: new(bn256.G2).ScalarBaseMult(k.s)}
+}
+
+// Sign signs the message with the BLS private key
+func (k *BLSPrivateKey) Sign(msg []byte) ([]byte, error) {
+	return k.sign(blsSignDomain, msg)
+}
+
+// ProofOfPossession returns the signature of the BLS public key by its private key,
+// which proves the signer holds the private key and prevents rogue key attacks on aggregation
+func (k *BLSPrivateKey) ProofOfPossession() ([]byte, error) {
+	return k.sign(blsPossessionDomain, k.PublicKey().Marshal())
+}
+
+func (k *BLSPrivateKey) sign(domain, msg []byte) ([]byte, error) {
+	h, err := hashToG1(domain, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(bn256.G1).ScalarMult(h, k.s).Marshal(), nil
+}
+
+// ParseBLSPublicKey parses a raw BLS public key
+func ParseBLSPublicKey(buf []byte) (*BLSPublicKey, error) {
+	if len(buf) != BLSPublicKeyLength {
+		return nil, fmt.Errorf("%w: invalid key length (%dB), should be %dB", errInvalidBLSPublicKey, len(buf), BLSPublicKeyLength)
+	}
+
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidBLSPublicKey, err)
+	}
+
+	// the point must be in the subgroup of G2, and not the point at infinity
+	if isG2Infinity(p) || !isG2Infinity(new(bn256.G2).ScalarMult(p, bn256.Order)) {
+		return nil, errInvalidBLSPublicKey
+	}
+
+	return &BLSPublicKey{p: p}, nil
+}
+
+// Marshal returns the raw BLS public key
+func (k *BLSPublicKey) Marshal() []byte {
+	return k.p.Marshal()
+}
+
+// Verify checks the signature of the message by the BLS public key
+func (k *BLSPublicKey) Verify(msg, signature []byte) bool {
+	return k.verify(blsSignDomain, msg, signature)
+}
+
+// VerifyProofOfPossession checks the proof of possession of the BLS public key
+func (k *BLSPublicKey) VerifyProofOfPossession(proof []byte) bool {
+	return k.verify(blsPossessionDomain, k.Marshal(), proof)
+}
+
+func (k *BLSPublicKey) verify(domain, msg, signature []byte) bool {
+	if len(signature) != BLSSignatureLength {
+		return false
+	}
+
+	sig := new(bn256.G1)
+	if _, err := sig.Unmarshal(signature); err != nil {
+		return false
+	}
+
+	h, err := hashToG1(domain, msg)
+	if err != nil {
+		return false
+	}
+
+	// e(sig, g2) == e(h, pk)
+	return bn256.PairingCheck(
+		[]*bn256.G1{sig, new(bn256.G1).Neg(h)},
+		[]*bn256.G2{new(bn256.G2).ScalarBaseMult(big1), k.p},
+	)
+}
+
+// hashToG1 maps the message to a point of G1 by try-and-increment.
+// The order of G1 is prime, so any point of the curve is in the group
+func hashToG1(domain, msg []byte) (*bn256.G1, error) {
+	counter := make([]byte, 4)
+
+	for i := uint32(0); i < 256; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+
+		x := new(big.Int).SetBytes(Keccak256(domain, msg, counter))
+		x.Mod(x, blsFieldModulus)
+
+		// y^2 = x^3 + 3
+		y2 := new(big.Int).Exp(x, big.NewInt(3), blsFieldModulus)
+		y2.Add(y2, blsCurveB).Mod(y2, blsFieldModulus)
+
+		y := new(big.Int).Exp(y2, blsSqrtExponent, blsFieldModulus)
+		if new(big.Int).Exp(y, big.NewInt(2), blsFieldModulus).Cmp(y2) != 0 {
+			// x isn't on the curve
+			continue
+		}
+
+		buf := make([]byte, BLSSignatureLength)
+		x.FillBytes(buf[:32])
+		y.FillBytes(buf[32:])
+
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(buf); err != nil {
+			return nil, err
+		}
+
+		return p, nil
+	}
+
+	return nil, errors.New("unable to hash the message to a curve point")
+}
+
+func isG2Infinity(p *bn256.G2) bool {
+	return bytes.Equal(p.Marshal(), blsG2Infinity)
+}
+
+// BytesToBLSPrivateKey parses the hex encoded BLS private key of the secrets manager
+func BytesToBLSPrivateKey(input []byte) (*BLSPrivateKey, error) {
+	decoded, err := hex.DecodeString(string(input))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBLSPrivateKey(decoded)
+}
+
+// GenerateAndEncodeBLSPrivateKey returns a newly generated BLS private key and the hex encoding of that private key
+func GenerateAndEncodeBLSPrivateKey() (*BLSPrivateKey, []byte, error) {
+	key, err := GenerateBLSKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, []byte(hex.EncodeToString(key.Marshal())), nil
+}
+
+// ReadBLSKey reads the BLS private key of the validator from the secrets manager
+func ReadBLSKey(manager secrets.SecretsManager) (*BLSPrivateKey, error) {
+	key, err := manager.GetSecret(secrets.ValidatorBLSKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return BytesToBLSPrivateKey(key)
+}
@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBLSKeyEncoding(t *testing.T) {
+	t.Parallel()
+
+	key, encoded, err := GenerateAndEncodeBLSPrivateKey()
+	assert.NoError(t, err)
+
+	key0, err := BytesToBLSPrivateKey(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Marshal(), key0.Marshal())
+
+	pub, err := ParseBLSPublicKey(key.PublicKey().Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, key.PublicKey().Marshal(), pub.Marshal())
+
+	// invalid keys
+	_, err = ParseBLSPrivateKey(make([]byte, BLSPrivateKeyLength))
+	assert.ErrorIs(t, err, errInvalidBLSPrivateKey)
+
+	_, err = ParseBLSPublicKey(make([]byte, BLSPublicKeyLength))
+	assert.ErrorIs(t, err, errInvalidBLSPublicKey)
+
+	_, err = ParseBLSPublicKey(key.PublicKey().Marshal()[1:])
+	assert.ErrorIs(t, err, errInvalidBLSPublicKey)
+}
+
+func TestBLSSign(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateBLSKey()
+	assert.NoError(t, err)
+
+	other, err := GenerateBLSKey()
+	assert.NoError(t, err)
+
+	msg := []byte("message")
+
+	signature, err := key.Sign(msg)
+	assert.NoError(t, err)
+	assert.Len(t, signature, BLSSignatureLength)
+
+	assert.True(t, key.PublicKey().Verify(msg, signature))
+	assert.False(t, key.PublicKey().Verify([]byte("other message"), signature))
+	assert.False(t, other.PublicKey().Verify(msg, signature))
+	assert.False(t, key.PublicKey().Verify(msg, signature[1:]))
+}
+
+func TestBLSProofOfPossession(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateBLSKey()
+	assert.NoError(t, err)
+
+	other, err := GenerateBLSKey()
+	assert.NoError(t, err)
+
+	proof, err := key.ProofOfPossession()
+	assert.NoError(t, err)
+
+	assert.True(t, key.PublicKey().VerifyProofOfPossession(proof))
+	assert.False(t, other.PublicKey().VerifyProofOfPossession(proof))
+
+	// a signature of the public key isn't a proof of possession
+	signature, err := key.Sign(key.PublicKey().Marshal())
+	assert.NoError(t, err)
+	assert.False(t, key.PublicKey().VerifyProofOfPossession(signature))
+}
@@ -27,11 +27,13 @@ type TxSigner interface {
 	CalculateV(parity byte) []byte
 }
 
-// NewSigner creates a new signer object (EIP155 or FrontierSigner)
+// NewSigner creates a new signer object (London, EIP155 or FrontierSigner)
 func NewSigner(forks chain.ForksInTime, chainID uint64) TxSigner {
 	var signer TxSigner
 
-	if forks.EIP155 {
+	if forks.London {
+		signer = NewLondonSigner(chainID)
+	} else if forks.EIP155 {
 		signer = &EIP155Signer{chainID: chainID}
 	} else {
 		signer = &FrontierSigner{}
@@ -227,6 +229,87 @@ func (e *EIP155Signer) CalculateV(parity byte) []byte {
 	return reference.Bytes()
 }
 
+// NewLondonSigner returns a new LondonSigner object
+func NewLondonSigner(chainID uint64) *LondonSigner {
+	return &LondonSigner{EIP155Signer: EIP155Signer{chainID: chainID}}
+}
+
+// LondonSigner signs and recovers the dynamic fee transactions (EIP-1559),
+// the legacy transactions being handled as by the EIP155Signer
+type LondonSigner struct {
+	EIP155Signer
+}
+
+// Hash returns the hash signed by the sender, the hash of the envelope without signature for the typed transactions
+func (l *LondonSigner) Hash(tx *types.Transaction) types.Hash {
+	if tx.Type != types.DynamicFeeTx {
+		return l.EIP155Signer.Hash(tx)
+	}
+
+	return types.BytesToHash(keccak.Keccak256(nil, tx.MarshalDynamicFeeSigningRLP()))
+}
+
+// Sender returns the transaction sender, the V value of the typed transactions being the signature parity
+func (l *LondonSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type != types.DynamicFeeTx {
+		return l.EIP155Signer.Sender(tx)
+	}
+
+	if tx.ChainID == nil || tx.ChainID.Cmp(new(big.Int).SetUint64(l.chainID)) != 0 {
+		return types.Address{}, fmt.Errorf("invalid chain id, expected %d", l.chainID)
+	}
+
+	if tx.V == nil || !tx.V.IsUint64() || tx.V.Uint64() > 1 {
+		return types.Address{}, fmt.Errorf("invalid txn signature")
+	}
+
+	sig, err := encodeSignature(tx.R, tx.S, byte(tx.V.Uint64()))
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	pub, err := Ecrecover(l.Hash(tx).Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	buf := Keccak256(pub[1:])[12:]
+
+	return types.BytesToAddress(buf), nil
+}
+
+// SignTx signs the transaction using the passed in private key
+func (l *LondonSigner) SignTx(
+	tx *types.Transaction,
+	privateKey *ecdsa.PrivateKey,
+) (*types.Transaction, error) {
+	if tx.Type != types.DynamicFeeTx {
+		return l.EIP155Signer.SignTx(tx, privateKey)
+	}
+
+	tx = tx.Copy()
+	tx.ChainID = new(big.Int).SetUint64(l.chainID)
+
+	h := l.Hash(tx)
+
+	sig, err := Sign(privateKey, h[:])
+	if err != nil {
+		return nil, err
+	}
+
+	tx.R = new(big.Int).SetBytes(sig[:32])
+	tx.S = new(big.Int).SetBytes(sig[32:64])
+	tx.V = new(big.Int).SetBytes(l.CalculateV(sig[64]))
+
+	return tx, nil
+}
+
+// CalculateV returns the V value of the dynamic fee transaction signatures, the signature parity.
+// The legacy transactions use the V value of the EIP155Signer
+func (l *LondonSigner) CalculateV(parity byte) []byte {
+	return big.NewInt(int64(parity)).Bytes()
+}
+
 // encodeSignature generates a signature value based on the R, S and V value
 func encodeSignature(R, S *big.Int, V byte) ([]byte, error) {
 	if !ValidateSignatureValues(V, R, S) {
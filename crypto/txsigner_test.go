@@ -143,3 +143,48 @@ func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 		}
 	}
 }
+
+func TestLondonSigner_DynamicFeeTx(t *testing.T) {
+	t.Parallel()
+
+	toAddress := types.StringToAddress("1")
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	signer := NewLondonSigner(100)
+
+	signedTx, err := signer.SignTx(&types.Transaction{
+		Type:      types.DynamicFeeTx,
+		To:        &toAddress,
+		Value:     big.NewInt(1),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       21000,
+	}, key)
+	assert.NoError(t, err)
+	assert.True(t, signedTx.V.Uint64() <= 1)
+
+	// the sender is recovered from the decoded envelope
+	decodedTx := &types.Transaction{}
+	assert.NoError(t, decodedTx.UnmarshalRLP(signedTx.MarshalRLP()))
+
+	from, err := signer.Sender(decodedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the chain id is part of the transaction
+	_, err = NewLondonSigner(1).Sender(decodedTx)
+	assert.Error(t, err)
+
+	// the legacy transactions are signed as by the EIP155 signer
+	legacyTx, err := signer.SignTx(&types.Transaction{
+		To:       &toAddress,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+	}, key)
+	assert.NoError(t, err)
+
+	from, err = NewEIP155Signer(100).Sender(legacyTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+}
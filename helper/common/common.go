@@ -157,6 +157,19 @@ func GetTerminationSignalCh() <-chan os.Signal {
 	return signalCh
 }
 
+// GetInterruptSignalCh returns a channel to emit signals by ctrl + c,
+// leaving SIGHUP to the caller
+func GetInterruptSignalCh() <-chan os.Signal {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(
+		signalCh,
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+
+	return signalCh
+}
+
 // PadLeftOrTrim left-pads the passed in byte array to the specified size,
 // or trims the array if it exceeds the passed in size
 func PadLeftOrTrim(bb []byte, size int) []byte {
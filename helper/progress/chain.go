@@ -13,6 +13,24 @@ const (
 	ChainSyncBulk    ChainSyncType = "bulk-sync"
 )
 
+// ProgressionStage is the stage of the sync reported by a progression event
+type ProgressionStage string
+
+const (
+	// ProgressionStarted is reported when the sync starts
+	ProgressionStarted ProgressionStage = "started"
+	// ProgressionBatch is reported when a batch of blocks is written
+	ProgressionBatch ProgressionStage = "batch"
+	// ProgressionPeerSwitch is reported when the sync continues with another peer
+	ProgressionPeerSwitch ProgressionStage = "peer-switch"
+	// ProgressionCompleted is reported when the sync ends
+	ProgressionCompleted ProgressionStage = "completed"
+)
+
+// progressionEventBufferSize is the number of events buffered for each subscriber.
+// The events are dropped for the subscribers which don't keep up
+const progressionEventBufferSize = 32
+
 // Progression defines the status of the sync
 // progression of the node
 type Progression struct {
@@ -28,6 +46,12 @@ type Progression struct {
 
 	// HighestBlock is the target block in the sync batch
 	HighestBlock uint64
+
+	// Stage is the last stage the sync reached
+	Stage ProgressionStage
+
+	// Peer is the ID of the peer the node is syncing with, if any
+	Peer string
 }
 
 type ProgressionWrapper struct {
@@ -42,6 +66,10 @@ type ProgressionWrapper struct {
 	lock sync.RWMutex
 
 	syncType ChainSyncType
+
+	// subscribers receive a copy of the progression on every stage change
+	subscribers     map[chan *Progression]struct{}
+	subscribersLock sync.Mutex
 }
 
 func NewProgressionWrapper(syncType ChainSyncType) *ProgressionWrapper {
@@ -49,6 +77,48 @@ func NewProgressionWrapper(syncType ChainSyncType) *ProgressionWrapper {
 		progression: nil,
 		stopCh:      make(chan struct{}),
 		syncType:    syncType,
+		subscribers: make(map[chan *Progression]struct{}),
+	}
+}
+
+// Subscribe registers a new listener for the progression events.
+// The returned function cancels the subscription, which closes the event channel
+func (pw *ProgressionWrapper) Subscribe() (<-chan *Progression, func()) {
+	pw.subscribersLock.Lock()
+	defer pw.subscribersLock.Unlock()
+
+	eventCh := make(chan *Progression, progressionEventBufferSize)
+	pw.subscribers[eventCh] = struct{}{}
+
+	var once sync.Once
+
+	return eventCh, func() {
+		once.Do(func() {
+			pw.subscribersLock.Lock()
+			defer pw.subscribersLock.Unlock()
+
+			delete(pw.subscribers, eventCh)
+			close(eventCh)
+		})
+	}
+}
+
+// notify sets the stage of the progression and sends a copy of it to the subscribers.
+// The caller must hold the lock
+func (pw *ProgressionWrapper) notify(stage ProgressionStage) {
+	pw.progression.Stage = stage
+	event := *pw.progression
+
+	pw.subscribersLock.Lock()
+	defer pw.subscribersLock.Unlock()
+
+	for eventCh := range pw.subscribers {
+		eventCopy := event
+
+		select {
+		case eventCh <- &eventCopy:
+		default:
+		}
 	}
 }
 
@@ -65,6 +135,8 @@ func (pw *ProgressionWrapper) StartProgression(
 		StartingBlock: startingBlock,
 	}
 
+	pw.notify(ProgressionStarted)
+
 	go pw.RunUpdateLoop(subscription)
 }
 
@@ -101,6 +173,8 @@ func (pw *ProgressionWrapper) StopProgression() {
 	pw.lock.Lock()
 	defer pw.lock.Unlock()
 
+	pw.notify(ProgressionCompleted)
+
 	pw.progression = nil
 }
 
@@ -120,6 +194,29 @@ func (pw *ProgressionWrapper) UpdateHighestProgression(highestBlock uint64) {
 	pw.progression.HighestBlock = highestBlock
 }
 
+// UpdatePeerProgression sets the peer the node is syncing with
+func (pw *ProgressionWrapper) UpdatePeerProgression(peer string) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	previous := pw.progression.Peer
+	pw.progression.Peer = peer
+
+	if previous != "" && previous != peer {
+		pw.notify(ProgressionPeerSwitch)
+	}
+}
+
+// CompleteBatchProgression sets the last written block of the completed sync batch
+func (pw *ProgressionWrapper) CompleteBatchProgression(currentBlock uint64) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	pw.progression.CurrentBlock = currentBlock
+
+	pw.notify(ProgressionBatch)
+}
+
 // GetProgression returns the latest sync progression
 func (pw *ProgressionWrapper) GetProgression() *Progression {
 	pw.lock.RLock()
@@ -0,0 +1,48 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressionWrapper_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	pw := NewProgressionWrapper(ChainSyncBulk)
+
+	eventCh, cancel := pw.Subscribe()
+
+	pw.StartProgression(1, blockchain.NewMockSubscription())
+	pw.UpdateHighestProgression(100)
+	pw.UpdatePeerProgression("A")
+	pw.CompleteBatchProgression(10)
+
+	// the same peer isn't a switch
+	pw.UpdatePeerProgression("A")
+	pw.UpdatePeerProgression("B")
+	pw.CompleteBatchProgression(20)
+	pw.StopProgression()
+
+	expected := []*Progression{
+		{SyncType: ChainSyncBulk, StartingBlock: 1, Stage: ProgressionStarted},
+		{SyncType: ChainSyncBulk, StartingBlock: 1, CurrentBlock: 10, HighestBlock: 100, Stage: ProgressionBatch, Peer: "A"},
+		{SyncType: ChainSyncBulk, StartingBlock: 1, CurrentBlock: 10, HighestBlock: 100, Stage: ProgressionPeerSwitch, Peer: "B"},
+		{SyncType: ChainSyncBulk, StartingBlock: 1, CurrentBlock: 20, HighestBlock: 100, Stage: ProgressionBatch, Peer: "B"},
+		{SyncType: ChainSyncBulk, StartingBlock: 1, CurrentBlock: 20, HighestBlock: 100, Stage: ProgressionCompleted, Peer: "B"},
+	}
+
+	for _, event := range expected {
+		assert.Equal(t, event, <-eventCh)
+	}
+
+	assert.Nil(t, pw.GetProgression())
+
+	// the channel is closed once the subscription is canceled
+	cancel()
+	cancel()
+
+	_, more := <-eventCh
+	assert.False(t, more)
+}
@@ -0,0 +1,180 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// apiKeyHeader is the header carrying the API key of a request
+	apiKeyHeader = "X-API-Key"
+	// apiKeyQueryParam is the query parameter carrying the API key of a request without the header,
+	// for the clients which can't set headers, such as the browser websockets
+	apiKeyQueryParam = "apikey"
+)
+
+var (
+	errInvalidAPIKey   = errors.New("invalid API key, expected <name>:<key>[:<requests per second>[:<burst>]]")
+	errDuplicateAPIKey = errors.New("duplicate API key")
+	errAPIKeyRequired  = errors.New("missing or unknown API key")
+	errAPIKeyLimited   = errors.New("API key rate limit exceeded")
+)
+
+// APIKey is a key the JSON-RPC clients authenticate with. The name identifies the key
+// in the logs and the metrics, the key itself being a secret
+type APIKey struct {
+	Name string
+	Key  string
+	// RateLimit is the number of requests per second allowed with the key, unlimited if zero
+	RateLimit float64
+	// Burst is the number of requests allowed at once with the key, the rate limit rounded up if zero
+	Burst uint64
+}
+
+// ParseAPIKeys parses the API keys of the form <name>:<key>[:<requests per second>[:<burst>]]
+func ParseAPIKeys(raw []string) ([]*APIKey, error) {
+	keys := make([]*APIKey, 0, len(raw))
+	names := make(map[string]bool, len(raw))
+	secrets := make(map[string]bool, len(raw))
+
+	for _, r := range raw {
+		parts := strings.Split(r, ":")
+		if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidAPIKey, parts[0])
+		}
+
+		key := &APIKey{
+			Name: parts[0],
+			Key:  parts[1],
+		}
+
+		var err error
+
+		if len(parts) > 2 {
+			if key.RateLimit, err = strconv.ParseFloat(parts[2], 64); err != nil || key.RateLimit < 0 {
+				return nil, fmt.Errorf("%w: %s", errInvalidAPIKey, key.Name)
+			}
+		}
+
+		if len(parts) > 3 {
+			if key.Burst, err = strconv.ParseUint(parts[3], 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidAPIKey, key.Name)
+			}
+		}
+
+		if names[key.Name] || secrets[key.Key] {
+			return nil, fmt.Errorf("%w: %s", errDuplicateAPIKey, key.Name)
+		}
+
+		names[key.Name] = true
+		secrets[key.Key] = true
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// apiKeyAuth authenticates the requests with the API keys and limits their rate per key
+type apiKeyAuth struct {
+	keys    map[string]*apiKeyLimiter
+	metrics *Metrics
+}
+
+type apiKeyLimiter struct {
+	name    string
+	limiter *rate.Limiter // nil if the key is unlimited
+}
+
+// newAPIKeyAuth returns the authentication of the given API keys, nil if there is none
+func newAPIKeyAuth(keys []*APIKey, metrics *Metrics) *apiKeyAuth {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	auth := &apiKeyAuth{
+		keys:    make(map[string]*apiKeyLimiter, len(keys)),
+		metrics: metrics,
+	}
+
+	for _, key := range keys {
+		limiter := &apiKeyLimiter{name: key.Name}
+
+		if key.RateLimit > 0 {
+			burst := key.Burst
+			if burst == 0 {
+				burst = uint64(math.Ceil(key.RateLimit))
+			}
+
+			limiter.limiter = rate.NewLimiter(rate.Limit(key.RateLimit), int(burst))
+		}
+
+		auth.keys[key.Key] = limiter
+	}
+
+	return auth
+}
+
+// authenticate returns the API key of the request, from its header or its query,
+// or false if the key is missing or unknown
+func (a *apiKeyAuth) authenticate(r *http.Request) (*apiKeyLimiter, bool) {
+	secret := r.Header.Get(apiKeyHeader)
+	if secret == "" {
+		secret = r.URL.Query().Get(apiKeyQueryParam)
+	}
+
+	key, ok := a.keys[secret]
+	if !ok {
+		a.metrics.UnauthorizedRequests.Add(1)
+
+		return nil, false
+	}
+
+	return key, true
+}
+
+// admit accounts a request with the key, and returns whether it is within the rate limit of the key
+func (a *apiKeyAuth) admit(key *apiKeyLimiter) bool {
+	if key.limiter != nil && !key.limiter.Allow() {
+		a.metrics.RateLimitedRequests.With("api_key", key.name).Add(1)
+
+		return false
+	}
+
+	a.metrics.Requests.With("api_key", key.name).Add(1)
+
+	return true
+}
+
+// middleware rejects the requests without a known API key or exceeding its rate limit.
+// The CORS preflight requests are passed, as the browsers send them without the key
+func (a *apiKeyAuth) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		key, ok := a.authenticate(r)
+		if !ok {
+			http.Error(w, errAPIKeyRequired.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		if !a.admit(key) {
+			http.Error(w, errAPIKeyLimited.Error(), http.StatusTooManyRequests)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := ParseAPIKeys([]string{"partner:secret", "bridge:other:10", "indexer:third:0.5:3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*APIKey{
+		{Name: "partner", Key: "secret"},
+		{Name: "bridge", Key: "other", RateLimit: 10},
+		{Name: "indexer", Key: "third", RateLimit: 0.5, Burst: 3},
+	}, keys)
+
+	for _, raw := range []string{"partner", "partner:", ":secret", "partner:secret:fast", "partner:secret:-1", "a:b:1:2:3"} {
+		_, err := ParseAPIKeys([]string{raw})
+		assert.ErrorIs(t, err, errInvalidAPIKey, raw)
+	}
+
+	_, err = ParseAPIKeys([]string{"partner:secret", "partner:other"})
+	assert.ErrorIs(t, err, errDuplicateAPIKey)
+
+	_, err = ParseAPIKeys([]string{"partner:secret", "bridge:secret"})
+	assert.ErrorIs(t, err, errDuplicateAPIKey)
+}
+
+func TestAPIKeyAuth_Middleware(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newAPIKeyAuth(nil, NilMetrics()))
+
+	auth := newAPIKeyAuth([]*APIKey{
+		{Name: "partner", Key: "secret"},
+		{Name: "limited", Key: "other", RateLimit: 0.001, Burst: 2},
+	}, NilMetrics())
+
+	handler := auth.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, target, key string) int {
+		req := httptest.NewRequest(method, target, nil)
+		if key != "" {
+			req.Header.Set(apiKeyHeader, key)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/", "unknown"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/", "secret"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/?apikey=secret", ""))
+
+	// the CORS preflight requests carry no key
+	assert.Equal(t, http.StatusOK, serve(http.MethodOptions, "/", ""))
+
+	// the burst of the limited key is used up, the other key isn't affected
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/", "other"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/", "other"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(http.MethodPost, "/", "other"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/", "secret"))
+}
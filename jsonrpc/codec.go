@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/0xPolygon/polygon-edge/types"
@@ -94,9 +95,11 @@ func (e *ObjectError) Error() string {
 }
 
 const (
-	PendingBlockNumber  = BlockNumber(-3)
-	LatestBlockNumber   = BlockNumber(-2)
-	EarliestBlockNumber = BlockNumber(-1)
+	SafeBlockNumber      = BlockNumber(-5)
+	FinalizedBlockNumber = BlockNumber(-4)
+	PendingBlockNumber   = BlockNumber(-3)
+	LatestBlockNumber    = BlockNumber(-2)
+	EarliestBlockNumber  = BlockNumber(-1)
 )
 
 type BlockNumber int64
@@ -109,14 +112,16 @@ type BlockNumberOrHash struct {
 // UnmarshalJSON will try to extract the filter's data.
 // Here are the possible input formats :
 //
-// 1 - "latest", "pending" or "earliest"	- self-explaining keywords
+// 1 - "latest", "pending", "earliest", "finalized" or "safe"	- self-explaining keywords
 // 2 - "0x2"								- block number #2 (EIP-1898 backward compatible)
 // 3 - {blockNumber:	"0x2"}				- EIP-1898 compliant block number #2
 // 4 - {blockHash:		"0xe0e..."}			- EIP-1898 compliant block hash 0xe0e...
 func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
-	type bnhCopy BlockNumberOrHash
-
-	var placeholder bnhCopy
+	// the hash is decoded as a string, its length isn't checked by types.Hash
+	var placeholder struct {
+		BlockNumber *BlockNumber `json:"blockNumber,omitempty"`
+		BlockHash   *string      `json:"blockHash,omitempty"`
+	}
 
 	err := json.Unmarshal(data, &placeholder)
 	if err != nil {
@@ -130,7 +135,17 @@ func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
 
 	// Try to extract object
 	bnh.BlockNumber = placeholder.BlockNumber
-	bnh.BlockHash = placeholder.BlockHash
+	bnh.BlockHash = nil
+
+	if placeholder.BlockHash != nil {
+		buf, err := parseFixedData(*placeholder.BlockHash, types.HashLength)
+		if err != nil {
+			return fmt.Errorf("invalid block hash: %w", err)
+		}
+
+		hash := types.BytesToHash(buf)
+		bnh.BlockHash = &hash
+	}
 
 	if bnh.BlockNumber != nil && bnh.BlockHash != nil {
 		return fmt.Errorf("cannot use both block number and block hash as filters")
@@ -154,11 +169,24 @@ func stringToBlockNumber(str string) (BlockNumber, error) {
 		return LatestBlockNumber, nil
 	case "earliest":
 		return EarliestBlockNumber, nil
+	case "finalized":
+		return FinalizedBlockNumber, nil
+	case "safe":
+		return SafeBlockNumber, nil
 	}
 
-	n, err := types.ParseUint64orHex(&str)
+	n, err := parseQuantity(str)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf(
+			"invalid block number %q, want a hex number or one of latest, earliest, pending, finalized, safe: %w",
+			str,
+			err,
+		)
+	}
+
+	// the negative block numbers are the tags
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("block number %s out of range", str)
 	}
 
 	return BlockNumber(n), nil
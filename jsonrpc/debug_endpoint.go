@@ -0,0 +1,50 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// debugStore provides access to the methods needed by debug endpoint
+type debugStore interface {
+	// BadBlocks returns the blocks rejected by the verification or the execution, the latest first
+	BadBlocks() ([]*storage.BadBlock, error)
+}
+
+// Debug is the debug jsonrpc endpoint, serving the methods
+// to diagnose the node
+type Debug struct {
+	store debugStore
+}
+
+type badBlock struct {
+	Hash   types.Hash `json:"hash"`
+	Block  *block     `json:"block"`
+	RLP    argBytes   `json:"rlp"`
+	Reason string     `json:"reason"`
+	// Time is the unix time the block was rejected at
+	Time argUint64 `json:"time"`
+}
+
+// GetBadBlocks returns the blocks rejected by the verification or the execution,
+// the latest first, along with their RLP encoding and the reason of the rejection
+func (d *Debug) GetBadBlocks() (interface{}, error) {
+	bads, err := d.store.BadBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*badBlock, len(bads))
+
+	for i, bad := range bads {
+		res[i] = &badBlock{
+			Hash:   bad.Block.Hash(),
+			Block:  toBlock(bad.Block, true),
+			RLP:    bad.Block.MarshalRLP(),
+			Reason: bad.Reason,
+			Time:   argUint64(bad.Time),
+		}
+	}
+
+	return res, nil
+}
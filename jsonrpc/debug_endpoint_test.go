@@ -0,0 +1,57 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBadBlocksStore struct {
+	bads []*storage.BadBlock
+	err  error
+}
+
+func (m *mockBadBlocksStore) BadBlocks() ([]*storage.BadBlock, error) {
+	return m.bads, m.err
+}
+
+func TestDebug_GetBadBlocks(t *testing.T) {
+	t.Parallel()
+
+	header := &types.Header{Number: 5, ExtraData: []byte{}}
+	header.ComputeHash()
+
+	block := &types.Block{
+		Header:       header,
+		Transactions: []*types.Transaction{},
+		Uncles:       []*types.Header{},
+	}
+
+	store := &mockBadBlocksStore{
+		bads: []*storage.BadBlock{
+			{Block: block, Reason: "invalid state root", Time: 100},
+		},
+	}
+
+	debug := &Debug{store: store}
+
+	res, err := debug.GetBadBlocks()
+	assert.NoError(t, err)
+	assert.Equal(t, []*badBlock{
+		{
+			Hash:   header.Hash,
+			Block:  toBlock(block, true),
+			RLP:    block.MarshalRLP(),
+			Reason: "invalid state root",
+			Time:   100,
+		},
+	}, res)
+
+	store.err = errors.New("journal not readable")
+
+	_, err = debug.GetBadBlocks()
+	assert.ErrorIs(t, err, store.err)
+}
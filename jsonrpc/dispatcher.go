@@ -8,6 +8,7 @@ import (
 	"math"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/hashicorp/go-hclog"
@@ -34,6 +35,8 @@ type endpoints struct {
 	Web3   *Web3
 	Net    *Net
 	TxPool *TxPool
+	Edge   *Edge
+	Debug  *Debug
 }
 
 // Dispatcher handles all json rpc requests by delegating
@@ -45,7 +48,7 @@ type Dispatcher struct {
 	endpoints               endpoints
 	chainID                 uint64
 	priceLimit              uint64
-	jsonRPCBatchLengthLimit uint64
+	jsonRPCBatchLengthLimit uint64 // accessed atomically
 }
 
 func newDispatcher(
@@ -73,16 +76,29 @@ func newDispatcher(
 	return d
 }
 
+// setLimits sets the maximum length of the batch requests, and the maximum block range of the logs queries
+func (d *Dispatcher) setLimits(batchLengthLimit, blockRangeLimit uint64) {
+	atomic.StoreUint64(&d.jsonRPCBatchLengthLimit, batchLengthLimit)
+
+	if d.filterManager != nil {
+		d.filterManager.SetBlockRangeLimit(blockRangeLimit)
+	}
+}
+
 func (d *Dispatcher) registerEndpoints(store JSONRPCStore) {
 	d.endpoints.Eth = &Eth{d.logger, store, d.chainID, d.filterManager, d.priceLimit}
 	d.endpoints.Net = &Net{store, d.chainID}
 	d.endpoints.Web3 = &Web3{}
 	d.endpoints.TxPool = &TxPool{store}
+	d.endpoints.Edge = &Edge{store, d.filterManager, d.priceLimit}
+	d.endpoints.Debug = &Debug{store}
 
 	d.registerService("eth", d.endpoints.Eth)
 	d.registerService("net", d.endpoints.Net)
 	d.registerService("web3", d.endpoints.Web3)
 	d.registerService("txpool", d.endpoints.TxPool)
+	d.registerService("edge", d.endpoints.Edge)
+	d.registerService("debug", d.endpoints.Debug)
 }
 
 func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error) {
@@ -162,6 +178,31 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 	return filterID, nil
 }
 
+func (d *Dispatcher) handleEdgeSubscribe(req Request, conn wsConn) (string, Error) {
+	var params []interface{}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return "", NewInvalidRequestError("Invalid json request")
+	}
+
+	if len(params) == 0 {
+		return "", NewInvalidParamsError("Invalid params")
+	}
+
+	subscribeMethod, ok := params[0].(string)
+	if !ok {
+		return "", NewSubscriptionNotFoundError(subscribeMethod)
+	}
+
+	switch subscribeMethod {
+	case "droppedTransactions":
+		return d.filterManager.NewDroppedTxFilter(conn), nil
+	case "reorg":
+		return d.filterManager.NewReorgFilter(conn), nil
+	default:
+		return "", NewSubscriptionNotFoundError(subscribeMethod)
+	}
+}
+
 func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	var params []interface{}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -190,10 +231,20 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 		return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 	}
 
-	// if the request method is eth_subscribe we need to create a
+	// if the request method is eth_subscribe or edge_subscribe we need to create a
 	// new filter with ws connection
-	if req.Method == "eth_subscribe" {
-		filterID, err := d.handleSubscribe(req, conn)
+	if req.Method == "eth_subscribe" || req.Method == "edge_subscribe" {
+		var (
+			filterID string
+			err      Error
+		)
+
+		if req.Method == "eth_subscribe" {
+			filterID, err = d.handleSubscribe(req, conn)
+		} else {
+			filterID, err = d.handleEdgeSubscribe(req, conn)
+		}
+
 		if err != nil {
 			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
 		}
@@ -207,7 +258,7 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 		return []byte(resp), nil
 	}
 
-	if req.Method == "eth_unsubscribe" {
+	if req.Method == "eth_unsubscribe" || req.Method == "edge_unsubscribe" {
 		ok, err := d.handleUnsubscribe(req)
 		if err != nil {
 			return nil, err
@@ -263,7 +314,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	}
 
 	// avoid handling long batch requests
-	if len(requests) > int(d.jsonRPCBatchLengthLimit) {
+	if len(requests) > int(atomic.LoadUint64(&d.jsonRPCBatchLengthLimit)) {
 		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Batch request length too long")).Bytes()
 	}
 
@@ -309,10 +360,8 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 		inArgs[i+1] = val.Elem()
 	}
 
-	if fd.numParams() > 0 {
-		if err := json.Unmarshal(req.Params, &inputs); err != nil {
-			return nil, NewInvalidParamsError("Invalid Params")
-		}
+	if err := decodeParams(req.Params, inputs); err != nil {
+		return nil, err
 	}
 
 	output := fd.fv.Call(inArgs)
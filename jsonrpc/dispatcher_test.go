@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -94,6 +95,55 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 	})
 }
 
+func TestDispatcher_HandleWebsocketConnection_EdgeSubscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clients should be able to receive \"droppedTransactions\" event thru edge_subscribe", func(t *testing.T) {
+		t.Parallel()
+
+		store := newMockStore()
+		dispatcher := newDispatcher(hclog.NewNullLogger(), store, 0, 0, 20, 1000)
+
+		mockConnection := &mockWsConn{
+			msgCh: make(chan []byte, 1),
+		}
+
+		req := []byte(`{
+		"method": "edge_subscribe",
+		"params": ["droppedTransactions"]
+	}`)
+		if _, err := dispatcher.HandleWs(req, mockConnection); err != nil {
+			t.Fatal(err)
+		}
+
+		store.txEventCh <- &txpoolProto.TxPoolEvent{
+			Type:   txpoolProto.EventType_DROPPED,
+			TxHash: hash1.String(),
+		}
+
+		select {
+		case <-mockConnection.msgCh:
+		case <-time.After(2 * time.Second):
+			t.Fatal("\"droppedTransactions\" event not received in 2 seconds")
+		}
+	})
+
+	t.Run("clients should not be able to subscribe to unknown edge_subscribe events", func(t *testing.T) {
+		t.Parallel()
+
+		dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), 0, 0, 20, 1000)
+
+		resp, err := dispatcher.HandleWs([]byte(`{
+		"method": "edge_subscribe",
+		"params": ["newHeads"]
+	}`), &mockWsConn{})
+		assert.NoError(t, err)
+
+		var result string
+		assert.Error(t, expectJSONResult(resp, &result))
+	})
+}
+
 func TestDispatcher_WebsocketConnection_RequestFormats(t *testing.T) {
 	store := newMockStore()
 	dispatcher := newDispatcher(hclog.NewNullLogger(), store, 0, 0, 20, 1000)
@@ -291,7 +341,7 @@ func TestDispatcherBatchRequest(t *testing.T) {
 				{"id":4,"jsonrpc":"2.0","method": "web3_sha3","params": ["0x68656c6c6f20776f726c64"]}]`)...),
 			nil,
 			[]*SuccessResponse{
-				{Error: &ObjectError{Code: -32602, Message: "Invalid Params"}},
+				{Error: &ObjectError{Code: -32602, Message: "invalid argument 0: hex string of odd length"}},
 				{Error: nil},
 				{Error: nil},
 				{Error: nil}},
@@ -0,0 +1,566 @@
+package jsonrpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+const (
+	// maxProofBatchKeys is the maximum number of accounts and storage slots
+	// that can be proven with a single edge_getProofBatch call
+	maxProofBatchKeys = 1024
+
+	// defaultLogsPageSize is the number of logs returned by edge_getLogsPaged when no limit is given
+	defaultLogsPageSize = 1000
+
+	// maxLogsPageSize is the maximum number of logs returned by a single edge_getLogsPaged call
+	maxLogsPageSize = 10000
+
+	// logCursorLength is the length of an encoded log cursor: the block number and the log position
+	logCursorLength = 16
+
+	// maxCallManyCalls is the maximum number of calls executed by a single edge_callMany call
+	maxCallManyCalls = 256
+)
+
+var (
+	ErrProofBatchEmpty     = errors.New("no accounts requested")
+	ErrProofBatchTooLong   = fmt.Errorf("too many accounts and storage keys requested, max is %d", maxProofBatchKeys)
+	ErrOpcodeStatsNotFound = errors.New("opcode statistics not available for the block")
+	ErrLogsPageSizeZero    = errors.New("logs page size must be positive")
+	ErrLogsPageSizeTooHigh = fmt.Errorf("logs page size too high, max is %d", maxLogsPageSize)
+	ErrMalformedLogCursor  = errors.New("malformed log cursor")
+	ErrKeyPreimageNotFound = errors.New("preimage not recorded for the key")
+	ErrCallManyEmpty       = errors.New("no calls requested")
+	ErrCallManyTooLong     = fmt.Errorf("too many calls requested, max is %d", maxCallManyCalls)
+)
+
+// edgeStore provides access to the methods needed by edge endpoint
+type edgeStore interface {
+	headerGetter
+
+	GetAccount(root types.Hash, addr types.Address) (*state.Account, error)
+	GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error)
+
+	// GetProof returns the trie nodes, indexed by hash, on the paths from the root to the keys.
+	// The keys are hashed before the lookup, the same way they are stored in the trie
+	GetProof(root types.Hash, keys [][]byte) (map[types.Hash][]byte, error)
+
+	// PreviewBlock packs the next block with the transactions in the pool,
+	// returning the block and the receipts of the included transactions.
+	// Neither the pool nor the state are modified
+	PreviewBlock() (*types.Block, []*types.Receipt, error)
+
+	// GetOpcodeStats returns the statistics of the opcodes executed by the block with the given hash
+	GetOpcodeStats(hash types.Hash) (*runtime.OpcodeStats, bool)
+
+	// GetChainStats returns the rolling aggregates of the recent blocks
+	GetChainStats() *blockchain.ChainStats
+
+	// GetKeyPreimage returns the address or the storage slot hashed to the given trie key,
+	// if the node records the preimages
+	GetKeyPreimage(hash types.Hash) ([]byte, bool)
+
+	// GetAvgGasPrice returns the average gas price of the written transactions
+	GetAvgGasPrice() *big.Int
+
+	// GetFees returns the fees paid in the canonical blocks of the given range, inclusive
+	GetFees(from, to uint64) (*types.Fees, error)
+
+	// ApplyCalls applies the calls one after the other on the state of the header, every call seeing
+	// the state changes of the previous ones if chained. It returns the result or the error of each call
+	ApplyCalls(header *types.Header, calls []*types.Transaction, chained bool) ([]*runtime.ExecutionResult, []error, error)
+}
+
+// Edge is the edge jsonrpc endpoint, serving the methods
+// which are specific to this client
+type Edge struct {
+	store         edgeStore
+	filterManager *FilterManager
+	priceLimit    uint64
+}
+
+type logsPage struct {
+	Logs []*Log `json:"logs"`
+	// Cursor is the opaque token to pass to get the next page, nil once the query range is exhausted
+	Cursor *string `json:"cursor"`
+}
+
+type proofRequest struct {
+	Address     types.Address `json:"address"`
+	StorageKeys []types.Hash  `json:"storageKeys"`
+}
+
+type storageProof struct {
+	Key   types.Hash `json:"key"`
+	Value argBytes   `json:"value"`
+}
+
+type accountProof struct {
+	Address     types.Address  `json:"address"`
+	Balance     argBig         `json:"balance"`
+	Nonce       argUint64      `json:"nonce"`
+	CodeHash    types.Hash     `json:"codeHash"`
+	StorageHash types.Hash     `json:"storageHash"`
+	Storage     []storageProof `json:"storage"`
+}
+
+type proofBatch struct {
+	BlockNumber argUint64      `json:"blockNumber"`
+	StateRoot   types.Hash     `json:"stateRoot"`
+	Accounts    []accountProof `json:"accounts"`
+	// Nodes is the set of trie nodes for the state trie and all the
+	// storage tries involved, every node being included only once
+	Nodes []argBytes `json:"nodes"`
+}
+
+type blockPreview struct {
+	Number       argUint64    `json:"number"`
+	GasLimit     argUint64    `json:"gasLimit"`
+	GasUsed      argUint64    `json:"gasUsed"`
+	TotalFees    argBig       `json:"totalFees"`
+	Transactions []types.Hash `json:"transactions"`
+}
+
+type opcodeStat struct {
+	Opcode string    `json:"opcode"`
+	Count  argUint64 `json:"count"`
+	Gas    argUint64 `json:"gas"`
+}
+
+type blockOpcodeStats struct {
+	BlockNumber argUint64    `json:"blockNumber"`
+	BlockHash   types.Hash   `json:"blockHash"`
+	Opcodes     []opcodeStat `json:"opcodes"`
+}
+
+type callResult struct {
+	ReturnData argBytes  `json:"returnData"`
+	GasUsed    argUint64 `json:"gasUsed"`
+	// Error is the reason the call failed or reverted, nil if it succeeded
+	Error *string `json:"error,omitempty"`
+}
+
+type dailyActiveAddresses struct {
+	Day   argUint64 `json:"day"`
+	Count argUint64 `json:"count"`
+}
+
+type chainStats struct {
+	FromBlock        argUint64              `json:"fromBlock"`
+	ToBlock          argUint64              `json:"toBlock"`
+	TPS              float64                `json:"tps"`
+	AvgGasUsed       float64                `json:"avgGasUsed"`
+	AvgBlockFullness float64                `json:"avgBlockFullness"`
+	ActiveAddresses  []dailyActiveAddresses `json:"activeAddresses"`
+}
+
+// GetChainStats returns the rolling aggregates of the recent blocks: the transactions per second,
+// the average gas used and block fullness, and the unique active addresses of each recent day
+func (e *Edge) GetChainStats() (interface{}, error) {
+	stats := e.store.GetChainStats()
+
+	res := &chainStats{
+		FromBlock:        argUint64(stats.FromBlock),
+		ToBlock:          argUint64(stats.ToBlock),
+		TPS:              stats.TPS,
+		AvgGasUsed:       stats.AvgGasUsed,
+		AvgBlockFullness: stats.AvgBlockFullness,
+		ActiveAddresses:  make([]dailyActiveAddresses, len(stats.ActiveAddresses)),
+	}
+
+	for i, day := range stats.ActiveAddresses {
+		res.ActiveAddresses[i] = dailyActiveAddresses{
+			Day:   argUint64(day.Day),
+			Count: argUint64(day.Count),
+		}
+	}
+
+	return res, nil
+}
+
+// GetOpcodeStats returns the number of executions and the gas used of the opcodes
+// executed by the given block, sorted by descending gas
+func (e *Edge) GetOpcodeStats(number BlockNumber) (interface{}, error) {
+	header, err := getBlockHeader(e.store, number)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, ok := e.store.GetOpcodeStats(header.Hash)
+	if !ok {
+		return nil, ErrOpcodeStatsNotFound
+	}
+
+	res := &blockOpcodeStats{
+		BlockNumber: argUint64(header.Number),
+		BlockHash:   header.Hash,
+		Opcodes:     []opcodeStat{},
+	}
+
+	for op, stat := range stats {
+		if stat.Count == 0 {
+			continue
+		}
+
+		res.Opcodes = append(res.Opcodes, opcodeStat{
+			Opcode: evm.OpCode(op).String(),
+			Count:  argUint64(stat.Count),
+			Gas:    argUint64(stat.Gas),
+		})
+	}
+
+	sort.SliceStable(res.Opcodes, func(i, j int) bool {
+		return res.Opcodes[i].Gas > res.Opcodes[j].Gas
+	})
+
+	return res, nil
+}
+
+// GetKeyPreimage returns the address or the storage slot hashed to the given trie key.
+// The preimages are only known for the keys committed while the node records them
+func (e *Edge) GetKeyPreimage(hash types.Hash) (interface{}, error) {
+	preimage, ok := e.store.GetKeyPreimage(hash)
+	if !ok {
+		return nil, ErrKeyPreimageNotFound
+	}
+
+	return argBytes(preimage), nil
+}
+
+// GetLogsPaged returns the logs matching the query one page at a time. The first call omits the cursor,
+// every following one passes the cursor returned by the previous page, until it is null.
+// Ranges ending at "latest" move with the chain head, so indexers should pass explicit block numbers
+func (e *Edge) GetLogsPaged(query *LogQuery, cursor *string, limit *argUint64) (interface{}, error) {
+	pageSize := uint64(defaultLogsPageSize)
+
+	if limit != nil {
+		pageSize = uint64(*limit)
+	}
+
+	if pageSize == 0 {
+		return nil, ErrLogsPageSizeZero
+	}
+
+	if pageSize > maxLogsPageSize {
+		return nil, ErrLogsPageSizeTooHigh
+	}
+
+	var (
+		from *logCursor
+		err  error
+	)
+
+	if cursor != nil {
+		if from, err = decodeLogCursor(*cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	logs, next, err := e.filterManager.GetLogsPaged(query, from, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &logsPage{
+		Logs: logs,
+	}
+
+	if next != nil {
+		encoded := encodeLogCursor(next)
+		res.Cursor = &encoded
+	}
+
+	return res, nil
+}
+
+func encodeLogCursor(cursor *logCursor) string {
+	buf := make([]byte, logCursorLength)
+
+	binary.BigEndian.PutUint64(buf[:8], cursor.BlockNumber)
+	binary.BigEndian.PutUint64(buf[8:], cursor.LogIndex)
+
+	return hex.EncodeToHex(buf)
+}
+
+func decodeLogCursor(raw string) (*logCursor, error) {
+	buf, err := hex.DecodeHex(raw)
+	if err != nil || len(buf) != logCursorLength {
+		return nil, ErrMalformedLogCursor
+	}
+
+	return &logCursor{
+		BlockNumber: binary.BigEndian.Uint64(buf[:8]),
+		LogIndex:    binary.BigEndian.Uint64(buf[8:]),
+	}, nil
+}
+
+// PreviewBlock packs a hypothetical next block from the transactions in the pool,
+// respecting the block gas limit and the pool ordering. It returns the hashes
+// of the included transactions, along with the gas used and the fees paid
+func (e *Edge) PreviewBlock() (interface{}, error) {
+	block, receipts, err := e.store.PreviewBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &blockPreview{
+		Number:       argUint64(block.Number()),
+		GasLimit:     argUint64(block.Header.GasLimit),
+		GasUsed:      argUint64(block.Header.GasUsed),
+		Transactions: make([]types.Hash, len(block.Transactions)),
+	}
+
+	totalFees := big.NewInt(0)
+
+	for i, tx := range block.Transactions {
+		res.Transactions[i] = tx.Hash
+
+		if i < len(receipts) {
+			fee := new(big.Int).SetUint64(receipts[i].GasUsed)
+			totalFees.Add(totalFees, fee.Mul(fee, tx.EffectiveGasPrice(block.Header.BaseFee)))
+		}
+	}
+
+	res.TotalFees = argBig(*totalFees)
+
+	return res, nil
+}
+
+// CallMany executes the calls one after the other against the state of the given block, which is far cheaper
+// than as many eth_call for rendering the pages of a collection. Every call sees the state changes of the
+// previous ones if chained, and the state of the block only otherwise. A failed call doesn't stop the next ones,
+// its error is returned in its result. The nonce of a call is always the one of its sender in the state it sees
+func (e *Edge) CallMany(args []*txnArgs, filter BlockNumberOrHash, chained *bool) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, ErrCallManyEmpty
+	}
+
+	if len(args) > maxCallManyCalls {
+		return nil, ErrCallManyTooLong
+	}
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := getHeaderFromBlockNumberOrHash(e.store, &filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	calls := make([]*types.Transaction, len(args))
+
+	for i, arg := range args {
+		if arg.From == nil {
+			arg.From = &types.ZeroAddress
+		}
+
+		// the nonce is set from the state the call is applied on
+		arg.Nonce = argUintPtr(0)
+
+		if calls[i], err = decodeTxnArgs(arg); err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+
+		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+		if calls[i].Gas == 0 {
+			calls[i].Gas = header.GasLimit
+		}
+	}
+
+	results, errs, err := e.store.ApplyCalls(header, calls, chained != nil && *chained)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*callResult, len(calls))
+
+	for i, result := range results {
+		res[i] = &callResult{
+			ReturnData: []byte{},
+		}
+
+		if errs[i] != nil {
+			msg := errs[i].Error()
+			res[i].Error = &msg
+
+			continue
+		}
+
+		res[i].ReturnData = result.ReturnValue
+		res[i].GasUsed = argUint64(result.GasUsed)
+
+		if result.Reverted() {
+			msg := constructErrorFromRevert(result).Error()
+			res[i].Error = &msg
+		} else if result.Failed() {
+			msg := fmt.Sprintf("unable to execute call: %v", result.Err)
+			res[i].Error = &msg
+		}
+	}
+
+	return res, nil
+}
+
+// GetProofBatch returns the accounts and storage slots requested, along with a single
+// multiproof of all of them against the state root of the given block
+func (e *Edge) GetProofBatch(requests []proofRequest, filter BlockNumberOrHash) (interface{}, error) {
+	if len(requests) == 0 {
+		return nil, ErrProofBatchEmpty
+	}
+
+	numKeys := len(requests)
+	for _, req := range requests {
+		numKeys += len(req.StorageKeys)
+	}
+
+	if numKeys > maxProofBatchKeys {
+		return nil, ErrProofBatchTooLong
+	}
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := getHeaderFromBlockNumberOrHash(e.store, &filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	nodes := map[types.Hash][]byte{}
+	addNodes := func(root types.Hash, keys [][]byte) error {
+		proof, err := e.store.GetProof(root, keys)
+		if err != nil {
+			return err
+		}
+
+		for hash, node := range proof {
+			nodes[hash] = node
+		}
+
+		return nil
+	}
+
+	// prove all the accounts in a single pass over the state trie
+	addrKeys := make([][]byte, len(requests))
+	for i, req := range requests {
+		addrKeys[i] = req.Address.Bytes()
+	}
+
+	if err := addNodes(header.StateRoot, addrKeys); err != nil {
+		return nil, err
+	}
+
+	res := &proofBatch{
+		BlockNumber: argUint64(header.Number),
+		StateRoot:   header.StateRoot,
+		Accounts:    make([]accountProof, len(requests)),
+		Nodes:       []argBytes{},
+	}
+
+	for i, req := range requests {
+		acc, err := e.getAccountProof(header.StateRoot, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(req.StorageKeys) > 0 && acc.StorageHash != types.EmptyRootHash {
+			slotKeys := make([][]byte, len(req.StorageKeys))
+			for j, key := range req.StorageKeys {
+				slotKeys[j] = key.Bytes()
+			}
+
+			if err := addNodes(acc.StorageHash, slotKeys); err != nil {
+				return nil, err
+			}
+		}
+
+		res.Accounts[i] = *acc
+	}
+
+	// sort the nodes so the response is deterministic
+	hashes := make([]types.Hash, 0, len(nodes))
+	for hash := range nodes {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].String() < hashes[j].String()
+	})
+
+	for _, hash := range hashes {
+		res.Nodes = append(res.Nodes, nodes[hash])
+	}
+
+	return res, nil
+}
+
+func (e *Edge) getAccountProof(root types.Hash, req proofRequest) (*accountProof, error) {
+	res := &accountProof{
+		Address:     req.Address,
+		Balance:     argBig(*big.NewInt(0)),
+		CodeHash:    types.BytesToHash(crypto.Keccak256(nil)),
+		StorageHash: types.EmptyRootHash,
+		Storage:     make([]storageProof, len(req.StorageKeys)),
+	}
+
+	for i, key := range req.StorageKeys {
+		res.Storage[i] = storageProof{Key: key, Value: types.ZeroHash[:]}
+	}
+
+	acc, err := e.store.GetAccount(root, req.Address)
+
+	if errors.Is(err, ErrStateNotFound) {
+		// The account doesn't exist, the proof shows its absence
+		return res, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res.Balance = argBig(*acc.Balance)
+	res.Nonce = argUint64(acc.Nonce)
+	res.CodeHash = types.BytesToHash(acc.CodeHash)
+	res.StorageHash = acc.Root
+
+	for i, key := range req.StorageKeys {
+		result, err := e.store.GetStorage(root, req.Address, key)
+
+		if errors.Is(err, ErrStateNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Parse the RLP value
+		p := &fastrlp.Parser{}
+
+		v, err := p.Parse(result)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := v.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		res.Storage[i].Value = data
+	}
+
+	return res, nil
+}
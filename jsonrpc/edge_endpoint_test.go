@@ -0,0 +1,604 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockProofStore is backed by a real trie, so the proofs can be verified
+type mockProofStore struct {
+	edgeStore
+
+	state  *itrie.State
+	header *types.Header
+}
+
+func newMockProofStore(t *testing.T, objs []*state.Object) *mockProofStore {
+	t.Helper()
+
+	st := itrie.NewState(itrie.NewMemoryStorage())
+	_, root := st.NewSnapshot().Commit(objs)
+
+	return &mockProofStore{
+		state:  st,
+		header: &types.Header{Number: 10, StateRoot: types.BytesToHash(root)},
+	}
+}
+
+func (m *mockProofStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockProofStore) get(root types.Hash, key []byte) ([]byte, error) {
+	snap, err := m.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := snap.Get(keccak.Keccak256(nil, key))
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	return res, nil
+}
+
+func (m *mockProofStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	obj, err := m.get(root, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var account state.Account
+	if err := account.UnmarshalRlp(obj); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (m *mockProofStore) GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error) {
+	account, err := m.GetAccount(root, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.get(account.Root, slot.Bytes())
+}
+
+func (m *mockProofStore) GetProof(root types.Hash, keys [][]byte) (map[types.Hash][]byte, error) {
+	hashedKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		hashedKeys[i] = keccak.Keccak256(nil, key)
+	}
+
+	proofDB := map[types.Hash][]byte{}
+	if err := m.state.Prove(root, hashedKeys, proofDB); err != nil {
+		return nil, err
+	}
+
+	return proofDB, nil
+}
+
+func TestEdge_GetProofBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		slot1 = types.StringToHash("0x1")
+		slot2 = types.StringToHash("0x2")
+		empty = types.StringToHash("0x3")
+	)
+
+	objs := []*state.Object{
+		{
+			Address:  addr0,
+			Balance:  big.NewInt(100),
+			Nonce:    2,
+			CodeHash: types.StringToHash("0xabcd"),
+			Root:     types.EmptyRootHash,
+			Storage: []*state.StorageObject{
+				{Key: slot1.Bytes(), Val: types.StringToHash("0x10").Bytes()},
+				{Key: slot2.Bytes(), Val: types.StringToHash("0x20").Bytes()},
+			},
+		},
+		{
+			Address: addr1,
+			Balance: big.NewInt(5),
+			Root:    types.EmptyRootHash,
+		},
+	}
+
+	store := newMockProofStore(t, objs)
+	edge := &Edge{store: store}
+
+	res, err := edge.GetProofBatch([]proofRequest{
+		{Address: addr0, StorageKeys: []types.Hash{slot1, slot2, empty}},
+		{Address: addr1},
+		{Address: addr2},
+	}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+
+	batch, ok := res.(*proofBatch)
+	assert.True(t, ok)
+
+	assert.Equal(t, argUint64(10), batch.BlockNumber)
+	assert.Equal(t, store.header.StateRoot, batch.StateRoot)
+	assert.Len(t, batch.Accounts, 3)
+
+	acc := batch.Accounts[0]
+	assert.Equal(t, uint64(2), uint64(acc.Nonce))
+	assert.Equal(t, int64(100), (*big.Int)(&acc.Balance).Int64())
+	assert.Equal(t, []byte{0x10}, []byte(acc.Storage[0].Value))
+	assert.Equal(t, []byte{0x20}, []byte(acc.Storage[1].Value))
+	assert.Equal(t, types.ZeroHash[:], []byte(acc.Storage[2].Value))
+
+	// the non existing account is returned empty
+	assert.Equal(t, types.EmptyRootHash, batch.Accounts[2].StorageHash)
+	assert.Equal(t, int64(0), (*big.Int)(&batch.Accounts[2].Balance).Int64())
+
+	// every value can be verified from the nodes in the batch alone
+	proofDB := map[types.Hash][]byte{}
+	for _, node := range batch.Nodes {
+		proofDB[types.BytesToHash(keccak.Keccak256(nil, node))] = node
+	}
+
+	assert.Len(t, proofDB, len(batch.Nodes))
+
+	for i, addr := range []types.Address{addr0, addr1} {
+		value, err := itrie.VerifyProof(batch.StateRoot, keccak.Keccak256(nil, addr.Bytes()), proofDB)
+		assert.NoError(t, err)
+
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(value))
+		assert.Equal(t, batch.Accounts[i].StorageHash, account.Root)
+	}
+
+	value, err := itrie.VerifyProof(batch.StateRoot, keccak.Keccak256(nil, addr2.Bytes()), proofDB)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	for _, slot := range []types.Hash{slot1, slot2} {
+		value, err := itrie.VerifyProof(acc.StorageHash, keccak.Keccak256(nil, slot.Bytes()), proofDB)
+		assert.NoError(t, err)
+		assert.NotNil(t, value)
+	}
+
+	value, err = itrie.VerifyProof(acc.StorageHash, keccak.Keccak256(nil, empty.Bytes()), proofDB)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestEdge_GetProofBatch_Limits(t *testing.T) {
+	t.Parallel()
+
+	edge := &Edge{store: newMockProofStore(t, nil)}
+
+	_, err := edge.GetProofBatch([]proofRequest{}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, ErrProofBatchEmpty)
+
+	_, err = edge.GetProofBatch([]proofRequest{
+		{Address: addr0, StorageKeys: make([]types.Hash, maxProofBatchKeys)},
+	}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, ErrProofBatchTooLong)
+}
+
+type mockPreviewStore struct {
+	edgeStore
+
+	block    *types.Block
+	receipts []*types.Receipt
+}
+
+func (m *mockPreviewStore) PreviewBlock() (*types.Block, []*types.Receipt, error) {
+	return m.block, m.receipts, nil
+}
+
+func TestEdge_PreviewBlock(t *testing.T) {
+	t.Parallel()
+
+	txs := []*types.Transaction{
+		{Nonce: 0, GasPrice: big.NewInt(10), Gas: 50000},
+		{Nonce: 1, GasPrice: big.NewInt(2), Gas: 50000},
+	}
+
+	for _, tx := range txs {
+		tx.ComputeHash()
+	}
+
+	edge := &Edge{store: &mockPreviewStore{
+		block: &types.Block{
+			Header: &types.Header{
+				Number:   11,
+				GasLimit: 100000,
+				GasUsed:  63000,
+			},
+			Transactions: txs,
+		},
+		receipts: []*types.Receipt{
+			{GasUsed: 21000, CumulativeGasUsed: 21000},
+			{GasUsed: 42000, CumulativeGasUsed: 63000},
+		},
+	}}
+
+	res, err := edge.PreviewBlock()
+	assert.NoError(t, err)
+
+	preview, ok := res.(*blockPreview)
+	assert.True(t, ok)
+
+	assert.Equal(t, argUint64(11), preview.Number)
+	assert.Equal(t, argUint64(100000), preview.GasLimit)
+	assert.Equal(t, argUint64(63000), preview.GasUsed)
+	assert.Equal(t, []types.Hash{txs[0].Hash, txs[1].Hash}, preview.Transactions)
+
+	// 21000 * 10 + 42000 * 2
+	expectedFees := big.NewInt(294000)
+	assert.Equal(t, argBig(*expectedFees), preview.TotalFees)
+}
+
+type mockOpcodeStatsStore struct {
+	edgeStore
+
+	header *types.Header
+	stats  map[types.Hash]*runtime.OpcodeStats
+}
+
+func (m *mockOpcodeStatsStore) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number != m.header.Number {
+		return nil, false
+	}
+
+	return m.header, true
+}
+
+func (m *mockOpcodeStatsStore) GetOpcodeStats(hash types.Hash) (*runtime.OpcodeStats, bool) {
+	stats, ok := m.stats[hash]
+
+	return stats, ok
+}
+
+func TestEdge_GetOpcodeStats(t *testing.T) {
+	t.Parallel()
+
+	header := &types.Header{Number: 5, Hash: types.StringToHash("5")}
+
+	stats := &runtime.OpcodeStats{}
+	stats.Record(byte(evm.PUSH1), 3)
+	stats.Record(byte(evm.PUSH1), 3)
+	stats.Record(byte(evm.SSTORE), 20000)
+
+	store := &mockOpcodeStatsStore{
+		header: header,
+		stats:  map[types.Hash]*runtime.OpcodeStats{header.Hash: stats},
+	}
+	edge := &Edge{store: store}
+
+	res, err := edge.GetOpcodeStats(BlockNumber(5))
+	assert.NoError(t, err)
+
+	assert.Equal(t, &blockOpcodeStats{
+		BlockNumber: 5,
+		BlockHash:   header.Hash,
+		Opcodes: []opcodeStat{
+			{Opcode: "SSTORE", Count: 1, Gas: 20000},
+			{Opcode: "PUSH1", Count: 2, Gas: 6},
+		},
+	}, res)
+
+	// the stats of the block aren't collected
+	delete(store.stats, header.Hash)
+
+	_, err = edge.GetOpcodeStats(BlockNumber(5))
+	assert.ErrorIs(t, err, ErrOpcodeStatsNotFound)
+
+	_, err = edge.GetOpcodeStats(BlockNumber(6))
+	assert.Error(t, err)
+}
+
+type mockPreimageStore struct {
+	edgeStore
+
+	state *itrie.State
+}
+
+func (m *mockPreimageStore) GetKeyPreimage(hash types.Hash) ([]byte, bool) {
+	return m.state.GetPreimage(hash)
+}
+
+func TestEdge_GetKeyPreimage(t *testing.T) {
+	t.Parallel()
+
+	addr := types.StringToAddress("1")
+	slot := types.StringToHash("2")
+
+	st := itrie.NewState(itrie.NewMemoryStorage())
+	st.RecordPreimages = true
+
+	st.NewSnapshot().Commit([]*state.Object{
+		{
+			Address: addr,
+			Balance: big.NewInt(1),
+			Root:    types.EmptyRootHash,
+			Storage: []*state.StorageObject{
+				{Key: slot.Bytes(), Val: []byte{0x1}},
+			},
+		},
+	})
+
+	edge := &Edge{store: &mockPreimageStore{state: st}}
+
+	res, err := edge.GetKeyPreimage(types.BytesToHash(keccak.Keccak256(nil, addr.Bytes())))
+	assert.NoError(t, err)
+	assert.Equal(t, argBytes(addr.Bytes()), res)
+
+	res, err = edge.GetKeyPreimage(types.BytesToHash(keccak.Keccak256(nil, slot.Bytes())))
+	assert.NoError(t, err)
+	assert.Equal(t, argBytes(slot.Bytes()), res)
+
+	_, err = edge.GetKeyPreimage(types.StringToHash("3"))
+	assert.ErrorIs(t, err, ErrKeyPreimageNotFound)
+}
+
+type mockChainStatsStore struct {
+	edgeStore
+
+	stats *blockchain.ChainStats
+}
+
+func (m *mockChainStatsStore) GetChainStats() *blockchain.ChainStats {
+	return m.stats
+}
+
+func TestEdge_GetChainStats(t *testing.T) {
+	t.Parallel()
+
+	edge := &Edge{store: &mockChainStatsStore{
+		stats: &blockchain.ChainStats{
+			FromBlock:        1,
+			ToBlock:          256,
+			TPS:              12.5,
+			AvgGasUsed:       21000,
+			AvgBlockFullness: 0.25,
+			ActiveAddresses: []blockchain.DailyActiveAddresses{
+				{Day: 86400, Count: 3},
+			},
+		},
+	}}
+
+	res, err := edge.GetChainStats()
+	assert.NoError(t, err)
+
+	assert.Equal(t, &chainStats{
+		FromBlock:        1,
+		ToBlock:          256,
+		TPS:              12.5,
+		AvgGasUsed:       21000,
+		AvgBlockFullness: 0.25,
+		ActiveAddresses: []dailyActiveAddresses{
+			{Day: 86400, Count: 3},
+		},
+	}, res)
+}
+
+// newLogsPagedEdge returns an edge endpoint over 5 blocks holding
+// 2, 2 and 3 logs in blocks 1 to 3
+func newLogsPagedEdge(t *testing.T, blockRangeLimit uint64) *Edge {
+	t.Helper()
+
+	store := &mockBlockStore{}
+	store.setupLogs()
+
+	for i := 0; i < 5; i++ {
+		store.add(&types.Block{
+			Header: &types.Header{
+				Number: uint64(i),
+				Hash:   types.StringToHash(strconv.Itoa(i)),
+			},
+			Transactions: []*types.Transaction{
+				{Value: big.NewInt(10)},
+				{Value: big.NewInt(11)},
+				{Value: big.NewInt(12)},
+			},
+		})
+	}
+
+	f := NewFilterManager(hclog.NewNullLogger(), store, blockRangeLimit)
+	t.Cleanup(f.Close)
+
+	return &Edge{filterManager: f}
+}
+
+// collectLogsPaged iterates the pages of the query, returning all the logs and the number of pages
+func collectLogsPaged(t *testing.T, edge *Edge, query *LogQuery, limit uint64) ([]*Log, int) {
+	t.Helper()
+
+	var (
+		logs   []*Log
+		cursor *string
+		pages  int
+	)
+
+	pageSize := argUint64(limit)
+
+	for {
+		res, err := edge.GetLogsPaged(query, cursor, &pageSize)
+		assert.NoError(t, err)
+
+		page, _ := res.(*logsPage)
+		assert.LessOrEqual(t, uint64(len(page.Logs)), limit)
+
+		logs = append(logs, page.Logs...)
+		pages++
+
+		if page.Cursor == nil {
+			return logs, pages
+		}
+
+		cursor = page.Cursor
+	}
+}
+
+func TestEdge_GetLogsPaged(t *testing.T) {
+	t.Parallel()
+
+	edge := newLogsPagedEdge(t, 1000)
+	query := &LogQuery{fromBlock: 0, toBlock: 4}
+
+	expected, err := edge.filterManager.GetLogsForQuery(query)
+	assert.NoError(t, err)
+	assert.Len(t, expected, 7)
+
+	for _, limit := range []uint64{1, 2, 3, 7, 100} {
+		logs, pages := collectLogsPaged(t, edge, query, limit)
+
+		assert.Equal(t, expected, logs, "limit %d", limit)
+		assert.Equal(t, (len(expected)+int(limit)-1)/int(limit), pages, "limit %d", limit)
+	}
+
+	// a single block
+	blockHash := types.StringToHash("3")
+
+	logs, pages := collectLogsPaged(t, edge, &LogQuery{BlockHash: &blockHash}, 1)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, 3, pages)
+
+	for _, log := range logs {
+		assert.Equal(t, blockHash, log.BlockHash)
+	}
+}
+
+func TestEdge_GetLogsPaged_BlockRangeLimit(t *testing.T) {
+	t.Parallel()
+
+	// every page scans at most 2 blocks
+	edge := newLogsPagedEdge(t, 1)
+	query := &LogQuery{fromBlock: 1, toBlock: 4}
+
+	res, err := edge.GetLogsPaged(query, nil, nil)
+	assert.NoError(t, err)
+
+	page, _ := res.(*logsPage)
+	assert.Len(t, page.Logs, 4)
+	assert.NotNil(t, page.Cursor)
+
+	res, err = edge.GetLogsPaged(query, page.Cursor, nil)
+	assert.NoError(t, err)
+
+	page, _ = res.(*logsPage)
+	assert.Len(t, page.Logs, 3)
+	assert.Nil(t, page.Cursor)
+}
+
+func TestEdge_GetLogsPaged_Errors(t *testing.T) {
+	t.Parallel()
+
+	edge := newLogsPagedEdge(t, 1000)
+	query := &LogQuery{fromBlock: 1, toBlock: 2}
+
+	zero, tooHigh := argUint64(0), argUint64(maxLogsPageSize+1)
+
+	_, err := edge.GetLogsPaged(query, nil, &zero)
+	assert.ErrorIs(t, err, ErrLogsPageSizeZero)
+
+	_, err = edge.GetLogsPaged(query, nil, &tooHigh)
+	assert.ErrorIs(t, err, ErrLogsPageSizeTooHigh)
+
+	malformed := "0x0102"
+	_, err = edge.GetLogsPaged(query, &malformed, nil)
+	assert.ErrorIs(t, err, ErrMalformedLogCursor)
+
+	outOfRange := encodeLogCursor(&logCursor{BlockNumber: 3})
+	_, err = edge.GetLogsPaged(query, &outOfRange, nil)
+	assert.ErrorIs(t, err, ErrInvalidLogCursor)
+}
+
+type mockCallsStore struct {
+	edgeStore
+
+	header  *types.Header
+	chained bool
+	calls   []*types.Transaction
+}
+
+func (m *mockCallsStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockCallsStore) ApplyCalls(
+	header *types.Header,
+	calls []*types.Transaction,
+	chained bool,
+) ([]*runtime.ExecutionResult, []error, error) {
+	m.chained, m.calls = chained, calls
+
+	results := []*runtime.ExecutionResult{
+		{ReturnValue: []byte{0x1}, GasUsed: 21000},
+		{Err: runtime.ErrExecutionReverted, GasUsed: 30000},
+		nil,
+	}
+
+	return results, []error{nil, nil, state.ErrNotEnoughFunds}, nil
+}
+
+func TestEdge_CallMany(t *testing.T) {
+	t.Parallel()
+
+	store := &mockCallsStore{header: &types.Header{Number: 3, GasLimit: 100000}}
+	edge := &Edge{store: store}
+
+	to := types.StringToAddress("1")
+	from := types.StringToAddress("2")
+	chained := true
+
+	res, err := edge.CallMany([]*txnArgs{
+		{To: &to},
+		{From: &from, To: &to, Gas: argUintPtr(50000)},
+		{From: &from, To: &to, Nonce: argUintPtr(7)},
+	}, BlockNumberOrHash{}, &chained)
+	assert.NoError(t, err)
+
+	assert.True(t, store.chained)
+	assert.Len(t, store.calls, 3)
+	assert.Equal(t, types.ZeroAddress, store.calls[0].From)
+	assert.Equal(t, uint64(100000), store.calls[0].Gas)
+	assert.Equal(t, uint64(50000), store.calls[1].Gas)
+	// the nonce is set from the state
+	assert.Equal(t, uint64(0), store.calls[2].Nonce)
+
+	results, ok := res.([]*callResult)
+	assert.True(t, ok)
+	assert.Len(t, results, 3)
+
+	assert.Equal(t, argBytes{0x1}, results[0].ReturnData)
+	assert.Equal(t, argUint64(21000), results[0].GasUsed)
+	assert.Nil(t, results[0].Error)
+
+	assert.Equal(t, argUint64(30000), results[1].GasUsed)
+	assert.Equal(t, runtime.ErrExecutionReverted.Error(), *results[1].Error)
+
+	assert.Equal(t, argBytes{}, results[2].ReturnData)
+	assert.Equal(t, state.ErrNotEnoughFunds.Error(), *results[2].Error)
+}
+
+func TestEdge_CallMany_Limits(t *testing.T) {
+	t.Parallel()
+
+	edge := &Edge{store: &mockCallsStore{header: &types.Header{}}}
+
+	_, err := edge.CallMany([]*txnArgs{}, BlockNumberOrHash{}, nil)
+	assert.ErrorIs(t, err, ErrCallManyEmpty)
+
+	_, err = edge.CallMany(make([]*txnArgs, maxCallManyCalls+1), BlockNumberOrHash{}, nil)
+	assert.ErrorIs(t, err, ErrCallManyTooLong)
+}
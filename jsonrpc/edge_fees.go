@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"sort"
+)
+
+const (
+	// feeSampleBlocks is the number of recent blocks the gas prices are sampled from
+	feeSampleBlocks = 20
+
+	// the percentiles of the sampled gas prices suggested for each speed
+	slowFeePercentile   = 25
+	normalFeePercentile = 50
+	fastFeePercentile   = 90
+)
+
+// feeTypeLegacy is the type of the suggested fees for the chains without dynamic fee transactions
+const feeTypeLegacy = "legacy"
+
+type feeTier struct {
+	GasPrice argBig `json:"gasPrice"`
+}
+
+type feeSuggestions struct {
+	// BlockNumber is the latest block the gas prices are sampled from
+	BlockNumber argUint64 `json:"blockNumber"`
+	// Type is the kind of the suggested fees. The tiers only carry a legacy gas price,
+	// the dynamic fee transactions being priced through eth_feeHistory and eth_maxPriorityFeePerGas
+	Type   string  `json:"type"`
+	Slow   feeTier `json:"slow"`
+	Normal feeTier `json:"normal"`
+	Fast   feeTier `json:"fast"`
+}
+
+type feeLedger struct {
+	FromBlock argUint64 `json:"fromBlock"`
+	ToBlock   argUint64 `json:"toBlock"`
+	// Collected are the fees paid by the senders
+	Collected argBig `json:"collected"`
+	// Validators are the fees credited to the block creators
+	Validators argBig `json:"validators"`
+	// ContractCreators are the fees credited to the creators of the called contracts
+	ContractCreators argBig `json:"contractCreators"`
+	// Burned are the fees collected but not credited to any account
+	Burned argBig `json:"burned"`
+}
+
+// SuggestFees returns the gas prices suggested for a slow, a normal and a fast inclusion.
+// They are the percentiles of the gas prices paid in the recent blocks, at least the price limit
+// of the node. The average gas price is suggested if the recent blocks have no transaction
+func (e *Edge) SuggestFees() (interface{}, error) {
+	header := e.store.Header()
+	prices := e.sampleGasPrices(header.Number)
+
+	floor := new(big.Int).SetUint64(e.priceLimit)
+
+	suggest := func(percentile int) feeTier {
+		price := e.store.GetAvgGasPrice()
+
+		if len(prices) > 0 {
+			// the nearest rank of the percentile
+			rank := (percentile*len(prices) + 99) / 100
+			if rank < 1 {
+				rank = 1
+			}
+
+			price = prices[rank-1]
+		}
+
+		if price == nil || price.Cmp(floor) < 0 {
+			price = floor
+		}
+
+		return feeTier{GasPrice: argBig(*price)}
+	}
+
+	return &feeSuggestions{
+		BlockNumber: argUint64(header.Number),
+		Type:        feeTypeLegacy,
+		Slow:        suggest(slowFeePercentile),
+		Normal:      suggest(normalFeePercentile),
+		Fast:        suggest(fastFeePercentile),
+	}, nil
+}
+
+// sampleGasPrices returns the sorted gas prices of the transactions of the recent blocks up to the given one
+func (e *Edge) sampleGasPrices(latest uint64) []*big.Int {
+	prices := []*big.Int{}
+
+	for i := uint64(0); i < feeSampleBlocks && i <= latest; i++ {
+		header, ok := e.store.GetHeaderByNumber(latest - i)
+		if !ok {
+			break
+		}
+
+		block, ok := e.store.GetBlockByHash(header.Hash, true)
+		if !ok {
+			break
+		}
+
+		for _, tx := range block.Transactions {
+			if tx.GasPrice != nil {
+				prices = append(prices, tx.EffectiveGasPrice(header.BaseFee))
+			}
+		}
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+
+	return prices
+}
+
+// GetFees returns the fees paid in the blocks of the given range, inclusive, and their distribution.
+// The fees are only tracked for the blocks executed by the node, from the first one following
+// the blocks it didn't execute, e.g. the ones restored from an archive
+func (e *Edge) GetFees(from, to BlockNumber) (interface{}, error) {
+	fromHeader, err := getBlockHeader(e.store, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toHeader, err := getBlockHeader(e.store, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := e.store.GetFees(fromHeader.Number, toHeader.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &feeLedger{
+		FromBlock:        argUint64(fromHeader.Number),
+		ToBlock:          argUint64(toHeader.Number),
+		Collected:        argBig(*fees.Collected),
+		Validators:       argBig(*fees.Validators),
+		ContractCreators: argBig(*fees.ContractCreators),
+		Burned:           argBig(*fees.Burned()),
+	}, nil
+}
@@ -0,0 +1,200 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFeesStore struct {
+	edgeStore
+
+	blocks   []*types.Block
+	avgPrice *big.Int
+	fees     map[[2]uint64]*types.Fees
+}
+
+func (m *mockFeesStore) add(gasPrices ...int64) {
+	number := uint64(len(m.blocks))
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number: number,
+			Hash:   types.StringToHash(strconv.FormatUint(number, 10)),
+		},
+	}
+
+	for _, price := range gasPrices {
+		block.Transactions = append(block.Transactions, &types.Transaction{GasPrice: big.NewInt(price)})
+	}
+
+	m.blocks = append(m.blocks, block)
+}
+
+func (m *mockFeesStore) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockFeesStore) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	if number >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[number].Header, true
+}
+
+func (m *mockFeesStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	for _, block := range m.blocks {
+		if block.Hash() == hash {
+			return block, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockFeesStore) GetAvgGasPrice() *big.Int {
+	return m.avgPrice
+}
+
+func (m *mockFeesStore) GetFees(from, to uint64) (*types.Fees, error) {
+	fees, ok := m.fees[[2]uint64{from, to}]
+	if !ok {
+		return nil, blockchain.ErrFeesNotTracked
+	}
+
+	return fees, nil
+}
+
+func TestEdge_SuggestFees(t *testing.T) {
+	t.Parallel()
+
+	tiers := func(slow, normal, fast int64) []int64 {
+		return []int64{slow, normal, fast}
+	}
+
+	tests := []struct {
+		name       string
+		blocks     [][]int64
+		avgPrice   int64
+		priceLimit uint64
+		expected   []int64
+	}{
+		{
+			name: "percentiles of the recent gas prices",
+			blocks: [][]int64{
+				{},
+				{1, 2, 3, 4, 5},
+				{6, 7, 8, 9, 10},
+			},
+			expected: tiers(3, 5, 9),
+		},
+		{
+			name: "only the recent blocks are sampled",
+			blocks: append(
+				[][]int64{{1000}},
+				func() [][]int64 {
+					blocks := make([][]int64, feeSampleBlocks)
+					for i := range blocks {
+						blocks[i] = []int64{10}
+					}
+
+					return blocks
+				}()...,
+			),
+			expected: tiers(10, 10, 10),
+		},
+		{
+			name:       "gas prices below the price limit",
+			blocks:     [][]int64{{}, {1, 2, 30}},
+			priceLimit: 5,
+			expected:   tiers(5, 5, 30),
+		},
+		{
+			name:       "no transactions in the recent blocks",
+			blocks:     [][]int64{{}, {}},
+			avgPrice:   7,
+			priceLimit: 5,
+			expected:   tiers(7, 7, 7),
+		},
+		{
+			name:       "average gas price below the price limit",
+			blocks:     [][]int64{{}},
+			avgPrice:   1,
+			priceLimit: 5,
+			expected:   tiers(5, 5, 5),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &mockFeesStore{avgPrice: big.NewInt(test.avgPrice)}
+			for _, prices := range test.blocks {
+				store.add(prices...)
+			}
+
+			edge := &Edge{store: store, priceLimit: test.priceLimit}
+
+			res, err := edge.SuggestFees()
+			assert.NoError(t, err)
+
+			fees, ok := res.(*feeSuggestions)
+			if !assert.True(t, ok) {
+				return
+			}
+
+			assert.Equal(t, argUint64(len(test.blocks)-1), fees.BlockNumber)
+			assert.Equal(t, feeTypeLegacy, fees.Type)
+
+			for i, tier := range []feeTier{fees.Slow, fees.Normal, fees.Fast} {
+				price := big.Int(tier.GasPrice)
+				assert.Equal(t, big.NewInt(test.expected[i]).String(), price.String())
+			}
+		})
+	}
+}
+
+func TestEdge_GetFees(t *testing.T) {
+	t.Parallel()
+
+	store := &mockFeesStore{
+		fees: map[[2]uint64]*types.Fees{
+			{1, 3}: {
+				Collected:        big.NewInt(1000),
+				Validators:       big.NewInt(600),
+				ContractCreators: big.NewInt(300),
+			},
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		store.add()
+	}
+
+	edge := &Edge{store: store}
+
+	res, err := edge.GetFees(BlockNumber(1), LatestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, &feeLedger{
+		FromBlock:        1,
+		ToBlock:          3,
+		Collected:        argBig(*big.NewInt(1000)),
+		Validators:       argBig(*big.NewInt(600)),
+		ContractCreators: argBig(*big.NewInt(300)),
+		Burned:           argBig(*big.NewInt(100)),
+	}, res)
+
+	_, err = edge.GetFees(BlockNumber(2), LatestBlockNumber)
+	assert.ErrorIs(t, err, blockchain.ErrFeesNotTracked)
+
+	_, err = edge.GetFees(BlockNumber(1), BlockNumber(5))
+	assert.Error(t, err)
+}
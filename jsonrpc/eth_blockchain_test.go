@@ -7,10 +7,13 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,6 +55,34 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 	}
 }
 
+func TestEth_Block_GetBlockByNumber_Finality(t *testing.T) {
+	store := &mockBlockStore{}
+	for i := 0; i < 10; i++ {
+		store.add(newTestBlock(uint64(i), hash1))
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	// no header is marked by the consensus engine
+	_, err := eth.GetBlockByNumber(FinalizedBlockNumber, false)
+	assert.ErrorIs(t, err, ErrFinalizedBlockNotFound)
+
+	_, err = eth.GetBlockByNumber(SafeBlockNumber, false)
+	assert.ErrorIs(t, err, ErrSafeBlockNotFound)
+
+	store.finalized = store.blocks[5].Header
+	store.safe = store.blocks[7].Header
+
+	for tag, expected := range map[BlockNumber]uint64{FinalizedBlockNumber: 5, SafeBlockNumber: 7} {
+		res, err := eth.GetBlockByNumber(tag, false)
+		assert.NoError(t, err)
+
+		if assert.IsType(t, &block{}, res) {
+			assert.Equal(t, argUint64(expected), res.(*block).Number)
+		}
+	}
+}
+
 func TestEth_Block_GetBlockByHash(t *testing.T) {
 	store := &mockBlockStore{}
 	store.add(newTestBlock(1, hash1))
@@ -82,6 +113,42 @@ func TestEth_Block_BlockNumber(t *testing.T) {
 	assert.Equal(t, argUintPtr(10), num)
 }
 
+func TestEth_Block_Uncles(t *testing.T) {
+	store := &mockBlockStore{}
+	store.add(newTestBlock(1, hash1))
+
+	eth := newTestEthEndpoint(store)
+
+	// the blocks have no uncles
+	count, err := eth.GetUncleCountByBlockNumber(BlockNumber(1))
+	assert.NoError(t, err)
+	assert.Equal(t, argUint64(0), count)
+
+	count, err = eth.GetUncleCountByBlockHash(hash1)
+	assert.NoError(t, err)
+	assert.Equal(t, argUint64(0), count)
+
+	uncle, err := eth.GetUncleByBlockNumberAndIndex(BlockNumber(1), 0)
+	assert.NoError(t, err)
+	assert.Nil(t, uncle)
+
+	uncle, err = eth.GetUncleByBlockHashAndIndex(hash1, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, uncle)
+
+	// the unknown blocks
+	count, err = eth.GetUncleCountByBlockNumber(BlockNumber(5))
+	assert.NoError(t, err)
+	assert.Nil(t, count)
+
+	count, err = eth.GetUncleCountByBlockHash(hash2)
+	assert.NoError(t, err)
+	assert.Nil(t, count)
+
+	_, err = eth.GetUncleByBlockNumberAndIndex(PendingBlockNumber, 0)
+	assert.Error(t, err)
+}
+
 func TestEth_Block_GetBlockTransactionCountByNumber(t *testing.T) {
 	store := &mockBlockStore{}
 	block := newTestBlock(1, hash1)
@@ -235,6 +302,8 @@ func TestEth_Syncing(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("0x%x", 1), response.StartingBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 10), response.CurrentBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 100), response.HighestBlock)
+		assert.Equal(t, string(progress.ProgressionBatch), response.Stage)
+		assert.Equal(t, "A", response.Peer)
 	})
 
 	t.Run("returns \"false\" if sync is not progress", func(t *testing.T) {
@@ -346,6 +415,14 @@ type mockBlockStore struct {
 	isSyncing       bool
 	averageGasPrice int64
 	ethCallError    error
+
+	// the blocks below bloomIndexed are only scanned if they are bloom candidates
+	bloomIndexed    uint64
+	bloomCandidates []uint64
+
+	// the headers marked by the consensus engine, nil if not marked
+	finalized *types.Header
+	safe      *types.Header
 }
 
 func newMockBlockStore() *mockBlockStore {
@@ -485,16 +562,29 @@ func (m *mockBlockStore) Header() *types.Header {
 	return m.blocks[len(m.blocks)-1].Header
 }
 
-func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
+func (m *mockBlockStore) FinalizedHeader() *types.Header {
+	return m.finalized
+}
+
+func (m *mockBlockStore) SafeHeader() *types.Header {
+	return m.safe
+}
+
+func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (*storage.TxLookupEntry, bool) {
 	for _, block := range m.blocks {
-		for _, txn := range block.Transactions {
+		for i, txn := range block.Transactions {
 			if txn.Hash == txnHash {
-				return block.Hash(), true
+				return &storage.TxLookupEntry{
+					BlockHash:   block.Hash(),
+					BlockNumber: block.Number(),
+					Index:       uint64(i),
+					Positioned:  true,
+				}, true
 			}
 		}
 	}
 
-	return types.ZeroHash, false
+	return nil, false
 }
 
 func (m *mockBlockStore) GetPendingTx(txHash types.Hash) (*types.Transaction, bool) {
@@ -514,6 +604,8 @@ func (m *mockBlockStore) GetSyncProgression() *progress.Progression {
 			StartingBlock: 1,
 			CurrentBlock:  10,
 			HighestBlock:  100,
+			Stage:         progress.ProgressionBatch,
+			Peer:          "A",
 		}
 	} else {
 		return nil
@@ -524,12 +616,36 @@ func (m *mockBlockStore) GetAvgGasPrice() *big.Int {
 	return big.NewInt(m.averageGasPrice)
 }
 
+func (m *mockBlockStore) CalculateBaseFee(parent *types.Header) uint64 {
+	return parent.BaseFee
+}
+
 func (m *mockBlockStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
 	return &runtime.ExecutionResult{Err: m.ethCallError}, nil
 }
 
-func (m *mockBlockStore) SubscribeEvents() blockchain.Subscription {
-	return nil
+func (m *mockBlockStore) SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription {
+	return blockchain.NewEventBus(hclog.NewNullLogger()).Subscribe(buffer, topics...)
+}
+
+func (m *mockBlockStore) SubscribeTxEvents(_ []txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return nil, func() {}
+}
+
+func (m *mockBlockStore) BloomIndexed() uint64 {
+	return m.bloomIndexed
+}
+
+func (m *mockBlockStore) BloomCandidates(from, to uint64, groups [][][]byte) []uint64 {
+	candidates := make([]uint64, 0)
+
+	for _, num := range m.bloomCandidates {
+		if num >= from && num <= to {
+			candidates = append(candidates, num)
+		}
+	}
+
+	return candidates
 }
 
 func newTestBlock(number uint64, hash types.Hash) *types.Block {
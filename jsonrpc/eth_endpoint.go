@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
@@ -23,6 +24,9 @@ type ethTxPoolStore interface {
 	// AddTx adds a new transaction to the tx pool
 	AddTx(tx *types.Transaction) error
 
+	// HoldTx holds the raw transaction of a type activating at a future fork until the fork
+	HoldTx(raw []byte) (types.Hash, error)
+
 	// GetPendingTx gets the pending transaction from the transaction pool, if it's present
 	GetPendingTx(txHash types.Hash) (*types.Transaction, bool)
 }
@@ -38,6 +42,12 @@ type ethBlockchainStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the latest finalized header, nil if none is finalized
+	FinalizedHeader() *types.Header
+
+	// SafeHeader returns the latest safe header, nil if none is marked as safe
+	SafeHeader() *types.Header
+
 	// GetHeaderByNumber returns the header by number
 	GetHeaderByNumber(block uint64) (*types.Header, bool)
 
@@ -47,8 +57,8 @@ type ethBlockchainStore interface {
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 
-	// ReadTxLookup returns a block hash in which a given txn was mined
-	ReadTxLookup(txnHash types.Hash) (types.Hash, bool)
+	// ReadTxLookup returns the block in which a given txn was mined, and its position in it
+	ReadTxLookup(txnHash types.Hash) (*storage.TxLookupEntry, bool)
 
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
@@ -56,6 +66,9 @@ type ethBlockchainStore interface {
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
+	// CalculateBaseFee returns the base fee of the block following the given parent, 0 before the London fork
+	CalculateBaseFee(parent *types.Header) uint64
+
 	// ApplyTxn applies a transaction object to the blockchain
 	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
 
@@ -89,29 +102,6 @@ func (e *Eth) ChainId() (interface{}, error) {
 	return argUintPtr(e.chainID), nil
 }
 
-func (e *Eth) getHeaderFromBlockNumberOrHash(bnh *BlockNumberOrHash) (*types.Header, error) {
-	var (
-		header *types.Header
-		err    error
-	)
-
-	if bnh.BlockNumber != nil {
-		header, err = e.getBlockHeader(*bnh.BlockNumber)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get the header of block %d: %w", *bnh.BlockNumber, err)
-		}
-	} else if bnh.BlockHash != nil {
-		block, ok := e.store.GetBlockByHash(*bnh.BlockHash, false)
-		if !ok {
-			return nil, fmt.Errorf("could not find block referenced by the hash %s", bnh.BlockHash.String())
-		}
-
-		header = block.Header
-	}
-
-	return header, nil
-}
-
 func (e *Eth) Syncing() (interface{}, error) {
 	if syncProgression := e.store.GetSyncProgression(); syncProgression != nil {
 		// Node is bulk syncing, return the status
@@ -120,6 +110,8 @@ func (e *Eth) Syncing() (interface{}, error) {
 			StartingBlock: hex.EncodeUint64(syncProgression.StartingBlock),
 			CurrentBlock:  hex.EncodeUint64(syncProgression.CurrentBlock),
 			HighestBlock:  hex.EncodeUint64(syncProgression.HighestBlock),
+			Stage:         string(syncProgression.Stage),
+			Peer:          syncProgression.Peer,
 		}, nil
 	}
 
@@ -138,6 +130,14 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 	case PendingBlockNumber:
 		return 0, fmt.Errorf("fetching the pending header is not supported")
 
+	case FinalizedBlockNumber, SafeBlockNumber:
+		header, err := getFinalityHeader(e.store, number)
+		if err != nil {
+			return 0, err
+		}
+
+		return header.Number, nil
+
 	default:
 		if number < 0 {
 			return 0, fmt.Errorf("invalid argument 0: block number larger than int64")
@@ -188,6 +188,67 @@ func (e *Eth) GetBlockTransactionCountByNumber(number BlockNumber) (interface{},
 	return len(block.Transactions), nil
 }
 
+// GetUncleCountByBlockNumber returns the number of uncles of the block, always zero on IBFT chains
+func (e *Eth) GetUncleCountByBlockNumber(number BlockNumber) (interface{}, error) {
+	num, err := GetNumericBlockNumber(number, e)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := e.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return argUint64(len(block.Uncles)), nil
+}
+
+// GetUncleCountByBlockHash returns the number of uncles of the block, always zero on IBFT chains
+func (e *Eth) GetUncleCountByBlockHash(hash types.Hash) (interface{}, error) {
+	block, ok := e.store.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return argUint64(len(block.Uncles)), nil
+}
+
+// GetUncleByBlockNumberAndIndex returns the uncle of the block at the given index,
+// null on IBFT chains whose blocks have no uncles
+func (e *Eth) GetUncleByBlockNumberAndIndex(number BlockNumber, index argUint64) (interface{}, error) {
+	num, err := GetNumericBlockNumber(number, e)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := e.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return toUncle(block, uint64(index)), nil
+}
+
+// GetUncleByBlockHashAndIndex returns the uncle of the block at the given index,
+// null on IBFT chains whose blocks have no uncles
+func (e *Eth) GetUncleByBlockHashAndIndex(hash types.Hash, index argUint64) (interface{}, error) {
+	block, ok := e.store.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return toUncle(block, uint64(index)), nil
+}
+
+// toUncle returns the uncle of the block at the given index, without transactions, or nil if there is none
+func toUncle(b *types.Block, index uint64) interface{} {
+	if index >= uint64(len(b.Uncles)) {
+		return nil
+	}
+
+	return toBlock(&types.Block{Header: b.Uncles[index]}, false)
+}
+
 // BlockNumber returns current block number
 func (e *Eth) BlockNumber() (interface{}, error) {
 	h := e.store.Header()
@@ -207,7 +268,17 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 
 	tx := &types.Transaction{}
 	if err := tx.UnmarshalRLP(buf); err != nil {
-		return nil, err
+		if !errors.Is(err, types.ErrTxTypeNotSupported) {
+			return nil, err
+		}
+
+		// the transaction of a future type is held until its fork
+		hash, holdErr := e.store.HoldTx(buf)
+		if holdErr != nil {
+			return nil, holdErr
+		}
+
+		return hash.String(), nil
 	}
 
 	tx.ComputeHash()
@@ -233,13 +304,13 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	// for the transaction with the provided hash
 	findSealedTx := func() *transaction {
 		// Check the chain state for the transaction
-		blockHash, ok := e.store.ReadTxLookup(hash)
+		entry, ok := e.store.ReadTxLookup(hash)
 		if !ok {
 			// Block not found in storage
 			return nil
 		}
 
-		block, ok := e.store.GetBlockByHash(blockHash, true)
+		block, ok := e.store.GetBlockByHash(entry.BlockHash, true)
 
 		if !ok {
 			// Block receipts not found in storage
@@ -247,18 +318,17 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 		}
 
 		// Find the transaction within the block
-		for idx, txn := range block.Transactions {
-			if txn.Hash == hash {
-				return toTransaction(
-					txn,
-					argUintPtr(block.Number()),
-					argHashPtr(block.Hash()),
-					&idx,
-				)
-			}
+		idx := findTxIndex(block, hash, entry)
+		if idx == -1 {
+			return nil
 		}
 
-		return nil
+		return toTransaction(
+			block.Transactions[idx],
+			argUintPtr(block.Number()),
+			argHashPtr(block.Hash()),
+			&idx,
+		)
 	}
 
 	// findPendingTx is a helper method for checking the TxPool
@@ -291,14 +361,33 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	return nil, nil
 }
 
+// findTxIndex returns the index of the transaction in the block, or -1 if it isn't included.
+// The position kept by the lookup is used if it has one, the lookups written before are scanned for
+func findTxIndex(block *types.Block, hash types.Hash, entry *storage.TxLookupEntry) int {
+	if entry.Positioned && entry.Index < uint64(len(block.Transactions)) &&
+		block.Transactions[entry.Index].Hash == hash {
+		return int(entry.Index)
+	}
+
+	for i, txn := range block.Transactions {
+		if txn.Hash == hash {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // GetTransactionReceipt returns a transaction receipt by his hash
 func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
-	blockHash, ok := e.store.ReadTxLookup(hash)
+	entry, ok := e.store.ReadTxLookup(hash)
 	if !ok {
 		// txn not found
 		return nil, nil
 	}
 
+	blockHash := entry.BlockHash
+
 	block, ok := e.store.GetBlockByHash(blockHash, true)
 	if !ok {
 		// block not found
@@ -328,16 +417,7 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		return nil, nil
 	}
 	// find the transaction in the body
-	indx := -1
-
-	for i, txn := range block.Transactions {
-		if txn.Hash == hash {
-			indx = i
-
-			break
-		}
-	}
-
+	indx := findTxIndex(block, hash, entry)
 	if indx == -1 {
 		// txn not found
 		return nil, nil
@@ -375,6 +455,8 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		FromAddr:          txn.From,
 		ToAddr:            txn.To,
 		Logs:              logs,
+		EffectiveGasPrice: argBig(*txn.EffectiveGasPrice(block.Header.BaseFee)),
+		Type:              argUint64(txn.Type),
 	}
 
 	return res, nil
@@ -396,7 +478,7 @@ func (e *Eth) GetStorageAt(
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = getHeaderFromBlockNumberOrHash(e.store, &filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
@@ -450,7 +532,7 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = getHeaderFromBlockNumberOrHash(e.store, &filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
@@ -496,7 +578,7 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 	}
 
 	// Fetch the requested header
-	header, err := e.getBlockHeader(number)
+	header, err := getBlockHeader(e.store, number)
 	if err != nil {
 		return nil, err
 	}
@@ -701,7 +783,7 @@ func (e *Eth) GetBalance(address types.Address, filter BlockNumberOrHash) (inter
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = getHeaderFromBlockNumberOrHash(e.store, &filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
@@ -733,7 +815,7 @@ func (e *Eth) GetTransactionCount(address types.Address, filter BlockNumberOrHas
 	}
 
 	if filter.BlockNumber == nil {
-		header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+		header, err = getHeaderFromBlockNumberOrHash(e.store, &filter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get header from block hash or block number")
 		}
@@ -767,7 +849,7 @@ func (e *Eth) GetCode(address types.Address, filter BlockNumberOrHash) (interfac
 		filter.BlockNumber, _ = createBlockNumberPointer("latest")
 	}
 
-	header, err = e.getHeaderFromBlockNumberOrHash(&filter)
+	header, err = getHeaderFromBlockNumberOrHash(e.store, &filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
@@ -818,33 +900,6 @@ func (e *Eth) Unsubscribe(id string) (bool, error) {
 	return e.filterManager.Uninstall(id), nil
 }
 
-func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
-	switch number {
-	case LatestBlockNumber:
-		return e.store.Header(), nil
-
-	case EarliestBlockNumber:
-		header, ok := e.store.GetHeaderByNumber(uint64(0))
-		if !ok {
-			return nil, fmt.Errorf("error fetching genesis block header")
-		}
-
-		return header, nil
-
-	case PendingBlockNumber:
-		return nil, fmt.Errorf("fetching the pending header is not supported")
-
-	default:
-		// Convert the block number from hex to uint64
-		header, ok := e.store.GetHeaderByNumber(uint64(number))
-		if !ok {
-			return nil, fmt.Errorf("error fetching block number %d header", uint64(number))
-		}
-
-		return header, nil
-	}
-}
-
 // getNextNonce returns the next nonce for the account for the specified block
 func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, error) {
 	if number == PendingBlockNumber {
@@ -857,7 +912,7 @@ func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, e
 		return res, nil
 	}
 
-	header, err := e.getBlockHeader(number)
+	header, err := getBlockHeader(e.store, number)
 	if err != nil {
 		return 0, err
 	}
@@ -890,6 +945,11 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 		arg.Nonce = argUintPtr(nonce)
 	}
 
+	return decodeTxnArgs(arg)
+}
+
+// decodeTxnArgs decodes the transaction, the sender and its nonce being set
+func decodeTxnArgs(arg *txnArgs) (*types.Transaction, error) {
 	if arg.Value == nil {
 		arg.Value = argBytesPtr([]byte{})
 	}
@@ -931,6 +991,23 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 		txn.To = arg.To
 	}
 
+	if arg.MaxFeePerGas != nil || arg.MaxPriorityFeePerGas != nil {
+		txn.Type = types.DynamicFeeTx
+		txn.GasTipCap = new(big.Int)
+		txn.GasFeeCap = new(big.Int)
+
+		if arg.MaxPriorityFeePerGas != nil {
+			txn.GasTipCap.SetBytes(*arg.MaxPriorityFeePerGas)
+		}
+
+		if arg.MaxFeePerGas != nil {
+			txn.GasFeeCap.SetBytes(*arg.MaxFeePerGas)
+		}
+
+		// the fee cap stands for the gas price, as for the decoded transactions
+		txn.GasPrice = new(big.Int).Set(txn.GasFeeCap)
+	}
+
 	txn.ComputeHash()
 
 	return txn, nil
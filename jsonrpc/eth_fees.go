@@ -0,0 +1,188 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// maxFeeHistoryBlocks is the maximum number of blocks returned by eth_feeHistory
+	maxFeeHistoryBlocks = 1024
+
+	// priorityFeePercentile is the percentile of the recent tips suggested by eth_maxPriorityFeePerGas
+	priorityFeePercentile = 60
+)
+
+type feeHistory struct {
+	OldestBlock argUint64 `json:"oldestBlock"`
+	// BaseFeePerGas holds one more entry than the blocks, the base fee of the block following the newest one
+	BaseFeePerGas []argUint64 `json:"baseFeePerGas"`
+	GasUsedRatio  []float64   `json:"gasUsedRatio"`
+	// Reward holds the percentiles of the effective tips of each block, weighted by the gas used
+	Reward [][]argBig `json:"reward,omitempty"`
+}
+
+// MaxPriorityFeePerGas returns the tip suggested for the dynamic fee transactions,
+// a percentile of the effective tips paid in the recent blocks.
+// The gas price is returned before the London fork, as the whole price goes to the block creator
+func (e *Eth) MaxPriorityFeePerGas() (interface{}, error) {
+	header := e.store.Header()
+
+	if e.store.CalculateBaseFee(header) == 0 {
+		return e.GasPrice()
+	}
+
+	tips := []*big.Int{}
+
+	for i := uint64(0); i < feeSampleBlocks && i <= header.Number; i++ {
+		block, ok := e.store.GetBlockByNumber(header.Number-i, true)
+		if !ok {
+			break
+		}
+
+		for _, tx := range block.Transactions {
+			if tx.GasPrice != nil {
+				tips = append(tips, tx.EffectiveTip(block.Header.BaseFee))
+			}
+		}
+	}
+
+	if len(tips) == 0 {
+		return hex.EncodeBig(big.NewInt(0)), nil
+	}
+
+	sort.Slice(tips, func(i, j int) bool {
+		return tips[i].Cmp(tips[j]) < 0
+	})
+
+	// the nearest rank of the percentile
+	rank := (priorityFeePercentile*len(tips) + 99) / 100
+
+	return hex.EncodeBig(tips[rank-1]), nil
+}
+
+// FeeHistory returns the base fees, the gas used ratios and the given percentiles of the effective tips
+// of the blockCount blocks up to newestBlock
+func (e *Eth) FeeHistory(blockCount argUint64, newestBlock BlockNumber, rewardPercentiles []float64) (interface{}, error) {
+	if blockCount == 0 {
+		return nil, fmt.Errorf("block count must be positive")
+	}
+
+	if blockCount > maxFeeHistoryBlocks {
+		blockCount = maxFeeHistoryBlocks
+	}
+
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid reward percentile %f", p)
+		}
+
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("reward percentiles must be in ascending order")
+		}
+	}
+
+	newest, err := getBlockHeader(e.store, newestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	count := uint64(blockCount)
+	if count > newest.Number+1 {
+		count = newest.Number + 1
+	}
+
+	oldest := newest.Number + 1 - count
+
+	res := &feeHistory{
+		OldestBlock:   argUint64(oldest),
+		BaseFeePerGas: make([]argUint64, 0, count+1),
+		GasUsedRatio:  make([]float64, 0, count),
+	}
+
+	for num := oldest; num <= newest.Number; num++ {
+		block, ok := e.store.GetBlockByNumber(num, true)
+		if !ok {
+			return nil, fmt.Errorf("error fetching block number %d", num)
+		}
+
+		res.BaseFeePerGas = append(res.BaseFeePerGas, argUint64(block.Header.BaseFee))
+
+		ratio := float64(0)
+		if block.Header.GasLimit != 0 {
+			ratio = float64(block.Header.GasUsed) / float64(block.Header.GasLimit)
+		}
+
+		res.GasUsedRatio = append(res.GasUsedRatio, ratio)
+
+		if len(rewardPercentiles) > 0 {
+			rewards, err := e.blockRewards(block, rewardPercentiles)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Reward = append(res.Reward, rewards)
+		}
+	}
+
+	res.BaseFeePerGas = append(res.BaseFeePerGas, argUint64(e.store.CalculateBaseFee(newest)))
+
+	return res, nil
+}
+
+// blockRewards returns the percentiles of the effective tips of the block transactions, weighted by their gas used
+func (e *Eth) blockRewards(block *types.Block, percentiles []float64) ([]argBig, error) {
+	rewards := make([]argBig, len(percentiles))
+	if len(block.Transactions) == 0 {
+		return rewards, nil
+	}
+
+	receipts, err := e.store.GetReceiptsByHash(block.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("missing receipts of block %d", block.Number())
+	}
+
+	type txTip struct {
+		tip     *big.Int
+		gasUsed uint64
+	}
+
+	tips := make([]txTip, len(block.Transactions))
+	cumulative := uint64(0)
+
+	for i, tx := range block.Transactions {
+		tips[i] = txTip{
+			tip:     tx.EffectiveTip(block.Header.BaseFee),
+			gasUsed: receipts[i].CumulativeGasUsed - cumulative,
+		}
+		cumulative = receipts[i].CumulativeGasUsed
+	}
+
+	sort.Slice(tips, func(i, j int) bool {
+		return tips[i].tip.Cmp(tips[j].tip) < 0
+	})
+
+	// the reward of a percentile is the tip of the transaction reaching that share of the gas used
+	index, sumGasUsed := 0, tips[0].gasUsed
+
+	for i, p := range percentiles {
+		threshold := uint64(float64(block.Header.GasUsed) * p / 100)
+
+		for sumGasUsed < threshold && index < len(tips)-1 {
+			index++
+			sumGasUsed += tips[index].gasUsed
+		}
+
+		rewards[i] = argBig(*tips[index].tip)
+	}
+
+	return rewards, nil
+}
@@ -0,0 +1,97 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestFeesBlockStore returns a store of two London blocks of base fee 10,
+// the second one holding a dynamic fee transaction of tip 1 and a legacy transaction of tip 5
+func newTestFeesBlockStore() *mockBlockStore {
+	store := newMockBlockStore()
+
+	store.add(
+		&types.Block{
+			Header: &types.Header{
+				Number:   0,
+				Hash:     types.StringToHash("0"),
+				BaseFee:  10,
+				GasLimit: 100,
+				GasUsed:  50,
+			},
+		},
+		&types.Block{
+			Header: &types.Header{
+				Number:   1,
+				Hash:     types.StringToHash("1"),
+				BaseFee:  10,
+				GasLimit: 100,
+				GasUsed:  60,
+			},
+			Transactions: []*types.Transaction{
+				{
+					Type:      types.DynamicFeeTx,
+					GasPrice:  big.NewInt(100),
+					GasTipCap: big.NewInt(1),
+					GasFeeCap: big.NewInt(100),
+				},
+				{
+					GasPrice: big.NewInt(15),
+				},
+			},
+		},
+	)
+
+	store.receipts[types.StringToHash("1")] = []*types.Receipt{
+		{CumulativeGasUsed: 20},
+		{CumulativeGasUsed: 60},
+	}
+
+	return store
+}
+
+func TestEth_FeeHistory(t *testing.T) {
+	t.Parallel()
+
+	eth := newTestEthEndpoint(newTestFeesBlockStore())
+
+	res, err := eth.FeeHistory(argUint64(3), LatestBlockNumber, []float64{10, 50})
+	assert.NoError(t, err)
+
+	history, ok := res.(*feeHistory)
+	assert.True(t, ok)
+
+	assert.Equal(t, argUint64(0), history.OldestBlock)
+	assert.Equal(t, []argUint64{10, 10, 10}, history.BaseFeePerGas)
+	assert.Equal(t, []float64{0.5, 0.6}, history.GasUsedRatio)
+	assert.Equal(t, [][]argBig{
+		{argBig(*big.NewInt(0)), argBig(*big.NewInt(0))},
+		{argBig(*big.NewInt(1)), argBig(*big.NewInt(5))},
+	}, history.Reward)
+
+	_, err = eth.FeeHistory(argUint64(0), LatestBlockNumber, nil)
+	assert.Error(t, err)
+
+	_, err = eth.FeeHistory(argUint64(1), LatestBlockNumber, []float64{50, 10})
+	assert.Error(t, err)
+}
+
+func TestEth_MaxPriorityFeePerGas(t *testing.T) {
+	t.Parallel()
+
+	res, err := newTestEthEndpoint(newTestFeesBlockStore()).MaxPriorityFeePerGas()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x5", res)
+
+	// the gas price is suggested before the London fork
+	store := newMockBlockStore()
+	store.averageGasPrice = 9999
+	store.add(&types.Block{Header: &types.Header{}})
+
+	res, err = newTestEthEndpoint(store).MaxPriorityFeePerGas()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x270f", res)
+}
@@ -48,10 +48,54 @@ func TestEth_TxnPool_SendTransaction(t *testing.T) {
 	assert.NotEqual(t, store.txn.Hash, types.ZeroHash)
 }
 
+func TestEth_TxnPool_SendRawTransaction_DynamicFee(t *testing.T) {
+	store := &mockStoreTxn{}
+	eth := newTestEthEndpoint(store)
+
+	txn := &types.Transaction{
+		Type:      types.DynamicFeeTx,
+		ChainID:   big.NewInt(100),
+		To:        argAddrPtr(addr0),
+		Value:     big.NewInt(1),
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(10),
+		V:         big.NewInt(1),
+	}
+	txn.ComputeHash()
+
+	hash, err := eth.SendRawTransaction(hex.EncodeToHex(txn.MarshalRLP()))
+	assert.NoError(t, err)
+	assert.Equal(t, txn.Hash.String(), hash)
+	assert.Nil(t, store.held)
+
+	// the transaction is added to the pool, the fee cap standing for its gas price
+	if assert.NotNil(t, store.txn) {
+		assert.Equal(t, types.DynamicFeeTx, store.txn.Type)
+		assert.Equal(t, txn.Hash, store.txn.Hash)
+		assert.Equal(t, txn.GasTipCap, store.txn.GasTipCap)
+		assert.Equal(t, txn.GasFeeCap, store.txn.GasPrice)
+	}
+}
+
+func TestEth_TxnPool_SendRawTransaction_FutureType(t *testing.T) {
+	store := &mockStoreTxn{}
+	eth := newTestEthEndpoint(store)
+
+	// the blob transactions (EIP-4844) aren't supported
+	raw := []byte{0x03, 0xc0}
+
+	hash, err := eth.SendRawTransaction(hex.EncodeToHex(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, types.StringToHash("1").String(), hash)
+	assert.Equal(t, raw, store.held)
+	assert.Nil(t, store.txn)
+}
+
 type mockStoreTxn struct {
 	ethStore
 	accounts map[types.Address]*mockAccount
 	txn      *types.Transaction
+	held     []byte
 }
 
 func (m *mockStoreTxn) AddTx(tx *types.Transaction) error {
@@ -60,6 +104,12 @@ func (m *mockStoreTxn) AddTx(tx *types.Transaction) error {
 	return nil
 }
 
+func (m *mockStoreTxn) HoldTx(raw []byte) (types.Hash, error) {
+	m.held = raw
+
+	return types.StringToHash("1"), nil
+}
+
 func (m *mockStoreTxn) GetNonce(addr types.Address) uint64 {
 	return 1
 }
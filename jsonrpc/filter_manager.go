@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -24,6 +26,7 @@ var (
 	ErrBlockRangeTooHigh                = errors.New("block range too high")
 	ErrPendingBlockNumber               = errors.New("pending block number is not supported")
 	ErrNoWSConnection                   = errors.New("no websocket connection")
+	ErrInvalidLogCursor                 = errors.New("log cursor out of the query range")
 )
 
 // defaultTimeout is the timeout to remove the filters that don't have a web socket stream
@@ -62,14 +65,18 @@ type filterBase struct {
 
 	// websocket connection
 	ws wsConn
+
+	// method of the notifications written to the websocket connection
+	notifyMethod string
 }
 
 // newFilterBase initializes filterBase with unique ID
 func newFilterBase(ws wsConn) filterBase {
 	return filterBase{
-		id:        uuid.New().String(),
-		ws:        ws,
-		heapIndex: NoIndexInHeap,
+		id:           uuid.New().String(),
+		ws:           ws,
+		heapIndex:    NoIndexInHeap,
+		notifyMethod: ethSubscriptionMethod,
 	}
 }
 
@@ -83,9 +90,17 @@ func (f *filterBase) hasWSConn() bool {
 	return f.ws != nil
 }
 
-const ethSubscriptionTemplate = `{
+const (
+	// ethSubscriptionMethod is the notification method of the eth_subscribe subscriptions
+	ethSubscriptionMethod = "eth_subscription"
+
+	// edgeSubscriptionMethod is the notification method of the edge_subscribe subscriptions
+	edgeSubscriptionMethod = "edge_subscription"
+)
+
+const subscriptionTemplate = `{
 	"jsonrpc": "2.0",
-	"method": "eth_subscription",
+	"method": "%s",
 	"params": {
 		"subscription":"%s",
 		"result": %s
@@ -100,7 +115,7 @@ func (f *filterBase) writeMessageToWs(msg string) error {
 
 	return f.ws.WriteMessage(
 		websocket.TextMessage,
-		[]byte(fmt.Sprintf(ethSubscriptionTemplate, f.id, msg)),
+		[]byte(fmt.Sprintf(subscriptionTemplate, f.notifyMethod, f.id, msg)),
 	)
 }
 
@@ -211,13 +226,148 @@ func (f *logFilter) sendUpdates() error {
 	return nil
 }
 
+// droppedTxFilter is a filter to store the hashes of the transactions dropped from the pool
+type droppedTxFilter struct {
+	filterBase
+	sync.Mutex
+
+	hashes []types.Hash
+}
+
+// appendHash appends the hash of a dropped transaction
+func (f *droppedTxFilter) appendHash(hash types.Hash) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.hashes = append(f.hashes, hash)
+}
+
+// takeHashUpdates returns all saved hashes in filter and set new hash slice
+func (f *droppedTxFilter) takeHashUpdates() []types.Hash {
+	f.Lock()
+	defer f.Unlock()
+
+	hashes := f.hashes
+	f.hashes = []types.Hash{}
+
+	return hashes
+}
+
+// getUpdates returns the stored hashes
+func (f *droppedTxFilter) getUpdates() (interface{}, error) {
+	return f.takeHashUpdates(), nil
+}
+
+// sendUpdates writes the stored hashes to web socket stream
+func (f *droppedTxFilter) sendUpdates() error {
+	for _, hash := range f.takeHashUpdates() {
+		raw, err := json.Marshal(hash)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(raw)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reorgBlock is a block reference in a reorg notification
+type reorgBlock struct {
+	Number argUint64  `json:"number"`
+	Hash   types.Hash `json:"hash"`
+}
+
+// reorgNotification is the payload of a reorg notification.
+// Removed lists the blocks leaving the canonical chain, Added the blocks replacing them
+type reorgNotification struct {
+	Removed []*reorgBlock `json:"removed"`
+	Added   []*reorgBlock `json:"added"`
+}
+
+// newReorgNotification builds the notification of a reorg event
+func newReorgNotification(evnt *blockchain.Event) *reorgNotification {
+	toBlocks := func(headers []*types.Header) []*reorgBlock {
+		blocks := make([]*reorgBlock, len(headers))
+		for i, header := range headers {
+			blocks[i] = &reorgBlock{
+				Number: argUint64(header.Number),
+				Hash:   header.Hash,
+			}
+		}
+
+		return blocks
+	}
+
+	return &reorgNotification{
+		Removed: toBlocks(evnt.OldChain),
+		Added:   toBlocks(evnt.NewChain),
+	}
+}
+
+// reorgFilter is a filter to store the reorgs of the chain
+type reorgFilter struct {
+	filterBase
+	sync.Mutex
+
+	reorgs []*reorgNotification
+}
+
+// appendReorg appends new reorg to reorgs
+func (f *reorgFilter) appendReorg(reorg *reorgNotification) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.reorgs = append(f.reorgs, reorg)
+}
+
+// takeReorgUpdates returns all saved reorgs in filter and set new reorg slice
+func (f *reorgFilter) takeReorgUpdates() []*reorgNotification {
+	f.Lock()
+	defer f.Unlock()
+
+	reorgs := f.reorgs
+	f.reorgs = []*reorgNotification{}
+
+	return reorgs
+}
+
+// getUpdates returns the stored reorgs
+func (f *reorgFilter) getUpdates() (interface{}, error) {
+	return f.takeReorgUpdates(), nil
+}
+
+// sendUpdates writes the stored reorgs to web socket stream
+func (f *reorgFilter) sendUpdates() error {
+	for _, reorg := range f.takeReorgUpdates() {
+		raw, err := json.Marshal(reorg)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(raw)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // filterManagerStore provides methods required by FilterManager
 type filterManagerStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
-	// SubscribeEvents subscribes for chain head events
-	SubscribeEvents() blockchain.Subscription
+	// FinalizedHeader returns the latest finalized header, nil if none is finalized
+	FinalizedHeader() *types.Header
+
+	// SafeHeader returns the latest safe header, nil if none is marked as safe
+	SafeHeader() *types.Header
+
+	// SubscribeBus subscribes for the typed chain events of the given topics
+	SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription
 
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
@@ -227,6 +377,16 @@ type filterManagerStore interface {
 
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// SubscribeTxEvents subscribes for the txpool events of the given types
+	SubscribeTxEvents(eventTypes []txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func())
+
+	// BloomIndexed returns the number of the first block not covered by the bloom bits index
+	BloomIndexed() uint64
+
+	// BloomCandidates returns the numbers of the blocks of the range covered by the bloom bits index
+	// whose logs bloom may match all the groups of addresses and topics
+	BloomCandidates(from, to uint64, groups [][][]byte) []uint64
 }
 
 // FilterManager manages all running filters
@@ -238,9 +398,11 @@ type FilterManager struct {
 	timeout time.Duration
 
 	store           filterManagerStore
-	subscription    blockchain.Subscription
+	subscription    *blockchain.BusSubscription
+	txEventCh       <-chan *txpoolProto.TxPoolEvent
+	cancelTxEvents  func()
 	blockStream     *blockStream
-	blockRangeLimit uint64
+	blockRangeLimit uint64 // accessed atomically
 
 	filters  map[string]filter
 	timeouts timeHeapImpl
@@ -266,8 +428,18 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 	header := store.Header()
 	m.blockStream.push(header)
 
-	// start the head watcher
-	m.subscription = store.SubscribeEvents()
+	// watch for the new heads, the reorgs and the logs of the chain
+	m.subscription = store.SubscribeBus(
+		blockchain.DefaultBusBuffer,
+		blockchain.TopicNewHeads,
+		blockchain.TopicReorgs,
+		blockchain.TopicLogs,
+	)
+
+	// watch for the transactions dropped from the pool
+	m.txEventCh, m.cancelTxEvents = store.SubscribeTxEvents(
+		[]txpoolProto.EventType{txpoolProto.EventType_DROPPED},
+	)
 
 	return m
 }
@@ -275,17 +447,7 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 // Run starts worker process to handle events
 func (f *FilterManager) Run() {
 	// watch for new events in the blockchain
-	watchCh := make(chan *blockchain.Event)
-
-	go func() {
-		for {
-			evnt := f.subscription.GetEvent()
-			if evnt == nil {
-				return
-			}
-			watchCh <- evnt
-		}
-	}()
+	watchCh := f.subscription.EventCh()
 
 	var timeoutCh <-chan time.Time
 
@@ -299,12 +461,32 @@ func (f *FilterManager) Run() {
 		}
 
 		select {
-		case evnt := <-watchCh:
+		case evnt, ok := <-watchCh:
+			if !ok {
+				// the blockchain subscription is closed
+				watchCh = nil
+
+				continue
+			}
+
 			// new blockchain event
 			if err := f.dispatchEvent(evnt); err != nil {
 				f.logger.Error("failed to dispatch event", "err", err)
 			}
 
+		case evnt, ok := <-f.txEventCh:
+			if !ok {
+				// the txpool subscription is closed
+				f.txEventCh = nil
+
+				continue
+			}
+
+			// new txpool event
+			if err := f.dispatchTxEvent(evnt); err != nil {
+				f.logger.Error("failed to dispatch txpool event", "err", err)
+			}
+
 		case <-timeoutCh:
 			// timeout for filter
 			// if filter still exists
@@ -324,9 +506,21 @@ func (f *FilterManager) Run() {
 
 // Close closed closeCh so that terminate worker
 func (f *FilterManager) Close() {
+	f.subscription.Unsubscribe()
+	f.cancelTxEvents()
 	close(f.closeCh)
 }
 
+// SetBlockRangeLimit sets the maximum number of blocks the logs are queried from at once
+func (f *FilterManager) SetBlockRangeLimit(blockRangeLimit uint64) {
+	atomic.StoreUint64(&f.blockRangeLimit, blockRangeLimit)
+}
+
+// getBlockRangeLimit returns the maximum number of blocks the logs are queried from at once
+func (f *FilterManager) getBlockRangeLimit() uint64 {
+	return atomic.LoadUint64(&f.blockRangeLimit)
+}
+
 // NewBlockFilter adds new BlockFilter
 func (f *FilterManager) NewBlockFilter(ws wsConn) string {
 	filter := &blockFilter{
@@ -355,6 +549,30 @@ func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) string {
 	return f.addFilter(filter)
 }
 
+// NewDroppedTxFilter adds new droppedTxFilter, only available over web socket
+func (f *FilterManager) NewDroppedTxFilter(ws wsConn) string {
+	filter := &droppedTxFilter{
+		filterBase: newFilterBase(ws),
+	}
+
+	filter.notifyMethod = edgeSubscriptionMethod
+	ws.SetFilterID(filter.id)
+
+	return f.addFilter(filter)
+}
+
+// NewReorgFilter adds new reorgFilter, only available over web socket
+func (f *FilterManager) NewReorgFilter(ws wsConn) string {
+	filter := &reorgFilter{
+		filterBase: newFilterBase(ws),
+	}
+
+	filter.notifyMethod = edgeSubscriptionMethod
+	ws.SetFilterID(filter.id)
+
+	return f.addFilter(filter)
+}
+
 // Exists checks the filter with given ID exists
 func (f *FilterManager) Exists(id string) bool {
 	f.RLock()
@@ -393,7 +611,8 @@ func (f *FilterManager) getLogsFromBlock(query *LogQuery, block *types.Block) ([
 	return logs, nil
 }
 
-func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
+// resolveLogRange resolves the block range of the query to block numbers, skipping the genesis block
+func (f *FilterManager) resolveLogRange(query *LogQuery) (uint64, uint64, error) {
 	latestBlockNumber := f.store.Header().Number
 
 	resolveNum := func(num BlockNumber) (uint64, error) {
@@ -404,6 +623,13 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 			num = 0
 		case LatestBlockNumber:
 			return latestBlockNumber, nil
+		case FinalizedBlockNumber, SafeBlockNumber:
+			header, err := getFinalityHeader(f.store, num)
+			if err != nil {
+				return 0, err
+			}
+
+			return header.Number, nil
 		}
 
 		return uint64(num), nil
@@ -411,16 +637,16 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 
 	from, err := resolveNum(query.fromBlock)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
 	to, err := resolveNum(query.toBlock)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
 	if to < from {
-		return nil, ErrIncorrectBlockRange
+		return 0, 0, ErrIncorrectBlockRange
 	}
 
 	// If from equals genesis block
@@ -429,13 +655,46 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 		from = 1
 	}
 
+	return from, to, nil
+}
+
+func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
+	from, to, err := f.resolveLogRange(query)
+	if err != nil {
+		return nil, err
+	}
+
 	// avoid handling large block ranges
-	if to-from > f.blockRangeLimit {
+	if to-from > f.getBlockRangeLimit() {
 		return nil, ErrBlockRangeTooHigh
 	}
 
 	logs := make([]*Log, 0)
 
+	// the blocks covered by the bloom bits index are only scanned if their logs bloom may match
+	if indexed := f.store.BloomIndexed(); from < indexed {
+		indexedTo := to
+		if indexedTo >= indexed {
+			indexedTo = indexed - 1
+		}
+
+		for _, num := range f.store.BloomCandidates(from, indexedTo, query.bloomGroups()) {
+			block, ok := f.store.GetBlockByNumber(num, true)
+			if !ok {
+				return logs, nil
+			}
+
+			blockLogs, err := f.getLogsFromBlock(query, block)
+			if err != nil {
+				return nil, err
+			}
+
+			logs = append(logs, blockLogs...)
+		}
+
+		from = indexedTo + 1
+	}
+
 	for i := from; i <= to; i++ {
 		block, ok := f.store.GetBlockByNumber(i, true)
 		if !ok {
@@ -479,6 +738,102 @@ func (f *FilterManager) GetLogsForQuery(query *LogQuery) ([]*Log, error) {
 	return f.getLogsFromBlocks(query)
 }
 
+// logCursor is the position a paged log query resumes from: the block number
+// and the position, among all the logs of the block, of the first log not returned yet
+type logCursor struct {
+	BlockNumber uint64
+	LogIndex    uint64
+}
+
+// GetLogsPaged returns at most limit logs matching the query, resuming from the cursor if any.
+// A single page scans at most as many blocks as a regular log query may span.
+// The returned cursor is nil once all the logs in the query range have been returned
+func (f *FilterManager) GetLogsPaged(query *LogQuery, cursor *logCursor, limit uint64) ([]*Log, *logCursor, error) {
+	var from, to uint64
+
+	if query.BlockHash != nil {
+		block, ok := f.store.GetBlockByHash(*query.BlockHash, true)
+		if !ok {
+			return nil, nil, ErrBlockNotFound
+		}
+
+		from, to = block.Number(), block.Number()
+	} else {
+		var err error
+
+		if from, to, err = f.resolveLogRange(query); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	offset := uint64(0)
+
+	if cursor != nil {
+		if cursor.BlockNumber < from || cursor.BlockNumber > to {
+			return nil, nil, ErrInvalidLogCursor
+		}
+
+		from, offset = cursor.BlockNumber, cursor.LogIndex
+	}
+
+	logs := make([]*Log, 0)
+
+	for num := from; num <= to; num++ {
+		if num-from > f.getBlockRangeLimit() {
+			// the page has scanned as many blocks as allowed
+			return logs, &logCursor{BlockNumber: num}, nil
+		}
+
+		block, ok := f.store.GetBlockByNumber(num, true)
+		if !ok {
+			break
+		}
+
+		if len(block.Transactions) == 0 {
+			// do not check logs if no txs
+			offset = 0
+
+			continue
+		}
+
+		receipts, err := f.store.GetReceiptsByHash(block.Header.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		position := uint64(0)
+
+		for idx, receipt := range receipts {
+			for logIdx, log := range receipt.Logs {
+				position++
+
+				if position <= offset || !query.Match(log) {
+					continue
+				}
+
+				if uint64(len(logs)) == limit {
+					return logs, &logCursor{BlockNumber: num, LogIndex: position - 1}, nil
+				}
+
+				logs = append(logs, &Log{
+					Address:     log.Address,
+					Topics:      log.Topics,
+					Data:        log.Data,
+					BlockNumber: argUint64(block.Header.Number),
+					BlockHash:   block.Header.Hash,
+					TxHash:      block.Transactions[idx].Hash,
+					TxIndex:     argUint64(idx),
+					LogIndex:    argUint64(logIdx),
+				})
+			}
+		}
+
+		offset = 0
+	}
+
+	return logs, nil, nil
+}
+
 // getFilterByID fetches the filter by the ID
 func (f *FilterManager) getFilterByID(filterID string) filter {
 	f.RLock()
@@ -563,6 +918,10 @@ func (f *FilterManager) removeFilterByID(id string) bool {
 		f.emitSignalToUpdateCh()
 	}
 
+	if _, ok := filter.(*logFilter); ok {
+		f.updateLogsFilter()
+	}
+
 	return true
 }
 
@@ -601,9 +960,55 @@ func (f *FilterManager) addFilter(filter filter) string {
 		f.addFilterTimeout(base)
 	}
 
+	if _, ok := filter.(*logFilter); ok {
+		f.updateLogsFilter()
+	}
+
 	return base.id
 }
 
+// updateLogsFilter narrows the logs received from the chain down to the contracts of the log filters,
+// so that the logs of the other contracts aren't copied for the filter manager. All the logs are received
+// if a log filter matches any contract. It must be called with the lock held
+func (f *FilterManager) updateLogsFilter() {
+	if f.subscription == nil {
+		return
+	}
+
+	var (
+		addresses = []types.Address{}
+		seen      = map[types.Address]bool{}
+	)
+
+	for _, filter := range f.filters {
+		logFilter, ok := filter.(*logFilter)
+		if !ok {
+			continue
+		}
+
+		if len(logFilter.query.Addresses) == 0 {
+			f.subscription.SetFilter(nil)
+
+			return
+		}
+
+		for _, addr := range logFilter.query.Addresses {
+			if !seen[addr] {
+				seen[addr] = true
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		f.subscription.SetFilter(nil)
+
+		return
+	}
+
+	f.subscription.SetFilter(&blockchain.EventFilter{Addresses: addresses})
+}
+
 func (f *FilterManager) emitSignalToUpdateCh() {
 	select {
 	// notify worker of new filter with timeout
@@ -629,7 +1034,7 @@ func (f *FilterManager) nextTimeoutFilter() *filterBase {
 }
 
 // dispatchEvent is an event handler for new block event
-func (f *FilterManager) dispatchEvent(evnt *blockchain.Event) error {
+func (f *FilterManager) dispatchEvent(evnt *blockchain.BusEvent) error {
 	// store new event in each filters
 	f.processEvent(evnt)
 
@@ -641,47 +1046,64 @@ func (f *FilterManager) dispatchEvent(evnt *blockchain.Event) error {
 	return nil
 }
 
+// dispatchTxEvent is an event handler for new txpool event
+func (f *FilterManager) dispatchTxEvent(evnt *txpoolProto.TxPoolEvent) error {
+	if evnt.Type != txpoolProto.EventType_DROPPED {
+		return nil
+	}
+
+	hash := types.StringToHash(evnt.TxHash)
+
+	f.RLock()
+
+	for _, filter := range f.filters {
+		if droppedTxFilter, ok := filter.(*droppedTxFilter); ok {
+			droppedTxFilter.appendHash(hash)
+		}
+	}
+
+	f.RUnlock()
+
+	return f.flushWsFilters()
+}
+
 // processEvent makes each filter append the new data that interests them
-func (f *FilterManager) processEvent(evnt *blockchain.Event) {
+func (f *FilterManager) processEvent(evnt *blockchain.BusEvent) {
 	f.RLock()
 	defer f.RUnlock()
 
-	for _, header := range evnt.NewChain {
-		// first include all the new headers in the blockstream for BlockFilter
-		f.blockStream.push(header)
+	switch evnt.Topic {
+	case blockchain.TopicReorgs:
+		// notify the reorg filters of the replaced blocks
+		reorg := newReorgNotification(evnt.Reorg)
 
-		// process new chain to include new logs for LogFilter
-		if processErr := f.appendLogsToFilters(header); processErr != nil {
-			f.logger.Error(fmt.Sprintf("Unable to process block, %v", processErr))
+		for _, filter := range f.filters {
+			if reorgFilter, ok := filter.(*reorgFilter); ok {
+				reorgFilter.appendReorg(reorg)
+			}
 		}
-	}
-}
 
-// appendLogsToFilters makes each LogFilters append logs in the header
-func (f *FilterManager) appendLogsToFilters(header *types.Header) error {
-	receipts, err := f.store.GetReceiptsByHash(header.Hash)
-	if err != nil {
-		return err
+	case blockchain.TopicNewHeads:
+		// include the new header in the blockstream for BlockFilter
+		f.blockStream.push(evnt.Head.Header)
+
+	case blockchain.TopicLogs:
+		// the logs of the blocks which left the canonical chain are flagged as removed
+		f.appendLogsToFilters(evnt.Logs)
 	}
+}
 
+// appendLogsToFilters makes each LogFilters append the matching logs of the block
+func (f *FilterManager) appendLogsToFilters(logs *blockchain.LogsEvent) {
 	// Get logFilters from filters
 	logFilters := f.getLogFilters()
 	if len(logFilters) == 0 {
-		return nil
+		return
 	}
 
-	block, ok := f.store.GetBlockByHash(header.Hash, true)
-	if !ok {
-		f.logger.Error("could not find block in store", "hash", header.Hash.String())
-
-		return nil
-	}
+	header := logs.Header
 
-	for indx, receipt := range receipts {
-		if receipt.TxHash == types.ZeroHash {
-			// Extract tx Hash
-			receipt.TxHash = block.Transactions[indx].Hash
-		}
+	for indx, receipt := range logs.Receipts {
 		// check the logs with the filters
 		for _, log := range receipt.Logs {
 			for _, f := range logFilters {
@@ -694,14 +1116,12 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header) error {
 						BlockHash:   header.Hash,
 						TxHash:      receipt.TxHash,
 						TxIndex:     argUint64(indx),
-						Removed:     false,
+						Removed:     logs.Removed,
 					})
 				}
 			}
 		}
 	}
-
-	return nil
 }
 
 // flushWsFilters make each filters with web socket connection write the updates to web socket stream
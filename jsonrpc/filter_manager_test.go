@@ -1,12 +1,14 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"math/big"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
@@ -149,6 +151,71 @@ func Test_GetLogsForQuery(t *testing.T) {
 	}
 }
 
+func Test_GetLogsForQuery_BloomIndex(t *testing.T) {
+	t.Parallel()
+
+	topics := [][]types.Hash{
+		{types.StringToHash("4")},
+		{types.StringToHash("5")},
+		{types.StringToHash("6")},
+	}
+
+	// the blocks 1 to 3 have a matching log, only the block 2 is a candidate of the index
+	store := &mockBlockStore{
+		topics:          []types.Hash{topics[0][0], topics[1][0], topics[2][0]},
+		bloomIndexed:    4,
+		bloomCandidates: []uint64{2},
+	}
+	store.setupLogs()
+
+	blocks := make([]*types.Block, 5)
+
+	for i := range blocks {
+		blocks[i] = &types.Block{
+			Header: &types.Header{
+				Number: uint64(i),
+				Hash:   types.StringToHash(strconv.Itoa(i)),
+			},
+			Transactions: []*types.Transaction{
+				{
+					Value: big.NewInt(10),
+				},
+				{
+					Value: big.NewInt(11),
+				},
+				{
+					Value: big.NewInt(12),
+				},
+			},
+		}
+	}
+
+	store.appendBlocksToStore(blocks)
+
+	f := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer f.Close()
+
+	logs, err := f.GetLogsForQuery(&LogQuery{
+		fromBlock: 1,
+		toBlock:   4,
+		Topics:    topics,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, argUint64(2), logs[0].BlockNumber)
+
+	// the blocks not covered by the index are scanned
+	store.bloomIndexed = 2
+
+	logs, err = f.GetLogsForQuery(&LogQuery{
+		fromBlock: 1,
+		toBlock:   4,
+		Topics:    topics,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+}
+
 func Test_GetLogFilterFromID(t *testing.T) {
 	t.Parallel()
 
@@ -236,6 +303,59 @@ func TestFilterLog(t *testing.T) {
 	}
 }
 
+func TestFilterLog_PushedDownAddresses(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	// the events are left queued on the subscription, as the manager doesn't run
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	publishLogs := func(addr types.Address) {
+		store.publishLogs(&mockHeader{
+			header:   &types.Header{Hash: hash1},
+			receipts: []*types.Receipt{{Logs: []*types.Log{{Address: addr}}}},
+		}, false)
+	}
+
+	queued := func() int {
+		n := 0
+
+		for len(m.subscription.EventCh()) > 0 {
+			<-m.subscription.EventCh()
+			n++
+		}
+
+		return n
+	}
+
+	addr1, addr2 := types.StringToAddress("1"), types.StringToAddress("2")
+
+	// the logs of the other contracts aren't received
+	id := m.NewLogFilter(&LogQuery{Addresses: []types.Address{addr1}}, nil)
+
+	publishLogs(addr1)
+	publishLogs(addr2)
+	assert.Equal(t, 1, queued())
+
+	// a filter on any contract receives all the logs
+	anyID := m.NewLogFilter(&LogQuery{}, nil)
+
+	publishLogs(addr2)
+	assert.Equal(t, 1, queued())
+
+	assert.True(t, m.Uninstall(anyID))
+
+	publishLogs(addr2)
+	assert.Equal(t, 0, queued())
+
+	assert.True(t, m.Uninstall(id))
+
+	publishLogs(addr2)
+	assert.Equal(t, 1, queued())
+}
+
 func TestFilterBlock(t *testing.T) {
 	t.Parallel()
 
@@ -381,6 +501,104 @@ func TestFilterWebsocket(t *testing.T) {
 	}
 }
 
+func TestFilterDroppedTxWebsocket(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	go m.Run()
+
+	id := m.NewDroppedTxFilter(mock)
+
+	// the other txpool events are ignored
+	store.txEventCh <- &txpoolProto.TxPoolEvent{
+		Type:   txpoolProto.EventType_ADDED,
+		TxHash: hash1.String(),
+	}
+
+	store.txEventCh <- &txpoolProto.TxPoolEvent{
+		Type:   txpoolProto.EventType_DROPPED,
+		TxHash: hash2.String(),
+	}
+
+	select {
+	case msg := <-mock.msgCh:
+		var notification struct {
+			Method string `json:"method"`
+			Params struct {
+				Subscription string     `json:"subscription"`
+				Result       types.Hash `json:"result"`
+			} `json:"params"`
+		}
+
+		assert.NoError(t, json.Unmarshal(msg, &notification))
+		assert.Equal(t, edgeSubscriptionMethod, notification.Method)
+		assert.Equal(t, id, notification.Params.Subscription)
+		assert.Equal(t, hash2, notification.Params.Result)
+	case <-time.After(2 * time.Second):
+		t.Fatal("dropped transaction not received in 2 seconds")
+	}
+}
+
+func TestFilterReorgWebsocket(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 2),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	defer m.Close()
+
+	id := m.NewReorgFilter(mock)
+
+	// a new head which doesn't replace any block isn't a reorg
+	assert.NoError(t, m.dispatchEvent(&blockchain.BusEvent{
+		Topic: blockchain.TopicNewHeads,
+		Head:  &blockchain.HeadEvent{Header: &types.Header{Number: 1, Hash: hash1}},
+	}))
+
+	assert.NoError(t, m.dispatchEvent(&blockchain.BusEvent{
+		Topic: blockchain.TopicReorgs,
+		Reorg: &blockchain.Event{
+			Type:     blockchain.EventReorg,
+			OldChain: []*types.Header{{Number: 1, Hash: hash1}},
+			NewChain: []*types.Header{{Number: 1, Hash: hash2}, {Number: 2, Hash: hash3}},
+		},
+	}))
+
+	assert.Len(t, mock.msgCh, 1)
+
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			Subscription string             `json:"subscription"`
+			Result       *reorgNotification `json:"result"`
+		} `json:"params"`
+	}
+
+	assert.NoError(t, json.Unmarshal(<-mock.msgCh, &notification))
+	assert.Equal(t, edgeSubscriptionMethod, notification.Method)
+	assert.Equal(t, id, notification.Params.Subscription)
+	assert.Equal(
+		t,
+		&reorgNotification{
+			Removed: []*reorgBlock{{Number: 1, Hash: hash1}},
+			Added:   []*reorgBlock{{Number: 1, Hash: hash2}, {Number: 2, Hash: hash3}},
+		},
+		notification.Params.Result,
+	)
+}
+
 type mockWsConn struct {
 	msgCh    chan []byte
 	filterID string
@@ -452,12 +670,9 @@ func TestClosedFilterDeletion(t *testing.T) {
 	assert.True(t, m.Exists(id))
 
 	// event is sent to the filter but writing to connection should fail
-	err := m.dispatchEvent(&blockchain.Event{
-		NewChain: []*types.Header{
-			{
-				Hash: types.StringToHash("1"),
-			},
-		},
+	err := m.dispatchEvent(&blockchain.BusEvent{
+		Topic: blockchain.TopicNewHeads,
+		Head:  &blockchain.HeadEvent{Header: &types.Header{Hash: types.StringToHash("1")}},
 	})
 
 	// should not return error when the error is websocket.ErrCloseSen because filter is removed instead
@@ -0,0 +1,96 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrFinalizedBlockNotFound = errors.New("finalized block not found")
+	ErrSafeBlockNotFound      = errors.New("safe block not found")
+)
+
+type finalityGetter interface {
+	FinalizedHeader() *types.Header
+	SafeHeader() *types.Header
+}
+
+type headerGetter interface {
+	finalityGetter
+
+	Header() *types.Header
+	GetHeaderByNumber(block uint64) (*types.Header, bool)
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+}
+
+func getHeaderFromBlockNumberOrHash(store headerGetter, bnh *BlockNumberOrHash) (*types.Header, error) {
+	var (
+		header *types.Header
+		err    error
+	)
+
+	if bnh.BlockNumber != nil {
+		header, err = getBlockHeader(store, *bnh.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the header of block %d: %w", *bnh.BlockNumber, err)
+		}
+	} else if bnh.BlockHash != nil {
+		block, ok := store.GetBlockByHash(*bnh.BlockHash, false)
+		if !ok {
+			return nil, fmt.Errorf("could not find block referenced by the hash %s", bnh.BlockHash.String())
+		}
+
+		header = block.Header
+	}
+
+	return header, nil
+}
+
+func getBlockHeader(store headerGetter, number BlockNumber) (*types.Header, error) {
+	switch number {
+	case LatestBlockNumber:
+		return store.Header(), nil
+
+	case EarliestBlockNumber:
+		header, ok := store.GetHeaderByNumber(uint64(0))
+		if !ok {
+			return nil, fmt.Errorf("error fetching genesis block header")
+		}
+
+		return header, nil
+
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("fetching the pending header is not supported")
+
+	case FinalizedBlockNumber, SafeBlockNumber:
+		return getFinalityHeader(store, number)
+
+	default:
+		// Convert the block number from hex to uint64
+		header, ok := store.GetHeaderByNumber(uint64(number))
+		if !ok {
+			return nil, fmt.Errorf("error fetching block number %d header", uint64(number))
+		}
+
+		return header, nil
+	}
+}
+
+// getFinalityHeader returns the finalized or the safe header, as marked by the consensus engine
+func getFinalityHeader(store finalityGetter, number BlockNumber) (*types.Header, error) {
+	if number == SafeBlockNumber {
+		if header := store.SafeHeader(); header != nil {
+			return header, nil
+		}
+
+		return nil, ErrSafeBlockNotFound
+	}
+
+	if header := store.FinalizedHeader(); header != nil {
+		return header, nil
+	}
+
+	return nil, ErrFinalizedBlockNotFound
+}
@@ -38,12 +38,14 @@ type JSONRPC struct {
 	logger     hclog.Logger
 	config     *Config
 	dispatcher dispatcher
+	auth       *apiKeyAuth // authentication of the requests, nil if no API key is configured
 }
 
 type dispatcher interface {
 	RemoveFilterByWs(conn wsConn)
 	HandleWs(reqBody []byte, conn wsConn) ([]byte, error)
 	Handle(reqBody []byte) ([]byte, error)
+	setLimits(batchLengthLimit, blockRangeLimit uint64)
 }
 
 // JSONRPCStore defines all the methods required
@@ -53,6 +55,8 @@ type JSONRPCStore interface {
 	networkStore
 	txPoolStore
 	filterManagerStore
+	edgeStore
+	debugStore
 }
 
 type Config struct {
@@ -63,15 +67,25 @@ type Config struct {
 	PriceLimit               uint64
 	BatchLengthLimit         uint64
 	BlockRangeLimit          uint64
+	// APIKeys are the keys the requests must carry, the requests aren't authenticated if there is none
+	APIKeys []*APIKey
+	// Metrics are the JSON-RPC metrics, they are discarded if nil
+	Metrics *Metrics
 }
 
 // NewJSONRPC returns the JSONRPC http server
 func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+
 	srv := &JSONRPC{
 		logger: logger.Named("jsonrpc"),
 		config: config,
 		dispatcher: newDispatcher(logger, config.Store, config.ChainID, config.PriceLimit,
 			config.BatchLengthLimit, config.BlockRangeLimit),
+		auth: newAPIKeyAuth(config.APIKeys, metrics),
 	}
 
 	// start http server
@@ -82,6 +96,11 @@ func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
 	return srv, nil
 }
 
+// SetLimits sets the maximum length of the batch requests, and the maximum block range of the logs queries
+func (j *JSONRPC) SetLimits(batchLengthLimit, blockRangeLimit uint64) {
+	j.dispatcher.setLimits(batchLengthLimit, blockRangeLimit)
+}
+
 func (j *JSONRPC) setupHTTP() error {
 	j.logger.Info("http server started", "addr", j.config.Addr.String())
 
@@ -93,10 +112,18 @@ func (j *JSONRPC) setupHTTP() error {
 	mux := http.DefaultServeMux
 
 	// The middleware factory returns a handler, so we need to wrap the handler function properly.
-	jsonRPCHandler := http.HandlerFunc(j.handle)
-	mux.Handle("/", middlewareFactory(j.config)(jsonRPCHandler))
+	var (
+		jsonRPCHandler http.Handler = http.HandlerFunc(j.handle)
+		wsHandler      http.Handler = http.HandlerFunc(j.handleWs)
+	)
+
+	if j.auth != nil {
+		jsonRPCHandler = j.auth.middleware(jsonRPCHandler)
+		wsHandler = j.auth.middleware(wsHandler)
+	}
 
-	mux.HandleFunc("/ws", j.handleWs)
+	mux.Handle("/", middlewareFactory(j.config)(jsonRPCHandler))
+	mux.Handle("/ws", wsHandler)
 
 	srv := http.Server{
 		Handler:           mux,
@@ -208,6 +235,12 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 
 	wrapConn := &wsWrapper{ws: ws, logger: j.logger}
 
+	// the messages of the connection are accounted to the API key it was opened with
+	var key *apiKeyLimiter
+	if j.auth != nil {
+		key, _ = j.auth.authenticate(req)
+	}
+
 	j.logger.Info("Websocket connection established")
 	// Run the listen loop
 	for {
@@ -232,6 +265,15 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if isSupportedWSType(msgType) {
+			if key != nil && !j.auth.admit(key) {
+				_ = wrapConn.WriteMessage(
+					msgType,
+					[]byte(fmt.Sprintf("WS Handle error: %s", errAPIKeyLimited.Error())),
+				)
+
+				continue
+			}
+
 			go func() {
 				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
 				if handleErr != nil {
@@ -254,7 +296,7 @@ func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set(
 		"Access-Control-Allow-Headers",
-		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
+		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, "+apiKeyHeader,
 	)
 
 	if (*req).Method == "OPTIONS" {
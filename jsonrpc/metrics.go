@@ -0,0 +1,59 @@
+package jsonrpc
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the JSON-RPC metrics
+type Metrics struct {
+	// Requests authenticated with an API key, labeled by key name
+	Requests metrics.Counter
+	// Requests rejected for exceeding the rate limit of their API key, labeled by key name
+	RateLimitedRequests metrics.Counter
+	// Requests rejected for a missing or unknown API key
+	UnauthorizedRequests metrics.Counter
+}
+
+// GetPrometheusMetrics return the JSON-RPC metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	keyLabels := append(labels, "api_key")
+
+	return &Metrics{
+		Requests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "api_key_requests",
+			Help:      "Number of requests authenticated with each API key.",
+		}, keyLabels).With(labelsWithValues...),
+		RateLimitedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "api_key_rate_limited_requests",
+			Help:      "Number of requests rejected for exceeding the rate limit of their API key.",
+		}, keyLabels).With(labelsWithValues...),
+		UnauthorizedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "unauthorized_requests",
+			Help:      "Number of requests rejected for a missing or unknown API key.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational JSON-RPC metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		Requests:             discard.NewCounter(),
+		RateLimitedRequests:  discard.NewCounter(),
+		UnauthorizedRequests: discard.NewCounter(),
+	}
+}
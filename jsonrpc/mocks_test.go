@@ -7,7 +7,9 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 )
 
 type mockAccount struct {
@@ -49,7 +51,8 @@ type mockStore struct {
 	JSONRPCStore
 
 	header       *types.Header
-	subscription *blockchain.MockSubscription
+	bus          *blockchain.EventBus
+	txEventCh    chan *txpoolProto.TxPoolEvent
 	receiptsLock sync.Mutex
 	receipts     map[types.Hash][]*types.Receipt
 	accounts     map[types.Address]*state.Account
@@ -57,33 +60,73 @@ type mockStore struct {
 
 func newMockStore() *mockStore {
 	return &mockStore{
-		header:       &types.Header{Number: 0},
-		subscription: blockchain.NewMockSubscription(),
-		accounts:     map[types.Address]*state.Account{},
+		header:    &types.Header{Number: 0},
+		bus:       blockchain.NewEventBus(hclog.NewNullLogger()),
+		txEventCh: make(chan *txpoolProto.TxPoolEvent),
+		accounts:  map[types.Address]*state.Account{},
 	}
 }
 
+// emitEvent publishes the events of the mock event as the blockchain does,
+// the replaced blocks first and then the new ones
 func (m *mockStore) emitEvent(evnt *mockEvent) {
+	m.receiptsLock.Lock()
+
 	if m.receipts == nil {
 		m.receipts = map[types.Hash][]*types.Receipt{}
 	}
 
-	bEvnt := &blockchain.Event{
-		NewChain: []*types.Header{},
-		OldChain: []*types.Header{},
-	}
-
 	for _, i := range evnt.NewChain {
 		m.receipts[i.header.Hash] = i.receipts
-		bEvnt.NewChain = append(bEvnt.NewChain, i.header)
 	}
 
 	for _, i := range evnt.OldChain {
 		m.receipts[i.header.Hash] = i.receipts
-		bEvnt.OldChain = append(bEvnt.OldChain, i.header)
 	}
 
-	m.subscription.Push(bEvnt)
+	m.receiptsLock.Unlock()
+
+	if len(evnt.OldChain) > 0 {
+		reorg := &blockchain.Event{Type: blockchain.EventReorg}
+
+		for _, i := range evnt.OldChain {
+			reorg.OldChain = append(reorg.OldChain, i.header)
+		}
+
+		for _, i := range evnt.NewChain {
+			reorg.NewChain = append(reorg.NewChain, i.header)
+		}
+
+		m.bus.Publish(&blockchain.BusEvent{Topic: blockchain.TopicReorgs, Reorg: reorg})
+
+		for _, i := range evnt.OldChain {
+			m.publishLogs(i, true)
+		}
+	}
+
+	for _, i := range evnt.NewChain {
+		m.bus.Publish(&blockchain.BusEvent{
+			Topic: blockchain.TopicNewHeads,
+			Head:  &blockchain.HeadEvent{Header: i.header},
+		})
+
+		m.publishLogs(i, false)
+	}
+}
+
+func (m *mockStore) publishLogs(h *mockHeader, removed bool) {
+	if len(h.receipts) == 0 {
+		return
+	}
+
+	m.bus.Publish(&blockchain.BusEvent{
+		Topic: blockchain.TopicLogs,
+		Logs: &blockchain.LogsEvent{
+			Header:   h.header,
+			Receipts: h.receipts,
+			Removed:  removed,
+		},
+	})
 }
 
 func (m *mockStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
@@ -111,8 +154,20 @@ func (m *mockStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
 	return receipts, nil
 }
 
-func (m *mockStore) SubscribeEvents() blockchain.Subscription {
-	return m.subscription
+func (m *mockStore) SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription {
+	return m.bus.Subscribe(buffer, topics...)
+}
+
+func (m *mockStore) SubscribeTxEvents(_ []txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return m.txEventCh, func() {}
+}
+
+func (m *mockStore) BloomIndexed() uint64 {
+	return 0
+}
+
+func (m *mockStore) BloomCandidates(from, to uint64, groups [][][]byte) []uint64 {
+	return nil
 }
 
 func (m *mockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
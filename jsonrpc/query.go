@@ -27,12 +27,12 @@ func (q *LogQuery) addTopicSet(set ...string) error {
 	res := []types.Hash{}
 
 	for _, i := range set {
-		item := types.Hash{}
-		if err := item.UnmarshalText([]byte(i)); err != nil {
-			return err
+		buf, err := parseFixedData(i, types.HashLength)
+		if err != nil {
+			return fmt.Errorf("invalid topic %q: %w", i, err)
 		}
 
-		res = append(res, item)
+		res = append(res, types.BytesToHash(buf))
 	}
 
 	q.Topics = append(q.Topics, res)
@@ -46,13 +46,12 @@ func (q *LogQuery) addAddress(raw string) error {
 		q.Addresses = []types.Address{}
 	}
 
-	addr := types.Address{}
-
-	if err := addr.UnmarshalText([]byte(raw)); err != nil {
-		return err
+	buf, err := parseFixedData(raw, types.AddressLength)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", raw, err)
 	}
 
-	q.Addresses = append(q.Addresses, addr)
+	q.Addresses = append(q.Addresses, types.BytesToAddress(buf))
 
 	return nil
 }
@@ -75,7 +74,7 @@ func decodeLogQueryFromInterface(i interface{}) (*LogQuery, error) {
 // UnmarshalJSON decodes a json object
 func (q *LogQuery) UnmarshalJSON(data []byte) error {
 	var obj struct {
-		BlockHash *types.Hash   `json:"blockHash"`
+		BlockHash *string       `json:"blockHash"`
 		FromBlock string        `json:"fromBlock"`
 		ToBlock   string        `json:"toBlock"`
 		Address   interface{}   `json:"address"`
@@ -88,7 +87,15 @@ func (q *LogQuery) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	q.BlockHash = obj.BlockHash
+	if obj.BlockHash != nil {
+		buf, err := parseFixedData(*obj.BlockHash, types.HashLength)
+		if err != nil {
+			return fmt.Errorf("invalid block hash: %w", err)
+		}
+
+		hash := types.BytesToHash(buf)
+		q.BlockHash = &hash
+	}
 
 	if obj.FromBlock == "" {
 		q.fromBlock = LatestBlockNumber
@@ -174,6 +181,36 @@ func (q *LogQuery) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// bloomGroups returns the values the logs bloom of a matching block contains, as groups
+// of alternatives: the addresses, then the topics of each position. Wildcards are skipped
+func (q *LogQuery) bloomGroups() [][][]byte {
+	groups := make([][][]byte, 0, len(q.Topics)+1)
+
+	if len(q.Addresses) > 0 {
+		group := make([][]byte, len(q.Addresses))
+		for i, addr := range q.Addresses {
+			group[i] = addr.Bytes()
+		}
+
+		groups = append(groups, group)
+	}
+
+	for _, sub := range q.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+
+		group := make([][]byte, len(sub))
+		for i, topic := range sub {
+			group[i] = topic.Bytes()
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
 // Match returns whether the receipt includes topics for this filter
 func (q *LogQuery) Match(log *types.Log) bool {
 	// check addresses
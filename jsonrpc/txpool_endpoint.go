@@ -22,8 +22,13 @@ type TxPool struct {
 }
 
 type ContentResponse struct {
-	Pending map[types.Address]map[uint64]*txpoolTransaction `json:"pending"`
-	Queued  map[types.Address]map[uint64]*txpoolTransaction `json:"queued"`
+	Pending map[types.Address]map[uint64]*transaction `json:"pending"`
+	Queued  map[types.Address]map[uint64]*transaction `json:"queued"`
+}
+
+type ContentFromResponse struct {
+	Pending map[uint64]*transaction `json:"pending"`
+	Queued  map[uint64]*transaction `json:"queued"`
 }
 
 type InspectResponse struct {
@@ -34,116 +39,93 @@ type InspectResponse struct {
 }
 
 type StatusResponse struct {
-	Pending uint64 `json:"pending"`
-	Queued  uint64 `json:"queued"`
+	Pending argUint64 `json:"pending"`
+	Queued  argUint64 `json:"queued"`
 }
 
-type txpoolTransaction struct {
-	Nonce       argUint64      `json:"nonce"`
-	GasPrice    argBig         `json:"gasPrice"`
-	Gas         argUint64      `json:"gas"`
-	To          *types.Address `json:"to"`
-	Value       argBig         `json:"value"`
-	Input       argBytes       `json:"input"`
-	Hash        types.Hash     `json:"hash"`
-	From        types.Address  `json:"from"`
-	BlockHash   types.Hash     `json:"blockHash"`
-	BlockNumber interface{}    `json:"blockNumber"`
-	TxIndex     interface{}    `json:"transactionIndex"`
-}
+// toTxsByNonce returns the transactions of an account by nonce, in the format of the pending transactions
+func toTxsByNonce(txs []*types.Transaction) map[uint64]*transaction {
+	byNonce := make(map[uint64]*transaction, len(txs))
 
-func toTxPoolTransaction(t *types.Transaction) *txpoolTransaction {
-	return &txpoolTransaction{
-		Nonce:       argUint64(t.Nonce),
-		GasPrice:    argBig(*t.GasPrice),
-		Gas:         argUint64(t.Gas),
-		To:          t.To,
-		Value:       argBig(*t.Value),
-		Input:       t.Input,
-		Hash:        t.Hash,
-		From:        t.From,
-		BlockHash:   types.ZeroHash,
-		BlockNumber: nil,
-		TxIndex:     nil,
+	for _, tx := range txs {
+		byNonce[tx.Nonce] = toPendingTransaction(tx)
 	}
+
+	return byNonce
 }
 
-// Create response for txpool_content request.
-// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_content.
-func (t *TxPool) Content() (interface{}, error) {
-	pendingTxs, queuedTxs := t.store.GetTxs(true)
+// toTxsByAccount returns the transactions of the accounts by account and nonce
+func toTxsByAccount(txs map[types.Address][]*types.Transaction) map[types.Address]map[uint64]*transaction {
+	byAccount := make(map[types.Address]map[uint64]*transaction, len(txs))
 
-	// collect pending
-	pendingRPCTxs := make(map[types.Address]map[uint64]*txpoolTransaction)
-	for addr, txs := range pendingTxs {
-		pendingRPCTxs[addr] = make(map[uint64]*txpoolTransaction, len(txs))
+	for addr, accountTxs := range txs {
+		byAccount[addr] = toTxsByNonce(accountTxs)
+	}
 
-		for _, tx := range txs {
-			nonce := tx.Nonce
-			rpcTx := toTxPoolTransaction(tx)
+	return byAccount
+}
 
-			pendingRPCTxs[addr][nonce] = rpcTx
-		}
-	}
+// toInspectSummaries returns the summaries of the transactions of the accounts by account and nonce
+func toInspectSummaries(txs map[types.Address][]*types.Transaction) map[string]map[string]string {
+	summaries := make(map[string]map[string]string, len(txs))
 
-	// collect enqueued
-	queuedRPCTxs := make(map[types.Address]map[uint64]*txpoolTransaction)
-	for addr, txs := range queuedTxs {
-		queuedRPCTxs[addr] = make(map[uint64]*txpoolTransaction, len(txs))
+	for addr, accountTxs := range txs {
+		accountSummaries := make(map[string]string, len(accountTxs))
 
-		for _, tx := range txs {
-			nonce := tx.Nonce
-			rpcTx := toTxPoolTransaction(tx)
+		for _, tx := range accountTxs {
+			to := "contract creation"
+			if tx.To != nil {
+				to = tx.To.String()
+			}
 
-			queuedRPCTxs[addr][nonce] = rpcTx
+			accountSummaries[strconv.FormatUint(tx.Nonce, 10)] = fmt.Sprintf(
+				"%s: %d wei + %d gas × %d wei", to, tx.Value, tx.Gas, tx.GasPrice,
+			)
 		}
+
+		summaries[addr.String()] = accountSummaries
 	}
 
+	return summaries
+}
+
+// Create response for txpool_content request.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_content.
+func (t *TxPool) Content() (interface{}, error) {
+	pendingTxs, queuedTxs := t.store.GetTxs(true)
+
 	resp := ContentResponse{
-		Pending: pendingRPCTxs,
-		Queued:  queuedRPCTxs,
+		Pending: toTxsByAccount(pendingTxs),
+		Queued:  toTxsByAccount(queuedTxs),
 	}
 
 	return resp, nil
 }
 
-// Create response for txpool_inspect request.
-// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_inspect.
-func (t *TxPool) Inspect() (interface{}, error) {
+// Create response for txpool_contentFrom request, the content of the pool for a single account.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_contentfrom.
+func (t *TxPool) ContentFrom(addr types.Address) (interface{}, error) {
 	pendingTxs, queuedTxs := t.store.GetTxs(true)
 
-	// collect pending
-	pendingRPCTxs := make(map[string]map[string]string)
-	for addr, txs := range pendingTxs {
-		pendingRPCTxs[addr.String()] = make(map[string]string, len(txs))
-
-		for _, tx := range txs {
-			nonceStr := strconv.FormatUint(tx.Nonce, 10)
-			pendingRPCTxs[addr.String()][nonceStr] = fmt.Sprintf(
-				"%d wei + %d gas x %d wei", tx.Value, tx.Gas, tx.GasPrice,
-			)
-		}
+	resp := ContentFromResponse{
+		Pending: toTxsByNonce(pendingTxs[addr]),
+		Queued:  toTxsByNonce(queuedTxs[addr]),
 	}
 
-	// collect enqueued
-	queuedRPCTxs := make(map[string]map[string]string)
-	for addr, txs := range queuedTxs {
-		queuedRPCTxs[addr.String()] = make(map[string]string, len(txs))
+	return resp, nil
+}
 
-		for _, tx := range txs {
-			nonceStr := strconv.FormatUint(tx.Nonce, 10)
-			queuedRPCTxs[addr.String()][nonceStr] = fmt.Sprintf(
-				"%d wei + %d gas x %d wei", tx.Value, tx.Gas, tx.GasPrice,
-			)
-		}
-	}
+// Create response for txpool_inspect request.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_inspect.
+func (t *TxPool) Inspect() (interface{}, error) {
+	pendingTxs, queuedTxs := t.store.GetTxs(true)
 
 	// get capacity of the TxPool
 	current, max := t.store.GetCapacity()
 
 	resp := InspectResponse{
-		Pending:         pendingRPCTxs,
-		Queued:          queuedRPCTxs,
+		Pending:         toInspectSummaries(pendingTxs),
+		Queued:          toInspectSummaries(queuedTxs),
 		CurrentCapacity: current,
 		MaxCapacity:     max,
 	}
@@ -169,8 +151,8 @@ func (t *TxPool) Status() (interface{}, error) {
 	}
 
 	resp := StatusResponse{
-		Pending: uint64(pendingCount),
-		Queued:  uint64(queuedCount),
+		Pending: argUint64(pendingCount),
+		Queued:  argUint64(queuedCount),
 	}
 
 	return resp, nil
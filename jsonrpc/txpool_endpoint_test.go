@@ -54,8 +54,9 @@ func TestContentEndpoint(t *testing.T) {
 		assert.Equal(t, testTx.From, txData.From)
 		assert.Equal(t, *testTx.Value, big.Int(txData.Value))
 		assert.Equal(t, testTx.Input, []byte(txData.Input))
-		assert.Equal(t, nil, txData.BlockNumber)
-		assert.Equal(t, nil, txData.TxIndex)
+		assert.Nil(t, txData.BlockHash)
+		assert.Nil(t, txData.BlockNumber)
+		assert.Nil(t, txData.TxIndex)
 	})
 
 	//nolint:dupl
@@ -84,8 +85,9 @@ func TestContentEndpoint(t *testing.T) {
 		assert.Equal(t, testTx.From, txData.From)
 		assert.Equal(t, *testTx.Value, big.Int(txData.Value))
 		assert.Equal(t, testTx.Input, []byte(txData.Input))
-		assert.Equal(t, nil, txData.BlockNumber)
-		assert.Equal(t, nil, txData.TxIndex)
+		assert.Nil(t, txData.BlockHash)
+		assert.Nil(t, txData.BlockNumber)
+		assert.Nil(t, txData.TxIndex)
 	})
 
 	t.Run("returns correct ContentResponse data for multiple transactions", func(t *testing.T) {
@@ -117,6 +119,37 @@ func TestContentEndpoint(t *testing.T) {
 	})
 }
 
+func TestContentFromEndpoint(t *testing.T) {
+	t.Parallel()
+
+	mockStore := newMockTxPoolStore()
+	address1 := types.Address{0x1}
+	testTx1 := newTestTransaction(2, address1)
+	testTx2 := newTestTransaction(5, address1)
+	address2 := types.Address{0x2}
+	mockStore.pending[address1] = []*types.Transaction{testTx1}
+	mockStore.queued[address1] = []*types.Transaction{testTx2}
+	mockStore.pending[address2] = []*types.Transaction{newTestTransaction(7, address2)}
+	txPoolEndpoint := &TxPool{mockStore}
+
+	result, _ := txPoolEndpoint.ContentFrom(address1)
+	//nolint:forcetypeassert
+	response := result.(ContentFromResponse)
+
+	assert.Len(t, response.Pending, 1)
+	assert.Equal(t, testTx1.Hash, response.Pending[testTx1.Nonce].Hash)
+	assert.Len(t, response.Queued, 1)
+	assert.Equal(t, testTx2.Hash, response.Queued[testTx2.Nonce].Hash)
+
+	// an account without transactions has empty content
+	result, _ = txPoolEndpoint.ContentFrom(types.Address{0x3})
+	//nolint:forcetypeassert
+	response = result.(ContentFromResponse)
+
+	assert.Empty(t, response.Pending)
+	assert.Empty(t, response.Queued)
+}
+
 func TestInspectEndpoint(t *testing.T) {
 	t.Parallel()
 
@@ -157,7 +190,30 @@ func TestInspectEndpoint(t *testing.T) {
 		assert.Equal(t, uint64(1), response.CurrentCapacity)
 		transactionInfo := response.Queued[testTx.From.String()]
 		assert.NotNil(t, transactionInfo)
-		assert.NotNil(t, transactionInfo[strconv.FormatUint(testTx.Nonce, 10)])
+		assert.Equal(t,
+			addr1.String()+": 200 wei + 200 gas × 1 wei",
+			transactionInfo[strconv.FormatUint(testTx.Nonce, 10)],
+		)
+	})
+
+	t.Run("returns contract creations", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := newMockTxPoolStore()
+		address1 := types.Address{0x1}
+		testTx := newTestTransaction(2, address1)
+		testTx.To = nil
+		mockStore.pending[address1] = []*types.Transaction{testTx}
+		txPoolEndpoint := &TxPool{mockStore}
+
+		result, _ := txPoolEndpoint.Inspect()
+		//nolint:forcetypeassert
+		response := result.(InspectResponse)
+
+		assert.Equal(t,
+			"contract creation: 200 wei + 200 gas × 1 wei",
+			response.Pending[address1.String()][strconv.FormatUint(testTx.Nonce, 10)],
+		)
 	})
 
 	t.Run("returns correct data for pending transactions", func(t *testing.T) {
@@ -198,8 +254,8 @@ func TestStatusEndpoint(t *testing.T) {
 		//nolint:forcetypeassert
 		response := result.(StatusResponse)
 
-		assert.Equal(t, uint64(0), response.Pending)
-		assert.Equal(t, uint64(0), response.Queued)
+		assert.Equal(t, argUint64(0), response.Pending)
+		assert.Equal(t, argUint64(0), response.Queued)
 	})
 
 	t.Run("returns correct count of pending/queued transactions", func(t *testing.T) {
@@ -223,8 +279,8 @@ func TestStatusEndpoint(t *testing.T) {
 		//nolint:forcetypeassert
 		response := result.(StatusResponse)
 
-		assert.Equal(t, uint64(3), response.Pending)
-		assert.Equal(t, uint64(2), response.Queued)
+		assert.Equal(t, argUint64(3), response.Pending)
+		assert.Equal(t, argUint64(2), response.Queued)
 	})
 }
 
@@ -3,7 +3,6 @@ package jsonrpc
 import (
 	"math/big"
 	"strconv"
-	"strings"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -29,6 +28,12 @@ type transaction struct {
 	BlockHash   *types.Hash    `json:"blockHash"`
 	BlockNumber *argUint64     `json:"blockNumber"`
 	TxIndex     *argUint64     `json:"transactionIndex"`
+
+	// the dynamic fee transaction fields (EIP-1559), the gas price being the fee cap
+	Type      argUint64 `json:"type"`
+	ChainID   *argBig   `json:"chainId,omitempty"`
+	GasTipCap *argBig   `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap *argBig   `json:"maxFeePerGas,omitempty"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -64,6 +69,16 @@ func toTransaction(
 		S:        argBig(*t.S),
 		Hash:     t.Hash,
 		From:     t.From,
+		Type:     argUint64(t.Type),
+	}
+
+	if t.Type == types.DynamicFeeTx {
+		res.GasTipCap = argBigPtr(t.GasTipCap)
+		res.GasFeeCap = argBigPtr(t.GasFeeCap)
+
+		if t.ChainID != nil {
+			res.ChainID = argBigPtr(t.ChainID)
+		}
 	}
 
 	if blockNumber != nil {
@@ -102,6 +117,7 @@ type block struct {
 	Hash            types.Hash          `json:"hash"`
 	Transactions    []transactionOrHash `json:"transactions"`
 	Uncles          []types.Hash        `json:"uncles"`
+	BaseFee         *argUint64          `json:"baseFeePerGas,omitempty"`
 }
 
 func toBlock(b *types.Block, fullTx bool) *block {
@@ -129,6 +145,10 @@ func toBlock(b *types.Block, fullTx bool) *block {
 		Uncles:          []types.Hash{},
 	}
 
+	if h.London {
+		res.BaseFee = argUintPtr(h.BaseFee)
+	}
+
 	for idx, txn := range b.Transactions {
 		if fullTx {
 			res.Transactions = append(
@@ -169,6 +189,8 @@ type receipt struct {
 	ContractAddress   *types.Address `json:"contractAddress"`
 	FromAddr          types.Address  `json:"from"`
 	ToAddr            *types.Address `json:"to"`
+	EffectiveGasPrice argBig         `json:"effectiveGasPrice"`
+	Type              argUint64      `json:"type"`
 }
 
 type Log struct {
@@ -192,13 +214,11 @@ func argBigPtr(b *big.Int) *argBig {
 }
 
 func (a *argBig) UnmarshalText(input []byte) error {
-	buf, err := decodeToHex(input)
+	b, err := parseBigQuantity(string(input))
 	if err != nil {
 		return err
 	}
 
-	b := new(big.Int)
-	b.SetBytes(buf)
 	*a = argBig(*b)
 
 	return nil
@@ -235,9 +255,7 @@ func (u argUint64) MarshalText() ([]byte, error) {
 }
 
 func (u *argUint64) UnmarshalText(input []byte) error {
-	str := strings.TrimPrefix(string(input), "0x")
-	num, err := strconv.ParseUint(str, 16, 64)
-
+	num, err := parseQuantity(string(input))
 	if err != nil {
 		return err
 	}
@@ -260,29 +278,16 @@ func (b argBytes) MarshalText() ([]byte, error) {
 }
 
 func (b *argBytes) UnmarshalText(input []byte) error {
-	hh, err := decodeToHex(input)
+	buf, err := parseData(string(input))
 	if err != nil {
-		return nil
+		return err
 	}
 
-	aux := make([]byte, len(hh))
-	copy(aux[:], hh[:])
-	*b = aux
+	*b = buf
 
 	return nil
 }
 
-func decodeToHex(b []byte) ([]byte, error) {
-	str := string(b)
-	str = strings.TrimPrefix(str, "0x")
-
-	if len(str)%2 != 0 {
-		str = "0" + str
-	}
-
-	return hex.DecodeString(str)
-}
-
 func encodeToHex(b []byte) []byte {
 	str := hex.EncodeToString(b)
 	if len(str)%2 != 0 {
@@ -292,16 +297,19 @@ func encodeToHex(b []byte) []byte {
 	return []byte("0x" + str)
 }
 
-// txnArgs is the transaction argument for the rpc endpoints
+// txnArgs is the transaction argument for the rpc endpoints.
+// Setting any of the fee caps makes it a dynamic fee transaction
 type txnArgs struct {
-	From     *types.Address
-	To       *types.Address
-	Gas      *argUint64
-	GasPrice *argBytes
-	Value    *argBytes
-	Data     *argBytes
-	Input    *argBytes
-	Nonce    *argUint64
+	From                 *types.Address
+	To                   *types.Address
+	Gas                  *argUint64
+	GasPrice             *argBytes
+	MaxFeePerGas         *argBytes
+	MaxPriorityFeePerGas *argBytes
+	Value                *argBytes
+	Data                 *argBytes
+	Input                *argBytes
+	Nonce                *argUint64
 }
 
 type progression struct {
@@ -309,4 +317,6 @@ type progression struct {
 	StartingBlock string `json:"startingBlock"`
 	CurrentBlock  string `json:"currentBlock"`
 	HighestBlock  string `json:"highestBlock"`
+	Stage         string `json:"stage"`
+	Peer          string `json:"peer,omitempty"`
 }
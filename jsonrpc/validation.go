@@ -0,0 +1,305 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errMissingHexPrefix = errors.New("hex string without 0x prefix")
+	errEmptyHexNumber   = errors.New("hex number without digits")
+	errInvalidHex       = errors.New("invalid hex string")
+	errOddHexLength     = errors.New("hex string of odd length")
+	errUint64Range      = errors.New("hex number exceeds 64 bits")
+	errUint256Range     = errors.New("hex number exceeds 256 bits")
+)
+
+var (
+	addressType         = reflect.TypeOf(types.Address{})
+	hashType            = reflect.TypeOf(types.Hash{})
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// trimHexPrefix returns the digits of a 0x prefixed hex string
+func trimHexPrefix(str string) (string, error) {
+	if !strings.HasPrefix(str, "0x") && !strings.HasPrefix(str, "0X") {
+		return "", errMissingHexPrefix
+	}
+
+	return str[2:], nil
+}
+
+// parseQuantity decodes a 0x prefixed hex number fitting 64 bits
+func parseQuantity(str string) (uint64, error) {
+	digits, err := trimHexPrefix(str)
+	if err != nil {
+		return 0, err
+	}
+
+	if digits == "" {
+		return 0, errEmptyHexNumber
+	}
+
+	num, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, errUint64Range
+		}
+
+		return 0, errInvalidHex
+	}
+
+	return num, nil
+}
+
+// parseBigQuantity decodes a 0x prefixed hex number fitting 256 bits
+func parseBigQuantity(str string) (*big.Int, error) {
+	digits, err := trimHexPrefix(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if digits == "" {
+		return nil, errEmptyHexNumber
+	}
+
+	// the signs parsed by big.Int aren't part of the hex format
+	if digits[0] == '+' || digits[0] == '-' {
+		return nil, errInvalidHex
+	}
+
+	num, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, errInvalidHex
+	}
+
+	if num.BitLen() > 256 {
+		return nil, errUint256Range
+	}
+
+	return num, nil
+}
+
+// parseData decodes 0x prefixed hex data, "0x" being empty data
+func parseData(str string) ([]byte, error) {
+	digits, err := trimHexPrefix(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(digits)%2 != 0 {
+		return nil, errOddHexLength
+	}
+
+	buf, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, errInvalidHex
+	}
+
+	return buf, nil
+}
+
+// parseFixedData decodes 0x prefixed hex data of the given length, like addresses and hashes
+func parseFixedData(str string, length int) ([]byte, error) {
+	buf, err := parseData(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) != length {
+		return nil, fmt.Errorf("hex string of %d bytes, want %d", len(buf), length)
+	}
+
+	return buf, nil
+}
+
+// decodeParams decodes the positional parameters of a request into the inputs of the method.
+// The error tells which parameter is invalid and why.
+// The missing trailing parameters are left to their zero value
+func decodeParams(params json.RawMessage, inputs []interface{}) Error {
+	if isNullParam(params) {
+		return nil
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(params, &raws); err != nil {
+		return NewInvalidParamsError("params must be an array of arguments")
+	}
+
+	if len(raws) > len(inputs) {
+		return NewInvalidParamsError(fmt.Sprintf("too many arguments, want at most %d", len(inputs)))
+	}
+
+	for i, raw := range raws {
+		if err := validateParam(raw, reflect.TypeOf(inputs[i]).Elem(), ""); err != nil {
+			return NewInvalidParamsError(fmt.Sprintf("invalid argument %d: %v", i, err))
+		}
+
+		if err := json.Unmarshal(raw, inputs[i]); err != nil {
+			return NewInvalidParamsError(fmt.Sprintf("invalid argument %d: %s", i, describeDecodeError(err)))
+		}
+	}
+
+	return nil
+}
+
+// validateParam checks the addresses and the hashes of the raw parameter, through the fields and the
+// elements of its type, as their decoding doesn't check their hex format and length.
+// The types decoding themselves otherwise are trusted to validate their input
+func validateParam(raw json.RawMessage, typ reflect.Type, path string) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if isNullParam(raw) {
+		return nil
+	}
+
+	withPath := func(err error) error {
+		if path == "" {
+			return err
+		}
+
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch {
+	case typ == addressType || typ == hashType:
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return withPath(errors.New("hex string expected"))
+		}
+
+		if _, err := parseFixedData(str, typ.Len()); err != nil {
+			return withPath(err)
+		}
+
+	case reflect.PtrTo(typ).Implements(jsonUnmarshalerType), reflect.PtrTo(typ).Implements(textUnmarshalerType):
+		return nil
+
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			// the decoding reports the mismatch
+			return nil
+		}
+
+		for i, elem := range elems {
+			if err := validateParam(elem, typ.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case typ.Kind() == reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil
+		}
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+
+			name := jsonFieldName(field)
+			if name == "" {
+				continue
+			}
+
+			for key, value := range fields {
+				// the decoding matches the keys case-insensitively
+				if !strings.EqualFold(key, name) {
+					continue
+				}
+
+				fieldPath := key
+				if path != "" {
+					fieldPath = path + "." + key
+				}
+
+				if err := validateParam(value, field.Type, fieldPath); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonFieldName returns the key of the struct field in the JSON objects, empty if it isn't decoded
+func jsonFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+func isNullParam(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// describeDecodeError returns the reason of a decoding error without the internals of the json package
+func describeDecodeError(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err.Error()
+	}
+
+	reason := fmt.Sprintf("%s expected, got %s", describeType(typeErr.Type), typeErr.Value)
+	if typeErr.Field != "" {
+		return typeErr.Field + ": " + reason
+	}
+
+	return reason
+}
+
+// describeType returns the name of the type in the terms of the JSON-RPC API
+func describeType(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ {
+	case reflect.TypeOf(argUint64(0)), reflect.TypeOf(argBig{}):
+		return "hex number"
+	case reflect.TypeOf(argBytes{}):
+		return "hex data"
+	case reflect.TypeOf(BlockNumber(0)):
+		return "block number"
+	case addressType:
+		return "address"
+	case hashType:
+		return "hash"
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return typ.Kind().String()
+	}
+}
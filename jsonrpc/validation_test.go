@@ -0,0 +1,124 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHex(t *testing.T) {
+	t.Parallel()
+
+	num, err := parseQuantity("0x1f")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x1f), num)
+
+	_, err = parseQuantity("1f")
+	assert.ErrorIs(t, err, errMissingHexPrefix)
+
+	_, err = parseQuantity("0x")
+	assert.ErrorIs(t, err, errEmptyHexNumber)
+
+	_, err = parseQuantity("0xz")
+	assert.ErrorIs(t, err, errInvalidHex)
+
+	_, err = parseQuantity("0x10000000000000000")
+	assert.ErrorIs(t, err, errUint64Range)
+
+	bigNum, err := parseBigQuantity("0x10000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 64), bigNum)
+
+	_, err = parseBigQuantity("0x-1")
+	assert.ErrorIs(t, err, errInvalidHex)
+
+	_, err = parseBigQuantity("0x1" + strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, errUint256Range)
+
+	data, err := parseData("0x")
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+
+	_, err = parseData("0x123")
+	assert.ErrorIs(t, err, errOddHexLength)
+
+	_, err = parseFixedData("0x1234", types.AddressLength)
+	assert.EqualError(t, err, "hex string of 2 bytes, want 20")
+}
+
+func TestDecodeParams(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr   types.Address
+		number *BlockNumber
+		args   txnArgs
+	)
+
+	inputs := []interface{}{&addr, &number}
+
+	cases := []struct {
+		params string
+		err    string
+	}{
+		{
+			`["` + addr1.String() + `", "latest"]`,
+			"",
+		},
+		{
+			// the trailing parameters are optional
+			`["` + addr1.String() + `"]`,
+			"",
+		},
+		{
+			`{"address": "` + addr1.String() + `"}`,
+			"params must be an array of arguments",
+		},
+		{
+			`["` + addr1.String() + `", "latest", true]`,
+			"too many arguments, want at most 2",
+		},
+		{
+			`["0x1234"]`,
+			"invalid argument 0: hex string of 2 bytes, want 20",
+		},
+		{
+			`[1]`,
+			"invalid argument 0: hex string expected",
+		},
+		{
+			`["` + addr1.String() + `", "10"]`,
+			`invalid argument 1: invalid block number "10", want a hex number or one of latest, earliest, ` +
+				`pending, finalized, safe: hex string without 0x prefix`,
+		},
+		{
+			`["` + addr1.String() + `", "0x8000000000000000"]`,
+			"invalid argument 1: block number 0x8000000000000000 out of range",
+		},
+	}
+
+	for _, c := range cases {
+		err := decodeParams([]byte(c.params), inputs)
+
+		if c.err == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.EqualError(t, err, c.err)
+		}
+	}
+
+	assert.Equal(t, addr1, addr)
+
+	// the fields of the objects are checked as well
+	err := decodeParams([]byte(`[{"to": "0x01", "gas": "0x10"}]`), []interface{}{&args})
+	assert.EqualError(t, err, "invalid argument 0: to: hex string of 1 bytes, want 20")
+
+	err = decodeParams([]byte(`[{"gas": 16}]`), []interface{}{&args})
+	assert.EqualError(t, err, "invalid argument 0: gas: hex number expected, got number")
+
+	err = decodeParams([]byte(`[{"gas": "16"}]`), []interface{}{&args})
+	assert.EqualError(t, err, "invalid argument 0: hex string without 0x prefix")
+}
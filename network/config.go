@@ -18,6 +18,7 @@ type Config struct {
 	MaxPeers         int64                  // the maximum number of peer connections
 	MaxInboundPeers  int64                  // the maximum number of inbound peer connections
 	MaxOutboundPeers int64                  // the maximum number of outbound peer connections
+	TargetPeers      int64                  // the number of peer connections maintained, MaxPeers if unset
 	Chain            *chain.Chain           // the reference to the chain configuration
 	SecretsManager   secrets.SecretsManager // the secrets manager used for key storage
 	Metrics          *Metrics               // the metrics reporting reference
@@ -0,0 +1,241 @@
+package network
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+const (
+	// connManagerInterval is the interval between two connection maintenance rounds
+	connManagerInterval = 30 * time.Second
+
+	// originIPv4Bits and originIPv6Bits are the prefix lengths of the subnets
+	// the peers are grouped by, as peers of the same subnet are likely run by the same operator
+	originIPv4Bits = 16
+	originIPv6Bits = 32
+)
+
+// connCandidate is a peer considered by the connection manager for dialing or pruning
+type connCandidate struct {
+	id      peer.ID
+	origin  string
+	inbound bool
+	streams int
+}
+
+// runConnManager keeps the peer count at the target. It dials the discovered peers
+// while the count is below the target, preferring the origins with the fewest peers,
+// and prunes the least valuable connections while the count is above it
+func (s *Server) runConnManager() {
+	ticker := time.NewTicker(connManagerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+
+		s.maintainConnections()
+	}
+}
+
+// targetPeers returns the number of peer connections the connection manager maintains
+func (s *Server) targetPeers() int64 {
+	if s.config.TargetPeers > 0 && s.config.TargetPeers < s.config.MaxPeers {
+		return s.config.TargetPeers
+	}
+
+	return s.config.MaxPeers
+}
+
+// maintainConnections runs a connection maintenance round
+func (s *Server) maintainConnections() {
+	connected := s.connectedCandidates()
+	origins := countOrigins(connected)
+	target := s.targetPeers()
+	count := int64(len(connected))
+
+	switch {
+	case count < target:
+		if !s.connectionCounts.HasFreeOutboundConn() {
+			return
+		}
+
+		for _, peerID := range selectDialCandidates(s.dialCandidates(), origins, int(target-count)) {
+			info := s.host.Peerstore().PeerInfo(peerID)
+			s.addToDialQueue(&info, common.PriorityRandomDial)
+		}
+	case count > target:
+		for _, peerID := range selectPruneCandidates(connected, origins, int(count-target)) {
+			s.DisconnectFromPeer(peerID, "pruning excess connection")
+		}
+	}
+}
+
+// connectedCandidates returns the connected peers which may be pruned.
+// The bootnodes and the temporary connections are left out
+func (s *Server) connectedCandidates() []*connCandidate {
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	candidates := make([]*connCandidate, 0, len(s.peers))
+
+	for peerID, connectionInfo := range s.peers {
+		if s.bootnodes.isBootnode(peerID) {
+			continue
+		}
+
+		if _, temporary := s.temporaryDials.Load(peerID); temporary {
+			continue
+		}
+
+		candidates = append(candidates, &connCandidate{
+			id:      peerID,
+			origin:  s.connOrigin(peerID),
+			inbound: connectionInfo.connDirections[network.DirInbound],
+			streams: len(connectionInfo.protocolStreams),
+		})
+	}
+
+	return candidates
+}
+
+// dialCandidates returns the discovered peers which aren't connected
+func (s *Server) dialCandidates() []*connCandidate {
+	peerIDs := s.host.Peerstore().PeersWithAddrs()
+	candidates := make([]*connCandidate, 0, len(peerIDs))
+
+	for _, peerID := range peerIDs {
+		if peerID == s.host.ID() || s.IsConnected(peerID) {
+			continue
+		}
+
+		addrs := s.host.Peerstore().Addrs(peerID)
+		if len(addrs) == 0 {
+			continue
+		}
+
+		candidates = append(candidates, &connCandidate{
+			id:     peerID,
+			origin: addrOrigin(addrs[0]),
+		})
+	}
+
+	return candidates
+}
+
+// connOrigin returns the origin of the connection to the peer
+func (s *Server) connOrigin(peerID peer.ID) string {
+	conns := s.host.Network().ConnsToPeer(peerID)
+	if len(conns) == 0 {
+		return ""
+	}
+
+	return addrOrigin(conns[0].RemoteMultiaddr())
+}
+
+// addrOrigin returns the subnet of the address, or the address itself if it has no IP
+func addrOrigin(addr multiaddr.Multiaddr) string {
+	ip, err := manet.ToIP(addr)
+	if err != nil {
+		return addr.String()
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(originIPv4Bits, 8*net.IPv4len)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(originIPv6Bits, 8*net.IPv6len)).String()
+}
+
+// countOrigins returns the number of peers of each origin
+func countOrigins(peers []*connCandidate) map[string]int {
+	origins := make(map[string]int)
+
+	for _, p := range peers {
+		origins[p.origin]++
+	}
+
+	return origins
+}
+
+// selectDialCandidates picks up to n candidates to dial, one at a time
+// from the origin with the fewest connected and picked peers
+func selectDialCandidates(candidates []*connCandidate, origins map[string]int, n int) []peer.ID {
+	counts := make(map[string]int, len(origins))
+	for origin, count := range origins {
+		counts[origin] = count
+	}
+
+	remaining := make([]*connCandidate, len(candidates))
+	copy(remaining, candidates)
+
+	sort.Slice(remaining, func(i, j int) bool {
+		return remaining[i].id < remaining[j].id
+	})
+
+	selected := make([]peer.ID, 0, n)
+
+	for len(selected) < n && len(remaining) > 0 {
+		best := 0
+
+		for i, candidate := range remaining {
+			if counts[candidate.origin] < counts[remaining[best].origin] {
+				best = i
+			}
+		}
+
+		selected = append(selected, remaining[best].id)
+		counts[remaining[best].origin]++
+
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+
+	return selected
+}
+
+// selectPruneCandidates picks up to n connected peers to prune, the least valuable first.
+// The peers of the most represented origins go first, then the inbound ones,
+// then the ones with the fewest protocol streams
+func selectPruneCandidates(connected []*connCandidate, origins map[string]int, n int) []peer.ID {
+	sorted := make([]*connCandidate, len(connected))
+	copy(sorted, connected)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+
+		if origins[a.origin] != origins[b.origin] {
+			return origins[a.origin] > origins[b.origin]
+		}
+
+		if a.inbound != b.inbound {
+			return a.inbound
+		}
+
+		if a.streams != b.streams {
+			return a.streams < b.streams
+		}
+
+		return a.id < b.id
+	})
+
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+
+	selected := make([]peer.ID, n)
+	for i := 0; i < n; i++ {
+		selected[i] = sorted[i].id
+	}
+
+	return selected
+}
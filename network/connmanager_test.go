@@ -0,0 +1,113 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddrOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr   string
+		origin string
+	}{
+		{"/ip4/10.1.2.3/tcp/1478", "10.1.0.0"},
+		{"/ip4/10.1.200.7/tcp/1479", "10.1.0.0"},
+		{"/ip4/10.2.2.3/tcp/1478", "10.2.0.0"},
+		{"/ip6/2001:db8:1:2::1/tcp/1478", "2001:db8::"},
+		{"/dns4/example.com/tcp/1478", "/dns4/example.com/tcp/1478"},
+	}
+
+	for _, test := range tests {
+		addr, err := multiaddr.NewMultiaddr(test.addr)
+		assert.NoError(t, err)
+
+		assert.Equal(t, test.origin, addrOrigin(addr), test.addr)
+	}
+}
+
+func TestSelectDialCandidates(t *testing.T) {
+	t.Parallel()
+
+	candidates := []*connCandidate{
+		{id: peer.ID("A"), origin: "10.1.0.0"},
+		{id: peer.ID("B"), origin: "10.1.0.0"},
+		{id: peer.ID("C"), origin: "10.2.0.0"},
+		{id: peer.ID("D"), origin: "10.3.0.0"},
+		{id: peer.ID("E"), origin: "10.3.0.0"},
+	}
+
+	// two peers are already connected from 10.3.0.0
+	origins := map[string]int{"10.3.0.0": 2}
+
+	assert.Equal(
+		t,
+		[]peer.ID{peer.ID("A"), peer.ID("C"), peer.ID("B")},
+		selectDialCandidates(candidates, origins, 3),
+	)
+
+	// the connected origins are left untouched
+	assert.Equal(t, map[string]int{"10.3.0.0": 2}, origins)
+
+	assert.Len(t, selectDialCandidates(candidates, origins, 10), len(candidates))
+	assert.Empty(t, selectDialCandidates(nil, origins, 3))
+}
+
+func TestSelectPruneCandidates(t *testing.T) {
+	t.Parallel()
+
+	connected := []*connCandidate{
+		{id: peer.ID("A"), origin: "10.1.0.0", streams: 1},
+		{id: peer.ID("B"), origin: "10.1.0.0", inbound: true, streams: 1},
+		{id: peer.ID("C"), origin: "10.1.0.0", streams: 0},
+		{id: peer.ID("D"), origin: "10.2.0.0", inbound: true},
+		{id: peer.ID("E"), origin: "10.3.0.0"},
+	}
+
+	origins := countOrigins(connected)
+
+	// the most represented origin goes first, inbound then without streams
+	assert.Equal(
+		t,
+		[]peer.ID{peer.ID("B"), peer.ID("C"), peer.ID("A"), peer.ID("D")},
+		selectPruneCandidates(connected, origins, 4),
+	)
+
+	assert.Len(t, selectPruneCandidates(connected, origins, 10), len(connected))
+	assert.Empty(t, selectPruneCandidates(connected, origins, 0))
+}
+
+func TestServer_TargetPeers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		target   int64
+		expected int64
+	}{
+		{"unset target", 0, 40},
+		{"target below max peers", 10, 10},
+		{"target above max peers", 50, 40},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &Server{
+				config: &Config{
+					MaxPeers:    40,
+					TargetPeers: test.target,
+				},
+			}
+
+			assert.Equal(t, test.expected, server.targetPeers())
+		})
+	}
+}
@@ -43,6 +43,11 @@ func (t *Topic) Publish(obj proto.Message) error {
 	return t.topic.Publish(context.Background(), data)
 }
 
+// ListPeers returns the connected peers subscribed to the topic
+func (t *Topic) ListPeers() []peer.ID {
+	return t.topic.ListPeers()
+}
+
 func (t *Topic) Subscribe(handler func(obj interface{}, from peer.ID)) error {
 	sub, err := t.topic.Subscribe(pubsub.WithBufferSize(subscribeOutputBufferSize))
 	if err != nil {
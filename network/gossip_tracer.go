@@ -0,0 +1,307 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+const (
+	// gossipLatencySamples is the number of recent delivery latencies kept for each topic
+	gossipLatencySamples = 256
+
+	// maxValidatingMessages bounds the messages tracked between their validation and their delivery
+	maxValidatingMessages = 4096
+)
+
+// GossipStats are the gossipsub mesh statistics of the node
+type GossipStats struct {
+	Topics []*TopicGossipStats
+
+	// message ids advertised (IHAVE) and requested (IWANT) through the gossip control messages
+	IHaveReceived uint64
+	IHaveSent     uint64
+	IWantReceived uint64
+	IWantSent     uint64
+}
+
+// TopicGossipStats are the gossipsub statistics of a topic
+type TopicGossipStats struct {
+	Topic      string
+	MeshPeers  uint64
+	Delivered  uint64
+	Duplicates uint64
+
+	// percentiles of the recent times from the validation of a message to its delivery
+	DeliveryP50 time.Duration
+	DeliveryP90 time.Duration
+	DeliveryMax time.Duration
+}
+
+// topicGossip is the state of a joined topic
+type topicGossip struct {
+	mesh       map[peer.ID]struct{}
+	delivered  uint64
+	duplicates uint64
+
+	// latencies is a ring of the recent delivery latencies, next being the index of the next sample
+	latencies []time.Duration
+	next      int
+}
+
+// gossipTracer traces the gossipsub events, to report the mesh health through the metrics and GossipStats
+type gossipTracer struct {
+	metrics *Metrics
+
+	lock   sync.Mutex
+	topics map[string]*topicGossip
+
+	ihaveReceived uint64
+	ihaveSent     uint64
+	iwantReceived uint64
+	iwantSent     uint64
+
+	// validatedAt are the validation times of the messages not delivered yet, by message id
+	validatedAt map[string]time.Time
+}
+
+func newGossipTracer(metrics *Metrics) *gossipTracer {
+	return &gossipTracer{
+		metrics:     metrics,
+		topics:      make(map[string]*topicGossip),
+		validatedAt: make(map[string]time.Time),
+	}
+}
+
+// topic returns the state of the topic, created if the topic wasn't joined yet
+func (t *gossipTracer) topic(name string) *topicGossip {
+	topic, ok := t.topics[name]
+	if !ok {
+		topic = &topicGossip{
+			mesh:      make(map[peer.ID]struct{}),
+			latencies: make([]time.Duration, 0, gossipLatencySamples),
+		}
+		t.topics[name] = topic
+	}
+
+	return topic
+}
+
+func (t *gossipTracer) updateMeshPeers(name string, topic *topicGossip) {
+	t.metrics.GossipMeshPeers.With("topic", name).Set(float64(len(topic.mesh)))
+}
+
+func (t *gossipTracer) AddPeer(peer.ID, protocol.ID) {}
+
+// RemovePeer removes the peer from the meshes
+func (t *gossipTracer) RemovePeer(p peer.ID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for name, topic := range t.topics {
+		if _, ok := topic.mesh[p]; ok {
+			delete(topic.mesh, p)
+			t.updateMeshPeers(name, topic)
+		}
+	}
+}
+
+func (t *gossipTracer) Join(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.updateMeshPeers(name, t.topic(name))
+}
+
+func (t *gossipTracer) Leave(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.topics, name)
+	t.metrics.GossipMeshPeers.With("topic", name).Set(0)
+}
+
+func (t *gossipTracer) Graft(p peer.ID, name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	topic := t.topic(name)
+	topic.mesh[p] = struct{}{}
+	t.updateMeshPeers(name, topic)
+}
+
+func (t *gossipTracer) Prune(p peer.ID, name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	topic := t.topic(name)
+	delete(topic.mesh, p)
+	t.updateMeshPeers(name, topic)
+}
+
+func (t *gossipTracer) ValidateMessage(msg *pubsub.Message) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// the messages neither delivered nor rejected are forgotten, rather than piling up
+	if len(t.validatedAt) >= maxValidatingMessages {
+		t.validatedAt = make(map[string]time.Time)
+	}
+
+	t.validatedAt[msg.ID] = time.Now()
+}
+
+func (t *gossipTracer) DeliverMessage(msg *pubsub.Message) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	name := msg.GetTopic()
+	topic := t.topic(name)
+	topic.delivered++
+
+	t.metrics.GossipDeliveredMessages.With("topic", name).Add(1)
+
+	validatedAt, ok := t.validatedAt[msg.ID]
+	if !ok {
+		return
+	}
+
+	delete(t.validatedAt, msg.ID)
+
+	latency := time.Since(validatedAt)
+
+	if len(topic.latencies) < gossipLatencySamples {
+		topic.latencies = append(topic.latencies, latency)
+	} else {
+		topic.latencies[topic.next] = latency
+	}
+
+	topic.next = (topic.next + 1) % gossipLatencySamples
+
+	t.metrics.GossipDeliveryLatency.With("topic", name).Observe(latency.Seconds())
+}
+
+func (t *gossipTracer) RejectMessage(msg *pubsub.Message, _ string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.validatedAt, msg.ID)
+}
+
+func (t *gossipTracer) DuplicateMessage(msg *pubsub.Message) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	name := msg.GetTopic()
+	t.topic(name).duplicates++
+
+	t.metrics.GossipDuplicateMessages.With("topic", name).Add(1)
+}
+
+func (t *gossipTracer) ThrottlePeer(peer.ID) {}
+
+func (t *gossipTracer) RecvRPC(rpc *pubsub.RPC) {
+	ihave, iwant := countControlMessageIDs(rpc)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.ihaveReceived += ihave
+	t.iwantReceived += iwant
+
+	t.metrics.GossipIHave.With("direction", "received").Add(float64(ihave))
+	t.metrics.GossipIWant.With("direction", "received").Add(float64(iwant))
+}
+
+func (t *gossipTracer) SendRPC(rpc *pubsub.RPC, _ peer.ID) {
+	ihave, iwant := countControlMessageIDs(rpc)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.ihaveSent += ihave
+	t.iwantSent += iwant
+
+	t.metrics.GossipIHave.With("direction", "sent").Add(float64(ihave))
+	t.metrics.GossipIWant.With("direction", "sent").Add(float64(iwant))
+}
+
+func (t *gossipTracer) DropRPC(*pubsub.RPC, peer.ID) {}
+
+func (t *gossipTracer) UndeliverableMessage(*pubsub.Message) {}
+
+// countControlMessageIDs returns the number of message ids of the IHAVE and the IWANT control messages of the RPC
+func countControlMessageIDs(rpc *pubsub.RPC) (ihave, iwant uint64) {
+	control := rpc.GetControl()
+	if control == nil {
+		return 0, 0
+	}
+
+	for _, msg := range control.GetIhave() {
+		ihave += uint64(len(msg.GetMessageIDs()))
+	}
+
+	for _, msg := range control.GetIwant() {
+		iwant += uint64(len(msg.GetMessageIDs()))
+	}
+
+	return ihave, iwant
+}
+
+// stats returns the statistics of the joined topics, by topic name
+func (t *gossipTracer) stats() *GossipStats {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats := &GossipStats{
+		Topics:        make([]*TopicGossipStats, 0, len(t.topics)),
+		IHaveReceived: t.ihaveReceived,
+		IHaveSent:     t.ihaveSent,
+		IWantReceived: t.iwantReceived,
+		IWantSent:     t.iwantSent,
+	}
+
+	for name, topic := range t.topics {
+		topicStats := &TopicGossipStats{
+			Topic:      name,
+			MeshPeers:  uint64(len(topic.mesh)),
+			Delivered:  topic.delivered,
+			Duplicates: topic.duplicates,
+		}
+
+		if len(topic.latencies) > 0 {
+			sorted := make([]time.Duration, len(topic.latencies))
+			copy(sorted, topic.latencies)
+
+			sort.Slice(sorted, func(i, j int) bool {
+				return sorted[i] < sorted[j]
+			})
+
+			// nearest-rank percentile
+			percentile := func(p int) time.Duration {
+				return sorted[(p*len(sorted)+99)/100-1]
+			}
+
+			topicStats.DeliveryP50 = percentile(50)
+			topicStats.DeliveryP90 = percentile(90)
+			topicStats.DeliveryMax = sorted[len(sorted)-1]
+		}
+
+		stats.Topics = append(stats.Topics, topicStats)
+	}
+
+	sort.Slice(stats.Topics, func(i, j int) bool {
+		return stats.Topics[i].Topic < stats.Topics[j].Topic
+	})
+
+	return stats
+}
+
+// GossipStats returns the gossipsub mesh statistics of the node
+func (s *Server) GossipStats() *GossipStats {
+	return s.gossipTracer.stats()
+}
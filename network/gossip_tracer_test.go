@@ -0,0 +1,74 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGossipMessage(id, topic string) *pubsub.Message {
+	return &pubsub.Message{
+		Message: &pb.Message{Topic: &topic},
+		ID:      id,
+	}
+}
+
+func TestGossipTracer_Stats(t *testing.T) {
+	t.Parallel()
+
+	tracer := newGossipTracer(NilMetrics())
+	peerA, peerB := peer.ID("A"), peer.ID("B")
+
+	tracer.Join("txs")
+	tracer.Graft(peerA, "txs")
+	tracer.Graft(peerB, "txs")
+	tracer.Graft(peerA, "blocks")
+	tracer.Prune(peerB, "txs")
+
+	tracer.ValidateMessage(newTestGossipMessage("1", "txs"))
+	tracer.DeliverMessage(newTestGossipMessage("1", "txs"))
+	tracer.DuplicateMessage(newTestGossipMessage("1", "txs"))
+
+	// the rejected messages are not delivered
+	tracer.ValidateMessage(newTestGossipMessage("2", "txs"))
+	tracer.RejectMessage(newTestGossipMessage("2", "txs"), pubsub.RejectValidationFailed)
+	assert.Empty(t, tracer.validatedAt)
+
+	tracer.RecvRPC(&pubsub.RPC{RPC: pb.RPC{Control: &pb.ControlMessage{
+		Ihave: []*pb.ControlIHave{{MessageIDs: []string{"3", "4"}}},
+		Iwant: []*pb.ControlIWant{{MessageIDs: []string{"5"}}},
+	}}})
+	tracer.SendRPC(&pubsub.RPC{RPC: pb.RPC{Control: &pb.ControlMessage{
+		Iwant: []*pb.ControlIWant{{MessageIDs: []string{"3"}}},
+	}}}, peerA)
+
+	stats := tracer.stats()
+
+	assert.Equal(t, uint64(2), stats.IHaveReceived)
+	assert.Equal(t, uint64(0), stats.IHaveSent)
+	assert.Equal(t, uint64(1), stats.IWantReceived)
+	assert.Equal(t, uint64(1), stats.IWantSent)
+
+	assert.Len(t, stats.Topics, 2)
+	assert.Equal(t, "blocks", stats.Topics[0].Topic)
+	assert.Equal(t, uint64(1), stats.Topics[0].MeshPeers)
+
+	txs := stats.Topics[1]
+	assert.Equal(t, "txs", txs.Topic)
+	assert.Equal(t, uint64(1), txs.MeshPeers)
+	assert.Equal(t, uint64(1), txs.Delivered)
+	assert.Equal(t, uint64(1), txs.Duplicates)
+	assert.Equal(t, txs.DeliveryP50, txs.DeliveryMax)
+
+	// the peers leaving are removed from the meshes
+	tracer.RemovePeer(peerA)
+	tracer.Leave("blocks")
+
+	stats = tracer.stats()
+
+	assert.Len(t, stats.Topics, 1)
+	assert.Equal(t, uint64(0), stats.Topics[0].MeshPeers)
+}
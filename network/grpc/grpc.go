@@ -25,9 +25,12 @@ type GrpcStream struct {
 
 func NewGrpcStream() *GrpcStream {
 	return &GrpcStream{
-		ctx:        context.Background(),
-		streamCh:   make(chan network.Stream),
-		grpcServer: grpc.NewServer(grpc.UnaryInterceptor(interceptor)),
+		ctx:      context.Background(),
+		streamCh: make(chan network.Stream),
+		grpcServer: grpc.NewServer(
+			grpc.UnaryInterceptor(interceptor),
+			grpc.StreamInterceptor(streamInterceptor),
+		),
 	}
 }
 
@@ -44,6 +47,33 @@ func interceptor(
 	_ *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
+	peerCtx, err := wrapPeerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(peerCtx, req)
+}
+
+// streamInterceptor is the middleware function that wraps
+// gRPC peer data of server streams to custom Polygon Edge structures
+func streamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	peerCtx, err := wrapPeerContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: stream, ctx: peerCtx})
+}
+
+// wrapPeerContext wraps the extracted PeerID and the context
+// so the stream handler has access to the PeerID
+func wrapPeerContext(ctx context.Context) (*Context, error) {
 	// Grab the peer info from the connection
 	contextPeer, ok := grpcPeer.FromContext(ctx)
 	if !ok {
@@ -57,15 +87,21 @@ func interceptor(
 		return nil, errors.New("invalid type assertion")
 	}
 
-	// Wrap the extracted PeerID and the context
-	// so the stream handler has access to the PeerID
-	return handler(
-		&Context{
-			Context: ctx,
-			PeerID:  addr.id,
-		},
-		req,
-	)
+	return &Context{
+		Context: ctx,
+		PeerID:  addr.id,
+	}, nil
+}
+
+// serverStream is a server stream with the context wrapping the PeerID
+type serverStream struct {
+	grpc.ServerStream
+	ctx *Context
+}
+
+// Context returns the context of the stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
 }
 
 func (g *GrpcStream) Client(stream network.Stream) *grpc.ClientConn {
@@ -23,6 +23,24 @@ type Metrics struct {
 
 	// Number of pending inbound connections
 	PendingInboundConnectionsCount metrics.Gauge
+
+	// Number of peers of the gossipsub mesh, by topic
+	GossipMeshPeers metrics.Gauge
+
+	// Number of message ids of the IHAVE gossip, by direction
+	GossipIHave metrics.Counter
+
+	// Number of message ids of the IWANT gossip, by direction
+	GossipIWant metrics.Counter
+
+	// Number of gossip messages delivered to the subscribers, by topic
+	GossipDeliveredMessages metrics.Counter
+
+	// Number of duplicate gossip messages dropped, by topic
+	GossipDuplicateMessages metrics.Counter
+
+	// Time from the validation of a gossip message to its delivery in seconds, by topic
+	GossipDeliveryLatency metrics.Histogram
 }
 
 // GetPrometheusMetrics return the network metrics instance
@@ -33,6 +51,11 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 		labels = append(labels, labelsWithValues[i])
 	}
 
+	// withLabel returns the labels with the one set by the gossip tracer
+	withLabel := func(label string) []string {
+		return append(append([]string{}, labels...), label)
+	}
+
 	return &Metrics{
 		TotalPeerCount: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
@@ -68,6 +91,48 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "pending_inbound_connections_count",
 			Help:      "Number of pending inbound connections",
 		}, labels).With(labelsWithValues...),
+
+		GossipMeshPeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_mesh_peers",
+			Help:      "Number of peers of the gossipsub mesh, by topic",
+		}, withLabel("topic")).With(labelsWithValues...),
+
+		GossipIHave: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_ihave_message_ids",
+			Help:      "Number of message ids of the IHAVE gossip, by direction",
+		}, withLabel("direction")).With(labelsWithValues...),
+
+		GossipIWant: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_iwant_message_ids",
+			Help:      "Number of message ids of the IWANT gossip, by direction",
+		}, withLabel("direction")).With(labelsWithValues...),
+
+		GossipDeliveredMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_delivered_messages",
+			Help:      "Number of gossip messages delivered to the subscribers, by topic",
+		}, withLabel("topic")).With(labelsWithValues...),
+
+		GossipDuplicateMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_duplicate_messages",
+			Help:      "Number of duplicate gossip messages dropped, by topic",
+		}, withLabel("topic")).With(labelsWithValues...),
+
+		GossipDeliveryLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "network",
+			Name:      "gossip_delivery_latency",
+			Help:      "Time from the validation of a gossip message to its delivery in seconds, by topic",
+		}, withLabel("topic")).With(labelsWithValues...),
 	}
 }
 
@@ -79,5 +144,11 @@ func NilMetrics() *Metrics {
 		InboundConnectionsCount:         discard.NewGauge(),
 		PendingOutboundConnectionsCount: discard.NewGauge(),
 		PendingInboundConnectionsCount:  discard.NewGauge(),
+		GossipMeshPeers:                 discard.NewGauge(),
+		GossipIHave:                     discard.NewCounter(),
+		GossipIWant:                     discard.NewCounter(),
+		GossipDeliveredMessages:         discard.NewCounter(),
+		GossipDuplicateMessages:         discard.NewCounter(),
+		GossipDeliveryLatency:           discard.NewHistogram(),
 	}
 }
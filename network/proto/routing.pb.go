@@ -0,0 +1,342 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.4
+// source: network/proto/routing.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AddProviderReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *AddProviderReq) Reset() {
+	*x = AddProviderReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_proto_routing_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProviderReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProviderReq) ProtoMessage() {}
+
+func (x *AddProviderReq) ProtoReflect() protoreflect.Message {
+	mi := &file_network_proto_routing_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProviderReq.ProtoReflect.Descriptor instead.
+func (*AddProviderReq) Descriptor() ([]byte, []int) {
+	return file_network_proto_routing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddProviderReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type AddProviderResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddProviderResp) Reset() {
+	*x = AddProviderResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_proto_routing_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProviderResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProviderResp) ProtoMessage() {}
+
+func (x *AddProviderResp) ProtoReflect() protoreflect.Message {
+	mi := &file_network_proto_routing_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProviderResp.ProtoReflect.Descriptor instead.
+func (*AddProviderResp) Descriptor() ([]byte, []int) {
+	return file_network_proto_routing_proto_rawDescGZIP(), []int{1}
+}
+
+type GetProvidersReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *GetProvidersReq) Reset() {
+	*x = GetProvidersReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_proto_routing_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvidersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvidersReq) ProtoMessage() {}
+
+func (x *GetProvidersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_network_proto_routing_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvidersReq.ProtoReflect.Descriptor instead.
+func (*GetProvidersReq) Descriptor() ([]byte, []int) {
+	return file_network_proto_routing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProvidersReq) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetProvidersReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetProvidersResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Providers []string `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+}
+
+func (x *GetProvidersResp) Reset() {
+	*x = GetProvidersResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_proto_routing_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvidersResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvidersResp) ProtoMessage() {}
+
+func (x *GetProvidersResp) ProtoReflect() protoreflect.Message {
+	mi := &file_network_proto_routing_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvidersResp.ProtoReflect.Descriptor instead.
+func (*GetProvidersResp) Descriptor() ([]byte, []int) {
+	return file_network_proto_routing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProvidersResp) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+var File_network_proto_routing_proto protoreflect.FileDescriptor
+
+var file_network_proto_routing_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76,
+	0x31, 0x22, 0x22, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x11, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x22, 0x39, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x30, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x73, 0x32, 0x83, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x36, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x39, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73,
+	0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x42, 0x10, 0x5a, 0x0e, 0x2f,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_network_proto_routing_proto_rawDescOnce sync.Once
+	file_network_proto_routing_proto_rawDescData = file_network_proto_routing_proto_rawDesc
+)
+
+func file_network_proto_routing_proto_rawDescGZIP() []byte {
+	file_network_proto_routing_proto_rawDescOnce.Do(func() {
+		file_network_proto_routing_proto_rawDescData = protoimpl.X.CompressGZIP(file_network_proto_routing_proto_rawDescData)
+	})
+	return file_network_proto_routing_proto_rawDescData
+}
+
+var file_network_proto_routing_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_network_proto_routing_proto_goTypes = []interface{}{
+	(*AddProviderReq)(nil),   // 0: v1.AddProviderReq
+	(*AddProviderResp)(nil),  // 1: v1.AddProviderResp
+	(*GetProvidersReq)(nil),  // 2: v1.GetProvidersReq
+	(*GetProvidersResp)(nil), // 3: v1.GetProvidersResp
+}
+var file_network_proto_routing_proto_depIdxs = []int32{
+	0, // 0: v1.ContentRouting.AddProvider:input_type -> v1.AddProviderReq
+	2, // 1: v1.ContentRouting.GetProviders:input_type -> v1.GetProvidersReq
+	1, // 2: v1.ContentRouting.AddProvider:output_type -> v1.AddProviderResp
+	3, // 3: v1.ContentRouting.GetProviders:output_type -> v1.GetProvidersResp
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_network_proto_routing_proto_init() }
+func file_network_proto_routing_proto_init() {
+	if File_network_proto_routing_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_network_proto_routing_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProviderReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_proto_routing_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProviderResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_proto_routing_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvidersReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_proto_routing_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvidersResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_network_proto_routing_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_network_proto_routing_proto_goTypes,
+		DependencyIndexes: file_network_proto_routing_proto_depIdxs,
+		MessageInfos:      file_network_proto_routing_proto_msgTypes,
+	}.Build()
+	File_network_proto_routing_proto = out.File
+	file_network_proto_routing_proto_rawDesc = nil
+	file_network_proto_routing_proto_goTypes = nil
+	file_network_proto_routing_proto_depIdxs = nil
+}
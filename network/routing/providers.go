@@ -0,0 +1,143 @@
+package routing
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// providerRecordTTL is the time a provider record is kept for after its announcement,
+	// the providers announce their content again before it expires
+	providerRecordTTL = 30 * time.Minute
+
+	// maxProvidersPerKey is the maximum number of providers kept for a content key
+	maxProvidersPerKey = 20
+
+	// maxProviderKeys is the maximum number of content keys the provider records are kept for
+	maxProviderKeys = 16384
+)
+
+var errProviderStoreFull = errors.New("provider store is full")
+
+// providerStore holds the provider records announced by the peers, by content key.
+// The records expire, so that the providers which stopped serving the content are forgotten
+type providerStore struct {
+	sync.Mutex
+
+	// records are the expiry times of the providers, by content key
+	records map[string]map[peer.ID]time.Time
+
+	now func() time.Time
+}
+
+func newProviderStore() *providerStore {
+	return &providerStore{
+		records: make(map[string]map[peer.ID]time.Time),
+		now:     time.Now,
+	}
+}
+
+// add records the peer as a provider of the content key, or renews its record.
+// The record expiring first is replaced when the key has the maximum number of providers
+func (s *providerStore) add(key string, provider peer.ID) error {
+	s.Lock()
+	defer s.Unlock()
+
+	providers, ok := s.records[key]
+	if !ok {
+		if len(s.records) >= maxProviderKeys {
+			s.prune()
+		}
+
+		if len(s.records) >= maxProviderKeys {
+			return errProviderStoreFull
+		}
+
+		providers = make(map[peer.ID]time.Time)
+		s.records[key] = providers
+	}
+
+	if _, ok := providers[provider]; !ok && len(providers) >= maxProvidersPerKey {
+		var (
+			oldest       peer.ID
+			oldestExpiry time.Time
+		)
+
+		for id, expiry := range providers {
+			if oldest == "" || expiry.Before(oldestExpiry) {
+				oldest, oldestExpiry = id, expiry
+			}
+		}
+
+		delete(providers, oldest)
+	}
+
+	providers[provider] = s.now().Add(providerRecordTTL)
+
+	return nil
+}
+
+// get returns up to count providers of the content key, the most recently announced first
+func (s *providerStore) get(key string, count int) []peer.ID {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+
+	providers := make([]peer.ID, 0, len(s.records[key]))
+
+	for id, expiry := range s.records[key] {
+		if !expiry.After(now) {
+			delete(s.records[key], id)
+
+			continue
+		}
+
+		providers = append(providers, id)
+	}
+
+	if len(providers) == 0 {
+		delete(s.records, key)
+
+		return nil
+	}
+
+	sort.Slice(providers, func(i, j int) bool {
+		return s.records[key][providers[i]].After(s.records[key][providers[j]])
+	})
+
+	if len(providers) > count {
+		providers = providers[:count]
+	}
+
+	return providers
+}
+
+// prune drops the expired records, the caller holding the lock
+func (s *providerStore) prune() {
+	now := s.now()
+
+	for key, providers := range s.records {
+		for id, expiry := range providers {
+			if !expiry.After(now) {
+				delete(providers, id)
+			}
+		}
+
+		if len(providers) == 0 {
+			delete(s.records, key)
+		}
+	}
+}
+
+// pruneExpired drops the expired records
+func (s *providerStore) pruneExpired() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.prune()
+}
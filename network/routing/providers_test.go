@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderStore_AddGet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = newProviderStore()
+		now   = time.Unix(1000, 0)
+	)
+
+	store.now = func() time.Time {
+		return now
+	}
+
+	assert.NoError(t, store.add("key", "A"))
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, store.add("key", "B"))
+
+	// the most recently announced providers are returned first
+	assert.Equal(t, []peer.ID{"B", "A"}, store.get("key", maxProvidersPerKey))
+	assert.Equal(t, []peer.ID{"B"}, store.get("key", 1))
+	assert.Empty(t, store.get("other", maxProvidersPerKey))
+
+	// the renewed record of A doesn't expire with the first one
+	now = now.Add(time.Minute)
+	assert.NoError(t, store.add("key", "A"))
+
+	now = now.Add(providerRecordTTL - time.Minute)
+	assert.Equal(t, []peer.ID{"A"}, store.get("key", maxProvidersPerKey))
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, store.get("key", maxProvidersPerKey))
+	assert.NotContains(t, store.records, "key")
+}
+
+func TestProviderStore_Limits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = newProviderStore()
+		now   = time.Unix(1000, 0)
+	)
+
+	store.now = func() time.Time {
+		return now
+	}
+
+	// the record expiring first is replaced past the maximum number of providers of a key
+	for i := 0; i <= maxProvidersPerKey; i++ {
+		now = now.Add(time.Second)
+
+		assert.NoError(t, store.add("key", peer.ID(fmt.Sprintf("peer-%d", i))))
+	}
+
+	providers := store.get("key", 2*maxProvidersPerKey)
+	assert.Len(t, providers, maxProvidersPerKey)
+	assert.NotContains(t, providers, peer.ID("peer-0"))
+	assert.Equal(t, peer.ID(fmt.Sprintf("peer-%d", maxProvidersPerKey)), providers[0])
+
+	// past the maximum number of keys, the expired records are pruned to make room
+	for i := len(store.records); i < maxProviderKeys; i++ {
+		store.records[fmt.Sprintf("key-%d", i)] = map[peer.ID]time.Time{"A": now.Add(time.Second)}
+	}
+
+	assert.ErrorIs(t, store.add("new", "A"), errProviderStoreFull)
+
+	now = now.Add(2 * time.Second)
+	assert.NoError(t, store.add("new", "A"))
+	assert.Equal(t, []peer.ID{"A"}, store.get("new", 1))
+}
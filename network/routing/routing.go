@@ -0,0 +1,362 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	"github.com/0xPolygon/polygon-edge/network/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	kb "github.com/libp2p/go-libp2p-kbucket"
+)
+
+const (
+	// providerReplicas is the number of peers closest to a content key its providers are announced to
+	providerReplicas = 4
+
+	// providerQueryPeers is the number of peers closest to a content key queried for its providers
+	providerQueryPeers = 4
+
+	// providerRepublishInterval is the interval at which the provided content is announced again,
+	// within the TTL of the provider records
+	providerRepublishInterval = 10 * time.Minute
+
+	// routingRequestTimeout is the timeout of a content routing request to a peer
+	routingRequestTimeout = 10 * time.Second
+
+	// maxContentKeyLength is the maximum length of a content key
+	maxContentKeyLength = 256
+)
+
+var (
+	ErrNoRoutingPeers     = errors.New("no peer in the routing table")
+	ErrInvalidContentKey  = errors.New("invalid content key")
+	errProvideNotAccepted = errors.New("no peer accepted the provider record")
+)
+
+// SnapshotKey returns the content key of the state snapshot of the given state root
+func SnapshotKey(root types.Hash) string {
+	return "/snapshot/" + root.String()
+}
+
+// StateChunkKey returns the content key of a chunk of the state of the given state root
+func StateChunkKey(root types.Hash, chunk types.Hash) string {
+	return "/state-chunk/" + root.String() + "/" + chunk.String()
+}
+
+// networkingServer defines the base communication interface between
+// any networking server implementation and the ContentRoutingService
+type networkingServer interface {
+	// NewContentRoutingClient returns a content routing gRPC client connection
+	NewContentRoutingClient(peerID peer.ID) (proto.ContentRoutingClient, error)
+
+	// GetPeerInfo fetches the peer information from the server's peer store
+	GetPeerInfo(peerID peer.ID) *peer.AddrInfo
+}
+
+// ContentRoutingService advertises the content the node serves, such as the state snapshots and chunks,
+// and finds the peers serving a content. Like the provider records of a DHT, a provider is announced
+// to the peers of the routing table closest to the content key, which are the ones queried for it
+type ContentRoutingService struct {
+	proto.UnimplementedContentRoutingServer
+
+	baseServer   networkingServer // The interface towards the base networking server
+	logger       hclog.Logger     // The ContentRoutingService logger
+	routingTable *kb.RoutingTable // Kademlia 'k-bucket' routing table shared with the discovery
+	selfID       peer.ID          // The ID of the node
+
+	providers *providerStore // The provider records announced by the peers
+
+	provided     map[string]struct{} // The content keys provided by the node, announced again periodically
+	providedLock sync.Mutex
+
+	closeCh chan struct{} // Channel used for stopping the ContentRoutingService
+}
+
+// NewContentRoutingService creates a new instance of the content routing service
+func NewContentRoutingService(
+	server networkingServer,
+	routingTable *kb.RoutingTable,
+	selfID peer.ID,
+	logger hclog.Logger,
+) *ContentRoutingService {
+	return &ContentRoutingService{
+		baseServer:   server,
+		logger:       logger.Named("routing"),
+		routingTable: routingTable,
+		selfID:       selfID,
+		providers:    newProviderStore(),
+		provided:     make(map[string]struct{}),
+		closeCh:      make(chan struct{}),
+	}
+}
+
+// Start starts the content routing service
+func (r *ContentRoutingService) Start() {
+	go r.runRepublish()
+}
+
+// Close stops the content routing service
+func (r *ContentRoutingService) Close() {
+	close(r.closeCh)
+}
+
+// Provide advertises the node as a provider of the content key, to the peers closest to it.
+// The content keeps being advertised periodically until StopProviding is called
+func (r *ContentRoutingService) Provide(ctx context.Context, key string) error {
+	if err := validateContentKey(key); err != nil {
+		return err
+	}
+
+	r.providedLock.Lock()
+	r.provided[key] = struct{}{}
+	r.providedLock.Unlock()
+
+	return r.announce(ctx, key)
+}
+
+// StopProviding stops advertising the content key, the provider records expire at the peers
+func (r *ContentRoutingService) StopProviding(key string) {
+	r.providedLock.Lock()
+	defer r.providedLock.Unlock()
+
+	delete(r.provided, key)
+}
+
+// FindProviders returns up to count providers of the content key. The records announced to the node
+// are completed with the ones of the peers closest to the key, which are queried concurrently
+func (r *ContentRoutingService) FindProviders(ctx context.Context, key string, count int) ([]*peer.AddrInfo, error) {
+	if err := validateContentKey(key); err != nil {
+		return nil, err
+	}
+
+	var (
+		found     = make([]*peer.AddrInfo, 0, count)
+		foundPeer = make(map[peer.ID]struct{})
+	)
+
+	addProvider := func(info *peer.AddrInfo) {
+		if len(found) >= count || info.ID == r.selfID || len(info.Addrs) == 0 {
+			return
+		}
+
+		if _, ok := foundPeer[info.ID]; ok {
+			return
+		}
+
+		foundPeer[info.ID] = struct{}{}
+		found = append(found, info)
+	}
+
+	for _, id := range r.providers.get(key, count) {
+		addProvider(r.baseServer.GetPeerInfo(id))
+	}
+
+	if len(found) >= count {
+		return found, nil
+	}
+
+	nearestPeers := r.routingTable.NearestPeers(kb.ConvertKey(key), providerQueryPeers)
+	if len(nearestPeers) == 0 && len(found) == 0 {
+		return nil, ErrNoRoutingPeers
+	}
+
+	results := make([][]string, len(nearestPeers))
+
+	var wg sync.WaitGroup
+
+	for i, id := range nearestPeers {
+		wg.Add(1)
+
+		go func(i int, id peer.ID) {
+			defer wg.Done()
+
+			providers, err := r.getProvidersCall(ctx, id, key, count)
+			if err != nil {
+				r.logger.Debug("failed to query the providers", "peer", id, "key", key, "err", err)
+
+				return
+			}
+
+			results[i] = providers
+		}(i, id)
+	}
+
+	wg.Wait()
+
+	// the providers are merged in the order of the closest peers
+	for _, providers := range results {
+		for _, addr := range providers {
+			info, err := common.StringToAddrInfo(addr)
+			if err != nil {
+				r.logger.Debug("invalid provider address", "addr", addr, "err", err)
+
+				continue
+			}
+
+			addProvider(info)
+		}
+	}
+
+	return found, nil
+}
+
+// announce sends the provider record of the node for the content key to the peers closest to it
+func (r *ContentRoutingService) announce(ctx context.Context, key string) error {
+	nearestPeers := r.routingTable.NearestPeers(kb.ConvertKey(key), providerReplicas)
+	if len(nearestPeers) == 0 {
+		return ErrNoRoutingPeers
+	}
+
+	accepted := 0
+
+	for _, id := range nearestPeers {
+		if err := r.addProviderCall(ctx, id, key); err != nil {
+			r.logger.Debug("failed to announce the provider record", "peer", id, "key", key, "err", err)
+
+			continue
+		}
+
+		accepted++
+	}
+
+	if accepted == 0 {
+		return errProvideNotAccepted
+	}
+
+	return nil
+}
+
+// runRepublish announces the provided content again before the provider records expire,
+// and drops the expired records announced to the node
+func (r *ContentRoutingService) runRepublish() {
+	ticker := time.NewTicker(providerRepublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		r.providers.pruneExpired()
+
+		r.providedLock.Lock()
+		keys := make([]string, 0, len(r.provided))
+
+		for key := range r.provided {
+			keys = append(keys, key)
+		}
+		r.providedLock.Unlock()
+
+		for _, key := range keys {
+			if err := r.announce(context.Background(), key); err != nil {
+				r.logger.Warn("failed to republish the provider record", "key", key, "err", err)
+			}
+		}
+	}
+}
+
+// addProviderCall announces the node as a provider of the content key to the peer
+func (r *ContentRoutingService) addProviderCall(ctx context.Context, peerID peer.ID, key string) error {
+	clt, err := r.baseServer.NewContentRoutingClient(peerID)
+	if err != nil {
+		return fmt.Errorf("unable to create new content routing client connection, %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, routingRequestTimeout)
+	defer cancel()
+
+	_, err = clt.AddProvider(ctx, &proto.AddProviderReq{Key: key})
+
+	return err
+}
+
+// getProvidersCall queries the peer for the providers of the content key
+func (r *ContentRoutingService) getProvidersCall(
+	ctx context.Context,
+	peerID peer.ID,
+	key string,
+	count int,
+) ([]string, error) {
+	clt, err := r.baseServer.NewContentRoutingClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new content routing client connection, %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, routingRequestTimeout)
+	defer cancel()
+
+	resp, err := clt.GetProviders(ctx, &proto.GetProvidersReq{Key: key, Count: int64(count)})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Providers, nil
+}
+
+// AddProvider implements the proto service for recording the requesting peer as a provider of a content key
+func (r *ContentRoutingService) AddProvider(
+	ctx context.Context,
+	req *proto.AddProviderReq,
+) (*proto.AddProviderResp, error) {
+	// Extract the requesting peer ID from the gRPC context,
+	// a peer can only announce itself as a provider
+	grpcContext, ok := ctx.(*grpc.Context)
+	if !ok {
+		return nil, errors.New("invalid type assertion")
+	}
+
+	if err := validateContentKey(req.Key); err != nil {
+		return nil, err
+	}
+
+	if err := r.providers.add(req.Key, grpcContext.PeerID); err != nil {
+		return nil, err
+	}
+
+	return &proto.AddProviderResp{}, nil
+}
+
+// GetProviders implements the proto service for finding the providers of a content key
+func (r *ContentRoutingService) GetProviders(
+	ctx context.Context,
+	req *proto.GetProvidersReq,
+) (*proto.GetProvidersResp, error) {
+	if err := validateContentKey(req.Key); err != nil {
+		return nil, err
+	}
+
+	// Sanity check for result set size
+	if req.Count <= 0 || req.Count > maxProvidersPerKey {
+		req.Count = maxProvidersPerKey
+	}
+
+	providers := make([]string, 0)
+
+	for _, id := range r.providers.get(req.Key, int(req.Count)) {
+		if info := r.baseServer.GetPeerInfo(id); len(info.Addrs) > 0 {
+			providers = append(providers, common.AddrInfoToString(info))
+		}
+	}
+
+	return &proto.GetProvidersResp{
+		Providers: providers,
+	}, nil
+}
+
+// validateContentKey checks that the content key is not empty, nor too long
+func validateContentKey(key string) error {
+	if key == "" || len(key) > maxContentKeyLength {
+		return fmt.Errorf("%w: length %d", ErrInvalidContentKey, len(key))
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	"github.com/0xPolygon/polygon-edge/network/proto"
+	networkTesting "github.com/0xPolygon/polygon-edge/network/testing"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	kb "github.com/libp2p/go-libp2p-kbucket"
+	"github.com/stretchr/testify/assert"
+	rawGrpc "google.golang.org/grpc"
+)
+
+var errPeerUnknown = errors.New("peer unknown")
+
+// testNetwork connects the content routing services of the test nodes in-process
+type testNetwork struct {
+	services map[peer.ID]*ContentRoutingService
+	infos    map[peer.ID]*peer.AddrInfo
+}
+
+// testNode is the networking server of a test node
+type testNode struct {
+	id      peer.ID
+	network *testNetwork
+}
+
+func (n *testNode) NewContentRoutingClient(peerID peer.ID) (proto.ContentRoutingClient, error) {
+	service, ok := n.network.services[peerID]
+	if !ok {
+		return nil, errPeerUnknown
+	}
+
+	return &testClient{from: n.id, service: service}, nil
+}
+
+func (n *testNode) GetPeerInfo(peerID peer.ID) *peer.AddrInfo {
+	if info, ok := n.network.infos[peerID]; ok {
+		return info
+	}
+
+	return &peer.AddrInfo{ID: peerID}
+}
+
+// testClient calls the content routing service of a peer as the given node
+type testClient struct {
+	from    peer.ID
+	service *ContentRoutingService
+}
+
+func (c *testClient) AddProvider(
+	ctx context.Context,
+	in *proto.AddProviderReq,
+	_ ...rawGrpc.CallOption,
+) (*proto.AddProviderResp, error) {
+	return c.service.AddProvider(&grpc.Context{Context: ctx, PeerID: c.from}, in)
+}
+
+func (c *testClient) GetProviders(
+	ctx context.Context,
+	in *proto.GetProvidersReq,
+	_ ...rawGrpc.CallOption,
+) (*proto.GetProvidersResp, error) {
+	return c.service.GetProviders(&grpc.Context{Context: ctx, PeerID: c.from}, in)
+}
+
+// newTestNetwork creates the given number of nodes, all of them in the routing table of the others
+func newTestNetwork(t *testing.T, count int) (*testNetwork, []*ContentRoutingService) {
+	t.Helper()
+
+	network := &testNetwork{
+		services: make(map[peer.ID]*ContentRoutingService),
+		infos:    make(map[peer.ID]*peer.AddrInfo),
+	}
+
+	for i := 0; i < count; i++ {
+		info, err := peer.AddrInfoFromP2pAddr(tests.GenerateTestMultiAddr(t))
+		assert.NoError(t, err)
+
+		network.infos[info.ID] = info
+	}
+
+	services := make([]*ContentRoutingService, 0, count)
+
+	for id := range network.infos {
+		routingTable, err := kb.NewRoutingTable(
+			10,
+			kb.ConvertPeerID(id),
+			time.Minute,
+			&networkTesting.MockPeerMetrics{},
+			10*time.Second,
+			nil,
+		)
+		assert.NoError(t, err)
+
+		for other := range network.infos {
+			if other != id {
+				_, err := routingTable.TryAddPeer(other, false, false)
+				assert.NoError(t, err)
+			}
+		}
+
+		service := NewContentRoutingService(
+			&testNode{id: id, network: network},
+			routingTable,
+			id,
+			hclog.NewNullLogger(),
+		)
+
+		network.services[id] = service
+		services = append(services, service)
+	}
+
+	return network, services
+}
+
+func TestContentRouting_ProvideFind(t *testing.T) {
+	t.Parallel()
+
+	_, services := newTestNetwork(t, 6)
+
+	var (
+		key      = SnapshotKey(types.StringToHash("root"))
+		provider = services[0]
+		seeker   = services[len(services)-1]
+	)
+
+	providers, err := seeker.FindProviders(context.Background(), key, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+
+	assert.NoError(t, provider.Provide(context.Background(), key))
+
+	// the record is announced to the peers closest to the key, the ones the seeker queries
+	providers, err = seeker.FindProviders(context.Background(), key, 10)
+	assert.NoError(t, err)
+
+	if assert.Len(t, providers, 1) {
+		assert.Equal(t, provider.selfID, providers[0].ID)
+		assert.NotEmpty(t, providers[0].Addrs)
+	}
+
+	// the node doesn't find itself
+	providers, err = provider.FindProviders(context.Background(), key, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+
+	// the content isn't announced again once the node stops providing it
+	provider.StopProviding(key)
+	assert.NotContains(t, provider.provided, key)
+}
+
+func TestContentRouting_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	_, services := newTestNetwork(t, 2)
+
+	assert.ErrorIs(t, services[0].Provide(context.Background(), ""), ErrInvalidContentKey)
+
+	_, err := services[0].FindProviders(context.Background(), string(make([]byte, maxContentKeyLength+1)), 1)
+	assert.ErrorIs(t, err, ErrInvalidContentKey)
+}
+
+func TestContentRouting_NoRoutingPeers(t *testing.T) {
+	t.Parallel()
+
+	_, services := newTestNetwork(t, 1)
+
+	key := StateChunkKey(types.StringToHash("root"), types.StringToHash("chunk"))
+
+	assert.ErrorIs(t, services[0].Provide(context.Background(), key), ErrNoRoutingPeers)
+
+	_, err := services[0].FindProviders(context.Background(), key, 1)
+	assert.ErrorIs(t, err, ErrNoRoutingPeers)
+}
+
+func TestContentRouting_AddProviderFromRequester(t *testing.T) {
+	t.Parallel()
+
+	_, services := newTestNetwork(t, 1)
+
+	// the requesting peer is recorded, not a peer of its choice
+	_, err := services[0].AddProvider(
+		&grpc.Context{Context: context.Background(), PeerID: "requester"},
+		&proto.AddProviderReq{Key: "key"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []peer.ID{"requester"}, services[0].providers.get("key", 1))
+
+	// the request has to come through the gRPC stream of a peer
+	_, err = services[0].AddProvider(context.Background(), &proto.AddProviderReq{Key: "key"})
+	assert.Error(t, err)
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/network/dial"
 	"github.com/0xPolygon/polygon-edge/network/discovery"
+	"github.com/0xPolygon/polygon-edge/network/routing"
 	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
 	rawGrpc "google.golang.org/grpc"
 
@@ -73,6 +75,8 @@ type Server struct {
 
 	discovery *discovery.DiscoveryService // service used for discovering other peers
 
+	routing *routing.ContentRoutingService // service used for advertising and finding content providers
+
 	protocols     map[string]Protocol // supported protocols
 	protocolsLock sync.Mutex          // lock for the supported protocols map
 
@@ -80,6 +84,8 @@ type Server struct {
 
 	ps *pubsub.PubSub // reference to the networking PubSub service
 
+	gossipTracer *gossipTracer // tracer of the gossipsub mesh health
+
 	emitterPeerEvent event.Emitter // event emitter for listeners
 
 	connectionCounts *ConnectionInfo
@@ -154,6 +160,7 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 			config.MaxInboundPeers,
 			config.MaxOutboundPeers,
 		),
+		gossipTracer: newGossipTracer(config.Metrics),
 	}
 
 	// start gossip protocol
@@ -161,6 +168,7 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		context.Background(),
 		host, pubsub.WithPeerOutboundQueueSize(peerOutboundBufferSize),
 		pubsub.WithValidateQueueSize(validateBufferSize),
+		pubsub.WithRawTracer(srv.gossipTracer),
 	)
 	if err != nil {
 		return nil, err
@@ -176,6 +184,12 @@ func (s *Server) HasFreeConnectionSlot(direction network.Direction) bool {
 	return s.connectionCounts.HasFreeConnectionSlot(direction)
 }
 
+// SetMaxPeers sets the maximum numbers of inbound and outbound peer connections [Thread safe].
+// The connections above the new limits are kept, only the new ones are refused
+func (s *Server) SetMaxPeers(maxInboundPeers, maxOutboundPeers int64) {
+	s.connectionCounts.setMaxConnCounts(maxInboundPeers, maxOutboundPeers)
+}
+
 // PeerConnInfo holds the connection information about the peer
 type PeerConnInfo struct {
 	Info peer.AddrInfo
@@ -263,6 +277,7 @@ func (s *Server) Start() error {
 
 	go s.runDial()
 	go s.checkPeerConnections()
+	go s.runConnManager()
 
 	// watch for disconnected peers
 	s.host.Network().Notify(&network.NotifyBundle{
@@ -451,6 +466,27 @@ func (s *Server) IsConnected(peerID peer.ID) bool {
 	return s.host.Network().Connectedness(peerID) == network.Connected
 }
 
+// Ping sends up to count pings to the peer with the libp2p ping protocol, one after the other.
+// It returns the round trip times of the pings answered before the first failure, and the failure
+func (s *Server) Ping(ctx context.Context, peerID peer.ID, count int) ([]time.Duration, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rtts := make([]time.Duration, 0, count)
+
+	for res := range ping.Ping(ctx, s.host, peerID) {
+		if res.Error != nil {
+			return rtts, res.Error
+		}
+
+		if rtts = append(rtts, res.RTT); len(rtts) == count {
+			break
+		}
+	}
+
+	return rtts, ctx.Err()
+}
+
 // GetProtocols fetches the list of node-supported protocols
 func (s *Server) GetProtocols(peerID peer.ID) ([]string, error) {
 	return s.host.Peerstore().GetProtocols(peerID)
@@ -560,6 +596,11 @@ func (s *Server) JoinPeer(rawPeerMultiaddr string) error {
 	return nil
 }
 
+// JoinPeerInfo attempts to add the peer with the given info to the networking server
+func (s *Server) JoinPeerInfo(peerInfo *peer.AddrInfo) {
+	s.joinPeer(peerInfo)
+}
+
 // joinPeer creates a new dial task for the peer (for async joining)
 func (s *Server) joinPeer(peerInfo *peer.AddrInfo) {
 	s.logger.Info("Join request", "addr", peerInfo.String())
@@ -579,6 +620,10 @@ func (s *Server) Close() error {
 		s.discovery.Close()
 	}
 
+	if s.routing != nil {
+		s.routing.Close()
+	}
+
 	close(s.closeCh)
 
 	return err
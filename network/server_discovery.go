@@ -243,6 +243,9 @@ func (s *Server) setupDiscovery() error {
 	// Set the discovery service reference
 	s.discovery = discoveryService
 
+	// The content providers are routed with the same routing table
+	s.setupContentRouting(routingTable)
+
 	return nil
 }
 
@@ -0,0 +1,87 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/network/grpc"
+	"github.com/0xPolygon/polygon-edge/network/proto"
+	"github.com/0xPolygon/polygon-edge/network/routing"
+	"github.com/libp2p/go-libp2p-core/peer"
+	kb "github.com/libp2p/go-libp2p-kbucket"
+)
+
+var (
+	ErrContentRoutingDisabled = errors.New("content routing is disabled along with the discovery")
+)
+
+// NewContentRoutingClient returns a new or existing content routing service client connection
+func (s *Server) NewContentRoutingClient(peerID peer.ID) (proto.ContentRoutingClient, error) {
+	// The content is only routed through the connected peers
+	if !s.IsConnected(peerID) {
+		return nil, errPeerDisconnected
+	}
+
+	// Check if there is an active stream connection already
+	if protoStream := s.getProtoStream(common.RoutingProto, peerID); protoStream != nil {
+		return proto.NewContentRoutingClient(protoStream), nil
+	}
+
+	// Create a new stream connection and save it, for the subsequent requests
+	protoStream, err := s.NewProtoConnection(common.RoutingProto, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.SaveProtocolStream(common.RoutingProto, protoStream, peerID)
+
+	return proto.NewContentRoutingClient(protoStream), nil
+}
+
+// Provide advertises the node as a provider of the content key, such as a state snapshot or chunk,
+// so that the peers looking for it find the node. The content is advertised until StopProviding is called
+func (s *Server) Provide(ctx context.Context, key string) error {
+	if s.routing == nil {
+		return ErrContentRoutingDisabled
+	}
+
+	return s.routing.Provide(ctx, key)
+}
+
+// StopProviding stops advertising the content key
+func (s *Server) StopProviding(key string) {
+	if s.routing != nil {
+		s.routing.StopProviding(key)
+	}
+}
+
+// FindProviders returns up to count peers providing the content key
+func (s *Server) FindProviders(ctx context.Context, key string, count int) ([]*peer.AddrInfo, error) {
+	if s.routing == nil {
+		return nil, ErrContentRoutingDisabled
+	}
+
+	return s.routing.FindProviders(ctx, key, count)
+}
+
+// setupContentRouting sets up the content routing service for the node, on top of the discovery routing table
+func (s *Server) setupContentRouting(routingTable *kb.RoutingTable) {
+	routingService := routing.NewContentRoutingService(
+		s,
+		routingTable,
+		s.host.ID(),
+		s.logger,
+	)
+
+	// Register the content routing service as a valid protocol
+	grpcStream := grpc.NewGrpcStream()
+	proto.RegisterContentRoutingServer(grpcStream.GrpcServer(), routingService)
+	grpcStream.Serve()
+
+	s.RegisterProtocol(common.RoutingProto, grpcStream)
+
+	routingService.Start()
+
+	s.routing = routingService
+}
@@ -321,6 +321,32 @@ func TestJoinWhenAlreadyConnected(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	servers, createErr := createServers(2, nil)
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	if joinErr := JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	rtts, err := servers[0].Ping(context.Background(), servers[1].AddrInfo().ID, 3)
+	assert.NoError(t, err)
+	assert.Len(t, rtts, 3)
+
+	// the pings stop at the first failure
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = servers[0].Ping(ctx, servers[1].AddrInfo().ID, 3)
+	assert.Error(t, err)
+}
+
 func TestNat(t *testing.T) {
 	testIP := "192.0.2.1"
 	testPort := 1500 // important to be less than 2000 because of other tests and more than 1024 because of OS security
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -17,6 +18,10 @@ import (
 	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
 )
 
+var (
+	ErrUnsupportedType = errors.New("unsupported secrets manager")
+)
+
 // SetupLocalSecretsManager is a helper method for boilerplate local secrets manager setup
 func SetupLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
 	subDirectories := []string{secrets.ConsensusFolderLocal, secrets.NetworkFolderLocal}
@@ -43,6 +48,28 @@ func SetupLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
 	)
 }
 
+// LoadLocalSecretsManager is a helper method for boilerplate setup of a local secrets manager
+// with previously initialized secrets data
+func LoadLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
+	if !common.DirectoryExists(filepath.Join(dataDir, secrets.ConsensusFolderLocal)) {
+		return nil,
+			fmt.Errorf(
+				"directory %s has no initialized secrets data",
+				dataDir,
+			)
+	}
+
+	return local.SecretsManagerFactory(
+		nil, // Local secrets manager doesn't require a config
+		&secrets.SecretsManagerParams{
+			Logger: hclog.NewNullLogger(),
+			Extra: map[string]interface{}{
+				secrets.Path: dataDir,
+			},
+		},
+	)
+}
+
 // SetupHashicorpVault is a helper method for boilerplate hashicorp vault secrets manager setup
 func SetupHashicorpVault(
 	secretsConfig *secrets.SecretsManagerConfig,
@@ -79,6 +106,20 @@ func SetupGCPSSM(
 	)
 }
 
+// InitCloudSecretsManager returns the secrets manager of the configured remote service
+func InitCloudSecretsManager(secretsConfig *secrets.SecretsManagerConfig) (secrets.SecretsManager, error) {
+	switch secretsConfig.Type {
+	case secrets.HashicorpVault:
+		return SetupHashicorpVault(secretsConfig)
+	case secrets.AWSSSM:
+		return SetupAWSSSM(secretsConfig)
+	case secrets.GCPSSM:
+		return SetupGCPSSM(secretsConfig)
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
 func InitValidatorKey(secretsManager secrets.SecretsManager) (*ecdsa.PrivateKey, error) {
 	// Generate the IBFT validator private key
 	validatorKey, validatorKeyEncoded, keyErr := crypto.GenerateAndEncodePrivateKey()
@@ -97,6 +138,24 @@ func InitValidatorKey(secretsManager secrets.SecretsManager) (*ecdsa.PrivateKey,
 	return validatorKey, nil
 }
 
+func InitBLSValidatorKey(secretsManager secrets.SecretsManager) (*crypto.BLSPrivateKey, error) {
+	// Generate the BLS validator private key
+	blsKey, blsKeyEncoded, keyErr := crypto.GenerateAndEncodeBLSPrivateKey()
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	// Write the BLS validator private key to the secrets manager storage
+	if setErr := secretsManager.SetSecret(
+		secrets.ValidatorBLSKey,
+		blsKeyEncoded,
+	); setErr != nil {
+		return nil, setErr
+	}
+
+	return blsKey, nil
+}
+
 func InitNetworkingPrivateKey(secretsManager secrets.SecretsManager) (libp2pCrypto.PrivKey, error) {
 	// Generate the libp2p private key
 	libp2pKey, libp2pKeyEncoded, keyErr := network.GenerateAndEncodeLibp2pKey()
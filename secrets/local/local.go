@@ -78,6 +78,13 @@ func (l *LocalSecretsManager) Setup() error {
 		secrets.ValidatorKeyLocal,
 	)
 
+	// baseDir/consensus/validator-bls.key
+	l.secretPathMap[secrets.ValidatorBLSKey] = filepath.Join(
+		l.path,
+		secrets.ConsensusFolderLocal,
+		secrets.ValidatorBLSKeyLocal,
+	)
+
 	// baseDir/libp2p/libp2p.key
 	l.secretPathMap[secrets.NetworkKey] = filepath.Join(
 		l.path,
@@ -1,6 +1,7 @@
 package local
 
 import (
+	"bytes"
 	"encoding/hex"
 	"io/ioutil"
 	"os"
@@ -129,6 +130,11 @@ func TestLocalSecretsManager_GetSetSecret(
 		t.Fatalf("Unable to generate validator private key, %v", genErr)
 	}
 
+	blsKey, blsKeyEncoded, genErr := crypto.GenerateAndEncodeBLSPrivateKey()
+	if genErr != nil {
+		t.Fatalf("Unable to generate validator BLS private key, %v", genErr)
+	}
+
 	libp2pKey, libp2pKeyEncoded, genErr := generateAndEncodeLibp2pKey()
 	if genErr != nil {
 		t.Fatalf("Unable to generate networking private key, %v", genErr)
@@ -144,6 +150,16 @@ func TestLocalSecretsManager_GetSetSecret(
 		return validatorKey.Equal(parsedKey)
 	}
 
+	// Compare validator BLS keys helper
+	compareBLSKeys := func(manager secrets.SecretsManager) bool {
+		parsedKey, parseErr := crypto.ReadBLSKey(manager)
+		if parseErr != nil {
+			t.Fatalf("unable to parse validator BLS private key, %v", parseErr)
+		}
+
+		return bytes.Equal(blsKey.Marshal(), parsedKey.Marshal())
+	}
+
 	// Compare networking keys helper
 	compareNetworkingKeys := func(manager secrets.SecretsManager) bool {
 		secret, err := manager.GetSecret(secrets.NetworkKey)
@@ -178,6 +194,13 @@ func TestLocalSecretsManager_GetSetSecret(
 			compareValidatorKeys,
 			true,
 		},
+		{
+			"Validator BLS key storage",
+			secrets.ValidatorBLSKey,
+			blsKeyEncoded,
+			compareBLSKeys,
+			true,
+		},
 		{
 			"Networking key storage",
 			secrets.NetworkKey,
@@ -2,12 +2,19 @@ package server
 
 import (
 	"net"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/0xPolygon/polygon-edge/txpool"
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 const DefaultGRPCPort int = 9632
@@ -25,8 +32,86 @@ type Config struct {
 	MaxSlots   uint64
 	BlockTime  uint64
 
+	// BlockDeadline is the fraction of the block time the proposer executes transactions within
+	BlockDeadline float64
+
+	// IBFTDryRun makes the validator follow the consensus without signing nor gossiping its messages
+	IBFTDryRun bool
+
+	// IBFTBuilderKeys are the keys of the external block builders, disabled if there is none
+	IBFTBuilderKeys []*consensus.BuilderKey
+
+	// IBFTBuilderTimeout is the time the proposer waits for an external builder payload
+	IBFTBuilderTimeout time.Duration
+
+	// ExemptAddresses are the senders not subject to the txpool limits
+	ExemptAddresses []types.Address
+
+	// LocalAddresses are the senders whose transactions the txpool treats as local
+	LocalAddresses []types.Address
+
+	// FutureTxTypes are the transaction types held by the txpool until their fork
+	FutureTxTypes []*txpool.FutureTxType
+
+	// OperatorAccount is the account of the embedded relayer the txpool reserves nonces for, none if nil
+	OperatorAccount *types.Address
+
+	// TxPoolJournalPath is the file the txpool journals its pending transactions to, in the data dir if empty
+	TxPoolJournalPath string
+
+	// TxPoolJournalInterval is the interval the txpool journals its pending transactions at, disabled if zero
+	TxPoolJournalInterval time.Duration
+
+	// TxPoolPriceBump is the percentage the txpool requires a replacement transaction to raise the gas price by
+	TxPoolPriceBump uint64
+
+	// TxPoolMaxAccountTxs is the number of pending transactions an account can have in the txpool, unlimited if zero
+	TxPoolMaxAccountTxs uint64
+
+	// TxPoolMaxEnqueued is the number of enqueued transactions the txpool holds, unlimited if zero
+	TxPoolMaxEnqueued uint64
+
+	// StateCommitInterval is the number of blocks after which
+	// the state kept in memory is written to disk
+	StateCommitInterval uint64
+
+	// OpcodeStats enables the statistics of the opcodes executed by the blocks
+	OpcodeStats bool
+
+	// StatePrefetchWorkers is the number of goroutines loading the accounts of a block
+	// while it executes, disabled if zero
+	StatePrefetchWorkers int
+
+	// RecordPreimages enables the recording of the preimages of the hashed trie keys
+	RecordPreimages bool
+
+	// StateSnapshot enables the flat snapshot of the state
+	StateSnapshot bool
+
+	// StorageCompression is the compression of the block bodies and receipts written to disk
+	StorageCompression storage.Compression
+
+	// CompactReceipts writes the receipts without the fields recomputed on read, with their log data compressed
+	CompactReceipts bool
+
+	// DBEngine is the database engine of the blockchain storage
+	DBEngine storage.Engine
+
+	// FreezerThreshold is the number of recent blocks kept in leveldb,
+	// the older ones being moved to the freezer. Zero disables the freezer
+	FreezerThreshold uint64
+
+	// TxLookupLimit is the number of recent blocks whose transactions
+	// are looked up by hash. Zero keeps the lookups of all the blocks
+	TxLookupLimit uint64
+
+	// FutureBlockTolerance is the time a synced block can be ahead of the local clock,
+	// such a block is written when its time arrives
+	FutureBlockTolerance time.Duration
+
 	Telemetry *Telemetry
 	Network   *network.Config
+	Syncer    *syncer.Config
 
 	DataDir     string
 	RestoreFile *string
@@ -38,6 +123,10 @@ type Config struct {
 	LogLevel hclog.Level
 
 	LogFilePath string
+
+	// LoadReloadableConfig loads the configuration file again on a reload, nil if
+	// the server isn't started with a configuration file
+	LoadReloadableConfig func() (*ReloadableConfig, error)
 }
 
 // Telemetry holds the config details for metric services
@@ -51,4 +140,5 @@ type JSONRPC struct {
 	AccessControlAllowOrigin []string
 	BatchLengthLimit         uint64
 	BlockRangeLimit          uint64
+	APIKeys                  []*jsonrpc.APIKey
 }
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// defaultLatencyPings is the number of pings sent to each peer if the request doesn't specify it
+	defaultLatencyPings = 10
+	// maxLatencyPings is the maximum number of pings sent to each peer
+	maxLatencyPings = 1000
+)
+
+// measurePeerLatencies pings the connected peers at the libp2p and the sync protocol levels.
+// The peers are pinged concurrently, the pings of a peer one after the other
+func (s *Server) measurePeerLatencies(ctx context.Context, count int) []*proto.PeerLatency {
+	var (
+		peers     = s.network.Peers()
+		latencies = make([]*proto.PeerLatency, len(peers))
+		wg        sync.WaitGroup
+	)
+
+	for i, p := range peers {
+		i, peerID := i, p.Info.ID
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			latencies[i] = s.measurePeerLatency(ctx, peerID, count)
+		}()
+	}
+
+	wg.Wait()
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i].Id < latencies[j].Id
+	})
+
+	return latencies
+}
+
+// measurePeerLatency pings the peer at the libp2p and the sync protocol levels
+func (s *Server) measurePeerLatency(ctx context.Context, peerID peer.ID, count int) *proto.PeerLatency {
+	libp2pRTTs, libp2pErr := s.network.Ping(ctx, peerID, count)
+	syncRTTs, syncErr := syncer.PingPeer(ctx, s.network, peerID, count)
+
+	return &proto.PeerLatency{
+		Id:     peerID.String(),
+		Libp2P: toLatencyStats(libp2pRTTs, count, libp2pErr),
+		Sync:   toLatencyStats(syncRTTs, count, syncErr),
+	}
+}
+
+// toLatencyStats computes the percentiles of the round trip times of count pings,
+// the pings without a round trip time failed with the given error
+func toLatencyStats(rtts []time.Duration, count int, err error) *proto.LatencyStats {
+	stats := &proto.LatencyStats{
+		Received: uint64(len(rtts)),
+		Failed:   uint64(count - len(rtts)),
+	}
+
+	if err != nil {
+		stats.Error = err.Error()
+	}
+
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	sorted := make([]time.Duration, len(rtts))
+	copy(sorted, rtts)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	// nearest-rank percentile
+	percentile := func(p int) uint64 {
+		rank := (p*len(sorted) + 99) / 100
+
+		return uint64(sorted[rank-1].Microseconds())
+	}
+
+	stats.P50Us = percentile(50)
+	stats.P90Us = percentile(90)
+	stats.P99Us = percentile(99)
+	stats.MaxUs = uint64(sorted[len(sorted)-1].Microseconds())
+
+	return stats
+}
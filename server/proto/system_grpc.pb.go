@@ -33,6 +33,22 @@ type SystemClient interface {
 	BlockByNumber(ctx context.Context, in *BlockByNumberRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (System_ExportClient, error)
+	// FlushState writes the state committed in memory to disk
+	FlushState(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*FlushStateResponse, error)
+	// PeersLatency measures the round trip times to the connected peers
+	PeersLatency(ctx context.Context, in *PeersLatencyRequest, opts ...grpc.CallOption) (*PeersLatencyResponse, error)
+	// Resync discards the blocks from a number and syncs them again from the peers
+	Resync(ctx context.Context, in *ResyncRequest, opts ...grpc.CallOption) (*ResyncResponse, error)
+	// SyncVerify downloads a range of blocks from a peer and verifies them without writing them
+	SyncVerify(ctx context.Context, in *SyncVerifyRequest, opts ...grpc.CallOption) (*SyncVerifyResponse, error)
+	// GetBlockGasTarget returns the gas limit target of the next blocks
+	GetBlockGasTarget(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*BlockGasTargetResponse, error)
+	// SetBlockGasTarget sets the gas limit target of the next blocks
+	SetBlockGasTarget(ctx context.Context, in *SetBlockGasTargetRequest, opts ...grpc.CallOption) (*BlockGasTargetResponse, error)
+	// ReloadConfig reads the config file again and applies the changes of the settings reloadable at runtime
+	ReloadConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// GossipStats returns the gossipsub mesh statistics of the node
+	GossipStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GossipStatsResponse, error)
 }
 
 type systemClient struct {
@@ -152,6 +168,78 @@ func (x *systemExportClient) Recv() (*ExportEvent, error) {
 	return m, nil
 }
 
+func (c *systemClient) FlushState(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*FlushStateResponse, error) {
+	out := new(FlushStateResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/FlushState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) PeersLatency(ctx context.Context, in *PeersLatencyRequest, opts ...grpc.CallOption) (*PeersLatencyResponse, error) {
+	out := new(PeersLatencyResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersLatency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) Resync(ctx context.Context, in *ResyncRequest, opts ...grpc.CallOption) (*ResyncResponse, error) {
+	out := new(ResyncResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/Resync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) SyncVerify(ctx context.Context, in *SyncVerifyRequest, opts ...grpc.CallOption) (*SyncVerifyResponse, error) {
+	out := new(SyncVerifyResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/SyncVerify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) GetBlockGasTarget(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*BlockGasTargetResponse, error) {
+	out := new(BlockGasTargetResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/GetBlockGasTarget", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) SetBlockGasTarget(ctx context.Context, in *SetBlockGasTargetRequest, opts ...grpc.CallOption) (*BlockGasTargetResponse, error) {
+	out := new(BlockGasTargetResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/SetBlockGasTarget", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) ReloadConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) GossipStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GossipStatsResponse, error) {
+	out := new(GossipStatsResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/GossipStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemServer is the server API for System service.
 // All implementations must embed UnimplementedSystemServer
 // for forward compatibility
@@ -170,6 +258,22 @@ type SystemServer interface {
 	BlockByNumber(context.Context, *BlockByNumberRequest) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(*ExportRequest, System_ExportServer) error
+	// FlushState writes the state committed in memory to disk
+	FlushState(context.Context, *emptypb.Empty) (*FlushStateResponse, error)
+	// PeersLatency measures the round trip times to the connected peers
+	PeersLatency(context.Context, *PeersLatencyRequest) (*PeersLatencyResponse, error)
+	// Resync discards the blocks from a number and syncs them again from the peers
+	Resync(context.Context, *ResyncRequest) (*ResyncResponse, error)
+	// SyncVerify downloads a range of blocks from a peer and verifies them without writing them
+	SyncVerify(context.Context, *SyncVerifyRequest) (*SyncVerifyResponse, error)
+	// GetBlockGasTarget returns the gas limit target of the next blocks
+	GetBlockGasTarget(context.Context, *emptypb.Empty) (*BlockGasTargetResponse, error)
+	// SetBlockGasTarget sets the gas limit target of the next blocks
+	SetBlockGasTarget(context.Context, *SetBlockGasTargetRequest) (*BlockGasTargetResponse, error)
+	// ReloadConfig reads the config file again and applies the changes of the settings reloadable at runtime
+	ReloadConfig(context.Context, *emptypb.Empty) (*ReloadConfigResponse, error)
+	// GossipStats returns the gossipsub mesh statistics of the node
+	GossipStats(context.Context, *emptypb.Empty) (*GossipStatsResponse, error)
 	mustEmbedUnimplementedSystemServer()
 }
 
@@ -198,6 +302,30 @@ func (UnimplementedSystemServer) BlockByNumber(context.Context, *BlockByNumberRe
 func (UnimplementedSystemServer) Export(*ExportRequest, System_ExportServer) error {
 	return status.Errorf(codes.Unimplemented, "method Export not implemented")
 }
+func (UnimplementedSystemServer) FlushState(context.Context, *emptypb.Empty) (*FlushStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushState not implemented")
+}
+func (UnimplementedSystemServer) PeersLatency(context.Context, *PeersLatencyRequest) (*PeersLatencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersLatency not implemented")
+}
+func (UnimplementedSystemServer) Resync(context.Context, *ResyncRequest) (*ResyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resync not implemented")
+}
+func (UnimplementedSystemServer) SyncVerify(context.Context, *SyncVerifyRequest) (*SyncVerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncVerify not implemented")
+}
+func (UnimplementedSystemServer) GetBlockGasTarget(context.Context, *emptypb.Empty) (*BlockGasTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockGasTarget not implemented")
+}
+func (UnimplementedSystemServer) SetBlockGasTarget(context.Context, *SetBlockGasTargetRequest) (*BlockGasTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBlockGasTarget not implemented")
+}
+func (UnimplementedSystemServer) ReloadConfig(context.Context, *emptypb.Empty) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedSystemServer) GossipStats(context.Context, *emptypb.Empty) (*GossipStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GossipStats not implemented")
+}
 func (UnimplementedSystemServer) mustEmbedUnimplementedSystemServer() {}
 
 // UnsafeSystemServer may be embedded to opt out of forward compatibility for this service.
@@ -343,6 +471,150 @@ func (x *systemExportServer) Send(m *ExportEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _System_FlushState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).FlushState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/FlushState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).FlushState(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_PeersLatency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersLatencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersLatency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersLatency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersLatency(ctx, req.(*PeersLatencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_Resync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).Resync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/Resync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).Resync(ctx, req.(*ResyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_SyncVerify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncVerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).SyncVerify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/SyncVerify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).SyncVerify(ctx, req.(*SyncVerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_GetBlockGasTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).GetBlockGasTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/GetBlockGasTarget",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).GetBlockGasTarget(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_SetBlockGasTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBlockGasTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).SetBlockGasTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/SetBlockGasTarget",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).SetBlockGasTarget(ctx, req.(*SetBlockGasTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).ReloadConfig(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_GossipStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).GossipStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/GossipStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).GossipStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // System_ServiceDesc is the grpc.ServiceDesc for System service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -370,6 +642,38 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlockByNumber",
 			Handler:    _System_BlockByNumber_Handler,
 		},
+		{
+			MethodName: "FlushState",
+			Handler:    _System_FlushState_Handler,
+		},
+		{
+			MethodName: "PeersLatency",
+			Handler:    _System_PeersLatency_Handler,
+		},
+		{
+			MethodName: "Resync",
+			Handler:    _System_Resync_Handler,
+		},
+		{
+			MethodName: "SyncVerify",
+			Handler:    _System_SyncVerify_Handler,
+		},
+		{
+			MethodName: "GetBlockGasTarget",
+			Handler:    _System_GetBlockGasTarget_Handler,
+		},
+		{
+			MethodName: "SetBlockGasTarget",
+			Handler:    _System_SetBlockGasTarget_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _System_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "GossipStats",
+			Handler:    _System_GossipStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
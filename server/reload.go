@@ -0,0 +1,292 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/hashicorp/go-hclog"
+)
+
+// configAuditFileName is the name of the file in the data directory the applied configuration changes are appended to
+const configAuditFileName = "config_changes.log"
+
+var (
+	errReloadUnsupported  = errors.New("the server wasn't started with a config file to reload")
+	errInvalidLogLevel    = errors.New("invalid log level")
+	errInvalidBatchLimit  = errors.New("the json-rpc batch request limit has to be positive")
+	errInvalidRangeLimit  = errors.New("the json-rpc block range limit has to be positive")
+	errInvalidPeerLimits  = errors.New("the peer limits can't be negative, and at least one peer has to be allowed")
+	errInvalidSyncLimits  = errors.New("the sync request rate limit and burst have to be positive")
+	errSyncLimitsNotFound = errors.New("the consensus doesn't support changing the sync request limits")
+)
+
+// syncRequestLimiter is implemented by the consensus mechanisms serving the blocks to the syncing peers
+type syncRequestLimiter interface {
+	SetSyncRequestLimits(rateLimit float64, burst uint64)
+}
+
+// ReloadableConfig is the part of the configuration which is applied without restarting the server
+type ReloadableConfig struct {
+	LogLevel hclog.Level
+
+	JSONRPCBatchLengthLimit uint64
+	JSONRPCBlockRangeLimit  uint64
+
+	PriceLimit uint64
+
+	MaxInboundPeers  int64
+	MaxOutboundPeers int64
+
+	SyncRequestRateLimit float64
+	SyncRequestBurst     uint64
+}
+
+// ConfigChange is a configuration value changed by a reload
+type ConfigChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// configAuditEntry is an entry of the audit log of the configuration changes
+type configAuditEntry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	ConfigChange
+}
+
+// configReload holds the configuration applied at runtime
+type configReload struct {
+	sync.Mutex
+
+	current *ReloadableConfig
+	sigCh   chan os.Signal
+}
+
+// reloadableConfig returns the reloadable part of the configuration the server is started with
+func reloadableConfig(config *Config) *ReloadableConfig {
+	c := &ReloadableConfig{
+		LogLevel:         config.LogLevel,
+		PriceLimit:       config.PriceLimit,
+		MaxInboundPeers:  config.Network.MaxInboundPeers,
+		MaxOutboundPeers: config.Network.MaxOutboundPeers,
+	}
+
+	if config.JSONRPC != nil {
+		c.JSONRPCBatchLengthLimit = config.JSONRPC.BatchLengthLimit
+		c.JSONRPCBlockRangeLimit = config.JSONRPC.BlockRangeLimit
+	}
+
+	if config.Syncer != nil {
+		c.SyncRequestRateLimit = config.Syncer.RequestRateLimit
+		c.SyncRequestBurst = config.Syncer.RequestBurst
+	}
+
+	// the syncer applies the defaults to the unset limits
+	if c.SyncRequestRateLimit == 0 {
+		c.SyncRequestRateLimit = syncer.DefaultRequestRateLimit
+	}
+
+	if c.SyncRequestBurst == 0 {
+		c.SyncRequestBurst = syncer.DefaultRequestBurst
+	}
+
+	return c
+}
+
+// validate checks the configuration before any of it is applied
+func (c *ReloadableConfig) validate() error {
+	if c.LogLevel == hclog.NoLevel {
+		return errInvalidLogLevel
+	}
+
+	if c.JSONRPCBatchLengthLimit == 0 {
+		return errInvalidBatchLimit
+	}
+
+	if c.JSONRPCBlockRangeLimit == 0 {
+		return errInvalidRangeLimit
+	}
+
+	if c.MaxInboundPeers < 0 || c.MaxOutboundPeers < 0 || c.MaxInboundPeers+c.MaxOutboundPeers == 0 {
+		return errInvalidPeerLimits
+	}
+
+	if c.SyncRequestRateLimit <= 0 || c.SyncRequestBurst == 0 {
+		return errInvalidSyncLimits
+	}
+
+	return nil
+}
+
+// ReloadConfig loads the configuration file again, and applies the changes of its reloadable part
+func (s *Server) ReloadConfig(source string) ([]*ConfigChange, error) {
+	if s.config.LoadReloadableConfig == nil {
+		return nil, errReloadUnsupported
+	}
+
+	config, err := s.config.LoadReloadableConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the config: %w", err)
+	}
+
+	return s.Reload(config, source)
+}
+
+// Reload applies the changes of the reloadable configuration, which is validated first so that
+// an invalid configuration changes nothing. The applied changes are appended to the audit log,
+// along with the source of the reload
+func (s *Server) Reload(config *ReloadableConfig, source string) ([]*ConfigChange, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	s.reload.Lock()
+	defer s.reload.Unlock()
+
+	var (
+		current = s.reload.current
+		changes = make([]*ConfigChange, 0)
+	)
+
+	addChange := func(key string, old, new interface{}) {
+		changes = append(changes, &ConfigChange{
+			Key: key,
+			Old: fmt.Sprint(old),
+			New: fmt.Sprint(new),
+		})
+	}
+
+	if config.SyncRequestRateLimit != current.SyncRequestRateLimit || config.SyncRequestBurst != current.SyncRequestBurst {
+		limiter, ok := s.consensus.(syncRequestLimiter)
+		if !ok {
+			return nil, errSyncLimitsNotFound
+		}
+
+		limiter.SetSyncRequestLimits(config.SyncRequestRateLimit, config.SyncRequestBurst)
+
+		if config.SyncRequestRateLimit != current.SyncRequestRateLimit {
+			addChange("sync.request_rate_limit", current.SyncRequestRateLimit, config.SyncRequestRateLimit)
+		}
+
+		if config.SyncRequestBurst != current.SyncRequestBurst {
+			addChange("sync.request_burst", current.SyncRequestBurst, config.SyncRequestBurst)
+		}
+	}
+
+	if config.LogLevel != current.LogLevel {
+		s.logger.SetLevel(config.LogLevel)
+		addChange("log_level", current.LogLevel, config.LogLevel)
+	}
+
+	if config.JSONRPCBatchLengthLimit != current.JSONRPCBatchLengthLimit ||
+		config.JSONRPCBlockRangeLimit != current.JSONRPCBlockRangeLimit {
+		if s.jsonrpcServer != nil {
+			s.jsonrpcServer.SetLimits(config.JSONRPCBatchLengthLimit, config.JSONRPCBlockRangeLimit)
+		}
+
+		if config.JSONRPCBatchLengthLimit != current.JSONRPCBatchLengthLimit {
+			addChange("json_rpc_batch_request_limit", current.JSONRPCBatchLengthLimit, config.JSONRPCBatchLengthLimit)
+		}
+
+		if config.JSONRPCBlockRangeLimit != current.JSONRPCBlockRangeLimit {
+			addChange("json_rpc_block_range_limit", current.JSONRPCBlockRangeLimit, config.JSONRPCBlockRangeLimit)
+		}
+	}
+
+	if config.PriceLimit != current.PriceLimit {
+		s.txpool.SetPriceLimit(config.PriceLimit)
+		addChange("tx_pool.price_limit", current.PriceLimit, config.PriceLimit)
+	}
+
+	if config.MaxInboundPeers != current.MaxInboundPeers || config.MaxOutboundPeers != current.MaxOutboundPeers {
+		s.network.SetMaxPeers(config.MaxInboundPeers, config.MaxOutboundPeers)
+
+		if config.MaxInboundPeers != current.MaxInboundPeers {
+			addChange("network.max_inbound_peers", current.MaxInboundPeers, config.MaxInboundPeers)
+		}
+
+		if config.MaxOutboundPeers != current.MaxOutboundPeers {
+			addChange("network.max_outbound_peers", current.MaxOutboundPeers, config.MaxOutboundPeers)
+		}
+	}
+
+	s.reload.current = config
+
+	s.auditConfigChanges(source, changes)
+
+	return changes, nil
+}
+
+// auditConfigChanges logs the applied configuration changes, and appends them to the audit log of the data directory
+func (s *Server) auditConfigChanges(source string, changes []*ConfigChange) {
+	if len(changes) == 0 {
+		s.logger.Info("config reloaded without changes", "source", source)
+
+		return
+	}
+
+	for _, change := range changes {
+		s.logger.Info("config changed", "source", source, "key", change.Key, "old", change.Old, "new", change.New)
+	}
+
+	file, err := os.OpenFile(
+		filepath.Join(s.config.DataDir, configAuditFileName),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0600,
+	)
+	if err != nil {
+		s.logger.Error("failed to open the config audit log", "err", err)
+
+		return
+	}
+
+	defer file.Close()
+
+	now := time.Now().UTC()
+	encoder := json.NewEncoder(file)
+
+	for _, change := range changes {
+		if err := encoder.Encode(&configAuditEntry{
+			Time:         now,
+			Source:       source,
+			ConfigChange: *change,
+		}); err != nil {
+			s.logger.Error("failed to write the config audit log", "err", err)
+
+			return
+		}
+	}
+}
+
+// runReloadSignalLoop reloads the configuration file on every SIGHUP, until stopReloadSignalLoop is called
+func (s *Server) runReloadSignalLoop() {
+	s.reload.sigCh = make(chan os.Signal, 1)
+	signal.Notify(s.reload.sigCh, syscall.SIGHUP)
+
+	go func(sigCh <-chan os.Signal) {
+		for range sigCh {
+			if _, err := s.ReloadConfig("SIGHUP"); err != nil {
+				s.logger.Error("failed to reload the config", "err", err)
+			}
+		}
+	}(s.reload.sigCh)
+}
+
+// stopReloadSignalLoop stops reloading the configuration file on SIGHUP
+func (s *Server) stopReloadSignalLoop() {
+	if s.reload.sigCh == nil {
+		return
+	}
+
+	signal.Stop(s.reload.sigCh)
+	close(s.reload.sigCh)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/archive"
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/crypto"
@@ -28,6 +29,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
 	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -43,6 +45,14 @@ type Server struct {
 	state        state.State
 	stateStorage itrie.Storage
 
+	// stateBuffer keeps the committed tries in memory between flushes to disk,
+	// nil if the state is written to disk on every block
+	stateBuffer *itrie.BufferedStorage
+	stateFlush  stateFlushStatus
+
+	// stateSnapshots is the flat snapshot of the state, nil if disabled
+	stateSnapshots *itrie.SnapshotTree
+
 	consensus consensus.Consensus
 
 	// blockchain stack
@@ -63,6 +73,9 @@ type Server struct {
 
 	// transaction pool
 	txpool *txpool.TxPool
+	// txpoolReorgSub is the reorgs subscription returning the transactions
+	// of the reorganized blocks to the pool
+	txpoolReorgSub *blockchain.BusSubscription
 
 	serverMetrics *serverMetrics
 
@@ -73,6 +86,9 @@ type Server struct {
 
 	// restore
 	restoreProgression *progress.ProgressionWrapper
+
+	// reload is the configuration applied at runtime
+	reload configReload
 }
 
 var dirPaths = []string{
@@ -174,26 +190,79 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// the snapshot is written to disk directly, it is flattened behind the head
+	snapshotStorage, _ := stateStorage.(itrie.SnapshotStorage)
+
+	if m.config.StateCommitInterval > 1 {
+		m.stateBuffer = itrie.NewBufferedStorage(stateStorage)
+		stateStorage = m.stateBuffer
+	}
+
 	m.stateStorage = stateStorage
 
 	st := itrie.NewState(stateStorage)
+	st.RecordPreimages = config.RecordPreimages
+
+	if config.StateSnapshot && snapshotStorage != nil {
+		m.stateSnapshots = itrie.NewSnapshotTree(snapshotStorage, stateStorage, logger)
+		st.Snapshots = m.stateSnapshots
+	}
+
 	m.state = st
 
 	m.executor = state.NewExecutor(config.Chain.Params, st, logger)
 	m.executor.SetRuntime(precompiled.NewPrecompiled())
 	m.executor.SetRuntime(evm.NewEVM())
+	m.executor.CollectOpcodeStats = config.OpcodeStats
+	m.executor.PrefetchWorkers = config.StatePrefetchWorkers
 
 	// compute the genesis root state
 	genesisRoot := m.executor.WriteGenesis(config.Chain.Genesis.Alloc)
+
+	// the allocations of a huge genesis are streamed, instead of being held in memory
+	if source := config.Chain.Genesis.AllocSource; source != nil {
+		if genesisRoot, err = m.executor.WriteGenesisStream(source); err != nil {
+			return nil, fmt.Errorf("failed to write the genesis allocations: %w", err)
+		}
+	}
+
 	config.Chain.Genesis.StateRoot = genesisRoot
 
 	// blockchain object
-	m.blockchain, err = blockchain.NewBlockchain(logger, m.config.DataDir, config.Chain, nil, m.executor)
+	m.blockchain, err = blockchain.NewBlockchain(
+		logger,
+		m.config.DataDir,
+		m.config.DBEngine,
+		config.Chain,
+		nil,
+		m.executor,
+		m.serverMetrics.blockchain,
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	m.executor.GetHash = m.blockchain.GetHashHelper
+	m.blockchain.SetStorageCompression(m.config.StorageCompression)
+	m.blockchain.SetCompactReceipts(m.config.CompactReceipts)
+
+	// the finalized blocks are moved out of leveldb to the freezer
+	if m.config.FreezerThreshold > 0 {
+		freezer, err := storage.NewFreezer(filepath.Join(m.config.DataDir, "blockchain", "ancient"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the freezer: %w", err)
+		}
+
+		m.blockchain.SetFreezer(freezer, m.config.FreezerThreshold)
+	}
+
+	// a gateway node keeps the bodies and the receipts of the recent blocks only
+	if m.config.Syncer != nil && m.config.Syncer.Mode == syncer.ModeGateway {
+		m.blockchain.SetRetainedBlocks(m.config.Syncer.RetainedBlocks)
+	}
+
+	m.blockchain.SetTxLookupLimit(m.config.TxLookupLimit)
+	m.blockchain.SetFutureBlockTolerance(m.config.FutureBlockTolerance)
 
 	{
 		hub := &txpoolHub{
@@ -209,17 +278,36 @@ func NewServer(config *Config) (*Server, error) {
 			m.network,
 			m.serverMetrics.txpool,
 			&txpool.Config{
-				Sealing:    m.config.Seal,
-				MaxSlots:   m.config.MaxSlots,
-				PriceLimit: m.config.PriceLimit,
+				Sealing:         m.config.Seal,
+				MaxSlots:        m.config.MaxSlots,
+				PriceLimit:      m.config.PriceLimit,
+				ExemptAddresses: m.config.ExemptAddresses,
+				LocalAddresses:  m.config.LocalAddresses,
+				FutureTxTypes:   m.config.FutureTxTypes,
+				OperatorAccount: m.config.OperatorAccount,
+				NonceReservationsPath: filepath.Join(
+					m.config.DataDir,
+					"blockchain",
+					txpool.NonceReservationsFileName,
+				),
+				SenderBansPath: filepath.Join(
+					m.config.DataDir,
+					"blockchain",
+					txpool.SenderBansFileName,
+				),
+				JournalPath:     m.txPoolJournalPath(),
+				JournalInterval: m.config.TxPoolJournalInterval,
+				PriceBump:       m.config.TxPoolPriceBump,
+				MaxAccountTxs:   m.config.TxPoolMaxAccountTxs,
+				MaxEnqueued:     m.config.TxPoolMaxEnqueued,
 			},
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		// use the eip155 signer
-		signer := crypto.NewEIP155Signer(uint64(m.config.Chain.Params.ChainID))
+		// use the london signer, recovering the legacy transactions as the eip155 signer
+		signer := crypto.NewLondonSigner(uint64(m.config.Chain.Params.ChainID))
 		m.txpool.SetSigner(signer)
 	}
 
@@ -243,6 +331,19 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// re-execute the blocks whose state wasn't written to disk before the node stopped,
+	// a light node has no state
+	if m.config.Syncer == nil || m.config.Syncer.Mode != syncer.ModeLight {
+		if err := m.recoverState(); err != nil {
+			return nil, err
+		}
+
+		// the snapshot is generated from the head state if it doesn't lead to it
+		if m.stateSnapshots != nil {
+			m.stateSnapshots.Load(m.blockchain.Header().StateRoot)
+		}
+	}
+
 	// setup and start grpc server
 	if err := m.setupGRPC(); err != nil {
 		return nil, err
@@ -252,6 +353,18 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// dial the trusted sync peers, the blocks are downloaded from them in priority,
+	// and the archive peers the missing bodies and receipts are fetched from
+	if m.config.Syncer != nil {
+		for _, peerInfo := range m.config.Syncer.TrustedPeers {
+			m.network.JoinPeerInfo(peerInfo)
+		}
+
+		for _, peerInfo := range m.config.Syncer.ArchivePeers {
+			m.network.JoinPeerInfo(peerInfo)
+		}
+	}
+
 	// setup and start jsonrpc server
 	if err := m.setupJSONRPC(); err != nil {
 		return nil, err
@@ -269,6 +382,20 @@ func NewServer(config *Config) (*Server, error) {
 
 	m.txpool.Start()
 
+	m.txpoolReorgSub = m.blockchain.SubscribeBus(blockchain.DefaultBusBuffer, blockchain.TopicReorgs)
+	go m.runTxPoolReorgLoop(m.txpoolReorgSub)
+
+	if m.stateBuffer != nil {
+		m.stateFlush.sub = m.blockchain.SubscribeEvents()
+		go m.runStateFlushLoop(m.stateFlush.sub)
+	}
+
+	m.reload.current = reloadableConfig(config)
+
+	if config.LoadReloadableConfig != nil {
+		m.runReloadSignalLoop()
+	}
+
 	return m, nil
 }
 
@@ -284,6 +411,14 @@ func (s *Server) restoreChain() error {
 	return nil
 }
 
+// runTxPoolReorgLoop resets the pool with the chain reorganizations,
+// the other blocks are processed by the consensus as they are written
+func (s *Server) runTxPoolReorgLoop(sub *blockchain.BusSubscription) {
+	for evnt := range sub.EventCh() {
+		s.txpool.ResetWithReorg(evnt.Reorg)
+	}
+}
+
 type txpoolHub struct {
 	state state.State
 	*blockchain.Blockchain
@@ -372,6 +507,20 @@ func (s *Server) setupSecretsManager() error {
 	return nil
 }
 
+// txPoolJournalPath returns the file the txpool journals its pending transactions to,
+// empty if the journal is disabled
+func (s *Server) txPoolJournalPath() string {
+	if s.config.TxPoolJournalInterval == 0 {
+		return ""
+	}
+
+	if s.config.TxPoolJournalPath != "" {
+		return s.config.TxPoolJournalPath
+	}
+
+	return filepath.Join(s.config.DataDir, "blockchain", txpool.TxJournalFileName)
+}
+
 // setupConsensus sets up the consensus mechanism
 func (s *Server) setupConsensus() error {
 	engineName := s.config.Chain.Params.GetEngine()
@@ -392,6 +541,13 @@ func (s *Server) setupConsensus() error {
 		Path:   filepath.Join(s.config.DataDir, "consensus"),
 	}
 
+	syncerConfig := &syncer.Config{}
+	if s.config.Syncer != nil {
+		*syncerConfig = *s.config.Syncer
+	}
+
+	syncerConfig.Metrics = s.serverMetrics.syncer
+
 	consensus, err := engine(
 		&consensus.Params{
 			Context:        context.Background(),
@@ -406,6 +562,11 @@ func (s *Server) setupConsensus() error {
 			Metrics:        s.serverMetrics.consensus,
 			SecretsManager: s.secretsManager,
 			BlockTime:      s.config.BlockTime,
+			BlockDeadline:  s.config.BlockDeadline,
+			DryRun:         s.config.IBFTDryRun,
+			Builders:       s.config.IBFTBuilderKeys,
+			BuilderTimeout: s.config.IBFTBuilderTimeout,
+			Syncer:         syncerConfig,
 		},
 	)
 
@@ -418,10 +579,22 @@ func (s *Server) setupConsensus() error {
 	return nil
 }
 
+// upstreamFetchTimeout is the time the bodies and the receipts not stored locally are fetched within
+const upstreamFetchTimeout = 30 * time.Second
+
+// upstreamFetcher fetches the bodies and the receipts not stored locally from the sync peers
+type upstreamFetcher interface {
+	FetchBody(ctx context.Context, header *types.Header) (*types.Body, error)
+	FetchReceipts(ctx context.Context, header *types.Header) ([]*types.Receipt, error)
+}
+
 type jsonRPCHub struct {
 	state              state.State
 	restoreProgression *progress.ProgressionWrapper
 
+	// upstream fetches the bodies and the receipts missing in the light and the gateway modes, nil otherwise
+	upstream upstreamFetcher
+
 	*blockchain.Blockchain
 	*txpool.TxPool
 	*state.Executor
@@ -435,6 +608,61 @@ func (j *jsonRPCHub) GetPeers() int {
 	return len(j.Server.Peers())
 }
 
+// GetBlockByHash returns the block with the given hash,
+// its body is fetched from the sync peers if it isn't stored locally
+func (j *jsonRPCHub) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	block, ok := j.Blockchain.GetBlockByHash(hash, full)
+
+	return j.withUpstreamBody(block, ok)
+}
+
+// GetBlockByNumber returns the canonical block with the given number,
+// its body is fetched from the sync peers if it isn't stored locally
+func (j *jsonRPCHub) GetBlockByNumber(number uint64, full bool) (*types.Block, bool) {
+	block, ok := j.Blockchain.GetBlockByNumber(number, full)
+
+	return j.withUpstreamBody(block, ok)
+}
+
+// withUpstreamBody fills the block found without its body with the body fetched from the sync peers
+func (j *jsonRPCHub) withUpstreamBody(block *types.Block, ok bool) (*types.Block, bool) {
+	if ok || block == nil || j.upstream == nil {
+		return block, ok
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), upstreamFetchTimeout)
+	defer cancel()
+
+	body, err := j.upstream.FetchBody(ctx, block.Header)
+	if err != nil {
+		return block, false
+	}
+
+	block.Transactions = body.Transactions
+	block.Uncles = body.Uncles
+
+	return block, true
+}
+
+// GetReceiptsByHash returns the receipts of the block with the given hash,
+// they are fetched from the sync peers if they aren't stored locally
+func (j *jsonRPCHub) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	receipts, err := j.Blockchain.GetReceiptsByHash(hash)
+	if err == nil || j.upstream == nil {
+		return receipts, err
+	}
+
+	header, ok := j.Blockchain.GetHeaderByHash(hash)
+	if !ok {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), upstreamFetchTimeout)
+	defer cancel()
+
+	return j.upstream.FetchReceipts(ctx, header)
+}
+
 func (j *jsonRPCHub) getState(root types.Hash, slot []byte) ([]byte, error) {
 	// the values in the trie are the hashed objects of the keys
 	key := keccak.Keccak256(nil, slot)
@@ -479,6 +707,16 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 		return nil, err
 	}
 
+	// the storage is read from the flat snapshot of the state if it has it
+	if storage, ok := j.storageAt(root, addr, account.Root); ok {
+		obj, ok := storage.Get(keccak.Keccak256(nil, slot.Bytes()))
+		if !ok {
+			return nil, jsonrpc.ErrStateNotFound
+		}
+
+		return obj, nil
+	}
+
 	obj, err := j.getState(account.Root, slot.Bytes())
 
 	if err != nil {
@@ -488,6 +726,21 @@ func (j *jsonRPCHub) GetStorage(root types.Hash, addr types.Address, slot types.
 	return obj, nil
 }
 
+// storageAt returns the reader of the storage of the account from the flat snapshot of the state, if any
+func (j *jsonRPCHub) storageAt(root types.Hash, addr types.Address, storageRoot types.Hash) (state.StorageReader, bool) {
+	snap, err := j.state.NewSnapshotAt(root)
+	if err != nil {
+		return nil, false
+	}
+
+	flat, ok := snap.(state.StorageSnapshot)
+	if !ok {
+		return nil, false
+	}
+
+	return flat.StorageAt(keccak.Keccak256(nil, addr.Bytes()), storageRoot)
+}
+
 func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	res, ok := j.state.GetCode(hash)
 
@@ -498,6 +751,41 @@ func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	return res, nil
 }
 
+// GetProof returns the trie nodes proving the keys against the given root
+func (j *jsonRPCHub) GetProof(root types.Hash, keys [][]byte) (map[types.Hash][]byte, error) {
+	prover, ok := j.state.(interface {
+		Prove(root types.Hash, keys [][]byte, proofDB map[types.Hash][]byte) error
+	})
+	if !ok {
+		return nil, fmt.Errorf("state does not support proofs")
+	}
+
+	// the values in the trie are the hashed objects of the keys
+	hashedKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		hashedKeys[i] = keccak.Keccak256(nil, key)
+	}
+
+	proofDB := map[types.Hash][]byte{}
+	if err := prover.Prove(root, hashedKeys, proofDB); err != nil {
+		return nil, err
+	}
+
+	return proofDB, nil
+}
+
+// GetKeyPreimage returns the address or the storage slot hashed to the given trie key, if it was recorded
+func (j *jsonRPCHub) GetKeyPreimage(hash types.Hash) ([]byte, bool) {
+	recorder, ok := j.state.(interface {
+		GetPreimage(hash types.Hash) ([]byte, bool)
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return recorder.GetPreimage(hash)
+}
+
 func (j *jsonRPCHub) ApplyTxn(
 	header *types.Header,
 	txn *types.Transaction,
@@ -513,11 +801,77 @@ func (j *jsonRPCHub) ApplyTxn(
 		return
 	}
 
-	result, err = transition.Apply(txn)
+	result, err = transition.ApplyCall(txn, false)
 
 	return
 }
 
+// ApplyCalls applies the calls one after the other on the state of the header. Every call sees the state changes
+// of the previous ones if chained, or the state of the header only otherwise. The nonce of a call is the one
+// of its sender in that state, the application error of each call is returned apart from its result
+func (j *jsonRPCHub) ApplyCalls(
+	header *types.Header,
+	calls []*types.Transaction,
+	chained bool,
+) ([]*runtime.ExecutionResult, []error, error) {
+	blockCreator, err := j.GetConsensus().GetBlockCreator(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transition, err := j.BeginTxn(header.StateRoot, header, blockCreator)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		results = make([]*runtime.ExecutionResult, len(calls))
+		errs    = make([]error, len(calls))
+	)
+
+	for i, call := range calls {
+		call.Nonce = transition.GetNonce(call.From)
+
+		results[i], errs[i] = transition.ApplyCall(call, chained)
+	}
+
+	return results, errs, nil
+}
+
+// PreviewBlock packs the next block on top of the current head with the transactions
+// in the pool. Neither the pool nor the state are modified
+func (j *jsonRPCHub) PreviewBlock() (*types.Block, []*types.Receipt, error) {
+	parent := j.Blockchain.Header()
+
+	header := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Timestamp:  uint64(time.Now().Unix()),
+	}
+
+	gasLimit, err := j.CalculateGasLimit(header.Number)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header.GasLimit = gasLimit
+	j.SetBaseFee(header, parent)
+
+	// the block creator is unknown before sealing, the fees are computed from the receipts
+	transition, err := j.BeginTxn(parent.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txs := j.TxPool.Pack(gasLimit, transition)
+	header.GasUsed = transition.TotalGas()
+
+	return &types.Block{
+		Header:       header,
+		Transactions: txs,
+	}, transition.Receipts(), nil
+}
+
 func (j *jsonRPCHub) GetSyncProgression() *progress.Progression {
 	// restore progression
 	if restoreProg := j.restoreProgression.GetProgression(); restoreProg != nil {
@@ -546,6 +900,13 @@ func (s *Server) setupJSONRPC() error {
 		Server:             s.network,
 	}
 
+	// the light and the gateway nodes fetch the bodies and the receipts they don't store from the sync peers
+	if s.config.Syncer != nil && s.config.Syncer.Mode != syncer.ModeFull {
+		if upstream, ok := s.consensus.(upstreamFetcher); ok {
+			hub.upstream = upstream
+		}
+	}
+
 	conf := &jsonrpc.Config{
 		Store:                    hub,
 		Addr:                     s.config.JSONRPC.JSONRPCAddr,
@@ -554,6 +915,8 @@ func (s *Server) setupJSONRPC() error {
 		PriceLimit:               s.config.PriceLimit,
 		BatchLengthLimit:         s.config.JSONRPC.BatchLengthLimit,
 		BlockRangeLimit:          s.config.JSONRPC.BlockRangeLimit,
+		APIKeys:                  s.config.JSONRPC.APIKeys,
+		Metrics:                  s.serverMetrics.jsonrpc,
 	}
 
 	srv, err := jsonrpc.NewJSONRPC(s.logger, conf)
@@ -598,6 +961,9 @@ func (s *Server) JoinPeer(rawPeerMultiaddr string) error {
 
 // Close closes the Minimal server (blockchain, networking, consensus)
 func (s *Server) Close() {
+	// Stop reloading the config on SIGHUP
+	s.stopReloadSignalLoop()
+
 	// Close the blockchain layer
 	if err := s.blockchain.Close(); err != nil {
 		s.logger.Error("failed to close blockchain", "err", err.Error())
@@ -613,7 +979,15 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close consensus", "err", err.Error())
 	}
 
-	// Close the state storage
+	// Close the state storage, writing the buffered state to disk
+	s.stopStateFlushLoop()
+
+	if s.stateSnapshots != nil {
+		if err := s.stateSnapshots.Close(s.blockchain.Header().StateRoot); err != nil {
+			s.logger.Error("failed to write the state snapshot", "err", err.Error())
+		}
+	}
+
 	if err := s.stateStorage.Close(); err != nil {
 		s.logger.Error("failed to close storage for trie", "err", err.Error())
 	}
@@ -625,6 +999,10 @@ func (s *Server) Close() {
 	}
 
 	// close the txpool's main loop
+	if s.txpoolReorgSub != nil {
+		s.txpoolReorgSub.Unsubscribe()
+	}
+
 	s.txpool.Close()
 }
 
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// stateFlushStatus tracks the writes of the buffered state to disk
+type stateFlushStatus struct {
+	sync.Mutex
+
+	// lastBlock is the latest block whose state is on disk
+	lastBlock uint64
+	// sub is the blockchain subscription triggering the flushes
+	sub blockchain.Subscription
+}
+
+// hasState checks if the state with the given root is available
+func (s *Server) hasState(root types.Hash) bool {
+	_, err := s.state.NewSnapshotAt(root)
+
+	return err == nil
+}
+
+// recoverState re-executes the blocks whose state is missing,
+// and writes the recovered state to disk
+func (s *Server) recoverState() error {
+	recovered, err := s.blockchain.RecoverState(s.hasState)
+	if err != nil {
+		return err
+	}
+
+	if recovered > 0 {
+		s.logger.Info("recovered state", "blocks", recovered)
+	}
+
+	s.flushState()
+
+	return nil
+}
+
+// flushState writes the state buffered in memory to disk. It returns the
+// latest block whose state is on disk, and the number of entries written
+func (s *Server) flushState() (uint64, uint64) {
+	s.stateFlush.Lock()
+	defer s.stateFlush.Unlock()
+
+	// the state of a block is committed before the block is written,
+	// so the state of the current head is part of the flush
+	head := s.blockchain.Header().Number
+
+	if s.stateBuffer == nil {
+		// the state is written on every block
+		s.stateFlush.lastBlock = head
+
+		return head, 0
+	}
+
+	written := s.stateBuffer.Flush()
+	s.stateFlush.lastBlock = head
+
+	s.logger.Debug("flushed state", "block", head, "entries", written)
+
+	return head, written
+}
+
+// runStateFlushLoop flushes the buffered state every StateCommitInterval blocks
+func (s *Server) runStateFlushLoop(sub blockchain.Subscription) {
+	for {
+		evnt := sub.GetEvent()
+		if evnt == nil {
+			return
+		}
+
+		if len(evnt.NewChain) == 0 {
+			continue
+		}
+
+		head := evnt.Header().Number
+
+		s.stateFlush.Lock()
+		lastBlock := s.stateFlush.lastBlock
+		s.stateFlush.Unlock()
+
+		if head >= lastBlock+s.config.StateCommitInterval {
+			s.flushState()
+		}
+	}
+}
+
+// stopStateFlushLoop stops the periodic flushes, the buffered state
+// is written to disk when the state storage is closed
+func (s *Server) stopStateFlushLoop() {
+	s.stateFlush.Lock()
+	defer s.stateFlush.Unlock()
+
+	if s.stateFlush.sub != nil {
+		s.stateFlush.sub.Close()
+		s.stateFlush.sub = nil
+	}
+}
@@ -9,11 +9,28 @@ import (
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/syncer"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
+var (
+	errResyncUnsupported     = errors.New("the consensus doesn't support resyncing")
+	errSyncVerifyUnsupported = errors.New("the consensus doesn't support verifying synced blocks")
+	errElasticWithoutTarget  = errors.New("the elastic gas limit adjustment requires a block gas target")
+)
+
+// resyncer is implemented by the consensus mechanisms syncing the blocks from the peers
+type resyncer interface {
+	Resync(from uint64, force bool) (uint64, error)
+}
+
+// syncVerifier is implemented by the consensus mechanisms syncing the blocks from the peers
+type syncVerifier interface {
+	VerifyBlocks(ctx context.Context, from, to uint64, peerID peer.ID) (*syncer.VerifyResult, error)
+}
+
 type systemService struct {
 	proto.UnimplementedSystemServer
 
@@ -27,6 +44,8 @@ type systemService struct {
 // Current: { Number: <blockNumber>; Hash: <headerHash> }
 //
 // P2PAddr: <libp2pAddress>
+//
+// Snapshot: { Enabled: <enabled>; Ready: <ready>; Generating: <generating>; Progress: <percents>; ... }
 func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto.ServerStatus, error) {
 	header := s.server.blockchain.Header()
 
@@ -36,7 +55,22 @@ func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto
 			Number: int64(header.Number),
 			Hash:   header.Hash.String(),
 		},
-		P2PAddr: common.AddrInfoToString(s.server.network.AddrInfo()),
+		P2PAddr:  common.AddrInfoToString(s.server.network.AddrInfo()),
+		Snapshot: &proto.ServerStatus_Snapshot{},
+	}
+
+	if s.server.stateSnapshots != nil {
+		snapshot := s.server.stateSnapshots.Status()
+
+		status.Snapshot = &proto.ServerStatus_Snapshot{
+			Enabled:    true,
+			Ready:      snapshot.Ready,
+			Generating: snapshot.Generating,
+			Progress:   snapshot.Progress,
+			Accounts:   snapshot.Accounts,
+			Slots:      snapshot.Slots,
+			Root:       snapshot.Root.String(),
+		}
 	}
 
 	return status, nil
@@ -171,6 +205,181 @@ func (s *systemService) BlockByNumber(
 	}, nil
 }
 
+// FlushState implements the FlushState operator service
+func (s *systemService) FlushState(ctx context.Context, req *empty.Empty) (*proto.FlushStateResponse, error) {
+	number, written := s.server.flushState()
+
+	return &proto.FlushStateResponse{
+		Number:  number,
+		Written: written,
+	}, nil
+}
+
+// PeersLatency implements the 'peers latency' operator service
+func (s *systemService) PeersLatency(
+	ctx context.Context,
+	req *proto.PeersLatencyRequest,
+) (*proto.PeersLatencyResponse, error) {
+	count := req.Count
+	if count == 0 {
+		count = defaultLatencyPings
+	}
+
+	if count > maxLatencyPings {
+		return nil, fmt.Errorf("at most %d pings can be sent to each peer", maxLatencyPings)
+	}
+
+	return &proto.PeersLatencyResponse{
+		Peers: s.server.measurePeerLatencies(ctx, int(count)),
+	}, nil
+}
+
+// Resync implements the 'server resync' operator service
+func (s *systemService) Resync(ctx context.Context, req *proto.ResyncRequest) (*proto.ResyncResponse, error) {
+	resyncer, ok := s.server.consensus.(resyncer)
+	if !ok {
+		return nil, errResyncUnsupported
+	}
+
+	discarded, err := resyncer.Resync(req.From, req.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ResyncResponse{
+		Discarded: discarded,
+		Head:      s.server.blockchain.Header().Number,
+	}, nil
+}
+
+// SyncVerify implements the 'sync verify' operator service
+func (s *systemService) SyncVerify(ctx context.Context, req *proto.SyncVerifyRequest) (*proto.SyncVerifyResponse, error) {
+	verifier, ok := s.server.consensus.(syncVerifier)
+	if !ok {
+		return nil, errSyncVerifyUnsupported
+	}
+
+	var peerID peer.ID
+
+	if req.PeerId != "" {
+		id, err := peer.Decode(req.PeerId)
+		if err != nil {
+			return nil, err
+		}
+
+		peerID = id
+	}
+
+	result, err := verifier.VerifyBlocks(ctx, req.From, req.To, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.SyncVerifyResponse{
+		PeerId:     result.PeerID.String(),
+		Verified:   result.Verified,
+		Mismatches: result.Mismatches,
+		ElapsedMs:  uint64(result.Elapsed.Milliseconds()),
+		VerifyMs:   uint64(result.VerifyTime.Milliseconds()),
+	}
+
+	if result.Err != nil {
+		resp.Error = result.Err.Error()
+	}
+
+	return resp, nil
+}
+
+// GetBlockGasTarget implements the 'server gas-target' operator service
+func (s *systemService) GetBlockGasTarget(
+	ctx context.Context,
+	req *empty.Empty,
+) (*proto.BlockGasTargetResponse, error) {
+	return s.getBlockGasTarget()
+}
+
+// SetBlockGasTarget implements the 'server gas-target' operator service, setting the target
+func (s *systemService) SetBlockGasTarget(
+	ctx context.Context,
+	req *proto.SetBlockGasTargetRequest,
+) (*proto.BlockGasTargetResponse, error) {
+	if req.Elastic && req.Target == 0 {
+		return nil, errElasticWithoutTarget
+	}
+
+	s.server.blockchain.SetBlockGasTarget(req.Target, req.Elastic)
+
+	return s.getBlockGasTarget()
+}
+
+// ReloadConfig reads the config file again and applies the changes of the settings reloadable at runtime
+func (s *systemService) ReloadConfig(context.Context, *empty.Empty) (*proto.ReloadConfigResponse, error) {
+	changes, err := s.server.ReloadConfig("rpc")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ReloadConfigResponse{
+		Changes: make([]*proto.ConfigChange, 0, len(changes)),
+	}
+
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, &proto.ConfigChange{
+			Key: change.Key,
+			Old: change.Old,
+			New: change.New,
+		})
+	}
+
+	return resp, nil
+}
+
+// GossipStats implements the 'peers gossip' operator service
+func (s *systemService) GossipStats(context.Context, *empty.Empty) (*proto.GossipStatsResponse, error) {
+	stats := s.server.network.GossipStats()
+
+	resp := &proto.GossipStatsResponse{
+		Topics:        make([]*proto.GossipTopicStats, 0, len(stats.Topics)),
+		IhaveReceived: stats.IHaveReceived,
+		IhaveSent:     stats.IHaveSent,
+		IwantReceived: stats.IWantReceived,
+		IwantSent:     stats.IWantSent,
+	}
+
+	for _, topic := range stats.Topics {
+		resp.Topics = append(resp.Topics, &proto.GossipTopicStats{
+			Topic:         topic.Topic,
+			MeshPeers:     topic.MeshPeers,
+			Delivered:     topic.Delivered,
+			Duplicates:    topic.Duplicates,
+			DeliveryP50Us: uint64(topic.DeliveryP50.Microseconds()),
+			DeliveryP90Us: uint64(topic.DeliveryP90.Microseconds()),
+			DeliveryMaxUs: uint64(topic.DeliveryMax.Microseconds()),
+		})
+	}
+
+	return resp, nil
+}
+
+// getBlockGasTarget returns the block gas target, with the gas limits of the head and of the next block
+func (s *systemService) getBlockGasTarget() (*proto.BlockGasTargetResponse, error) {
+	header := s.server.blockchain.Header()
+
+	nextGasLimit, err := s.server.blockchain.CalculateGasLimit(header.Number + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	target, elastic := s.server.blockchain.BlockGasTarget()
+
+	return &proto.BlockGasTargetResponse{
+		Target:       target,
+		Elastic:      elastic,
+		GasLimit:     header.GasLimit,
+		NextGasLimit: nextGasLimit,
+	}, nil
+}
+
 func (s *systemService) Export(req *proto.ExportRequest, stream proto.System_ExportServer) error {
 	var (
 		from uint64 = 0
@@ -20,6 +20,9 @@ const (
 
 	TxGas                 uint64 = 21000 // Per transaction not creating a contract
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract
+
+	// genesisCommitInterval is the number of streamed genesis allocations committed at once
+	genesisCommitInterval = 10000
 )
 
 var emptyCodeHashTwo = types.BytesToHash(crypto.Keccak256(nil))
@@ -38,6 +41,13 @@ type Executor struct {
 	GetHash  GetHashByNumberHelper
 
 	PostHook func(txn *Transition)
+
+	// CollectOpcodeStats enables the statistics of the opcodes executed by the processed blocks
+	CollectOpcodeStats bool
+
+	// PrefetchWorkers is the number of goroutines loading the accounts of a processed block
+	// from the state while its transactions execute, the prefetching being disabled if zero
+	PrefetchWorkers int
 }
 
 // NewExecutor creates a new executor
@@ -55,26 +65,71 @@ func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) t
 	txn := NewTxn(e.state, snap)
 
 	for addr, account := range alloc {
-		if account.Balance != nil {
-			txn.AddBalance(addr, account.Balance)
-		}
+		writeGenesisAccount(txn, addr, account)
+	}
 
-		if account.Nonce != 0 {
-			txn.SetNonce(addr, account.Nonce)
-		}
+	_, root := txn.Commit(false)
 
-		if len(account.Code) != 0 {
-			txn.SetCode(addr, account.Code)
-		}
+	return types.BytesToHash(root)
+}
+
+// WriteGenesisStream writes the genesis allocations streamed by the source, for the genesis too large
+// to be held in memory. The trie is committed every genesisCommitInterval allocations, so that only
+// the accounts of the current interval are held by the transaction
+func (e *Executor) WriteGenesisStream(source chain.GenesisAllocSource) (types.Hash, error) {
+	snap := e.state.NewSnapshot()
+	txn := NewTxn(e.state, snap)
+
+	var (
+		root    []byte
+		written = 0
+		total   = source.Len()
+	)
 
-		for key, value := range account.Storage {
-			txn.SetState(addr, key, value)
+	err := source.ForEach(func(addr types.Address, account *chain.GenesisAccount) error {
+		writeGenesisAccount(txn, addr, account)
+
+		written++
+
+		if written%genesisCommitInterval != 0 {
+			return nil
 		}
+
+		snap, root = txn.Commit(false)
+		txn = NewTxn(e.state, snap)
+
+		e.logger.Info("writing genesis allocations", "written", written, "total", total)
+
+		return nil
+	})
+	if err != nil {
+		return types.Hash{}, err
 	}
 
-	_, root := txn.Commit(false)
+	_, root = txn.Commit(false)
 
-	return types.BytesToHash(root)
+	e.logger.Info("genesis allocations written", "total", written, "root", types.BytesToHash(root))
+
+	return types.BytesToHash(root), nil
+}
+
+// writeGenesisAccount writes the genesis allocation of the account
+func writeGenesisAccount(txn *Txn, addr types.Address, account *chain.GenesisAccount) {
+	if account.Balance != nil {
+		txn.AddBalance(addr, account.Balance)
+	}
+
+	if account.Nonce != 0 {
+		txn.SetNonce(addr, account.Nonce)
+	}
+
+	if len(account.Code) != 0 {
+		txn.SetCode(addr, account.Code)
+	}
+
+	for key, value := range account.Storage {
+		txn.SetState(addr, key, value)
+	}
 }
 
 // SetRuntime adds a runtime to the runtime set
@@ -101,6 +156,13 @@ func (e *Executor) ProcessBlock(
 
 	txn.block = block
 
+	stopPrefetch := e.prefetch(parentRoot, block, blockCreator)
+	defer stopPrefetch()
+
+	if e.CollectOpcodeStats {
+		txn.opcodeStats = &runtime.OpcodeStats{}
+	}
+
 	for _, t := range block.Transactions {
 		if t.ExceedsBlockGasLimit(block.Header.GasLimit) {
 			if err := txn.WriteFailedReceipt(t); err != nil {
@@ -118,6 +180,52 @@ func (e *Executor) ProcessBlock(
 	return txn, nil
 }
 
+// prefetch loads the accounts of the block in the background, so that the execution of its transactions
+// finds them in the caches of the state storage rather than waiting on the disk.
+// The returned function stops the prefetching
+func (e *Executor) prefetch(root types.Hash, block *types.Block, blockCreator types.Address) func() {
+	prefetcher, ok := e.state.(Prefetcher)
+	if !ok || e.PrefetchWorkers <= 0 || len(block.Transactions) == 0 {
+		return func() {}
+	}
+
+	stopCh := make(chan struct{})
+
+	go prefetcher.Prefetch(root, prefetchAddresses(block, blockCreator), e.PrefetchWorkers, stopCh)
+
+	return func() {
+		close(stopCh)
+	}
+}
+
+// prefetchAddresses returns the distinct accounts read by the block: its creator, and the senders and
+// the recipients of its transactions. The storage slots aren't known ahead, as the access lists are not supported
+func prefetchAddresses(block *types.Block, blockCreator types.Address) []types.Address {
+	var (
+		addrs = make([]types.Address, 0, 2*len(block.Transactions)+1)
+		seen  = make(map[types.Address]struct{}, 2*len(block.Transactions)+1)
+	)
+
+	add := func(addr types.Address) {
+		if _, ok := seen[addr]; !ok {
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	add(blockCreator)
+
+	for _, tx := range block.Transactions {
+		add(tx.From)
+
+		if tx.To != nil {
+			add(*tx.To)
+		}
+	}
+
+	return addrs
+}
+
 // StateAt returns snapshot at given root
 func (e *Executor) State() State {
 	return e.state
@@ -156,6 +264,12 @@ func (e *Executor) BeginTxn(
 		ChainID:    int64(e.config.ChainID),
 	}
 
+	var baseFee uint64
+	if config.London {
+		baseFee = header.BaseFee
+		env2.BaseFee = types.BytesToHash(new(big.Int).SetUint64(baseFee).Bytes())
+	}
+
 	txn := &Transition{
 		logger:   e.logger,
 		r:        e,
@@ -165,9 +279,11 @@ func (e *Executor) BeginTxn(
 		auxState: e.state,
 		config:   config,
 		gasPool:  uint64(env2.GasLimit),
+		baseFee:  baseFee,
 
 		receipts: []*types.Receipt{},
 		totalGas: 0,
+		fees:     types.NewFees(),
 	}
 
 	return txn, nil
@@ -189,19 +305,57 @@ type Transition struct {
 	ctx     runtime.TxContext
 	gasPool uint64
 
+	// baseFee is the EIP-1559 base fee of the block, burned for each gas used. Zero before the London fork
+	baseFee uint64
+
+	// noBaseFee is set while applying a simulated call, the calls without gas price paying no base fee
+	noBaseFee bool
+
 	// result
 	receipts []*types.Receipt
 	totalGas uint64
+
+	// opcodeStats are the statistics of the executed opcodes, nil if not collected
+	opcodeStats *runtime.OpcodeStats
+
+	// fees are the fees paid by the applied transactions
+	fees *types.Fees
 }
 
 func (t *Transition) TotalGas() uint64 {
 	return t.totalGas
 }
 
+// OpcodeStats returns the statistics of the opcodes executed by the transition,
+// or nil if they aren't collected
+func (t *Transition) OpcodeStats() *runtime.OpcodeStats {
+	return t.opcodeStats
+}
+
 func (t *Transition) Receipts() []*types.Receipt {
 	return t.receipts
 }
 
+// Fees returns the fees paid by the transactions applied by the transition, and their distribution
+func (t *Transition) Fees() *types.Fees {
+	if t.fees == nil {
+		return types.NewFees()
+	}
+
+	return t.fees.Copy()
+}
+
+// recordFees adds the fees paid by a transaction to the fees of the transition
+func (t *Transition) recordFees(collected, validator, contractCreator *big.Int) {
+	if t.fees == nil {
+		t.fees = types.NewFees()
+	}
+
+	t.fees.Collected.Add(t.fees.Collected, collected)
+	t.fees.Validators.Add(t.fees.Validators, validator)
+	t.fees.ContractCreators.Add(t.fees.ContractCreators, contractCreator)
+}
+
 var emptyFrom = types.Address{}
 
 func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
@@ -221,6 +375,7 @@ func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash,
 		Logs:              t.state.Logs(),
+		TransactionType:   txn.Type,
 	}
 
 	receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
@@ -267,6 +422,7 @@ func (t *Transition) Write(txn *types.Transaction) error {
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash,
 		GasUsed:           result.GasUsed,
+		TransactionType:   txn.Type,
 	}
 
 	if t.config.Byzantium {
@@ -300,9 +456,16 @@ func (t *Transition) Write(txn *types.Transaction) error {
 
 // Commit commits the final result
 func (t *Transition) Commit() (Snapshot, types.Hash) {
-	s2, root := t.state.Commit(t.config.EIP155)
+	s2, root, _ := t.CommitWithDiff()
+
+	return s2, root
+}
+
+// CommitWithDiff commits the final result, and returns the accounts modified by the transition
+func (t *Transition) CommitWithDiff() (Snapshot, types.Hash, []*Object) {
+	s2, root, diff := t.state.CommitObjects(t.config.EIP155)
 
-	return s2, types.BytesToHash(root)
+	return s2, types.BytesToHash(root), diff
 }
 
 func (t *Transition) subGasPool(amount uint64) error {
@@ -340,6 +503,9 @@ func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		t.state.RevertToSnapshot(s)
 	}
 
+	// the transient storage lasts for the transaction only
+	t.state.ClearTransientStorage()
+
 	if t.r.PostHook != nil {
 		t.r.PostHook(t)
 	}
@@ -347,16 +513,73 @@ func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	return result, err
 }
 
+// ApplyCall applies the transaction like Apply for a simulated call, which doesn't consume the gas of the block,
+// so that every call of a sequence can use up to the block gas limit. The calls without gas price don't pay
+// the base fee either. The state changes of the call are reverted unless keepState is set, for the next calls to see them
+func (t *Transition) ApplyCall(msg *types.Transaction, keepState bool) (*runtime.ExecutionResult, error) {
+	var (
+		s       = t.state.Snapshot()
+		gasPool = t.gasPool
+	)
+
+	t.noBaseFee = true
+	result, err := t.Apply(msg)
+	t.noBaseFee = false
+
+	if !keepState {
+		t.state.RevertToSnapshot(s)
+	}
+
+	t.gasPool = gasPool
+
+	return result, err
+}
+
 // ContextPtr returns reference of context
 // This method is called only by test
 func (t *Transition) ContextPtr() *runtime.TxContext {
 	return &t.ctx
 }
 
+// msgBaseFee returns the base fee paid by the message, none for the simulated calls without gas price
+func (t *Transition) msgBaseFee(msg *types.Transaction) uint64 {
+	if t.noBaseFee && msg.MaxGasPrice().Sign() == 0 {
+		return 0
+	}
+
+	return t.baseFee
+}
+
+// checkDynamicFees checks the type of the message and that its gas price covers the base fee of the block
+func (t *Transition) checkDynamicFees(msg *types.Transaction) error {
+	if msg.Type == types.DynamicFeeTx {
+		if !t.config.London {
+			return fmt.Errorf("%w: 0x%x", types.ErrTxTypeNotSupported, byte(msg.Type))
+		}
+
+		if msg.GasTipCap.Cmp(msg.GasFeeCap) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
+	if msg.MaxGasPrice().Cmp(new(big.Int).SetUint64(t.msgBaseFee(msg))) < 0 {
+		return ErrFeeCapTooLow
+	}
+
+	return nil
+}
+
 func (t *Transition) subGasLimitPrice(msg *types.Transaction) error {
-	// deduct the upfront max gas cost
-	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
-	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
+	gas := new(big.Int).SetUint64(msg.Gas)
+
+	// the balance covers the max gas cost, of which the gas at the effective price is deducted upfront
+	maxGasCost := new(big.Int).Mul(msg.MaxGasPrice(), gas)
+	if balance := t.state.GetBalance(msg.From); balance.Cmp(maxGasCost) < 0 {
+		return ErrNotEnoughFundsForGas
+	}
+
+	upfrontGasCost := msg.EffectiveGasPrice(t.msgBaseFee(msg))
+	upfrontGasCost.Mul(upfrontGasCost, gas)
 
 	if err := t.state.SubBalance(msg.From, upfrontGasCost); err != nil {
 		if errors.Is(err, runtime.ErrNotEnoughFunds) {
@@ -389,6 +612,8 @@ var (
 	ErrIntrinsicGasOverflow  = fmt.Errorf("overflow in intrinsic gas calculation")
 	ErrNotEnoughIntrinsicGas = fmt.Errorf("not enough gas supplied for intrinsic gas costs")
 	ErrNotEnoughFunds        = fmt.Errorf("not enough funds for transfer with given value")
+	ErrTipAboveFeeCap        = fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	ErrFeeCapTooLow          = fmt.Errorf("max fee per gas less than block base fee")
 )
 
 type TransitionApplicationError struct {
@@ -427,8 +652,15 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	// 4. there is no overflow when calculating intrinsic gas
 	// 5. the purchased gas is enough to cover intrinsic usage
 	// 6. caller has enough balance to cover asset transfer for **topmost** call
+	//
+	// The type and the fees of the message are checked beforehand, against the base fee of the block
 	txn := t.state
 
+	if err := t.checkDynamicFees(msg); err != nil {
+		// a price below the base fee may cover the base fee of a later block
+		return nil, NewTransitionApplicationError(err, errors.Is(err, ErrFeeCapTooLow))
+	}
+
 	// 1. the nonce of the message caller is correct
 	if err := t.nonceCheck(msg); err != nil {
 		return nil, NewTransitionApplicationError(err, true)
@@ -462,7 +694,8 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(ErrNotEnoughFunds, true)
 	}
 
-	gasPrice := new(big.Int).Set(msg.GasPrice)
+	baseFee := t.msgBaseFee(msg)
+	gasPrice := msg.EffectiveGasPrice(baseFee)
 	value := new(big.Int).Set(msg.Value)
 
 	// Set the specific transaction fields in the context
@@ -484,8 +717,9 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	txn.AddBalance(msg.From, remaining)
 
-	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
+	// pay the coinbase the tip, the base fee being burned
+	collectedFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
+	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), msg.EffectiveTip(baseFee))
 
 	if IsContract(t, msg.To) {
 		ratio := big.NewInt(2) // ratio between reward for contract and validator
@@ -498,8 +732,12 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		validatorFee := new(big.Int)
 		validatorFee.Sub(coinbaseFee, contractFee)
 		txn.AddBalance(t.ctx.Coinbase, validatorFee)
+
+		t.recordFees(collectedFee, validatorFee, contractFee)
 	} else {
 		txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
+
+		t.recordFees(collectedFee, coinbaseFee, new(big.Int))
 	}
 
 	// return gas to the pool
@@ -754,6 +992,14 @@ func (t *Transition) GetStorage(addr types.Address, key types.Hash) types.Hash {
 	return t.state.GetState(addr, key)
 }
 
+func (t *Transition) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	return t.state.GetTransientState(addr, key)
+}
+
+func (t *Transition) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	t.state.SetTransientState(addr, key, value)
+}
+
 func (t *Transition) AccountExists(addr types.Address) bool {
 	return t.state.Exist(addr)
 }
@@ -0,0 +1,140 @@
+package itrie
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// BufferedStorage keeps the trie nodes and the code written to it in memory,
+// until they are flushed to the underlying storage. It allows committing the
+// tries of several blocks to disk at once, instead of on every block.
+// Whatever is not flushed is lost if the node stops without closing the storage
+type BufferedStorage struct {
+	storage Storage
+
+	lock  sync.RWMutex
+	nodes map[string][]byte
+	code  map[types.Hash][]byte
+}
+
+// NewBufferedStorage creates a buffered storage on top of the given storage
+func NewBufferedStorage(storage Storage) *BufferedStorage {
+	return &BufferedStorage{
+		storage: storage,
+		nodes:   map[string][]byte{},
+		code:    map[types.Hash][]byte{},
+	}
+}
+
+// bufferedBatch collects the nodes of a batch, which
+// are added to the buffer all at once on write
+type bufferedBatch struct {
+	storage *BufferedStorage
+	nodes   map[string][]byte
+}
+
+func (b *bufferedBatch) Put(k, v []byte) {
+	b.nodes[string(k)] = copyBytes(v)
+}
+
+func (b *bufferedBatch) Write() {
+	b.storage.lock.Lock()
+	defer b.storage.lock.Unlock()
+
+	for k, v := range b.nodes {
+		b.storage.nodes[k] = v
+	}
+}
+
+func (s *BufferedStorage) Put(k, v []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nodes[string(k)] = copyBytes(v)
+}
+
+func (s *BufferedStorage) Get(k []byte) ([]byte, bool) {
+	s.lock.RLock()
+	v, ok := s.nodes[string(k)]
+	s.lock.RUnlock()
+
+	if ok {
+		return v, true
+	}
+
+	return s.storage.Get(k)
+}
+
+func (s *BufferedStorage) Batch() Batch {
+	return &bufferedBatch{storage: s, nodes: map[string][]byte{}}
+}
+
+func (s *BufferedStorage) SetCode(hash types.Hash, code []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.code[hash] = copyBytes(code)
+}
+
+func (s *BufferedStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	s.lock.RLock()
+	code, ok := s.code[hash]
+	s.lock.RUnlock()
+
+	if ok {
+		return code, true
+	}
+
+	return s.storage.GetCode(hash)
+}
+
+// Pending returns the number of trie nodes and code entries not flushed yet
+func (s *BufferedStorage) Pending() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return uint64(len(s.nodes) + len(s.code))
+}
+
+// Flush writes the buffered trie nodes and code to the underlying storage.
+// The nodes are written in a single batch, so either all the tries
+// committed since the last flush are persisted or none of them.
+// It returns the number of entries written
+func (s *BufferedStorage) Flush() uint64 {
+	// readers are blocked until the entries are in the underlying storage
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for hash, code := range s.code {
+		s.storage.SetCode(hash, code)
+	}
+
+	batch := s.storage.Batch()
+	for k, v := range s.nodes {
+		batch.Put([]byte(k), v)
+	}
+
+	batch.Write()
+
+	flushed := uint64(len(s.nodes) + len(s.code))
+
+	s.nodes = map[string][]byte{}
+	s.code = map[types.Hash][]byte{}
+
+	return flushed
+}
+
+// Close flushes the buffer and closes the underlying storage
+func (s *BufferedStorage) Close() error {
+	s.Flush()
+
+	return s.storage.Close()
+}
+
+func copyBytes(b []byte) []byte {
+	buf := make([]byte, len(b))
+	copy(buf, b)
+
+	return buf
+}
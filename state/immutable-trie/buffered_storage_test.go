@@ -0,0 +1,84 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func commitTestObjects(t *testing.T, st *State) types.Hash {
+	t.Helper()
+
+	code := []byte{0x60, 0x00}
+
+	_, root := st.NewSnapshot().Commit([]*state.Object{
+		{
+			Address:   types.StringToAddress("1"),
+			Balance:   big.NewInt(100),
+			Nonce:     1,
+			CodeHash:  types.BytesToHash(hashit(code)),
+			Root:      types.EmptyRootHash,
+			DirtyCode: true,
+			Code:      code,
+		},
+		{
+			Address: types.StringToAddress("2"),
+			Balance: big.NewInt(200),
+			Root:    types.EmptyRootHash,
+		},
+	})
+
+	return types.BytesToHash(root)
+}
+
+func TestBufferedStorage_Flush(t *testing.T) {
+	t.Parallel()
+
+	disk := NewMemoryStorage()
+	buffered := NewBufferedStorage(disk)
+
+	root := commitTestObjects(t, NewState(buffered))
+	assert.NotZero(t, buffered.Pending())
+
+	// the state is only available through the buffer
+	_, err := NewState(buffered).NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	_, err = NewState(disk).NewSnapshotAt(root)
+	assert.Error(t, err)
+
+	pending := buffered.Pending()
+	assert.Equal(t, pending, buffered.Flush())
+	assert.Zero(t, buffered.Pending())
+
+	// the state is persisted
+	snap, err := NewState(disk).NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	_, ok := snap.Get(hashit(types.StringToAddress("2").Bytes()))
+	assert.True(t, ok)
+
+	_, ok = disk.GetCode(types.BytesToHash(hashit([]byte{0x60, 0x00})))
+	assert.True(t, ok)
+
+	// and still available through the buffer
+	_, err = NewState(buffered).NewSnapshotAt(root)
+	assert.NoError(t, err)
+}
+
+func TestBufferedStorage_Close(t *testing.T) {
+	t.Parallel()
+
+	disk := NewMemoryStorage()
+	buffered := NewBufferedStorage(disk)
+
+	root := commitTestObjects(t, NewState(buffered))
+
+	assert.NoError(t, buffered.Close())
+
+	_, err := NewState(disk).NewSnapshotAt(root)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,47 @@
+package itrie
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var errStateEntryHash = errors.New("state entry hash mismatch")
+
+// StateEntry is a trie node or a contract code of a state, stored by its hash
+type StateEntry struct {
+	Hash  types.Hash
+	Value []byte
+	Code  bool
+}
+
+// WalkState calls visit with the trie nodes and the code reachable from the given state root.
+// The nodes shared by several tries and the code shared by several accounts are visited once
+func WalkState(s Storage, root types.Hash, visit func(*StateEntry) error) error {
+	marker := &pruneMarker{
+		storage: s,
+		nodes:   map[types.Hash]struct{}{},
+		code:    map[types.Hash]struct{}{},
+		visit:   visit,
+	}
+
+	return marker.markTrie(root, true)
+}
+
+// WriteStateEntry writes the trie node or the code to the storage,
+// once its hash is checked against its value
+func WriteStateEntry(s Storage, entry *StateEntry) error {
+	if hash := types.BytesToHash(keccak.Keccak256(nil, entry.Value)); hash != entry.Hash {
+		return fmt.Errorf("%w: have %s, want %s", errStateEntryHash, hash, entry.Hash)
+	}
+
+	if entry.Code {
+		s.SetCode(entry.Hash, entry.Value)
+	} else {
+		s.Put(entry.Hash.Bytes(), entry.Value)
+	}
+
+	return nil
+}
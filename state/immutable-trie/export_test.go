@@ -0,0 +1,57 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkState(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addrA = types.StringToAddress("1")
+		addrB = types.StringToAddress("2")
+
+		slot = types.StringToHash("1")
+	)
+
+	src := NewMemoryStorage()
+
+	txn := state.NewTxn(NewState(src), NewState(src).NewSnapshot())
+	txn.AddBalance(addrA, big.NewInt(1))
+	txn.SetCode(addrA, []byte{0x1})
+	txn.SetState(addrA, slot, types.StringToHash("1"))
+	txn.AddBalance(addrB, big.NewInt(2))
+
+	_, root := txn.Commit(false)
+	stateRoot := types.BytesToHash(root)
+
+	// the state is copied to another storage
+	dst := NewMemoryStorage()
+	codes := 0
+
+	assert.NoError(t, WalkState(src, stateRoot, func(entry *StateEntry) error {
+		if entry.Code {
+			codes++
+		}
+
+		return WriteStateEntry(dst, entry)
+	}))
+
+	assert.Equal(t, 1, codes)
+
+	snap, err := NewState(dst).NewSnapshotAt(stateRoot)
+	assert.NoError(t, err)
+
+	txn = state.NewTxn(NewState(dst), snap)
+	assert.Equal(t, types.StringToHash("1"), txn.GetState(addrA, slot))
+	assert.Equal(t, []byte{0x1}, txn.GetCode(addrA))
+	assert.Equal(t, big.NewInt(2), txn.GetBalance(addrB))
+
+	// an entry not matching its hash isn't written
+	assert.ErrorIs(t, WriteStateEntry(dst, &StateEntry{Hash: stateRoot, Value: []byte{0x1}}), errStateEntryHash)
+}
@@ -0,0 +1,99 @@
+package itrie
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// emptyCodeHash is the code hash of the accounts without code
+var emptyCodeHash = types.BytesToHash(hashit(nil))
+
+// Prefetch loads the accounts of the state with the given root from the storage, with their code and the root
+// node of their storage trie, so that the execution of a block on the state finds them in the storage caches.
+// The accounts are split between the workers, each traversing its own copy of the trie,
+// as the lookups expand the nodes of the trie they go through.
+// It returns once the accounts are loaded or stopCh is closed
+func (s *State) Prefetch(root types.Hash, addrs []types.Address, workers int, stopCh <-chan struct{}) {
+	if root == types.EmptyRootHash || len(addrs) == 0 {
+		return
+	}
+
+	if workers > len(addrs) {
+		workers = len(addrs)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		addrCh = make(chan types.Address)
+		wg     sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s.prefetchAccounts(root, addrCh)
+		}()
+	}
+
+	defer func() {
+		close(addrCh)
+		wg.Wait()
+	}()
+
+	for _, addr := range addrs {
+		// a stop is noticed before the next account, even if a worker is ready
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		select {
+		case addrCh <- addr:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// prefetchAccounts loads the accounts received on addrCh through a copy of the trie with the given root
+func (s *State) prefetchAccounts(root types.Hash, addrCh <-chan types.Address) {
+	n, ok, err := GetNode(root.Bytes(), s.storage)
+	if err != nil || !ok {
+		// the state isn't stored, the execution of the block will fail on it as well
+		for range addrCh {
+		}
+
+		return
+	}
+
+	t := s.withFlat(&Trie{root: n, state: s, storage: s.storage}, root)
+
+	for addr := range addrCh {
+		data, ok := t.Get(hashit(addr.Bytes()))
+		if !ok {
+			continue
+		}
+
+		var account state.Account
+		if err := account.UnmarshalRlp(data); err != nil {
+			continue
+		}
+
+		if account.Root != types.EmptyRootHash {
+			_, _, _ = GetNode(account.Root.Bytes(), s.storage)
+		}
+
+		if codeHash := types.BytesToHash(account.CodeHash); codeHash != emptyCodeHash {
+			_, _ = s.storage.GetCode(codeHash)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package itrie
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingStorage counts the codes read from the storage
+type countingStorage struct {
+	Storage
+
+	lock      sync.Mutex
+	codeReads int
+}
+
+func (c *countingStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	c.lock.Lock()
+	c.codeReads++
+	c.lock.Unlock()
+
+	return c.Storage.GetCode(hash)
+}
+
+func TestState_Prefetch(t *testing.T) {
+	t.Parallel()
+
+	storage := &countingStorage{Storage: NewMemoryStorage()}
+	st := NewState(storage)
+
+	var (
+		contract = types.StringToAddress("1")
+		account  = types.StringToAddress("2")
+		missing  = types.StringToAddress("3")
+	)
+
+	txn := state.NewTxn(st, st.NewSnapshot())
+	txn.SetCode(contract, []byte{0x1})
+	txn.SetState(contract, types.StringToHash("1"), types.StringToHash("1"))
+	txn.AddBalance(account, big.NewInt(1))
+
+	_, root := txn.Commit(false)
+
+	// only the code of the contract is read, the other accounts have none
+	st.Prefetch(types.BytesToHash(root), []types.Address{contract, account, missing}, 2, nil)
+	assert.Equal(t, 1, storage.codeReads)
+
+	// nothing is read once stopped
+	stopCh := make(chan struct{})
+	close(stopCh)
+
+	st.Prefetch(types.BytesToHash(root), []types.Address{contract}, 1, stopCh)
+	assert.Equal(t, 1, storage.codeReads)
+}
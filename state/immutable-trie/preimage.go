@@ -0,0 +1,21 @@
+package itrie
+
+import (
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// writePreimage writes the preimage of the hashed trie key
+func writePreimage(batch Batch, hash, key []byte) {
+	batch.Put(append(append([]byte{}, preimagePrefix...), hash...), key)
+}
+
+// GetPreimage returns the address or the storage slot hashed to the given trie key.
+// The preimages are only recorded while RecordPreimages is enabled
+func (s *State) GetPreimage(hash types.Hash) ([]byte, bool) {
+	preimage, ok := s.storage.Get(append(append([]byte{}, preimagePrefix...), hash.Bytes()...))
+	if !ok || len(preimage) == 0 {
+		return nil, false
+	}
+
+	return preimage, true
+}
@@ -0,0 +1,52 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_Preimages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr = types.StringToAddress("1")
+		slot = types.StringToHash("2")
+	)
+
+	commit := func(st *State) {
+		txn := state.NewTxn(st, st.NewSnapshot())
+		txn.AddBalance(addr, big.NewInt(1))
+		txn.SetState(addr, slot, types.StringToHash("1"))
+		txn.Commit(false)
+	}
+
+	addrHash := types.BytesToHash(keccak.Keccak256(nil, addr.Bytes()))
+	slotHash := types.BytesToHash(keccak.Keccak256(nil, slot.Bytes()))
+
+	// the preimages aren't recorded by default
+	st := NewState(NewMemoryStorage())
+	commit(st)
+
+	_, ok := st.GetPreimage(addrHash)
+	assert.False(t, ok)
+
+	st = NewState(NewMemoryStorage())
+	st.RecordPreimages = true
+	commit(st)
+
+	preimage, ok := st.GetPreimage(addrHash)
+	assert.True(t, ok)
+	assert.Equal(t, addr.Bytes(), preimage)
+
+	preimage, ok = st.GetPreimage(slotHash)
+	assert.True(t, ok)
+	assert.Equal(t, slot.Bytes(), preimage)
+
+	_, ok = st.GetPreimage(types.StringToHash("3"))
+	assert.False(t, ok)
+}
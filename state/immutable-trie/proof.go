@@ -0,0 +1,136 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	ErrMissingProofNode = errors.New("missing trie node")
+	ErrInvalidProofNode = errors.New("invalid trie node")
+)
+
+// Prove collects the RLP encoded nodes on the path from the root to each of the keys
+// into proofDB, indexed by their hash. Nodes shared between the paths are only stored once,
+// so proving many keys against the same root results in a compact multiproof.
+// The keys are the raw trie keys, i.e. the hashed addresses or storage slots
+func (s *State) Prove(root types.Hash, keys [][]byte, proofDB map[types.Hash][]byte) error {
+	get := func(hash []byte) ([]byte, bool) {
+		data, ok := s.storage.Get(hash)
+		if ok {
+			proofDB[types.BytesToHash(hash)] = append([]byte{}, data...)
+		}
+
+		return data, ok
+	}
+
+	for _, key := range keys {
+		if _, err := walkProof(root, key, get); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifyProof checks the key against the root using the nodes in proofDB,
+// and returns the value stored under the key. A nil value with no error
+// means the proof shows the key is absent from the trie
+func VerifyProof(root types.Hash, key []byte, proofDB map[types.Hash][]byte) ([]byte, error) {
+	get := func(hash []byte) ([]byte, bool) {
+		data, ok := proofDB[types.BytesToHash(hash)]
+		if !ok || !bytes.Equal(hashit(data), hash) {
+			return nil, false
+		}
+
+		return data, true
+	}
+
+	return walkProof(root, key, get)
+}
+
+// walkProof follows the key from the root, resolving hashed nodes with get
+func walkProof(root types.Hash, key []byte, get func(hash []byte) ([]byte, bool)) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	resolve := func(hash []byte) (*fastrlp.Value, error) {
+		data, ok := get(hash)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingProofNode, types.BytesToHash(hash))
+		}
+
+		return p.Parse(data)
+	}
+
+	v, err := resolve(root.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	search := bytesToHexNibbles(key)
+
+	for {
+		switch {
+		case v.Type() == fastrlp.TypeBytes:
+			raw := v.Raw()
+			if len(raw) == 0 {
+				// empty edge, the key is not in the trie
+				return nil, nil
+			}
+
+			if len(raw) != 32 {
+				return nil, fmt.Errorf("%w: reference of length %d", ErrInvalidProofNode, len(raw))
+			}
+
+			// resolve overwrites the parser buffer, so copy the reference first
+			if v, err = resolve(append([]byte{}, raw...)); err != nil {
+				return nil, err
+			}
+
+		case v.Elems() == 2:
+			nodeKey := v.Get(0)
+			if nodeKey.Type() != fastrlp.TypeBytes {
+				return nil, fmt.Errorf("%w: short key expected to be bytes", ErrInvalidProofNode)
+			}
+
+			prefix := decodeCompact(nodeKey.Raw())
+			if len(prefix) > len(search) || !bytes.Equal(prefix, search[:len(prefix)]) {
+				// the path diverges, the key is not in the trie
+				return nil, nil
+			}
+
+			if hasTerminator(prefix) {
+				return append([]byte{}, v.Get(1).Raw()...), nil
+			}
+
+			search = search[len(prefix):]
+			v = v.Get(1)
+
+		case v.Elems() == 17:
+			idx := search[0]
+			if idx == 16 {
+				value := v.Get(16).Raw()
+				if len(value) == 0 {
+					return nil, nil
+				}
+
+				return append([]byte{}, value...), nil
+			}
+
+			search = search[1:]
+			v = v.Get(int(idx))
+
+		default:
+			return nil, fmt.Errorf("%w: node has incorrect number of leafs", ErrInvalidProofNode)
+		}
+	}
+}
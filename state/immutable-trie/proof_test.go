@@ -0,0 +1,99 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildProofState(t *testing.T, num int) (*State, types.Hash, []types.Address) {
+	t.Helper()
+
+	st := NewState(NewMemoryStorage())
+
+	addrs := make([]types.Address, num)
+	objs := make([]*state.Object, num)
+
+	for i := 0; i < num; i++ {
+		addrs[i] = types.StringToAddress(big.NewInt(int64(i + 1)).String())
+		objs[i] = &state.Object{
+			Address:  addrs[i],
+			Balance:  big.NewInt(int64(i * 100)),
+			Nonce:    uint64(i),
+			CodeHash: types.BytesToHash(hashit(nil)),
+			Root:     types.EmptyRootHash,
+		}
+	}
+
+	_, root := st.NewSnapshot().Commit(objs)
+
+	return st, types.BytesToHash(root), addrs
+}
+
+func TestProof_Accounts(t *testing.T) {
+	t.Parallel()
+
+	st, root, addrs := buildProofState(t, 50)
+
+	keys := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		keys[i] = hashit(addr.Bytes())
+	}
+
+	proofDB := map[types.Hash][]byte{}
+	assert.NoError(t, st.Prove(root, keys, proofDB))
+
+	for i, key := range keys {
+		value, err := VerifyProof(root, key, proofDB)
+		assert.NoError(t, err)
+
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(value))
+		assert.Equal(t, uint64(i), account.Nonce)
+		assert.Equal(t, int64(i*100), account.Balance.Int64())
+	}
+
+	// a multiproof shares the upper nodes between all the paths
+	single := map[types.Hash][]byte{}
+	assert.NoError(t, st.Prove(root, keys[:1], single))
+
+	assert.Less(t, len(proofDB), len(single)*len(keys))
+}
+
+func TestProof_Absent(t *testing.T) {
+	t.Parallel()
+
+	st, root, _ := buildProofState(t, 10)
+
+	missing := hashit(types.StringToAddress("0xdead").Bytes())
+
+	proofDB := map[types.Hash][]byte{}
+	assert.NoError(t, st.Prove(root, [][]byte{missing}, proofDB))
+
+	value, err := VerifyProof(root, missing, proofDB)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestProof_Tampered(t *testing.T) {
+	t.Parallel()
+
+	st, root, addrs := buildProofState(t, 10)
+	key := hashit(addrs[0].Bytes())
+
+	proofDB := map[types.Hash][]byte{}
+	assert.NoError(t, st.Prove(root, [][]byte{key}, proofDB))
+
+	// corrupt the root node, its hash does not match anymore
+	proofDB[root] = append([]byte{0x1}, proofDB[root]...)
+
+	_, err := VerifyProof(root, key, proofDB)
+	assert.ErrorIs(t, err, ErrMissingProofNode)
+
+	// proofs against a root that is not known fail too
+	_, err = VerifyProof(types.StringToHash("0x1"), key, map[types.Hash][]byte{})
+	assert.ErrorIs(t, err, ErrMissingProofNode)
+}
@@ -0,0 +1,203 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// pruneBatchSize is the number of deletions written at once while pruning
+const pruneBatchSize = 10000
+
+var errMissingNode = errors.New("trie node not found")
+
+// PruneResult is the number of the entries deleted by a prune
+type PruneResult struct {
+	Nodes uint64
+	Code  uint64
+}
+
+// Prune deletes the trie nodes and the code unreachable from the given state roots.
+// The trie nodes are shared between the roots without being reference counted,
+// so the storage must not be written to while it is pruned
+func (kv *KVStorage) Prune(roots []types.Hash) (*PruneResult, error) {
+	marker := &pruneMarker{
+		storage: kv,
+		nodes:   map[types.Hash]struct{}{},
+		code:    map[types.Hash]struct{}{},
+	}
+
+	for _, root := range roots {
+		if err := marker.markTrie(root, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return kv.sweep(marker)
+}
+
+// Compact compacts the whole storage, reclaiming the space of the deleted entries
+func (kv *KVStorage) Compact() error {
+	return kv.db.CompactRange(util.Range{})
+}
+
+// sweep deletes the trie nodes and the code not marked. The other entries are kept
+func (kv *KVStorage) sweep(marker *pruneMarker) (*PruneResult, error) {
+	res := &PruneResult{}
+	batch := &leveldb.Batch{}
+
+	iter := kv.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+
+		switch {
+		case len(key) == types.HashLength:
+			if _, ok := marker.nodes[types.BytesToHash(key)]; ok {
+				continue
+			}
+
+			res.Nodes++
+		case len(key) == len(codePrefix)+types.HashLength && bytes.HasPrefix(key, codePrefix):
+			if _, ok := marker.code[types.BytesToHash(key[len(codePrefix):])]; ok {
+				continue
+			}
+
+			res.Code++
+		default:
+			continue
+		}
+
+		batch.Delete(key)
+
+		if batch.Len() >= pruneBatchSize {
+			if err := kv.db.Write(batch, nil); err != nil {
+				return nil, err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	if err := kv.db.Write(batch, nil); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// pruneMarker collects the trie nodes and the code reachable from the state roots
+type pruneMarker struct {
+	storage Storage
+	nodes   map[types.Hash]struct{}
+	code    map[types.Hash]struct{}
+
+	// visit is called with each marked node and code if set, to walk the state
+	visit func(*StateEntry) error
+}
+
+// markTrie marks the stored node with the given hash and its descendants.
+// The leaves of the account tries are decoded to mark their storage tries and code
+func (m *pruneMarker) markTrie(root types.Hash, accounts bool) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	// the subtries shared between the roots are walked once
+	if _, ok := m.nodes[root]; ok {
+		return nil
+	}
+
+	n, ok, err := GetNode(root.Bytes(), m.storage)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s", errMissingNode, root)
+	}
+
+	m.nodes[root] = struct{}{}
+
+	if m.visit != nil {
+		data, _ := m.storage.Get(root.Bytes())
+
+		if err := m.visit(&StateEntry{Hash: root, Value: data}); err != nil {
+			return err
+		}
+	}
+
+	return m.markNode(n, accounts)
+}
+
+func (m *pruneMarker) markNode(node Node, accounts bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			return m.markTrie(types.BytesToHash(n.buf), accounts)
+		}
+
+		if !accounts {
+			return nil
+		}
+
+		var account state.Account
+		if err := account.UnmarshalRlp(n.buf); err != nil {
+			return err
+		}
+
+		if err := m.markCode(types.BytesToHash(account.CodeHash)); err != nil {
+			return err
+		}
+
+		return m.markTrie(account.Root, false)
+
+	case *ShortNode:
+		return m.markNode(n.child, accounts)
+
+	case *FullNode:
+		for _, child := range n.children {
+			if err := m.markNode(child, accounts); err != nil {
+				return err
+			}
+		}
+
+		return m.markNode(n.value, accounts)
+
+	default:
+		return fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// markCode marks the code with the given hash, the accounts without code have no stored code
+func (m *pruneMarker) markCode(hash types.Hash) error {
+	if _, ok := m.code[hash]; ok {
+		return nil
+	}
+
+	m.code[hash] = struct{}{}
+
+	if m.visit == nil {
+		return nil
+	}
+
+	code, ok := m.storage.GetCode(hash)
+	if !ok {
+		return nil
+	}
+
+	return m.visit(&StateEntry{Hash: hash, Value: code, Code: true})
+}
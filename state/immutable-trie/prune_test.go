@@ -0,0 +1,86 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+func TestKVStorage_Prune(t *testing.T) {
+	t.Parallel()
+
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	assert.NoError(t, err)
+
+	kv := &KVStorage{db}
+	defer kv.Close()
+
+	// an entry which isn't part of the tries
+	kv.Put([]byte("other"), []byte{0x1})
+
+	var (
+		addrA = types.StringToAddress("1")
+		addrB = types.StringToAddress("2")
+		addrC = types.StringToAddress("3")
+
+		slot = types.StringToHash("1")
+	)
+
+	// the first state, with a contract destroyed in the second one
+	txn := state.NewTxn(NewState(kv), NewState(kv).NewSnapshot())
+	txn.AddBalance(addrA, big.NewInt(1))
+	txn.SetCode(addrA, []byte{0x1})
+	txn.SetState(addrA, slot, types.StringToHash("1"))
+	txn.AddBalance(addrB, big.NewInt(2))
+	txn.SetCode(addrC, []byte{0x2})
+
+	snap, root := txn.Commit(false)
+	oldRoot := types.BytesToHash(root)
+
+	// the second state, updating the storage of the first contract
+	txn = state.NewTxn(NewState(kv), snap)
+	txn.SetState(addrA, slot, types.StringToHash("2"))
+	txn.AddBalance(addrB, big.NewInt(3))
+	txn.Suicide(addrC)
+
+	_, root = txn.Commit(true)
+	newRoot := types.BytesToHash(root)
+
+	res, err := kv.Prune([]types.Hash{newRoot})
+	assert.NoError(t, err)
+	assert.NotZero(t, res.Nodes)
+	assert.Equal(t, uint64(1), res.Code)
+	assert.NoError(t, kv.Compact())
+
+	// the pruned state is gone, the retained one is whole
+	st := NewState(kv)
+
+	_, err = st.NewSnapshotAt(oldRoot)
+	assert.Error(t, err)
+
+	snap, err = st.NewSnapshotAt(newRoot)
+	assert.NoError(t, err)
+
+	txn = state.NewTxn(st, snap)
+	assert.Equal(t, types.StringToHash("2"), txn.GetState(addrA, slot))
+	assert.Equal(t, []byte{0x1}, txn.GetCode(addrA))
+	assert.Equal(t, big.NewInt(5), txn.GetBalance(addrB))
+	assert.False(t, txn.Exist(addrC))
+
+	_, ok := kv.Get([]byte("other"))
+	assert.True(t, ok)
+
+	// pruning again deletes nothing
+	res, err = kv.Prune([]types.Hash{newRoot})
+	assert.NoError(t, err)
+	assert.Equal(t, &PruneResult{}, res)
+
+	// the roots to retain have to be whole
+	_, err = kv.Prune([]types.Hash{oldRoot})
+	assert.ErrorIs(t, err, errMissingNode)
+}
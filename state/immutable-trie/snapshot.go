@@ -0,0 +1,464 @@
+package itrie
+
+import (
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// maxDiffLayers is the number of the latest committed states kept in memory,
+// the older ones are flattened into the snapshot on disk
+const maxDiffLayers = 128
+
+var (
+	// snapAccountPrefix is the prefix of the accounts of the flat snapshot, by hashed address
+	snapAccountPrefix = []byte("snapa")
+	// snapStoragePrefix is the prefix of the storage slots of the flat snapshot, by hashed address and slot
+	snapStoragePrefix = []byte("snaps")
+	// snapRootKey is the key of the state root of the flat snapshot on disk
+	snapRootKey = []byte("snaproot")
+	// snapGeneratorKey is the key of the progress of the flat snapshot generation
+	snapGeneratorKey = []byte("snapgen")
+)
+
+// SnapshotBatch is a batch write of the flat snapshot
+type SnapshotBatch interface {
+	Put(k, v []byte)
+	Delete(k []byte)
+	// DeletePrefix deletes the stored entries with the prefix, the ones put in the batch are kept
+	DeletePrefix(prefix []byte)
+	Write() error
+}
+
+// SnapshotStorage stores the flat snapshot next to the tries
+type SnapshotStorage interface {
+	Get(k []byte) ([]byte, bool)
+	SnapshotBatch() SnapshotBatch
+}
+
+func snapAccountKey(account types.Hash) []byte {
+	return append(append([]byte{}, snapAccountPrefix...), account.Bytes()...)
+}
+
+func snapStorageKey(account, slot types.Hash) []byte {
+	return append(snapStorageAccountPrefix(account), slot.Bytes()...)
+}
+
+func snapStorageAccountPrefix(account types.Hash) []byte {
+	return append(append([]byte{}, snapStoragePrefix...), account.Bytes()...)
+}
+
+// diffLayer is the state written by a commit on top of the state of its parent root
+type diffLayer struct {
+	root   types.Hash
+	parent types.Hash
+
+	// accounts are the written accounts by hashed address, nil for the deleted ones
+	accounts map[types.Hash][]byte
+	// destructs are the accounts whose previous storage is discarded, as they are deleted or recreated
+	destructs map[types.Hash]struct{}
+	// storage are the written slots by hashed address and slot, nil for the cleared ones
+	storage map[types.Hash]map[types.Hash][]byte
+}
+
+func newDiffLayer() *diffLayer {
+	return &diffLayer{
+		accounts:  map[types.Hash][]byte{},
+		destructs: map[types.Hash]struct{}{},
+		storage:   map[types.Hash]map[types.Hash][]byte{},
+	}
+}
+
+func (l *diffLayer) setSlot(account, slot types.Hash, value []byte) {
+	slots, ok := l.storage[account]
+	if !ok {
+		slots = map[types.Hash][]byte{}
+		l.storage[account] = slots
+	}
+
+	slots[slot] = value
+}
+
+// SnapshotStatus is the status of the flat snapshot of the state
+type SnapshotStatus struct {
+	// Root is the state root of the snapshot on disk
+	Root types.Hash
+	// Ready is set once the snapshot is generated, and read instead of the tries
+	Ready bool
+	// Generating is set while the snapshot is generated in the background
+	Generating bool
+	// Progress is the share of the accounts generated, in percents
+	Progress float64
+	// Accounts and Slots are the number of the entries generated
+	Accounts uint64
+	Slots    uint64
+	// Layers is the number of the committed states kept in memory
+	Layers int
+}
+
+// SnapshotTree is a flat snapshot of the state, the accounts and the storage slots by their hashed keys,
+// so that they are read without traversing the tries. The latest committed states are kept in memory
+// as diff layers on top of the snapshot on disk, in which the oldest ones are flattened.
+// The states which are not in the snapshot are read from the tries
+type SnapshotTree struct {
+	logger  hclog.Logger
+	storage SnapshotStorage
+	// trie is the storage of the tries the snapshot is generated from
+	trie Storage
+
+	lock     sync.RWMutex
+	diskRoot types.Hash
+	layers   map[types.Hash]*diffLayer
+	// head is the latest committed state root
+	head types.Hash
+	// gen is the generation of the snapshot on disk, set until it is done
+	gen *snapshotGenerator
+	// failed is set if the generation failed, the snapshot is not used until it is generated again
+	failed bool
+}
+
+// NewSnapshotTree loads the flat snapshot from the storage, its generation
+// is resumed or started once the head state is known with Load
+func NewSnapshotTree(storage SnapshotStorage, trie Storage, logger hclog.Logger) *SnapshotTree {
+	t := &SnapshotTree{
+		logger:   logger.Named("snapshot"),
+		storage:  storage,
+		trie:     trie,
+		diskRoot: types.EmptyRootHash,
+		layers:   map[types.Hash]*diffLayer{},
+	}
+
+	if root, ok := storage.Get(snapRootKey); ok && len(root) == types.HashLength {
+		t.diskRoot = types.BytesToHash(root)
+	}
+
+	if progress, ok := storage.Get(snapGeneratorKey); ok && len(progress) > 0 {
+		t.gen = decodeSnapshotGenerator(progress)
+	}
+
+	return t
+}
+
+// Load checks the snapshot against the head state, the snapshot is generated
+// in the background if it is not complete or doesn't lead to the head state
+func (t *SnapshotTree) Load(head types.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.head = head
+
+	if t.gen == nil && !t.failed && t.reaches(head) {
+		t.logger.Info("loaded the state snapshot", "root", t.diskRoot)
+
+		return
+	}
+
+	if t.gen != nil && t.diskRoot == head {
+		t.logger.Info("resuming the state snapshot generation", "root", head, "accounts", t.gen.accounts)
+	} else {
+		t.logger.Info("generating the state snapshot", "root", head)
+
+		t.diskRoot = head
+		t.gen = &snapshotGenerator{wipe: true}
+	}
+
+	t.layers = map[types.Hash]*diffLayer{}
+	t.failed = false
+	t.gen.start(t, head)
+}
+
+// Status returns the status of the snapshot and of its generation
+func (t *SnapshotTree) Status() *SnapshotStatus {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	status := &SnapshotStatus{
+		Root:   t.diskRoot,
+		Ready:  t.gen == nil && !t.failed,
+		Layers: len(t.layers),
+	}
+
+	if t.gen != nil {
+		status.Generating = t.gen.running()
+		status.Progress = t.gen.progress()
+		status.Accounts = t.gen.accounts
+		status.Slots = t.gen.slots
+	}
+
+	return status
+}
+
+// Close stops the generation, and flattens the states committed up to the head into the snapshot on disk,
+// so that it is loaded on the next start. The states committed since are lost
+func (t *SnapshotTree) Close(head types.Hash) error {
+	t.lock.RLock()
+	gen := t.gen
+	t.lock.RUnlock()
+
+	if gen != nil {
+		gen.stop()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.gen != nil || t.failed {
+		return nil
+	}
+
+	return t.capLayers(head, 0)
+}
+
+// update adds the diff layer of a committed state, and flattens the oldest layers
+// under it into the snapshot on disk
+func (t *SnapshotTree) update(layer *diffLayer) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.failed || layer.root == layer.parent || layer.root == t.diskRoot {
+		return
+	}
+
+	t.head = layer.root
+
+	if _, ok := t.layers[layer.root]; ok {
+		// the same state is committed again, e.g. when a block is verified and then written
+		return
+	}
+
+	t.layers[layer.root] = layer
+
+	// the layers are kept in memory while the snapshot on disk is generated
+	if t.gen != nil {
+		return
+	}
+
+	if err := t.capLayers(layer.root, maxDiffLayers); err != nil {
+		t.logger.Error("failed to write the state snapshot, it is disabled until restart", "err", err)
+
+		t.failed = true
+		t.layers = map[types.Hash]*diffLayer{}
+	}
+}
+
+// has checks if the state with the given root is in the snapshot
+func (t *SnapshotTree) has(root types.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.gen == nil && !t.failed && t.reaches(root)
+}
+
+// reaches checks if the diff layers from the given root lead to the snapshot on disk
+func (t *SnapshotTree) reaches(root types.Hash) bool {
+	for r := root; r != t.diskRoot; {
+		layer, ok := t.layers[r]
+		if !ok {
+			return false
+		}
+
+		r = layer.parent
+	}
+
+	return true
+}
+
+// account returns the account with the hashed address in the state with the given root, and if it is found.
+// The last value is false if the state is not in the snapshot, and has to be read from the trie
+func (t *SnapshotTree) account(root types.Hash, addrHash []byte) ([]byte, bool, bool) {
+	if len(addrHash) != types.HashLength {
+		return nil, false, false
+	}
+
+	account := types.BytesToHash(addrHash)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.gen != nil || t.failed {
+		return nil, false, false
+	}
+
+	for r := root; ; {
+		if r == t.diskRoot {
+			data, found := t.storage.Get(snapAccountKey(account))
+			if !found || len(data) == 0 {
+				return nil, false, true
+			}
+
+			return data, true, true
+		}
+
+		layer, exists := t.layers[r]
+		if !exists {
+			return nil, false, false
+		}
+
+		if data, written := layer.accounts[account]; written {
+			return data, data != nil, true
+		}
+
+		r = layer.parent
+	}
+}
+
+// slot returns the storage slot with the hashed key of the account in the state with the given root, and if it
+// is found. The last value is false if the state is not in the snapshot, and has to be read from the trie
+func (t *SnapshotTree) slot(root, account types.Hash, slotHash []byte) ([]byte, bool, bool) {
+	if len(slotHash) != types.HashLength {
+		return nil, false, false
+	}
+
+	slot := types.BytesToHash(slotHash)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.gen != nil || t.failed {
+		return nil, false, false
+	}
+
+	for r := root; ; {
+		if r == t.diskRoot {
+			data, found := t.storage.Get(snapStorageKey(account, slot))
+			if !found || len(data) == 0 {
+				return nil, false, true
+			}
+
+			return data, true, true
+		}
+
+		layer, exists := t.layers[r]
+		if !exists {
+			return nil, false, false
+		}
+
+		if data, written := layer.storage[account][slot]; written {
+			return data, data != nil, true
+		}
+
+		// the storage under a destructed account is discarded
+		if _, destructed := layer.destructs[account]; destructed {
+			return nil, false, true
+		}
+
+		r = layer.parent
+	}
+}
+
+// capLayers flattens the oldest diff layers under the given root into the snapshot on disk,
+// keeping the given number of layers. The layers not leading to the new disk root are dropped
+func (t *SnapshotTree) capLayers(root types.Hash, keep int) error {
+	chain := []*diffLayer{}
+
+	for r := root; r != t.diskRoot; {
+		layer, ok := t.layers[r]
+		if !ok {
+			// the state is not on top of the snapshot on disk
+			return nil
+		}
+
+		chain = append(chain, layer)
+		r = layer.parent
+	}
+
+	if len(chain) <= keep {
+		return nil
+	}
+
+	for i := len(chain) - 1; i >= keep; i-- {
+		// each layer is written on its own, so that the storage
+		// of the destructed accounts is read from the previous ones
+		if err := t.flatten(chain[i]); err != nil {
+			return err
+		}
+
+		delete(t.layers, chain[i].root)
+		t.diskRoot = chain[i].root
+	}
+
+	for r := range t.layers {
+		if !t.reaches(r) {
+			delete(t.layers, r)
+		}
+	}
+
+	return nil
+}
+
+// flatten writes the diff layer to the snapshot on disk
+func (t *SnapshotTree) flatten(layer *diffLayer) error {
+	batch := t.storage.SnapshotBatch()
+
+	for account := range layer.destructs {
+		batch.DeletePrefix(snapStorageAccountPrefix(account))
+	}
+
+	for account, data := range layer.accounts {
+		if data == nil {
+			batch.Delete(snapAccountKey(account))
+		} else {
+			batch.Put(snapAccountKey(account), data)
+		}
+	}
+
+	for account, slots := range layer.storage {
+		for slot, data := range slots {
+			if data == nil {
+				batch.Delete(snapStorageKey(account, slot))
+			} else {
+				batch.Put(snapStorageKey(account, slot), data)
+			}
+		}
+	}
+
+	batch.Put(snapRootKey, layer.root.Bytes())
+
+	return batch.Write()
+}
+
+// flatState is the state with the given root in the flat snapshot
+type flatState struct {
+	tree *SnapshotTree
+	root types.Hash
+}
+
+// flatStorage reads the storage of an account from the flat snapshot,
+// or from its storage trie if the state is not in the snapshot anymore
+type flatStorage struct {
+	flat        *flatState
+	state       *State
+	account     types.Hash
+	storageRoot types.Hash
+}
+
+func (s *flatStorage) Get(k []byte) ([]byte, bool) {
+	if data, found, ok := s.flat.tree.slot(s.flat.root, s.account, k); ok {
+		return data, found
+	}
+
+	trie, err := s.state.NewSnapshotAt(s.storageRoot)
+	if err != nil {
+		return nil, false
+	}
+
+	return trie.Get(k)
+}
+
+// StorageAt returns the reader of the storage of the account from the flat snapshot,
+// false if the state of the trie is not in the snapshot
+func (t *Trie) StorageAt(addrHash []byte, root types.Hash) (state.StorageReader, bool) {
+	if t.flat == nil || len(addrHash) != types.HashLength || !t.flat.tree.has(t.flat.root) {
+		return nil, false
+	}
+
+	return &flatStorage{
+		flat:        t.flat,
+		state:       t.state,
+		account:     types.BytesToHash(addrHash),
+		storageRoot: root,
+	}, true
+}
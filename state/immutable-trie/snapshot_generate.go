@@ -0,0 +1,330 @@
+package itrie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// snapshotGenBatchSize is the number of entries written at once while the snapshot is generated
+const snapshotGenBatchSize = 10000
+
+var errSnapshotGenStopped = errors.New("snapshot generation stopped")
+
+// snapshotGenerator generates the flat snapshot from the account trie and the storage tries
+// of a state. Its progress is written along with the entries, so that it is resumed after a restart
+type snapshotGenerator struct {
+	// wipe is set if the previous snapshot has to be deleted first
+	wipe bool
+
+	// marker is the hashed address of the latest account generated, nil if none
+	marker   []byte
+	accounts uint64
+	slots    uint64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func decodeSnapshotGenerator(data []byte) *snapshotGenerator {
+	gen := &snapshotGenerator{}
+
+	// accounts (8 bytes) | slots (8 bytes) | marker
+	if len(data) < 16 {
+		return gen
+	}
+
+	gen.accounts = binary.BigEndian.Uint64(data[:8])
+	gen.slots = binary.BigEndian.Uint64(data[8:16])
+
+	if marker := data[16:]; len(marker) == types.HashLength {
+		gen.marker = copyBytes(marker)
+	}
+
+	return gen
+}
+
+func (g *snapshotGenerator) encode(marker []byte, accounts, slots uint64) []byte {
+	data := make([]byte, 16, 16+len(marker))
+
+	binary.BigEndian.PutUint64(data[:8], accounts)
+	binary.BigEndian.PutUint64(data[8:16], slots)
+
+	return append(data, marker...)
+}
+
+// running checks if the generation is in progress, the tree lock is held by the caller
+func (g *snapshotGenerator) running() bool {
+	if g.doneCh == nil {
+		return false
+	}
+
+	select {
+	case <-g.doneCh:
+		return false
+	default:
+		return true
+	}
+}
+
+// progress returns the share of the accounts generated in percents. As the addresses
+// are hashed, the accounts are spread evenly over the keys
+func (g *snapshotGenerator) progress() float64 {
+	if len(g.marker) < 8 {
+		return 0
+	}
+
+	return float64(binary.BigEndian.Uint64(g.marker[:8])) / math.MaxUint64 * 100
+}
+
+// start generates the snapshot of the state with the given root in the background
+func (g *snapshotGenerator) start(t *SnapshotTree, root types.Hash) {
+	g.stopCh = make(chan struct{})
+	g.doneCh = make(chan struct{})
+
+	go func() {
+		defer close(g.doneCh)
+
+		if err := g.generate(t, root); err != nil {
+			t.logger.Error("failed to generate the state snapshot", "root", root, "err", err)
+
+			t.lock.Lock()
+			t.failed = true
+			t.layers = map[types.Hash]*diffLayer{}
+			t.lock.Unlock()
+		}
+	}()
+}
+
+// stop interrupts the generation, which is resumed from its progress on the next start
+func (g *snapshotGenerator) stop() {
+	if g.stopCh == nil {
+		return
+	}
+
+	select {
+	case <-g.stopCh:
+	default:
+		close(g.stopCh)
+	}
+
+	<-g.doneCh
+}
+
+func (g *snapshotGenerator) generate(t *SnapshotTree, root types.Hash) error {
+	t.lock.RLock()
+	marker, accounts, slots := g.marker, g.accounts, g.slots
+	t.lock.RUnlock()
+
+	batch := t.storage.SnapshotBatch()
+	pending := 0
+
+	if g.wipe {
+		batch.DeletePrefix(snapAccountPrefix)
+		batch.DeletePrefix(snapStoragePrefix)
+	}
+
+	batch.Put(snapRootKey, root.Bytes())
+	batch.Put(snapGeneratorKey, g.encode(marker, accounts, slots))
+
+	// write commits the batch, along with the progress once an account is done
+	write := func(done bool) error {
+		if done {
+			batch.Put(snapGeneratorKey, g.encode(marker, accounts, slots))
+		}
+
+		if err := batch.Write(); err != nil {
+			return err
+		}
+
+		batch = t.storage.SnapshotBatch()
+		pending = 0
+
+		if done {
+			t.lock.Lock()
+			g.marker, g.accounts, g.slots = marker, accounts, slots
+			t.lock.Unlock()
+		}
+
+		return nil
+	}
+
+	if err := write(false); err != nil {
+		return err
+	}
+
+	var from []byte
+	if marker != nil {
+		from = bytesToHexNibbles(marker)
+	}
+
+	err := walkTrie(t.trie, root, from, func(key, value []byte) error {
+		// the latest account generated is done
+		if marker != nil && bytes.Compare(key, marker) <= 0 {
+			return nil
+		}
+
+		select {
+		case <-g.stopCh:
+			return errSnapshotGenStopped
+		default:
+		}
+
+		var account state.Account
+		if err := account.UnmarshalRlp(value); err != nil {
+			return err
+		}
+
+		accountHash := types.BytesToHash(key)
+		batch.Put(snapAccountKey(accountHash), value)
+		pending++
+
+		err := walkTrie(t.trie, account.Root, nil, func(slot, value []byte) error {
+			batch.Put(snapStorageKey(accountHash, types.BytesToHash(slot)), value)
+			slots++
+			pending++
+
+			// the slots of a large storage are written before the account is done
+			if pending >= snapshotGenBatchSize {
+				return write(false)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		marker = copyBytes(key)
+		accounts++
+
+		if pending >= snapshotGenBatchSize {
+			return write(true)
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errSnapshotGenStopped) {
+		t.logger.Info("stopped the state snapshot generation", "accounts", accounts)
+
+		return write(true)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	batch.Delete(snapGeneratorKey)
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	g.marker, g.accounts, g.slots = marker, accounts, slots
+	t.gen = nil
+
+	t.logger.Info("generated the state snapshot", "root", root, "accounts", accounts, "slots", slots)
+
+	// the states committed during the generation are flattened
+	if err := t.capLayers(t.head, maxDiffLayers); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// walkTrie calls fn with the keys and the values of the trie with the given root, in the order
+// of the keys. The subtries with keys before the given nibbles are skipped
+func walkTrie(storage Storage, root types.Hash, from []byte, fn func(key, value []byte) error) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+
+	n, ok, err := GetNode(root.Bytes(), storage)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s", errMissingNode, root)
+	}
+
+	return walkNode(storage, n, nil, from, fn)
+}
+
+func walkNode(storage Storage, node Node, path, from []byte, fn func(key, value []byte) error) error {
+	if from != nil {
+		l := len(path)
+		if l > len(from) {
+			l = len(from)
+		}
+
+		if bytes.Compare(path[:l], from[:l]) < 0 {
+			return nil
+		}
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			nc, ok, err := GetNode(n.buf, storage)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return fmt.Errorf("%w: %s", errMissingNode, types.BytesToHash(n.buf))
+			}
+
+			return walkNode(storage, nc, path, from, fn)
+		}
+
+		return fn(hexNibblesToBytes(path), n.buf)
+
+	case *ShortNode:
+		key := n.key
+		if hasTerminator(key) {
+			key = key[:len(key)-1]
+		}
+
+		return walkNode(storage, n.child, concat(path, key), from, fn)
+
+	case *FullNode:
+		if err := walkNode(storage, n.value, path, from, fn); err != nil {
+			return err
+		}
+
+		for i, child := range n.children {
+			if err := walkNode(storage, child, concat(path, []byte{byte(i)}), from, fn); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// hexNibblesToBytes packs the nibbles of a key without terminator into bytes
+func hexNibblesToBytes(nibbles []byte) []byte {
+	key := make([]byte, len(nibbles)/2)
+	for i := range key {
+		key[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+
+	return key
+}
@@ -0,0 +1,217 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_Snapshots(t *testing.T) {
+	state.TestState(t, func(pre state.PreStates) (state.State, state.Snapshot) {
+		storage := NewMemoryStorage()
+
+		st := NewState(storage)
+		st.Snapshots = NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+
+		return st, st.NewSnapshot()
+	})
+}
+
+// commitState applies the changes on top of the state with the given root, and commits them
+func commitState(t *testing.T, st *State, root types.Hash, apply func(txn *state.Txn)) types.Hash {
+	t.Helper()
+
+	snap, err := st.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	txn := state.NewTxn(st, snap)
+	apply(txn)
+
+	_, newRoot := txn.Commit(true)
+
+	return types.BytesToHash(newRoot)
+}
+
+var (
+	snapAddr1 = types.StringToAddress("1")
+	snapAddr2 = types.StringToAddress("2")
+	snapSlot1 = types.StringToHash("1")
+	snapSlot2 = types.StringToHash("2")
+)
+
+// buildSnapshotStates commits a few states touching the storage, and destructing and recreating an account
+func buildSnapshotStates(t *testing.T, st *State) []types.Hash {
+	t.Helper()
+
+	roots := []types.Hash{types.EmptyRootHash}
+	commit := func(apply func(txn *state.Txn)) {
+		roots = append(roots, commitState(t, st, roots[len(roots)-1], apply))
+	}
+
+	commit(func(txn *state.Txn) {
+		txn.AddBalance(snapAddr1, big.NewInt(1))
+		txn.SetState(snapAddr1, snapSlot1, types.StringToHash("1"))
+		txn.SetState(snapAddr1, snapSlot2, types.StringToHash("2"))
+		txn.AddBalance(snapAddr2, big.NewInt(2))
+	})
+
+	commit(func(txn *state.Txn) {
+		txn.SetState(snapAddr1, snapSlot2, types.ZeroHash)
+		txn.AddBalance(snapAddr2, big.NewInt(2))
+	})
+
+	commit(func(txn *state.Txn) {
+		txn.Suicide(snapAddr1)
+	})
+
+	commit(func(txn *state.Txn) {
+		txn.CreateAccount(snapAddr1)
+		txn.SetNonce(snapAddr1, 1)
+		txn.SetState(snapAddr1, snapSlot2, types.StringToHash("3"))
+	})
+
+	return roots
+}
+
+// assertSnapshotState checks the accounts and the storage read from the snapshot against the tries
+func assertSnapshotState(t *testing.T, tree *SnapshotTree, storage Storage, root types.Hash) {
+	t.Helper()
+
+	tries := NewState(storage)
+
+	trie, err := tries.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	for _, addr := range []types.Address{snapAddr1, snapAddr2} {
+		addrHash := keccak.Keccak256(nil, addr.Bytes())
+
+		expected, expectedFound := trie.Get(addrHash)
+
+		data, found, ok := tree.account(root, addrHash)
+		assert.True(t, ok)
+		assert.Equal(t, expectedFound, found)
+
+		if !found {
+			continue
+		}
+
+		assert.Equal(t, expected, data)
+
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(data))
+
+		storageTrie, err := tries.NewSnapshotAt(account.Root)
+		assert.NoError(t, err)
+
+		for _, slot := range []types.Hash{snapSlot1, snapSlot2} {
+			slotHash := keccak.Keccak256(nil, slot.Bytes())
+
+			expected, expectedFound := storageTrie.Get(slotHash)
+
+			data, found, ok := tree.slot(root, types.BytesToHash(addrHash), slotHash)
+			assert.True(t, ok)
+			assert.Equal(t, expectedFound, found)
+			assert.Equal(t, expected, data)
+		}
+	}
+}
+
+func TestSnapshotTree_Layers(t *testing.T) {
+	t.Parallel()
+
+	storage := NewMemoryStorage()
+	tree := NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+
+	st := NewState(storage)
+	st.Snapshots = tree
+
+	roots := buildSnapshotStates(t, st)
+	head := roots[len(roots)-1]
+
+	tree.Load(head)
+
+	// the states are read from the diff layers
+	assert.Len(t, tree.layers, len(roots)-1)
+
+	for _, root := range roots[1:] {
+		assertSnapshotState(t, tree, storage, root)
+	}
+
+	// the states are flattened into the snapshot on disk
+	assert.NoError(t, tree.Close(head))
+	assert.Len(t, tree.layers, 0)
+
+	tree = NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+	assert.Equal(t, head, tree.diskRoot)
+
+	tree.Load(head)
+	assert.True(t, tree.Status().Ready)
+
+	assertSnapshotState(t, tree, storage, head)
+
+	// the flattened states are read from the tries
+	_, _, ok := tree.account(roots[1], keccak.Keccak256(nil, snapAddr1.Bytes()))
+	assert.False(t, ok)
+}
+
+func TestSnapshotTree_Cap(t *testing.T) {
+	t.Parallel()
+
+	storage := NewMemoryStorage()
+	tree := NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+
+	st := NewState(storage)
+	st.Snapshots = tree
+
+	root := types.EmptyRootHash
+	for i := 0; i < maxDiffLayers+2; i++ {
+		root = commitState(t, st, root, func(txn *state.Txn) {
+			txn.AddBalance(snapAddr1, big.NewInt(1))
+		})
+	}
+
+	// the oldest layers are flattened
+	assert.Len(t, tree.layers, maxDiffLayers)
+	assert.NotEqual(t, types.EmptyRootHash, tree.diskRoot)
+
+	snap, err := st.NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	account, ok := state.NewTxn(st, snap).GetAccount(snapAddr1)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(maxDiffLayers+2), account.Balance)
+}
+
+func TestSnapshotTree_Generate(t *testing.T) {
+	t.Parallel()
+
+	storage := NewMemoryStorage()
+
+	// the states are committed without a snapshot
+	roots := buildSnapshotStates(t, NewState(storage))
+	head := roots[len(roots)-1]
+
+	tree := NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+	tree.Load(head)
+
+	assert.Eventually(t, func() bool {
+		return tree.Status().Ready
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := tree.Status()
+	assert.Equal(t, head, status.Root)
+	assert.False(t, status.Generating)
+
+	assertSnapshotState(t, tree, storage, head)
+
+	// the generation isn't repeated once the snapshot is on disk
+	tree = NewSnapshotTree(storage.(SnapshotStorage), storage, hclog.NewNullLogger())
+	tree.Load(head)
+	assert.True(t, tree.Status().Ready)
+}
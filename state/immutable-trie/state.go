@@ -13,6 +13,13 @@ import (
 type State struct {
 	storage Storage
 	cache   *lru.Cache
+
+	// RecordPreimages enables the recording of the preimages of the hashed
+	// trie keys, the addresses and the storage slots, as they are committed
+	RecordPreimages bool
+
+	// Snapshots is the flat snapshot of the state, read before the tries if set
+	Snapshots *SnapshotTree
 }
 
 func NewState(storage Storage) *State {
@@ -31,7 +38,7 @@ func (s *State) NewSnapshot() state.Snapshot {
 	t.state = s
 	t.storage = s.storage
 
-	return t
+	return s.withFlat(t, types.EmptyRootHash)
 }
 
 func (s *State) SetCode(hash types.Hash, code []byte) {
@@ -62,7 +69,7 @@ func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
 			return nil, errors.New("invalid type assertion")
 		}
 
-		return trie, nil
+		return s.withFlat(trie, root), nil
 	}
 
 	n, ok, err := GetNode(root.Bytes(), s.storage)
@@ -81,7 +88,20 @@ func (s *State) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
 		storage: s.storage,
 	}
 
-	return t, nil
+	return s.withFlat(t, root), nil
+}
+
+// withFlat returns the trie reading the state with the given root from the flat snapshot.
+// The cached tries are shared, so the trie is copied
+func (s *State) withFlat(t *Trie, root types.Hash) *Trie {
+	if s.Snapshots == nil {
+		return t
+	}
+
+	tt := *t
+	tt.flat = &flatState{tree: s.Snapshots, root: root}
+
+	return &tt
 }
 
 func (s *State) AddState(root types.Hash, t *Trie) {
@@ -2,11 +2,13 @@ package itrie
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -15,6 +17,8 @@ var parserPool fastrlp.ParserPool
 var (
 	// codePrefix is the code prefix for leveldb
 	codePrefix = []byte("code")
+	// preimagePrefix is the prefix of the preimages of the hashed trie keys
+	preimagePrefix = []byte("preimage")
 )
 
 type Batch interface {
@@ -81,6 +85,42 @@ func (kv *KVStorage) Get(k []byte) ([]byte, bool) {
 	return data, true
 }
 
+// kvSnapshotBatch is a batch write of the flat snapshot for leveldb
+type kvSnapshotBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+}
+
+func (b *kvSnapshotBatch) Put(k, v []byte) {
+	b.batch.Put(k, v)
+}
+
+func (b *kvSnapshotBatch) Delete(k []byte) {
+	b.batch.Delete(k)
+}
+
+func (b *kvSnapshotBatch) DeletePrefix(prefix []byte) {
+	iter := b.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		// the trie nodes are keyed by their hash, which can start with any prefix
+		if len(iter.Key()) == types.HashLength {
+			continue
+		}
+
+		b.batch.Delete(copyBytes(iter.Key()))
+	}
+}
+
+func (b *kvSnapshotBatch) Write() error {
+	return b.db.Write(b.batch, nil)
+}
+
+func (kv *KVStorage) SnapshotBatch() SnapshotBatch {
+	return &kvSnapshotBatch{db: kv.db, batch: &leveldb.Batch{}}
+}
+
 func (kv *KVStorage) Close() error {
 	return kv.db.Close()
 }
@@ -150,6 +190,58 @@ func (m *memBatch) Put(p, v []byte) {
 func (m *memBatch) Write() {
 }
 
+// memSnapshotBatch is a batch write of the flat snapshot, the operations are applied in order on write
+type memSnapshotBatch struct {
+	storage *memStorage
+	ops     []func()
+}
+
+func (b *memSnapshotBatch) Put(k, v []byte) {
+	k, v = copyBytes(k), copyBytes(v)
+
+	b.ops = append(b.ops, func() {
+		b.storage.Put(k, v)
+	})
+}
+
+func (b *memSnapshotBatch) Delete(k []byte) {
+	key := hex.EncodeToHex(k)
+
+	b.ops = append(b.ops, func() {
+		delete(b.storage.db, key)
+	})
+}
+
+func (b *memSnapshotBatch) DeletePrefix(prefix []byte) {
+	hexPrefix := hex.EncodeToHex(prefix)
+
+	// the keys are hex encoded with the 0x prefix, the trie nodes are keyed by their hash
+	nodeKeyLen := 2 + 2*types.HashLength
+
+	// the entries are selected when the prefix is deleted, as with leveldb
+	for key := range b.storage.db {
+		if strings.HasPrefix(key, hexPrefix) && len(key) != nodeKeyLen {
+			key := key
+
+			b.ops = append(b.ops, func() {
+				delete(b.storage.db, key)
+			})
+		}
+	}
+}
+
+func (b *memSnapshotBatch) Write() error {
+	for _, op := range b.ops {
+		op()
+	}
+
+	return nil
+}
+
+func (m *memStorage) SnapshotBatch() SnapshotBatch {
+	return &memSnapshotBatch{storage: m}
+}
+
 // GetNode retrieves a node from storage
 func GetNode(root []byte, storage Storage) (Node, bool, error) {
 	data, ok := storage.Get(root)
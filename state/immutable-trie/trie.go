@@ -3,6 +3,7 @@ package itrie
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -94,6 +95,9 @@ type Trie struct {
 	root    Node
 	epoch   uint32
 	storage Storage
+
+	// flat is the state of the trie in the flat snapshot, if any
+	flat *flatState
 }
 
 func NewTrie() *Trie {
@@ -101,6 +105,12 @@ func NewTrie() *Trie {
 }
 
 func (t *Trie) Get(k []byte) ([]byte, bool) {
+	if t.flat != nil {
+		if data, found, ok := t.flat.tree.account(t.flat.root, k); ok {
+			return data, found
+		}
+	}
+
 	txn := t.Txn()
 	res := txn.Lookup(k)
 
@@ -131,9 +141,26 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 	ar1 := stateArenaPool.Get()
 	defer stateArenaPool.Put(ar1)
 
+	// the destroyed accounts are removed all at once
+	deletedAccounts := [][]byte{}
+
+	recordPreimages := t.state.RecordPreimages
+
+	// the diff layer of the flat snapshot, if the state of the trie is known to it
+	var diff *diffLayer
+	if t.state.Snapshots != nil && t.flat != nil {
+		diff = newDiffLayer()
+	}
+
 	for _, obj := range objs {
 		if obj.Deleted {
-			tt.Delete(hashit(obj.Address.Bytes()))
+			k := hashit(obj.Address.Bytes())
+			deletedAccounts = append(deletedAccounts, k)
+
+			if diff != nil {
+				diff.accounts[types.BytesToHash(k)] = nil
+				diff.destructs[types.BytesToHash(k)] = struct{}{}
+			}
 		} else {
 			account := state.Account{
 				Balance:  obj.Balance,
@@ -142,6 +169,13 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 				Root:     obj.Root, // old root
 			}
 
+			addrHash := types.BytesToHash(hashit(obj.Address.Bytes()))
+
+			// the storage of an account with an empty root is new, the previous one was discarded
+			if diff != nil && obj.Root == types.EmptyRootHash {
+				diff.destructs[addrHash] = struct{}{}
+			}
+
 			if len(obj.Storage) != 0 {
 				localSnapshot, err := t.state.NewSnapshotAt(obj.Root)
 				if err != nil {
@@ -156,16 +190,34 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 				localTxn := trie.Txn()
 				localTxn.batch = batch
 
+				// the cleared slots are removed in a single pass over the storage trie
+				deletedSlots := [][]byte{}
+
 				for _, entry := range obj.Storage {
 					k := hashit(entry.Key)
+					if recordPreimages {
+						writePreimage(batch, k, entry.Key)
+					}
+
 					if entry.Deleted {
-						localTxn.Delete(k)
+						deletedSlots = append(deletedSlots, k)
+
+						if diff != nil {
+							diff.setSlot(addrHash, types.BytesToHash(k), nil)
+						}
 					} else {
 						vv := ar1.NewBytes(bytes.TrimLeft(entry.Val, "\x00"))
-						localTxn.Insert(k, vv.MarshalTo(nil))
+						data := vv.MarshalTo(nil)
+						localTxn.Insert(k, data)
+
+						if diff != nil {
+							diff.setSlot(addrHash, types.BytesToHash(k), data)
+						}
 					}
 				}
 
+				localTxn.DeleteBatch(deletedSlots)
+
 				accountStateRoot, _ := localTxn.Hash()
 				accountStateTrie := localTxn.Commit()
 
@@ -182,11 +234,23 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 			vv := account.MarshalWith(arena)
 			data := vv.MarshalTo(nil)
 
-			tt.Insert(hashit(obj.Address.Bytes()), data)
+			k := addrHash.Bytes()
+			if recordPreimages {
+				writePreimage(batch, k, obj.Address.Bytes())
+			}
+
+			tt.Insert(k, data)
+
+			if diff != nil {
+				diff.accounts[addrHash] = data
+			}
+
 			arena.Reset()
 		}
 	}
 
+	tt.DeleteBatch(deletedAccounts)
+
 	root, _ := tt.Hash()
 
 	nTrie := tt.Commit()
@@ -198,6 +262,14 @@ func (t *Trie) Commit(objs []*state.Object) (state.Snapshot, []byte) {
 
 	t.state.AddState(types.BytesToHash(root), nTrie)
 
+	if diff != nil {
+		diff.parent = t.flat.root
+		diff.root = types.BytesToHash(root)
+		t.state.Snapshots.update(diff)
+
+		nTrie = t.state.withFlat(nTrie, diff.root)
+	}
+
 	return nTrie, root
 }
 
@@ -468,17 +540,7 @@ func (t *Txn) delete(node Node, search []byte) (Node, bool) {
 			return nil, false
 		}
 
-		if child == nil {
-			return nil, true
-		}
-
-		if short, ok := child.(*ShortNode); ok {
-			// merge nodes
-			return &ShortNode{key: concat(n.key, short.key), child: short.child}, true
-		} else {
-			// full node
-			return &ShortNode{key: n.key, child: child}, true
-		}
+		return mergeShortNode(n.key, child), true
 
 	case *ValueNode:
 		if n.hash {
@@ -513,49 +575,76 @@ func (t *Txn) delete(node Node, search []byte) (Node, bool) {
 
 		n.setEdge(key, newChild)
 
-		indx := -1
+		return t.collapse(n)
+	}
+
+	panic("it should not happen")
+}
 
-		var notEmpty bool
+// DeleteBatch removes the given keys in a single pass over the trie. The nodes on the
+// paths shared by the keys are resolved from the storage and copied once for the whole
+// batch, instead of once per key, which makes clearing many entries proportional to the batch
+func (t *Txn) DeleteBatch(keys [][]byte) {
+	if len(keys) == 0 {
+		return
+	}
 
-		for edge, i := range n.children {
-			if i != nil {
-				if indx != -1 {
-					notEmpty = true
+	search := make([][]byte, len(keys))
+	for i, key := range keys {
+		search[i] = bytesToHexNibbles(key)
+	}
 
-					break
-				} else {
-					indx = edge
-				}
+	sort.Slice(search, func(i, j int) bool {
+		return bytes.Compare(search[i], search[j]) < 0
+	})
+
+	root, ok := t.deleteBatch(t.root, search)
+	if ok {
+		t.root = root
+	}
+}
+
+// deleteBatch removes the sorted keys from the node,
+// it returns false if none of the keys is in the node
+func (t *Txn) deleteBatch(node Node, search [][]byte) (Node, bool) {
+	if len(search) == 1 {
+		return t.delete(node, search[0])
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return nil, false
+
+	case *ShortNode:
+		// the keys are sorted, the ones under the node are contiguous
+		matches := make([][]byte, 0, len(search))
+
+		for _, key := range search {
+			if len(key) < len(n.key) || !bytes.Equal(key[:len(n.key)], n.key) {
+				continue
 			}
-		}
 
-		if indx != -1 && n.value != nil {
-			// We have one children and value, set notEmpty to true
-			notEmpty = true
+			if len(key) == len(n.key) {
+				return nil, true
+			}
+
+			matches = append(matches, key[len(n.key):])
 		}
 
-		if notEmpty {
-			// The full node still has some other values
-			return n, true
+		if len(matches) == 0 {
+			return nil, false
 		}
 
-		if indx == -1 {
-			// There are no children nodes
-			if n.value == nil {
-				// Everything is empty, return nil
-				return nil, true
-			}
-			// The value is the only left, return a short node with it
-			return &ShortNode{key: []byte{0x10}, child: n.value}, true
+		child, ok := t.deleteBatch(n.child, matches)
+		if !ok {
+			return nil, false
 		}
 
-		// Only one value left at indx
-		nc := n.children[indx]
+		return mergeShortNode(n.key, child), true
 
-		if vv, ok := nc.(*ValueNode); ok && vv.hash {
-			// If the value is a hash, we have to resolve it first.
-			// This needs better testing
-			aux, ok, err := GetNode(vv.buf, t.storage)
+	case *ValueNode:
+		if n.hash {
+			nc, ok, err := GetNode(n.buf, t.storage)
 			if err != nil {
 				panic(err)
 			}
@@ -564,28 +653,148 @@ func (t *Txn) delete(node Node, search []byte) (Node, bool) {
 				return nil, false
 			}
 
-			nc = aux
+			return t.deleteBatch(nc, search)
 		}
 
-		obj, ok := nc.(*ShortNode)
-		if !ok {
-			obj := &ShortNode{}
-			obj.key = []byte{byte(indx)}
-			obj.child = nc
+		for _, key := range search {
+			if len(key) == 0 {
+				return nil, true
+			}
+		}
+
+		return nil, false
+
+	case *FullNode:
+		n = n.copy()
+
+		var deleted bool
+
+		for start := 0; start < len(search); {
+			if len(search[start]) == 0 {
+				start++
+
+				continue
+			}
 
-			return obj, true
+			// group the keys going through the same edge
+			edge := search[start][0]
+			end := start + 1
+
+			for end < len(search) && len(search[end]) != 0 && search[end][0] == edge {
+				end++
+			}
+
+			group := make([][]byte, 0, end-start)
+			for _, key := range search[start:end] {
+				group = append(group, key[1:])
+			}
+
+			if newChild, ok := t.deleteBatch(n.getEdge(edge), group); ok {
+				n.setEdge(edge, newChild)
+
+				deleted = true
+			}
+
+			start = end
 		}
 
-		ncc := &ShortNode{}
-		ncc.key = concat([]byte{byte(indx)}, obj.key)
-		ncc.child = obj.child
+		if !deleted {
+			return nil, false
+		}
 
-		return ncc, true
+		return t.collapse(n)
 	}
 
 	panic("it should not happen")
 }
 
+// collapse shrinks a full node left with a single child or value after a delete
+func (t *Txn) collapse(n *FullNode) (Node, bool) {
+	indx := -1
+
+	var notEmpty bool
+
+	for edge, i := range n.children {
+		if i != nil {
+			if indx != -1 {
+				notEmpty = true
+
+				break
+			} else {
+				indx = edge
+			}
+		}
+	}
+
+	if indx != -1 && n.value != nil {
+		// We have one children and value, set notEmpty to true
+		notEmpty = true
+	}
+
+	if notEmpty {
+		// The full node still has some other values
+		return n, true
+	}
+
+	if indx == -1 {
+		// There are no children nodes
+		if n.value == nil {
+			// Everything is empty, return nil
+			return nil, true
+		}
+		// The value is the only left, return a short node with it
+		return &ShortNode{key: []byte{0x10}, child: n.value}, true
+	}
+
+	// Only one value left at indx
+	nc := n.children[indx]
+
+	if vv, ok := nc.(*ValueNode); ok && vv.hash {
+		// If the value is a hash, we have to resolve it first.
+		// This needs better testing
+		aux, ok, err := GetNode(vv.buf, t.storage)
+		if err != nil {
+			panic(err)
+		}
+
+		if !ok {
+			return nil, false
+		}
+
+		nc = aux
+	}
+
+	obj, ok := nc.(*ShortNode)
+	if !ok {
+		obj := &ShortNode{}
+		obj.key = []byte{byte(indx)}
+		obj.child = nc
+
+		return obj, true
+	}
+
+	ncc := &ShortNode{}
+	ncc.key = concat([]byte{byte(indx)}, obj.key)
+	ncc.child = obj.child
+
+	return ncc, true
+}
+
+// mergeShortNode returns the node replacing a short node whose child changed after a delete
+func mergeShortNode(key []byte, child Node) Node {
+	if child == nil {
+		return nil
+	}
+
+	if short, ok := child.(*ShortNode); ok {
+		// merge nodes
+		return &ShortNode{key: concat(key, short.key), child: short.child}
+	}
+
+	// full node
+	return &ShortNode{key: key, child: child}
+}
+
 func prefixLen(k1, k2 []byte) int {
 	max := len(k1)
 	if l := len(k2); l < max {
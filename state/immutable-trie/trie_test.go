@@ -0,0 +1,160 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStorageSlots(n int) []*state.StorageObject {
+	slots := make([]*state.StorageObject, n)
+
+	for i := range slots {
+		slots[i] = &state.StorageObject{
+			Key: types.BytesToHash(big.NewInt(int64(i)).Bytes()).Bytes(),
+			Val: big.NewInt(int64(i + 1)).Bytes(),
+		}
+	}
+
+	return slots
+}
+
+func TestTxn_DeleteBatch(t *testing.T) {
+	t.Parallel()
+
+	keys := [][]byte{}
+	for i := 0; i < 100; i++ {
+		keys = append(keys, hashit(big.NewInt(int64(i)).Bytes()))
+	}
+
+	build := func() *Txn {
+		txn := NewTrie().Txn()
+		for _, key := range keys {
+			txn.Insert(key, key)
+		}
+
+		return txn
+	}
+
+	// the batch ends up with the same trie as deleting the keys one by one
+	deleted := [][]byte{hashit([]byte("missing"))}
+	deleted = append(deleted, keys[10:50]...)
+
+	single := build()
+	for _, key := range deleted {
+		single.Delete(key)
+	}
+
+	batch := build()
+	batch.DeleteBatch(deleted)
+
+	singleRoot, _ := single.Hash()
+	batchRoot, _ := batch.Hash()
+	assert.Equal(t, singleRoot, batchRoot)
+
+	for i, key := range keys {
+		assert.Equal(t, i < 10 || i >= 50, batch.Lookup(key) != nil)
+	}
+
+	// deleting all the keys empties the trie
+	batch.DeleteBatch(keys)
+	assert.Nil(t, batch.root)
+}
+
+func TestTrie_Commit_ClearStorage(t *testing.T) {
+	t.Parallel()
+
+	addr := types.StringToAddress("1")
+	slots := testStorageSlots(200)
+
+	storage := NewMemoryStorage()
+
+	_, root := NewState(storage).NewSnapshot().Commit([]*state.Object{
+		{
+			Address: addr,
+			Balance: big.NewInt(1),
+			Root:    types.EmptyRootHash,
+			Storage: slots,
+		},
+	})
+
+	// the storage trie is read back from the storage, not from the cache
+	st := NewState(storage)
+
+	snap, err := st.NewSnapshotAt(types.BytesToHash(root))
+	assert.NoError(t, err)
+
+	data, ok := snap.Get(hashit(addr.Bytes()))
+	assert.True(t, ok)
+
+	var account state.Account
+	assert.NoError(t, account.UnmarshalRlp(data))
+
+	cleared := make([]*state.StorageObject, 0, 150)
+	for _, slot := range slots[:150] {
+		cleared = append(cleared, &state.StorageObject{Key: slot.Key, Deleted: true})
+	}
+
+	_, root = snap.Commit([]*state.Object{
+		{
+			Address: addr,
+			Balance: big.NewInt(1),
+			Root:    account.Root,
+			Storage: cleared,
+		},
+	})
+
+	// same state as if only the remaining slots were ever written
+	_, expected := NewState(NewMemoryStorage()).NewSnapshot().Commit([]*state.Object{
+		{
+			Address: addr,
+			Balance: big.NewInt(1),
+			Root:    types.EmptyRootHash,
+			Storage: slots[150:],
+		},
+	})
+
+	assert.Equal(t, expected, root)
+}
+
+func TestTrie_Commit_DeleteAccounts(t *testing.T) {
+	t.Parallel()
+
+	objs := make([]*state.Object, 20)
+	for i := range objs {
+		objs[i] = &state.Object{
+			Address: types.BytesToAddress(big.NewInt(int64(i + 1)).Bytes()),
+			Balance: big.NewInt(int64(i + 1)),
+			Root:    types.EmptyRootHash,
+		}
+	}
+
+	storage := NewMemoryStorage()
+
+	_, root := NewState(storage).NewSnapshot().Commit(objs)
+
+	snap, err := NewState(storage).NewSnapshotAt(types.BytesToHash(root))
+	assert.NoError(t, err)
+
+	deleted := make([]*state.Object, 0, 15)
+	for _, obj := range objs[:15] {
+		deleted = append(deleted, &state.Object{Address: obj.Address, Deleted: true})
+	}
+
+	_, root = snap.Commit(deleted)
+
+	_, expected := NewState(NewMemoryStorage()).NewSnapshot().Commit(objs[15:])
+
+	assert.Equal(t, expected, root)
+
+	// destroying the remaining accounts empties the state
+	for _, obj := range objs[15:] {
+		deleted = append(deleted, &state.Object{Address: obj.Address, Deleted: true})
+	}
+
+	_, root = snap.Commit(deleted)
+	assert.Equal(t, types.EmptyRootHash.Bytes(), root)
+}
@@ -85,6 +85,8 @@ func init() {
 	// store
 	register(SLOAD, handler{opSload, 1, 0})
 	register(SSTORE, handler{opSStore, 2, 0})
+	register(TLOAD, handler{opTload, 1, 100})
+	register(TSTORE, handler{opTstore, 2, 100})
 
 	register(SHA3, handler{opSha3, 2, 30})
 
@@ -106,6 +108,7 @@ func init() {
 	register(GASPRICE, handler{opGasPrice, 0, 2})
 	register(RETURNDATASIZE, handler{opReturnDataSize, 0, 2})
 	register(CHAINID, handler{opChainID, 0, 2})
+	register(BASEFEE, handler{opBaseFee, 0, 2})
 	register(PC, handler{opPC, 0, 2})
 	register(MSIZE, handler{opMSize, 0, 2})
 	register(GAS, handler{opGas, 0, 2})
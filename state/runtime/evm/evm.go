@@ -40,6 +40,10 @@ func (e *EVM) Run(c *runtime.Contract, host runtime.Host, config *chain.ForksInT
 	contract.host = host
 	contract.config = config
 
+	if statsHost, ok := host.(runtime.OpcodeStatsHost); ok {
+		contract.stats = statsHost.OpcodeStats()
+	}
+
 	contract.bitmap.setCode(c.Code)
 
 	ret, err := contract.Run()
@@ -43,6 +43,14 @@ func (m *mockHost) SetStorage(
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	panic("Not implemented in tests")
+}
+
 func (m *mockHost) GetBalance(addr types.Address) *big.Int {
 	panic("Not implemented in tests")
 }
@@ -169,3 +177,41 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// mockStatsHost is a mockHost collecting the opcode statistics
+type mockStatsHost struct {
+	mockHost
+
+	stats runtime.OpcodeStats
+}
+
+func (m *mockStatsHost) OpcodeStats() *runtime.OpcodeStats {
+	return &m.stats
+}
+
+func TestRun_OpcodeStats(t *testing.T) {
+	t.Parallel()
+
+	code := []byte{
+		PUSH1, 0x01, PUSH1, 0x02, ADD,
+		PUSH1, 0x00, MSTORE8,
+		PUSH1, 0x01, PUSH1, 0x00, RETURN,
+	}
+
+	host := &mockStatsHost{}
+	res := NewEVM().Run(newMockContract(big.NewInt(0), 5000, code), host, &chain.ForksInTime{})
+	assert.NoError(t, res.Err)
+
+	assert.Equal(t, runtime.OpcodeStat{Count: 5, Gas: 15}, host.stats[PUSH1])
+	assert.Equal(t, runtime.OpcodeStat{Count: 1, Gas: 3}, host.stats[ADD])
+	// the memory expansion is charged to MSTORE8
+	assert.Equal(t, runtime.OpcodeStat{Count: 1, Gas: 6}, host.stats[MSTORE8])
+	assert.Equal(t, runtime.OpcodeStat{Count: 1, Gas: 0}, host.stats[RETURN])
+
+	var totalGas uint64
+	for _, stat := range host.stats {
+		totalGas += stat.Gas
+	}
+
+	assert.Equal(t, 5000-res.GasLeft, totalGas)
+}
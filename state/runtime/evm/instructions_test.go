@@ -378,3 +378,92 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+type mockHostForTransient struct {
+	mockHost
+	storage map[types.Hash]types.Hash
+}
+
+func (m *mockHostForTransient) GetTransientStorage(_ types.Address, key types.Hash) types.Hash {
+	return m.storage[key]
+}
+
+func (m *mockHostForTransient) SetTransientStorage(_ types.Address, key types.Hash, value types.Hash) {
+	m.storage[key] = value
+}
+
+func TestTransientStorage(t *testing.T) {
+	host := &mockHostForTransient{storage: map[types.Hash]types.Hash{}}
+
+	run := func(contract *runtime.Contract, config *chain.ForksInTime, inst instruction, stack ...*big.Int) *state {
+		s, closeFn := getState()
+		t.Cleanup(closeFn)
+
+		s.msg = contract
+		s.config = config
+		s.host = host
+
+		for _, v := range stack {
+			s.push(v)
+		}
+
+		inst(s)
+
+		return s
+	}
+
+	contract := &runtime.Contract{Address: addr1}
+	config := &chain.ForksInTime{EIP1153: true}
+
+	// value, then key on top
+	s := run(contract, config, opTstore, big.NewInt(7), big.NewInt(1))
+	assert.NoError(t, s.err)
+	assert.Equal(t, types.BytesToHash([]byte{7}), host.storage[types.BytesToHash([]byte{1})])
+
+	s = run(contract, config, opTload, big.NewInt(1))
+	assert.NoError(t, s.err)
+	assert.Equal(t, big.NewInt(7), s.top())
+
+	// the transient storage can't be written in a static call
+	s = run(&runtime.Contract{Address: addr1, Static: true}, config, opTstore, big.NewInt(8), big.NewInt(1))
+	assert.Equal(t, errWriteProtection, s.err)
+
+	// the opcodes don't exist before the fork
+	s = run(contract, &chain.ForksInTime{Istanbul: true}, opTload, big.NewInt(1))
+	assert.Equal(t, errOpCodeNotFound, s.err)
+
+	s = run(contract, &chain.ForksInTime{Istanbul: true}, opTstore, big.NewInt(8), big.NewInt(1))
+	assert.Equal(t, errOpCodeNotFound, s.err)
+	assert.Equal(t, types.BytesToHash([]byte{7}), host.storage[types.BytesToHash([]byte{1})])
+}
+
+type mockHostForBaseFee struct {
+	mockHost
+	baseFee uint64
+}
+
+func (m *mockHostForBaseFee) GetTxContext() runtime.TxContext {
+	return runtime.TxContext{BaseFee: types.BytesToHash(new(big.Int).SetUint64(m.baseFee).Bytes())}
+}
+
+func TestBaseFee(t *testing.T) {
+	run := func(config *chain.ForksInTime) *state {
+		s, closeFn := getState()
+		t.Cleanup(closeFn)
+
+		s.config = config
+		s.host = &mockHostForBaseFee{baseFee: 1000000000}
+
+		opBaseFee(s)
+
+		return s
+	}
+
+	s := run(&chain.ForksInTime{London: true})
+	assert.NoError(t, s.err)
+	assert.Equal(t, big.NewInt(1000000000), s.top())
+
+	// the opcode doesn't exist before the fork
+	s = run(&chain.ForksInTime{Istanbul: true})
+	assert.Equal(t, errOpCodeNotFound, s.err)
+}
@@ -79,6 +79,9 @@ type state struct {
 
 	returnData []byte
 	ret        []byte
+
+	// stats are the opcode statistics of the host, the opcodes aren't recorded if nil
+	stats *runtime.OpcodeStats
 }
 
 func (c *state) reset() {
@@ -88,6 +91,7 @@ func (c *state) reset() {
 	c.lastGasCost = 0
 	c.stop = false
 	c.err = nil
+	c.stats = nil
 
 	// reset bitmap
 	c.bitmap.reset()
@@ -234,6 +238,8 @@ func (c *state) Run() ([]byte, error) {
 
 			break
 		}
+		gasBefore := c.gas
+
 		// consume the gas of the instruction
 		if !c.consumeGas(inst.gas) {
 			c.exit(errOutOfGas)
@@ -244,6 +250,11 @@ func (c *state) Run() ([]byte, error) {
 		// execute the instruction
 		inst.inst(c)
 
+		if c.stats != nil && c.gas <= gasBefore {
+			// the gas of the calls and creates includes the gas used by the callee
+			c.stats.Record(byte(op), gasBefore-c.gas)
+		}
+
 		// check if stack size exceeds the max size
 		if c.sp > stackSize {
 			c.exit(errStackOverflow)
@@ -0,0 +1,22 @@
+package runtime
+
+// OpcodeStat is the number of executions of an opcode and the gas they used
+type OpcodeStat struct {
+	Count uint64
+	Gas   uint64
+}
+
+// OpcodeStats are the statistics of the opcodes executed, indexed by opcode
+type OpcodeStats [256]OpcodeStat
+
+// Record records an execution of the opcode which used the given gas
+func (s *OpcodeStats) Record(op byte, gas uint64) {
+	s[op].Count++
+	s[op].Gas += gas
+}
+
+// OpcodeStatsHost is implemented by the hosts collecting the statistics of the opcodes they execute
+type OpcodeStatsHost interface {
+	// OpcodeStats returns the statistics to record the executed opcodes in, or nil
+	OpcodeStats() *OpcodeStats
+}
@@ -23,6 +23,26 @@ type Snapshot interface {
 	Commit(objs []*Object) (Snapshot, []byte)
 }
 
+// Prefetcher is a State loading the accounts of a state ahead of the execution reading them
+type Prefetcher interface {
+	// Prefetch loads the accounts of the state with the given root with the given number of workers,
+	// until they are all loaded or stopCh is closed
+	Prefetch(root types.Hash, addrs []types.Address, workers int, stopCh <-chan struct{})
+}
+
+// StorageSnapshot is a snapshot reading the storage of its accounts from a flat
+// snapshot of the state, instead of traversing their storage tries
+type StorageSnapshot interface {
+	// StorageAt returns the reader of the storage of the account with the given hashed
+	// address and storage root, false if the storage is not in the flat snapshot
+	StorageAt(addrHash []byte, root types.Hash) (StorageReader, bool)
+}
+
+// StorageReader reads the storage slots of an account by their hashed keys
+type StorageReader interface {
+	Get(k []byte) ([]byte, bool)
+}
+
 // account trie
 type accountTrie interface {
 	Get(k []byte) ([]byte, bool)
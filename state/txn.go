@@ -21,6 +21,9 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// transientIndex is the prefix of the transient storage slots in the trie
+	transientIndex = types.BytesToHash([]byte{4}).Bytes()
 )
 
 // Txn is a reference of the state
@@ -117,6 +120,8 @@ func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
 	// Load trie from memory if there is some state
 	if account.Root == emptyStateHash {
 		account.Trie = txn.state.NewSnapshot()
+	} else if storage, ok := txn.storageAt(addr, account.Root); ok {
+		account.Trie = storage
 	} else {
 		account.Trie, err = txn.state.NewSnapshotAt(account.Root)
 		if err != nil {
@@ -131,6 +136,16 @@ func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
 	return obj, true
 }
 
+// storageAt returns the reader of the storage of the account from the flat snapshot, if any
+func (txn *Txn) storageAt(addr types.Address, root types.Hash) (StorageReader, bool) {
+	snapshot, ok := txn.snapshot.(StorageSnapshot)
+	if !ok {
+		return nil, false
+	}
+
+	return snapshot.StorageAt(txn.hashit(addr.Bytes()), root)
+}
+
 func (txn *Txn) upsertAccount(addr types.Address, create bool, f func(object *StateObject)) {
 	object, exists := txn.getStateObject(addr)
 	if !exists && create {
@@ -461,6 +476,45 @@ func (txn *Txn) HasSuicided(addr types.Address) bool {
 	return exists && object.Suicide
 }
 
+// Transient storage (eip-1153)
+
+// transientKey returns the key of the transient storage slot of the address in the trie
+func transientKey(addr types.Address, key types.Hash) []byte {
+	k := make([]byte, 0, len(transientIndex)+types.AddressLength+types.HashLength)
+	k = append(k, transientIndex...)
+	k = append(k, addr.Bytes()...)
+
+	return append(k, key.Bytes()...)
+}
+
+// GetTransientState returns the value of the transient storage slot of the address
+func (txn *Txn) GetTransientState(addr types.Address, key types.Hash) types.Hash {
+	val, exists := txn.txn.Get(transientKey(addr, key))
+	if !exists {
+		return types.Hash{}
+	}
+
+	//nolint:forcetypeassert
+	return val.(types.Hash)
+}
+
+// SetTransientState sets the value of the transient storage slot of the address.
+// The slots are kept in the trie, so that they are reverted along with the snapshots
+func (txn *Txn) SetTransientState(addr types.Address, key, value types.Hash) {
+	if value == zeroHash {
+		txn.txn.Delete(transientKey(addr, key))
+
+		return
+	}
+
+	txn.txn.Insert(transientKey(addr, key), value)
+}
+
+// ClearTransientStorage discards the transient storage, at the end of each transaction
+func (txn *Txn) ClearTransientStorage() {
+	txn.txn.DeletePrefix(transientIndex)
+}
+
 // Refund
 func (txn *Txn) AddRefund(gas uint64) {
 	refund := txn.GetRefund() + gas
@@ -592,6 +646,14 @@ func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
 }
 
 func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
+	t, hash, _ := txn.CommitObjects(deleteEmptyObjects)
+
+	return t, hash
+}
+
+// CommitObjects commits the changes like Commit, and returns the modified accounts as well,
+// with their modified storage slots. The storage root of an account is the one before the changes
+func (txn *Txn) CommitObjects(deleteEmptyObjects bool) (Snapshot, []byte, []*Object) {
 	txn.CleanDeleteObjects(deleteEmptyObjects)
 
 	x := txn.txn.Commit()
@@ -640,5 +702,5 @@ func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
 
 	t, hash := txn.snapshot.Commit(objs)
 
-	return t, hash
+	return t, hash, objs
 }
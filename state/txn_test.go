@@ -125,6 +125,32 @@ func randomHash() types.Hash {
 	return types.BytesToHash(b)
 }
 
+func TestTransientStorage(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	txn.SetTransientState(addr1, hash1, hash1)
+	assert.Equal(t, hash1, txn.GetTransientState(addr1, hash1))
+
+	// the transient storage isn't the persistent one
+	assert.Equal(t, types.Hash{}, txn.GetState(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr2, hash1))
+
+	// the transient writes are reverted along with the snapshots
+	ss := txn.Snapshot()
+	txn.SetTransientState(addr1, hash1, hash2)
+	txn.SetTransientState(addr1, hash2, hash2)
+	txn.RevertToSnapshot(ss)
+
+	assert.Equal(t, hash1, txn.GetTransientState(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr1, hash2))
+
+	txn.SetTransientState(addr2, hash2, hash2)
+	txn.ClearTransientStorage()
+
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientState(addr2, hash2))
+}
+
 func TestSnapshotUpdateData(t *testing.T) {
 	txn := newTestTxn(defaultPreState)
 
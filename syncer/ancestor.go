@@ -0,0 +1,138 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// maxRewindDepth is the maximum number of local blocks unwound to follow the branch of a peer.
+// A peer diverging deeper than that is quarantined instead
+const maxRewindDepth = 1024
+
+var (
+	errDivergentFork       = errors.New("peer is on a divergent fork")
+	errNoCommonAncestor    = errors.New("no common ancestor within the rewind depth")
+	errPeerNotAhead        = errors.New("peer is not ahead of the local chain")
+	errUnknownPeerStatus   = errors.New("peer status is unknown")
+	errLocalHeaderNotFound = errors.New("local header not found")
+)
+
+// findCommonAncestor returns the latest block shared by the local chain and the chain of the peer.
+// It runs a binary search over the headers of the peer, down to maxRewindDepth blocks below the local head
+func (s *syncer) findCommonAncestor(ctx context.Context, peerID peer.ID, peerNumber uint64) (*types.Header, error) {
+	hi := s.blockchain.Header().Number
+	if peerNumber < hi {
+		hi = peerNumber
+	}
+
+	lo := uint64(0)
+	if hi > maxRewindDepth {
+		lo = hi - maxRewindDepth
+	}
+
+	// the lower bound must be shared for the search to find the fork point
+	ancestor, shared, err := s.isSharedWithPeer(ctx, peerID, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !shared {
+		return nil, errNoCommonAncestor
+	}
+
+	header, shared, err := s.isSharedWithPeer(ctx, peerID, hi)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared {
+		return header, nil
+	}
+
+	// lo is always shared and hi never is
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+
+		header, shared, err := s.isSharedWithPeer(ctx, peerID, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if shared {
+			lo, ancestor = mid, header
+		} else {
+			hi = mid
+		}
+	}
+
+	return ancestor, nil
+}
+
+// isSharedWithPeer returns the local header of the given number,
+// and whether the peer has the same header in its chain
+func (s *syncer) isSharedWithPeer(ctx context.Context, peerID peer.ID, number uint64) (*types.Header, bool, error) {
+	local, ok := s.blockchain.GetHeaderByNumber(number)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %d", errLocalHeaderNotFound, number)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+	defer cancel()
+
+	headers, err := s.syncPeerClient.GetHeaders(reqCtx, peerID, number, 1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(headers) == 0 || headers[0].Number != number {
+		return nil, false, fmt.Errorf("%w: no header %d returned", ErrPeerNoResponse, number)
+	}
+
+	return local, headers[0].Hash == local.Hash, nil
+}
+
+// rewindToCommonAncestor unwinds the local chain to its fork point with the chain of the peer,
+// so that the branch of the peer can be synced. Only a peer ahead of the local chain is followed
+func (s *syncer) rewindToCommonAncestor(peerID peer.ID) error {
+	status := s.peerMap.Get(peerID)
+	if status == nil {
+		return errUnknownPeerStatus
+	}
+
+	head := s.blockchain.Header()
+	if status.Number <= head.Number {
+		return errPeerNotAhead
+	}
+
+	ancestor, err := s.findCommonAncestor(s.sessionContext(), peerID, status.Number)
+	if err != nil {
+		return err
+	}
+
+	if ancestor.Number == head.Number {
+		// the local head is on the chain of the peer, there is nothing to unwind
+		return nil
+	}
+
+	if number, ok := s.trustedCheckpoints.highestInRange(ancestor.Number, head.Number); ok {
+		// the local chain includes the checkpoint, the chain of the peer doesn't
+		return fmt.Errorf("%w: block %d", ErrCheckpointMismatch, number)
+	}
+
+	s.logger.Warn(
+		"rewinding to the common ancestor with the sync peer",
+		"peer ID", peerID,
+		"from", head.Number,
+		"to", ancestor.Number,
+		"hash", ancestor.Hash,
+	)
+
+	// the checkpoint is on the unwound branch
+	s.discardCheckpoint()
+
+	return s.blockchain.RewindTo(ancestor.Number, syncerName)
+}
@@ -0,0 +1,211 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// createForkChain returns num blocks on top of the parent, on another branch than createMockChain
+func createForkChain(parent *types.Header, num int) []*types.Block {
+	header := (&types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Sha3Uncles: types.EmptyUncleHash,
+		TxRoot:     types.EmptyRootHash,
+		ExtraData:  []byte("fork"),
+	}).ComputeHash()
+
+	return append([]*types.Block{{Header: header}}, createMockChain(header, num-1)...)
+}
+
+// mockChain is a local chain which can be unwound, the header of a block is at the index of its number
+type mockChain struct {
+	headers []*types.Header
+	written []*types.Block
+}
+
+func newMockChain(head *types.Header, blocks ...[]*types.Block) *mockChain {
+	c := &mockChain{headers: []*types.Header{head}}
+
+	for _, branch := range blocks {
+		for _, block := range branch {
+			c.headers = append(c.headers, block.Header)
+		}
+	}
+
+	return c
+}
+
+func (c *mockChain) blockchain() *mockBlockchain {
+	return &mockBlockchain{
+		headerHandler: func() *types.Header {
+			return c.headers[len(c.headers)-1]
+		},
+		getHeaderByNumberHandler: func(number uint64) (*types.Header, bool) {
+			if number >= uint64(len(c.headers)) {
+				return nil, false
+			}
+
+			return c.headers[number], true
+		},
+		verifyFinalizedBlockHandler: func(b *types.Block) error {
+			return nil
+		},
+		writeBlockHandler: func(b *types.Block) error {
+			c.headers = append(c.headers, b.Header)
+			c.written = append(c.written, b)
+
+			return nil
+		},
+		rewindToHandler: func(number uint64) error {
+			c.headers = c.headers[:number+1]
+
+			return nil
+		},
+	}
+}
+
+func Test_findCommonAncestor(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	shared := createMockChain(head, 10)
+
+	otherHead := (&types.Header{Number: 0, ExtraData: []byte("other")}).ComputeHash()
+
+	longShared := createMockChain(head, maxRewindDepth+100)
+
+	// withGenesis returns the blocks of the peer, which serves its genesis too
+	withGenesis := func(genesis *types.Header, branches ...[]*types.Block) []*types.Block {
+		blocks := []*types.Block{{Header: genesis}}
+		for _, branch := range branches {
+			blocks = append(blocks, branch...)
+		}
+
+		return blocks
+	}
+
+	tests := []struct {
+		name       string
+		local      *mockChain
+		peerBlocks []*types.Block
+		ancestor   uint64
+		err        error
+	}{
+		{
+			name:       "should find the fork point",
+			local:      newMockChain(head, shared, createForkChain(shared[9].Header, 5)),
+			peerBlocks: withGenesis(head, shared, createMockChain(shared[9].Header, 10)),
+			ancestor:   10,
+		},
+		{
+			name:       "should find the fork point above the genesis",
+			local:      newMockChain(head, createForkChain(head, 15)),
+			peerBlocks: withGenesis(head, createMockChain(head, 20)),
+			ancestor:   0,
+		},
+		{
+			name:       "should return the local head if the peer extends the local chain",
+			local:      newMockChain(head, shared),
+			peerBlocks: withGenesis(head, createMockChain(head, 20)),
+			ancestor:   10,
+		},
+		{
+			name:       "should fail if the peer has another genesis",
+			local:      newMockChain(head, shared),
+			peerBlocks: withGenesis(otherHead, createMockChain(otherHead, 20)),
+			err:        errNoCommonAncestor,
+		},
+		{
+			name:       "should fail if the fork point is deeper than the rewind depth",
+			local:      newMockChain(head, longShared[:10], createForkChain(longShared[9].Header, maxRewindDepth+90)),
+			peerBlocks: withGenesis(head, longShared),
+			err:        errNoCommonAncestor,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+
+			client := newHeaderFirstSyncPeerClient(test.peerBlocks)
+			getHeaders := client.getHeadersHandler
+			client.getHeadersHandler = func(ctx context.Context, id peer.ID, from, amount uint64) ([]*types.Header, error) {
+				requests++
+
+				return getHeaders(ctx, id, from, amount)
+			}
+
+			syncer := NewTestSyncer(nil, test.local.blockchain(), time.Second, client, &mockProgression{})
+
+			ancestor, err := syncer.findCommonAncestor(
+				context.Background(),
+				peer.ID("A"),
+				test.peerBlocks[len(test.peerBlocks)-1].Number(),
+			)
+
+			assert.ErrorIs(t, err, test.err)
+
+			if test.err == nil {
+				assert.Equal(t, test.ancestor, ancestor.Number)
+				assert.Equal(t, test.local.headers[test.ancestor].Hash, ancestor.Hash)
+			}
+
+			// the headers are looked up with a binary search
+			assert.LessOrEqual(t, requests, 2+12)
+		})
+	}
+}
+
+func Test_bulkSyncWithPeer_RewindToCommonAncestor(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	shared := createMockChain(head, 10)
+	peerBranch := createMockChain(shared[9].Header, 10)
+
+	local := newMockChain(head, shared, createForkChain(shared[9].Header, 5))
+
+	syncer := NewTestSyncer(
+		nil,
+		local.blockchain(),
+		time.Second,
+		newHeaderFirstSyncPeerClient(append(append([]*types.Block{{Header: head}}, shared...), peerBranch...)),
+		&mockProgression{},
+	)
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   20,
+		Distance: big.NewInt(1),
+	})
+
+	lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), lastNumber)
+
+	// the local branch is replaced with the branch of the peer
+	assert.Equal(t, peerBranch, local.written)
+	assert.Equal(t, peerBranch[9].Hash(), local.headers[20].Hash)
+
+	// the fork isn't a failure of the peer
+	assert.False(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+
+	scores := syncer.PeerScores()
+	if assert.Len(t, scores, 1) {
+		assert.Empty(t, scores[0].Failures)
+	}
+}
@@ -0,0 +1,71 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// DefaultRetainedBlocks is the default number of recent blocks whose bodies and receipts are kept in the gateway mode
+const DefaultRetainedBlocks uint64 = 4096
+
+var (
+	// ErrReceiptsNotFound is returned when no sync peer serves the valid receipts of the requested block
+	ErrReceiptsNotFound = errors.New("receipts not found on the sync peers")
+
+	errInvalidArchivePeer = errors.New("invalid archive peer, expected a multiaddr with the peer ID")
+)
+
+// ParseArchivePeers parses the multiaddrs of the archive peers, which must include the peer IDs
+func ParseArchivePeers(raw []string) ([]*peer.AddrInfo, error) {
+	peers := make([]*peer.AddrInfo, 0, len(raw))
+
+	for _, r := range raw {
+		info, err := common.StringToAddrInfo(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidArchivePeer, r)
+		}
+
+		peers = append(peers, info)
+	}
+
+	return peers, nil
+}
+
+// FetchReceipts fetches the receipts of the block of the given header from the sync peers serving them,
+// the archive peers first, and verifies them against the header. It serves the receipts of the blocks
+// older than the retention window in the gateway sync mode, and of any block in the light sync mode
+func (s *syncer) FetchReceipts(ctx context.Context, header *types.Header) ([]*types.Receipt, error) {
+	for _, p := range s.peerMap.ArchivePeersWithBlock(header.Number, maxBodyFetchPeers, proto.Capability_RECEIPTS) {
+		reqCtx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+		receipts, err := s.syncPeerClient.GetReceipts(reqCtx, p.ID, []types.Hash{header.Hash})
+
+		cancel()
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err != nil || len(receipts) == 0 {
+			s.logger.Debug("peer didn't serve the requested receipts", "peer ID", p.ID, "number", header.Number, "error", err)
+
+			continue
+		}
+
+		if err := verifyReceipts(header, receipts[0]); err != nil {
+			err = s.failPeer(p.ID, FailureInvalidBlock, err)
+			s.logger.Warn("peer served invalid receipts", "peer ID", p.ID, "error", err)
+
+			continue
+		}
+
+		return receipts[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: block %d", ErrReceiptsNotFound, header.Number)
+}
@@ -0,0 +1,121 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArchivePeers(t *testing.T) {
+	t.Parallel()
+
+	rawID := "QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC"
+
+	peers, err := ParseArchivePeers([]string{
+		"/ip4/127.0.0.1/tcp/1478/p2p/" + rawID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+	assert.Equal(t, rawID, peers[0].ID.String())
+
+	_, err = ParseArchivePeers([]string{"/ip4/127.0.0.1/tcp/1478"})
+	assert.ErrorIs(t, err, errInvalidArchivePeer)
+}
+
+func TestPeerMap_ArchivePeersWithBlock(t *testing.T) {
+	t.Parallel()
+
+	peerMap := &PeerMap{
+		archive: newPeerIDSet([]*peer.AddrInfo{{ID: peer.ID("C")}}),
+	}
+	peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(1)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 10, Distance: big.NewInt(2)},
+		&NoForkPeer{ID: peer.ID("C"), Number: 10, Distance: big.NewInt(3)},
+		// D doesn't serve the receipts
+		&NoForkPeer{
+			ID:           peer.ID("D"),
+			Number:       10,
+			Distance:     big.NewInt(0),
+			Version:      ProtocolVersion2,
+			Capabilities: ModeGateway.capabilities(),
+		},
+	)
+
+	peers := peerMap.ArchivePeersWithBlock(5, 2, proto.Capability_RECEIPTS)
+
+	assert.Len(t, peers, 2)
+	assert.Equal(t, peer.ID("C"), peers[0].ID)
+	assert.Equal(t, peer.ID("A"), peers[1].ID)
+}
+
+func Test_syncer_FetchReceipts(t *testing.T) {
+	t.Parallel()
+
+	receipts := []*types.Receipt{
+		{CumulativeGasUsed: 21000, GasUsed: 21000, TxHash: types.StringToHash("1")},
+		{CumulativeGasUsed: 50000, GasUsed: 29000, TxHash: types.StringToHash("2")},
+	}
+	receipts[0].SetStatus(types.ReceiptSuccess)
+	receipts[1].SetStatus(types.ReceiptSuccess)
+
+	header := (&types.Header{
+		Number:       1,
+		ReceiptsRoot: buildroot.CalculateReceiptsRoot(receipts),
+	}).ComputeHash()
+
+	// A is the best peer but serves tampered receipts, B serves the right ones
+	client := &mockSyncPeerClient{
+		getReceiptsHandler: func(_ context.Context, peerID peer.ID, hashes []types.Hash) ([][]*types.Receipt, error) {
+			assert.Equal(t, []types.Hash{header.Hash}, hashes)
+
+			if peerID == peer.ID("A") {
+				return [][]*types.Receipt{receipts[:1]}, nil
+			}
+
+			return [][]*types.Receipt{receipts}, nil
+		},
+	}
+
+	syncer := NewTestSyncer(nil, nil, time.Second, client, &mockProgression{})
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 2, Distance: big.NewInt(1)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 2, Distance: big.NewInt(2)},
+	)
+
+	fetched, err := syncer.FetchReceipts(context.Background(), header)
+	assert.NoError(t, err)
+	assert.Equal(t, receipts, fetched)
+
+	scores := syncer.PeerScores()
+	assert.Len(t, scores, 1)
+	assert.Equal(t, peer.ID("A"), scores[0].ID)
+	assert.Equal(t, uint64(1), scores[0].Failures[FailureInvalidBlock])
+
+	_, err = syncer.FetchReceipts(context.Background(), (&types.Header{Number: 3}).ComputeHash())
+	assert.ErrorIs(t, err, ErrReceiptsNotFound)
+}
+
+func Test_verifyReceipts(t *testing.T) {
+	t.Parallel()
+
+	receipts := []*types.Receipt{
+		{CumulativeGasUsed: 21000, GasUsed: 21000},
+		{CumulativeGasUsed: 50000, GasUsed: 29000},
+	}
+	header := &types.Header{ReceiptsRoot: buildroot.CalculateReceiptsRoot(receipts)}
+
+	assert.NoError(t, verifyReceipts(header, receipts))
+	assert.ErrorIs(t, verifyReceipts(header, receipts[:1]), errReceiptsRootMismatch)
+
+	// the gas used isn't part of the root
+	receipts[1].GasUsed = 1
+	assert.ErrorIs(t, verifyReceipts(header, receipts), errReceiptsGasUsedInvalid)
+}
@@ -0,0 +1,211 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// CheckpointFileName is the name of the file the header-first sync progress is persisted to
+const CheckpointFileName = "sync_checkpoint"
+
+// checkpointStore persists the header-first sync progress, so that the blocks
+// validated but not written yet aren't fetched again after a restart
+type checkpointStore struct {
+	path string
+}
+
+// load reads the checkpoint, it returns nil if there is none
+func (c *checkpointStore) load() (*proto.SyncCheckpoint, error) {
+	if c.path == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &proto.SyncCheckpoint{}
+	if err := googleProto.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// save writes the checkpoint to a temporary file and renames it,
+// so that a crash in the middle of the write leaves the previous one
+func (c *checkpointStore) save(checkpoint *proto.SyncCheckpoint) error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := googleProto.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := c.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, c.path)
+}
+
+// clear removes the checkpoint
+func (c *checkpointStore) clear() error {
+	if c.path == "" {
+		return nil
+	}
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// toCheckpoint returns the queued headers and the delivered bodies
+func (q *syncQueue) toCheckpoint(pivot uint64) *proto.SyncCheckpoint {
+	checkpoint := &proto.SyncCheckpoint{
+		Pivot:  pivot,
+		Blocks: make([]*proto.CheckpointBlock, len(q.headers)),
+	}
+
+	for i, header := range q.headers {
+		block := &proto.CheckpointBlock{
+			Header: header.MarshalRLP(),
+		}
+
+		if body, ok := q.bodies[header.Hash]; ok {
+			block.Body = toProtoBody(body)
+		}
+
+		checkpoint.Blocks[i] = block
+	}
+
+	return checkpoint
+}
+
+// restore queues the blocks of the checkpoint following the last queued header.
+// The blocks up to the last queued header are skipped, as they were written
+// after the checkpoint was saved. The headers and the bodies are validated
+// the same way as the ones received from a peer. It returns the number of restored blocks
+func (q *syncQueue) restore(checkpoint *proto.SyncCheckpoint) (int, error) {
+	var (
+		headers = make([]*types.Header, 0, len(checkpoint.Blocks))
+		hashes  = make([]types.Hash, 0, len(checkpoint.Blocks))
+		bodies  = make([]*types.Body, 0, len(checkpoint.Blocks))
+	)
+
+	for _, block := range checkpoint.Blocks {
+		header := &types.Header{}
+		if err := header.UnmarshalRLP(block.Header); err != nil {
+			return 0, err
+		}
+
+		if header.Number <= q.last.Number {
+			continue
+		}
+
+		headers = append(headers, header)
+
+		if block.Body == nil {
+			continue
+		}
+
+		body, err := fromProtoBody(block.Body)
+		if err != nil {
+			return 0, err
+		}
+
+		hashes = append(hashes, header.Hash)
+		bodies = append(bodies, body)
+	}
+
+	if err := q.addHeaders(headers); err != nil {
+		return 0, err
+	}
+
+	if err := q.deliverBodies(hashes, bodies); err != nil {
+		return 0, fmt.Errorf("invalid checkpoint bodies, %w", err)
+	}
+
+	return len(headers), nil
+}
+
+// restoreCheckpoint queues the blocks of the persisted checkpoint.
+// It returns the number of the last restored block, or the local head if none
+func (s *syncer) restoreCheckpoint(queue *syncQueue) uint64 {
+	head := queue.last
+
+	checkpoint, err := s.checkpoint.load()
+	if err != nil {
+		s.logger.Warn("failed to load sync checkpoint", "error", err)
+		s.discardCheckpoint()
+
+		return head.Number
+	}
+
+	if checkpoint == nil {
+		return head.Number
+	}
+
+	restored, err := queue.restore(checkpoint)
+	if err != nil {
+		s.logger.Warn("failed to restore sync checkpoint", "error", err)
+		s.discardCheckpoint()
+		queue.reset(head)
+
+		return head.Number
+	}
+
+	if restored > 0 {
+		s.logger.Info(
+			"resuming sync from checkpoint",
+			"from", head.Number+1,
+			"to", queue.last.Number,
+			"pivot", checkpoint.Pivot,
+		)
+	}
+
+	return queue.last.Number
+}
+
+// saveCheckpoint persists the queue, or removes the checkpoint if the queue is empty
+func (s *syncer) saveCheckpoint(peerID peer.ID, queue *syncQueue) {
+	if queue.len() == 0 {
+		s.discardCheckpoint()
+
+		return
+	}
+
+	pivot := queue.last.Number
+	if peer := s.peerMap.Get(peerID); peer != nil && peer.Number > pivot {
+		pivot = peer.Number
+	}
+
+	if err := s.checkpoint.save(queue.toCheckpoint(pivot)); err != nil {
+		s.logger.Warn("failed to save sync checkpoint", "error", err)
+	}
+}
+
+// discardCheckpoint removes the persisted checkpoint
+func (s *syncer) discardCheckpoint() {
+	if err := s.checkpoint.clear(); err != nil {
+		s.logger.Warn("failed to remove sync checkpoint", "error", err)
+	}
+}
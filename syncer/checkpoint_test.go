@@ -0,0 +1,211 @@
+package syncer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCheckpointQueue returns a queue with the headers of the blocks,
+// and the bodies of the first withBodies blocks
+func newCheckpointQueue(t *testing.T, head *types.Header, blocks []*types.Block, withBodies int) *syncQueue {
+	t.Helper()
+
+	queue := newSyncQueue(head)
+
+	headers := make([]*types.Header, len(blocks))
+	hashes := make([]types.Hash, withBodies)
+	bodies := make([]*types.Body, withBodies)
+
+	for i, block := range blocks {
+		headers[i] = block.Header
+
+		if i < withBodies {
+			hashes[i] = block.Hash()
+			bodies[i] = block.Body()
+		}
+	}
+
+	assert.NoError(t, queue.addHeaders(headers))
+	assert.NoError(t, queue.deliverBodies(hashes, bodies))
+
+	return queue
+}
+
+func TestCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	queue := newCheckpointQueue(t, head, createMockChain(head, 5), 3)
+
+	store := &checkpointStore{path: filepath.Join(t.TempDir(), CheckpointFileName)}
+
+	// no checkpoint was saved yet
+	checkpoint, err := store.load()
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	assert.NoError(t, store.save(queue.toCheckpoint(20)))
+
+	checkpoint, err = store.load()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), checkpoint.Pivot)
+	assert.Len(t, checkpoint.Blocks, 5)
+
+	for i, block := range checkpoint.Blocks {
+		assert.Equal(t, i < 3, block.Body != nil)
+	}
+
+	assert.NoError(t, store.clear())
+	assert.NoError(t, store.clear())
+
+	checkpoint, err = store.load()
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func TestSyncQueue_Restore(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 5)
+	checkpoint := newCheckpointQueue(t, head, blocks, 3).toCheckpoint(5)
+
+	// the first two blocks were written after the checkpoint was saved
+	queue := newSyncQueue(blocks[1].Header)
+
+	restored, err := queue.restore(checkpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, restored)
+	assert.Equal(t, blocks[4].Hash(), queue.last.Hash)
+
+	// only the body of block 3 is restored
+	assert.Equal(t, []types.Hash{blocks[3].Hash(), blocks[4].Hash()}, queue.pendingBodies(5))
+
+	popped := queue.popBlocks()
+	assert.Len(t, popped, 1)
+	assert.Equal(t, blocks[2].Hash(), popped[0].Hash())
+
+	// the checkpoint doesn't follow the head of another chain
+	otherHead := (&types.Header{Number: 0, ExtraData: []byte("other")}).ComputeHash()
+
+	_, err = newSyncQueue(otherHead).restore(checkpoint)
+	assert.ErrorIs(t, err, ErrMismatchedParent)
+}
+
+func Test_bulkSyncWithPeer_ResumeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 20)
+
+	otherHead := (&types.Header{Number: 0, ExtraData: []byte("other")}).ComputeHash()
+	otherBlocks := createMockChain(otherHead, 20)
+
+	tests := []struct {
+		name            string
+		checkpoint      *syncQueue
+		peerBlocks      []*types.Block
+		expectedFetched int
+		expectedWritten int
+		shouldFail      bool
+	}{
+		{
+			name:            "should not fetch the bodies of the checkpoint again",
+			checkpoint:      newCheckpointQueue(t, head, blocks[:10], 10),
+			peerBlocks:      blocks,
+			expectedFetched: 10,
+			expectedWritten: 20,
+		},
+		{
+			name:            "should fetch the pending bodies of the checkpoint",
+			checkpoint:      newCheckpointQueue(t, head, blocks[:10], 4),
+			peerBlocks:      blocks,
+			expectedFetched: 16,
+			expectedWritten: 20,
+		},
+		{
+			name:            "should discard the checkpoint not extended by the peer",
+			checkpoint:      newCheckpointQueue(t, head, blocks[:10], 10),
+			peerBlocks:      otherBlocks,
+			expectedFetched: 0,
+			expectedWritten: 10,
+			shouldFail:      true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				written    []*types.Block
+				fetched    int
+				fetchedMux sync.Mutex
+				latestHead = head
+			)
+
+			client := newHeaderFirstSyncPeerClient(test.peerBlocks)
+			getBodies := client.getBodiesHandler
+			client.getBodiesHandler = func(ctx context.Context, id peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+				fetchedMux.Lock()
+				fetched += len(hashes)
+				fetchedMux.Unlock()
+
+				return getBodies(ctx, id, hashes)
+			}
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						written = append(written, b)
+						latestHead = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				client,
+				&mockProgression{},
+			)
+
+			syncer.checkpoint.path = filepath.Join(t.TempDir(), CheckpointFileName)
+			assert.NoError(t, syncer.checkpoint.save(test.checkpoint.toCheckpoint(20)))
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			assert.Equal(t, test.shouldFail, err != nil)
+			assert.Equal(t, test.expectedFetched, fetched)
+
+			assert.Equal(t, uint64(test.expectedWritten), lastNumber)
+			assert.Len(t, written, test.expectedWritten)
+
+			// the checkpoint is removed once the queue is empty or discarded
+			_, statErr := os.Stat(syncer.checkpoint.path)
+			assert.ErrorIs(t, statErr, os.ErrNotExist)
+
+			// the peer isn't blamed for the checkpoint
+			for _, score := range syncer.PeerScores() {
+				assert.Zero(t, score.Failures[FailureHashMismatch])
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -13,8 +15,12 @@ import (
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/0xPolygon/polygon-edge/syncer/proto"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	googleProto "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -29,19 +35,31 @@ type syncPeerClient struct {
 	network    Network      // reference to the network module
 	blockchain Blockchain   // reference to the blockchain module
 
-	subscription           blockchain.Subscription // reference to the blockchain subscription
-	topic                  *network.Topic          // reference to the network topic
-	id                     string                  // node id
-	peerStatusUpdateCh     chan *NoForkPeer        // peer status update channel
-	peerConnectionUpdateCh chan *event.PeerEvent   // peer connection update channel
+	subscription           *blockchain.BusSubscription // reference to the new heads subscription
+	topic                  *network.Topic              // reference to the network topic
+	id                     string                      // node id
+	peerStatusUpdateCh     chan *NoForkPeer            // peer status update channel
+	peerConnectionUpdateCh chan *event.PeerEvent       // peer connection update channel
+
+	closeCh   chan struct{} // channel closed on Close, it aborts the pending updates
+	closeLock sync.RWMutex  // lock preventing the updates from being sent while the channels are closed
+	closed    bool          // flag indicating the update channels are closed
 
 	shouldEmitBlocks bool // flag for emitting blocks in the topic
+
+	compression  proto.Compression  // compression algorithm requested for the block streams
+	capabilities []proto.Capability // optional parts of the protocol served, advertised in the gossiped status
+
+	metrics *Metrics // metrics of the downloaded data
 }
 
 func NewSyncPeerClient(
 	logger hclog.Logger,
 	network Network,
 	blockchain Blockchain,
+	compression proto.Compression,
+	mode SyncMode,
+	metrics *Metrics,
 ) SyncPeerClient {
 	return &syncPeerClient{
 		logger:                 logger.Named(SyncPeerClientLoggerName),
@@ -50,13 +68,17 @@ func NewSyncPeerClient(
 		id:                     network.AddrInfo().ID.String(),
 		peerStatusUpdateCh:     make(chan *NoForkPeer, 1),
 		peerConnectionUpdateCh: make(chan *event.PeerEvent, 1),
+		closeCh:                make(chan struct{}),
 		shouldEmitBlocks:       true,
+		compression:            compression,
+		capabilities:           mode.capabilities(),
+		metrics:                metrics,
 	}
 }
 
 // Start processes for SyncPeerClient
 func (m *syncPeerClient) Start() error {
-	go m.startNewBlockProcess()
+	go m.startNewBlockProcess(m.subscribeNewHeads())
 	go m.startPeerEventProcess()
 
 	if err := m.startGossip(); err != nil {
@@ -69,11 +91,20 @@ func (m *syncPeerClient) Start() error {
 // Close terminates running processes for SyncPeerClient
 func (m *syncPeerClient) Close() {
 	if m.subscription != nil {
-		m.subscription.Close()
+		m.subscription.Unsubscribe()
 
 		m.subscription = nil
 	}
 
+	// abort the pending updates, the gossip and the peer events
+	// may still be delivered while closing
+	close(m.closeCh)
+
+	m.closeLock.Lock()
+	defer m.closeLock.Unlock()
+
+	m.closed = true
+
 	close(m.peerStatusUpdateCh)
 	close(m.peerConnectionUpdateCh)
 }
@@ -88,7 +119,8 @@ func (m *syncPeerClient) EnablePublishingPeerStatus() {
 	m.shouldEmitBlocks = true
 }
 
-// GetPeerStatus fetches peer status
+// GetPeerStatus fetches peer status.
+// It returns ErrIncompatiblePeer if the peer doesn't speak any version of the protocol spoken by the node
 func (m *syncPeerClient) GetPeerStatus(peerID peer.ID) (*NoForkPeer, error) {
 	clt, err := m.newSyncPeerClient(peerID)
 	if err != nil {
@@ -103,11 +135,12 @@ func (m *syncPeerClient) GetPeerStatus(peerID peer.ID) (*NoForkPeer, error) {
 		return nil, err
 	}
 
-	return &NoForkPeer{
-		ID:       peerID,
-		Number:   status.Number,
-		Distance: m.network.GetPeerDistance(peerID),
-	}, nil
+	peer := statusToPeer(peerID, status, m.network.GetPeerDistance(peerID))
+	if peer.Version == 0 {
+		return nil, ErrIncompatiblePeer
+	}
+
+	return peer, nil
 }
 
 // GetConnectedPeerStatuses fetches the statuses of all connecting peers
@@ -132,6 +165,8 @@ func (m *syncPeerClient) GetConnectedPeerStatuses() []*NoForkPeer {
 			status, err := m.GetPeerStatus(peerID)
 			if err != nil {
 				m.logger.Warn("failed to get status from a peer, skip", "id", peerID, "err", err)
+
+				return
 			}
 
 			syncPeersLock.Lock()
@@ -190,30 +225,59 @@ func (m *syncPeerClient) handleStatusUpdate(obj interface{}, from peer.ID) {
 		return
 	}
 
-	m.peerStatusUpdateCh <- &NoForkPeer{
-		ID:       from,
-		Number:   status.Number,
-		Distance: m.network.GetPeerDistance(from),
+	peerStatus := statusToPeer(from, status, m.network.GetPeerDistance(from))
+	if peerStatus.Version == 0 {
+		m.logger.Debug("received status from incompatible peer, ignore", "id", from, "versions", status.Versions)
+
+		return
+	}
+
+	m.closeLock.RLock()
+	defer m.closeLock.RUnlock()
+
+	if m.closed {
+		return
+	}
+
+	select {
+	case m.peerStatusUpdateCh <- peerStatus:
+	case <-m.closeCh:
 	}
 }
 
-// startNewBlockProcess starts blockchain event subscription
-func (m *syncPeerClient) startNewBlockProcess() {
-	m.subscription = m.blockchain.SubscribeEvents()
+// subscribeNewHeads subscribes to the new heads before their process starts,
+// so that no head written meanwhile is missed
+func (m *syncPeerClient) subscribeNewHeads() *blockchain.BusSubscription {
+	m.subscription = m.blockchain.SubscribeBus(blockchain.DefaultBusBuffer, blockchain.TopicNewHeads)
+
+	return m.subscription
+}
 
-	for event := range m.subscription.GetEventCh() {
-		if !m.shouldEmitBlocks {
+// startNewBlockProcess publishes the status of the new heads, until the subscription is cancelled
+func (m *syncPeerClient) startNewBlockProcess(sub *blockchain.BusSubscription) {
+	eventCh := sub.EventCh()
+
+	for evnt := range eventCh {
+		// only the status of the latest queued head is worth publishing
+		if !m.shouldEmitBlocks || len(eventCh) > 0 {
 			continue
 		}
 
-		if l := len(event.NewChain); l > 0 {
-			latest := event.NewChain[l-1]
-			// Publish status
-			if err := m.topic.Publish(&proto.SyncPeerStatus{
-				Number: latest.Number,
-			}); err != nil {
-				m.logger.Warn("failed to publish status", "err", err)
-			}
+		latest := evnt.Head.Header
+		// Publish status
+		status := &proto.SyncPeerStatus{
+			Number:       latest.Number,
+			Hash:         latest.Hash.Bytes(),
+			Versions:     supportedVersions,
+			Capabilities: m.capabilities,
+		}
+
+		if evnt.Head.Difficulty != nil {
+			status.Difficulty = evnt.Head.Difficulty.Bytes()
+		}
+
+		if err := m.topic.Publish(status); err != nil {
+			m.logger.Warn("failed to publish status", "err", err)
 		}
 	}
 }
@@ -229,32 +293,61 @@ func (m *syncPeerClient) startPeerEventProcess() {
 
 	for e := range peerEventCh {
 		if e.Type == event.PeerConnected || e.Type == event.PeerDisconnected {
-			m.peerConnectionUpdateCh <- e
+			if !m.sendPeerConnectionUpdate(e) {
+				return
+			}
 		}
 	}
 }
 
+// sendPeerConnectionUpdate sends the peer event to the update channel,
+// it returns false if the client is closed
+func (m *syncPeerClient) sendPeerConnectionUpdate(e *event.PeerEvent) bool {
+	m.closeLock.RLock()
+	defer m.closeLock.RUnlock()
+
+	if m.closed {
+		return false
+	}
+
+	select {
+	case m.peerConnectionUpdateCh <- e:
+		return true
+	case <-m.closeCh:
+		return false
+	}
+}
+
 // CloseStream closes stream
 func (m *syncPeerClient) CloseStream(peerID peer.ID) error {
 	return m.network.CloseProtocolStream(syncerProto, peerID)
 }
 
 // GetBlocks returns a stream of blocks from given height to peer's latest
+// Canceling the given context closes the gRPC stream and the returned channel
 func (m *syncPeerClient) GetBlocks(
+	ctx context.Context,
 	peerID peer.ID,
 	from uint64,
 	timeoutPerBlock time.Duration,
 ) (<-chan *types.Block, error) {
 	clt, err := m.newSyncPeerClient(peerID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create sync peer client: %w", err)
+		return nil, fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 
-	stream, err := clt.GetBlocks(ctx, &proto.GetBlocksRequest{
+	req := &proto.GetBlocksRequest{
 		From: from,
-	})
+	}
+
+	if m.compression != proto.Compression_NONE {
+		// peers not supporting the compression send uncompressed blocks
+		req.Compression = []proto.Compression{m.compression}
+	}
+
+	stream, err := clt.GetBlocks(ctx, req)
 	if err != nil {
 		cancel()
 
@@ -262,23 +355,37 @@ func (m *syncPeerClient) GetBlocks(
 	}
 
 	// input channel
-	streamBlockCh, streamErrorCh := blockStreamToChannel(stream)
+	streamBlockCh, streamErrorCh := blockStreamToChannel(ctx, stream, m.downloadedBytes(peerID))
 
 	// output channel
 	blockCh := make(chan *types.Block, 1)
 
 	go func() {
-		defer cancel()
 		defer close(blockCh)
 
+		defer func() {
+			// cancel the stream and wait for the reader goroutine to finish
+			// so that no goroutine remains after blockCh is closed
+			cancel()
+
+			for range streamBlockCh {
+			}
+		}()
+
 		for {
 			select {
+			case <-ctx.Done():
+				return
 			case block, ok := <-streamBlockCh:
 				if !ok {
 					return
 				}
 
-				blockCh <- block
+				select {
+				case blockCh <- block:
+				case <-ctx.Done():
+					return
+				}
 			case err := <-streamErrorCh:
 				m.logger.Error("failed to get block from gRPC stream", "peer", peerID, "err", err)
 
@@ -294,6 +401,217 @@ func (m *syncPeerClient) GetBlocks(
 	return blockCh, nil
 }
 
+// GetHeaders fetches up to the given amount of consecutive headers from given height
+func (m *syncPeerClient) GetHeaders(
+	ctx context.Context,
+	peerID peer.ID,
+	from uint64,
+	amount uint64,
+) ([]*types.Header, error) {
+	clt, closeConn, err := m.openSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
+	}
+
+	defer closeConn()
+
+	resp, err := clt.GetHeaders(ctx, &proto.GetHeadersRequest{
+		From:   from,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.downloadedBytes(peerID).Add(float64(googleProto.Size(resp)))
+
+	headers := make([]*types.Header, len(resp.Headers))
+
+	for i, rawHeader := range resp.Headers {
+		header := &types.Header{}
+		if err := header.UnmarshalRLP(rawHeader); err != nil {
+			return nil, err
+		}
+
+		headers[i] = header
+	}
+
+	return headers, nil
+}
+
+// GetBodies fetches the bodies of the blocks with the given hashes, in the same order
+func (m *syncPeerClient) GetBodies(
+	ctx context.Context,
+	peerID peer.ID,
+	hashes []types.Hash,
+) ([]*types.Body, error) {
+	clt, closeConn, err := m.openSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
+	}
+
+	defer closeConn()
+
+	req := &proto.GetBodiesRequest{
+		Hashes: make([][]byte, len(hashes)),
+	}
+
+	for i, hash := range hashes {
+		req.Hashes[i] = hash.Bytes()
+	}
+
+	resp, err := clt.GetBodies(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.downloadedBytes(peerID).Add(float64(googleProto.Size(resp)))
+
+	bodies := make([]*types.Body, len(resp.Bodies))
+
+	for i, protoBody := range resp.Bodies {
+		if bodies[i], err = fromProtoBody(protoBody); err != nil {
+			return nil, err
+		}
+	}
+
+	return bodies, nil
+}
+
+// Ping sends a keepalive ping to the peer and waits for its pong. The peers not serving
+// the pings answer with an Unimplemented error, which shows they are alive
+func (m *syncPeerClient) Ping(ctx context.Context, peerID peer.ID) error {
+	clt, closeConn, err := m.openSyncPeerClient(peerID)
+	if err != nil {
+		return fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
+	}
+
+	defer closeConn()
+
+	nonce := rand.Uint64()
+
+	resp, err := clt.Ping(ctx, &proto.PingRequest{Nonce: nonce})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if resp.Nonce != nonce {
+		return errPongNonceMismatch
+	}
+
+	return nil
+}
+
+// GetReceipts fetches the receipts of the blocks with the given hashes, in the same order
+func (m *syncPeerClient) GetReceipts(
+	ctx context.Context,
+	peerID peer.ID,
+	hashes []types.Hash,
+) ([][]*types.Receipt, error) {
+	clt, closeConn, err := m.openSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
+	}
+
+	defer closeConn()
+
+	req := &proto.GetReceiptsRequest{
+		Hashes: make([][]byte, len(hashes)),
+	}
+
+	for i, hash := range hashes {
+		req.Hashes[i] = hash.Bytes()
+	}
+
+	resp, err := clt.GetReceipts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.downloadedBytes(peerID).Add(float64(googleProto.Size(resp)))
+
+	receipts := make([][]*types.Receipt, len(resp.Receipts))
+
+	for i, raw := range resp.Receipts {
+		var blockReceipts types.Receipts
+		if err := blockReceipts.UnmarshalStoreRLP(raw); err != nil {
+			return nil, err
+		}
+
+		receipts[i] = blockReceipts
+	}
+
+	return receipts, nil
+}
+
+// GetEra fetches the archive of the given complete era and returns its blocks, once the archive is checked.
+// The fetching fails with ErrTimeout if the peer doesn't send a chunk of the archive in time
+func (m *syncPeerClient) GetEra(
+	ctx context.Context,
+	peerID peer.ID,
+	era uint64,
+	timeoutPerChunk time.Duration,
+) ([]*types.Block, error) {
+	clt, closeConn, err := m.openSyncPeerClient(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, err)
+	}
+
+	defer closeConn()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the stream is canceled if a chunk doesn't arrive in time
+	timer := time.AfterFunc(timeoutPerChunk, cancel)
+	defer timer.Stop()
+
+	stream, err := clt.GetEra(streamCtx, &proto.GetEraRequest{Era: era})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		data       []byte
+		downloaded = m.downloadedBytes(peerID)
+	)
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			if !timer.Stop() && ctx.Err() == nil {
+				return nil, ErrTimeout
+			}
+
+			return nil, err
+		}
+
+		timer.Reset(timeoutPerChunk)
+		downloaded.Add(float64(googleProto.Size(chunk)))
+
+		if len(data)+len(chunk.Data) > maxEraArchiveSize {
+			return nil, errEraArchiveTooLarge
+		}
+
+		data = append(data, chunk.Data...)
+	}
+
+	return decodeEra(data, era)
+}
+
+// downloadedBytes returns the counter of the bytes downloaded from the peer
+func (m *syncPeerClient) downloadedBytes(peerID peer.ID) metrics.Counter {
+	return m.metrics.DownloadedBytes.With("peer_id", peerID.String())
+}
+
 // newSyncPeerClient creates gRPC client
 func (m *syncPeerClient) newSyncPeerClient(peerID peer.ID) (proto.SyncPeerClient, error) {
 	conn, err := m.network.NewProtoConnection(syncerProto, peerID)
@@ -306,17 +624,126 @@ func (m *syncPeerClient) newSyncPeerClient(peerID peer.ID) (proto.SyncPeerClient
 	return proto.NewSyncPeerClient(conn), nil
 }
 
+// openSyncPeerClient creates gRPC client for a single request
+// Unlike newSyncPeerClient, the connection is not saved and has to be closed by the caller
+func (m *syncPeerClient) openSyncPeerClient(peerID peer.ID) (proto.SyncPeerClient, func(), error) {
+	conn, err := m.network.NewProtoConnection(syncerProto, peerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open a stream, err %w", err)
+	}
+
+	closeConn := func() {
+		if err := conn.Close(); err != nil {
+			m.logger.Debug("failed to close stream", "peer", peerID, "err", err)
+		}
+	}
+
+	return proto.NewSyncPeerClient(conn), closeConn, nil
+}
+
+// PingPeer sends up to count status requests to the peer over a new sync protocol connection,
+// one after the other. It returns the round trip times of the requests answered before the first failure,
+// and the failure
+func PingPeer(ctx context.Context, network Network, peerID peer.ID, count int) ([]time.Duration, error) {
+	conn, err := network.NewProtoConnection(syncerProto, peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a stream, err %w", err)
+	}
+
+	defer conn.Close()
+
+	clt := proto.NewSyncPeerClient(conn)
+	rtts := make([]time.Duration, 0, count)
+
+	for len(rtts) < count {
+		reqCtx, cancel := context.WithTimeout(ctx, defaultTimeoutForStatus)
+		start := time.Now()
+
+		_, err := clt.GetStatus(reqCtx, &emptypb.Empty{})
+
+		cancel()
+
+		if err != nil {
+			return rtts, err
+		}
+
+		rtts = append(rtts, time.Since(start))
+	}
+
+	return rtts, nil
+}
+
+// statusToPeer gets peer status from gRPC response or gossip data.
+// The hash and the total difficulty are left unset if the peer doesn't report them,
+// the version is 0 if the peer doesn't speak any version spoken by the node
+func statusToPeer(peerID peer.ID, status *proto.SyncPeerStatus, distance *big.Int) *NoForkPeer {
+	peer := &NoForkPeer{
+		ID:           peerID,
+		Number:       status.Number,
+		Distance:     distance,
+		Version:      negotiateVersion(status.Versions),
+		Capabilities: status.Capabilities,
+	}
+
+	if len(status.Hash) == types.HashLength {
+		peer.Hash = types.BytesToHash(status.Hash)
+	}
+
+	if len(status.Difficulty) > 0 {
+		peer.Difficulty = new(big.Int).SetBytes(status.Difficulty)
+	}
+
+	return peer
+}
+
 // fromProto gets block from gRPC response data
 func fromProto(protoBlock *proto.Block) (*types.Block, error) {
+	data, err := decompressBlock(protoBlock.Compression, protoBlock.Block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress block: %w", err)
+	}
+
 	block := &types.Block{}
-	if err := block.UnmarshalRLP(protoBlock.Block); err != nil {
+	if err := block.UnmarshalRLP(data); err != nil {
 		return nil, err
 	}
 
 	return block, nil
 }
 
-func blockStreamToChannel(stream proto.SyncPeer_GetBlocksClient) (<-chan *types.Block, <-chan error) {
+// fromProtoBody gets body from gRPC response data
+func fromProtoBody(protoBody *proto.Body) (*types.Body, error) {
+	body := &types.Body{
+		Transactions: make([]*types.Transaction, len(protoBody.Transactions)),
+		Uncles:       make([]*types.Header, len(protoBody.Uncles)),
+	}
+
+	for i, rawTx := range protoBody.Transactions {
+		tx := &types.Transaction{}
+		if err := tx.UnmarshalRLP(rawTx); err != nil {
+			return nil, err
+		}
+
+		body.Transactions[i] = tx
+	}
+
+	for i, rawUncle := range protoBody.Uncles {
+		uncle := &types.Header{}
+		if err := uncle.UnmarshalRLP(rawUncle); err != nil {
+			return nil, err
+		}
+
+		body.Uncles[i] = uncle
+	}
+
+	return body, nil
+}
+
+func blockStreamToChannel(
+	ctx context.Context,
+	stream proto.SyncPeer_GetBlocksClient,
+	downloaded metrics.Counter,
+) (<-chan *types.Block, <-chan error) {
 	blockCh := make(chan *types.Block)
 	errorCh := make(chan error, 1)
 
@@ -335,6 +762,8 @@ func blockStreamToChannel(stream proto.SyncPeer_GetBlocksClient) (<-chan *types.
 				break
 			}
 
+			downloaded.Add(float64(googleProto.Size(protoBlock)))
+
 			block, err := fromProto(protoBlock)
 			if err != nil {
 				errorCh <- err
@@ -342,7 +771,11 @@ func blockStreamToChannel(stream proto.SyncPeer_GetBlocksClient) (<-chan *types.
 				break
 			}
 
-			blockCh <- block
+			select {
+			case blockCh <- block:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
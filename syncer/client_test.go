@@ -43,6 +43,9 @@ func newTestSyncPeerClient(network Network, blockchain Blockchain) *syncPeerClie
 		id:                     network.AddrInfo().ID.String(),
 		peerStatusUpdateCh:     make(chan *NoForkPeer, 1),
 		peerConnectionUpdateCh: make(chan *event.PeerEvent, 1),
+		closeCh:                make(chan struct{}),
+		capabilities:           ModeFull.capabilities(),
+		metrics:                NilMetrics(),
 	}
 
 	// need to register protocol
@@ -57,8 +60,9 @@ func createTestSyncerService(t *testing.T, chain Blockchain) (*syncPeerService,
 	srv := newTestNetwork(t)
 
 	service := &syncPeerService{
-		blockchain: chain,
-		network:    srv,
+		blockchain:   chain,
+		network:      srv,
+		capabilities: ModeFull.capabilities(),
 	}
 
 	service.Start()
@@ -90,14 +94,44 @@ func TestGetPeerStatus(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := &NoForkPeer{
-		ID:       peerSrv.AddrInfo().ID,
-		Number:   peerLatest,
-		Distance: clientSrv.GetPeerDistance(peerSrv.AddrInfo().ID),
+		ID:           peerSrv.AddrInfo().ID,
+		Number:       peerLatest,
+		Distance:     clientSrv.GetPeerDistance(peerSrv.AddrInfo().ID),
+		Version:      ProtocolVersion2,
+		Capabilities: ModeFull.capabilities(),
 	}
 
 	assert.Equal(t, expected, status)
 }
 
+func TestPingPeer(t *testing.T) {
+	t.Parallel()
+
+	clientSrv := newTestNetwork(t)
+	_ = newTestSyncPeerClient(clientSrv, nil)
+
+	_, peerSrv := createTestSyncerService(t, &mockBlockchain{
+		headerHandler: newSimpleHeaderHandler(10),
+	})
+
+	err := network.JoinAndWait(
+		clientSrv,
+		peerSrv,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	)
+
+	assert.NoError(t, err)
+
+	rtts, err := PingPeer(context.Background(), clientSrv, peerSrv.AddrInfo().ID, 3)
+	assert.NoError(t, err)
+	assert.Len(t, rtts, 3)
+
+	for _, rtt := range rtts {
+		assert.Greater(t, rtt, time.Duration(0))
+	}
+}
+
 func TestGetConnectedPeerStatuses(t *testing.T) {
 	t.Parallel()
 
@@ -143,9 +177,11 @@ func TestGetConnectedPeerStatuses(t *testing.T) {
 			)
 
 			expected[idx] = &NoForkPeer{
-				ID:       peerID,
-				Number:   latest,
-				Distance: clientSrv.GetPeerDistance(peerID),
+				ID:           peerID,
+				Number:       latest,
+				Distance:     clientSrv.GetPeerDistance(peerID),
+				Version:      ProtocolVersion2,
+				Capabilities: ModeFull.capabilities(),
 			}
 		}()
 	}
@@ -208,8 +244,8 @@ func TestStatusPubSub(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	// close channel and wait for events
-	close(client.peerConnectionUpdateCh)
+	// close the client to close the channel and wait for events
+	client.Close()
 
 	wg.Wait()
 
@@ -241,20 +277,18 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 		peerLatest1 = uint64(10)
 		peerLatest2 = uint64(20)
 
-		// blockchain subscription
-		subscription1 = blockchain.NewMockSubscription()
-		subscription2 = blockchain.NewMockSubscription()
+		// blockchain event buses
+		bus1 = blockchain.NewEventBus(hclog.NewNullLogger())
+		bus2 = blockchain.NewEventBus(hclog.NewNullLogger())
 
 		// syncer client
-		client = newTestSyncPeerClient(clientSrv, &mockBlockchain{
-			subscription: &blockchain.MockSubscription{},
-		})
+		client      = newTestSyncPeerClient(clientSrv, &mockBlockchain{})
 		peerClient1 = newTestSyncPeerClient(peerSrv1, &mockBlockchain{
-			subscription:  subscription1,
+			bus:           bus1,
 			headerHandler: newSimpleHeaderHandler(peerLatest1),
 		})
 		peerClient2 = newTestSyncPeerClient(peerSrv2, &mockBlockchain{
-			subscription:  subscription2,
+			bus:           bus2,
 			headerHandler: newSimpleHeaderHandler(peerLatest2),
 		})
 	)
@@ -312,13 +346,17 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 	peerClient2.EnablePublishingPeerStatus()
 
 	// start to subscribe blockchain events
-	go peerClient1.startNewBlockProcess()
-	go peerClient2.startNewBlockProcess()
+	go peerClient1.startNewBlockProcess(peerClient1.subscribeNewHeads())
+	go peerClient2.startNewBlockProcess(peerClient2.subscribeNewHeads())
 
 	// collect peer status changes
 	var (
 		wgForConnectingStatus sync.WaitGroup
 		newStatuses           []*NoForkPeer
+
+		// closed when the first status is received
+		receivedCh   = make(chan struct{})
+		receivedOnce sync.Once
 	)
 
 	wgForConnectingStatus.Add(1)
@@ -328,14 +366,17 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 
 		for status := range client.GetPeerStatusUpdateCh() {
 			newStatuses = append(newStatuses, status)
+
+			receivedOnce.Do(func() { close(receivedCh) })
 		}
 	}()
 
 	// push latest block number to blockchain subscription
-	pushSubscription := func(sub *blockchain.MockSubscription, latest uint64) {
-		sub.Push(&blockchain.Event{
-			NewChain: []*types.Header{
-				{
+	pushSubscription := func(bus *blockchain.EventBus, latest uint64) {
+		bus.Publish(&blockchain.BusEvent{
+			Topic: blockchain.TopicNewHeads,
+			Head: &blockchain.HeadEvent{
+				Header: &types.Header{
 					Number: latest,
 				},
 			},
@@ -344,14 +385,21 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 
 	// peer1 and peer2 emit Blockchain event
 	// they should publish their status via gossip
-	pushSubscription(subscription1, peerLatest1)
-	pushSubscription(subscription2, peerLatest2)
+	pushSubscription(bus1, peerLatest1)
+	pushSubscription(bus2, peerLatest2)
 
 	// wait until 2 messages are propagated
 	wgForGossip.Wait()
 
-	// close to terminate goroutine
-	close(client.peerStatusUpdateCh)
+	// wait until the client receives the status of peer1
+	select {
+	case <-receivedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client didn't receive the peer status")
+	}
+
+	// close to terminate goroutine, late gossip is dropped by the closed client
+	client.Close()
 
 	// wait until collecting routine is done
 	wgForConnectingStatus.Wait()
@@ -359,9 +407,11 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 	// client connects to only peer1, then expects to have a status from peer1
 	expected := []*NoForkPeer{
 		{
-			ID:       peerSrv1.AddrInfo().ID,
-			Number:   peerLatest1,
-			Distance: clientSrv.GetPeerDistance(peerSrv1.AddrInfo().ID),
+			ID:           peerSrv1.AddrInfo().ID,
+			Number:       peerLatest1,
+			Distance:     clientSrv.GetPeerDistance(peerSrv1.AddrInfo().ID),
+			Version:      ProtocolVersion2,
+			Capabilities: ModeFull.capabilities(),
 		},
 	}
 
@@ -371,6 +421,7 @@ func TestPeerConnectionUpdateEventCh(t *testing.T) {
 // Make sure the peer shouldn't emit status if the shouldEmitBlocks flag is set.
 // The subtests cannot contain t.Parallel() due to how
 // the test is organized
+//
 //nolint:tparallel, gofmt
 func Test_shouldEmitBlocks(t *testing.T) {
 	t.Parallel()
@@ -382,10 +433,10 @@ func Test_shouldEmitBlocks(t *testing.T) {
 
 		clientLatest = uint64(10)
 
-		subscription = blockchain.NewMockSubscription()
+		bus = blockchain.NewEventBus(hclog.NewNullLogger())
 
 		client = newTestSyncPeerClient(clientSrv, &mockBlockchain{
-			subscription:  subscription,
+			bus:           bus,
 			headerHandler: newSimpleHeaderHandler(clientLatest),
 		})
 	)
@@ -408,13 +459,14 @@ func Test_shouldEmitBlocks(t *testing.T) {
 	assert.NoError(t, client.startGossip())
 
 	// start to subscribe blockchain events
-	go client.startNewBlockProcess()
+	go client.startNewBlockProcess(client.subscribeNewHeads())
 
 	// push latest block number to blockchain subscription
-	pushSubscription := func(sub *blockchain.MockSubscription, latest uint64) {
-		sub.Push(&blockchain.Event{
-			NewChain: []*types.Header{
-				{
+	pushSubscription := func(bus *blockchain.EventBus, latest uint64) {
+		bus.Publish(&blockchain.BusEvent{
+			Topic: blockchain.TopicNewHeads,
+			Head: &blockchain.HeadEvent{
+				Header: &types.Header{
 					Number: latest,
 				},
 			},
@@ -454,7 +506,7 @@ func Test_shouldEmitBlocks(t *testing.T) {
 			client.DisablePublishingPeerStatus()
 		}
 
-		pushSubscription(subscription, clientLatest)
+		pushSubscription(bus, clientLatest)
 
 		canceled := waitForContext(receiveContext)
 
@@ -505,7 +557,7 @@ func Test_syncPeerClient_GetBlocks(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	blockStream, err := client.GetBlocks(peerSrv.AddrInfo().ID, syncFrom, 5*time.Second)
+	blockStream, err := client.GetBlocks(context.Background(), peerSrv.AddrInfo().ID, syncFrom, 5*time.Second)
 	assert.NoError(t, err)
 
 	blocks := make([]*types.Block, 0, peerLatest)
@@ -521,3 +573,121 @@ func Test_syncPeerClient_GetBlocks(t *testing.T) {
 
 	assert.Equal(t, expected, blocks)
 }
+
+func Test_syncPeerClient_GetBlocks_Cancel(t *testing.T) {
+	t.Parallel()
+
+	clientSrv := newTestNetwork(t)
+	client := newTestSyncPeerClient(clientSrv, nil)
+
+	var (
+		peerLatest = uint64(1000)
+		syncFrom   = uint64(1)
+	)
+
+	_, peerSrv := createTestSyncerService(t, &mockBlockchain{
+		headerHandler: newSimpleHeaderHandler(peerLatest),
+		getBlockByNumberHandler: func(u uint64, b bool) (*types.Block, bool) {
+			return &types.Block{
+				Header: &types.Header{
+					Number: u,
+				},
+			}, true
+		},
+	})
+
+	err := network.JoinAndWait(
+		clientSrv,
+		peerSrv,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	)
+
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blockStream, err := client.GetBlocks(ctx, peerSrv.AddrInfo().ID, syncFrom, 5*time.Second)
+	assert.NoError(t, err)
+
+	// receive the first block and cancel in the middle of the stream
+	block, ok := <-blockStream
+	assert.True(t, ok)
+	assert.Equal(t, syncFrom, block.Number())
+
+	cancel()
+
+	closedCh := make(chan uint64)
+
+	go func() {
+		received := uint64(0)
+		for range blockStream {
+			received++
+		}
+
+		closedCh <- received
+	}()
+
+	select {
+	case received := <-closedCh:
+		assert.Less(t, received, peerLatest-syncFrom)
+	case <-time.After(5 * time.Second):
+		t.Fatal("block stream wasn't closed after cancellation")
+	}
+}
+
+func Test_syncPeerClient_GetHeadersAndBodies(t *testing.T) {
+	t.Parallel()
+
+	clientSrv := newTestNetwork(t)
+	client := newTestSyncPeerClient(clientSrv, nil)
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 10)
+
+	_, peerSrv := createTestSyncerService(t, &mockBlockchain{
+		headerHandler: newSimpleHeaderHandler(10),
+		getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+			if u == 0 || u > 10 {
+				return nil, false
+			}
+
+			return blocks[u-1].Header, true
+		},
+		getBodyByHashHandler: func(h types.Hash) (*types.Body, bool) {
+			for _, b := range blocks {
+				if b.Hash() == h {
+					return b.Body(), true
+				}
+			}
+
+			return nil, false
+		},
+	})
+
+	err := network.JoinAndWait(
+		clientSrv,
+		peerSrv,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	)
+
+	assert.NoError(t, err)
+
+	headers, err := client.GetHeaders(context.Background(), peerSrv.AddrInfo().ID, 3, 4)
+	assert.NoError(t, err)
+	assert.Len(t, headers, 4)
+
+	hashes := make([]types.Hash, len(headers))
+
+	for i, header := range headers {
+		// hash is calculated on unmarshaling
+		assert.Equal(t, blocks[i+2].Hash(), header.Hash)
+
+		hashes[i] = header.Hash
+	}
+
+	bodies, err := client.GetBodies(context.Background(), peerSrv.AddrInfo().ID, hashes)
+	assert.NoError(t, err)
+	assert.Len(t, bodies, len(hashes))
+}
@@ -0,0 +1,91 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxDecompressedBlockSize is the maximum size of a decompressed block,
+// it prevents a peer from exhausting the memory with a small compressed block
+const maxDecompressedBlockSize = 64 * 1024 * 1024
+
+var (
+	// supportedCompressions are the compression algorithms served by the sync peer server
+	supportedCompressions = map[proto.Compression]bool{
+		proto.Compression_SNAPPY: true,
+		proto.Compression_ZSTD:   true,
+	}
+
+	// the zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBlockSize))
+)
+
+var (
+	ErrUnknownCompression = errors.New("unknown compression")
+	errBlockTooLarge      = fmt.Errorf("decompressed block exceeds %d bytes", maxDecompressedBlockSize)
+)
+
+// ParseCompression returns the compression algorithm of the given name, one of none, snappy and zstd
+func ParseCompression(name string) (proto.Compression, error) {
+	compression, ok := proto.Compression_value[strings.ToUpper(name)]
+	if !ok {
+		return proto.Compression_NONE, fmt.Errorf("%w: %s", ErrUnknownCompression, name)
+	}
+
+	return proto.Compression(compression), nil
+}
+
+// selectCompression returns the first compression algorithm accepted by the requester
+// and supported by the server, or none
+func selectCompression(accepted []proto.Compression) proto.Compression {
+	for _, compression := range accepted {
+		if supportedCompressions[compression] {
+			return compression
+		}
+	}
+
+	return proto.Compression_NONE
+}
+
+// compressBlock compresses the encoded block with the given algorithm
+func compressBlock(compression proto.Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case proto.Compression_NONE:
+		return data, nil
+	case proto.Compression_SNAPPY:
+		return snappy.Encode(nil, data), nil
+	case proto.Compression_ZSTD:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, compression)
+	}
+}
+
+// decompressBlock decompresses the encoded block with the given algorithm
+func decompressBlock(compression proto.Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case proto.Compression_NONE:
+		return data, nil
+	case proto.Compression_SNAPPY:
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if size > maxDecompressedBlockSize {
+			return nil, errBlockTooLarge
+		}
+
+		return snappy.Decode(nil, data)
+	case proto.Compression_ZSTD:
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, compression)
+	}
+}
@@ -0,0 +1,100 @@
+package syncer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		compression proto.Compression
+		err         error
+	}{
+		{"none", proto.Compression_NONE, nil},
+		{"snappy", proto.Compression_SNAPPY, nil},
+		{"ZSTD", proto.Compression_ZSTD, nil},
+		{"gzip", proto.Compression_NONE, ErrUnknownCompression},
+	}
+
+	for _, test := range tests {
+		compression, err := ParseCompression(test.name)
+
+		assert.ErrorIs(t, err, test.err)
+		assert.Equal(t, test.compression, compression)
+	}
+}
+
+func TestSelectCompression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		accepted []proto.Compression
+		selected proto.Compression
+	}{
+		{
+			name:     "should not compress for a requester without compression support",
+			accepted: nil,
+			selected: proto.Compression_NONE,
+		},
+		{
+			name:     "should select the first supported compression",
+			accepted: []proto.Compression{proto.Compression_ZSTD, proto.Compression_SNAPPY},
+			selected: proto.Compression_ZSTD,
+		},
+		{
+			name:     "should skip the unknown compressions",
+			accepted: []proto.Compression{proto.Compression(10), proto.Compression_SNAPPY},
+			selected: proto.Compression_SNAPPY,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.selected, selectCompression(test.accepted), test.name)
+	}
+}
+
+func TestCompressBlock(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat(createMockBlocks(1)[0].MarshalRLP(), 16)
+
+	for _, compression := range []proto.Compression{
+		proto.Compression_NONE,
+		proto.Compression_SNAPPY,
+		proto.Compression_ZSTD,
+	} {
+		compressed, err := compressBlock(compression, data)
+		assert.NoError(t, err)
+
+		if compression != proto.Compression_NONE {
+			assert.Less(t, len(compressed), len(data))
+		}
+
+		decompressed, err := decompressBlock(compression, compressed)
+		assert.NoError(t, err)
+		assert.Equal(t, data, decompressed)
+	}
+
+	_, err := compressBlock(proto.Compression(10), data)
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+
+	_, err = decompressBlock(proto.Compression(10), data)
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestDecompressBlock_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	compressed := snappy.Encode(nil, make([]byte, maxDecompressedBlockSize+1))
+
+	_, err := decompressBlock(proto.Compression_SNAPPY, compressed)
+	assert.ErrorIs(t, err, errBlockTooLarge)
+}
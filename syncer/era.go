@@ -0,0 +1,323 @@
+package syncer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The era archives bundle the blocks of an era, so that the history is synced one era at a time
+// in a single request, checked against a single hash. The archive of an era is immutable once the era
+// is complete, and is laid out as
+//
+//	block RLP | ... | block RLP | block offset | ... | block offset | era | block count | checksum
+//
+// the offsets of the blocks from the start of the archive, the era and the number of blocks being
+// big-endian uint64, and the checksum the keccak256 hash of all the preceding bytes
+
+const (
+	// EraSize is the number of blocks of an era, the era N covering the blocks from N*EraSize to (N+1)*EraSize-1
+	EraSize uint64 = 8192
+
+	// eraChunkSize is the size of the chunks the era archives are streamed in
+	eraChunkSize = 1 << 20
+
+	// maxEraArchiveSize is the maximum size of an era archive downloaded from a peer
+	maxEraArchiveSize = 1 << 30
+
+	// eraTrailerSize is the size of the era and the block count ending the index of an archive
+	eraTrailerSize = 16
+)
+
+var (
+	errInvalidEraArchive  = errors.New("invalid era archive")
+	errEraArchiveTooLarge = fmt.Errorf("%w, larger than %d bytes", errInvalidEraArchive, maxEraArchiveSize)
+)
+
+// eraRange returns the first and the last block of the era
+func eraRange(era uint64) (uint64, uint64) {
+	return era * EraSize, (era+1)*EraSize - 1
+}
+
+// completeEras returns the number of complete eras of a chain with the given latest block
+func completeEras(latest uint64) uint64 {
+	return (latest + 1) / EraSize
+}
+
+// eraWriter writes the archive of an era block by block, hashing it along
+type eraWriter struct {
+	w       io.Writer
+	hasher  *keccak.Keccak
+	era     uint64
+	offsets []uint64
+	size    uint64
+}
+
+func newEraWriter(w io.Writer, era uint64) *eraWriter {
+	return &eraWriter{
+		w:       w,
+		hasher:  keccak.NewKeccak256(),
+		era:     era,
+		offsets: make([]uint64, 0, EraSize),
+	}
+}
+
+func (e *eraWriter) write(data []byte) error {
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+
+	_, _ = e.hasher.Write(data)
+	e.size += uint64(len(data))
+
+	return nil
+}
+
+// add appends the block to the archive
+func (e *eraWriter) add(block *types.Block) error {
+	e.offsets = append(e.offsets, e.size)
+
+	return e.write(block.MarshalRLP())
+}
+
+// finish writes the index and the checksum ending the archive
+func (e *eraWriter) finish() error {
+	index := make([]byte, 8*len(e.offsets)+eraTrailerSize)
+
+	for i, offset := range e.offsets {
+		binary.BigEndian.PutUint64(index[8*i:], offset)
+	}
+
+	binary.BigEndian.PutUint64(index[8*len(e.offsets):], e.era)
+	binary.BigEndian.PutUint64(index[8*len(e.offsets)+8:], uint64(len(e.offsets)))
+
+	if err := e.write(index); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(e.hasher.Sum(nil))
+
+	return err
+}
+
+// decodeEra decodes the archive of the era. The checksum of the archive is checked, and its blocks
+// have to be the blocks of the era, each one being the parent of the next
+func decodeEra(data []byte, era uint64) ([]*types.Block, error) {
+	if len(data) < eraTrailerSize+types.HashLength {
+		return nil, fmt.Errorf("%w, truncated", errInvalidEraArchive)
+	}
+
+	content, checksum := data[:len(data)-types.HashLength], data[len(data)-types.HashLength:]
+	if !bytes.Equal(keccak.Keccak256(nil, content), checksum) {
+		return nil, fmt.Errorf("%w, checksum mismatch", errInvalidEraArchive)
+	}
+
+	trailer := content[len(content)-eraTrailerSize:]
+
+	if archiveEra := binary.BigEndian.Uint64(trailer); archiveEra != era {
+		return nil, fmt.Errorf("%w, era %d, want %d", errInvalidEraArchive, archiveEra, era)
+	}
+
+	count := binary.BigEndian.Uint64(trailer[8:])
+	if count != EraSize {
+		return nil, fmt.Errorf("%w, %d blocks, want %d", errInvalidEraArchive, count, EraSize)
+	}
+
+	indexStart := len(content) - eraTrailerSize - 8*int(count)
+	if indexStart < 0 {
+		return nil, fmt.Errorf("%w, truncated index", errInvalidEraArchive)
+	}
+
+	index := content[indexStart : len(content)-eraTrailerSize]
+	number, _ := eraRange(era)
+	blocks := make([]*types.Block, count)
+
+	for i := range blocks {
+		start := binary.BigEndian.Uint64(index[8*i:])
+
+		end := uint64(indexStart)
+		if i+1 < len(blocks) {
+			end = binary.BigEndian.Uint64(index[8*(i+1):])
+		}
+
+		// the blocks are contiguous, from the start of the archive to its index
+		if (i == 0 && start != 0) || start > end || end > uint64(indexStart) {
+			return nil, fmt.Errorf("%w, invalid offset of block %d", errInvalidEraArchive, i)
+		}
+
+		block := &types.Block{}
+		if err := block.UnmarshalRLP(content[start:end]); err != nil {
+			return nil, fmt.Errorf("%w, block %d: %v", errInvalidEraArchive, i, err)
+		}
+
+		if block.Number() != number {
+			return nil, fmt.Errorf("%w, block %d at the height of %d", errInvalidEraArchive, block.Number(), number)
+		}
+
+		if i > 0 && block.ParentHash() != blocks[i-1].Hash() {
+			return nil, fmt.Errorf("%w, block %d doesn't follow its parent", errInvalidEraArchive, block.Number())
+		}
+
+		blocks[i] = block
+		number++
+	}
+
+	return blocks, nil
+}
+
+// eraChunkWriter sends the bytes written to it in chunks of eraChunkSize
+type eraChunkWriter struct {
+	send func(*proto.EraChunk) error
+	buf  []byte
+}
+
+func newEraChunkWriter(send func(*proto.EraChunk) error) *eraChunkWriter {
+	return &eraChunkWriter{
+		send: send,
+		buf:  make([]byte, 0, eraChunkSize),
+	}
+}
+
+func (c *eraChunkWriter) Write(data []byte) (int, error) {
+	written := len(data)
+
+	for len(data) > 0 {
+		n := eraChunkSize - len(c.buf)
+		if n > len(data) {
+			n = len(data)
+		}
+
+		c.buf = append(c.buf, data[:n]...)
+		data = data[n:]
+
+		if len(c.buf) == eraChunkSize {
+			if err := c.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// flush sends the buffered bytes, if any
+func (c *eraChunkWriter) flush() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+
+	if err := c.send(&proto.EraChunk{Data: c.buf}); err != nil {
+		return err
+	}
+
+	// the chunk is marshaled by the send, its buffer can be reused
+	c.buf = c.buf[:0]
+
+	return nil
+}
+
+// eraSyncWithPeer syncs the complete eras of the peer following the local head, downloading each one
+// as a single archive instead of block by block. The blocks of an era stored already are skipped.
+// It returns once the era of the next block isn't complete on the peer, or the peer doesn't serve it,
+// the following blocks being synced block by block
+func (s *syncer) eraSyncWithPeer(syncPeer *NoForkPeer, newBlockCallback func(*types.Block) bool) (uint64, bool, error) {
+	peerID := syncPeer.ID
+
+	// the context is canceled when bulk sync ends, the syncer is stopped or closed,
+	// or the peer is found dead, which closes the gRPC stream of the archive
+	ctx, cancel := s.peerContext(peerID)
+	defer cancel()
+
+	var (
+		lastReceivedNumber uint64
+		shouldTerminate    bool
+	)
+
+	for {
+		localHeader := s.blockchain.Header()
+
+		era := (localHeader.Number + 1) / EraSize
+		if era >= completeEras(syncPeer.Number) {
+			return lastReceivedNumber, shouldTerminate, nil
+		}
+
+		blocks, err := s.syncPeerClient.GetEra(ctx, peerID, era, s.timeouts.ceiling)
+		if err != nil {
+			switch {
+			case ctx.Err() != nil:
+				return lastReceivedNumber, shouldTerminate, ctx.Err()
+			case status.Code(err) == codes.Unimplemented, status.Code(err) == codes.NotFound:
+				s.logger.Debug("peer doesn't serve era archive, fall back to blocks", "peer ID", peerID, "era", era)
+
+				return lastReceivedNumber, shouldTerminate, nil
+			case errors.Is(err, errInvalidEraArchive):
+				return lastReceivedNumber, shouldTerminate, s.failPeer(peerID, FailureHashMismatch, err)
+			default:
+				return lastReceivedNumber, shouldTerminate, s.requestFailed(peerID, err)
+			}
+		}
+
+		first, _ := eraRange(era)
+		blocks = blocks[localHeader.Number+1-first:]
+
+		// the senders are recovered concurrently, the blocks are verified and written in order
+		for _, preverified := range s.preverifyBlocks(ctx, blocks) {
+			block := preverified.block
+
+			err = preverified.wait()
+			if ctx.Err() != nil {
+				return lastReceivedNumber, shouldTerminate, ctx.Err()
+			}
+
+			if trustedErr := s.trustedCheckpoints.verifyHeader(block.Header); trustedErr != nil {
+				// the peer is on another chain than the trusted one
+				s.quarantinePeer(peerID, block.Number())
+
+				return lastReceivedNumber, false, s.peerError(ErrHashMismatch, peerID, trustedErr)
+			}
+
+			if err == nil {
+				err = s.blockchain.VerifyFinalizedBlock(block)
+			}
+
+			if err != nil {
+				if lastReceivedNumber == 0 && isForkError(err) {
+					// the first block doesn't follow the local head, the peer is on another fork
+					return lastReceivedNumber, false,
+						s.peerError(ErrHashMismatch, peerID, fmt.Errorf("%w, unable to verify block: %v", errDivergentFork, err))
+				}
+
+				return lastReceivedNumber, false,
+					s.failPeer(peerID, verificationFailure(err), fmt.Errorf("unable to verify block, %w", err))
+			}
+
+			if err := s.blockchain.WriteFinalizedBlock(ctx, block, syncerName); err != nil {
+				return lastReceivedNumber, false, fmt.Errorf("failed to write block while bulk syncing: %w", err)
+			}
+
+			s.recordWrittenBlock()
+			s.timeouts.observe(block.Size())
+			s.peerMap.RecordSuccess(peerID)
+
+			shouldTerminate = newBlockCallback(block)
+
+			lastReceivedNumber = block.Number()
+		}
+
+		s.metrics.SyncedEras.Add(1)
+		s.syncProgression.CompleteBatchProgression(lastReceivedNumber)
+
+		if s.sessionLimitReached() {
+			// the session yields once the era is written, the next one resumes from the next era
+			return lastReceivedNumber, shouldTerminate, nil
+		}
+	}
+}
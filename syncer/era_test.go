@@ -0,0 +1,212 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// createEraChain returns the blocks of the first eras, from the genesis block
+func createEraChain(eras int) []*types.Block {
+	genesis := &types.Block{
+		Header: (&types.Header{Number: 0}).ComputeHash(),
+	}
+
+	return append([]*types.Block{genesis}, createMockChain(genesis.Header, eras*int(EraSize)-1)...)
+}
+
+// encodeEra returns the archive of the era and the chunks it is streamed in
+func encodeEra(t *testing.T, blocks []*types.Block, era uint64) ([]byte, []*proto.EraChunk) {
+	t.Helper()
+
+	var (
+		buf    bytes.Buffer
+		chunks []*proto.EraChunk
+	)
+
+	chunkWriter := newEraChunkWriter(func(chunk *proto.EraChunk) error {
+		chunks = append(chunks, &proto.EraChunk{Data: append([]byte{}, chunk.Data...)})
+		buf.Write(chunk.Data)
+
+		return nil
+	})
+
+	archive := newEraWriter(chunkWriter, era)
+	first, last := eraRange(era)
+
+	for _, block := range blocks[first : last+1] {
+		assert.NoError(t, archive.add(block))
+	}
+
+	assert.NoError(t, archive.finish())
+	assert.NoError(t, chunkWriter.flush())
+
+	return buf.Bytes(), chunks
+}
+
+func TestEraArchive(t *testing.T) {
+	t.Parallel()
+
+	blocks := createEraChain(2)
+	data, chunks := encodeEra(t, blocks, 1)
+
+	// the chunks are full, but the last one
+	assert.Greater(t, len(chunks), 1)
+
+	for _, chunk := range chunks[:len(chunks)-1] {
+		assert.Len(t, chunk.Data, eraChunkSize)
+	}
+
+	decoded, err := decodeEra(data, 1)
+	assert.NoError(t, err)
+	assert.Len(t, decoded, int(EraSize))
+	assert.Equal(t, blocks[EraSize].Hash(), decoded[0].Hash())
+	assert.Equal(t, blocks[2*EraSize-1].Hash(), decoded[EraSize-1].Hash())
+
+	// the archive is of another era
+	_, err = decodeEra(data, 0)
+	assert.ErrorIs(t, err, errInvalidEraArchive)
+
+	// a corrupted byte fails the checksum
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)/2] ^= 0xff
+
+	_, err = decodeEra(corrupted, 1)
+	assert.ErrorIs(t, err, errInvalidEraArchive)
+
+	_, err = decodeEra(data[:types.HashLength], 1)
+	assert.ErrorIs(t, err, errInvalidEraArchive)
+}
+
+func TestEraArchive_BrokenChain(t *testing.T) {
+	t.Parallel()
+
+	blocks := createEraChain(1)
+
+	// the archive is consistent, but a block doesn't follow its parent
+	broken := append([]*types.Block{}, blocks...)
+	broken[10] = &types.Block{
+		Header: (&types.Header{Number: 10}).ComputeHash(),
+	}
+
+	data, _ := encodeEra(t, broken, 0)
+
+	_, err := decodeEra(data, 0)
+	assert.ErrorIs(t, err, errInvalidEraArchive)
+}
+
+func TestCompleteEras(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint64(0), completeEras(0))
+	assert.Equal(t, uint64(0), completeEras(EraSize-2))
+	assert.Equal(t, uint64(1), completeEras(EraSize-1))
+	assert.Equal(t, uint64(2), completeEras(3*EraSize-2))
+}
+
+func Test_eraSyncWithPeer(t *testing.T) {
+	t.Parallel()
+
+	blocks := createEraChain(2)
+
+	var (
+		written     []*types.Block
+		latestHead  = blocks[100].Header
+		requestEras []uint64
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return latestHead
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				if b.ParentHash() != latestHead.Hash {
+					return errors.New("unknown parent")
+				}
+
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				written = append(written, b)
+				latestHead = b.Header
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getEraHandler: func(_ context.Context, _ peer.ID, era uint64) ([]*types.Block, error) {
+				requestEras = append(requestEras, era)
+				data, _ := encodeEra(t, blocks, era)
+
+				return decodeEra(data, era)
+			},
+		},
+		&mockProgression{},
+	)
+
+	// the second era isn't complete on the peer
+	syncPeer := &NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   2*EraSize - 2,
+		Distance: big.NewInt(0),
+	}
+	syncer.peerMap.Put(syncPeer)
+
+	lastNumber, shouldTerminate, err := syncer.eraSyncWithPeer(syncPeer, func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, shouldTerminate)
+	assert.Equal(t, EraSize-1, lastNumber)
+	assert.Equal(t, []uint64{0}, requestEras)
+
+	// the stored blocks are skipped
+	assert.Len(t, written, int(EraSize)-101)
+	assert.Equal(t, uint64(101), written[0].Number())
+}
+
+func Test_eraSyncWithPeer_Unimplemented(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+
+	syncPeer := &NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   3 * EraSize,
+		Distance: big.NewInt(0),
+	}
+	syncer.peerMap.Put(syncPeer)
+
+	// the peer not serving the archives is synced block by block, without failure
+	lastNumber, _, err := syncer.eraSyncWithPeer(syncPeer, func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), lastNumber)
+	assert.Empty(t, syncer.PeerScores())
+}
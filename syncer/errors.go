@@ -0,0 +1,89 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The kinds of the sync errors, matched with errors.Is. The timeouts and the gone peers are transient
+// network issues, while the hash mismatches and the verification failures are problems of the chain served
+var (
+	// ErrTimeout is a peer not serving the requested blocks, headers or bodies in time
+	ErrTimeout = errors.New("timeout awaiting block from peer")
+	// ErrPeerGone is a peer disconnecting, or the connection to the peer failing
+	ErrPeerGone = errors.New("peer is gone")
+	// ErrHashMismatch is a peer serving blocks whose hashes don't match each other, the local chain
+	// or the trusted checkpoints
+	ErrHashMismatch = errors.New("hash mismatch")
+	// ErrVerification is a peer serving blocks failing the verification of the consensus
+	ErrVerification = errors.New("block verification failed")
+)
+
+// errorKindNames are the names of the kinds of sync errors in the operator API
+var errorKindNames = map[error]string{
+	ErrTimeout:      "timeout",
+	ErrPeerGone:     "peer_gone",
+	ErrHashMismatch: "hash_mismatch",
+	ErrVerification: "verification",
+}
+
+// SyncError is an error of the sync with a peer, of one of the kinds of sync errors
+type SyncError struct {
+	// Kind is ErrTimeout, ErrPeerGone, ErrHashMismatch or ErrVerification
+	Kind   error
+	PeerID peer.ID
+	Err    error
+}
+
+func (e *SyncError) Error() string {
+	if errors.Is(e.Err, e.Kind) {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *SyncError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the kind of the error, the wrapped error being matched through Unwrap
+func (e *SyncError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// KindName returns the name of the kind of the error
+func (e *SyncError) KindName() string {
+	return errorKindNames[e.Kind]
+}
+
+// kind returns the kind of sync error of the failure
+func (f PeerFailure) kind() error {
+	switch f {
+	case FailureTimeout:
+		return ErrTimeout
+	case FailureHashMismatch:
+		return ErrHashMismatch
+	default:
+		return ErrVerification
+	}
+}
+
+// requestErrorKind returns the kind of sync error of a failed request to a peer,
+// a timeout unless the connection to the peer failed
+func requestErrorKind(err error) error {
+	if errors.Is(err, ErrPeerGone) {
+		return ErrPeerGone
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Canceled:
+		return ErrPeerGone
+	default:
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,153 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncError(t *testing.T) {
+	t.Parallel()
+
+	err := &SyncError{
+		Kind:   ErrHashMismatch,
+		PeerID: peer.ID("A"),
+		Err:    fmt.Errorf("%w at block 2", ErrMismatchedParent),
+	}
+
+	assert.ErrorIs(t, err, ErrHashMismatch)
+	assert.ErrorIs(t, err, ErrMismatchedParent)
+	assert.NotErrorIs(t, err, ErrVerification)
+	assert.Equal(t, "hash_mismatch", err.KindName())
+	assert.Equal(t, "hash mismatch: "+err.Err.Error(), err.Error())
+
+	// the kind isn't repeated
+	err = &SyncError{Kind: ErrTimeout, Err: ErrTimeout}
+	assert.Equal(t, ErrTimeout.Error(), err.Error())
+	assert.Equal(t, "timeout", err.KindName())
+
+	var syncErr *SyncError
+
+	assert.True(t, errors.As(fmt.Errorf("bulk sync failed, %w", err), &syncErr))
+	assert.Equal(t, ErrTimeout, syncErr.Kind)
+}
+
+func TestRequestErrorKind(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ErrTimeout, requestErrorKind(context.DeadlineExceeded))
+	assert.Equal(t, ErrTimeout, requestErrorKind(status.Error(codes.DeadlineExceeded, "deadline")))
+	assert.Equal(t, ErrPeerGone, requestErrorKind(status.Error(codes.Unavailable, "connection closed")))
+	assert.Equal(t, ErrPeerGone, requestErrorKind(fmt.Errorf("%w, failed to create sync peer client: %v", ErrPeerGone, "no stream")))
+}
+
+func TestPeerMap_RecordError(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	peerMap := NewPeerMap(nil)
+	peerMap.reputation.now = func() time.Time {
+		return now
+	}
+
+	peerMap.RecordError(&SyncError{Kind: ErrPeerGone, PeerID: peer.ID("A"), Err: ErrPeerGone})
+
+	scores := peerMap.Scores()
+	assert.Len(t, scores, 1)
+	assert.ErrorIs(t, scores[0].LastError, ErrPeerGone)
+	assert.Equal(t, now, scores[0].LastErrorTime)
+
+	// the last error doesn't penalize the peer
+	assert.Zero(t, scores[0].Score)
+	assert.False(t, peerMap.IsBanned(peer.ID("A")))
+}
+
+func Test_streamSyncWithPeer_ErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 2)
+
+	tests := []struct {
+		name     string
+		client   *mockSyncPeerClient
+		verifyFn func(*types.Block) error
+		kind     error
+	}{
+		{
+			name: "peer gone",
+			client: &mockSyncPeerClient{
+				getBlocksHandler: func(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error) {
+					return nil, status.Error(codes.Unavailable, "connection closed")
+				},
+			},
+			kind: ErrPeerGone,
+		},
+		{
+			name: "timeout",
+			client: &mockSyncPeerClient{
+				getBlocksHandler: func(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error) {
+					return make(chan *types.Block), nil
+				},
+			},
+			kind: ErrTimeout,
+		},
+		{
+			name: "verification",
+			client: &mockSyncPeerClient{
+				getBlocksHandler: func(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error) {
+					return blocksToCh(blocks, 0), nil
+				},
+			},
+			verifyFn: func(*types.Block) error {
+				return errors.New("invalid signature")
+			},
+			kind: ErrVerification,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return head
+					},
+					verifyFinalizedBlockHandler: test.verifyFn,
+				},
+				100*time.Millisecond,
+				test.client,
+				&mockProgression{},
+			)
+			syncer.peerMap.Put(&NoForkPeer{ID: peer.ID("A"), Number: 2, Distance: big.NewInt(0)})
+
+			_, _, err := syncer.streamSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			var syncErr *SyncError
+
+			assert.True(t, errors.As(err, &syncErr))
+			assert.Equal(t, test.kind, syncErr.Kind)
+			assert.Equal(t, peer.ID("A"), syncErr.PeerID)
+
+			scores := syncer.PeerScores()
+			assert.Len(t, scores, 1)
+			assert.ErrorIs(t, scores[0].LastError, test.kind)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package syncer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// forkQuarantineDuration is how long a peer on a divergent fork is excluded from
+// the sync peer selection, unless its status shows it is back on the local chain
+const forkQuarantineDuration = 10 * time.Minute
+
+// peerQuarantine tracks the peers detected on a divergent fork. Unlike a ban,
+// the quarantine doesn't lower the score of the peer, as it doesn't misbehave
+type peerQuarantine struct {
+	sync.Mutex
+
+	until map[peer.ID]time.Time
+	// now returns the current time, overridden in tests
+	now func() time.Time
+}
+
+func (q *peerQuarantine) currentTime() time.Time {
+	if q.now != nil {
+		return q.now()
+	}
+
+	return time.Now()
+}
+
+// add quarantines the peer, it returns true if the peer wasn't quarantined yet
+func (q *peerQuarantine) add(peerID peer.ID) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.until == nil {
+		q.until = make(map[peer.ID]time.Time)
+	}
+
+	until, ok := q.until[peerID]
+	q.until[peerID] = q.currentTime().Add(forkQuarantineDuration)
+
+	return !ok || !q.currentTime().Before(until)
+}
+
+// remove lifts the quarantine of the peer
+func (q *peerQuarantine) remove(peerID peer.ID) {
+	q.Lock()
+	defer q.Unlock()
+
+	delete(q.until, peerID)
+}
+
+// contains returns whether the peer is quarantined, the expired quarantines are lifted
+func (q *peerQuarantine) contains(peerID peer.ID) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	until, ok := q.until[peerID]
+	if !ok {
+		return false
+	}
+
+	if !q.currentTime().Before(until) {
+		delete(q.until, peerID)
+
+		return false
+	}
+
+	return true
+}
+
+// forkStatus is the relation between the chain of a peer and the local chain
+type forkStatus int
+
+const (
+	// forkUnknown is returned if the status of the peer is not enough to tell,
+	// as the peer doesn't report its head hash or is ahead of the local chain
+	forkUnknown forkStatus = iota
+	// forkNone is returned if the head of the peer is on the local chain
+	forkNone
+	// forkDivergent is returned if the head of the peer is not on the local chain
+	forkDivergent
+)
+
+// isForkError returns whether the verification error of the block
+// following the local head means it is built on another parent
+func isForkError(err error) bool {
+	return errors.Is(err, blockchain.ErrParentNotFound) ||
+		errors.Is(err, blockchain.ErrParentHashMismatch)
+}
+
+// detectFork compares the head of the peer with the local block of the same number
+func (s *syncer) detectFork(status *NoForkPeer) forkStatus {
+	if status.Hash == types.ZeroHash {
+		return forkUnknown
+	}
+
+	header, ok := s.blockchain.GetHeaderByNumber(status.Number)
+	if !ok {
+		return forkUnknown
+	}
+
+	if header.Hash != status.Hash {
+		return forkDivergent
+	}
+
+	return forkNone
+}
+
+// checkPeerFork quarantines the peer if its head is on a divergent fork,
+// and lifts the quarantine once its head is on the local chain again
+func (s *syncer) checkPeerFork(status *NoForkPeer) {
+	switch s.detectFork(status) {
+	case forkDivergent:
+		s.quarantinePeer(status.ID, status.Number)
+	case forkNone:
+		s.peerMap.Release(status.ID)
+	}
+}
+
+// quarantinePeer excludes the peer on a divergent fork from the sync peer selection
+func (s *syncer) quarantinePeer(peerID peer.ID, number uint64) {
+	if s.peerMap.Quarantine(peerID) {
+		s.logger.Warn(
+			"quarantined sync peer on a divergent fork",
+			"peer ID", peerID,
+			"number", number,
+			"duration", forkQuarantineDuration,
+		)
+	}
+}
@@ -0,0 +1,229 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerMap_Quarantine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	peerMap := NewPeerMap(cloneNoForkPeers(peers))
+	peerMap.quarantine.now = func() time.Time {
+		return now
+	}
+
+	assert.Equal(t, peer.ID("C"), peerMap.BestPeer(nil).ID)
+
+	assert.True(t, peerMap.Quarantine(peer.ID("C")))
+	assert.False(t, peerMap.Quarantine(peer.ID("C")))
+	assert.True(t, peerMap.IsQuarantined(peer.ID("C")))
+
+	// the quarantined peer is skipped, without lowering its score
+	assert.Equal(t, peer.ID("B"), peerMap.BestPeer(nil).ID)
+	assert.Empty(t, peerMap.PeersWithBlock(20, 2, map[peer.ID]bool{peer.ID("B"): true}))
+	assert.Empty(t, peerMap.Scores())
+
+	peerMap.Release(peer.ID("C"))
+	assert.False(t, peerMap.IsQuarantined(peer.ID("C")))
+
+	assert.True(t, peerMap.Quarantine(peer.ID("C")))
+
+	now = now.Add(forkQuarantineDuration)
+
+	assert.False(t, peerMap.IsQuarantined(peer.ID("C")))
+	assert.Equal(t, peer.ID("C"), peerMap.BestPeer(nil).ID)
+}
+
+func TestNoForkPeer_IsBetter_Difficulty(t *testing.T) {
+	t.Parallel()
+
+	low := &NoForkPeer{ID: peer.ID("A"), Number: 20, Difficulty: big.NewInt(20), Distance: big.NewInt(1)}
+	high := &NoForkPeer{ID: peer.ID("B"), Number: 10, Difficulty: big.NewInt(30), Distance: big.NewInt(2)}
+	unknown := &NoForkPeer{ID: peer.ID("C"), Number: 15, Distance: big.NewInt(1)}
+
+	// the total difficulty wins over the block number
+	assert.True(t, high.IsBetter(low))
+	assert.False(t, low.IsBetter(high))
+
+	// the block number is compared if a peer doesn't report its difficulty
+	assert.True(t, low.IsBetter(unknown))
+	assert.True(t, unknown.IsBetter(high))
+}
+
+func Test_statusToPeer(t *testing.T) {
+	t.Parallel()
+
+	hash := types.StringToHash("1")
+	distance := big.NewInt(5)
+
+	assert.Equal(
+		t,
+		&NoForkPeer{
+			ID:         peer.ID("A"),
+			Number:     10,
+			Hash:       hash,
+			Difficulty: big.NewInt(11),
+			Distance:   distance,
+			Version:    ProtocolVersion1,
+		},
+		statusToPeer(peer.ID("A"), &proto.SyncPeerStatus{
+			Number:     10,
+			Hash:       hash.Bytes(),
+			Difficulty: big.NewInt(11).Bytes(),
+		}, distance),
+	)
+
+	// the status of an older node has no hash and difficulty
+	assert.Equal(
+		t,
+		&NoForkPeer{
+			ID:       peer.ID("A"),
+			Number:   10,
+			Distance: distance,
+			Version:  ProtocolVersion1,
+		},
+		statusToPeer(peer.ID("A"), &proto.SyncPeerStatus{Number: 10}, distance),
+	)
+}
+
+func Test_checkPeerFork(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 10)
+
+	otherHead := (&types.Header{Number: 0, ExtraData: []byte("other")}).ComputeHash()
+	otherBlocks := createMockChain(otherHead, 20)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			getHeaderByNumberHandler: func(number uint64) (*types.Header, bool) {
+				if number == 0 || number > uint64(len(blocks)) {
+					return nil, false
+				}
+
+				return blocks[number-1].Header, true
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+
+	statusAt := func(block *types.Block) *NoForkPeer {
+		return &NoForkPeer{
+			ID:       peer.ID("A"),
+			Number:   block.Number(),
+			Hash:     block.Hash(),
+			Distance: big.NewInt(1),
+		}
+	}
+
+	// the peer is ahead, its fork can't be told from the status
+	syncer.putToPeerMap(statusAt(otherBlocks[15]))
+	assert.False(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+
+	syncer.putToPeerMap(statusAt(otherBlocks[5]))
+	assert.True(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+	assert.Nil(t, syncer.peerMap.BestPeer(nil))
+
+	// the peer is back on the local chain
+	syncer.putToPeerMap(statusAt(blocks[8]))
+	assert.False(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+	assert.Equal(t, peer.ID("A"), syncer.peerMap.BestPeer(nil).ID)
+}
+
+func Test_bulkSyncWithPeer_DivergentFork(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+
+	otherHead := (&types.Header{Number: 0, ExtraData: []byte("other")}).ComputeHash()
+	otherBlocks := createMockChain(otherHead, 10)
+
+	tests := []struct {
+		name   string
+		client func() *mockSyncPeerClient
+	}{
+		{
+			name: "header-first",
+			client: func() *mockSyncPeerClient {
+				return newHeaderFirstSyncPeerClient(otherBlocks)
+			},
+		},
+		{
+			name: "block stream",
+			client: func() *mockSyncPeerClient {
+				return &mockSyncPeerClient{
+					getBlocksHandler: func(
+						_ context.Context,
+						_ peer.ID,
+						_ uint64,
+						_ time.Duration,
+					) (<-chan *types.Block, error) {
+						return blocksToCh(otherBlocks, 0), nil
+					},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return head
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						if b.ParentHash() != head.Hash {
+							return blockchain.ErrParentNotFound
+						}
+
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+				},
+				time.Second,
+				test.client(),
+				&mockProgression{},
+			)
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			assert.Error(t, err)
+			assert.Zero(t, lastNumber)
+
+			// the peer is quarantined instead of penalized
+			assert.True(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+
+			scores := syncer.PeerScores()
+			assert.Len(t, scores, 1)
+			assert.Zero(t, scores[0].Score)
+			assert.Empty(t, scores[0].Failures)
+			assert.ErrorIs(t, scores[0].LastError, ErrHashMismatch)
+			assert.ErrorIs(t, err, ErrHashMismatch)
+		})
+	}
+}
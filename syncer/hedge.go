@@ -0,0 +1,124 @@
+package syncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultHedgeDelay is the default time the sync peer has to serve the blocks near the chain tip
+	// before they are also requested from a second peer
+	DefaultHedgeDelay = 500 * time.Millisecond
+	// hedgeTipDistance is the maximum number of blocks between the requested block and the latest
+	// block of the sync peer for the request to be hedged
+	hedgeTipDistance = 2
+)
+
+// hedgeResult is the response of one of the peers a hedged request is sent to
+type hedgeResult struct {
+	peerID peer.ID
+	value  interface{}
+	err    error
+}
+
+// hedgePeer returns the peer a request of the given block to the sync peer is hedged with. It is nil
+// if the hedging is disabled, if the block isn't near the tip of the sync peer or if no other peer has it
+func (s *syncer) hedgePeer(peerID peer.ID, number uint64, skipMap map[peer.ID]bool) *NoForkPeer {
+	if s.hedgeDelay == 0 {
+		return nil
+	}
+
+	syncPeer := s.peerMap.Get(peerID)
+	if syncPeer == nil || syncPeer.Number > number+hedgeTipDistance {
+		return nil
+	}
+
+	skip := map[peer.ID]bool{peerID: true}
+	for id := range skipMap {
+		skip[id] = true
+	}
+
+	peers := s.peerMap.PeersWithBlock(number, 1, skip)
+	if len(peers) == 0 {
+		return nil
+	}
+
+	return peers[0]
+}
+
+// hedgedRequest sends the request to the sync peer, and to the hedge peer as well if the sync peer
+// hasn't responded after the hedge delay. The first successful response is returned along with the peer
+// that sent it, the other request being canceled and its response discarded.
+// The request isn't hedged if the sync peer fails first, and its error is returned if both peers fail
+func (s *syncer) hedgedRequest(
+	ctx context.Context,
+	peerID peer.ID,
+	hedge *NoForkPeer,
+	request func(ctx context.Context, peerID peer.ID) (interface{}, error),
+) (peer.ID, interface{}, error) {
+	if hedge == nil {
+		value, err := request(ctx, peerID)
+
+		return peerID, value, err
+	}
+
+	// the request losing the race is canceled on return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resultCh := make(chan hedgeResult, 2)
+	send := func(id peer.ID) {
+		go func() {
+			value, err := request(ctx, id)
+			resultCh <- hedgeResult{peerID: id, value: value, err: err}
+		}()
+	}
+
+	send(peerID)
+
+	timer := time.NewTimer(s.hedgeDelay)
+	defer timer.Stop()
+
+	var (
+		timerCh     = timer.C
+		pending     = 1
+		syncPeerErr error
+	)
+
+	for pending > 0 {
+		select {
+		case <-timerCh:
+			timerCh = nil
+
+			s.logger.Debug("sync peer is late, hedging the request", "peer ID", peerID, "hedge peer ID", hedge.ID)
+			s.metrics.HedgedRequests.Add(1)
+
+			send(hedge.ID)
+
+			pending++
+		case res := <-resultCh:
+			pending--
+
+			if res.err == nil {
+				if res.peerID != peerID {
+					s.metrics.HedgeWins.Add(1)
+				}
+
+				return res.peerID, res.value, nil
+			}
+
+			if res.peerID != peerID {
+				s.logger.Debug("hedge peer failed to respond", "peer ID", res.peerID, "error", res.err)
+
+				continue
+			}
+
+			syncPeerErr = res.err
+			timerCh = nil
+		}
+	}
+
+	return peerID, nil, syncPeerErr
+}
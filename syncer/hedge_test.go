@@ -0,0 +1,154 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHedgeTestSyncer(hedgeDelay time.Duration) *syncer {
+	s := NewTestSyncer(nil, nil, time.Second, nil, nil)
+	s.hedgeDelay = hedgeDelay
+
+	s.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(10)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 10, Distance: big.NewInt(10)},
+		&NoForkPeer{ID: peer.ID("C"), Number: 5, Distance: big.NewInt(5)},
+	)
+
+	return s
+}
+
+func TestSyncer_hedgePeer(t *testing.T) {
+	t.Parallel()
+
+	s := newHedgeTestSyncer(time.Second)
+
+	// the block is near the tip of the sync peer
+	hedge := s.hedgePeer(peer.ID("A"), 9, nil)
+	if assert.NotNil(t, hedge) {
+		assert.Equal(t, peer.ID("B"), hedge.ID)
+	}
+
+	// the block is far from the tip of the sync peer
+	assert.Nil(t, s.hedgePeer(peer.ID("A"), 5, nil))
+
+	// the other peer having the block is skipped
+	assert.Nil(t, s.hedgePeer(peer.ID("A"), 9, map[peer.ID]bool{peer.ID("B"): true}))
+
+	// the hedging is disabled
+	s.hedgeDelay = 0
+	assert.Nil(t, s.hedgePeer(peer.ID("A"), 9, nil))
+}
+
+func TestSyncer_hedgedRequest(t *testing.T) {
+	t.Parallel()
+
+	errRequest := errors.New("request failed")
+
+	tests := []struct {
+		name     string
+		delays   map[peer.ID]time.Duration
+		errs     map[peer.ID]error
+		hedged   bool
+		expected peer.ID
+		err      error
+	}{
+		{
+			name:     "sync peer responds in time",
+			delays:   map[peer.ID]time.Duration{"A": 0, "B": 0},
+			hedged:   false,
+			expected: peer.ID("A"),
+		},
+		{
+			name:     "hedge peer responds first",
+			delays:   map[peer.ID]time.Duration{"A": time.Minute, "B": 0},
+			hedged:   true,
+			expected: peer.ID("B"),
+		},
+		{
+			name:     "sync peer responds first after the hedge delay",
+			delays:   map[peer.ID]time.Duration{"A": 200 * time.Millisecond, "B": time.Minute},
+			hedged:   true,
+			expected: peer.ID("A"),
+		},
+		{
+			name:     "sync peer fails before the hedge delay",
+			delays:   map[peer.ID]time.Duration{"A": 0, "B": 0},
+			errs:     map[peer.ID]error{"A": errRequest},
+			hedged:   false,
+			expected: peer.ID("A"),
+			err:      errRequest,
+		},
+		{
+			name:     "hedge peer fails",
+			delays:   map[peer.ID]time.Duration{"A": 200 * time.Millisecond, "B": 0},
+			errs:     map[peer.ID]error{"B": errRequest},
+			hedged:   true,
+			expected: peer.ID("A"),
+		},
+		{
+			name:     "both peers fail",
+			delays:   map[peer.ID]time.Duration{"A": 200 * time.Millisecond, "B": 0},
+			errs:     map[peer.ID]error{"A": errRequest, "B": errors.New("hedge failed")},
+			hedged:   true,
+			expected: peer.ID("A"),
+			err:      errRequest,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newHedgeTestSyncer(50 * time.Millisecond)
+
+			requested := make(chan peer.ID, 2)
+
+			peerID, value, err := s.hedgedRequest(
+				context.Background(),
+				peer.ID("A"),
+				s.peerMap.Get(peer.ID("B")),
+				func(ctx context.Context, id peer.ID) (interface{}, error) {
+					requested <- id
+
+					select {
+					case <-time.After(test.delays[id]):
+					case <-ctx.Done():
+						// the request losing the race is canceled
+						return nil, ctx.Err()
+					}
+
+					if err := test.errs[id]; err != nil {
+						return nil, err
+					}
+
+					return id, nil
+				},
+			)
+
+			assert.Equal(t, test.expected, peerID)
+			assert.ErrorIs(t, err, test.err)
+
+			if test.err == nil {
+				assert.Equal(t, test.expected, value)
+			}
+
+			// the loser may still be starting
+			if test.hedged {
+				assert.Eventually(t, func() bool {
+					return len(requested) == 2
+				}, time.Second, 10*time.Millisecond)
+			} else {
+				assert.Len(t, requested, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// keepaliveMaxMisses is the number of consecutive keepalive pings a peer may miss before being removed
+	keepaliveMaxMisses = 2
+)
+
+var (
+	errPongNonceMismatch = errors.New("keepalive pong doesn't echo the ping nonce")
+)
+
+// startKeepaliveProcess periodically pings the sync peers. A half-open connection isn't noticed
+// by the OS for hours, the peers not answering are removed instead within a few intervals
+func (s *syncer) startKeepaliveProcess() {
+	ticker := time.NewTicker(s.keepaliveInterval)
+	defer ticker.Stop()
+
+	misses := make(map[peer.ID]int)
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.pingPeers(misses)
+	}
+}
+
+// pingPeers pings the sync peers concurrently and counts the consecutive pings each one missed.
+// The peers reaching the maximum misses are removed, and the sync with them is aborted
+func (s *syncer) pingPeers(misses map[peer.ID]int) {
+	peerIDs := s.peerMap.IDs()
+
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		failed = make(map[peer.ID]error)
+	)
+
+	for _, peerID := range peerIDs {
+		wg.Add(1)
+
+		go func(peerID peer.ID) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(s.ctx, s.keepaliveTimeout)
+			defer cancel()
+
+			if err := s.syncPeerClient.Ping(ctx, peerID); err != nil {
+				lock.Lock()
+				failed[peerID] = err
+				lock.Unlock()
+			}
+		}(peerID)
+	}
+
+	wg.Wait()
+
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	current := make(map[peer.ID]int, len(peerIDs))
+
+	for _, peerID := range peerIDs {
+		err, ok := failed[peerID]
+		if !ok {
+			continue
+		}
+
+		current[peerID] = misses[peerID] + 1
+		if current[peerID] < keepaliveMaxMisses {
+			s.logger.Debug("sync peer missed a keepalive ping", "peer ID", peerID, "err", err)
+
+			continue
+		}
+
+		s.logger.Info("removing dead sync peer", "peer ID", peerID, "misses", current[peerID], "err", err)
+		s.metrics.DeadPeers.Add(1)
+		s.removeFromPeerMap(peerID)
+		s.abortPeerSync(peerID)
+
+		if err := s.syncPeerClient.CloseStream(peerID); err != nil {
+			s.logger.Debug("failed to close the stream of dead sync peer", "peer ID", peerID, "err", err)
+		}
+
+		delete(current, peerID)
+	}
+
+	// the peers answering or gone from the map start over
+	for peerID := range misses {
+		delete(misses, peerID)
+	}
+
+	for peerID, n := range current {
+		misses[peerID] = n
+	}
+}
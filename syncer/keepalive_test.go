@@ -0,0 +1,149 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncer_pingPeers(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{},
+		time.Second,
+		&mockSyncPeerClient{
+			pingHandler: func(ctx context.Context, id peer.ID) error {
+				if id == peer.ID("A") {
+					return nil
+				}
+
+				// the half-open connection of B never answers
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+		},
+		&mockProgression{},
+	)
+	syncer.keepaliveTimeout = 10 * time.Millisecond
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(1)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 20, Distance: big.NewInt(1)},
+	)
+
+	misses := make(map[peer.ID]int)
+
+	// a single missed ping is tolerated
+	syncer.pingPeers(misses)
+	assert.Equal(t, map[peer.ID]int{peer.ID("B"): 1}, misses)
+	assert.NotNil(t, syncer.peerMap.Get(peer.ID("B")))
+
+	syncer.pingPeers(misses)
+	assert.Empty(t, misses)
+	assert.Nil(t, syncer.peerMap.Get(peer.ID("B")))
+	assert.NotNil(t, syncer.peerMap.Get(peer.ID("A")))
+}
+
+func TestSyncer_pingPeers_MissesReset(t *testing.T) {
+	t.Parallel()
+
+	answer := false
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{},
+		time.Second,
+		&mockSyncPeerClient{
+			pingHandler: func(context.Context, peer.ID) error {
+				if answer {
+					return nil
+				}
+
+				return ErrPeerGone
+			},
+		},
+		&mockProgression{},
+	)
+	syncer.keepaliveTimeout = 10 * time.Millisecond
+	syncer.peerMap.Put(&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(1)})
+
+	misses := make(map[peer.ID]int)
+
+	syncer.pingPeers(misses)
+	assert.Equal(t, 1, misses[peer.ID("A")])
+
+	// the missed pings must be consecutive
+	answer = true
+	syncer.pingPeers(misses)
+	assert.Empty(t, misses)
+
+	answer = false
+	syncer.pingPeers(misses)
+	assert.NotNil(t, syncer.peerMap.Get(peer.ID("A")))
+}
+
+func TestSyncer_pingPeers_AbortSync(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Minute,
+		&mockSyncPeerClient{
+			getBlocksHandler: func(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error) {
+				// the stream hangs until the sync is aborted
+				return make(chan *types.Block), nil
+			},
+			pingHandler: func(context.Context, peer.ID) error {
+				return ErrPeerGone
+			},
+		},
+		&mockProgression{},
+	)
+	syncer.keepaliveTimeout = 10 * time.Millisecond
+	syncer.peerMap.Put(&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(1)})
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		_, _, err := syncer.streamSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+			return false
+		})
+
+		errCh <- err
+	}()
+
+	// the sync registers its context before opening the stream
+	assert.Eventually(t, func() bool {
+		syncer.syncPeerLock.Lock()
+		defer syncer.syncPeerLock.Unlock()
+
+		return syncer.syncPeerID == peer.ID("A")
+	}, 5*time.Second, time.Millisecond)
+
+	misses := make(map[peer.ID]int)
+
+	for i := 0; i < keepaliveMaxMisses; i++ {
+		syncer.pingPeers(misses)
+	}
+
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the sync with the dead peer wasn't aborted")
+	}
+}
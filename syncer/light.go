@@ -0,0 +1,276 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyncMode is the way the syncer follows the chain of its peers
+type SyncMode int
+
+const (
+	// ModeFull syncs the blocks, which are verified and executed
+	ModeFull SyncMode = iota
+	// ModeLight syncs the headers only, which are verified by the consensus.
+	// The blocks aren't executed, so the state isn't available, and neither are the bodies
+	// and the receipts unless they are synced as well
+	ModeLight
+	// ModeGateway syncs the blocks like the full mode, but only the bodies and the receipts
+	// of the recent blocks are kept. The older ones are fetched from the archive peers on demand
+	ModeGateway
+)
+
+var syncModeNames = map[SyncMode]string{
+	ModeFull:    "full",
+	ModeLight:   "light",
+	ModeGateway: "gateway",
+}
+
+var (
+	ErrUnknownSyncMode = errors.New("unknown sync mode")
+	// ErrBodyNotFound is returned when no sync peer serves a valid body of the requested block
+	ErrBodyNotFound = errors.New("body not found on the sync peers")
+)
+
+// maxBodyFetchPeers is the maximum number of peers the body of a block is requested from by FetchBody
+const maxBodyFetchPeers = 3
+
+func (m SyncMode) String() string {
+	return syncModeNames[m]
+}
+
+// ParseSyncMode returns the sync mode of the given name, full, light or gateway
+func ParseSyncMode(name string) (SyncMode, error) {
+	for mode, modeName := range syncModeNames {
+		if strings.EqualFold(name, modeName) {
+			return mode, nil
+		}
+	}
+
+	return ModeFull, fmt.Errorf("%w: %s", ErrUnknownSyncMode, name)
+}
+
+// lightSyncWithPeer syncs the headers of a given peer, the bodies aren't fetched unless the receipts are synced.
+// Each header is verified by the consensus and written on its own, the new block callback
+// isn't called as no block is executed
+func (s *syncer) lightSyncWithPeer(peerID peer.ID) (uint64, error) {
+	// the context is canceled when bulk sync ends, the syncer is stopped or closed,
+	// or the peer is found dead, which stops the header fetching
+	ctx, cancel := s.peerContext(peerID)
+	defer cancel()
+
+	localHeader := s.blockchain.Header()
+	queue := newSyncQueue(localHeader)
+	queue.withReceipts = s.syncReceipts
+	headerCh, headerErrCh := s.fetchHeaders(ctx, peerID, localHeader.Number+1)
+
+	var (
+		lastReceivedNumber uint64
+
+		// peers that failed to serve bodies in this session
+		helperSkipList = map[peer.ID]bool{peerID: true}
+	)
+
+	for {
+		var (
+			batch *headerBatch
+			ok    bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return lastReceivedNumber, ctx.Err()
+		case batch, ok = <-headerCh:
+		}
+
+		if !ok {
+			// the error is sent before the channel is closed
+			select {
+			case err := <-headerErrCh:
+				if status.Code(err) != codes.Unimplemented {
+					err = s.requestFailed(peerID, err)
+				}
+
+				return lastReceivedNumber, err
+			default:
+				return lastReceivedNumber, nil
+			}
+		}
+
+		headers, sourceID := batch.headers, batch.peerID
+
+		if err := s.trustedCheckpoints.verifyHeaders(headers); err != nil {
+			// the peer is on another chain than the trusted one
+			s.quarantinePeer(sourceID, headers[len(headers)-1].Number)
+
+			return lastReceivedNumber, s.peerError(ErrHashMismatch, sourceID, err)
+		}
+
+		if err := queue.addHeaders(headers); err != nil {
+			if sourceID == peerID && queue.last.Hash == localHeader.Hash && headers[0].ParentHash != localHeader.Hash {
+				// the first header doesn't follow the local head, the peer is on another fork
+				return lastReceivedNumber, s.peerError(ErrHashMismatch, peerID, fmt.Errorf("%w, %v", errDivergentFork, err))
+			}
+
+			return lastReceivedNumber, s.failPeer(sourceID, FailureHashMismatch, err)
+		}
+
+		// the headers of a batch are verified right away, so the written headers come from its peer
+		var (
+			writtenNumber uint64
+			err           error
+		)
+
+		if s.syncReceipts {
+			writtenNumber, err = s.writeLightBlocks(ctx, peerID, sourceID, queue, helperSkipList)
+		} else {
+			writtenNumber, err = s.writeLightHeaders(peerID, sourceID, queue)
+		}
+
+		if writtenNumber > 0 {
+			lastReceivedNumber = writtenNumber
+		}
+
+		if err != nil {
+			return lastReceivedNumber, err
+		}
+
+		s.syncProgression.CompleteBatchProgression(lastReceivedNumber)
+
+		if s.sessionLimitReached() {
+			return lastReceivedNumber, nil
+		}
+	}
+}
+
+// writeLightHeaders verifies and writes the queued headers, which come from the source peer
+func (s *syncer) writeLightHeaders(peerID, sourceID peer.ID, queue *syncQueue) (uint64, error) {
+	var lastNumber uint64
+
+	for _, header := range queue.popHeaders() {
+		if err := s.blockchain.VerifyFinalizedHeader(header); err != nil {
+			return lastNumber,
+				s.failPeer(sourceID, verificationFailure(err), fmt.Errorf("unable to verify header, %w", err))
+		}
+
+		if err := s.blockchain.WriteFinalizedHeader(header, syncerName); err != nil {
+			return lastNumber, fmt.Errorf("failed to write header while light syncing: %w", err)
+		}
+
+		s.recordWrittenBlock()
+		s.peerMap.RecordSuccess(peerID)
+
+		lastNumber = header.Number
+	}
+
+	return lastNumber, nil
+}
+
+// writeLightBlocks fetches the bodies and the receipts of the queued headers, which come from the source peer,
+// and writes the blocks with their receipts without executing them
+func (s *syncer) writeLightBlocks(
+	ctx context.Context,
+	peerID, sourceID peer.ID,
+	queue *syncQueue,
+	helperSkipList map[peer.ID]bool,
+) (uint64, error) {
+	var lastNumber uint64
+
+	for queue.len() > 0 {
+		if err := s.fetchQueuedBodies(ctx, peerID, queue, helperSkipList); err != nil {
+			return lastNumber, err
+		}
+
+		if err := s.fetchQueuedReceipts(ctx, peerID, queue); err != nil {
+			return lastNumber, err
+		}
+
+		blocks, receipts := queue.popBlocksWithReceipts()
+
+		for i, block := range blocks {
+			if err := s.blockchain.VerifyFinalizedHeader(block.Header); err != nil {
+				return lastNumber,
+					s.failPeer(sourceID, verificationFailure(err), fmt.Errorf("unable to verify header, %w", err))
+			}
+
+			if err := s.blockchain.WriteFinalizedBlockWithReceipts(block, receipts[i], syncerName); err != nil {
+				return lastNumber, fmt.Errorf("failed to write block while light syncing: %w", err)
+			}
+
+			s.recordWrittenBlock()
+			s.peerMap.RecordSuccess(peerID)
+
+			lastNumber = block.Number()
+		}
+	}
+
+	return lastNumber, nil
+}
+
+// fetchQueuedReceipts fetches the pending receipts of the queue from the sync peer, verified against the headers
+func (s *syncer) fetchQueuedReceipts(ctx context.Context, peerID peer.ID, queue *syncQueue) error {
+	for {
+		hashes := queue.pendingReceipts(s.bodyBatchSize())
+		if len(hashes) == 0 {
+			return nil
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, s.adaptiveTimeout(peerID, len(hashes), nil))
+		receipts, err := s.syncPeerClient.GetReceipts(reqCtx, peerID, hashes)
+
+		cancel()
+
+		if err == nil && len(receipts) == 0 {
+			err = fmt.Errorf("%w: no receipts returned", ErrPeerNoResponse)
+		}
+
+		if err != nil {
+			return s.requestFailed(peerID, err)
+		}
+
+		if err := queue.deliverReceipts(hashes, receipts); err != nil {
+			return s.failPeer(peerID, FailureInvalidBlock, fmt.Errorf("invalid receipts, %w", err))
+		}
+	}
+}
+
+// FetchBody fetches the body of the block of the given header from the sync peers having the block,
+// the archive peers first, and verifies it against the header. It proves the inclusion of the block
+// transactions on demand in the light and the gateway sync modes, where the bodies aren't all stored
+func (s *syncer) FetchBody(ctx context.Context, header *types.Header) (*types.Body, error) {
+	for _, p := range s.peerMap.ArchivePeersWithBlock(header.Number, maxBodyFetchPeers, proto.Capability_BODIES) {
+		reqCtx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+		bodies, err := s.syncPeerClient.GetBodies(reqCtx, p.ID, []types.Hash{header.Hash})
+
+		cancel()
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err != nil || len(bodies) == 0 {
+			s.logger.Debug("peer didn't serve the requested body", "peer ID", p.ID, "number", header.Number, "error", err)
+
+			continue
+		}
+
+		if err := verifyBody(header, bodies[0]); err != nil {
+			err = s.failPeer(p.ID, FailureInvalidBlock, err)
+			s.logger.Warn("peer served an invalid body", "peer ID", p.ID, "error", err)
+
+			continue
+		}
+
+		return bodies[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: block %d", ErrBodyNotFound, header.Number)
+}
@@ -0,0 +1,262 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSyncMode(t *testing.T) {
+	t.Parallel()
+
+	mode, err := ParseSyncMode("full")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeFull, mode)
+
+	mode, err = ParseSyncMode("Light")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeLight, mode)
+	assert.Equal(t, "light", mode.String())
+
+	_, err = ParseSyncMode("fast")
+	assert.ErrorIs(t, err, ErrUnknownSyncMode)
+}
+
+func Test_bulkSyncWithPeer_Light(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, int(DefaultBatchSize)+10)
+
+	errInvalidSeal := errors.New("invalid seal")
+
+	tests := []struct {
+		name            string
+		invalidHeader   uint64
+		expectedWritten uint64
+		err             error
+	}{
+		{
+			name:            "should write all headers of the peer",
+			expectedWritten: uint64(len(blocks)),
+		},
+		{
+			name:            "should stop at the header failing verification",
+			invalidHeader:   5,
+			expectedWritten: 4,
+			err:             errInvalidSeal,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				written    []*types.Header
+				latestHead = head
+			)
+
+			client := newHeaderFirstSyncPeerClient(blocks)
+			client.getBodiesHandler = func(context.Context, peer.ID, []types.Hash) ([]*types.Body, error) {
+				t.Error("bodies are not fetched in the light mode")
+
+				return nil, nil
+			}
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedHeaderHandler: func(h *types.Header) error {
+						if h.Number == test.invalidHeader {
+							return errInvalidSeal
+						}
+
+						return nil
+					},
+					writeFinalizedHeaderHandler: func(h *types.Header) error {
+						written = append(written, h)
+						latestHead = h
+
+						return nil
+					},
+				},
+				time.Second,
+				client,
+				&mockProgression{},
+			)
+			syncer.mode = ModeLight
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				t.Error("the callback is not called in the light mode")
+
+				return false
+			})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.expectedWritten, lastNumber)
+			assert.Len(t, written, int(test.expectedWritten))
+
+			for i, header := range written {
+				assert.Equal(t, blocks[i].Hash(), header.Hash)
+			}
+
+			if test.err != nil {
+				assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureInvalidBlock])
+			}
+		})
+	}
+}
+
+func Test_bulkSyncWithPeer_LightReceipts(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks, receipts := createMockChainWithReceipts(head, int(DefaultBatchSize)+10)
+
+	tests := []struct {
+		name            string
+		invalidReceipts uint64
+		expectedWritten uint64
+		err             error
+	}{
+		{
+			name:            "should write all blocks of the peer with their receipts",
+			expectedWritten: uint64(len(blocks)),
+		},
+		{
+			name:            "should stop at the batch whose receipts don't match the headers",
+			invalidReceipts: DefaultBatchSize + 5,
+			expectedWritten: DefaultBatchSize,
+			err:             errReceiptsRootMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				written         []*types.Block
+				writtenReceipts [][]*types.Receipt
+				latestHead      = head
+			)
+
+			client := newHeaderFirstSyncPeerClient(blocks)
+			client.getReceiptsHandler = func(_ context.Context, _ peer.ID, hashes []types.Hash) ([][]*types.Receipt, error) {
+				res := make([][]*types.Receipt, 0, len(hashes))
+
+				for _, hash := range hashes {
+					blockReceipts := receipts[hash]
+					if test.invalidReceipts > 0 && blocks[test.invalidReceipts-1].Hash() == hash {
+						blockReceipts = []*types.Receipt{}
+					}
+
+					res = append(res, blockReceipts)
+				}
+
+				return res, nil
+			}
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedHeaderHandler: func(h *types.Header) error {
+						return nil
+					},
+					writeFinalizedHeaderHandler: func(h *types.Header) error {
+						t.Error("the headers are written with their blocks")
+
+						return nil
+					},
+					writeBlockReceiptsHandler: func(b *types.Block, r []*types.Receipt) error {
+						written = append(written, b)
+						writtenReceipts = append(writtenReceipts, r)
+						latestHead = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				client,
+				&mockProgression{},
+			)
+			syncer.mode = ModeLight
+			syncer.syncReceipts = true
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				t.Error("the callback is not called in the light mode")
+
+				return false
+			})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.expectedWritten, lastNumber)
+			assert.Len(t, written, int(test.expectedWritten))
+
+			for i, block := range written {
+				assert.Equal(t, blocks[i].Hash(), block.Hash())
+				assert.Equal(t, blocks[i].Transactions, block.Transactions)
+				assert.Equal(t, receipts[block.Hash()], writtenReceipts[i])
+			}
+
+			if test.err != nil {
+				assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureInvalidBlock])
+			}
+		})
+	}
+}
+
+func Test_syncer_FetchBody(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 2)
+
+	// A is the best peer but serves a tampered body, B serves the right one
+	client := newHeaderFirstSyncPeerClient(blocks)
+	serveBodies := client.getBodiesHandler
+	client.getBodiesHandler = func(ctx context.Context, peerID peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+		if peerID == peer.ID("A") {
+			return []*types.Body{{Uncles: []*types.Header{{Number: 1}}}}, nil
+		}
+
+		return serveBodies(ctx, peerID, hashes)
+	}
+
+	syncer := NewTestSyncer(nil, nil, time.Second, client, &mockProgression{})
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 2, Distance: big.NewInt(1)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 2, Distance: big.NewInt(2)},
+		// C doesn't have the block
+		&NoForkPeer{ID: peer.ID("C"), Number: 1, Distance: big.NewInt(0)},
+	)
+
+	body, err := syncer.FetchBody(context.Background(), blocks[1].Header)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[1].Body(), body)
+
+	scores := syncer.PeerScores()
+	assert.Len(t, scores, 1)
+	assert.Equal(t, peer.ID("A"), scores[0].ID)
+	assert.Equal(t, uint64(1), scores[0].Failures[FailureInvalidBlock])
+
+	_, err = syncer.FetchBody(context.Background(), (&types.Header{Number: 3}).ComputeHash())
+	assert.ErrorIs(t, err, ErrBodyNotFound)
+}
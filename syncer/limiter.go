@@ -0,0 +1,136 @@
+package syncer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTimeout is the time after which the limiter of a peer without requests is dropped
+const limiterIdleTimeout = time.Minute
+
+// slowReaderPenalty is the time a peer reading a block stream too slowly is refused by the sync peer server
+const slowReaderPenalty = 5 * time.Minute
+
+// requestLimiter limits the rate of the requests of each peer to the sync peer server,
+// and the number of block streams served at once
+type requestLimiter struct {
+	limit rate.Limit
+	burst int
+
+	peers     map[peer.ID]*peerLimiter
+	lastPrune time.Time
+	peersLock sync.Mutex
+
+	// semaphore of the block streams being served
+	streams chan struct{}
+}
+
+type peerLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+
+	// the requests of the peer are refused until then
+	penalizedUntil time.Time
+}
+
+func newRequestLimiter(limit float64, burst, maxStreams uint64) *requestLimiter {
+	return &requestLimiter{
+		limit:     rate.Limit(limit),
+		burst:     int(burst),
+		peers:     make(map[peer.ID]*peerLimiter),
+		lastPrune: time.Now(),
+		streams:   make(chan struct{}, maxStreams),
+	}
+}
+
+// allow returns whether the peer can send a request now
+func (l *requestLimiter) allow(peerID peer.ID) bool {
+	l.peersLock.Lock()
+	defer l.peersLock.Unlock()
+
+	now := time.Now()
+
+	l.prune(now)
+
+	p := l.getPeer(peerID)
+	p.lastSeen = now
+
+	if now.Before(p.penalizedUntil) {
+		return false
+	}
+
+	return p.limiter.AllowN(now, 1)
+}
+
+// setLimits sets the rate and the burst of the requests of each peer, the limiters of the known peers included
+func (l *requestLimiter) setLimits(limit float64, burst uint64) {
+	l.peersLock.Lock()
+	defer l.peersLock.Unlock()
+
+	l.limit = rate.Limit(limit)
+	l.burst = int(burst)
+
+	now := time.Now()
+
+	for _, p := range l.peers {
+		p.limiter.SetLimitAt(now, l.limit)
+		p.limiter.SetBurstAt(now, l.burst)
+	}
+}
+
+// penalize refuses the requests of the peer for the given duration
+func (l *requestLimiter) penalize(peerID peer.ID, duration time.Duration) {
+	l.peersLock.Lock()
+	defer l.peersLock.Unlock()
+
+	now := time.Now()
+
+	p := l.getPeer(peerID)
+	p.lastSeen = now
+	p.penalizedUntil = now.Add(duration)
+}
+
+// getPeer returns the limiter of the peer, creating it if needed
+func (l *requestLimiter) getPeer(peerID peer.ID) *peerLimiter {
+	p, ok := l.peers[peerID]
+	if !ok {
+		p = &peerLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.peers[peerID] = p
+	}
+
+	return p
+}
+
+// prune drops the limiters of the peers which didn't send requests lately and aren't penalized,
+// the limiter of a peer idle for that long is full anyway
+func (l *requestLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < limiterIdleTimeout {
+		return
+	}
+
+	for id, p := range l.peers {
+		if now.Sub(p.lastSeen) >= limiterIdleTimeout && !now.Before(p.penalizedUntil) {
+			delete(l.peers, id)
+		}
+	}
+
+	l.lastPrune = now
+}
+
+// acquireStream takes a slot for serving a block stream, it returns false if all the slots are taken
+func (l *requestLimiter) acquireStream() bool {
+	select {
+	case l.streams <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseStream frees the slot taken by acquireStream
+func (l *requestLimiter) releaseStream() {
+	<-l.streams
+}
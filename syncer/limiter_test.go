@@ -0,0 +1,102 @@
+package syncer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func Test_requestLimiter_allow(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(0.001, 2, 1)
+
+	// the burst is allowed, then the peer has to wait for the rate
+	assert.True(t, limiter.allow(peer.ID("A")))
+	assert.True(t, limiter.allow(peer.ID("A")))
+	assert.False(t, limiter.allow(peer.ID("A")))
+
+	// the limit is per peer
+	assert.True(t, limiter.allow(peer.ID("B")))
+}
+
+func Test_requestLimiter_prune(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(0.001, 1, 1)
+
+	assert.True(t, limiter.allow(peer.ID("A")))
+	assert.True(t, limiter.allow(peer.ID("B")))
+
+	// A is idle for long, B is not
+	limiter.peers[peer.ID("A")].lastSeen = time.Now().Add(-2 * limiterIdleTimeout)
+	limiter.lastPrune = time.Now().Add(-2 * limiterIdleTimeout)
+
+	assert.False(t, limiter.allow(peer.ID("B")))
+
+	assert.Len(t, limiter.peers, 1)
+	assert.Contains(t, limiter.peers, peer.ID("B"))
+
+	// the limiter of A starts over
+	assert.True(t, limiter.allow(peer.ID("A")))
+}
+
+func Test_requestLimiter_setLimits(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(0.001, 1, 1)
+
+	assert.True(t, limiter.allow(peer.ID("A")))
+	assert.False(t, limiter.allow(peer.ID("A")))
+
+	limiter.setLimits(1000, 3)
+
+	// the limiter of the known peer is updated
+	assert.Equal(t, rate.Limit(1000), limiter.peers[peer.ID("A")].limiter.Limit())
+	assert.Equal(t, 3, limiter.peers[peer.ID("A")].limiter.Burst())
+
+	// a new peer gets the new burst
+	assert.True(t, limiter.allow(peer.ID("B")))
+	assert.True(t, limiter.allow(peer.ID("B")))
+	assert.True(t, limiter.allow(peer.ID("B")))
+}
+
+func Test_requestLimiter_streams(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(1, 1, 2)
+
+	assert.True(t, limiter.acquireStream())
+	assert.True(t, limiter.acquireStream())
+	assert.False(t, limiter.acquireStream())
+
+	limiter.releaseStream()
+
+	assert.True(t, limiter.acquireStream())
+}
+
+func Test_requestLimiter_penalize(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(100, 100, 1)
+
+	limiter.penalize(peer.ID("A"), time.Hour)
+
+	assert.False(t, limiter.allow(peer.ID("A")))
+	assert.True(t, limiter.allow(peer.ID("B")))
+
+	// a penalized peer isn't pruned while idle
+	limiter.peers[peer.ID("A")].lastSeen = time.Now().Add(-2 * limiterIdleTimeout)
+	limiter.lastPrune = time.Now().Add(-2 * limiterIdleTimeout)
+
+	assert.True(t, limiter.allow(peer.ID("B")))
+	assert.Contains(t, limiter.peers, peer.ID("A"))
+
+	// the peer is allowed once the penalty expires
+	limiter.peers[peer.ID("A")].penalizedUntil = time.Now()
+
+	assert.True(t, limiter.allow(peer.ID("A")))
+}
@@ -0,0 +1,205 @@
+package syncer
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the syncer metrics
+type Metrics struct {
+	// Requests of peers rejected by the sync peer server for exceeding the rate limit
+	RateLimitedRequests metrics.Counter
+	// Block streams rejected by the sync peer server as too many were served at once
+	RejectedStreams metrics.Counter
+	// Block streams dropped by the sync peer server as the peer didn't read the blocks in time
+	SlowReaders metrics.Counter
+
+	// Blocks written by the bulk sync
+	WrittenBlocks metrics.Counter
+	// Blocks written per second by the current bulk sync session
+	BlockWriteRate metrics.Gauge
+	// Number of peers in the peer map
+	Peers metrics.Gauge
+	// Number of blocks the best peer is ahead of the local chain
+	BestPeerDistance metrics.Gauge
+	// Blocks and headers of the sync peers failing verification
+	VerificationFailures metrics.Counter
+	// Sync peers not serving the requested blocks in time
+	StreamTimeouts metrics.Counter
+	// Bytes downloaded from the sync peers, labeled by peer ID
+	DownloadedBytes metrics.Counter
+	// Blocks written from the prefetch cache
+	PrefetchHits metrics.Counter
+	// Lookups of the next block missing in the prefetch cache, the block being fetched from the peer
+	PrefetchMisses metrics.Counter
+	// Number of blocks in the prefetch cache
+	PrefetchedBlocks metrics.Gauge
+	// Bulk sync sessions yielding after writing the maximum number of blocks
+	SessionYields metrics.Counter
+	// Peers removed for not responding after not sending a status for the status TTL
+	StalePeers metrics.Counter
+	// Peers removed for not answering the keepalive pings
+	DeadPeers metrics.Counter
+	// Requests near the chain tip also sent to a second peer as the sync peer was late
+	HedgedRequests metrics.Counter
+	// Hedged requests the second peer responded to first
+	HedgeWins metrics.Counter
+	// Eras synced from the era archives of the sync peers
+	SyncedEras metrics.Counter
+}
+
+// GetPrometheusMetrics return the syncer metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	peerLabels := append(labels, "peer_id")
+
+	return &Metrics{
+		RateLimitedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "rate_limited_requests",
+			Help:      "Number of peer requests rejected for exceeding the rate limit.",
+		}, labels).With(labelsWithValues...),
+		RejectedStreams: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "rejected_streams",
+			Help:      "Number of block streams rejected as too many were served at once.",
+		}, labels).With(labelsWithValues...),
+		SlowReaders: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "slow_readers",
+			Help:      "Number of block streams dropped as the peer didn't read the blocks in time.",
+		}, labels).With(labelsWithValues...),
+
+		WrittenBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "written_blocks",
+			Help:      "Number of blocks written by the bulk sync.",
+		}, labels).With(labelsWithValues...),
+		BlockWriteRate: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "block_write_rate",
+			Help:      "Blocks written per second by the current bulk sync session.",
+		}, labels).With(labelsWithValues...),
+		Peers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "peers",
+			Help:      "Number of sync peers.",
+		}, labels).With(labelsWithValues...),
+		BestPeerDistance: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "best_peer_distance",
+			Help:      "Number of blocks the best sync peer is ahead of the local chain.",
+		}, labels).With(labelsWithValues...),
+		VerificationFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "verification_failures",
+			Help:      "Number of blocks and headers of the sync peers failing verification.",
+		}, labels).With(labelsWithValues...),
+		StreamTimeouts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "stream_timeouts",
+			Help:      "Number of times a sync peer didn't serve the requested blocks in time.",
+		}, labels).With(labelsWithValues...),
+		DownloadedBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "downloaded_bytes",
+			Help:      "Number of bytes downloaded from each sync peer.",
+		}, peerLabels).With(labelsWithValues...),
+		PrefetchHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "prefetch_hits",
+			Help:      "Number of blocks written from the prefetch cache.",
+		}, labels).With(labelsWithValues...),
+		PrefetchMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "prefetch_misses",
+			Help:      "Number of times the next block was missing in the prefetch cache.",
+		}, labels).With(labelsWithValues...),
+		PrefetchedBlocks: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "prefetched_blocks",
+			Help:      "Number of blocks in the prefetch cache.",
+		}, labels).With(labelsWithValues...),
+		SessionYields: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "session_yields",
+			Help:      "Number of bulk sync sessions yielding after writing the maximum number of blocks.",
+		}, labels).With(labelsWithValues...),
+		StalePeers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "stale_peers",
+			Help:      "Number of sync peers removed for not responding after not sending a status for the status TTL.",
+		}, labels).With(labelsWithValues...),
+		DeadPeers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "dead_peers",
+			Help:      "Number of sync peers removed for not answering the keepalive pings.",
+		}, labels).With(labelsWithValues...),
+		HedgedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "hedged_requests",
+			Help:      "Number of requests near the chain tip also sent to a second peer as the sync peer was late.",
+		}, labels).With(labelsWithValues...),
+		HedgeWins: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "hedge_wins",
+			Help:      "Number of hedged requests the second peer responded to first.",
+		}, labels).With(labelsWithValues...),
+		SyncedEras: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "synced_eras",
+			Help:      "Number of eras synced from the era archives of the sync peers.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational syncer metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		RateLimitedRequests:  discard.NewCounter(),
+		RejectedStreams:      discard.NewCounter(),
+		SlowReaders:          discard.NewCounter(),
+		WrittenBlocks:        discard.NewCounter(),
+		BlockWriteRate:       discard.NewGauge(),
+		Peers:                discard.NewGauge(),
+		BestPeerDistance:     discard.NewGauge(),
+		VerificationFailures: discard.NewCounter(),
+		StreamTimeouts:       discard.NewCounter(),
+		DownloadedBytes:      discard.NewCounter(),
+		PrefetchHits:         discard.NewCounter(),
+		PrefetchMisses:       discard.NewCounter(),
+		PrefetchedBlocks:     discard.NewGauge(),
+		SessionYields:        discard.NewCounter(),
+		StalePeers:           discard.NewCounter(),
+		DeadPeers:            discard.NewCounter(),
+		HedgedRequests:       discard.NewCounter(),
+		HedgeWins:            discard.NewCounter(),
+		SyncedEras:           discard.NewCounter(),
+	}
+}
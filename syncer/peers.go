@@ -2,8 +2,12 @@ package syncer
 
 import (
 	"math/big"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
@@ -12,11 +16,68 @@ type NoForkPeer struct {
 	ID peer.ID
 	// peer's latest block number
 	Number uint64
+	// peer's latest block hash, zero if the peer doesn't report it
+	Hash types.Hash
+	// peer's total difficulty, nil if the peer doesn't report it
+	Difficulty *big.Int
 	// peer's distance
 	Distance *big.Int
+	// rolling estimate of the time to the first block of the peer's block streams, zero if never measured
+	RTT time.Duration
+	// rolling estimate of the bytes per second the peer streams blocks at, zero if never measured
+	Throughput float64
+	// sync protocol version negotiated with the peer
+	Version uint32
+	// optional parts of the protocol served by the peer, advertised from the version 2
+	Capabilities []proto.Capability
 }
 
+// Supports returns whether the peer serves the optional part of the protocol.
+// It is assumed for the peers speaking the version 1, the requests falling back if they don't
+func (p *NoForkPeer) Supports(capability proto.Capability) bool {
+	if p.Version < ProtocolVersion2 || capability == proto.Capability_CAPABILITY_NONE {
+		return true
+	}
+
+	for _, c := range p.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	// bandwidthHeightTolerance is the maximum height difference between two peers
+	// for the one streaming blocks faster to be preferred over the higher one
+	bandwidthHeightTolerance = 16
+
+	// streamEstimateWeight is the weight of a new measurement in the rolling estimates of a peer
+	streamEstimateWeight = 0.3
+)
+
+// IsBetter returns whether the peer is a better sync peer than the given one.
+// Among measured peers of similar heights the one with the higher throughput, then lower RTT is better,
+// as it brings the node to the head sooner. Otherwise the total difficulty is compared
+// if both peers report it, then the latest block number
 func (p *NoForkPeer) IsBetter(t *NoForkPeer) bool {
+	if p.Throughput > 0 && t.Throughput > 0 && heightDiff(p.Number, t.Number) <= bandwidthHeightTolerance {
+		if p.Throughput != t.Throughput {
+			return p.Throughput > t.Throughput
+		}
+
+		if p.RTT != t.RTT {
+			return p.RTT < t.RTT
+		}
+	}
+
+	if p.Difficulty != nil && t.Difficulty != nil {
+		if cmp := p.Difficulty.Cmp(t.Difficulty); cmp != 0 {
+			return cmp > 0
+		}
+	}
+
 	if p.Number != t.Number {
 		return p.Number > t.Number
 	}
@@ -24,8 +85,46 @@ func (p *NoForkPeer) IsBetter(t *NoForkPeer) bool {
 	return p.Distance.Cmp(t.Distance) < 0
 }
 
+func heightDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+// rollingAverage folds the sample into the estimate, the sample being the estimate if there is none yet
+func rollingAverage(estimate, sample float64) float64 {
+	if estimate == 0 {
+		return sample
+	}
+
+	return estimate*(1-streamEstimateWeight) + sample*streamEstimateWeight
+}
+
 type PeerMap struct {
 	sync.Map
+
+	reputation peerReputation
+	quarantine peerQuarantine
+
+	// estimatesLock keeps the stream estimates from being lost
+	// when the status of a peer is updated at the same time
+	estimatesLock sync.Mutex
+
+	// requiredCapability is the capability of the peers returned by BestPeer
+	requiredCapability proto.Capability
+
+	// trusted are the IDs of the peers preferred for downloading the blocks, set on creation
+	trusted map[peer.ID]bool
+
+	// archive are the IDs of the peers preferred for fetching the bodies and the receipts
+	// not stored locally, set on creation
+	archive map[peer.ID]bool
+
+	// statusTimes are the times the last status of each peer was put
+	statusTimes     map[peer.ID]time.Time
+	statusTimesLock sync.Mutex
 }
 
 func NewPeerMap(peers []*NoForkPeer) *PeerMap {
@@ -36,20 +135,192 @@ func NewPeerMap(peers []*NoForkPeer) *PeerMap {
 	return peerMap
 }
 
+// Put stores the peers, keeping the stream estimates of the peers already in the map
 func (m *PeerMap) Put(peers ...*NoForkPeer) {
+	m.estimatesLock.Lock()
+	defer m.estimatesLock.Unlock()
+
+	now := time.Now()
+
 	for _, peer := range peers {
+		if previous := m.Get(peer.ID); previous != nil && peer.RTT == 0 && peer.Throughput == 0 {
+			peer.RTT, peer.Throughput = previous.RTT, previous.Throughput
+		}
+
 		m.Store(peer.ID.String(), peer)
+		m.setStatusTime(peer.ID, now)
 	}
 }
 
+func (m *PeerMap) setStatusTime(peerID peer.ID, t time.Time) {
+	m.statusTimesLock.Lock()
+	defer m.statusTimesLock.Unlock()
+
+	if m.statusTimes == nil {
+		m.statusTimes = make(map[peer.ID]time.Time)
+	}
+
+	m.statusTimes[peerID] = t
+}
+
+// StalePeers returns the peers whose last status was put before the given time
+func (m *PeerMap) StalePeers(before time.Time) []peer.ID {
+	m.statusTimesLock.Lock()
+	defer m.statusTimesLock.Unlock()
+
+	stale := []peer.ID{}
+
+	for peerID, t := range m.statusTimes {
+		if t.Before(before) {
+			stale = append(stale, peerID)
+		}
+	}
+
+	return stale
+}
+
+// RecordStream folds the measurements of a block stream of the peer into its rolling estimates.
+// A zero throughput is ignored, as for a stream too short to be measured
+func (m *PeerMap) RecordStream(peerID peer.ID, rtt time.Duration, throughput float64) {
+	m.estimatesLock.Lock()
+	defer m.estimatesLock.Unlock()
+
+	current := m.Get(peerID)
+	if current == nil {
+		return
+	}
+
+	// the stored peer may be read concurrently, replace it with an updated copy
+	updated := *current
+	updated.RTT = time.Duration(rollingAverage(float64(current.RTT), float64(rtt)))
+
+	if throughput > 0 {
+		updated.Throughput = rollingAverage(current.Throughput, throughput)
+	}
+
+	m.Store(peerID.String(), &updated)
+}
+
+// Len returns the number of peers in the map
+func (m *PeerMap) Len() int {
+	count := 0
+
+	m.Range(func(key, value interface{}) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
+
+// IDs returns the IDs of the peers in the map
+func (m *PeerMap) IDs() []peer.ID {
+	ids := []peer.ID{}
+
+	m.Range(func(key, value interface{}) bool {
+		if p, ok := value.(*NoForkPeer); ok {
+			ids = append(ids, p.ID)
+		}
+
+		return true
+	})
+
+	return ids
+}
+
+// Quarantine excludes the peer on a divergent fork from the sync peer selection.
+// It returns true if the peer wasn't quarantined yet
+func (m *PeerMap) Quarantine(peerID peer.ID) bool {
+	return m.quarantine.add(peerID)
+}
+
+// Release lifts the quarantine of the peer
+func (m *PeerMap) Release(peerID peer.ID) {
+	m.quarantine.remove(peerID)
+}
+
+// IsQuarantined returns whether the peer is quarantined
+func (m *PeerMap) IsQuarantined(peerID peer.ID) bool {
+	return m.quarantine.contains(peerID)
+}
+
+// Get returns the status of the peer, or nil if it isn't in the map
+func (m *PeerMap) Get(peerID peer.ID) *NoForkPeer {
+	value, ok := m.Load(peerID.String())
+	if !ok {
+		return nil
+	}
+
+	peer, _ := value.(*NoForkPeer)
+
+	return peer
+}
+
 // Remove removes a peer from heap if it exists
 func (m *PeerMap) Remove(peerID peer.ID) {
 	m.Delete(peerID.String())
+
+	m.statusTimesLock.Lock()
+	delete(m.statusTimes, peerID)
+	m.statusTimesLock.Unlock()
+}
+
+// RecordFailure lowers the score of the peer for the given failure.
+// It returns true if the peer got banned as a result
+func (m *PeerMap) RecordFailure(peerID peer.ID, failure PeerFailure) bool {
+	return m.reputation.recordFailure(peerID, failure)
+}
+
+// RecordError keeps the error as the last error of the sync with its peer
+func (m *PeerMap) RecordError(err *SyncError) {
+	m.reputation.recordError(err)
+}
+
+// RecordSuccess raises the score of the peer for serving a valid block
+func (m *PeerMap) RecordSuccess(peerID peer.ID) {
+	m.reputation.recordSuccess(peerID)
+}
+
+// IsBanned returns whether the peer is currently banned from syncing
+func (m *PeerMap) IsBanned(peerID peer.ID) bool {
+	banned, _ := m.reputation.status(peerID)
+
+	return banned
+}
+
+// Scores returns the scores of all the peers a failure or a success was recorded for
+func (m *PeerMap) Scores() []*PeerScore {
+	return m.reputation.list()
 }
 
 // BestPeer returns the top of heap
+// Banned and quarantined peers, and the peers lacking the required capability are never returned,
+// and deprioritized peers only if there is no other peer available. The peers announcing a head hash
+// other than the one of the majority at their height come after the others
 func (m *PeerMap) BestPeer(skipMap map[peer.ID]bool) *NoForkPeer {
-	var bestPeer *NoForkPeer
+	return m.bestPeer(skipMap, false)
+}
+
+// BestTrustedPeer returns the best of the trusted peers, under the same conditions as BestPeer
+func (m *PeerMap) BestTrustedPeer(skipMap map[peer.ID]bool) *NoForkPeer {
+	if len(m.trusted) == 0 {
+		return nil
+	}
+
+	return m.bestPeer(skipMap, true)
+}
+
+// IsTrusted returns whether the peer is a trusted sync peer
+func (m *PeerMap) IsTrusted(peerID peer.ID) bool {
+	return m.trusted[peerID]
+}
+
+func (m *PeerMap) bestPeer(skipMap map[peer.ID]bool, trustedOnly bool) *NoForkPeer {
+	var (
+		candidates    = make([]*NoForkPeer, 0)
+		deprioritized = make(map[peer.ID]bool)
+	)
 
 	m.Range(func(key, value interface{}) bool {
 		peer, _ := value.(*NoForkPeer)
@@ -58,12 +329,143 @@ func (m *PeerMap) BestPeer(skipMap map[peer.ID]bool) *NoForkPeer {
 			return true
 		}
 
-		if bestPeer == nil || peer.IsBetter(bestPeer) {
-			bestPeer = peer
+		if trustedOnly && !m.trusted[peer.ID] {
+			return true
 		}
 
+		banned, isDeprioritized := m.reputation.status(peer.ID)
+		if banned || m.quarantine.contains(peer.ID) || !peer.Supports(m.requiredCapability) {
+			return true
+		}
+
+		candidates = append(candidates, peer)
+		deprioritized[peer.ID] = isDeprioritized
+
 		return true
 	})
 
+	minority := minorityHeadPeers(candidates)
+
+	var bestPeer *NoForkPeer
+
+	for _, peer := range candidates {
+		if bestPeer == nil || isPreferred(peer, bestPeer, deprioritized, minority) {
+			bestPeer = peer
+		}
+	}
+
 	return bestPeer
 }
+
+// isPreferred returns whether the peer is preferred over the given one for syncing:
+// the peers not deprioritized first, then the peers announcing the majority head hash, then the better peers
+func isPreferred(p, t *NoForkPeer, deprioritized, minority map[peer.ID]bool) bool {
+	if deprioritized[p.ID] != deprioritized[t.ID] {
+		return !deprioritized[p.ID]
+	}
+
+	if minority[p.ID] != minority[t.ID] {
+		return !minority[p.ID]
+	}
+
+	return p.IsBetter(t)
+}
+
+// minorityHeadPeers returns the peers whose announced head hash is announced by fewer peers than another hash
+// at the same height. They are on a minority fork or corrupted, so they aren't synced from while others are
+// available. The peers not reporting their head hash are never in the minority
+func minorityHeadPeers(peers []*NoForkPeer) map[peer.ID]bool {
+	votes := make(map[uint64]map[types.Hash]int)
+
+	for _, peer := range peers {
+		if peer.Hash == types.ZeroHash {
+			continue
+		}
+
+		if votes[peer.Number] == nil {
+			votes[peer.Number] = make(map[types.Hash]int)
+		}
+
+		votes[peer.Number][peer.Hash]++
+	}
+
+	minority := make(map[peer.ID]bool)
+
+	for _, peer := range peers {
+		if peer.Hash == types.ZeroHash {
+			continue
+		}
+
+		own := votes[peer.Number][peer.Hash]
+
+		for _, count := range votes[peer.Number] {
+			if count > own {
+				minority[peer.ID] = true
+
+				break
+			}
+		}
+	}
+
+	return minority
+}
+
+// PeersWithBlock returns up to n peers serving the bodies whose latest block is at least
+// the given number, from the trusted ones and then the best one.
+// Banned, deprioritized, quarantined and skipped peers are not returned
+func (m *PeerMap) PeersWithBlock(number uint64, n int, skipMap map[peer.ID]bool) []*NoForkPeer {
+	return m.peersWithBlock(number, n, skipMap, proto.Capability_BODIES, m.trusted)
+}
+
+// ArchivePeersWithBlock returns up to n peers serving the given capability whose latest block
+// is at least the given number, from the archive ones and then the best one.
+// Banned, deprioritized and quarantined peers are not returned
+func (m *PeerMap) ArchivePeersWithBlock(number uint64, n int, capability proto.Capability) []*NoForkPeer {
+	return m.peersWithBlock(number, n, nil, capability, m.archive)
+}
+
+// peersWithBlock returns up to n peers serving the capability whose latest block is at least
+// the given number, from the preferred ones and then the best one
+func (m *PeerMap) peersWithBlock(
+	number uint64,
+	n int,
+	skipMap map[peer.ID]bool,
+	capability proto.Capability,
+	preferred map[peer.ID]bool,
+) []*NoForkPeer {
+	peers := make([]*NoForkPeer, 0, n)
+
+	m.Range(func(key, value interface{}) bool {
+		peer, _ := value.(*NoForkPeer)
+
+		if peer.Number < number || skipMap[peer.ID] || !peer.Supports(capability) {
+			return true
+		}
+
+		if banned, deprioritized := m.reputation.status(peer.ID); banned || deprioritized {
+			return true
+		}
+
+		if m.quarantine.contains(peer.ID) {
+			return true
+		}
+
+		peers = append(peers, peer)
+
+		return true
+	})
+
+	sort.Slice(peers, func(i, j int) bool {
+		if preferredI, preferredJ := preferred[peers[i].ID], preferred[peers[j].ID]; preferredI != preferredJ {
+			return preferredI
+		}
+
+		return peers[i].IsBetter(peers[j])
+	})
+
+	if len(peers) > n {
+		peers = peers[:n]
+	}
+
+	return peers
+}
@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 )
@@ -115,6 +117,43 @@ func TestPutPeer(t *testing.T) {
 	)
 }
 
+func TestPeerMap_Get(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(cloneNoForkPeers(peers))
+
+	assert.Equal(t, uint64(20), peerMap.Get(peer.ID("B")).Number)
+	assert.Nil(t, peerMap.Get(peer.ID("D")))
+}
+
+func TestPeerMap_Len(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(cloneNoForkPeers(peers))
+
+	assert.Equal(t, len(peers), peerMap.Len())
+
+	peerMap.Remove(peer.ID("A"))
+
+	assert.Equal(t, len(peers)-1, peerMap.Len())
+}
+
+func TestPeerMap_StalePeers(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(cloneNoForkPeers(peers[:2]))
+	before := time.Now().Add(time.Second)
+
+	assert.ElementsMatch(t, []peer.ID{"A", "B"}, peerMap.StalePeers(before))
+	assert.Empty(t, peerMap.StalePeers(before.Add(-time.Hour)))
+
+	// the status of B is updated and A is removed
+	peerMap.setStatusTime(peer.ID("B"), before.Add(time.Second))
+	peerMap.Remove(peer.ID("A"))
+
+	assert.Empty(t, peerMap.StalePeers(before))
+}
+
 func TestBestPeer(t *testing.T) {
 	t.Parallel()
 
@@ -160,3 +199,104 @@ func TestBestPeer(t *testing.T) {
 		})
 	}
 }
+
+func TestBestPeer_MajorityHead(t *testing.T) {
+	t.Parallel()
+
+	majority, fork := types.StringToHash("1"), types.StringToHash("2")
+
+	peers := []*NoForkPeer{
+		{ID: peer.ID("A"), Number: 10, Hash: majority, Distance: big.NewInt(3)},
+		{ID: peer.ID("B"), Number: 10, Hash: majority, Distance: big.NewInt(2)},
+		// the closest peer announces a minority fork at the same height
+		{ID: peer.ID("C"), Number: 10, Hash: fork, Distance: big.NewInt(1)},
+		// the peers not reporting their head hash aren't in the minority
+		{ID: peer.ID("D"), Number: 8, Distance: big.NewInt(1)},
+	}
+
+	peerMap := NewPeerMap(peers)
+
+	assert.Equal(t, peer.ID("B"), peerMap.BestPeer(nil).ID)
+
+	// the minority peer is returned if there is no other peer
+	assert.Equal(t, peer.ID("C"), peerMap.BestPeer(map[peer.ID]bool{"A": true, "B": true, "D": true}).ID)
+
+	// a tie has no minority
+	assert.Equal(t, peer.ID("C"), peerMap.BestPeer(map[peer.ID]bool{"A": true}).ID)
+
+	assert.Equal(t, map[peer.ID]bool{peer.ID("C"): true}, minorityHeadPeers(peers))
+}
+
+func TestPeerMap_PeersWithBlock(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(peers)
+
+	// sorted from the best peer
+	assert.Equal(t, []*NoForkPeer{peers[2], peers[1], peers[0]}, peerMap.PeersWithBlock(0, 3, nil))
+	assert.Equal(t, []*NoForkPeer{peers[2]}, peerMap.PeersWithBlock(15, 1, nil))
+
+	// skipped and banned peers are not returned
+	peerMap.RecordFailure(peer.ID("B"), FailureInvalidBlock)
+	peerMap.RecordFailure(peer.ID("B"), FailureInvalidBlock)
+
+	assert.Empty(t, peerMap.PeersWithBlock(15, 3, map[peer.ID]bool{peer.ID("C"): true}))
+}
+
+func TestNoForkPeer_IsBetter_Bandwidth(t *testing.T) {
+	t.Parallel()
+
+	high := &NoForkPeer{ID: peer.ID("A"), Number: 100, Distance: big.NewInt(1), RTT: time.Second, Throughput: 1000}
+	fast := &NoForkPeer{ID: peer.ID("B"), Number: 90, Distance: big.NewInt(2), RTT: time.Second, Throughput: 5000}
+	lowRTT := &NoForkPeer{ID: peer.ID("C"), Number: 95, Distance: big.NewInt(2), RTT: time.Millisecond, Throughput: 5000}
+	far := &NoForkPeer{ID: peer.ID("D"), Number: 100 - bandwidthHeightTolerance - 1, Distance: big.NewInt(1),
+		RTT: time.Millisecond, Throughput: 100000}
+	unmeasured := &NoForkPeer{ID: peer.ID("E"), Number: 99, Distance: big.NewInt(1)}
+
+	// within the height tolerance, the throughput then the RTT decide
+	assert.True(t, fast.IsBetter(high))
+	assert.False(t, high.IsBetter(fast))
+	assert.True(t, lowRTT.IsBetter(fast))
+
+	// beyond the tolerance, the height decides
+	assert.True(t, high.IsBetter(far))
+
+	// an unmeasured peer is compared by height only
+	assert.True(t, high.IsBetter(unmeasured))
+	assert.True(t, unmeasured.IsBetter(fast))
+}
+
+func TestPeerMap_RecordStream(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(cloneNoForkPeers(peers))
+	peerID := peers[0].ID
+
+	peerMap.RecordStream(peerID, 100*time.Millisecond, 1000)
+
+	recorded := peerMap.Get(peerID)
+	assert.Equal(t, 100*time.Millisecond, recorded.RTT)
+	assert.Equal(t, float64(1000), recorded.Throughput)
+
+	peerMap.RecordStream(peerID, 200*time.Millisecond, 2000)
+
+	recorded = peerMap.Get(peerID)
+	assert.Equal(t, 130*time.Millisecond, recorded.RTT)
+	assert.InDelta(t, 1300, recorded.Throughput, 0.001)
+
+	// an unmeasured throughput keeps the estimate
+	peerMap.RecordStream(peerID, 130*time.Millisecond, 0)
+	assert.InDelta(t, 1300, peerMap.Get(peerID).Throughput, 0.001)
+
+	// a status update keeps the estimates
+	peerMap.Put(&NoForkPeer{ID: peerID, Number: 30, Distance: big.NewInt(1)})
+
+	updated := peerMap.Get(peerID)
+	assert.Equal(t, uint64(30), updated.Number)
+	assert.Equal(t, 130*time.Millisecond, updated.RTT)
+	assert.InDelta(t, 1300, updated.Throughput, 0.001)
+
+	// unknown peers are ignored
+	peerMap.RecordStream(peer.ID("unknown"), time.Second, 1000)
+	assert.Nil(t, peerMap.Get(peer.ID("unknown")))
+}
@@ -0,0 +1,65 @@
+package syncer
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// preverifiedBlock is a block whose transaction senders are recovered in the worker pool.
+// The done channel is closed once they are, err being the recovery error
+type preverifiedBlock struct {
+	block *types.Block
+	done  chan struct{}
+	err   error
+}
+
+// preverifyBlocks recovers the transaction senders of the blocks in a pool of workers. The signature
+// recovery is CPU-bound and independent of the chain, unlike the full verification, so the blocks
+// are returned in order to be verified and written one after the other as soon as they are recovered.
+// The remaining blocks are skipped once the context is canceled
+func (s *syncer) preverifyBlocks(ctx context.Context, blocks []*types.Block) []*preverifiedBlock {
+	preverified := make([]*preverifiedBlock, len(blocks))
+	jobs := make(chan *preverifiedBlock, len(blocks))
+
+	for i, block := range blocks {
+		preverified[i] = &preverifiedBlock{
+			block: block,
+			done:  make(chan struct{}),
+		}
+
+		jobs <- preverified[i]
+	}
+
+	close(jobs)
+
+	workers := int(s.verifyWorkers)
+	if workers > len(blocks) {
+		workers = len(blocks)
+	}
+
+	if workers == 0 && len(blocks) > 0 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range jobs {
+				if job.err = ctx.Err(); job.err == nil {
+					job.err = s.blockchain.RecoverSenders(job.block)
+				}
+
+				close(job.done)
+			}
+		}()
+	}
+
+	return preverified
+}
+
+// wait waits for the recovery of the transaction senders of the block, and returns its error
+func (b *preverifiedBlock) wait() error {
+	<-b.done
+
+	return b.err
+}
@@ -0,0 +1,159 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_syncer_preverifyBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		head         = (&types.Header{Number: 0}).ComputeHash()
+		blocks       = createMockChain(head, 8)
+		errSignature = errors.New("invalid signature")
+
+		lock    sync.Mutex
+		running int
+		maxRun  int
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			recoverSendersHandler: func(b *types.Block) error {
+				lock.Lock()
+				running++
+				if running > maxRun {
+					maxRun = running
+				}
+				lock.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				lock.Lock()
+				running--
+				lock.Unlock()
+
+				if b.Number() == 5 {
+					return errSignature
+				}
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+	syncer.verifyWorkers = 4
+
+	preverified := syncer.preverifyBlocks(context.Background(), blocks)
+
+	// the blocks are returned in order, with their own recovery error
+	assert.Len(t, preverified, len(blocks))
+
+	for i, p := range preverified {
+		assert.Equal(t, blocks[i], p.block)
+
+		if p.block.Number() == 5 {
+			assert.ErrorIs(t, p.wait(), errSignature)
+		} else {
+			assert.NoError(t, p.wait())
+		}
+	}
+
+	assert.Greater(t, maxRun, 1)
+	assert.LessOrEqual(t, maxRun, 4)
+}
+
+func Test_syncer_preverifyBlocks_Canceled(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 4)
+
+	recovered := 0
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			recoverSendersHandler: func(*types.Block) error {
+				recovered++
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, p := range syncer.preverifyBlocks(ctx, blocks) {
+		assert.ErrorIs(t, p.wait(), context.Canceled)
+	}
+
+	assert.Zero(t, recovered)
+}
+
+func Test_bulkSyncWithPeer_InvalidSenders(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 4)
+	errSignature := errors.New("invalid signature")
+
+	var written []*types.Block
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				if len(written) == 0 {
+					return head
+				}
+
+				return written[len(written)-1].Header
+			},
+			recoverSendersHandler: func(b *types.Block) error {
+				if b.Number() == 3 {
+					return errSignature
+				}
+
+				return nil
+			},
+			verifyFinalizedBlockHandler: func(*types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				written = append(written, b)
+
+				return nil
+			},
+		},
+		time.Second,
+		newHeaderFirstSyncPeerClient(blocks),
+		&mockProgression{},
+	)
+	syncer.verifyWorkers = 4
+
+	lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	// the blocks before the invalid one are written in order
+	assert.ErrorIs(t, err, errSignature)
+	assert.ErrorIs(t, err, ErrVerification)
+	assert.Equal(t, uint64(2), lastNumber)
+	assert.Equal(t, blocks[:2], written)
+	assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureInvalidBlock])
+}
@@ -0,0 +1,292 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// DefaultPrefetchCacheSize is the default number of blocks prefetched ahead of the local head
+const DefaultPrefetchCacheSize uint64 = 32
+
+// prefetchedBlock is a block of the prefetch cache along with the peer it was fetched from
+type prefetchedBlock struct {
+	block  *types.Block
+	peerID peer.ID
+}
+
+// blockCache holds the blocks prefetched ahead of the local head by number,
+// at most size blocks above the head
+type blockCache struct {
+	lock   sync.Mutex
+	size   uint64
+	blocks map[uint64]*prefetchedBlock
+}
+
+func newBlockCache(size uint64) *blockCache {
+	return &blockCache{
+		size:   size,
+		blocks: make(map[uint64]*prefetchedBlock),
+	}
+}
+
+// add caches the blocks within the size above the head
+func (c *blockCache) add(head uint64, peerID peer.ID, blocks []*types.Block) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, block := range blocks {
+		if number := block.Number(); number > head && number <= head+c.size {
+			c.blocks[number] = &prefetchedBlock{block: block, peerID: peerID}
+		}
+	}
+}
+
+// take removes and returns the cached block following the given header.
+// A cached block with another parent is dropped, the local chain having moved to another branch
+func (c *blockCache) take(parent *types.Header) (*prefetchedBlock, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.blocks[parent.Number+1]
+	if !ok {
+		return nil, false
+	}
+
+	delete(c.blocks, parent.Number+1)
+
+	if cached.block.ParentHash() != parent.Hash {
+		return nil, false
+	}
+
+	return cached, true
+}
+
+// next returns the number and the parent hash of the first block to prefetch, following
+// the blocks cached contiguously above the head
+func (c *blockCache) next(head *types.Header) (uint64, types.Hash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	number, parentHash := head.Number+1, head.Hash
+
+	for {
+		cached, ok := c.blocks[number]
+		if !ok {
+			return number, parentHash
+		}
+
+		number, parentHash = number+1, cached.block.Hash()
+	}
+}
+
+// prune removes the blocks at or below the head
+func (c *blockCache) prune(head uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for number := range c.blocks {
+		if number <= head {
+			delete(c.blocks, number)
+		}
+	}
+}
+
+// clear removes all the blocks
+func (c *blockCache) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.blocks = make(map[uint64]*prefetchedBlock)
+}
+
+// len returns the number of cached blocks
+func (c *blockCache) len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.blocks)
+}
+
+// notifyPrefetch wakes up the prefetching, if it isn't busy already
+func (s *syncer) notifyPrefetch() {
+	if s.blockCache == nil {
+		return
+	}
+
+	select {
+	case s.prefetchCh <- struct{}{}:
+	default:
+	}
+}
+
+// startPrefetchProcess prefetches the blocks of the best peer ahead of the local head whenever
+// a new status arrives or prefetched blocks are written, so that the blocks are written
+// without waiting for the network
+func (s *syncer) startPrefetchProcess() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.prefetchCh:
+		}
+
+		if err := s.prefetch(); err != nil {
+			s.logger.Debug("failed to prefetch blocks", "err", err)
+		}
+	}
+}
+
+// prefetch fetches the headers and the bodies following the cached blocks from the best peer,
+// up to the cache size above the local head. The bodies are verified against the headers,
+// the blocks are fully verified when written
+func (s *syncer) prefetch() error {
+	// the context is canceled when the syncer is stopped or closed
+	ctx := s.sessionContext()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	head := s.blockchain.Header()
+	s.blockCache.prune(head.Number)
+
+	from, parentHash := s.blockCache.next(head)
+
+	bestPeer := s.bestPeer(nil, head.Number)
+	if bestPeer == nil || !bestPeer.Supports(proto.Capability_HEADERS) {
+		return nil
+	}
+
+	to := bestPeer.Number
+	if limit := head.Number + s.blockCache.size; to > limit {
+		to = limit
+	}
+
+	if from > to {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.adaptiveTimeout(bestPeer.ID, int(to-from+1), nil))
+	defer cancel()
+
+	headers, err := s.syncPeerClient.GetHeaders(ctx, bestPeer.ID, from, to-from+1)
+	if err != nil {
+		return s.peerError(requestErrorKind(err), bestPeer.ID, err)
+	}
+
+	hashes := make([]types.Hash, 0, len(headers))
+
+	for _, header := range headers {
+		if header.ParentHash != parentHash {
+			return s.peerError(ErrHashMismatch, bestPeer.ID, fmt.Errorf("%w at block %d", ErrMismatchedParent, header.Number))
+		}
+
+		if err := s.trustedCheckpoints.verifyHeader(header); err != nil {
+			return s.peerError(ErrHashMismatch, bestPeer.ID, err)
+		}
+
+		parentHash = header.Hash
+		hashes = append(hashes, header.Hash)
+	}
+
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	bodies, err := s.syncPeerClient.GetBodies(ctx, bestPeer.ID, hashes)
+	if err != nil {
+		return s.peerError(requestErrorKind(err), bestPeer.ID, err)
+	}
+
+	blocks := make([]*types.Block, 0, len(bodies))
+
+	// the peer may return fewer bodies than requested, the following blocks are prefetched next time
+	for i, body := range bodies {
+		if i >= len(headers) {
+			break
+		}
+
+		if err := verifyBody(headers[i], body); err != nil {
+			return s.failPeer(bestPeer.ID, FailureHashMismatch, err)
+		}
+
+		blocks = append(blocks, &types.Block{
+			Header:       headers[i],
+			Transactions: body.Transactions,
+			Uncles:       body.Uncles,
+		})
+	}
+
+	s.blockCache.add(head.Number, bestPeer.ID, blocks)
+	s.metrics.PrefetchedBlocks.Set(float64(s.blockCache.len()))
+
+	return nil
+}
+
+// writePrefetchedBlocks writes the prefetched blocks following the local head, until one is missing.
+// A prefetched block failing verification empties the cache, the blocks being synced from the peer instead
+func (s *syncer) writePrefetchedBlocks(
+	peerID peer.ID,
+	newBlockCallback func(*types.Block) bool,
+) (uint64, bool, error) {
+	if s.blockCache == nil {
+		return 0, false, nil
+	}
+
+	defer s.notifyPrefetch()
+
+	var lastNumber uint64
+
+	for {
+		if err := s.sessionContext().Err(); err != nil {
+			return lastNumber, false, err
+		}
+
+		head := s.blockchain.Header()
+
+		cached, ok := s.blockCache.take(head)
+		if !ok {
+			if syncPeer := s.peerMap.Get(peerID); syncPeer == nil || syncPeer.Number > head.Number {
+				s.metrics.PrefetchMisses.Add(1)
+			}
+
+			break
+		}
+
+		block := cached.block
+
+		if err := s.blockchain.VerifyFinalizedBlock(block); err != nil {
+			err = s.failPeer(cached.peerID, verificationFailure(err), err)
+			s.logger.Warn("prefetched block failed verification", "number", block.Number(), "peer ID", cached.peerID, "err", err)
+			s.blockCache.clear()
+
+			break
+		}
+
+		if err := s.blockchain.WriteFinalizedBlock(s.sessionContext(), block, syncerName); err != nil {
+			return lastNumber, false, fmt.Errorf("failed to write prefetched block: %w", err)
+		}
+
+		s.recordWrittenBlock()
+		s.metrics.PrefetchHits.Add(1)
+		s.peerMap.RecordSuccess(cached.peerID)
+
+		lastNumber = block.Number()
+
+		if newBlockCallback(block) {
+			return lastNumber, true, nil
+		}
+
+		if s.sessionLimitReached() {
+			break
+		}
+	}
+
+	s.metrics.PrefetchedBlocks.Set(float64(s.blockCache.len()))
+
+	return lastNumber, false, nil
+}
@@ -0,0 +1,226 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockCache(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 6)
+
+	cache := newBlockCache(4)
+
+	// only the blocks within the size above the head are cached
+	cache.add(head.Number, peer.ID("A"), blocks)
+	assert.Equal(t, 4, cache.len())
+
+	number, parentHash := cache.next(head)
+	assert.Equal(t, uint64(5), number)
+	assert.Equal(t, blocks[3].Hash(), parentHash)
+
+	cached, ok := cache.take(head)
+	assert.True(t, ok)
+	assert.Equal(t, blocks[0].Hash(), cached.block.Hash())
+	assert.Equal(t, peer.ID("A"), cached.peerID)
+
+	// the next block doesn't follow another parent
+	forkedHead := (&types.Header{Number: 1, ExtraData: []byte("fork")}).ComputeHash()
+
+	_, ok = cache.take(forkedHead)
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.len())
+
+	cache.prune(3)
+	assert.Equal(t, 1, cache.len())
+
+	cache.clear()
+	assert.Equal(t, 0, cache.len())
+}
+
+func TestSyncer_prefetch(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 20)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Second,
+		newHeaderFirstSyncPeerClient(blocks),
+		&mockProgression{},
+	)
+	syncer.blockCache = newBlockCache(8)
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   20,
+		Distance: big.NewInt(0),
+	})
+
+	assert.NoError(t, syncer.prefetch())
+	assert.Equal(t, 8, syncer.blockCache.len())
+
+	// nothing is fetched while the cache is full
+	syncer.syncPeerClient.(*mockSyncPeerClient).getHeadersHandler = func(
+		context.Context, peer.ID, uint64, uint64,
+	) ([]*types.Header, error) {
+		return nil, errors.New("unexpected call")
+	}
+
+	assert.NoError(t, syncer.prefetch())
+	assert.Equal(t, 8, syncer.blockCache.len())
+}
+
+func TestSyncer_prefetch_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 4)
+
+	client := newHeaderFirstSyncPeerClient(blocks)
+	client.getBodiesHandler = func(_ context.Context, _ peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+		bodies := make([]*types.Body, len(hashes))
+
+		for i := range bodies {
+			bodies[i] = &types.Body{
+				Uncles: []*types.Header{{Number: 100}},
+			}
+		}
+
+		return bodies, nil
+	}
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Second,
+		client,
+		&mockProgression{},
+	)
+	syncer.blockCache = newBlockCache(8)
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   4,
+		Distance: big.NewInt(0),
+	})
+
+	assert.Error(t, syncer.prefetch())
+	assert.Equal(t, 0, syncer.blockCache.len())
+	assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureHashMismatch])
+}
+
+func Test_syncWithPeer_Prefetched(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 10)
+
+	var (
+		written    []*types.Block
+		latestHead = head
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return latestHead
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				written = append(written, b)
+				latestHead = b.Header
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getHeadersHandler: func(context.Context, peer.ID, uint64, uint64) ([]*types.Header, error) {
+				return nil, errors.New("unexpected call")
+			},
+		},
+		&mockProgression{},
+	)
+	syncer.blockCache = newBlockCache(16)
+	syncer.prefetchCh = make(chan struct{}, 1)
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   10,
+		Distance: big.NewInt(0),
+	})
+	syncer.blockCache.add(head.Number, peer.ID("A"), blocks)
+
+	lastNumber, shouldTerminate, err := syncer.syncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, shouldTerminate)
+	assert.Equal(t, uint64(10), lastNumber)
+	assert.Len(t, written, len(blocks))
+	assert.Equal(t, 0, syncer.blockCache.len())
+
+	// the prefetching is woken up after the cached blocks are written
+	assert.Len(t, syncer.prefetchCh, 1)
+}
+
+func Test_writePrefetchedBlocks_VerificationFailure(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 4)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return errors.New("invalid seal")
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+	syncer.blockCache = newBlockCache(8)
+	syncer.prefetchCh = make(chan struct{}, 1)
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   4,
+		Distance: big.NewInt(0),
+	})
+	syncer.blockCache.add(head.Number, peer.ID("A"), blocks)
+
+	lastNumber, shouldTerminate, err := syncer.writePrefetchedBlocks(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, shouldTerminate)
+	assert.Equal(t, uint64(0), lastNumber)
+	assert.Equal(t, 0, syncer.blockCache.len())
+	assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureInvalidBlock])
+}
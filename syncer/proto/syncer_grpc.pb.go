@@ -22,6 +22,16 @@ type SyncPeerClient interface {
 	GetBlocks(ctx context.Context, in *GetBlocksRequest, opts ...grpc.CallOption) (SyncPeer_GetBlocksClient, error)
 	// Returns server's status
 	GetStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SyncPeerStatus, error)
+	// Returns a batch of headers beginning specified from
+	GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (*GetHeadersResponse, error)
+	// Returns the bodies of the blocks with the specified hashes
+	GetBodies(ctx context.Context, in *GetBodiesRequest, opts ...grpc.CallOption) (*GetBodiesResponse, error)
+	// Returns the receipts of the blocks with the specified hashes
+	GetReceipts(ctx context.Context, in *GetReceiptsRequest, opts ...grpc.CallOption) (*GetReceiptsResponse, error)
+	// Answers a keepalive ping, echoing its nonce
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// Returns the archive of a complete era in chunks
+	GetEra(ctx context.Context, in *GetEraRequest, opts ...grpc.CallOption) (SyncPeer_GetEraClient, error)
 }
 
 type syncPeerClient struct {
@@ -73,6 +83,74 @@ func (c *syncPeerClient) GetStatus(ctx context.Context, in *emptypb.Empty, opts
 	return out, nil
 }
 
+func (c *syncPeerClient) GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (*GetHeadersResponse, error) {
+	out := new(GetHeadersResponse)
+	err := c.cc.Invoke(ctx, "/v1.SyncPeer/GetHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncPeerClient) GetBodies(ctx context.Context, in *GetBodiesRequest, opts ...grpc.CallOption) (*GetBodiesResponse, error) {
+	out := new(GetBodiesResponse)
+	err := c.cc.Invoke(ctx, "/v1.SyncPeer/GetBodies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncPeerClient) GetReceipts(ctx context.Context, in *GetReceiptsRequest, opts ...grpc.CallOption) (*GetReceiptsResponse, error) {
+	out := new(GetReceiptsResponse)
+	err := c.cc.Invoke(ctx, "/v1.SyncPeer/GetReceipts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncPeerClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, "/v1.SyncPeer/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncPeerClient) GetEra(ctx context.Context, in *GetEraRequest, opts ...grpc.CallOption) (SyncPeer_GetEraClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SyncPeer_serviceDesc.Streams[1], "/v1.SyncPeer/GetEra", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &syncPeerGetEraClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SyncPeer_GetEraClient interface {
+	Recv() (*EraChunk, error)
+	grpc.ClientStream
+}
+
+type syncPeerGetEraClient struct {
+	grpc.ClientStream
+}
+
+func (x *syncPeerGetEraClient) Recv() (*EraChunk, error) {
+	m := new(EraChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SyncPeerServer is the server API for SyncPeer service.
 // All implementations must embed UnimplementedSyncPeerServer
 // for forward compatibility
@@ -81,6 +159,16 @@ type SyncPeerServer interface {
 	GetBlocks(*GetBlocksRequest, SyncPeer_GetBlocksServer) error
 	// Returns server's status
 	GetStatus(context.Context, *emptypb.Empty) (*SyncPeerStatus, error)
+	// Returns a batch of headers beginning specified from
+	GetHeaders(context.Context, *GetHeadersRequest) (*GetHeadersResponse, error)
+	// Returns the bodies of the blocks with the specified hashes
+	GetBodies(context.Context, *GetBodiesRequest) (*GetBodiesResponse, error)
+	// Returns the receipts of the blocks with the specified hashes
+	GetReceipts(context.Context, *GetReceiptsRequest) (*GetReceiptsResponse, error)
+	// Answers a keepalive ping, echoing its nonce
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// Returns the archive of a complete era in chunks
+	GetEra(*GetEraRequest, SyncPeer_GetEraServer) error
 	mustEmbedUnimplementedSyncPeerServer()
 }
 
@@ -94,6 +182,21 @@ func (UnimplementedSyncPeerServer) GetBlocks(*GetBlocksRequest, SyncPeer_GetBloc
 func (UnimplementedSyncPeerServer) GetStatus(context.Context, *emptypb.Empty) (*SyncPeerStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
 }
+func (UnimplementedSyncPeerServer) GetHeaders(context.Context, *GetHeadersRequest) (*GetHeadersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeaders not implemented")
+}
+func (UnimplementedSyncPeerServer) GetBodies(context.Context, *GetBodiesRequest) (*GetBodiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBodies not implemented")
+}
+func (UnimplementedSyncPeerServer) GetReceipts(context.Context, *GetReceiptsRequest) (*GetReceiptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceipts not implemented")
+}
+func (UnimplementedSyncPeerServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedSyncPeerServer) GetEra(*GetEraRequest, SyncPeer_GetEraServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetEra not implemented")
+}
 func (UnimplementedSyncPeerServer) mustEmbedUnimplementedSyncPeerServer() {}
 
 // UnsafeSyncPeerServer may be embedded to opt out of forward compatibility for this service.
@@ -146,6 +249,99 @@ func _SyncPeer_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SyncPeer_GetHeaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeadersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncPeerServer).GetHeaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.SyncPeer/GetHeaders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncPeerServer).GetHeaders(ctx, req.(*GetHeadersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncPeer_GetBodies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBodiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncPeerServer).GetBodies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.SyncPeer/GetBodies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncPeerServer).GetBodies(ctx, req.(*GetBodiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncPeer_GetReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncPeerServer).GetReceipts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.SyncPeer/GetReceipts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncPeerServer).GetReceipts(ctx, req.(*GetReceiptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncPeer_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncPeerServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.SyncPeer/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncPeerServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncPeer_GetEra_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEraRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyncPeerServer).GetEra(m, &syncPeerGetEraServer{stream})
+}
+
+type SyncPeer_GetEraServer interface {
+	Send(*EraChunk) error
+	grpc.ServerStream
+}
+
+type syncPeerGetEraServer struct {
+	grpc.ServerStream
+}
+
+func (x *syncPeerGetEraServer) Send(m *EraChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _SyncPeer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.SyncPeer",
 	HandlerType: (*SyncPeerServer)(nil),
@@ -154,6 +350,22 @@ var _SyncPeer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStatus",
 			Handler:    _SyncPeer_GetStatus_Handler,
 		},
+		{
+			MethodName: "GetHeaders",
+			Handler:    _SyncPeer_GetHeaders_Handler,
+		},
+		{
+			MethodName: "GetBodies",
+			Handler:    _SyncPeer_GetBodies_Handler,
+		},
+		{
+			MethodName: "GetReceipts",
+			Handler:    _SyncPeer_GetReceipts_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _SyncPeer_Ping_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -161,6 +373,11 @@ var _SyncPeer_serviceDesc = grpc.ServiceDesc{
 			Handler:       _SyncPeer_GetBlocks_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetEra",
+			Handler:       _SyncPeer_GetEra_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "syncer/proto/syncer.proto",
 }
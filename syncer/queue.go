@@ -0,0 +1,279 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+)
+
+var (
+	// ErrInvalidHeaderChain is returned when the headers of a peer don't form a chain from the local head
+	ErrInvalidHeaderChain = errors.New("invalid header chain")
+	// ErrMismatchedParent is returned when a header doesn't reference the hash of the previous header
+	ErrMismatchedParent = fmt.Errorf("%w: header parent hash doesn't match the previous header", ErrInvalidHeaderChain)
+
+	errUnexpectedHeaderNumber = fmt.Errorf("%w: header number is not consecutive", ErrInvalidHeaderChain)
+	errTooManyBodies          = errors.New("more bodies than requested")
+	errBodyTxRootMismatch     = errors.New("body transactions don't match the header")
+	errBodyUnclesMismatch     = errors.New("body uncles don't match the header")
+	errTooManyReceipts        = errors.New("more receipts than requested")
+	errReceiptsRootMismatch   = errors.New("receipts don't match the receipts root")
+	errReceiptsGasUsedInvalid = errors.New("receipt gas used doesn't match the cumulative gas used")
+)
+
+// syncQueue assembles the blocks of a header-first sync.
+// Headers are added in batches after their hash chain is validated, the bodies
+// are delivered separately and a block is only handed out once its body arrived.
+// The blocks executed on import get their receipts from the execution. For the blocks written
+// without being executed, the receipts are delivered as well and a block is only
+// handed out once they arrived too
+type syncQueue struct {
+	// last is the latest queued header, or the local head if nothing was queued yet
+	last *types.Header
+
+	// headers are the queued headers in ascending order
+	headers []*types.Header
+
+	// bodies are the delivered bodies, by block hash
+	bodies map[types.Hash]*types.Body
+
+	// withReceipts is whether the blocks wait for their receipts
+	withReceipts bool
+
+	// receipts are the delivered receipts, by block hash
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func newSyncQueue(head *types.Header) *syncQueue {
+	return &syncQueue{
+		last:     head,
+		bodies:   make(map[types.Hash]*types.Body),
+		receipts: make(map[types.Hash][]*types.Receipt),
+	}
+}
+
+// reset drops the queued headers, bodies and receipts
+func (q *syncQueue) reset(head *types.Header) {
+	q.last = head
+	q.headers = nil
+	q.bodies = make(map[types.Hash]*types.Body)
+	q.receipts = make(map[types.Hash][]*types.Receipt)
+}
+
+// len returns the number of queued headers
+func (q *syncQueue) len() int {
+	return len(q.headers)
+}
+
+// addHeaders queues the headers if they extend the hash chain of the queue
+func (q *syncQueue) addHeaders(headers []*types.Header) error {
+	last := q.last
+
+	for _, header := range headers {
+		if header.Number != last.Number+1 {
+			return fmt.Errorf("%w: expected %d, got %d", errUnexpectedHeaderNumber, last.Number+1, header.Number)
+		}
+
+		if header.ParentHash != last.Hash {
+			return fmt.Errorf("%w at block %d", ErrMismatchedParent, header.Number)
+		}
+
+		last = header
+	}
+
+	q.last = last
+	q.headers = append(q.headers, headers...)
+
+	return nil
+}
+
+// pendingBodies returns the hashes of up to limit queued headers whose body hasn't arrived yet
+func (q *syncQueue) pendingBodies(limit int) []types.Hash {
+	hashes := make([]types.Hash, 0, limit)
+
+	for _, header := range q.headers {
+		if len(hashes) == limit {
+			break
+		}
+
+		if _, ok := q.bodies[header.Hash]; !ok {
+			hashes = append(hashes, header.Hash)
+		}
+	}
+
+	return hashes
+}
+
+// deliverBodies stores the bodies of the requested hashes, in the same order.
+// The peer may return fewer bodies than requested, the rest stays pending
+func (q *syncQueue) deliverBodies(hashes []types.Hash, bodies []*types.Body) error {
+	if len(bodies) > len(hashes) {
+		return errTooManyBodies
+	}
+
+	for i, body := range bodies {
+		header := q.findHeader(hashes[i])
+		if header == nil {
+			continue
+		}
+
+		if err := verifyBody(header, body); err != nil {
+			return err
+		}
+
+		q.bodies[header.Hash] = body
+	}
+
+	return nil
+}
+
+// verifyBody checks that the body is the one of the block of the given header
+func verifyBody(header *types.Header, body *types.Body) error {
+	if buildroot.CalculateTransactionsRoot(body.Transactions) != header.TxRoot {
+		return fmt.Errorf("%w at block %d", errBodyTxRootMismatch, header.Number)
+	}
+
+	if buildroot.CalculateUncleRoot(body.Uncles) != header.Sha3Uncles {
+		return fmt.Errorf("%w at block %d", errBodyUnclesMismatch, header.Number)
+	}
+
+	return nil
+}
+
+// pendingReceipts returns the hashes of up to limit queued headers whose receipts haven't arrived yet.
+// The blocks without receipts, according to their header, don't wait for any
+func (q *syncQueue) pendingReceipts(limit int) []types.Hash {
+	hashes := make([]types.Hash, 0, limit)
+
+	if !q.withReceipts {
+		return hashes
+	}
+
+	for _, header := range q.headers {
+		if len(hashes) == limit {
+			break
+		}
+
+		if _, ok := q.receipts[header.Hash]; !ok && header.HasReceipts() {
+			hashes = append(hashes, header.Hash)
+		}
+	}
+
+	return hashes
+}
+
+// deliverReceipts stores the receipts of the requested hashes, in the same order.
+// The peer may return fewer receipts than requested, the rest stays pending
+func (q *syncQueue) deliverReceipts(hashes []types.Hash, receipts [][]*types.Receipt) error {
+	if len(receipts) > len(hashes) {
+		return errTooManyReceipts
+	}
+
+	for i, blockReceipts := range receipts {
+		header := q.findHeader(hashes[i])
+		if header == nil {
+			continue
+		}
+
+		if err := verifyReceipts(header, blockReceipts); err != nil {
+			return err
+		}
+
+		q.receipts[header.Hash] = blockReceipts
+	}
+
+	return nil
+}
+
+// verifyReceipts checks that the receipts are the ones of the block of the given header.
+// The contract addresses and the transaction hashes aren't part of the receipts root,
+// they are trusted from the peer
+func verifyReceipts(header *types.Header, receipts []*types.Receipt) error {
+	if buildroot.CalculateReceiptsRoot(receipts) != header.ReceiptsRoot {
+		return fmt.Errorf("%w at block %d", errReceiptsRootMismatch, header.Number)
+	}
+
+	var cumulativeGasUsed uint64
+
+	for _, receipt := range receipts {
+		if receipt.CumulativeGasUsed-cumulativeGasUsed != receipt.GasUsed {
+			return fmt.Errorf("%w at block %d", errReceiptsGasUsedInvalid, header.Number)
+		}
+
+		cumulativeGasUsed = receipt.CumulativeGasUsed
+	}
+
+	return nil
+}
+
+// findHeader returns the queued header with the given hash
+func (q *syncQueue) findHeader(hash types.Hash) *types.Header {
+	for _, header := range q.headers {
+		if header.Hash == hash {
+			return header
+		}
+	}
+
+	return nil
+}
+
+// popHeaders removes and returns all the queued headers, for the light sync which doesn't fetch the bodies
+func (q *syncQueue) popHeaders() []*types.Header {
+	headers := q.headers
+	q.headers = nil
+
+	return headers
+}
+
+// popBlocks removes and returns the assembled blocks from the front of the queue
+func (q *syncQueue) popBlocks() []*types.Block {
+	blocks, _ := q.popBlocksWithReceipts()
+
+	return blocks
+}
+
+// popBlocksWithReceipts removes and returns the assembled blocks from the front of the queue,
+// along with their receipts if the queue waits for them
+func (q *syncQueue) popBlocksWithReceipts() ([]*types.Block, [][]*types.Receipt) {
+	var (
+		blocks   []*types.Block
+		receipts [][]*types.Receipt
+	)
+
+	for len(q.headers) > 0 {
+		header := q.headers[0]
+
+		body, ok := q.bodies[header.Hash]
+		if !ok {
+			break
+		}
+
+		var blockReceipts []*types.Receipt
+
+		if q.withReceipts {
+			if blockReceipts, ok = q.receipts[header.Hash]; !ok {
+				if header.HasReceipts() {
+					break
+				}
+
+				blockReceipts = []*types.Receipt{}
+			}
+
+			receipts = append(receipts, blockReceipts)
+		}
+
+		blocks = append(blocks, &types.Block{
+			Header:       header,
+			Transactions: body.Transactions,
+			Uncles:       body.Uncles,
+		})
+
+		delete(q.bodies, header.Hash)
+		delete(q.receipts, header.Hash)
+		q.headers = q.headers[1:]
+	}
+
+	return blocks, receipts
+}
@@ -0,0 +1,159 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncQueue_AddHeaders(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 10}).ComputeHash()
+	blocks := createMockChain(head, 4)
+
+	headers := make([]*types.Header, len(blocks))
+	for i, b := range blocks {
+		headers[i] = b.Header
+	}
+
+	queue := newSyncQueue(head)
+
+	// the headers have to start after the head
+	err := queue.addHeaders(headers[1:])
+	assert.ErrorIs(t, err, errUnexpectedHeaderNumber)
+	assert.ErrorIs(t, err, ErrInvalidHeaderChain)
+
+	assert.NoError(t, queue.addHeaders(headers[:2]))
+	assert.Equal(t, 2, queue.len())
+
+	// the next batch has to extend the queued headers
+	forked := (&types.Header{
+		Number:     13,
+		ParentHash: types.BytesToHash([]byte("fork")),
+	}).ComputeHash()
+	err = queue.addHeaders([]*types.Header{forked})
+	assert.ErrorIs(t, err, ErrMismatchedParent)
+	assert.ErrorIs(t, err, ErrInvalidHeaderChain)
+	assert.Equal(t, 2, queue.len())
+
+	assert.NoError(t, queue.addHeaders(headers[2:]))
+	assert.Equal(t, 4, queue.len())
+}
+
+func TestSyncQueue_Bodies(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 4)
+
+	queue := newSyncQueue(head)
+
+	for _, b := range blocks {
+		assert.NoError(t, queue.addHeaders([]*types.Header{b.Header}))
+	}
+
+	hashes := queue.pendingBodies(3)
+	assert.Equal(t, []types.Hash{blocks[0].Hash(), blocks[1].Hash(), blocks[2].Hash()}, hashes)
+
+	// no block is assembled until the bodies arrive
+	assert.Empty(t, queue.popBlocks())
+
+	// partial delivery of the second body only keeps the first block pending
+	assert.NoError(t, queue.deliverBodies(hashes[1:], []*types.Body{blocks[1].Body()}))
+	assert.Empty(t, queue.popBlocks())
+	assert.Equal(t, []types.Hash{blocks[0].Hash(), blocks[2].Hash(), blocks[3].Hash()}, queue.pendingBodies(3))
+
+	assert.NoError(t, queue.deliverBodies(hashes[:1], []*types.Body{blocks[0].Body()}))
+
+	popped := queue.popBlocks()
+	assert.Len(t, popped, 2)
+	assert.Equal(t, blocks[0].Hash(), popped[0].Hash())
+	assert.Equal(t, blocks[1].Hash(), popped[1].Hash())
+	assert.Equal(t, 2, queue.len())
+
+	// more bodies than requested
+	assert.ErrorIs(
+		t,
+		queue.deliverBodies(hashes[2:3], []*types.Body{blocks[2].Body(), blocks[3].Body()}),
+		errTooManyBodies,
+	)
+
+	// body not matching the header
+	assert.ErrorIs(
+		t,
+		queue.deliverBodies(hashes[2:3], []*types.Body{{Uncles: []*types.Header{blocks[0].Header}}}),
+		errBodyUnclesMismatch,
+	)
+}
+
+func TestSyncQueue_Receipts(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks, receipts := createMockChainWithReceipts(head, 3)
+
+	// a block without transactions has no receipts to wait for
+	empty := (&types.Header{
+		ParentHash:   blocks[2].Hash(),
+		Number:       4,
+		Sha3Uncles:   types.EmptyUncleHash,
+		TxRoot:       types.EmptyRootHash,
+		ReceiptsRoot: types.EmptyRootHash,
+	}).ComputeHash()
+	blocks = append(blocks, &types.Block{Header: empty})
+
+	queue := newSyncQueue(head)
+	queue.withReceipts = true
+
+	for _, b := range blocks {
+		assert.NoError(t, queue.addHeaders([]*types.Header{b.Header}))
+		assert.NoError(t, queue.deliverBodies([]types.Hash{b.Hash()}, []*types.Body{b.Body()}))
+	}
+
+	hashes := queue.pendingReceipts(4)
+	assert.Equal(t, []types.Hash{blocks[0].Hash(), blocks[1].Hash(), blocks[2].Hash()}, hashes)
+
+	// no block is assembled until the receipts arrive
+	popped, _ := queue.popBlocksWithReceipts()
+	assert.Empty(t, popped)
+
+	// receipts not matching the header
+	assert.ErrorIs(
+		t,
+		queue.deliverReceipts(hashes[:1], [][]*types.Receipt{receipts[blocks[1].Hash()]}),
+		errReceiptsRootMismatch,
+	)
+
+	// more receipts than requested
+	assert.ErrorIs(
+		t,
+		queue.deliverReceipts(hashes[:1], [][]*types.Receipt{receipts[blocks[0].Hash()], receipts[blocks[1].Hash()]}),
+		errTooManyReceipts,
+	)
+
+	// partial delivery keeps the other receipts pending
+	assert.NoError(t, queue.deliverReceipts(hashes[:2], [][]*types.Receipt{receipts[blocks[0].Hash()]}))
+	assert.Equal(t, []types.Hash{blocks[1].Hash(), blocks[2].Hash()}, queue.pendingReceipts(4))
+
+	popped, poppedReceipts := queue.popBlocksWithReceipts()
+	assert.Len(t, popped, 1)
+	assert.Equal(t, blocks[0].Hash(), popped[0].Hash())
+	assert.Equal(t, [][]*types.Receipt{receipts[blocks[0].Hash()]}, poppedReceipts)
+
+	assert.NoError(t, queue.deliverReceipts(hashes[1:], [][]*types.Receipt{
+		receipts[blocks[1].Hash()],
+		receipts[blocks[2].Hash()],
+	}))
+	assert.Empty(t, queue.pendingReceipts(4))
+
+	popped, poppedReceipts = queue.popBlocksWithReceipts()
+	assert.Len(t, popped, 3)
+	assert.Equal(t, [][]*types.Receipt{
+		receipts[blocks[1].Hash()],
+		receipts[blocks[2].Hash()],
+		{},
+	}, poppedReceipts)
+	assert.Zero(t, queue.len())
+}
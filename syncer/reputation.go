@@ -0,0 +1,246 @@
+package syncer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// PeerFailure is a kind of misbehavior recorded against a sync peer
+type PeerFailure int
+
+const (
+	// FailureTimeout is recorded when the peer doesn't serve blocks in time
+	FailureTimeout PeerFailure = iota
+	// FailureInvalidBlock is recorded when a block from the peer fails verification
+	FailureInvalidBlock
+	// FailureHashMismatch is recorded when a block from the peer has hashes
+	// (parent hash, roots) that don't match the block or the local chain
+	FailureHashMismatch
+)
+
+func (f PeerFailure) String() string {
+	switch f {
+	case FailureTimeout:
+		return "timeout"
+	case FailureInvalidBlock:
+		return "invalid block"
+	case FailureHashMismatch:
+		return "hash mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// maxPeerScore is the highest score a peer can reach by serving blocks
+	maxPeerScore int64 = 100
+	// deprioritizedPeerScore is the score at or below which a peer
+	// is only picked for syncing if there is no better behaving peer
+	deprioritizedPeerScore int64 = -20
+	// bannedPeerScore is the score at or below which a peer is banned
+	bannedPeerScore int64 = -100
+	// peerBanDuration is how long a peer is excluded from the sync peer selection
+	peerBanDuration = 5 * time.Minute
+)
+
+// failurePenalties are the score penalties for each kind of failure
+var failurePenalties = map[PeerFailure]int64{
+	FailureTimeout:      10,
+	FailureInvalidBlock: 50,
+	FailureHashMismatch: 50,
+}
+
+// hashMismatchErrors are the verification errors caused by mismatching hashes
+var hashMismatchErrors = []error{
+	blockchain.ErrParentHashMismatch,
+	blockchain.ErrInvalidParentHash,
+	blockchain.ErrInvalidSha3Uncles,
+	blockchain.ErrInvalidTxRoot,
+	blockchain.ErrInvalidStateRoot,
+	blockchain.ErrInvalidReceiptsRoot,
+}
+
+// verificationFailure returns the kind of failure for an error returned by VerifyFinalizedBlock
+func verificationFailure(err error) PeerFailure {
+	for _, hashErr := range hashMismatchErrors {
+		if errors.Is(err, hashErr) {
+			return FailureHashMismatch
+		}
+	}
+
+	return FailureInvalidBlock
+}
+
+// PeerScore is the reputation of a sync peer
+type PeerScore struct {
+	ID peer.ID
+	// Score decreases with the failures and increases with the blocks served
+	Score int64
+	// Failures is the number of failures recorded for each kind
+	Failures map[PeerFailure]uint64
+	// BannedUntil is the time the ban of the peer expires, zero if the peer is not banned
+	BannedUntil time.Time
+	// LastError is the last error of the sync with the peer, nil if none
+	LastError *SyncError
+	// LastErrorTime is the time the last error occurred
+	LastErrorTime time.Time
+}
+
+func (s *PeerScore) copy() *PeerScore {
+	c := *s
+	c.Failures = make(map[PeerFailure]uint64, len(s.Failures))
+
+	for failure, count := range s.Failures {
+		c.Failures[failure] = count
+	}
+
+	return &c
+}
+
+// peerReputation tracks the scores of the peers. The scores are kept
+// when the peers disconnect, so reconnecting doesn't clear a ban
+type peerReputation struct {
+	sync.Mutex
+
+	scores map[peer.ID]*PeerScore
+	// now returns the current time, overridden in tests
+	now func() time.Time
+}
+
+func (r *peerReputation) currentTime() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+
+	return time.Now()
+}
+
+// getScore returns the score of the peer, creating it if needed. It must be called with the lock held
+func (r *peerReputation) getScore(peerID peer.ID) *PeerScore {
+	if r.scores == nil {
+		r.scores = make(map[peer.ID]*PeerScore)
+	}
+
+	score, ok := r.scores[peerID]
+	if !ok {
+		score = &PeerScore{
+			ID:       peerID,
+			Failures: make(map[PeerFailure]uint64),
+		}
+		r.scores[peerID] = score
+	}
+
+	// lift the expired ban, the peer stays deprioritized until it serves blocks again
+	if !score.BannedUntil.IsZero() && !r.currentTime().Before(score.BannedUntil) {
+		score.BannedUntil = time.Time{}
+		score.Score = deprioritizedPeerScore
+	}
+
+	return score
+}
+
+// recordFailure lowers the score of the peer and bans it if the score gets too low.
+// It returns true if the peer has been banned
+func (r *peerReputation) recordFailure(peerID peer.ID, failure PeerFailure) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	score := r.getScore(peerID)
+	score.Failures[failure]++
+	score.Score -= failurePenalties[failure]
+
+	if score.Score > bannedPeerScore {
+		return false
+	}
+
+	score.Score = bannedPeerScore
+
+	if !score.BannedUntil.IsZero() {
+		// already banned
+		return false
+	}
+
+	score.BannedUntil = r.currentTime().Add(peerBanDuration)
+
+	return true
+}
+
+// recordError keeps the error as the last error of the sync with the peer
+func (r *peerReputation) recordError(err *SyncError) {
+	r.Lock()
+	defer r.Unlock()
+
+	score := r.getScore(err.PeerID)
+	score.LastError = err
+	score.LastErrorTime = r.currentTime()
+}
+
+// recordSuccess raises the score of the peer after it served a valid block
+func (r *peerReputation) recordSuccess(peerID peer.ID) {
+	r.Lock()
+	defer r.Unlock()
+
+	if score := r.getScore(peerID); score.Score < maxPeerScore && score.BannedUntil.IsZero() {
+		score.Score++
+	}
+}
+
+// status returns whether the peer is banned or deprioritized
+func (r *peerReputation) status(peerID peer.ID) (banned bool, deprioritized bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.scores[peerID]; !ok {
+		return false, false
+	}
+
+	score := r.getScore(peerID)
+
+	return !score.BannedUntil.IsZero(), score.Score <= deprioritizedPeerScore
+}
+
+// list returns a copy of all the scores, sorted by peer ID
+func (r *peerReputation) list() []*PeerScore {
+	r.Lock()
+	defer r.Unlock()
+
+	scores := make([]*PeerScore, 0, len(r.scores))
+	for peerID := range r.scores {
+		scores = append(scores, r.getScore(peerID).copy())
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].ID < scores[j].ID
+	})
+
+	return scores
+}
+
+// restore sets the scores of the peers which have none yet, the scores recorded since
+// the start take precedence. It returns the number of restored scores
+func (r *peerReputation) restore(scores []*PeerScore) int {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.scores == nil {
+		r.scores = make(map[peer.ID]*PeerScore)
+	}
+
+	restored := 0
+
+	for _, score := range scores {
+		if _, ok := r.scores[score.ID]; ok {
+			continue
+		}
+
+		r.scores[score.ID] = score.copy()
+		restored++
+	}
+
+	return restored
+}
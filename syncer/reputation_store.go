@@ -0,0 +1,149 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ReputationFileName is the name of the file the misbehavior of the sync peers is persisted to
+const ReputationFileName = "sync_reputation"
+
+// maxPersistedPeerScores is the maximum number of peer scores persisted, the worst ones are kept
+const maxPersistedPeerScores = 1000
+
+// persistedPeerScore is the persisted part of the reputation of a sync peer
+type persistedPeerScore struct {
+	ID          string                 `json:"id"`
+	Score       int64                  `json:"score"`
+	Failures    map[PeerFailure]uint64 `json:"failures,omitempty"`
+	BannedUntil time.Time              `json:"bannedUntil"`
+}
+
+// reputationStore persists the scores of the misbehaving peers, so that
+// a peer doesn't reset its reputation, or lift its ban, by waiting for a restart
+type reputationStore struct {
+	// lock serializes the saves, which write the same temporary file
+	lock sync.Mutex
+	path string
+}
+
+// load reads the persisted peer scores, it returns nil if there are none
+func (r *reputationStore) load() ([]*PeerScore, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var persisted []*persistedPeerScore
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, err
+	}
+
+	scores := make([]*PeerScore, 0, len(persisted))
+
+	for _, p := range persisted {
+		peerID, err := peer.Decode(p.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		score := &PeerScore{
+			ID:          peerID,
+			Score:       p.Score,
+			Failures:    p.Failures,
+			BannedUntil: p.BannedUntil,
+		}
+
+		if score.Failures == nil {
+			score.Failures = make(map[PeerFailure]uint64)
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+// save writes the scores of the misbehaving peers to a temporary file and renames it,
+// so that a crash in the middle of the write leaves the previous ones.
+// The peers in good standing aren't persisted, they start from scratch after a restart
+func (r *reputationStore) save(scores []*PeerScore) error {
+	if r.path == "" {
+		return nil
+	}
+
+	persisted := make([]*persistedPeerScore, 0, len(scores))
+
+	for _, score := range scores {
+		if score.Score >= 0 && score.BannedUntil.IsZero() {
+			continue
+		}
+
+		persisted = append(persisted, &persistedPeerScore{
+			ID:          peer.Encode(score.ID),
+			Score:       score.Score,
+			Failures:    score.Failures,
+			BannedUntil: score.BannedUntil,
+		})
+	}
+
+	if len(persisted) > maxPersistedPeerScores {
+		sort.SliceStable(persisted, func(i, j int) bool {
+			return persisted[i].Score < persisted[j].Score
+		})
+
+		persisted = persisted[:maxPersistedPeerScores]
+	}
+
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	tmpPath := r.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, r.path)
+}
+
+// restoreReputation loads the persisted peer scores into the peer map
+func (s *syncer) restoreReputation() {
+	scores, err := s.reputationStore.load()
+	if err != nil {
+		s.logger.Warn("failed to load sync peer reputation", "error", err)
+
+		return
+	}
+
+	if restored := s.peerMap.reputation.restore(scores); restored > 0 {
+		s.logger.Info("restored sync peer reputation", "peers", restored)
+	}
+}
+
+// saveReputation persists the scores of the misbehaving peers
+func (s *syncer) saveReputation() {
+	if err := s.reputationStore.save(s.peerMap.Scores()); err != nil {
+		s.logger.Warn("failed to save sync peer reputation", "error", err)
+	}
+}
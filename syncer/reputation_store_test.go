@@ -0,0 +1,96 @@
+package syncer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPeerID(t *testing.T) peer.ID {
+	t.Helper()
+
+	_, pub, err := crypto.GenerateKeyPair(crypto.Secp256k1, 256)
+	assert.NoError(t, err)
+
+	id, err := peer.IDFromPublicKey(pub)
+	assert.NoError(t, err)
+
+	return id
+}
+
+func TestReputationStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Now()
+		banned = newTestPeerID(t)
+		faulty = newTestPeerID(t)
+		good   = newTestPeerID(t)
+	)
+
+	peerMap := NewPeerMap(nil)
+	peerMap.reputation.now = func() time.Time {
+		return now
+	}
+
+	peerMap.RecordFailure(banned, FailureInvalidBlock)
+	assert.True(t, peerMap.RecordFailure(banned, FailureHashMismatch))
+	peerMap.RecordFailure(faulty, FailureTimeout)
+	peerMap.RecordSuccess(good)
+
+	store := &reputationStore{path: filepath.Join(t.TempDir(), ReputationFileName)}
+
+	// nothing was saved yet
+	scores, err := store.load()
+	assert.NoError(t, err)
+	assert.Nil(t, scores)
+
+	assert.NoError(t, store.save(peerMap.Scores()))
+
+	// the peers in good standing aren't persisted
+	scores, err = store.load()
+	assert.NoError(t, err)
+	assert.Len(t, scores, 2)
+
+	// the ban survives the restart
+	restarted := NewPeerMap(nil)
+	restarted.reputation.now = peerMap.reputation.now
+
+	assert.Equal(t, 2, restarted.reputation.restore(scores))
+	assert.True(t, restarted.IsBanned(banned))
+	assert.False(t, restarted.IsBanned(faulty))
+
+	for _, score := range restarted.Scores() {
+		switch score.ID {
+		case banned:
+			assert.Equal(t, bannedPeerScore, score.Score)
+			assert.Equal(t, uint64(1), score.Failures[FailureInvalidBlock])
+			assert.Equal(t, uint64(1), score.Failures[FailureHashMismatch])
+		case faulty:
+			assert.Equal(t, -failurePenalties[FailureTimeout], score.Score)
+			assert.Equal(t, uint64(1), score.Failures[FailureTimeout])
+		default:
+			t.Fatalf("unexpected peer %s", score.ID)
+		}
+	}
+
+	// the scores recorded since the start aren't overwritten
+	restarted = NewPeerMap(nil)
+	restarted.RecordSuccess(banned)
+
+	assert.Equal(t, 1, restarted.reputation.restore(scores))
+	assert.False(t, restarted.IsBanned(banned))
+
+	// the ban expires as if the node didn't restart
+	now = now.Add(peerBanDuration)
+
+	restarted = NewPeerMap(nil)
+	restarted.reputation.now = peerMap.reputation.now
+	restarted.reputation.restore(scores)
+
+	assert.False(t, restarted.IsBanned(banned))
+}
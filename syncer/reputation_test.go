@@ -0,0 +1,156 @@
+package syncer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationFailure(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, FailureHashMismatch, verificationFailure(blockchain.ErrParentHashMismatch))
+	assert.Equal(t, FailureHashMismatch, verificationFailure(
+		fmt.Errorf("unable to verify block, %w", blockchain.ErrInvalidStateRoot),
+	))
+	assert.Equal(t, FailureInvalidBlock, verificationFailure(blockchain.ErrInvalidBlockSequence))
+}
+
+func TestPeerMap_RecordFailure(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(nil)
+
+	// timeouts deprioritize the peer before banning it
+	for i := 0; i < 9; i++ {
+		assert.False(t, peerMap.RecordFailure(peer.ID("A"), FailureTimeout))
+	}
+
+	assert.False(t, peerMap.IsBanned(peer.ID("A")))
+	assert.True(t, peerMap.RecordFailure(peer.ID("A"), FailureTimeout))
+	assert.True(t, peerMap.IsBanned(peer.ID("A")))
+
+	// a banned peer is not banned twice
+	assert.False(t, peerMap.RecordFailure(peer.ID("A"), FailureInvalidBlock))
+
+	// invalid blocks are penalized harder
+	assert.False(t, peerMap.RecordFailure(peer.ID("B"), FailureInvalidBlock))
+	assert.True(t, peerMap.RecordFailure(peer.ID("B"), FailureHashMismatch))
+
+	scores := peerMap.Scores()
+	assert.Len(t, scores, 2)
+
+	assert.Equal(t, peer.ID("A"), scores[0].ID)
+	assert.Equal(t, bannedPeerScore, scores[0].Score)
+	assert.Equal(t, uint64(10), scores[0].Failures[FailureTimeout])
+	assert.Equal(t, uint64(1), scores[0].Failures[FailureInvalidBlock])
+
+	assert.Equal(t, peer.ID("B"), scores[1].ID)
+	assert.Equal(t, uint64(1), scores[1].Failures[FailureInvalidBlock])
+	assert.Equal(t, uint64(1), scores[1].Failures[FailureHashMismatch])
+}
+
+func TestPeerMap_BanExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	peerMap := NewPeerMap(nil)
+	peerMap.reputation.now = func() time.Time {
+		return now
+	}
+
+	peerMap.RecordFailure(peer.ID("A"), FailureInvalidBlock)
+	peerMap.RecordFailure(peer.ID("A"), FailureInvalidBlock)
+	assert.True(t, peerMap.IsBanned(peer.ID("A")))
+
+	// blocks served while banned don't count
+	peerMap.RecordSuccess(peer.ID("A"))
+	assert.Equal(t, bannedPeerScore, peerMap.Scores()[0].Score)
+
+	now = now.Add(peerBanDuration)
+
+	assert.False(t, peerMap.IsBanned(peer.ID("A")))
+	assert.Equal(t, deprioritizedPeerScore, peerMap.Scores()[0].Score)
+
+	peerMap.RecordSuccess(peer.ID("A"))
+	assert.Equal(t, deprioritizedPeerScore+1, peerMap.Scores()[0].Score)
+}
+
+func TestPeerMap_RecordSuccess(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap(nil)
+
+	for i := int64(0); i < maxPeerScore+10; i++ {
+		peerMap.RecordSuccess(peer.ID("A"))
+	}
+
+	assert.Equal(t, maxPeerScore, peerMap.Scores()[0].Score)
+}
+
+func TestBestPeer_Reputation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		failures map[peer.ID][]PeerFailure
+		result   *NoForkPeer
+	}{
+		{
+			name: "should skip banned peer",
+			failures: map[peer.ID][]PeerFailure{
+				peer.ID("C"): {FailureInvalidBlock, FailureInvalidBlock},
+			},
+			result: peers[1],
+		},
+		{
+			name: "should prefer peer with better reputation",
+			failures: map[peer.ID][]PeerFailure{
+				peer.ID("C"): {FailureTimeout, FailureTimeout},
+				peer.ID("B"): {FailureTimeout, FailureTimeout},
+			},
+			result: peers[0],
+		},
+		{
+			name: "should return deprioritized peer if no other peer is available",
+			failures: map[peer.ID][]PeerFailure{
+				peer.ID("A"): {FailureInvalidBlock, FailureInvalidBlock},
+				peer.ID("B"): {FailureInvalidBlock, FailureInvalidBlock},
+				peer.ID("C"): {FailureTimeout, FailureTimeout},
+			},
+			result: peers[2],
+		},
+		{
+			name: "should return null if all peers are banned",
+			failures: map[peer.ID][]PeerFailure{
+				peer.ID("A"): {FailureInvalidBlock, FailureInvalidBlock},
+				peer.ID("B"): {FailureInvalidBlock, FailureInvalidBlock},
+				peer.ID("C"): {FailureInvalidBlock, FailureInvalidBlock},
+			},
+			result: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			peerMap := NewPeerMap(peers)
+
+			for peerID, failures := range test.failures {
+				for _, failure := range failures {
+					peerMap.RecordFailure(peerID, failure)
+				}
+			}
+
+			assert.Equal(t, test.result, peerMap.BestPeer(nil))
+		})
+	}
+}
@@ -3,15 +3,36 @@ package syncer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/0xPolygon/polygon-edge/syncer/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxHeadersPerRequest is the maximum number of headers served by GetHeaders
+	maxHeadersPerRequest = 256
+	// maxBodiesPerRequest is the maximum number of bodies served by GetBodies
+	maxBodiesPerRequest = 128
+	// maxReceiptsPerRequest is the maximum number of block receipts served by GetReceipts
+	maxReceiptsPerRequest = 128
 )
 
 var (
-	ErrBlockNotFound = errors.New("block not found")
+	ErrBlockNotFound        = errors.New("block not found")
+	ErrTooManyBodiesInReq   = fmt.Errorf("too many bodies requested, at most %d", maxBodiesPerRequest)
+	ErrTooManyReceiptsInReq = fmt.Errorf("too many receipts requested, at most %d", maxReceiptsPerRequest)
+	errInvalidRequestedHash = errors.New("invalid block hash requested")
+	ErrRateLimited          = status.Error(codes.ResourceExhausted, "request rate limit exceeded")
+	ErrTooManyStreams       = status.Error(codes.ResourceExhausted, "too many block streams")
+	ErrSlowReader           = status.Error(codes.DeadlineExceeded, "block not read in time")
+	ErrEraNotAvailable      = status.Error(codes.NotFound, "era not complete")
 )
 
 type syncPeerService struct {
@@ -20,15 +41,54 @@ type syncPeerService struct {
 	blockchain Blockchain       // reference to the blockchain module
 	network    Network          // reference to the network module
 	stream     *grpc.GrpcStream // reference to the grpc stream
+
+	limiter *requestLimiter // limiter of the peer requests, nil if the requests aren't limited
+	metrics *Metrics
+
+	// time a peer has to read each block of a stream
+	writeTimeout time.Duration
+
+	// optional parts of the protocol served, advertised in the status
+	capabilities []proto.Capability
 }
 
 func NewSyncPeerService(
 	network Network,
 	blockchain Blockchain,
+	config *Config,
 ) SyncPeerService {
+	rateLimit := config.RequestRateLimit
+	if rateLimit == 0 {
+		rateLimit = DefaultRequestRateLimit
+	}
+
+	burst := config.RequestBurst
+	if burst == 0 {
+		burst = DefaultRequestBurst
+	}
+
+	maxStreams := config.MaxConcurrentStreams
+	if maxStreams == 0 {
+		maxStreams = DefaultMaxConcurrentStreams
+	}
+
+	writeTimeout := config.StreamWriteTimeout
+	if writeTimeout == 0 {
+		writeTimeout = DefaultStreamWriteTimeout
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+
 	return &syncPeerService{
-		blockchain: blockchain,
-		network:    network,
+		blockchain:   blockchain,
+		network:      network,
+		limiter:      newRequestLimiter(rateLimit, burst, maxStreams),
+		metrics:      metrics,
+		writeTimeout: writeTimeout,
+		capabilities: config.Mode.capabilities(),
 	}
 }
 
@@ -42,6 +102,13 @@ func (s *syncPeerService) Close() error {
 	return s.stream.Close()
 }
 
+// SetRequestLimits sets the rate and the burst of the requests each peer can send
+func (s *syncPeerService) SetRequestLimits(rateLimit float64, burst uint64) {
+	if s.limiter != nil {
+		s.limiter.setLimits(rateLimit, burst)
+	}
+}
+
 // setupGRPCServer setup GRPC server
 func (s *syncPeerService) setupGRPCServer() {
 	s.stream = grpc.NewGrpcStream()
@@ -56,6 +123,23 @@ func (s *syncPeerService) GetBlocks(
 	req *proto.GetBlocksRequest,
 	stream proto.SyncPeer_GetBlocksServer,
 ) error {
+	if err := s.admitRequest(stream.Context()); err != nil {
+		return err
+	}
+
+	if s.limiter != nil {
+		if !s.limiter.acquireStream() {
+			s.metrics.RejectedStreams.Add(1)
+
+			return ErrTooManyStreams
+		}
+
+		defer s.limiter.releaseStream()
+	}
+
+	// the blocks are compressed with the preferred algorithm of the requester supported by the server
+	compression := selectCompression(req.Compression)
+
 	// from to latest
 	for i := req.From; i <= s.blockchain.Header().Number; i++ {
 		block, ok := s.blockchain.GetBlockByNumber(i, true)
@@ -63,10 +147,17 @@ func (s *syncPeerService) GetBlocks(
 			return ErrBlockNotFound
 		}
 
-		resp := toProtoBlock(block)
+		resp, err := toProtoBlock(block, compression)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sendBlock(stream, resp); err != nil {
+			if errors.Is(err, ErrSlowReader) {
+				return err
+			}
 
-		// if client closes stream, context.Canceled is given
-		if err := stream.Send(resp); err != nil {
+			// if client closes stream, context.Canceled is given
 			break
 		}
 	}
@@ -74,24 +165,290 @@ func (s *syncPeerService) GetBlocks(
 	return nil
 }
 
-// GetStatus is a gRPC endpoint to return the latest block number as a node status
+// sendBlock sends the block to the stream, the peer has to read it before the write timeout.
+// The blocks are sent one at a time, so a peer reading slowly holds at most one block in memory.
+// A peer not reading in time is penalized, and the stream is dropped with ErrSlowReader
+func (s *syncPeerService) sendBlock(stream proto.SyncPeer_GetBlocksServer, block *proto.Block) error {
+	return s.sendInTime(stream.Context(), func() error {
+		return stream.Send(block)
+	})
+}
+
+// sendInTime sends a message of the stream of the given context with the given send function,
+// the peer has to read it before the write timeout
+func (s *syncPeerService) sendInTime(ctx context.Context, send func() error) error {
+	if s.writeTimeout <= 0 {
+		return send()
+	}
+
+	errCh := make(chan error, 1)
+
+	// Send blocks until the peer has room for the message, and stops when the stream ends
+	go func() {
+		errCh <- send()
+	}()
+
+	timer := time.NewTimer(s.writeTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C:
+		s.metrics.SlowReaders.Add(1)
+
+		if s.limiter != nil {
+			s.limiter.penalize(requestPeerID(ctx), slowReaderPenalty)
+		}
+
+		return ErrSlowReader
+	}
+}
+
+// GetEra is a gRPC endpoint to stream the archive of a complete era in chunks.
+// The archive is built from the stored blocks as it is sent
+func (s *syncPeerService) GetEra(
+	req *proto.GetEraRequest,
+	stream proto.SyncPeer_GetEraServer,
+) error {
+	if err := s.admitRequest(stream.Context()); err != nil {
+		return err
+	}
+
+	if req.Era >= completeEras(s.blockchain.Header().Number) {
+		return ErrEraNotAvailable
+	}
+
+	// the archives are streamed like the blocks, and count against the same limit
+	if s.limiter != nil {
+		if !s.limiter.acquireStream() {
+			s.metrics.RejectedStreams.Add(1)
+
+			return ErrTooManyStreams
+		}
+
+		defer s.limiter.releaseStream()
+	}
+
+	chunks := newEraChunkWriter(func(chunk *proto.EraChunk) error {
+		return s.sendInTime(stream.Context(), func() error {
+			return stream.Send(chunk)
+		})
+	})
+
+	archive := newEraWriter(chunks, req.Era)
+	first, last := eraRange(req.Era)
+
+	for i := first; i <= last; i++ {
+		if err := stream.Context().Err(); err != nil {
+			// the client closed the stream
+			return err
+		}
+
+		block, ok := s.blockchain.GetBlockByNumber(i, true)
+		if !ok {
+			return ErrBlockNotFound
+		}
+
+		if err := archive.add(block); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.finish(); err != nil {
+		return err
+	}
+
+	return chunks.flush()
+}
+
+// GetStatus is a gRPC endpoint to return the latest block as a node status
 func (s *syncPeerService) GetStatus(
 	ctx context.Context,
 	req *empty.Empty,
 ) (*proto.SyncPeerStatus, error) {
-	var number uint64
+	if err := s.admitRequest(ctx); err != nil {
+		return nil, err
+	}
+
+	peerStatus := &proto.SyncPeerStatus{
+		Versions:     supportedVersions,
+		Capabilities: s.capabilities,
+	}
+
 	if header := s.blockchain.Header(); header != nil {
-		number = header.Number
+		peerStatus.Number = header.Number
+		peerStatus.Hash = header.Hash.Bytes()
+
+		if td, ok := s.blockchain.GetTD(header.Hash); ok {
+			peerStatus.Difficulty = td.Bytes()
+		}
 	}
 
-	return &proto.SyncPeerStatus{
-		Number: number,
-	}, nil
+	return peerStatus, nil
 }
 
-// toProtoBlock converts type.Block -> proto.Block
-func toProtoBlock(block *types.Block) *proto.Block {
-	return &proto.Block{
-		Block: block.MarshalRLP(),
+// GetHeaders is a gRPC endpoint to return consecutive headers from the specific height
+func (s *syncPeerService) GetHeaders(
+	ctx context.Context,
+	req *proto.GetHeadersRequest,
+) (*proto.GetHeadersResponse, error) {
+	if err := s.admitRequest(ctx); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount > maxHeadersPerRequest {
+		amount = maxHeadersPerRequest
+	}
+
+	resp := &proto.GetHeadersResponse{
+		Headers: make([][]byte, 0, amount),
+	}
+
+	latest := s.blockchain.Header().Number
+
+	for i := req.From; i <= latest && uint64(len(resp.Headers)) < amount; i++ {
+		header, ok := s.blockchain.GetHeaderByNumber(i)
+		if !ok {
+			return nil, ErrBlockNotFound
+		}
+
+		resp.Headers = append(resp.Headers, header.MarshalRLP())
+	}
+
+	return resp, nil
+}
+
+// GetBodies is a gRPC endpoint to return the bodies of the blocks with the given hashes
+func (s *syncPeerService) GetBodies(
+	ctx context.Context,
+	req *proto.GetBodiesRequest,
+) (*proto.GetBodiesResponse, error) {
+	if err := s.admitRequest(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(req.Hashes) > maxBodiesPerRequest {
+		return nil, ErrTooManyBodiesInReq
 	}
+
+	resp := &proto.GetBodiesResponse{
+		Bodies: make([]*proto.Body, 0, len(req.Hashes)),
+	}
+
+	for _, rawHash := range req.Hashes {
+		if len(rawHash) != types.HashLength {
+			return nil, errInvalidRequestedHash
+		}
+
+		body, ok := s.blockchain.GetBodyByHash(types.BytesToHash(rawHash))
+		if !ok {
+			return nil, ErrBlockNotFound
+		}
+
+		resp.Bodies = append(resp.Bodies, toProtoBody(body))
+	}
+
+	return resp, nil
+}
+
+// Ping is a gRPC endpoint to answer a keepalive ping. It isn't subject to the request rate limit,
+// so that a peer at the limit isn't taken for dead
+func (s *syncPeerService) Ping(
+	ctx context.Context,
+	req *proto.PingRequest,
+) (*proto.PingResponse, error) {
+	return &proto.PingResponse{Nonce: req.Nonce}, nil
+}
+
+// GetReceipts is a gRPC endpoint to return the receipts of the blocks with the given hashes
+func (s *syncPeerService) GetReceipts(
+	ctx context.Context,
+	req *proto.GetReceiptsRequest,
+) (*proto.GetReceiptsResponse, error) {
+	if err := s.admitRequest(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(req.Hashes) > maxReceiptsPerRequest {
+		return nil, ErrTooManyReceiptsInReq
+	}
+
+	resp := &proto.GetReceiptsResponse{
+		Receipts: make([][]byte, 0, len(req.Hashes)),
+	}
+
+	for _, rawHash := range req.Hashes {
+		if len(rawHash) != types.HashLength {
+			return nil, errInvalidRequestedHash
+		}
+
+		receipts, err := s.blockchain.GetReceiptsByHash(types.BytesToHash(rawHash))
+		if err != nil {
+			// the light and the gateway nodes don't have the receipts of all the blocks
+			return nil, ErrBlockNotFound
+		}
+
+		resp.Receipts = append(resp.Receipts, types.Receipts(receipts).MarshalStoreRLPTo(nil))
+	}
+
+	return resp, nil
+}
+
+// admitRequest returns an error if the peer sending the request exceeds its rate limit
+func (s *syncPeerService) admitRequest(ctx context.Context) error {
+	if s.limiter == nil {
+		return nil
+	}
+
+	if !s.limiter.allow(requestPeerID(ctx)) {
+		s.metrics.RateLimitedRequests.Add(1)
+
+		return ErrRateLimited
+	}
+
+	return nil
+}
+
+// requestPeerID returns the ID of the peer sending the request,
+// the requests of unknown peers share the same limit
+func requestPeerID(ctx context.Context) peer.ID {
+	if peerCtx, ok := ctx.(*grpc.Context); ok {
+		return peerCtx.PeerID
+	}
+
+	return ""
+}
+
+// toProtoBody converts types.Body -> proto.Body
+// The transactions are encoded without their senders, which are recovered by the receiver
+func toProtoBody(body *types.Body) *proto.Body {
+	protoBody := &proto.Body{
+		Transactions: make([][]byte, len(body.Transactions)),
+		Uncles:       make([][]byte, len(body.Uncles)),
+	}
+
+	for i, tx := range body.Transactions {
+		protoBody.Transactions[i] = tx.MarshalRLP()
+	}
+
+	for i, uncle := range body.Uncles {
+		protoBody.Uncles[i] = uncle.MarshalRLP()
+	}
+
+	return protoBody
+}
+
+// toProtoBlock converts type.Block -> proto.Block, the encoded block is compressed with the given algorithm
+func toProtoBlock(block *types.Block, compression proto.Compression) (*proto.Block, error) {
+	data, err := compressBlock(compression, block.MarshalRLP())
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.Block{
+		Block:       data,
+		Compression: compression,
+	}, nil
 }
@@ -2,22 +2,56 @@ package syncer
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log"
+	"math/big"
 	"net"
+	"sync"
 	"testing"
+	"time"
 
+	edgegrpc "github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/0xPolygon/polygon-edge/syncer/proto"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 const bufSize = 1024 * 1024
 
+// mockCounter is a counter metric which can be read
+type mockCounter struct {
+	lock  sync.Mutex
+	value float64
+}
+
+func (c *mockCounter) With(...string) metrics.Counter {
+	return c
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.value += delta
+}
+
+func (c *mockCounter) Value() float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.value
+}
+
 func newMockGrpcClient(t *testing.T, service *syncPeerService) proto.SyncPeerClient {
 	t.Helper()
 
@@ -135,6 +169,76 @@ func Test_syncPeerService_GetBlocks(t *testing.T) {
 	}
 }
 
+func Test_syncPeerService_GetBlocks_Compression(t *testing.T) {
+	t.Parallel()
+
+	blocks := createMockBlocks(5)
+
+	tests := []struct {
+		name        string
+		accepted    []proto.Compression
+		compression proto.Compression
+	}{
+		{
+			name:        "should send uncompressed blocks to the peers without compression support",
+			accepted:    nil,
+			compression: proto.Compression_NONE,
+		},
+		{
+			name:        "should send the blocks with the compression requested by the peer",
+			accepted:    []proto.Compression{proto.Compression_ZSTD},
+			compression: proto.Compression_ZSTD,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &syncPeerService{
+				blockchain: &mockBlockchain{
+					headerHandler: newSimpleHeaderHandler(5),
+					getBlockByNumberHandler: func(u uint64, _ bool) (*types.Block, bool) {
+						return blocks[u-1], true
+					},
+				},
+			}
+
+			client := newMockGrpcClient(t, service)
+
+			stream, err := client.GetBlocks(context.Background(), &proto.GetBlocksRequest{
+				From:        1,
+				Compression: test.accepted,
+			})
+
+			assert.NoError(t, err)
+
+			count := 0
+
+			for {
+				protoBlock, err := stream.Recv()
+				if err != nil {
+					assert.ErrorIs(t, err, io.EOF)
+
+					break
+				}
+
+				assert.Equal(t, test.compression, protoBlock.Compression)
+
+				block, err := fromProto(protoBlock)
+				assert.NoError(t, err)
+				assert.Equal(t, blocks[count].MarshalRLP(), block.MarshalRLP())
+
+				count++
+			}
+
+			assert.Equal(t, len(blocks), count)
+		})
+	}
+}
+
 func TestGetStatus(t *testing.T) {
 	t.Parallel()
 
@@ -153,3 +257,359 @@ func TestGetStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, headerNumber, status.Number)
 }
+
+func Test_syncPeerService_GetHeaders(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 10)
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(10),
+			getHeaderByNumberHandler: func(u uint64) (*types.Header, bool) {
+				if u == 0 || u > 10 {
+					return nil, false
+				}
+
+				return blocks[u-1].Header, true
+			},
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	tests := []struct {
+		name     string
+		from     uint64
+		amount   uint64
+		expected []*types.Block
+	}{
+		{
+			name:     "should return the requested amount of headers",
+			from:     2,
+			amount:   3,
+			expected: blocks[1:4],
+		},
+		{
+			name:     "should return the headers up to the latest",
+			from:     8,
+			amount:   10,
+			expected: blocks[7:],
+		},
+		{
+			name:     "should return no header past the latest",
+			from:     11,
+			amount:   10,
+			expected: []*types.Block{},
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := client.GetHeaders(context.Background(), &proto.GetHeadersRequest{
+			From:   test.from,
+			Amount: test.amount,
+		})
+
+		assert.NoError(t, err, test.name)
+		assert.Len(t, resp.Headers, len(test.expected), test.name)
+
+		for i, b := range test.expected {
+			assert.Equal(t, b.Header.MarshalRLP(), resp.Headers[i], test.name)
+		}
+	}
+}
+
+func Test_syncPeerService_GetBodies(t *testing.T) {
+	t.Parallel()
+
+	body := &types.Body{
+		Transactions: []*types.Transaction{
+			{
+				Nonce:    1,
+				GasPrice: big.NewInt(1),
+				Value:    big.NewInt(10),
+				From:     types.StringToAddress("1"),
+			},
+		},
+	}
+
+	knownHash := types.BytesToHash([]byte("known"))
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			getBodyByHashHandler: func(h types.Hash) (*types.Body, bool) {
+				if h != knownHash {
+					return nil, false
+				}
+
+				return body, true
+			},
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	resp, err := client.GetBodies(context.Background(), &proto.GetBodiesRequest{
+		Hashes: [][]byte{knownHash.Bytes()},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Bodies, 1)
+
+	received, err := fromProtoBody(resp.Bodies[0])
+	assert.NoError(t, err)
+	assert.Len(t, received.Transactions, 1)
+	assert.Equal(t, body.Transactions[0].Nonce, received.Transactions[0].Nonce)
+	assert.Equal(t, body.Transactions[0].Value, received.Transactions[0].Value)
+
+	// the sender is not sent
+	assert.Equal(t, types.ZeroAddress, received.Transactions[0].From)
+
+	_, err = client.GetBodies(context.Background(), &proto.GetBodiesRequest{
+		Hashes: [][]byte{types.BytesToHash([]byte("unknown")).Bytes()},
+	})
+	assert.ErrorContains(t, err, ErrBlockNotFound.Error())
+
+	_, err = client.GetBodies(context.Background(), &proto.GetBodiesRequest{
+		Hashes: make([][]byte, maxBodiesPerRequest+1),
+	})
+	assert.ErrorContains(t, err, ErrTooManyBodiesInReq.Error())
+}
+
+func Test_syncPeerService_GetReceipts(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("2")
+	receipts := []*types.Receipt{
+		{
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            types.StringToHash("1"),
+			ContractAddress:   &contract,
+		},
+	}
+	receipts[0].SetStatus(types.ReceiptSuccess)
+
+	knownHash := types.BytesToHash([]byte("known"))
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			getReceiptsByHashHandler: func(h types.Hash) ([]*types.Receipt, error) {
+				if h != knownHash {
+					return nil, errors.New("not found")
+				}
+
+				return receipts, nil
+			},
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	resp, err := client.GetReceipts(context.Background(), &proto.GetReceiptsRequest{
+		Hashes: [][]byte{knownHash.Bytes()},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Receipts, 1)
+
+	// the receipts are sent in the storage format, with the fields out of the receipts root
+	var received types.Receipts
+
+	assert.NoError(t, received.UnmarshalStoreRLP(resp.Receipts[0]))
+	assert.Equal(t, types.Receipts(receipts), received)
+
+	_, err = client.GetReceipts(context.Background(), &proto.GetReceiptsRequest{
+		Hashes: [][]byte{types.BytesToHash([]byte("unknown")).Bytes()},
+	})
+	assert.ErrorContains(t, err, ErrBlockNotFound.Error())
+
+	_, err = client.GetReceipts(context.Background(), &proto.GetReceiptsRequest{
+		Hashes: make([][]byte, maxReceiptsPerRequest+1),
+	})
+	assert.ErrorContains(t, err, ErrTooManyReceiptsInReq.Error())
+}
+
+func Test_syncPeerService_GetEra(t *testing.T) {
+	t.Parallel()
+
+	blocks := createEraChain(2)
+
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			headerHandler: func() *types.Header {
+				// the second era isn't complete
+				return blocks[2*EraSize-2].Header
+			},
+			getBlockByNumberHandler: func(number uint64, _ bool) (*types.Block, bool) {
+				return blocks[number], true
+			},
+		},
+		metrics: NilMetrics(),
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	stream, err := client.GetEra(context.Background(), &proto.GetEraRequest{Era: 0})
+	assert.NoError(t, err)
+
+	var data []byte
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		assert.NoError(t, err)
+
+		data = append(data, chunk.Data...)
+	}
+
+	received, err := decodeEra(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[EraSize-1].Hash(), received[EraSize-1].Hash())
+
+	stream, err = client.GetEra(context.Background(), &proto.GetEraRequest{Era: 1})
+	assert.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_syncPeerService_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	rateLimited := &mockCounter{}
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(10),
+		},
+		limiter: newRequestLimiter(0.001, 2, 1),
+		metrics: &Metrics{
+			RateLimitedRequests: rateLimited,
+			RejectedStreams:     discard.NewCounter(),
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetStatus(context.Background(), &emptypb.Empty{})
+		assert.NoError(t, err)
+	}
+
+	_, err := client.GetHeaders(context.Background(), &proto.GetHeadersRequest{From: 1, Amount: 1})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, float64(1), rateLimited.Value())
+}
+
+func Test_syncPeerService_MaxConcurrentStreams(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 1)
+
+	servingCh, unblockCh := make(chan struct{}), make(chan struct{})
+
+	rejectedStreams := &mockCounter{}
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(1),
+			getBlockByNumberHandler: func(u uint64, _ bool) (*types.Block, bool) {
+				close(servingCh)
+				<-unblockCh
+
+				return blocks[0], true
+			},
+		},
+		limiter: newRequestLimiter(100, 100, 1),
+		metrics: &Metrics{
+			RateLimitedRequests: discard.NewCounter(),
+			RejectedStreams:     rejectedStreams,
+		},
+	}
+
+	client := newMockGrpcClient(t, service)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := client.GetBlocks(ctx, &proto.GetBlocksRequest{From: 1})
+	assert.NoError(t, err)
+
+	// the first stream takes the only slot
+	<-servingCh
+
+	second, err := client.GetBlocks(ctx, &proto.GetBlocksRequest{From: 1})
+	assert.NoError(t, err)
+
+	_, err = second.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, float64(1), rejectedStreams.Value())
+
+	close(unblockCh)
+
+	block, err := first.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[0].MarshalRLP(), block.Block)
+}
+
+// blockingBlocksStream is a block stream of a peer never reading the blocks
+type blockingBlocksStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *blockingBlocksStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *blockingBlocksStream) Send(*proto.Block) error {
+	<-s.ctx.Done()
+
+	return s.ctx.Err()
+}
+
+func Test_syncPeerService_SlowReader(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 2)
+
+	slowReaders := &mockCounter{}
+	service := &syncPeerService{
+		blockchain: &mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(2),
+			getBlockByNumberHandler: func(u uint64, _ bool) (*types.Block, bool) {
+				return blocks[u-1], true
+			},
+		},
+		limiter: newRequestLimiter(100, 100, 1),
+		metrics: &Metrics{
+			RateLimitedRequests: discard.NewCounter(),
+			RejectedStreams:     discard.NewCounter(),
+			SlowReaders:         slowReaders,
+		},
+		writeTimeout: 50 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &blockingBlocksStream{
+		ctx: &edgegrpc.Context{Context: ctx, PeerID: peer.ID("A")},
+	}
+
+	err := service.GetBlocks(&proto.GetBlocksRequest{From: 1}, stream)
+	assert.ErrorIs(t, err, ErrSlowReader)
+	assert.Equal(t, float64(1), slowReaders.Value())
+
+	// the stream slot is released
+	assert.True(t, service.limiter.acquireStream())
+	service.limiter.releaseStream()
+
+	// the peer is refused for a while, the other peers are not
+	assert.ErrorIs(t, service.admitRequest(stream.Context()), ErrRateLimited)
+	assert.NoError(t, service.admitRequest(&edgegrpc.Context{Context: ctx, PeerID: peer.ID("B")}))
+}
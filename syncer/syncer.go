@@ -1,15 +1,22 @@
 package syncer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network/event"
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -17,8 +24,114 @@ const (
 	syncerProto = "/syncer/0.2"
 )
 
+const (
+	// DefaultBatchSize is the default number of headers requested at once in header-first sync
+	DefaultBatchSize uint64 = 128
+	// DefaultMaxPeers is the default number of peers the bodies are fetched from concurrently
+	DefaultMaxPeers uint64 = 1
+	// bodyBatchDivisor is the ratio between the header and the body batch sizes,
+	// as bodies are much larger than headers
+	bodyBatchDivisor = 4
+	// maxPendingHeaderBatches is the number of header batches fetched ahead of the bodies
+	maxPendingHeaderBatches = 2
+
+	// DefaultRequestRateLimit is the default number of requests per second a peer can send to the sync peer server
+	DefaultRequestRateLimit float64 = 50
+	// DefaultRequestBurst is the default number of requests a peer can send at once to the sync peer server
+	DefaultRequestBurst uint64 = 100
+	// DefaultMaxConcurrentStreams is the default number of block streams the sync peer server serves at once
+	DefaultMaxConcurrentStreams uint64 = 16
+	// DefaultStreamWriteTimeout is the default time a peer has to read each block streamed by the sync peer server
+	DefaultStreamWriteTimeout = 10 * time.Second
+	// DefaultCompression is the default compression requested for the block streams
+	DefaultCompression = "snappy"
+	// DefaultPeerStatusTTL is the default time after which the status of a silent peer is requested again
+	DefaultPeerStatusTTL = 2 * time.Minute
+	// DefaultKeepaliveInterval is the default interval between the keepalive pings of the sync peers
+	DefaultKeepaliveInterval = 10 * time.Second
+	// DefaultKeepaliveTimeout is the default time a sync peer has to answer a keepalive ping
+	DefaultKeepaliveTimeout = 5 * time.Second
+	// sessionYieldDelay is the pause between a bulk sync session reaching the maximum
+	// number of blocks and the next session
+	sessionYieldDelay = 100 * time.Millisecond
+)
+
+// Config holds the tunable parameters of the syncer
+type Config struct {
+	// BlockTimeout is the timeout for receiving a block, or a batch of headers or bodies.
+	// The timeout for the blocks and the bodies adapts to their size once it is measured
+	BlockTimeout time.Duration
+	// MinBlockTimeout is the floor of the adaptive block timeout, the block timeout if zero
+	MinBlockTimeout time.Duration
+	// MaxBlockTimeout is the ceiling of the adaptive block timeout, the default if zero
+	MaxBlockTimeout time.Duration
+	// BatchSize is the number of headers requested at once
+	BatchSize uint64
+	// MaxPeers is the maximum number of peers the bodies are fetched from concurrently
+	MaxPeers uint64
+	// CheckpointPath is the file the header-first sync progress is persisted to,
+	// so that it resumes after a restart. Empty disables the checkpoints
+	CheckpointPath string
+	// ReputationPath is the file the misbehavior of the sync peers is persisted to,
+	// so that their penalties and bans survive a restart. Empty disables the persistence
+	ReputationPath string
+	// RequestRateLimit is the number of requests per second a peer can send to the sync peer server
+	RequestRateLimit float64
+	// RequestBurst is the number of requests a peer can send at once to the sync peer server
+	RequestBurst uint64
+	// MaxConcurrentStreams is the maximum number of block streams the sync peer server serves at once
+	MaxConcurrentStreams uint64
+	// StreamWriteTimeout is the time a peer has to read each block streamed by the sync peer server,
+	// the peers reading slower are dropped and penalized
+	StreamWriteTimeout time.Duration
+	// Compression is the compression algorithm requested for the block streams
+	Compression proto.Compression
+	// TrustedCheckpoints are the blocks the synced chain must include
+	TrustedCheckpoints []*TrustedCheckpoint
+	// Mode is the way the chain is synced, full by default
+	Mode SyncMode
+	// TrustedPeers are the peers the blocks are downloaded from in priority,
+	// the other peers are used only if none of them is ahead of the local chain
+	TrustedPeers []*peer.AddrInfo
+	// PeerStatusTTL is the time after which the status of a peer not sending any is requested,
+	// the peer being removed from the sync peers if it doesn't respond. Zero disables the check
+	PeerStatusTTL time.Duration
+	// MaxSessionBlocks is the maximum number of blocks written in a bulk sync session. The session
+	// then yields, releasing its memory, and the sync resumes in a new session. Zero is unlimited
+	MaxSessionBlocks uint64
+	// PrefetchCacheSize is the number of blocks prefetched ahead of the local head in the full
+	// and the gateway modes. Zero disables the prefetching
+	PrefetchCacheSize uint64
+	// ArchivePeers are the peers the bodies and the receipts not stored locally are fetched from in priority,
+	// in the light and the gateway modes
+	ArchivePeers []*peer.AddrInfo
+	// RetainedBlocks is the number of recent blocks whose bodies and receipts are kept in the gateway mode
+	RetainedBlocks uint64
+	// VerifyWorkers is the number of workers recovering the transaction senders of the synced blocks
+	// concurrently, ahead of their in-order verification and writing. Zero uses one worker per CPU
+	VerifyWorkers uint64
+	// KeepaliveInterval is the interval between the keepalive pings of the sync peers, the peers
+	// missing consecutive pings are removed as dead. Zero disables the pings
+	KeepaliveInterval time.Duration
+	// KeepaliveTimeout is the time a sync peer has to answer a keepalive ping, the default if zero
+	KeepaliveTimeout time.Duration
+	// HedgeDelay is the time the sync peer has to serve the headers or the bodies near the chain tip
+	// before they are also requested from a second peer, the first response being taken.
+	// Zero disables the hedging
+	HedgeDelay time.Duration
+	// EraSync is whether the complete eras ahead of the local head are synced from the era archives
+	// of the peers serving them, rather than block by block
+	EraSync bool
+	// SyncReceipts is whether the bodies and the receipts of the blocks are synced in the light mode,
+	// so that they are served locally rather than fetched from the peers on demand
+	SyncReceipts bool
+	// Metrics are the syncer metrics, they are discarded if nil
+	Metrics *Metrics
+}
+
 var (
-	errTimeout = errors.New("timeout awaiting block from peer")
+	// ErrPeerNoResponse is returned when a peer doesn't return the requested data
+	ErrPeerNoResponse = errors.New("no response from peer")
 )
 
 // XXX: Don't use this syncer for the consensus that may cause fork.
@@ -35,25 +148,166 @@ type syncer struct {
 	// Timeout for syncing a block
 	blockTimeout time.Duration
 
+	// Adaptive timeouts for the blocks and the bodies
+	timeouts *blockTimeouts
+
+	// Number of headers requested at once
+	batchSize uint64
+
+	// Maximum number of peers the bodies are fetched from concurrently
+	maxPeers uint64
+
+	// Number of workers recovering the transaction senders of the synced blocks
+	verifyWorkers uint64
+
+	// Store of the header-first sync progress
+	checkpoint *checkpointStore
+
+	// Store of the scores of the misbehaving peers
+	reputationStore *reputationStore
+
+	// Blocks the synced chain must include, the peers with other blocks at their heights are rejected
+	trustedCheckpoints trustedCheckpoints
+
+	// Whether the blocks or the headers only are synced
+	mode SyncMode
+
+	// Time after which the status of a silent peer is requested again, disabled if zero
+	peerStatusTTL time.Duration
+
+	// Interval between the keepalive pings, disabled if zero, and time to answer them
+	keepaliveInterval time.Duration
+	keepaliveTimeout  time.Duration
+
+	// Time the sync peer has to serve the blocks near the chain tip before the request is hedged,
+	// disabled if zero
+	hedgeDelay time.Duration
+
+	// Whether the complete eras are synced from the era archives of the peers
+	eraSync bool
+
+	// Whether the light sync writes the blocks with their receipts, rather than the headers only
+	syncReceipts bool
+
+	// Maximum number of blocks written in a bulk sync session, unlimited if zero
+	maxSessionBlocks uint64
+
+	// Blocks prefetched ahead of the local head, nil if the prefetching is disabled
+	blockCache *blockCache
+
+	// Channel to notify the prefetching that it may fetch more blocks
+	prefetchCh chan struct{}
+
+	metrics *Metrics
+
+	// Start time and number of written blocks of the current bulk sync session
+	sessionStart  time.Time
+	sessionBlocks uint64
+
 	// Channel to notify Sync that a new status arrived
 	newStatusCh chan struct{}
+
+	// Context canceled on Close, it propagates to in-flight gRPC streams
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// WaitGroup to wait for the running Sync (and its in-flight block writes) on Close
+	syncWg sync.WaitGroup
+
+	// Context of the bulk sync sessions, derived from ctx. It is canceled on Stop
+	// and replaced on Restart, the lock protects it along with the stopped flag
+	sessionLock   sync.RWMutex
+	sessionCtx    context.Context
+	sessionCancel context.CancelFunc
+	stopped       bool
+
+	// WaitGroup to wait for the running bulk sync session on Stop
+	sessionWg sync.WaitGroup
+
+	// Peer the blocks are being synced from and the cancel of its context,
+	// to abort the sync when the peer is found dead
+	syncPeerLock   sync.Mutex
+	syncPeerID     peer.ID
+	syncPeerCancel context.CancelFunc
 }
 
 func NewSyncer(
 	logger hclog.Logger,
 	network Network,
 	blockchain Blockchain,
-	blockTimeout time.Duration,
+	config *Config,
 ) Syncer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	batchSize := config.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	maxPeers := config.MaxPeers
+	if maxPeers == 0 {
+		maxPeers = DefaultMaxPeers
+	}
+
+	verifyWorkers := config.VerifyWorkers
+	if verifyWorkers == 0 {
+		verifyWorkers = uint64(runtime.NumCPU())
+	}
+
+	keepaliveTimeout := config.KeepaliveTimeout
+	if keepaliveTimeout == 0 {
+		keepaliveTimeout = DefaultKeepaliveTimeout
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+
+	var cache *blockCache
+	if config.PrefetchCacheSize > 0 && config.Mode != ModeLight {
+		cache = newBlockCache(config.PrefetchCacheSize)
+	}
+
+	peerMap := &PeerMap{
+		requiredCapability: config.Mode.requiredCapability(),
+		trusted:            newPeerIDSet(config.TrustedPeers),
+		archive:            newPeerIDSet(config.ArchivePeers),
+	}
+
+	sessionCtx, sessionCancel := context.WithCancel(ctx)
+
 	return &syncer{
-		logger:          logger.Named(syncerName),
-		blockchain:      blockchain,
-		syncProgression: progress.NewProgressionWrapper(progress.ChainSyncBulk),
-		syncPeerService: NewSyncPeerService(network, blockchain),
-		syncPeerClient:  NewSyncPeerClient(logger, network, blockchain),
-		blockTimeout:    blockTimeout,
-		newStatusCh:     make(chan struct{}),
-		peerMap:         new(PeerMap),
+		logger:             logger.Named(syncerName),
+		blockchain:         blockchain,
+		syncProgression:    progress.NewProgressionWrapper(progress.ChainSyncBulk),
+		syncPeerService:    NewSyncPeerService(network, blockchain, config),
+		syncPeerClient:     NewSyncPeerClient(logger, network, blockchain, config.Compression, config.Mode, metrics),
+		blockTimeout:       config.BlockTimeout,
+		timeouts:           newBlockTimeouts(config.BlockTimeout, config.MinBlockTimeout, config.MaxBlockTimeout),
+		batchSize:          batchSize,
+		maxPeers:           maxPeers,
+		verifyWorkers:      verifyWorkers,
+		checkpoint:         &checkpointStore{path: config.CheckpointPath},
+		reputationStore:    &reputationStore{path: config.ReputationPath},
+		trustedCheckpoints: newTrustedCheckpoints(config.TrustedCheckpoints),
+		mode:               config.Mode,
+		peerStatusTTL:      config.PeerStatusTTL,
+		keepaliveInterval:  config.KeepaliveInterval,
+		keepaliveTimeout:   keepaliveTimeout,
+		hedgeDelay:         config.HedgeDelay,
+		eraSync:            config.EraSync,
+		syncReceipts:       config.SyncReceipts,
+		maxSessionBlocks:   config.MaxSessionBlocks,
+		blockCache:         cache,
+		prefetchCh:         make(chan struct{}, 1),
+		metrics:            metrics,
+		newStatusCh:        make(chan struct{}),
+		peerMap:            peerMap,
+		ctx:                ctx,
+		cancel:             cancel,
+		sessionCtx:         sessionCtx,
+		sessionCancel:      sessionCancel,
 	}
 }
 
@@ -65,17 +319,34 @@ func (s *syncer) Start() error {
 
 	s.syncPeerService.Start()
 
+	s.restoreReputation()
 	s.initializePeerMap()
 
 	go s.startPeerStatusUpdateProcess()
 	go s.startPeerConnectionEventProcess()
 
+	if s.blockCache != nil {
+		go s.startPrefetchProcess()
+	}
+
+	if s.peerStatusTTL > 0 {
+		go s.startStalePeerProcess()
+	}
+
+	if s.keepaliveInterval > 0 {
+		go s.startKeepaliveProcess()
+	}
+
 	return nil
 }
 
 // Close terminates goroutine processes
+// It cancels the in-flight sync and waits for the block being written to be finished
 func (s *syncer) Close() error {
-	close(s.newStatusCh)
+	s.cancel()
+	s.syncWg.Wait()
+
+	s.saveReputation()
 
 	if err := s.syncPeerService.Close(); err != nil {
 		return err
@@ -86,10 +357,108 @@ func (s *syncer) Close() error {
 	return nil
 }
 
+// Stop pauses syncing. It cancels the in-flight bulk sync session, including its gRPC streams,
+// and waits for the blocks being written to be finished. The blocks fetched but not written
+// are discarded, the header-first progress is kept in the checkpoint.
+// Sync keeps running and waits for Restart, the peer map is kept up to date meanwhile
+func (s *syncer) Stop() {
+	s.sessionLock.Lock()
+
+	if s.stopped {
+		s.sessionLock.Unlock()
+
+		return
+	}
+
+	s.stopped = true
+	s.sessionCancel()
+
+	s.sessionLock.Unlock()
+
+	s.sessionWg.Wait()
+}
+
+// Restart resumes syncing paused by Stop
+func (s *syncer) Restart() {
+	s.sessionLock.Lock()
+
+	if !s.stopped {
+		s.sessionLock.Unlock()
+
+		return
+	}
+
+	s.sessionCtx, s.sessionCancel = context.WithCancel(s.ctx)
+	s.stopped = false
+
+	s.sessionLock.Unlock()
+
+	// the peers may have advanced meanwhile
+	s.notifyNewStatusEvent()
+}
+
+// beginSession registers a new bulk sync session and returns its context,
+// or false if syncing is stopped
+func (s *syncer) beginSession() (context.Context, bool) {
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	if s.stopped {
+		return nil, false
+	}
+
+	s.sessionWg.Add(1)
+
+	return s.sessionCtx, true
+}
+
+// sessionContext returns the context of the current bulk sync session
+func (s *syncer) sessionContext() context.Context {
+	s.sessionLock.RLock()
+	defer s.sessionLock.RUnlock()
+
+	return s.sessionCtx
+}
+
+// peerContext returns the context of a sync with the peer, derived from the session context.
+// It is canceled by the returned cancel, or when the peer is found dead
+func (s *syncer) peerContext(peerID peer.ID) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(s.sessionContext())
+
+	s.syncPeerLock.Lock()
+	s.syncPeerID, s.syncPeerCancel = peerID, cancel
+	s.syncPeerLock.Unlock()
+
+	return ctx, func() {
+		s.syncPeerLock.Lock()
+		if s.syncPeerID == peerID {
+			s.syncPeerID, s.syncPeerCancel = "", nil
+		}
+		s.syncPeerLock.Unlock()
+
+		cancel()
+	}
+}
+
+// abortPeerSync cancels the in-flight sync with the peer, if any
+func (s *syncer) abortPeerSync(peerID peer.ID) {
+	s.syncPeerLock.Lock()
+	defer s.syncPeerLock.Unlock()
+
+	if s.syncPeerID == peerID && s.syncPeerCancel != nil {
+		s.syncPeerCancel()
+	}
+}
+
 // initializePeerMap fetches peer statuses and initializes map
 func (s *syncer) initializePeerMap() {
 	peerStatuses := s.syncPeerClient.GetConnectedPeerStatuses()
 	s.peerMap.Put(peerStatuses...)
+	s.metrics.Peers.Set(float64(s.peerMap.Len()))
+
+	for _, status := range peerStatuses {
+		s.checkPeerFork(status)
+	}
 }
 
 // startPeerStatusUpdateProcess subscribes peer status change event and updates peer map
@@ -128,12 +497,59 @@ func (s *syncer) initNewPeerStatus(peerID peer.ID) {
 // putToPeerMap puts given status to peer map
 func (s *syncer) putToPeerMap(status *NoForkPeer) {
 	s.peerMap.Put(status)
+	s.metrics.Peers.Set(float64(s.peerMap.Len()))
+	s.checkPeerFork(status)
 	s.notifyNewStatusEvent()
+	s.notifyPrefetch()
+}
+
+// startStalePeerProcess periodically checks the peers which didn't send a status for the status TTL
+func (s *syncer) startStalePeerProcess() {
+	ticker := time.NewTicker(s.peerStatusTTL / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.refreshStalePeers()
+	}
+}
+
+// refreshStalePeers requests the status of the peers which didn't send one for the status TTL.
+// The peers not responding are removed, so that they aren't picked for syncing until they send a status again
+func (s *syncer) refreshStalePeers() {
+	var wg sync.WaitGroup
+
+	for _, peerID := range s.peerMap.StalePeers(time.Now().Add(-s.peerStatusTTL)) {
+		wg.Add(1)
+
+		go func(peerID peer.ID) {
+			defer wg.Done()
+
+			status, err := s.syncPeerClient.GetPeerStatus(peerID)
+			if err != nil {
+				s.logger.Info("removing stale sync peer", "peer ID", peerID, "err", err)
+				s.metrics.StalePeers.Add(1)
+				s.removeFromPeerMap(peerID)
+
+				return
+			}
+
+			s.putToPeerMap(status)
+		}(peerID)
+	}
+
+	wg.Wait()
 }
 
 // removeFromPeerMap removes the peer from peer map
 func (s *syncer) removeFromPeerMap(peerID peer.ID) {
 	s.peerMap.Remove(peerID)
+	s.metrics.Peers.Set(float64(s.peerMap.Len()))
 }
 
 // notifyNewStatusEvent emits signal to newStatusCh
@@ -144,36 +560,178 @@ func (s *syncer) notifyNewStatusEvent() {
 	}
 }
 
+// recordPeerFailure lowers the score of the peer, which may get it banned
+func (s *syncer) recordPeerFailure(peerID peer.ID, failure PeerFailure) {
+	if s.sessionContext().Err() != nil {
+		// the failure is caused by the syncer stopping or closing
+		return
+	}
+
+	if failure == FailureTimeout {
+		s.metrics.StreamTimeouts.Add(1)
+	} else {
+		s.metrics.VerificationFailures.Add(1)
+	}
+
+	if s.peerMap.RecordFailure(peerID, failure) {
+		s.logger.Warn("banned sync peer", "peer ID", peerID, "failure", failure, "duration", peerBanDuration)
+
+		// the ban is persisted right away, so that it survives a crash
+		s.saveReputation()
+	}
+}
+
+// failPeer records the failure against the peer and returns the error as a sync error of the kind of the failure
+func (s *syncer) failPeer(peerID peer.ID, failure PeerFailure, err error) error {
+	s.recordPeerFailure(peerID, failure)
+
+	return s.peerError(failure.kind(), peerID, err)
+}
+
+// requestFailed records a timeout against the peer for the failed request, and returns
+// the error as a sync error, a timeout unless the connection to the peer failed
+func (s *syncer) requestFailed(peerID peer.ID, err error) error {
+	s.recordPeerFailure(peerID, FailureTimeout)
+
+	return s.peerError(requestErrorKind(err), peerID, err)
+}
+
+// peerError returns the error as a sync error of the given kind and keeps it as the last error of the peer
+func (s *syncer) peerError(kind error, peerID peer.ID, err error) error {
+	syncErr := &SyncError{
+		Kind:   kind,
+		PeerID: peerID,
+		Err:    err,
+	}
+
+	if s.sessionContext().Err() == nil {
+		// the error isn't caused by the syncer stopping or closing
+		s.peerMap.RecordError(syncErr)
+	}
+
+	return syncErr
+}
+
+// recordWrittenBlock updates the metrics with a block written in the current bulk sync session
+func (s *syncer) recordWrittenBlock() {
+	s.sessionBlocks++
+	s.metrics.WrittenBlocks.Add(1)
+
+	if elapsed := time.Since(s.sessionStart).Seconds(); elapsed > 0 {
+		s.metrics.BlockWriteRate.Set(float64(s.sessionBlocks) / elapsed)
+	}
+}
+
+// sessionLimitReached returns whether the current bulk sync session wrote the maximum number of blocks
+func (s *syncer) sessionLimitReached() bool {
+	return s.maxSessionBlocks > 0 && s.sessionBlocks >= s.maxSessionBlocks
+}
+
+// yieldSession releases the memory of the bulk sync session which reached the maximum
+// number of blocks, and pauses to let the other processes progress before the next session
+func (s *syncer) yieldSession() {
+	s.metrics.SessionYields.Add(1)
+
+	debug.FreeOSMemory()
+
+	select {
+	case <-s.ctx.Done():
+	case <-time.After(sessionYieldDelay):
+	}
+}
+
+// PeerScores returns the reputation of the sync peers
+func (s *syncer) PeerScores() []*PeerScore {
+	return s.peerMap.Scores()
+}
+
+// SetRequestLimits sets the rate and the burst of the requests each peer can send to the sync peer server
+func (s *syncer) SetRequestLimits(rateLimit float64, burst uint64) {
+	s.syncPeerService.SetRequestLimits(rateLimit, burst)
+}
+
 // GetSyncProgression returns progression
 func (s *syncer) GetSyncProgression() *progress.Progression {
 	return s.syncProgression.GetProgression()
 }
 
+// SubscribeProgress subscribes for the sync progression events, emitted when the sync starts,
+// a batch of blocks is written, the sync peer changes and the sync completes.
+// The returned function cancels the subscription
+func (s *syncer) SubscribeProgress() (<-chan *progress.Progression, func()) {
+	return s.syncProgression.Subscribe()
+}
+
+// updateSyncProgression starts tracking the sync progression if it isn't yet,
+// and sets the peer the node syncs with and its target block
+func (s *syncer) updateSyncProgression(localLatest uint64, syncPeer *NoForkPeer) {
+	if s.syncProgression.GetProgression() == nil {
+		s.syncProgression.StartProgression(localLatest, s.blockchain.SubscribeEvents())
+	}
+
+	s.syncProgression.UpdatePeerProgression(syncPeer.ID.String())
+	s.syncProgression.UpdateHighestProgression(syncPeer.Number)
+}
+
+// stopSyncProgression stops tracking the sync progression, if it is tracked
+func (s *syncer) stopSyncProgression() {
+	if s.syncProgression.GetProgression() != nil {
+		s.syncProgression.StopProgression()
+	}
+}
+
 // HasSyncPeer returns whether syncer has the peer to syncs blocks
 // return false if syncer has no peer whose latest block height doesn't exceed local height
 func (s *syncer) HasSyncPeer() bool {
-	bestPeer := s.peerMap.BestPeer(nil)
 	header := s.blockchain.Header()
+	bestPeer := s.bestPeer(nil, header.Number)
 
 	return bestPeer != nil && bestPeer.Number > header.Number
 }
 
+// bestPeer returns the best trusted peer if one is ahead of the local latest block,
+// or the best peer of the peer map otherwise
+func (s *syncer) bestPeer(skipMap map[peer.ID]bool, localLatest uint64) *NoForkPeer {
+	if trusted := s.peerMap.BestTrustedPeer(skipMap); trusted != nil && trusted.Number > localLatest {
+		return trusted
+	}
+
+	return s.peerMap.BestPeer(skipMap)
+}
+
 // Sync syncs block with the best peer until callback returns true
+// or the syncer is closed
 func (s *syncer) Sync(callback func(*types.Block) bool) error {
+	s.syncWg.Add(1)
+	defer s.syncWg.Done()
+
 	localLatest := s.blockchain.Header().Number
 	skipList := make(map[peer.ID]bool)
 
+	// whether the previous session yielded, the next one starts without waiting for a new status
+	yielded := false
+
+	defer s.stopSyncProgression()
+
 	for {
-		// Wait for a new event to arrive
-		<-s.newStatusCh
+		if !yielded {
+			// Wait for a new event to arrive
+			select {
+			case <-s.ctx.Done():
+				return nil
+			case <-s.newStatusCh:
+			}
+		}
+
+		yielded = false
 
 		// fetch local latest block
 		if header := s.blockchain.Header(); header != nil {
 			localLatest = header.Number
 		}
 
-		// pick one best peer
-		bestPeer := s.peerMap.BestPeer(skipList)
+		// pick one best peer, a trusted one if any is ahead
+		bestPeer := s.bestPeer(skipList, localLatest)
 		if bestPeer == nil {
 			// Empty skipList map if there are no best peers
 			skipList = make(map[peer.ID]bool)
@@ -183,13 +741,53 @@ func (s *syncer) Sync(callback func(*types.Block) bool) error {
 
 		// if the bestPeer does not have a new block continue
 		if bestPeer.Number <= localLatest {
+			// the node caught up with its peers
+			s.metrics.BestPeerDistance.Set(0)
+			s.stopSyncProgression()
+
+			continue
+		}
+
+		s.metrics.BestPeerDistance.Set(float64(bestPeer.Number - localLatest))
+
+		sessionCtx, ok := s.beginSession()
+		if !ok {
+			// syncing is stopped until Restart
 			continue
 		}
 
+		s.updateSyncProgression(localLatest, bestPeer)
+
+		s.sessionStart = time.Now()
+		s.sessionBlocks = 0
+
 		// fetch block from the peer
 		lastNumber, shouldTerminate, err := s.bulkSyncWithPeer(bestPeer.ID, callback)
+
+		s.sessionWg.Done()
+
+		if s.ctx.Err() != nil {
+			// syncer has been closed in the middle of bulk sync
+			return nil
+		}
+
+		if sessionCtx.Err() != nil {
+			// syncer has been stopped in the middle of bulk sync, the peer isn't skipped
+			s.stopSyncProgression()
+
+			continue
+		}
+
 		if err != nil {
-			s.logger.Warn("failed to complete bulk sync with peer, try to next one", "peer ID", "error", bestPeer.ID, err)
+			s.logger.Warn("failed to complete bulk sync with peer, try to next one", "peer ID", bestPeer.ID, "error", err)
+		} else if !shouldTerminate && lastNumber < bestPeer.Number && s.sessionLimitReached() {
+			// the session wrote the maximum number of blocks, the peer isn't skipped
+			s.logger.Debug("bulk sync session yields", "blocks", s.sessionBlocks, "latest", lastNumber)
+			s.yieldSession()
+
+			yielded = true
+
+			continue
 		}
 
 		if lastNumber < bestPeer.Number {
@@ -208,13 +806,500 @@ func (s *syncer) Sync(callback func(*types.Block) bool) error {
 }
 
 // bulkSyncWithPeer syncs block with a given peer
+// If the peer is on another branch, the local chain is unwound to the fork point
+// and the branch of the peer is synced. The peer is quarantined if the fork point isn't found
 func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.Block) bool) (uint64, bool, error) {
+	lastNumber, shouldTerminate, err := s.syncWithPeer(peerID, newBlockCallback)
+	if !errors.Is(err, errDivergentFork) {
+		return lastNumber, shouldTerminate, err
+	}
+
+	if rewindErr := s.rewindToCommonAncestor(peerID); rewindErr != nil {
+		s.logger.Warn("unable to rewind to the common ancestor with the peer", "peer ID", peerID, "error", rewindErr)
+		s.quarantinePeer(peerID, s.blockchain.Header().Number+1)
+
+		return lastNumber, shouldTerminate, err
+	}
+
+	return s.syncWithPeer(peerID, newBlockCallback)
+}
+
+// syncWithPeer syncs block with a given peer
+// The prefetched blocks are written first, then the complete eras of the peer are synced from its era archives.
+// The following blocks are synced with the header-first pipeline, unless the peer only serves the block stream.
+// Only the headers are synced in the light mode
+func (s *syncer) syncWithPeer(peerID peer.ID, newBlockCallback func(*types.Block) bool) (uint64, bool, error) {
+	if s.mode == ModeLight {
+		lastNumber, err := s.lightSyncWithPeer(peerID)
+
+		return lastNumber, false, err
+	}
+
+	writtenNumber, shouldTerminate, err := s.writePrefetchedBlocks(peerID, newBlockCallback)
+	if err != nil || shouldTerminate || s.sessionLimitReached() {
+		return writtenNumber, shouldTerminate, err
+	}
+
+	syncPeer := s.peerMap.Get(peerID)
+	if syncPeer != nil && writtenNumber > 0 && writtenNumber >= syncPeer.Number {
+		// the prefetched blocks caught up with the peer
+		return writtenNumber, false, nil
+	}
+
+	if s.eraSync && syncPeer != nil && syncPeer.Supports(proto.Capability_ERA_ARCHIVES) {
+		var eraNumber uint64
+
+		eraNumber, shouldTerminate, err = s.eraSyncWithPeer(syncPeer, newBlockCallback)
+		if eraNumber > writtenNumber {
+			writtenNumber = eraNumber
+		}
+
+		if err != nil || shouldTerminate || s.sessionLimitReached() {
+			return writtenNumber, shouldTerminate, err
+		}
+	}
+
+	lastNumber, shouldTerminate, err := s.syncBlocksWithPeer(syncPeer, peerID, newBlockCallback)
+	if lastNumber < writtenNumber {
+		lastNumber = writtenNumber
+	}
+
+	return lastNumber, shouldTerminate, err
+}
+
+// syncBlocksWithPeer syncs block with a given peer over the header-first pipeline,
+// or the block stream if the peer doesn't serve the headers
+func (s *syncer) syncBlocksWithPeer(
+	syncPeer *NoForkPeer,
+	peerID peer.ID,
+	newBlockCallback func(*types.Block) bool,
+) (uint64, bool, error) {
+	if syncPeer != nil && !syncPeer.Supports(proto.Capability_HEADERS) {
+		// the peer advertises it doesn't serve the headers
+		return s.streamSyncWithPeer(peerID, newBlockCallback)
+	}
+
+	lastNumber, shouldTerminate, err := s.headerFirstSyncWithPeer(peerID, newBlockCallback)
+	if status.Code(err) != codes.Unimplemented {
+		return lastNumber, shouldTerminate, err
+	}
+
+	s.logger.Debug("peer doesn't serve headers, fall back to block stream", "peer ID", peerID)
+
+	return s.streamSyncWithPeer(peerID, newBlockCallback)
+}
+
+// headerFirstSyncWithPeer syncs block with a given peer by fetching the headers in batches,
+// validating their hash chain and then fetching the bodies. The next batch of headers
+// is fetched while the bodies of the current one are fetched and written.
+// The queued blocks are persisted as a checkpoint, and the sync resumes from it after a restart
+func (s *syncer) headerFirstSyncWithPeer(
+	peerID peer.ID,
+	newBlockCallback func(*types.Block) bool,
+) (uint64, bool, error) {
+	// the context is canceled when bulk sync ends, the syncer is stopped or closed,
+	// or the peer is found dead, which stops the header fetching
+	ctx, cancel := s.peerContext(peerID)
+	defer cancel()
+
+	localHeader := s.blockchain.Header()
+	queue := newSyncQueue(localHeader)
+
+	// the blocks up to restoredNumber come from the checkpoint, not from the peer
+	restoredNumber := s.restoreCheckpoint(queue)
+	resumed := restoredNumber > localHeader.Number
+	headerCh, headerErrCh := s.fetchHeaders(ctx, peerID, queue.last.Number+1)
+
+	var (
+		lastReceivedNumber uint64
+		shouldTerminate    bool
+
+		// peers that failed to serve bodies in this session
+		helperSkipList = map[peer.ID]bool{peerID: true}
+
+		// peers other than the sync peer that served headers to hedged requests, by block number
+		headerSources = map[uint64]peer.ID{}
+	)
+
+	for {
+		for queue.len() > 0 {
+			if err := s.fetchQueuedBodies(ctx, peerID, queue, helperSkipList); err != nil {
+				return lastReceivedNumber, shouldTerminate, err
+			}
+
+			blocks := queue.popBlocks()
+
+			// the senders are recovered concurrently, the blocks are verified and written in order
+			for _, preverified := range s.preverifyBlocks(ctx, blocks) {
+				block := preverified.block
+
+				err := preverified.wait()
+				if ctx.Err() != nil {
+					return lastReceivedNumber, shouldTerminate, ctx.Err()
+				}
+
+				if err == nil {
+					err = s.blockchain.VerifyFinalizedBlock(block)
+				}
+
+				if err != nil {
+					verifyErr := fmt.Errorf("unable to verify block, %w", err)
+
+					if block.Number() <= restoredNumber {
+						// the block doesn't come from the peer
+						s.discardCheckpoint()
+
+						return lastReceivedNumber, false, verifyErr
+					}
+
+					sourceID := peerID
+					if id, ok := headerSources[block.Number()]; ok {
+						sourceID = id
+					}
+
+					return lastReceivedNumber, false, s.failPeer(sourceID, verificationFailure(err), verifyErr)
+				}
+
+				if err := s.blockchain.WriteFinalizedBlock(ctx, block, syncerName); err != nil {
+					return lastReceivedNumber, false, fmt.Errorf("failed to write block while bulk syncing: %w", err)
+				}
+
+				s.recordWrittenBlock()
+				s.timeouts.observe(block.Size())
+
+				if block.Number() > restoredNumber {
+					s.peerMap.RecordSuccess(peerID)
+				}
+
+				shouldTerminate = newBlockCallback(block)
+
+				lastReceivedNumber = block.Number()
+			}
+
+			s.saveCheckpoint(peerID, queue)
+
+			if len(blocks) > 0 {
+				s.syncProgression.CompleteBatchProgression(lastReceivedNumber)
+			}
+
+			if s.sessionLimitReached() {
+				// the session yields, the next one resumes from the checkpoint
+				return lastReceivedNumber, shouldTerminate, nil
+			}
+		}
+
+		var (
+			batch *headerBatch
+			ok    bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return lastReceivedNumber, shouldTerminate, ctx.Err()
+		case batch, ok = <-headerCh:
+		}
+
+		if !ok {
+			// the error is sent before the channel is closed
+			select {
+			case err := <-headerErrCh:
+				if status.Code(err) != codes.Unimplemented {
+					err = s.requestFailed(peerID, err)
+				}
+
+				return lastReceivedNumber, shouldTerminate, err
+			default:
+				return lastReceivedNumber, shouldTerminate, nil
+			}
+		}
+
+		headers, sourceID := batch.headers, batch.peerID
+
+		if err := s.trustedCheckpoints.verifyHeaders(headers); err != nil {
+			// the peer is on another chain than the trusted one
+			s.quarantinePeer(sourceID, headers[len(headers)-1].Number)
+
+			return lastReceivedNumber, shouldTerminate, s.peerError(ErrHashMismatch, sourceID, err)
+		}
+
+		if err := queue.addHeaders(headers); err != nil {
+			if resumed && queue.last.Number == restoredNumber {
+				// the checkpoint may be on another chain than the peer,
+				// the next session starts over from the local head
+				s.discardCheckpoint()
+
+				return lastReceivedNumber, shouldTerminate, fmt.Errorf("headers don't extend the sync checkpoint, %w", err)
+			}
+
+			if sourceID == peerID && queue.last.Hash == localHeader.Hash && headers[0].ParentHash != localHeader.Hash {
+				// the first header doesn't follow the local head, the peer is on another fork
+				return lastReceivedNumber, shouldTerminate,
+					s.peerError(ErrHashMismatch, peerID, fmt.Errorf("%w, %v", errDivergentFork, err))
+			}
+
+			return lastReceivedNumber, shouldTerminate, s.failPeer(sourceID, FailureHashMismatch, err)
+		}
+
+		if sourceID != peerID {
+			for _, header := range headers {
+				headerSources[header.Number] = sourceID
+			}
+		}
+
+		s.saveCheckpoint(peerID, queue)
+	}
+}
+
+// headerBatch is a batch of consecutive headers along with the peer that served it
+type headerBatch struct {
+	peerID  peer.ID
+	headers []*types.Header
+}
+
+// fetchHeaders fetches the headers of the peer in batches from the given height,
+// until the peer has no more headers. The headers near the tip of the peer are requested
+// from a second peer as well if the peer is late. The channel is closed when fetching stops,
+// and the error is sent to the error channel beforehand if fetching failed
+func (s *syncer) fetchHeaders(
+	ctx context.Context,
+	peerID peer.ID,
+	from uint64,
+) (<-chan *headerBatch, <-chan error) {
+	headerCh := make(chan *headerBatch, maxPendingHeaderBatches)
+	errCh := make(chan error, 1)
+
+	request := func(ctx context.Context, id peer.ID) (interface{}, error) {
+		reqCtx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+		defer cancel()
+
+		headers, err := s.syncPeerClient.GetHeaders(reqCtx, id, from, s.batchSize)
+		if err == nil && len(headers) == 0 && id != peerID {
+			// only the sync peer having no more headers stops the fetching
+			return nil, fmt.Errorf("%w: no headers returned", ErrPeerNoResponse)
+		}
+
+		return headers, err
+	}
+
+	go func() {
+		defer close(headerCh)
+
+		for {
+			sourceID, value, err := s.hedgedRequest(ctx, peerID, s.hedgePeer(peerID, from, nil), request)
+			if err != nil {
+				errCh <- err
+
+				return
+			}
+
+			headers, _ := value.([]*types.Header)
+			if len(headers) == 0 {
+				return
+			}
+
+			select {
+			case headerCh <- &headerBatch{peerID: sourceID, headers: headers}:
+			case <-ctx.Done():
+				return
+			}
+
+			from += uint64(len(headers))
+		}
+	}()
+
+	return headerCh, errCh
+}
+
+// bodyBatchSize returns the number of bodies requested at once
+func (s *syncer) bodyBatchSize() int {
+	size := s.batchSize / bodyBatchDivisor
+
+	switch {
+	case size == 0:
+		return 1
+	case size > maxBodiesPerRequest:
+		return maxBodiesPerRequest
+	default:
+		return int(size)
+	}
+}
+
+// fetchQueuedBodies fetches the pending bodies of the queue once. The batches are spread
+// over the sync peer and up to maxPeers-1 helper peers having the blocks, and fetched concurrently.
+// A failure of the sync peer is returned, while a failing helper is added to the skip list
+// and its batch stays pending
+func (s *syncer) fetchQueuedBodies(
+	ctx context.Context,
+	peerID peer.ID,
+	queue *syncQueue,
+	helperSkipList map[peer.ID]bool,
+) error {
+	batchSize := s.bodyBatchSize()
+	peerIDs := []peer.ID{peerID}
+
+	if s.maxPeers > 1 {
+		for _, helper := range s.peerMap.PeersWithBlock(queue.last.Number, int(s.maxPeers-1), helperSkipList) {
+			peerIDs = append(peerIDs, helper.ID)
+		}
+	}
+
+	hashes := queue.pendingBodies(batchSize * len(peerIDs))
+
+	type bodiesResult struct {
+		peerID peer.ID
+		hashes []types.Hash
+		bodies []*types.Body
+		err    error
+	}
+
+	results := make([]bodiesResult, 0, len(peerIDs))
+
+	for i := 0; i < len(peerIDs) && i*batchSize < len(hashes); i++ {
+		end := (i + 1) * batchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		results = append(results, bodiesResult{
+			peerID: peerIDs[i],
+			hashes: hashes[i*batchSize : end],
+		})
+	}
+
+	var wg sync.WaitGroup
+
+	// the batch of the sync peer is requested from a peer not fetching another batch as well,
+	// if the blocks are near the tip of the sync peer and it is late
+	fetching := make(map[peer.ID]bool, len(peerIDs))
+	for _, id := range peerIDs {
+		fetching[id] = true
+	}
+
+	hedge := s.hedgePeer(peerID, queue.last.Number, fetching)
+
+	for i := range results {
+		wg.Add(1)
+
+		go func(res *bodiesResult) {
+			defer wg.Done()
+
+			if res.peerID != peerID {
+				res.bodies, res.err = s.fetchBodies(ctx, res.peerID, res.hashes)
+
+				return
+			}
+
+			var value interface{}
+
+			res.peerID, value, res.err = s.hedgedRequest(ctx, peerID, hedge,
+				func(ctx context.Context, id peer.ID) (interface{}, error) {
+					return s.fetchBodies(ctx, id, res.hashes)
+				},
+			)
+
+			res.bodies, _ = value.([]*types.Body)
+		}(&results[i])
+	}
+
+	wg.Wait()
+
+	for _, res := range results {
+		err := res.err
+
+		if err != nil {
+			err = s.requestFailed(res.peerID, err)
+		} else if err = queue.deliverBodies(res.hashes, res.bodies); err != nil {
+			err = s.failPeer(res.peerID, FailureHashMismatch, fmt.Errorf("invalid bodies, %w", err))
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if res.peerID == peerID {
+			return err
+		}
+
+		s.logger.Debug("failed to fetch bodies from helper peer", "peer ID", res.peerID, "error", err)
+
+		helperSkipList[res.peerID] = true
+	}
+
+	return nil
+}
+
+// fetchBodies fetches the bodies of the given blocks from the peer
+func (s *syncer) fetchBodies(ctx context.Context, peerID peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, s.adaptiveTimeout(peerID, len(hashes), nil))
+	defer cancel()
+
+	bodies, err := s.syncPeerClient.GetBodies(reqCtx, peerID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bodies) == 0 {
+		return nil, fmt.Errorf("%w: no bodies returned", ErrPeerNoResponse)
+	}
+
+	return bodies, nil
+}
+
+// streamMeter measures a block stream: the time to its first block, and the rate of the
+// following blocks over the time spent waiting for them, so excluding the time spent importing them
+type streamMeter struct {
+	start   time.Time
+	rtt     time.Duration
+	bytes   uint64
+	waiting time.Duration
+}
+
+func newStreamMeter() *streamMeter {
+	return &streamMeter{
+		start: time.Now(),
+	}
+}
+
+// received accounts a block received after waiting for the given duration
+func (m *streamMeter) received(block *types.Block, waited time.Duration) {
+	if m.rtt == 0 {
+		m.rtt = time.Since(m.start)
+
+		return
+	}
+
+	m.bytes += block.Size()
+	m.waiting += waited
+}
+
+// record folds the measurements into the estimates of the peer, if a block was received
+func (m *streamMeter) record(peerMap *PeerMap, peerID peer.ID) {
+	if m.rtt == 0 {
+		return
+	}
+
+	throughput := float64(0)
+	if m.waiting > 0 {
+		throughput = float64(m.bytes) / m.waiting.Seconds()
+	}
+
+	peerMap.RecordStream(peerID, m.rtt, throughput)
+}
+
+// streamSyncWithPeer syncs block with a given peer over a single stream of full blocks
+func (s *syncer) streamSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.Block) bool) (uint64, bool, error) {
 	localLatest := s.blockchain.Header().Number
 	shouldTerminate := false
 
-	blockCh, err := s.syncPeerClient.GetBlocks(peerID, localLatest+1, s.blockTimeout)
+	// the context is canceled when bulk sync ends, the syncer is stopped or closed,
+	// or the peer is found dead, which closes the gRPC stream and the goroutines reading from it
+	ctx, cancel := s.peerContext(peerID)
+	defer cancel()
+
+	// the adaptive timeout is checked here, the stream only closes past its ceiling
+	blockCh, err := s.syncPeerClient.GetBlocks(ctx, peerID, localLatest+1, s.timeouts.ceiling)
 	if err != nil {
-		return 0, false, err
+		return 0, false, s.requestFailed(peerID, err)
 	}
 
 	defer func() {
@@ -224,33 +1309,65 @@ func (s *syncer) bulkSyncWithPeer(peerID peer.ID, newBlockCallback func(*types.B
 		}
 	}()
 
+	meter := newStreamMeter()
+	defer meter.record(s.peerMap, peerID)
+
 	var lastReceivedNumber uint64
 
 	for {
+		waitStart := time.Now()
+
 		select {
+		case <-ctx.Done():
+			return lastReceivedNumber, shouldTerminate, ctx.Err()
 		case block, ok := <-blockCh:
 			if !ok {
 				return lastReceivedNumber, shouldTerminate, nil
 			}
 
+			meter.received(block, time.Since(waitStart))
+			s.timeouts.observe(block.Size())
+
 			// safe check
 			if block.Number() == 0 {
 				continue
 			}
 
+			if err := s.trustedCheckpoints.verifyHeader(block.Header); err != nil {
+				// the peer is on another chain than the trusted one
+				s.quarantinePeer(peerID, block.Number())
+
+				return lastReceivedNumber, false, s.peerError(ErrHashMismatch, peerID, err)
+			}
+
 			if err := s.blockchain.VerifyFinalizedBlock(block); err != nil {
-				return lastReceivedNumber, false, fmt.Errorf("unable to verify block, %w", err)
+				if lastReceivedNumber == 0 && isForkError(err) {
+					// the first block doesn't follow the local head, the peer is on another fork
+					return lastReceivedNumber, false,
+						s.peerError(ErrHashMismatch, peerID, fmt.Errorf("%w, unable to verify block: %v", errDivergentFork, err))
+				}
+
+				return lastReceivedNumber, false,
+					s.failPeer(peerID, verificationFailure(err), fmt.Errorf("unable to verify block, %w", err))
 			}
 
-			if err := s.blockchain.WriteBlock(block, syncerName); err != nil {
+			if err := s.blockchain.WriteFinalizedBlock(ctx, block, syncerName); err != nil {
 				return lastReceivedNumber, false, fmt.Errorf("failed to write block while bulk syncing: %w", err)
 			}
 
+			s.recordWrittenBlock()
+
+			s.peerMap.RecordSuccess(peerID)
+
 			shouldTerminate = newBlockCallback(block)
 
 			lastReceivedNumber = block.Number()
-		case <-time.After(s.blockTimeout):
-			return lastReceivedNumber, shouldTerminate, errTimeout
+
+			if s.sessionLimitReached() {
+				return lastReceivedNumber, shouldTerminate, nil
+			}
+		case <-time.After(s.adaptiveTimeout(peerID, 1, meter)):
+			return lastReceivedNumber, shouldTerminate, s.failPeer(peerID, FailureTimeout, ErrTimeout)
 		}
 	}
 }
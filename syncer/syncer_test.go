@@ -1,10 +1,13 @@
 package syncer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,39 +15,77 @@ import (
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network/event"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type mockProgression struct {
 	startingBlock uint64
 	highestBlock  uint64
+	peers         []string
+	batches       []uint64
+	progression   *progress.Progression
 }
 
 func (m *mockProgression) StartProgression(startingBlock uint64, subscription blockchain.Subscription) {
 	m.startingBlock = startingBlock
+	m.progression = &progress.Progression{
+		StartingBlock: startingBlock,
+	}
 }
 
 func (m *mockProgression) UpdateHighestProgression(highestBlock uint64) {
 	m.highestBlock = highestBlock
 }
 
+func (m *mockProgression) UpdatePeerProgression(peer string) {
+	m.peers = append(m.peers, peer)
+}
+
+func (m *mockProgression) CompleteBatchProgression(currentBlock uint64) {
+	m.batches = append(m.batches, currentBlock)
+}
+
+func (m *mockProgression) Subscribe() (<-chan *progress.Progression, func()) {
+	return nil, func() {}
+}
+
 func (m *mockProgression) GetProgression() *progress.Progression {
-	// Syncer doesn't use this method. It just exports
-	return nil
+	return m.progression
 }
 
 type mockBlockchain struct {
-	subscription                blockchain.Subscription
-	headerHandler               func() *types.Header
-	getBlockByNumberHandler     func(uint64, bool) (*types.Block, bool)
-	verifyFinalizedBlockHandler func(*types.Block) error
-	writeBlockHandler           func(*types.Block) error
+	bus                          *blockchain.EventBus
+	headerHandler                func() *types.Header
+	getBlockByNumberHandler      func(uint64, bool) (*types.Block, bool)
+	getHeaderByNumberHandler     func(uint64) (*types.Header, bool)
+	getBodyByHashHandler         func(types.Hash) (*types.Body, bool)
+	getTDHandler                 func(types.Hash) (*big.Int, bool)
+	getReceiptsByHashHandler     func(types.Hash) ([]*types.Receipt, error)
+	recoverSendersHandler        func(*types.Block) error
+	verifyFinalizedBlockHandler  func(*types.Block) error
+	writeBlockHandler            func(*types.Block) error
+	verifyFinalizedHeaderHandler func(*types.Header) error
+	writeFinalizedHeaderHandler  func(*types.Header) error
+	writeBlockReceiptsHandler    func(*types.Block, []*types.Receipt) error
+	rewindToHandler              func(uint64) error
 }
 
 func (m *mockBlockchain) SubscribeEvents() blockchain.Subscription {
-	return m.subscription
+	return blockchain.NewMockSubscription()
+}
+
+// SubscribeBus subscribes to the bus of the mock, no event is received if it has none
+func (m *mockBlockchain) SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription {
+	if m.bus == nil {
+		return blockchain.NewEventBus(hclog.NewNullLogger()).Subscribe(buffer, topics...)
+	}
+
+	return m.bus.Subscribe(buffer, topics...)
 }
 
 func (m *mockBlockchain) Header() *types.Header {
@@ -55,14 +96,60 @@ func (m *mockBlockchain) GetBlockByNumber(number uint64, full bool) (*types.Bloc
 	return m.getBlockByNumberHandler(number, full)
 }
 
+func (m *mockBlockchain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	return m.getHeaderByNumberHandler(number)
+}
+
+func (m *mockBlockchain) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
+	return m.getBodyByHashHandler(hash)
+}
+
+// GetTD behaves like a chain without total difficulties if no handler is set
+func (m *mockBlockchain) GetTD(hash types.Hash) (*big.Int, bool) {
+	if m.getTDHandler == nil {
+		return nil, false
+	}
+
+	return m.getTDHandler(hash)
+}
+
+func (m *mockBlockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.getReceiptsByHashHandler(hash)
+}
+
+// RecoverSenders succeeds if no handler is set
+func (m *mockBlockchain) RecoverSenders(b *types.Block) error {
+	if m.recoverSendersHandler == nil {
+		return nil
+	}
+
+	return m.recoverSendersHandler(b)
+}
+
 func (m *mockBlockchain) VerifyFinalizedBlock(b *types.Block) error {
 	return m.verifyFinalizedBlockHandler(b)
 }
 
-func (m *mockBlockchain) WriteBlock(b *types.Block, s string) error {
+func (m *mockBlockchain) WriteFinalizedBlock(ctx context.Context, b *types.Block, s string) error {
 	return m.writeBlockHandler(b)
 }
 
+func (m *mockBlockchain) VerifyFinalizedHeader(h *types.Header) error {
+	return m.verifyFinalizedHeaderHandler(h)
+}
+
+func (m *mockBlockchain) WriteFinalizedHeader(h *types.Header, s string) error {
+	return m.writeFinalizedHeaderHandler(h)
+}
+
+func (m *mockBlockchain) WriteFinalizedBlockWithReceipts(b *types.Block, r []*types.Receipt, s string) error {
+	return m.writeBlockReceiptsHandler(b, r)
+}
+
+func (m *mockBlockchain) RewindTo(number uint64, s string) error {
+	return m.rewindToHandler(number)
+}
+
 func newSimpleHeaderHandler(num uint64) func() *types.Header {
 	return func() *types.Header {
 		return &types.Header{
@@ -79,12 +166,21 @@ func (m *mockSyncPeerService) Close() error {
 	return nil
 }
 
-func (m *mockProgression) StopProgression() {}
+func (m *mockSyncPeerService) SetRequestLimits(float64, uint64) {}
+
+func (m *mockProgression) StopProgression() {
+	m.progression = nil
+}
 
 type mockSyncPeerClient struct {
 	getPeerStatusHandler                  func(peer.ID) (*NoForkPeer, error)
 	getConnectedPeerStatusesHandler       func() []*NoForkPeer
-	getBlocksHandler                      func(peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	getBlocksHandler                      func(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	getHeadersHandler                     func(context.Context, peer.ID, uint64, uint64) ([]*types.Header, error)
+	getBodiesHandler                      func(context.Context, peer.ID, []types.Hash) ([]*types.Body, error)
+	getReceiptsHandler                    func(context.Context, peer.ID, []types.Hash) ([][]*types.Receipt, error)
+	getEraHandler                         func(context.Context, peer.ID, uint64) ([]*types.Block, error)
+	pingHandler                           func(context.Context, peer.ID) error
 	getPeerStatusUpdateChHandler          func() <-chan *NoForkPeer
 	getPeerConnectionUpdateEventChHandler func() <-chan *event.PeerEvent
 }
@@ -108,11 +204,64 @@ func (m *mockSyncPeerClient) GetConnectedPeerStatuses() []*NoForkPeer {
 }
 
 func (m *mockSyncPeerClient) GetBlocks(
+	ctx context.Context,
 	id peer.ID,
 	start uint64,
 	timeoutPerBlock time.Duration,
 ) (<-chan *types.Block, error) {
-	return m.getBlocksHandler(id, start, timeoutPerBlock)
+	return m.getBlocksHandler(ctx, id, start, timeoutPerBlock)
+}
+
+// GetHeaders behaves like a peer that only serves the block stream if no handler is set
+func (m *mockSyncPeerClient) GetHeaders(
+	ctx context.Context,
+	id peer.ID,
+	from uint64,
+	amount uint64,
+) ([]*types.Header, error) {
+	if m.getHeadersHandler == nil {
+		return nil, status.Error(codes.Unimplemented, "method GetHeaders not implemented")
+	}
+
+	return m.getHeadersHandler(ctx, id, from, amount)
+}
+
+func (m *mockSyncPeerClient) GetBodies(
+	ctx context.Context,
+	id peer.ID,
+	hashes []types.Hash,
+) ([]*types.Body, error) {
+	return m.getBodiesHandler(ctx, id, hashes)
+}
+
+func (m *mockSyncPeerClient) GetReceipts(
+	ctx context.Context,
+	id peer.ID,
+	hashes []types.Hash,
+) ([][]*types.Receipt, error) {
+	return m.getReceiptsHandler(ctx, id, hashes)
+}
+
+// GetEra answers as a peer not serving the era archives if no handler is set
+func (m *mockSyncPeerClient) GetEra(
+	ctx context.Context,
+	id peer.ID,
+	era uint64,
+	timeoutPerChunk time.Duration,
+) ([]*types.Block, error) {
+	if m.getEraHandler == nil {
+		return nil, status.Error(codes.Unimplemented, "method GetEra not implemented")
+	}
+
+	return m.getEraHandler(ctx, id, era)
+}
+
+func (m *mockSyncPeerClient) Ping(ctx context.Context, id peer.ID) error {
+	if m.pingHandler == nil {
+		return nil
+	}
+
+	return m.pingHandler(ctx, id)
 }
 
 func (m *mockSyncPeerClient) GetPeerStatusUpdateCh() <-chan *NoForkPeer {
@@ -159,6 +308,9 @@ func NewTestSyncer(
 	mockSyncPeerClient *mockSyncPeerClient,
 	mockProgression Progression,
 ) *syncer {
+	ctx, cancel := context.WithCancel(context.Background())
+	sessionCtx, sessionCancel := context.WithCancel(ctx)
+
 	return &syncer{
 		logger:          hclog.NewNullLogger(),
 		blockchain:      blockchain,
@@ -166,8 +318,18 @@ func NewTestSyncer(
 		syncPeerService: &mockSyncPeerService{},
 		syncPeerClient:  mockSyncPeerClient,
 		blockTimeout:    blockTimeout,
+		timeouts:        newBlockTimeouts(blockTimeout, 0, 0),
+		batchSize:       DefaultBatchSize,
+		maxPeers:        DefaultMaxPeers,
+		checkpoint:      &checkpointStore{},
+		reputationStore: &reputationStore{},
+		metrics:         NilMetrics(),
 		newStatusCh:     make(chan struct{}),
 		peerMap:         new(PeerMap),
+		ctx:             ctx,
+		cancel:          cancel,
+		sessionCtx:      sessionCtx,
+		sessionCancel:   sessionCancel,
 	}
 }
 
@@ -538,10 +700,9 @@ func TestSync(t *testing.T) {
 					return nil
 				}
 			},
-			blocks: blocks[:10],
-			// TODO: need to fix implementation?
+			blocks:             blocks[:10],
 			progressionStart:   0,
-			progressionHighest: 0,
+			progressionHighest: 10,
 			err:                nil,
 		},
 		{
@@ -584,10 +745,9 @@ func TestSync(t *testing.T) {
 					return nil
 				}
 			},
-			blocks: blocks[:10],
-			// TODO: need to fix implementation?
+			blocks:             blocks[:10],
 			progressionStart:   0,
-			progressionHighest: 0,
+			progressionHighest: 10,
 			err:                nil,
 		},
 	}
@@ -617,7 +777,7 @@ func TestSync(t *testing.T) {
 					},
 					time.Second,
 					&mockSyncPeerClient{
-						getBlocksHandler: func(i peer.ID, u uint64, _ time.Duration) (<-chan *types.Block, error) {
+						getBlocksHandler: func(_ context.Context, i peer.ID, u uint64, _ time.Duration) (<-chan *types.Block, error) {
 							// should not panic
 							peerCh := test.peerBlocksCh[i]
 
@@ -649,11 +809,177 @@ func TestSync(t *testing.T) {
 			assert.Equal(t, test.blocks, syncedBlocks)
 			assert.Equal(t, test.progressionStart, progression.startingBlock)
 			assert.Equal(t, test.progressionHighest, progression.highestBlock)
+			assert.Nil(t, progression.GetProgression())
 			assert.ErrorIs(t, err, test.err)
 		})
 	}
 }
 
+func TestSync_Progression(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 30)
+
+	var (
+		latestHead  = head
+		progression = &mockProgression{}
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return latestHead
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				latestHead = b.Header
+
+				return nil
+			},
+		},
+		time.Second,
+		newHeaderFirstSyncPeerClient(blocks),
+		progression,
+	)
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   30,
+		Distance: big.NewInt(0),
+	})
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- syncer.Sync(func(b *types.Block) bool {
+			return b.Number() >= 30
+		})
+	}()
+
+	syncer.newStatusCh <- struct{}{}
+
+	assert.NoError(t, <-errCh)
+
+	assert.Equal(t, uint64(30), progression.highestBlock)
+	assert.Equal(t, []string{peer.ID("A").String()}, progression.peers)
+
+	// a batch event is reported for every written batch
+	assert.NotEmpty(t, progression.batches)
+	assert.Equal(t, uint64(30), progression.batches[len(progression.batches)-1])
+	assert.IsIncreasing(t, progression.batches)
+
+	// the progression is stopped once the sync completes
+	assert.Nil(t, progression.GetProgression())
+}
+
+func TestSyncer_refreshStalePeers(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{},
+		time.Second,
+		&mockSyncPeerClient{
+			getPeerStatusHandler: func(id peer.ID) (*NoForkPeer, error) {
+				if id == peer.ID("B") {
+					return nil, errors.New("no response")
+				}
+
+				return &NoForkPeer{ID: id, Number: 15, Distance: big.NewInt(0)}, nil
+			},
+		},
+		&mockProgression{},
+	)
+	syncer.peerStatusTTL = time.Minute
+
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(0)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 20, Distance: big.NewInt(0)},
+	)
+
+	// nothing is requested while the statuses are recent
+	syncer.refreshStalePeers()
+	assert.Equal(t, 2, syncer.peerMap.Len())
+
+	for _, id := range []peer.ID{"A", "B"} {
+		syncer.peerMap.setStatusTime(id, time.Now().Add(-2*time.Minute))
+	}
+
+	// the responding peer is updated, the other one is removed
+	syncer.refreshStalePeers()
+
+	assert.Equal(t, 1, syncer.peerMap.Len())
+	assert.Equal(t, uint64(15), syncer.peerMap.Get(peer.ID("A")).Number)
+	assert.Nil(t, syncer.peerMap.Get(peer.ID("B")))
+	assert.Empty(t, syncer.peerMap.StalePeers(time.Now().Add(-time.Minute)))
+}
+
+func TestSync_MaxSessionBlocks(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 30)
+
+	var (
+		syncer       *syncer
+		latestHead   = head
+		sessionSizes []uint64
+	)
+
+	syncer = NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return latestHead
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				if syncer.sessionBlocks == 0 {
+					sessionSizes = append(sessionSizes, 0)
+				}
+
+				sessionSizes[len(sessionSizes)-1]++
+				latestHead = b.Header
+
+				return nil
+			},
+		},
+		time.Second,
+		newHeaderFirstSyncPeerClient(blocks),
+		&mockProgression{},
+	)
+	syncer.batchSize = 4
+	syncer.maxSessionBlocks = 8
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   30,
+		Distance: big.NewInt(0),
+	})
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- syncer.Sync(func(b *types.Block) bool {
+			return b.Number() >= 30
+		})
+	}()
+
+	syncer.newStatusCh <- struct{}{}
+
+	assert.NoError(t, <-errCh)
+
+	// the sessions yield after the maximum number of blocks and the sync resumes without a new status
+	assert.Equal(t, uint64(30), latestHead.Number)
+	assert.Equal(t, []uint64{8, 8, 8, 6}, sessionSizes)
+}
+
 func Test_bulkSyncWithPeer(t *testing.T) {
 	t.Parallel()
 
@@ -684,7 +1010,12 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 		blockCallback   func(*types.Block) bool
 
 		// peers
-		getBlocksHandler func(id peer.ID, start uint64, timeoutPerBlock time.Duration) (<-chan *types.Block, error)
+		getBlocksHandler func(
+			ctx context.Context,
+			id peer.ID,
+			start uint64,
+			timeoutPerBlock time.Duration,
+		) (<-chan *types.Block, error)
 
 		// handlers
 		verifyFinalizedBlockHandler func(*types.Block) error
@@ -703,7 +1034,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blockCallback: func(b *types.Block) bool {
 				return false
 			},
-			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+			getBlocksHandler: func(_ context.Context, id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
 				return blocksToCh(blocks[:10], 0), nil
 			},
 			verifyFinalizedBlockHandler: func(b *types.Block) error {
@@ -724,7 +1055,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blockCallback: func(b *types.Block) bool {
 				return false
 			},
-			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+			getBlocksHandler: func(_ context.Context, id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
 				return nil, errPeerNoResponse
 			},
 			verifyFinalizedBlockHandler: func(b *types.Block) error {
@@ -745,7 +1076,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blockCallback: func(b *types.Block) bool {
 				return false
 			},
-			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+			getBlocksHandler: func(_ context.Context, id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
 				return blocksToCh(blocks[:10], 0), nil
 			},
 			verifyFinalizedBlockHandler: func(b *types.Block) error {
@@ -770,7 +1101,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blockCallback: func(b *types.Block) bool {
 				return false
 			},
-			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+			getBlocksHandler: func(_ context.Context, id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
 				return blocksToCh(blocks[:10], 0), nil
 			},
 			verifyFinalizedBlockHandler: func(b *types.Block) error {
@@ -795,7 +1126,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blockCallback: func(b *types.Block) bool {
 				return false
 			},
-			getBlocksHandler: func(id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
+			getBlocksHandler: func(_ context.Context, id peer.ID, start uint64, _ time.Duration) (<-chan *types.Block, error) {
 				return blocksToCh(blocks[:10], time.Second*1), nil
 			},
 			verifyFinalizedBlockHandler: func(b *types.Block) error {
@@ -807,7 +1138,7 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 			blocks:                []*types.Block{},
 			lastSyncedBlockNumber: 0,
 			shouldTerminate:       false,
-			err:                   errTimeout,
+			err:                   ErrTimeout,
 		},
 	}
 
@@ -852,3 +1183,772 @@ func Test_bulkSyncWithPeer(t *testing.T) {
 		})
 	}
 }
+
+func Test_bulkSyncWithPeer_StreamEstimates(t *testing.T) {
+	t.Parallel()
+
+	blocks := createMockBlocks(5)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(0),
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getBlocksHandler: func(_ context.Context, _ peer.ID, _ uint64, _ time.Duration) (<-chan *types.Block, error) {
+				return blocksToCh(blocks, 10*time.Millisecond), nil
+			},
+		},
+		&mockProgression{},
+	)
+
+	syncer.peerMap.Put(&NoForkPeer{ID: peer.ID("X"), Number: 5, Distance: big.NewInt(1)})
+
+	lastSynced, _, err := syncer.bulkSyncWithPeer(peer.ID("X"), func(*types.Block) bool { return false })
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), lastSynced)
+
+	measured := syncer.peerMap.Get(peer.ID("X"))
+	assert.GreaterOrEqual(t, measured.RTT, 10*time.Millisecond)
+	assert.Greater(t, measured.Throughput, float64(0))
+}
+
+// ctxBlocksToCh emits blocks from the given height until the context is canceled
+func ctxBlocksToCh(ctx context.Context, from uint64, canceledCh chan<- struct{}) <-chan *types.Block {
+	ch := make(chan *types.Block)
+
+	go func() {
+		defer close(ch)
+		defer close(canceledCh)
+
+		for i := from; ; i++ {
+			select {
+			case ch <- &types.Block{Header: &types.Header{Number: i}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+func TestSync_CloseInTheMiddleOfBulkSync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		writtenLock   sync.Mutex
+		writtenBlocks uint64
+		latest        uint64
+
+		// closed by the mock stream when the context is canceled
+		streamCanceledCh = make(chan struct{})
+		// closed once some blocks have been written
+		syncingCh   = make(chan struct{})
+		syncingOnce sync.Once
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				writtenLock.Lock()
+				defer writtenLock.Unlock()
+
+				return &types.Header{Number: latest}
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				writtenLock.Lock()
+				defer writtenLock.Unlock()
+
+				writtenBlocks++
+				latest = b.Number()
+
+				if writtenBlocks >= 5 {
+					syncingOnce.Do(func() { close(syncingCh) })
+				}
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getBlocksHandler: func(
+				ctx context.Context,
+				_ peer.ID,
+				from uint64,
+				_ time.Duration,
+			) (<-chan *types.Block, error) {
+				return ctxBlocksToCh(ctx, from, streamCanceledCh), nil
+			},
+		},
+		&mockProgression{},
+	)
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   math.MaxUint64,
+		Distance: big.NewInt(0),
+	})
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- syncer.Sync(func(*types.Block) bool {
+			return false
+		})
+	}()
+
+	syncer.newStatusCh <- struct{}{}
+
+	select {
+	case <-syncingCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("syncer didn't start writing blocks")
+	}
+
+	assert.NoError(t, syncer.Close())
+
+	// Close must wait for Sync to finish, no more blocks are written after it
+	writtenLock.Lock()
+	writtenOnClose := writtenBlocks
+	writtenLock.Unlock()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync didn't return after Close")
+	}
+
+	select {
+	case <-streamCanceledCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream context wasn't canceled")
+	}
+
+	writtenLock.Lock()
+	defer writtenLock.Unlock()
+
+	assert.Equal(t, writtenOnClose, writtenBlocks)
+}
+
+func TestSync_StopAndRestart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		writtenLock   sync.Mutex
+		writtenBlocks uint64
+		latest        uint64
+
+		// channels closed by the mock streams when their context is canceled
+		streamsCh = make(chan chan struct{}, 2)
+		// receives once some blocks have been written since the last reset
+		syncingCh = make(chan struct{}, 1)
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				writtenLock.Lock()
+				defer writtenLock.Unlock()
+
+				return &types.Header{Number: latest}
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				writtenLock.Lock()
+				defer writtenLock.Unlock()
+
+				writtenBlocks++
+				latest = b.Number()
+
+				if writtenBlocks%5 == 0 {
+					select {
+					case syncingCh <- struct{}{}:
+					default:
+					}
+				}
+
+				return nil
+			},
+		},
+		time.Second,
+		&mockSyncPeerClient{
+			getBlocksHandler: func(
+				ctx context.Context,
+				_ peer.ID,
+				from uint64,
+				_ time.Duration,
+			) (<-chan *types.Block, error) {
+				canceledCh := make(chan struct{})
+				streamsCh <- canceledCh
+
+				return ctxBlocksToCh(ctx, from, canceledCh), nil
+			},
+		},
+		&mockProgression{},
+	)
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   math.MaxUint64,
+		Distance: big.NewInt(0),
+	})
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- syncer.Sync(func(*types.Block) bool {
+			return false
+		})
+	}()
+
+	waitForSyncing := func() {
+		t.Helper()
+
+		select {
+		case <-syncingCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("syncer didn't write blocks")
+		}
+	}
+
+	waitForStreamCanceled := func(canceledCh chan struct{}) {
+		t.Helper()
+
+		select {
+		case <-canceledCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream context wasn't canceled")
+		}
+	}
+
+	syncer.newStatusCh <- struct{}{}
+
+	waitForSyncing()
+
+	// Stop must cancel the stream and wait for the block writes to finish
+	syncer.Stop()
+	waitForStreamCanceled(<-streamsCh)
+
+	writtenLock.Lock()
+	writtenOnStop := writtenBlocks
+	writtenLock.Unlock()
+
+	// Sync keeps running while stopped, without writing blocks
+	syncer.notifyNewStatusEvent()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("Sync returned after Stop: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	writtenLock.Lock()
+	assert.Equal(t, writtenOnStop, writtenBlocks)
+	writtenLock.Unlock()
+
+	// drain a notification sent before Stop
+	select {
+	case <-syncingCh:
+	default:
+	}
+
+	// the sync resumes from the local head after Restart
+	syncer.Restart()
+
+	waitForSyncing()
+
+	assert.NoError(t, syncer.Close())
+	waitForStreamCanceled(<-streamsCh)
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync didn't return after Close")
+	}
+
+	writtenLock.Lock()
+	defer writtenLock.Unlock()
+
+	assert.Greater(t, writtenBlocks, writtenOnStop)
+}
+
+func TestSync_CloseWhileWaitingForStatus(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: newSimpleHeaderHandler(0),
+		},
+		time.Second,
+		&mockSyncPeerClient{},
+		&mockProgression{},
+	)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- syncer.Sync(func(*types.Block) bool {
+			return false
+		})
+	}()
+
+	assert.NoError(t, syncer.Close())
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync didn't return after Close")
+	}
+
+	// notifying after Close must not panic
+	syncer.notifyNewStatusEvent()
+}
+
+// createMockChain creates the blocks following the given head, with a valid hash chain
+func createMockChain(head *types.Header, num int) []*types.Block {
+	blocks := make([]*types.Block, num)
+	parent := head
+
+	for i := 0; i < num; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash,
+			Number:     parent.Number + 1,
+			Sha3Uncles: types.EmptyUncleHash,
+			TxRoot:     types.EmptyRootHash,
+		}
+
+		blocks[i] = &types.Block{
+			Header: header.ComputeHash(),
+		}
+		// the size is cached as on the blocks the syncer measures
+		blocks[i].Size()
+
+		parent = header
+	}
+
+	return blocks
+}
+
+// createMockChainWithReceipts creates blocks of one transaction each, whose headers commit to their receipts
+func createMockChainWithReceipts(head *types.Header, num int) ([]*types.Block, map[types.Hash][]*types.Receipt) {
+	blocks := make([]*types.Block, num)
+	receipts := make(map[types.Hash][]*types.Receipt, num)
+	parent := head
+
+	for i := 0; i < num; i++ {
+		txn := (&types.Transaction{Nonce: uint64(i), GasPrice: big.NewInt(1), Value: big.NewInt(1)}).ComputeHash()
+		gasUsed := uint64(21000 + i)
+		receipt := &types.Receipt{CumulativeGasUsed: gasUsed, GasUsed: gasUsed, TxHash: txn.Hash}
+		receipt.SetStatus(types.ReceiptSuccess)
+
+		txs := []*types.Transaction{txn}
+
+		header := &types.Header{
+			ParentHash:   parent.Hash,
+			Number:       parent.Number + 1,
+			Sha3Uncles:   types.EmptyUncleHash,
+			TxRoot:       buildroot.CalculateTransactionsRoot(txs),
+			ReceiptsRoot: buildroot.CalculateReceiptsRoot([]*types.Receipt{receipt}),
+		}
+
+		blocks[i] = &types.Block{
+			Header:       header.ComputeHash(),
+			Transactions: txs,
+		}
+		receipts[header.Hash] = []*types.Receipt{receipt}
+
+		parent = header
+	}
+
+	return blocks, receipts
+}
+
+// newHeaderFirstSyncPeerClient returns a client which serves the headers and bodies of the given blocks
+func newHeaderFirstSyncPeerClient(blocks []*types.Block) *mockSyncPeerClient {
+	return &mockSyncPeerClient{
+		getHeadersHandler: func(_ context.Context, _ peer.ID, from, amount uint64) ([]*types.Header, error) {
+			headers := []*types.Header{}
+
+			for _, block := range blocks {
+				if block.Number() >= from && uint64(len(headers)) < amount {
+					headers = append(headers, block.Header)
+				}
+			}
+
+			return headers, nil
+		},
+		getBodiesHandler: func(_ context.Context, _ peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+			bodies := []*types.Body{}
+
+			for _, hash := range hashes {
+				for _, block := range blocks {
+					if block.Hash() == hash {
+						bodies = append(bodies, block.Body())
+					}
+				}
+			}
+
+			return bodies, nil
+		},
+	}
+}
+
+func Test_bulkSyncWithPeer_HeaderFirst(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 2*int(DefaultBatchSize)+10)
+
+	tamperedBlocks := createMockChain(head, 10)
+	tamperedBlocks[5] = &types.Block{
+		Header: &types.Header{
+			Number:     6,
+			ParentHash: types.BytesToHash([]byte("wrong")),
+		},
+	}
+	tamperedBlocks[5].Header.ComputeHash()
+
+	tests := []struct {
+		name            string
+		blocks          []*types.Block
+		expectedWritten uint64
+		expectedFailure *PeerFailure
+		err             error
+	}{
+		{
+			name:            "should write all blocks of the peer",
+			blocks:          blocks,
+			expectedWritten: uint64(len(blocks)),
+		},
+		{
+			name:            "should stop at broken hash chain",
+			blocks:          tamperedBlocks,
+			expectedWritten: 0,
+			expectedFailure: failurePtr(FailureHashMismatch),
+			err:             ErrMismatchedParent,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				written    []*types.Block
+				latestHead = head
+			)
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						written = append(written, b)
+						latestHead = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				newHeaderFirstSyncPeerClient(test.blocks),
+				&mockProgression{},
+			)
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.expectedWritten, lastNumber)
+			assert.Len(t, written, int(test.expectedWritten))
+
+			for i, block := range written {
+				assert.Equal(t, test.blocks[i].Hash(), block.Hash())
+			}
+
+			scores := syncer.PeerScores()
+
+			if test.expectedFailure == nil {
+				assert.Equal(t, uint64(0), scores[0].Failures[FailureHashMismatch])
+			} else {
+				assert.Equal(t, uint64(1), scores[0].Failures[*test.expectedFailure])
+			}
+		})
+	}
+}
+
+func Test_bulkSyncWithPeer_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 3)
+
+	client := newHeaderFirstSyncPeerClient(blocks)
+	client.getBodiesHandler = func(_ context.Context, _ peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+		bodies := make([]*types.Body, len(hashes))
+		for i := range bodies {
+			// the header commits to no transactions
+			bodies[i] = &types.Body{
+				Transactions: []*types.Transaction{{Nonce: 1, Value: big.NewInt(0), GasPrice: big.NewInt(0)}},
+			}
+		}
+
+		return bodies, nil
+	}
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Second,
+		client,
+		&mockProgression{},
+	)
+
+	lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.ErrorIs(t, err, errBodyTxRootMismatch)
+	assert.ErrorIs(t, err, ErrHashMismatch)
+	assert.Equal(t, uint64(0), lastNumber)
+	assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureHashMismatch])
+	assert.ErrorIs(t, syncer.PeerScores()[0].LastError, errBodyTxRootMismatch)
+}
+
+func Test_bulkSyncWithPeer_NoBodies(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+
+	client := newHeaderFirstSyncPeerClient(createMockChain(head, 3))
+	client.getBodiesHandler = func(context.Context, peer.ID, []types.Hash) ([]*types.Body, error) {
+		return nil, nil
+	}
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return head
+			},
+		},
+		time.Second,
+		client,
+		&mockProgression{},
+	)
+
+	lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.ErrorIs(t, err, ErrPeerNoResponse)
+	assert.Equal(t, uint64(0), lastNumber)
+	assert.Equal(t, uint64(1), syncer.PeerScores()[0].Failures[FailureTimeout])
+}
+
+func Test_bulkSyncWithPeer_Metrics(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 3)
+
+	tamperedBlocks := createMockChain(head, 3)
+	tamperedBlocks[1] = &types.Block{
+		Header: &types.Header{
+			Number:     2,
+			ParentHash: types.BytesToHash([]byte("wrong")),
+		},
+	}
+	tamperedBlocks[1].Header.ComputeHash()
+
+	tests := []struct {
+		name                         string
+		client                       *mockSyncPeerClient
+		expectedWrittenBlocks        float64
+		expectedVerificationFailures float64
+		expectedStreamTimeouts       float64
+	}{
+		{
+			name:                  "should count written blocks",
+			client:                newHeaderFirstSyncPeerClient(blocks),
+			expectedWrittenBlocks: 3,
+		},
+		{
+			name:                         "should count verification failures",
+			client:                       newHeaderFirstSyncPeerClient(tamperedBlocks),
+			expectedVerificationFailures: 1,
+		},
+		{
+			name: "should count stream timeouts",
+			client: func() *mockSyncPeerClient {
+				client := newHeaderFirstSyncPeerClient(blocks)
+				client.getBodiesHandler = func(context.Context, peer.ID, []types.Hash) ([]*types.Body, error) {
+					return nil, nil
+				}
+
+				return client
+			}(),
+			expectedStreamTimeouts: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			latestHead := head
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						latestHead = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				test.client,
+				&mockProgression{},
+			)
+
+			var (
+				writtenBlocks        = &mockCounter{}
+				verificationFailures = &mockCounter{}
+				streamTimeouts       = &mockCounter{}
+			)
+
+			syncer.metrics.WrittenBlocks = writtenBlocks
+			syncer.metrics.VerificationFailures = verificationFailures
+			syncer.metrics.StreamTimeouts = streamTimeouts
+
+			_, _, _ = syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			assert.Equal(t, test.expectedWrittenBlocks, writtenBlocks.Value())
+			assert.Equal(t, test.expectedVerificationFailures, verificationFailures.Value())
+			assert.Equal(t, test.expectedStreamTimeouts, streamTimeouts.Value())
+		})
+	}
+}
+
+func failurePtr(f PeerFailure) *PeerFailure {
+	return &f
+}
+
+func Test_bulkSyncWithPeer_ConcurrentBodies(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 50)
+
+	var (
+		servedLock sync.Mutex
+		served     = map[peer.ID]int{}
+	)
+
+	client := newHeaderFirstSyncPeerClient(blocks)
+	getBodies := client.getBodiesHandler
+	client.getBodiesHandler = func(ctx context.Context, id peer.ID, hashes []types.Hash) ([]*types.Body, error) {
+		if id == peer.ID("C") {
+			return nil, errors.New("unavailable")
+		}
+
+		servedLock.Lock()
+		served[id] += len(hashes)
+		servedLock.Unlock()
+
+		return getBodies(ctx, id, hashes)
+	}
+
+	var (
+		written    []*types.Block
+		latestHead = head
+	)
+
+	syncer := NewTestSyncer(
+		nil,
+		&mockBlockchain{
+			headerHandler: func() *types.Header {
+				return latestHead
+			},
+			verifyFinalizedBlockHandler: func(b *types.Block) error {
+				return nil
+			},
+			writeBlockHandler: func(b *types.Block) error {
+				written = append(written, b)
+				latestHead = b.Header
+
+				return nil
+			},
+		},
+		time.Second,
+		client,
+		&mockProgression{},
+	)
+
+	syncer.batchSize = 16
+	syncer.maxPeers = 3
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 50, Distance: big.NewInt(1)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 50, Distance: big.NewInt(2)},
+		&NoForkPeer{ID: peer.ID("C"), Number: 50, Distance: big.NewInt(3)},
+	)
+
+	lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50), lastNumber)
+	assert.Len(t, written, 50)
+
+	for i, block := range written {
+		assert.Equal(t, blocks[i].Hash(), block.Hash())
+	}
+
+	// the helper served bodies, while the failing helper was penalized
+	assert.Greater(t, served[peer.ID("B")], 0)
+	assert.Equal(t, 50, served[peer.ID("A")]+served[peer.ID("B")])
+	assert.False(t, syncer.peerMap.IsBanned(peer.ID("C")))
+	assert.Equal(t, uint64(1), syncer.PeerScores()[1].Failures[FailureTimeout])
+}
@@ -0,0 +1,332 @@
+package testutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	ErrBlockNotFound = errors.New("block not found")
+	ErrNotNextBlock  = errors.New("block is not the next block of the chain")
+)
+
+// subscriptionBufferSize is the number of events a subscription holds before they are dropped
+const subscriptionBufferSize = 64
+
+// MockBlockchain is an in-memory chain implementing the syncer blockchain. It keeps the blocks
+// written, and emits their events to its subscriptions and its bus. Each block has a difficulty of one
+type MockBlockchain struct {
+	lock sync.RWMutex
+
+	blocks   []*types.Block
+	receipts map[types.Hash][]*types.Receipt
+
+	subscriptions map[*mockSubscription]struct{}
+	bus           *blockchain.EventBus
+
+	// VerifyBlockFn verifies the finalized blocks and headers, all are valid if nil
+	VerifyBlockFn func(*types.Block) error
+}
+
+// NewMockBlockchain creates a chain with the given blocks, the first one being the genesis
+func NewMockBlockchain(blocks []*types.Block) *MockBlockchain {
+	return &MockBlockchain{
+		blocks:        append([]*types.Block{}, blocks...),
+		receipts:      make(map[types.Hash][]*types.Receipt),
+		subscriptions: make(map[*mockSubscription]struct{}),
+		bus:           blockchain.NewEventBus(hclog.NewNullLogger()),
+	}
+}
+
+// NewTestBlocks creates a chain of the given number of empty blocks, starting with the genesis
+func NewTestBlocks(count int) []*types.Block {
+	blocks := make([]*types.Block, count)
+	parentHash := types.ZeroHash
+
+	for i := range blocks {
+		header := &types.Header{
+			ParentHash:   parentHash,
+			Number:       uint64(i),
+			Difficulty:   1,
+			Sha3Uncles:   types.EmptyUncleHash,
+			TxRoot:       types.EmptyRootHash,
+			ReceiptsRoot: types.EmptyRootHash,
+			ExtraData:    []byte{},
+		}
+		header.ComputeHash()
+
+		blocks[i] = &types.Block{Header: header}
+		parentHash = header.Hash
+	}
+
+	return blocks
+}
+
+// SetReceipts sets the receipts of the block with the given hash
+func (m *MockBlockchain) SetReceipts(hash types.Hash, receipts []*types.Receipt) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.receipts[hash] = receipts
+}
+
+// Blocks returns the blocks of the chain
+func (m *MockBlockchain) Blocks() []*types.Block {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*types.Block{}, m.blocks...)
+}
+
+// SubscribeEvents subscribes to the events of the blocks written from now on
+func (m *MockBlockchain) SubscribeEvents() blockchain.Subscription {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	sub := &mockSubscription{
+		chain:   m,
+		eventCh: make(chan *blockchain.Event, subscriptionBufferSize),
+		closeCh: make(chan struct{}),
+	}
+
+	m.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+// SubscribeBus subscribes to the new heads of the blocks written from now on, the other topics have no events
+func (m *MockBlockchain) SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription {
+	return m.bus.Subscribe(buffer, topics...)
+}
+
+// Header returns the header of the head, nil if the chain is empty
+func (m *MockBlockchain) Header() *types.Header {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if len(m.blocks) == 0 {
+		return nil
+	}
+
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+// GetBlockByNumber returns the block with the given number
+func (m *MockBlockchain) GetBlockByNumber(number uint64, full bool) (*types.Block, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if number >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	block := m.blocks[number]
+	if !full {
+		return &types.Block{Header: block.Header}, true
+	}
+
+	return block, true
+}
+
+// GetHeaderByNumber returns the header of the block with the given number
+func (m *MockBlockchain) GetHeaderByNumber(number uint64) (*types.Header, bool) {
+	block, ok := m.GetBlockByNumber(number, false)
+	if !ok {
+		return nil, false
+	}
+
+	return block.Header, true
+}
+
+// GetBodyByHash returns the body of the block with the given hash
+func (m *MockBlockchain) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	block := m.blockByHash(hash)
+	if block == nil {
+		return nil, false
+	}
+
+	return block.Body(), true
+}
+
+// GetTD returns the total difficulty of the block with the given hash
+func (m *MockBlockchain) GetTD(hash types.Hash) (*big.Int, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	block := m.blockByHash(hash)
+	if block == nil {
+		return nil, false
+	}
+
+	return new(big.Int).SetUint64(block.Number() + 1), true
+}
+
+// GetReceiptsByHash returns the receipts of the block with the given hash, empty if not set
+func (m *MockBlockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.blockByHash(hash) == nil {
+		return nil, ErrBlockNotFound
+	}
+
+	if receipts, ok := m.receipts[hash]; ok {
+		return receipts, nil
+	}
+
+	return []*types.Receipt{}, nil
+}
+
+// RecoverSenders does nothing, the senders are left as set
+func (m *MockBlockchain) RecoverSenders(*types.Block) error {
+	return nil
+}
+
+// VerifyFinalizedBlock verifies the block with VerifyBlockFn
+func (m *MockBlockchain) VerifyFinalizedBlock(block *types.Block) error {
+	if m.VerifyBlockFn == nil {
+		return nil
+	}
+
+	return m.VerifyBlockFn(block)
+}
+
+// WriteBlock appends the block to the chain, it has to be the next block of the head
+func (m *MockBlockchain) WriteBlock(block *types.Block, _ string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(m.blocks) > 0 {
+		head := m.blocks[len(m.blocks)-1].Header
+		if block.Number() != head.Number+1 || block.ParentHash() != head.Hash {
+			return fmt.Errorf("%w: block %d on top of %d", ErrNotNextBlock, block.Number(), head.Number)
+		}
+	}
+
+	m.blocks = append(m.blocks, block)
+
+	evnt := &blockchain.Event{Type: blockchain.EventHead}
+	evnt.AddNewHeader(block.Header)
+	evnt.SetDifficulty(new(big.Int).SetUint64(block.Number() + 1))
+
+	for sub := range m.subscriptions {
+		sub.push(evnt)
+	}
+
+	m.bus.Publish(&blockchain.BusEvent{
+		Topic: blockchain.TopicNewHeads,
+		Head: &blockchain.HeadEvent{
+			Header:     block.Header,
+			Difficulty: evnt.Difficulty,
+		},
+	})
+
+	return nil
+}
+
+// WriteFinalizedBlock appends the block to the chain, it has to be the next block of the head
+func (m *MockBlockchain) WriteFinalizedBlock(_ context.Context, block *types.Block, source string) error {
+	return m.WriteBlock(block, source)
+}
+
+// VerifyFinalizedHeader verifies the block of the header with VerifyBlockFn
+func (m *MockBlockchain) VerifyFinalizedHeader(header *types.Header) error {
+	return m.VerifyFinalizedBlock(&types.Block{Header: header})
+}
+
+// WriteFinalizedHeader appends the block of the header, without body, to the chain
+func (m *MockBlockchain) WriteFinalizedHeader(header *types.Header, source string) error {
+	return m.WriteBlock(&types.Block{Header: header}, source)
+}
+
+// WriteFinalizedBlockWithReceipts appends the block to the chain and sets its receipts
+func (m *MockBlockchain) WriteFinalizedBlockWithReceipts(
+	block *types.Block,
+	receipts []*types.Receipt,
+	source string,
+) error {
+	if err := m.WriteBlock(block, source); err != nil {
+		return err
+	}
+
+	m.SetReceipts(block.Hash(), receipts)
+
+	return nil
+}
+
+// RewindTo drops the blocks above the given number
+func (m *MockBlockchain) RewindTo(number uint64, _ string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if number >= uint64(len(m.blocks)) {
+		return fmt.Errorf("%w: %d", ErrBlockNotFound, number)
+	}
+
+	m.blocks = m.blocks[:number+1]
+
+	return nil
+}
+
+func (m *MockBlockchain) blockByHash(hash types.Hash) *types.Block {
+	for _, block := range m.blocks {
+		if block.Hash() == hash {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// mockSubscription is a subscription to the events of the mock blockchain
+type mockSubscription struct {
+	chain   *MockBlockchain
+	eventCh chan *blockchain.Event
+	closeCh chan struct{}
+}
+
+// push sends the event to the subscription, it is dropped if the buffer is full
+func (s *mockSubscription) push(evnt *blockchain.Event) {
+	select {
+	case s.eventCh <- evnt:
+	default:
+	}
+}
+
+// GetEventCh returns the channel of the events
+func (s *mockSubscription) GetEventCh() chan *blockchain.Event {
+	return s.eventCh
+}
+
+// GetEvent returns the next event, or nil once the subscription is closed
+func (s *mockSubscription) GetEvent() *blockchain.Event {
+	select {
+	case evnt := <-s.eventCh:
+		return evnt
+	case <-s.closeCh:
+		return nil
+	}
+}
+
+// Close closes the subscription
+func (s *mockSubscription) Close() {
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	if _, ok := s.chain.subscriptions[s]; !ok {
+		return
+	}
+
+	delete(s.chain.subscriptions, s)
+	close(s.closeCh)
+}
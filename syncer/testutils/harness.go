@@ -0,0 +1,114 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/syncer"
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Peer is a node of the harness, a libp2p server on the loopback interface
+// running the sync peer service and client on top of its chain
+type Peer struct {
+	Network    *network.Server
+	Blockchain *MockBlockchain
+	Service    syncer.SyncPeerService
+	Client     syncer.SyncPeerClient
+}
+
+// PeerConfig is the configuration of a harness peer
+type PeerConfig struct {
+	// Mode is the sync mode of the peer, which sets the capabilities it advertises
+	Mode syncer.SyncMode
+	// Compression is the compression requested by the client for the block streams
+	Compression proto.Compression
+	// Service is the configuration of the sync peer service, its mode is set to Mode
+	Service syncer.Config
+}
+
+// NewPeer starts a harness peer with the given chain and the default configuration.
+// It is closed with the test
+func NewPeer(t *testing.T, chain *MockBlockchain) *Peer {
+	t.Helper()
+
+	return NewPeerWithConfig(t, chain, &PeerConfig{})
+}
+
+// NewPeerWithConfig starts a harness peer with the given chain and configuration.
+// It is closed with the test
+func NewPeerWithConfig(t *testing.T, chain *MockBlockchain, config *PeerConfig) *Peer {
+	t.Helper()
+
+	srv, err := network.CreateServer(&network.CreateServerParams{
+		ConfigCallback: func(c *network.Config) {
+			c.NoDiscover = true
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create the network server: %v", err)
+	}
+
+	serviceConfig := config.Service
+	serviceConfig.Mode = config.Mode
+
+	p := &Peer{
+		Network:    srv,
+		Blockchain: chain,
+		Service:    syncer.NewSyncPeerService(srv, chain, &serviceConfig),
+		Client: syncer.NewSyncPeerClient(
+			hclog.NewNullLogger(),
+			srv,
+			chain,
+			config.Compression,
+			config.Mode,
+			syncer.NilMetrics(),
+		),
+	}
+
+	p.Service.Start()
+
+	if err := p.Client.Start(); err != nil {
+		_ = p.Service.Close()
+		_ = srv.Close()
+
+		t.Fatalf("failed to start the sync peer client: %v", err)
+	}
+
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// ID returns the libp2p ID of the peer
+func (p *Peer) ID() peer.ID {
+	return p.Network.AddrInfo().ID
+}
+
+// Close stops the client, the service and the network server of the peer
+func (p *Peer) Close() {
+	p.Client.Close()
+
+	_ = p.Service.Close()
+	_ = p.Network.Close()
+}
+
+// Connect connects the given peers to each other, and waits for the connections
+func Connect(t *testing.T, peers ...*Peer) {
+	t.Helper()
+
+	for i, source := range peers {
+		for _, destination := range peers[i+1:] {
+			if err := network.JoinAndWait(
+				source.Network,
+				destination.Network,
+				network.DefaultBufferTimeout,
+				network.DefaultJoinTimeout,
+			); err != nil {
+				t.Fatalf("failed to connect %s to %s: %v", source.ID(), destination.ID(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package testutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarness_SyncPeerProtocol(t *testing.T) {
+	t.Parallel()
+
+	blocks := NewTestBlocks(10)
+
+	local := NewPeer(t, NewMockBlockchain(blocks[:1]))
+	remote := NewPeer(t, NewMockBlockchain(blocks))
+
+	Connect(t, local, remote)
+
+	status, err := local.Client.GetPeerStatus(remote.ID())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), status.Number)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	headers, err := local.Client.GetHeaders(ctx, remote.ID(), 1, 5)
+	assert.NoError(t, err)
+	assert.Len(t, headers, 5)
+
+	for i, header := range headers {
+		assert.Equal(t, blocks[i+1].Hash(), header.Hash)
+	}
+
+	blockCh, err := local.Client.GetBlocks(ctx, remote.ID(), 1, 5*time.Second)
+	assert.NoError(t, err)
+
+	// the streamed blocks are written to the local chain
+	for block := range blockCh {
+		assert.NoError(t, local.Blockchain.WriteBlock(block, "test"))
+
+		if block.Number() == 9 {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, blocks[9].Hash(), local.Blockchain.Header().Hash)
+
+	bodies, err := local.Client.GetBodies(context.Background(), remote.ID(), []types.Hash{blocks[3].Hash()})
+	assert.NoError(t, err)
+	assert.Len(t, bodies, 1)
+}
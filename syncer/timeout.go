@@ -0,0 +1,101 @@
+package syncer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultMaxBlockTimeout is the default ceiling of the adaptive block timeout
+	DefaultMaxBlockTimeout = 2 * time.Minute
+	// adaptiveTimeoutFactor is the margin of the adaptive block timeout over the expected time to receive the blocks
+	adaptiveTimeoutFactor = 4
+)
+
+// blockTimeouts computes the time to wait for blocks from a peer. The deadline scales with the
+// moving average of the received block sizes over the throughput of the peer, within a floor
+// and a ceiling, so that the peers serving large blocks aren't taken for timing out.
+// The base timeout is used until the block sizes and the throughput of the peer are measured
+type blockTimeouts struct {
+	base    time.Duration
+	floor   time.Duration
+	ceiling time.Duration
+
+	lock    sync.Mutex
+	avgSize float64
+}
+
+// newBlockTimeouts returns the block timeouts, the floor being the base timeout if zero
+// and the ceiling the default one if zero, at least the floor
+func newBlockTimeouts(base, floor, ceiling time.Duration) *blockTimeouts {
+	if floor == 0 {
+		floor = base
+	}
+
+	if ceiling == 0 {
+		ceiling = DefaultMaxBlockTimeout
+	}
+
+	if ceiling < floor {
+		ceiling = floor
+	}
+
+	return &blockTimeouts{
+		base:    base,
+		floor:   floor,
+		ceiling: ceiling,
+	}
+}
+
+// observe folds the size of a received block into the moving average
+func (t *blockTimeouts) observe(size uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.avgSize = rollingAverage(t.avgSize, float64(size))
+}
+
+// get returns the time to wait for the given number of blocks from a peer with the given RTT and throughput
+func (t *blockTimeouts) get(blocks int, rtt time.Duration, throughput float64) time.Duration {
+	t.lock.Lock()
+	avgSize := t.avgSize
+	t.lock.Unlock()
+
+	timeout := t.base
+
+	if avgSize > 0 && throughput > 0 {
+		expected := rtt + time.Duration(float64(blocks)*avgSize/throughput*float64(time.Second))
+		timeout = adaptiveTimeoutFactor * expected
+	}
+
+	if timeout < t.floor {
+		return t.floor
+	}
+
+	if timeout > t.ceiling {
+		return t.ceiling
+	}
+
+	return timeout
+}
+
+// adaptiveTimeout returns the time to wait for the given number of blocks from the peer.
+// The measurements of the running stream are preferred to the estimates of the peer, if any
+func (s *syncer) adaptiveTimeout(peerID peer.ID, blocks int, meter *streamMeter) time.Duration {
+	var (
+		rtt        time.Duration
+		throughput float64
+	)
+
+	if p := s.peerMap.Get(peerID); p != nil {
+		rtt, throughput = p.RTT, p.Throughput
+	}
+
+	if meter != nil && meter.waiting > 0 {
+		rtt, throughput = meter.rtt, float64(meter.bytes)/meter.waiting.Seconds()
+	}
+
+	return s.timeouts.get(blocks, rtt, throughput)
+}
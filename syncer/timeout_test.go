@@ -0,0 +1,68 @@
+package syncer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newBlockTimeouts(t *testing.T) {
+	t.Parallel()
+
+	timeouts := newBlockTimeouts(5*time.Second, 0, 0)
+	assert.Equal(t, 5*time.Second, timeouts.floor)
+	assert.Equal(t, DefaultMaxBlockTimeout, timeouts.ceiling)
+
+	// the ceiling is at least the floor
+	timeouts = newBlockTimeouts(5*time.Second, 10*time.Second, time.Second)
+	assert.Equal(t, 10*time.Second, timeouts.ceiling)
+}
+
+func TestBlockTimeouts_get(t *testing.T) {
+	t.Parallel()
+
+	timeouts := newBlockTimeouts(5*time.Second, time.Second, time.Minute)
+
+	// the base timeout is used until the block sizes are measured
+	assert.Equal(t, 5*time.Second, timeouts.get(1, 100*time.Millisecond, 1000))
+
+	timeouts.observe(1000)
+
+	// the throughput of the peer isn't measured yet
+	assert.Equal(t, 5*time.Second, timeouts.get(1, 0, 0))
+
+	// 4 times the RTT and the transfer of a block at 1000 bytes/s
+	assert.Equal(t, 4400*time.Millisecond, timeouts.get(1, 100*time.Millisecond, 1000))
+
+	// small blocks from a fast peer are waited for the floor
+	assert.Equal(t, time.Second, timeouts.get(1, 10*time.Millisecond, 1e6))
+
+	// a batch of large blocks from a slow peer is waited for the ceiling
+	assert.Equal(t, time.Minute, timeouts.get(100, 100*time.Millisecond, 1000))
+}
+
+func TestSyncer_adaptiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTestSyncer(nil, &mockBlockchain{}, 5*time.Second, &mockSyncPeerClient{}, &mockProgression{})
+	syncer.timeouts = newBlockTimeouts(5*time.Second, time.Second, time.Minute)
+	syncer.timeouts.observe(10000)
+
+	// the peer isn't measured
+	assert.Equal(t, 5*time.Second, syncer.adaptiveTimeout(peer.ID("A"), 1, nil))
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:         peer.ID("A"),
+		Number:     10,
+		Distance:   big.NewInt(1),
+		Throughput: 10000,
+	})
+	assert.Equal(t, 4*time.Second, syncer.adaptiveTimeout(peer.ID("A"), 1, nil))
+
+	// the measurements of the running stream are preferred
+	meter := &streamMeter{rtt: 0, bytes: 20000, waiting: 4 * time.Second}
+	assert.Equal(t, 8*time.Second, syncer.adaptiveTimeout(peer.ID("A"), 1, meter))
+}
@@ -0,0 +1,150 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var (
+	// ErrCheckpointMismatch is returned when the chain of a peer doesn't include a trusted checkpoint
+	ErrCheckpointMismatch = errors.New("chain doesn't include the trusted checkpoint")
+
+	errInvalidTrustedCheckpoint     = errors.New("invalid trusted checkpoint, expected <number>:<hash>")
+	errConflictingTrustedCheckpoint = errors.New("conflicting trusted checkpoints")
+	errInvalidTrustedPeer           = errors.New("invalid trusted peer, expected a multiaddr with the peer ID")
+)
+
+// TrustedCheckpoint is a block the synced chain must include
+type TrustedCheckpoint struct {
+	Number uint64
+	Hash   types.Hash
+}
+
+// ParseTrustedCheckpoint parses a trusted checkpoint of the form <number>:<hash>
+func ParseTrustedCheckpoint(raw string) (*TrustedCheckpoint, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: %s", errInvalidTrustedCheckpoint, raw)
+	}
+
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidTrustedCheckpoint, raw)
+	}
+
+	hash, err := hex.DecodeHex(parts[1])
+	if err != nil || len(hash) != types.HashLength {
+		return nil, fmt.Errorf("%w: %s", errInvalidTrustedCheckpoint, raw)
+	}
+
+	return &TrustedCheckpoint{
+		Number: number,
+		Hash:   types.BytesToHash(hash),
+	}, nil
+}
+
+// ParseTrustedCheckpoints parses the trusted checkpoints, which can't have different hashes for the same block
+func ParseTrustedCheckpoints(raw []string) ([]*TrustedCheckpoint, error) {
+	checkpoints := make([]*TrustedCheckpoint, 0, len(raw))
+	hashes := make(map[uint64]types.Hash, len(raw))
+
+	for _, r := range raw {
+		checkpoint, err := ParseTrustedCheckpoint(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash, ok := hashes[checkpoint.Number]; ok && hash != checkpoint.Hash {
+			return nil, fmt.Errorf("%w at block %d", errConflictingTrustedCheckpoint, checkpoint.Number)
+		}
+
+		hashes[checkpoint.Number] = checkpoint.Hash
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// trustedCheckpoints are the hashes of the trusted checkpoints, by block number
+type trustedCheckpoints map[uint64]types.Hash
+
+func newTrustedCheckpoints(checkpoints []*TrustedCheckpoint) trustedCheckpoints {
+	res := make(trustedCheckpoints, len(checkpoints))
+
+	for _, checkpoint := range checkpoints {
+		res[checkpoint.Number] = checkpoint.Hash
+	}
+
+	return res
+}
+
+// verifyHeader checks the header against the trusted checkpoint of its number, if any
+func (c trustedCheckpoints) verifyHeader(header *types.Header) error {
+	hash, ok := c[header.Number]
+	if !ok || hash == header.Hash {
+		return nil
+	}
+
+	return fmt.Errorf("%w: block %d is %s, expected %s", ErrCheckpointMismatch, header.Number, header.Hash, hash)
+}
+
+// verifyHeaders checks the headers against the trusted checkpoints
+func (c trustedCheckpoints) verifyHeaders(headers []*types.Header) error {
+	for _, header := range headers {
+		if err := c.verifyHeader(header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// highestInRange returns the highest trusted checkpoint in the range (from, to]
+func (c trustedCheckpoints) highestInRange(from, to uint64) (uint64, bool) {
+	var (
+		highest uint64
+		found   bool
+	)
+
+	for number := range c {
+		if number > from && number <= to && (!found || number > highest) {
+			highest, found = number, true
+		}
+	}
+
+	return highest, found
+}
+
+// ParseTrustedPeers parses the multiaddrs of the trusted sync peers, which must include the peer IDs
+func ParseTrustedPeers(raw []string) ([]*peer.AddrInfo, error) {
+	peers := make([]*peer.AddrInfo, 0, len(raw))
+
+	for _, r := range raw {
+		info, err := common.StringToAddrInfo(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidTrustedPeer, r)
+		}
+
+		peers = append(peers, info)
+	}
+
+	return peers, nil
+}
+
+// newPeerIDSet returns the set of the IDs of the given peers
+func newPeerIDSet(peers []*peer.AddrInfo) map[peer.ID]bool {
+	res := make(map[peer.ID]bool, len(peers))
+
+	for _, info := range peers {
+		res[info.ID] = true
+	}
+
+	return res
+}
@@ -0,0 +1,249 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	hash := types.StringToHash("0x1234")
+
+	checkpoints, err := ParseTrustedCheckpoints([]string{
+		"100:" + hash.String(),
+		"100:" + hash.String(),
+		"200:" + hash.String(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*TrustedCheckpoint{
+		{Number: 100, Hash: hash},
+		{Number: 100, Hash: hash},
+		{Number: 200, Hash: hash},
+	}, checkpoints)
+
+	for _, raw := range []string{
+		"100",
+		"100:0x1234",
+		"-1:" + hash.String(),
+		"a:" + hash.String(),
+		"100:" + hash.String() + ":1",
+	} {
+		_, err := ParseTrustedCheckpoints([]string{raw})
+		assert.ErrorIs(t, err, errInvalidTrustedCheckpoint, raw)
+	}
+
+	_, err = ParseTrustedCheckpoints([]string{
+		"100:" + hash.String(),
+		"100:" + types.StringToHash("0x5678").String(),
+	})
+	assert.ErrorIs(t, err, errConflictingTrustedCheckpoint)
+}
+
+func TestTrustedCheckpoints_highestInRange(t *testing.T) {
+	t.Parallel()
+
+	checkpoints := newTrustedCheckpoints([]*TrustedCheckpoint{
+		{Number: 10},
+		{Number: 20},
+		{Number: 30},
+	})
+
+	number, ok := checkpoints.highestInRange(10, 25)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(20), number)
+
+	number, ok = checkpoints.highestInRange(5, 30)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(30), number)
+
+	_, ok = checkpoints.highestInRange(20, 29)
+	assert.False(t, ok)
+}
+
+func Test_bulkSyncWithPeer_TrustedCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(head, 10)
+
+	streamClient := &mockSyncPeerClient{
+		getBlocksHandler: func(_ context.Context, _ peer.ID, from uint64, _ time.Duration) (<-chan *types.Block, error) {
+			return blocksToCh(blocks[from-1:], 0), nil
+		},
+	}
+
+	tests := []struct {
+		name            string
+		client          *mockSyncPeerClient
+		checkpoint      *TrustedCheckpoint
+		expectedWritten uint64
+		err             error
+	}{
+		{
+			name:            "header-first sync should write the chain including the checkpoint",
+			client:          newHeaderFirstSyncPeerClient(blocks),
+			checkpoint:      &TrustedCheckpoint{Number: 5, Hash: blocks[4].Hash()},
+			expectedWritten: 10,
+		},
+		{
+			name:            "header-first sync should reject the chain without the checkpoint",
+			client:          newHeaderFirstSyncPeerClient(blocks),
+			checkpoint:      &TrustedCheckpoint{Number: 5, Hash: types.StringToHash("0x1")},
+			expectedWritten: 0,
+			err:             ErrCheckpointMismatch,
+		},
+		{
+			name:            "stream sync should write the chain including the checkpoint",
+			client:          streamClient,
+			checkpoint:      &TrustedCheckpoint{Number: 5, Hash: blocks[4].Hash()},
+			expectedWritten: 10,
+		},
+		{
+			name:            "stream sync should stop before the checkpoint it doesn't match",
+			client:          streamClient,
+			checkpoint:      &TrustedCheckpoint{Number: 5, Hash: types.StringToHash("0x1")},
+			expectedWritten: 4,
+			err:             ErrCheckpointMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				written    []*types.Block
+				latestHead = head
+			)
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					headerHandler: func() *types.Header {
+						return latestHead
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						return nil
+					},
+					writeBlockHandler: func(b *types.Block) error {
+						written = append(written, b)
+						latestHead = b.Header
+
+						return nil
+					},
+				},
+				time.Second,
+				test.client,
+				&mockProgression{},
+			)
+			syncer.trustedCheckpoints = newTrustedCheckpoints([]*TrustedCheckpoint{test.checkpoint})
+
+			lastNumber, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+				return false
+			})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.expectedWritten, lastNumber)
+			assert.Len(t, written, int(test.expectedWritten))
+			assert.Equal(t, test.err != nil, syncer.peerMap.IsQuarantined(peer.ID("A")))
+		})
+	}
+}
+
+func Test_bulkSyncWithPeer_RewindPastTrustedCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	head := (&types.Header{Number: 0}).ComputeHash()
+	shared := createMockChain(head, 10)
+	peerBranch := createMockChain(shared[9].Header, 10)
+	localBranch := createForkChain(shared[9].Header, 5)
+
+	local := newMockChain(head, shared, localBranch)
+
+	syncer := NewTestSyncer(
+		nil,
+		local.blockchain(),
+		time.Second,
+		newHeaderFirstSyncPeerClient(append(append([]*types.Block{{Header: head}}, shared...), peerBranch...)),
+		&mockProgression{},
+	)
+
+	// the local branch includes the checkpoint
+	syncer.trustedCheckpoints = newTrustedCheckpoints([]*TrustedCheckpoint{
+		{Number: 12, Hash: localBranch[1].Hash()},
+	})
+
+	syncer.peerMap.Put(&NoForkPeer{
+		ID:       peer.ID("A"),
+		Number:   20,
+		Distance: big.NewInt(1),
+	})
+
+	_, _, err := syncer.bulkSyncWithPeer(peer.ID("A"), func(*types.Block) bool {
+		return false
+	})
+
+	assert.ErrorIs(t, err, errDivergentFork)
+
+	// the local chain isn't unwound past the checkpoint
+	assert.Len(t, local.headers, 16)
+	assert.Empty(t, local.written)
+	assert.True(t, syncer.peerMap.IsQuarantined(peer.ID("A")))
+}
+
+func TestParseTrustedPeers(t *testing.T) {
+	t.Parallel()
+
+	rawID := "QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC"
+
+	peers, err := ParseTrustedPeers([]string{
+		"/ip4/127.0.0.1/tcp/1478/p2p/" + rawID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+	assert.Equal(t, rawID, peers[0].ID.String())
+	assert.Len(t, peers[0].Addrs, 1)
+
+	for _, raw := range []string{
+		"",
+		"127.0.0.1:1478",
+		"/ip4/127.0.0.1/tcp/1478",
+	} {
+		_, err := ParseTrustedPeers([]string{raw})
+		assert.ErrorIs(t, err, errInvalidTrustedPeer, raw)
+	}
+}
+
+func TestSyncer_bestPeer_Trusted(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTestSyncer(nil, &mockBlockchain{}, time.Second, &mockSyncPeerClient{}, &mockProgression{})
+	syncer.peerMap = &PeerMap{
+		trusted: newPeerIDSet([]*peer.AddrInfo{{ID: peer.ID("A")}}),
+	}
+	syncer.peerMap.Put(
+		&NoForkPeer{ID: peer.ID("A"), Number: 10, Distance: big.NewInt(10)},
+		&NoForkPeer{ID: peer.ID("B"), Number: 20, Distance: big.NewInt(20)},
+	)
+
+	// the trusted peer is preferred while it's ahead
+	assert.Equal(t, peer.ID("A"), syncer.bestPeer(nil, 5).ID)
+
+	// the peer map is used if the trusted peer isn't ahead or is skipped
+	assert.Equal(t, peer.ID("B"), syncer.bestPeer(nil, 10).ID)
+	assert.Equal(t, peer.ID("B"), syncer.bestPeer(map[peer.ID]bool{"A": true}, 5).ID)
+
+	// the trusted peer is preferred for the bodies as well
+	peers := syncer.peerMap.PeersWithBlock(5, 2, nil)
+	assert.Len(t, peers, 2)
+	assert.Equal(t, peer.ID("A"), peers[0].ID)
+}
@@ -1,6 +1,7 @@
 package syncer
 
 import (
+	"context"
 	"math/big"
 	"time"
 
@@ -18,14 +19,34 @@ import (
 type Blockchain interface {
 	// SubscribeEvents subscribes new blockchain event
 	SubscribeEvents() blockchain.Subscription
+	// SubscribeBus subscribes to the typed blockchain events of the given topics
+	SubscribeBus(buffer int, topics ...blockchain.Topic) *blockchain.BusSubscription
 	// Header returns get latest header
 	Header() *types.Header
 	// GetBlockByNumber returns block by number
 	GetBlockByNumber(uint64, bool) (*types.Block, bool)
+	// GetHeaderByNumber returns header by number
+	GetHeaderByNumber(uint64) (*types.Header, bool)
+	// GetBodyByHash returns the body of the block with the given hash
+	GetBodyByHash(types.Hash) (*types.Body, bool)
+	// GetTD returns the total difficulty of the block with the given hash
+	GetTD(types.Hash) (*big.Int, bool)
+	// GetReceiptsByHash returns the receipts of the block with the given hash
+	GetReceiptsByHash(types.Hash) ([]*types.Receipt, error)
+	// RecoverSenders recovers the senders of the block transactions, concurrently to the other blocks
+	RecoverSenders(*types.Block) error
 	// VerifyFinalizedBlock verifies finalized block
 	VerifyFinalizedBlock(*types.Block) error
-	// WriteBlock writes a given block to chain
-	WriteBlock(*types.Block, string) error
+	// WriteFinalizedBlock writes a given verified block to chain, waiting for its time if it is ahead of the clock
+	WriteFinalizedBlock(context.Context, *types.Block, string) error
+	// VerifyFinalizedHeader verifies finalized header without its block body
+	VerifyFinalizedHeader(*types.Header) error
+	// WriteFinalizedHeader writes a given header to chain without its block body
+	WriteFinalizedHeader(*types.Header, string) error
+	// WriteFinalizedBlockWithReceipts writes a given block with its receipts without executing it
+	WriteFinalizedBlockWithReceipts(*types.Block, []*types.Receipt, string) error
+	// RewindTo unwinds the canonical chain to the block with the given number
+	RewindTo(uint64, string) error
 }
 
 type Network interface {
@@ -57,12 +78,29 @@ type Syncer interface {
 	Start() error
 	// Close terminates syncer process
 	Close() error
+	// Stop pauses syncing, canceling the in-flight bulk sync and waiting for the block writes
+	Stop()
+	// Restart resumes syncing paused by Stop
+	Restart()
 	// GetSyncProgression returns sync progression
 	GetSyncProgression() *progress.Progression
+	// SubscribeProgress subscribes for sync progression events
+	SubscribeProgress() (<-chan *progress.Progression, func())
 	// HasSyncPeer returns whether syncer has the peer syncer can sync with
 	HasSyncPeer() bool
 	// Sync starts routine to sync blocks
 	Sync(func(*types.Block) bool) error
+	// PeerScores returns the reputation of the sync peers
+	PeerScores() []*PeerScore
+	// FetchBody fetches the body of the block of the given header from the sync peers, verified against the header
+	FetchBody(context.Context, *types.Header) (*types.Body, error)
+	// FetchReceipts fetches the receipts of the block of the given header from the sync peers,
+	// verified against the header
+	FetchReceipts(context.Context, *types.Header) ([]*types.Receipt, error)
+	// VerifyBlocks downloads the blocks in the given range from a peer and verifies them without writing them
+	VerifyBlocks(ctx context.Context, from, to uint64, peerID peer.ID) (*VerifyResult, error)
+	// SetRequestLimits sets the rate and the burst of the requests each peer can send to the sync peer server
+	SetRequestLimits(rateLimit float64, burst uint64)
 }
 
 type Progression interface {
@@ -70,6 +108,12 @@ type Progression interface {
 	StartProgression(startingBlock uint64, subscription blockchain.Subscription)
 	// UpdateHighestProgression updates highest block number
 	UpdateHighestProgression(highestBlock uint64)
+	// UpdatePeerProgression updates the sync peer
+	UpdatePeerProgression(peer string)
+	// CompleteBatchProgression updates the last written block of a completed batch
+	CompleteBatchProgression(currentBlock uint64)
+	// Subscribe subscribes for progression events
+	Subscribe() (<-chan *progress.Progression, func())
 	// GetProgression returns Progression
 	GetProgression() *progress.Progression
 	// StopProgression finishes progression
@@ -81,6 +125,8 @@ type SyncPeerService interface {
 	Start()
 	// Close terminates running processes for SyncPeerService
 	Close() error
+	// SetRequestLimits sets the rate and the burst of the requests each peer can send
+	SetRequestLimits(rateLimit float64, burst uint64)
 }
 
 type SyncPeerClient interface {
@@ -93,7 +139,19 @@ type SyncPeerClient interface {
 	// GetConnectedPeerStatuses fetches the statuses of all connecting peers
 	GetConnectedPeerStatuses() []*NoForkPeer
 	// GetBlocks returns a stream of blocks from given height to peer's latest
-	GetBlocks(peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	// The stream is closed when the given context is canceled
+	GetBlocks(context.Context, peer.ID, uint64, time.Duration) (<-chan *types.Block, error)
+	// GetHeaders fetches up to the given amount of consecutive headers from given height
+	GetHeaders(context.Context, peer.ID, uint64, uint64) ([]*types.Header, error)
+	// GetBodies fetches the bodies of the blocks with the given hashes, in the same order
+	GetBodies(context.Context, peer.ID, []types.Hash) ([]*types.Body, error)
+	// GetReceipts fetches the receipts of the blocks with the given hashes, in the same order
+	GetReceipts(context.Context, peer.ID, []types.Hash) ([][]*types.Receipt, error)
+	// GetEra fetches the archive of the given complete era and returns its blocks,
+	// the peer having the given time to send each chunk of the archive
+	GetEra(context.Context, peer.ID, uint64, time.Duration) ([]*types.Block, error)
+	// Ping sends a keepalive ping to the peer and waits for its pong
+	Ping(context.Context, peer.ID) error
 	// GetPeerStatusUpdateCh returns a channel of peer's status update
 	GetPeerStatusUpdateCh() <-chan *NoForkPeer
 	// GetPeerConnectionUpdateEventCh returns peer's connection change event
@@ -0,0 +1,147 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var (
+	// ErrInvalidVerifyRange is returned when the blocks to verify don't all have a local parent
+	ErrInvalidVerifyRange = errors.New("invalid range of blocks to verify")
+	// ErrVerifyPeerNotFound is returned when no sync peer serves the blocks to verify
+	ErrVerifyPeerNotFound = errors.New("no sync peer serves the blocks to verify")
+
+	errVerifyLightMode = errors.New("the blocks can't be verified in the light mode, the state isn't stored")
+)
+
+// VerifyResult is the outcome of the verification of a range of blocks downloaded from a peer
+type VerifyResult struct {
+	PeerID peer.ID
+	// Verified is the number of blocks that passed verification
+	Verified uint64
+	// Mismatches are the numbers of the verified blocks that differ from the local canonical ones
+	Mismatches []uint64
+	// Elapsed is the duration of the whole run, VerifyTime the part spent verifying the blocks
+	Elapsed    time.Duration
+	VerifyTime time.Duration
+	// Err is the error that stopped the run before the last block, nil if all the blocks were verified
+	Err error
+}
+
+// VerifyBlocks downloads the blocks in the given range from a peer and fully verifies them,
+// without writing them. The parents of the blocks must be local, so the range ends at most
+// right after the local head. The best peer having the blocks is used if no peer is given
+func (s *syncer) VerifyBlocks(ctx context.Context, from, to uint64, peerID peer.ID) (*VerifyResult, error) {
+	if s.mode == ModeLight {
+		return nil, errVerifyLightMode
+	}
+
+	if head := s.blockchain.Header().Number; from == 0 || from > to || to > head+1 {
+		return nil, fmt.Errorf("%w: %d to %d, the local head is %d", ErrInvalidVerifyRange, from, to, head)
+	}
+
+	if peerID == "" {
+		peers := s.peerMap.ArchivePeersWithBlock(to, 1, proto.Capability_BODIES)
+		if len(peers) == 0 {
+			return nil, ErrVerifyPeerNotFound
+		}
+
+		peerID = peers[0].ID
+	} else if p := s.peerMap.Get(peerID); p == nil || p.Number < to {
+		return nil, fmt.Errorf("%w: %s", ErrVerifyPeerNotFound, peerID)
+	}
+
+	result := &VerifyResult{PeerID: peerID}
+	start := time.Now()
+
+	result.Err = s.verifyBlocksFromPeer(ctx, from, to, result)
+	result.Elapsed = time.Since(start)
+
+	s.logger.Info(
+		"verified blocks",
+		"peer ID", peerID,
+		"from", from,
+		"verified", result.Verified,
+		"mismatches", len(result.Mismatches),
+		"elapsed", result.Elapsed,
+		"error", result.Err,
+	)
+
+	return result, nil
+}
+
+// verifyBlocksFromPeer streams the blocks from the peer and verifies them until the given number
+func (s *syncer) verifyBlocksFromPeer(ctx context.Context, from, to uint64, result *VerifyResult) error {
+	peerID := result.PeerID
+
+	// the stream is closed once the last block is verified
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	blockCh, err := s.syncPeerClient.GetBlocks(ctx, peerID, from, s.timeouts.ceiling)
+	if err != nil {
+		return s.requestFailed(peerID, err)
+	}
+
+	defer func() {
+		if err := s.syncPeerClient.CloseStream(peerID); err != nil {
+			s.logger.Error("Failed to close stream: ", err)
+		}
+	}()
+
+	next := from
+
+	for next <= to {
+		var block *types.Block
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.adaptiveTimeout(peerID, 1, nil)):
+			return s.failPeer(peerID, FailureTimeout, ErrTimeout)
+		case b, ok := <-blockCh:
+			if !ok {
+				return fmt.Errorf("%w: the stream ended before block %d", ErrPeerNoResponse, next)
+			}
+
+			block = b
+			s.timeouts.observe(block.Size())
+		}
+
+		if block.Number() != next {
+			return s.failPeer(
+				peerID,
+				FailureInvalidBlock,
+				fmt.Errorf("%w: expected %d, got %d", errUnexpectedHeaderNumber, next, block.Number()),
+			)
+		}
+
+		verifyStart := time.Now()
+		err := s.blockchain.VerifyFinalizedBlock(block)
+		result.VerifyTime += time.Since(verifyStart)
+
+		if err != nil {
+			if isForkError(err) {
+				// the chain of the peer diverged from the local one before this block
+				return s.peerError(ErrHashMismatch, peerID, fmt.Errorf("%w, unable to verify block: %v", errDivergentFork, err))
+			}
+
+			return s.failPeer(peerID, verificationFailure(err), fmt.Errorf("unable to verify block %d, %w", next, err))
+		}
+
+		if header, ok := s.blockchain.GetHeaderByNumber(next); ok && header.Hash != block.Hash() {
+			result.Mismatches = append(result.Mismatches, next)
+		}
+
+		result.Verified++
+		next++
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_syncer_VerifyBlocks(t *testing.T) {
+	t.Parallel()
+
+	genesis := (&types.Header{Number: 0}).ComputeHash()
+	blocks := createMockChain(genesis, 6)
+	errInvalidSeal := errors.New("invalid seal")
+
+	tests := []struct {
+		name       string
+		from, to   uint64
+		peerID     peer.ID
+		mode       SyncMode
+		invalid    uint64
+		local      map[uint64]types.Hash
+		verified   uint64
+		mismatches []uint64
+		runErr     error
+		err        error
+	}{
+		{
+			name:     "should verify all the blocks of the range",
+			from:     2,
+			to:       5,
+			verified: 4,
+		},
+		{
+			name:       "should report the blocks differing from the local ones",
+			from:       1,
+			to:         3,
+			local:      map[uint64]types.Hash{2: types.StringToHash("2")},
+			verified:   3,
+			mismatches: []uint64{2},
+		},
+		{
+			name:     "should stop at the first invalid block",
+			from:     1,
+			to:       5,
+			invalid:  3,
+			verified: 2,
+			runErr:   ErrVerification,
+		},
+		{
+			name: "should reject a range without local parents",
+			from: 1,
+			to:   6,
+			err:  ErrInvalidVerifyRange,
+		},
+		{
+			name:   "should reject a peer not having the blocks",
+			from:   1,
+			to:     5,
+			peerID: peer.ID("B"),
+			err:    ErrVerifyPeerNotFound,
+		},
+		{
+			name: "should reject the light mode",
+			from: 1,
+			to:   5,
+			mode: ModeLight,
+			err:  errVerifyLightMode,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			written := 0
+
+			syncer := NewTestSyncer(
+				nil,
+				&mockBlockchain{
+					// the local head is block 4
+					headerHandler: func() *types.Header {
+						return blocks[3].Header
+					},
+					getHeaderByNumberHandler: func(n uint64) (*types.Header, bool) {
+						if hash, ok := test.local[n]; ok {
+							return &types.Header{Number: n, Hash: hash}, true
+						}
+
+						if n > 4 {
+							return nil, false
+						}
+
+						return blocks[n-1].Header, true
+					},
+					verifyFinalizedBlockHandler: func(b *types.Block) error {
+						if b.Number() == test.invalid {
+							return errInvalidSeal
+						}
+
+						return nil
+					},
+					writeBlockHandler: func(*types.Block) error {
+						written++
+
+						return nil
+					},
+				},
+				time.Second,
+				&mockSyncPeerClient{
+					getBlocksHandler: func(_ context.Context, _ peer.ID, from uint64, _ time.Duration) (<-chan *types.Block, error) {
+						return blocksToCh(blocks[from-1:], 0), nil
+					},
+				},
+				&mockProgression{},
+			)
+			syncer.mode = test.mode
+			syncer.peerMap.Put(
+				&NoForkPeer{ID: peer.ID("A"), Number: 6, Distance: big.NewInt(0)},
+				&NoForkPeer{ID: peer.ID("B"), Number: 3, Distance: big.NewInt(1)},
+			)
+
+			result, err := syncer.VerifyBlocks(context.Background(), test.from, test.to, test.peerID)
+			assert.ErrorIs(t, err, test.err)
+			assert.Zero(t, written)
+
+			if test.err != nil {
+				return
+			}
+
+			assert.Equal(t, peer.ID("A"), result.PeerID)
+			assert.Equal(t, test.verified, result.Verified)
+			assert.Equal(t, test.mismatches, result.Mismatches)
+
+			if test.runErr == nil {
+				assert.NoError(t, result.Err)
+			} else {
+				assert.ErrorIs(t, result.Err, test.runErr)
+				assert.ErrorIs(t, result.Err, errInvalidSeal)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package syncer
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+)
+
+// The versions of the sync protocol. The peers advertise the versions they support in their status,
+// and the highest version supported by both sides is spoken
+const (
+	// ProtocolVersion1 is the block stream and the status. It is assumed for the peers
+	// predating the versioning, which don't advertise any version
+	ProtocolVersion1 uint32 = 1
+	// ProtocolVersion2 adds the capabilities advertised in the status
+	ProtocolVersion2 uint32 = 2
+)
+
+// supportedVersions are the versions of the sync protocol spoken by the node
+var supportedVersions = []uint32{ProtocolVersion1, ProtocolVersion2}
+
+// ErrIncompatiblePeer is returned for a peer not supporting any version spoken by the node
+var ErrIncompatiblePeer = errors.New("no common sync protocol version with the peer")
+
+// negotiateVersion returns the highest version supported by both the node and the peer, or 0 if none
+func negotiateVersion(peerVersions []uint32) uint32 {
+	if len(peerVersions) == 0 {
+		return ProtocolVersion1
+	}
+
+	best := uint32(0)
+
+	for _, peerVersion := range peerVersions {
+		for _, version := range supportedVersions {
+			if version == peerVersion && version > best {
+				best = version
+			}
+		}
+	}
+
+	return best
+}
+
+// capabilities returns the optional parts of the protocol served in the sync mode.
+// The light and the gateway nodes serve the headers only, not having all the bodies
+func (m SyncMode) capabilities() []proto.Capability {
+	if m == ModeLight || m == ModeGateway {
+		return []proto.Capability{proto.Capability_HEADERS}
+	}
+
+	return []proto.Capability{
+		proto.Capability_HEADERS,
+		proto.Capability_BODIES,
+		proto.Capability_BLOCK_COMPRESSION,
+		proto.Capability_RECEIPTS,
+		proto.Capability_ERA_ARCHIVES,
+	}
+}
+
+// requiredCapability returns the capability a peer needs to be synced from in the sync mode
+func (m SyncMode) requiredCapability() proto.Capability {
+	if m == ModeLight {
+		return proto.Capability_HEADERS
+	}
+
+	return proto.Capability_BODIES
+}
@@ -0,0 +1,104 @@
+package syncer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/syncer/proto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		peerVersions []uint32
+		expected     uint32
+	}{
+		{"peer predating the versioning", nil, ProtocolVersion1},
+		{"highest common version", []uint32{ProtocolVersion1, ProtocolVersion2}, ProtocolVersion2},
+		{"newer peer", []uint32{ProtocolVersion2, 3}, ProtocolVersion2},
+		{"older peer", []uint32{ProtocolVersion1}, ProtocolVersion1},
+		{"no common version", []uint32{3, 4}, 0},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, negotiateVersion(test.peerVersions))
+		})
+	}
+}
+
+func Test_statusToPeer_Version(t *testing.T) {
+	t.Parallel()
+
+	status := &proto.SyncPeerStatus{
+		Number:       10,
+		Versions:     supportedVersions,
+		Capabilities: ModeLight.capabilities(),
+	}
+
+	peer := statusToPeer(peer.ID("A"), status, big.NewInt(1))
+	assert.Equal(t, ProtocolVersion2, peer.Version)
+	assert.True(t, peer.Supports(proto.Capability_HEADERS))
+	assert.False(t, peer.Supports(proto.Capability_BODIES))
+
+	status.Versions = []uint32{3}
+	assert.Equal(t, uint32(0), statusToPeer(peer.ID, status, big.NewInt(1)).Version)
+}
+
+func TestNoForkPeer_Supports(t *testing.T) {
+	t.Parallel()
+
+	// capabilities are assumed for the peers speaking the version 1
+	legacy := &NoForkPeer{Version: ProtocolVersion1}
+	assert.True(t, legacy.Supports(proto.Capability_HEADERS))
+	assert.True(t, legacy.Supports(proto.Capability_BODIES))
+
+	light := &NoForkPeer{Version: ProtocolVersion2, Capabilities: ModeLight.capabilities()}
+	assert.True(t, light.Supports(proto.Capability_HEADERS))
+	assert.False(t, light.Supports(proto.Capability_BODIES))
+	assert.False(t, light.Supports(proto.Capability_BLOCK_COMPRESSION))
+	assert.True(t, light.Supports(proto.Capability_CAPABILITY_NONE))
+
+	full := &NoForkPeer{Version: ProtocolVersion2, Capabilities: ModeFull.capabilities()}
+	assert.True(t, full.Supports(proto.Capability_BODIES))
+}
+
+func TestPeerMap_RequiredCapability(t *testing.T) {
+	t.Parallel()
+
+	light := &NoForkPeer{
+		ID:           peer.ID("A"),
+		Number:       20,
+		Distance:     big.NewInt(1),
+		Version:      ProtocolVersion2,
+		Capabilities: ModeLight.capabilities(),
+	}
+	full := &NoForkPeer{
+		ID:           peer.ID("B"),
+		Number:       10,
+		Distance:     big.NewInt(1),
+		Version:      ProtocolVersion2,
+		Capabilities: ModeFull.capabilities(),
+	}
+
+	// a full node syncs from the peers serving the bodies only
+	fullMap := &PeerMap{requiredCapability: ModeFull.requiredCapability()}
+	fullMap.Put(light, full)
+
+	assert.Equal(t, full, fullMap.BestPeer(nil))
+	assert.Equal(t, []*NoForkPeer{full}, fullMap.PeersWithBlock(5, 2, nil))
+
+	// a light node syncs the headers from both
+	lightMap := &PeerMap{requiredCapability: ModeLight.requiredCapability()}
+	lightMap.Put(light, full)
+
+	assert.Equal(t, light, lightMap.BestPeer(nil))
+}
@@ -1,6 +1,7 @@
 package txpool
 
 import (
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -12,6 +13,9 @@ import (
 type accountsMap struct {
 	sync.Map
 	count uint64
+
+	// number of all enqueued transactions
+	enqueuedCount uint64
 }
 
 // Intializes an account for the given address.
@@ -22,6 +26,7 @@ func (m *accountsMap) initOnce(addr types.Address, nonce uint64) *account {
 	newAccount.init.Do(func() {
 		// create queues
 		newAccount.enqueued = newAccountQueue()
+		newAccount.enqueued.total = &m.enqueuedCount
 		newAccount.promoted = newAccountQueue()
 
 		// set the nonce
@@ -102,6 +107,11 @@ func (m *accountsMap) promoted() (total uint64) {
 	return
 }
 
+// enqueued returns the number of all enqueued transactions.
+func (m *accountsMap) enqueued() uint64 {
+	return atomic.LoadUint64(&m.enqueuedCount)
+}
+
 // allTxs returns all promoted and all enqueued transactions, depending on the flag.
 func (m *accountsMap) allTxs(includeEnqueued bool) (
 	allPromoted, allEnqueued map[types.Address][]*types.Transaction,
@@ -135,6 +145,71 @@ func (m *accountsMap) allTxs(includeEnqueued bool) (
 	return
 }
 
+// promotedCopy returns a copy of the promoted transactions of each account, sorted by nonce.
+// Unlike allTxs, the returned slices are not shared with the queues
+func (m *accountsMap) promotedCopy() map[types.Address][]*types.Transaction {
+	promoted := make(map[types.Address][]*types.Transaction)
+
+	m.Range(func(key, value interface{}) bool {
+		addr, _ := key.(types.Address)
+		account := m.get(addr)
+
+		account.promoted.lock(false)
+		defer account.promoted.unlock()
+
+		if account.promoted.length() == 0 {
+			return true
+		}
+
+		txs := make([]*types.Transaction, len(account.promoted.queue))
+		copy(txs, account.promoted.queue)
+
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].Nonce < txs[j].Nonce
+		})
+
+		promoted[addr] = txs
+
+		return true
+	})
+
+	return promoted
+}
+
+// pendingCopy returns a copy of the promoted and enqueued transactions of each account, sorted by nonce
+func (m *accountsMap) pendingCopy() map[types.Address][]*types.Transaction {
+	pending := make(map[types.Address][]*types.Transaction)
+
+	m.Range(func(key, value interface{}) bool {
+		addr, _ := key.(types.Address)
+		account := m.get(addr)
+
+		account.promoted.lock(false)
+		defer account.promoted.unlock()
+
+		account.enqueued.lock(false)
+		defer account.enqueued.unlock()
+
+		if account.promoted.length() == 0 && account.enqueued.length() == 0 {
+			return true
+		}
+
+		txs := make([]*types.Transaction, 0, account.promoted.length()+account.enqueued.length())
+		txs = append(txs, account.promoted.queue...)
+		txs = append(txs, account.enqueued.queue...)
+
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].Nonce < txs[j].Nonce
+		})
+
+		pending[addr] = txs
+
+		return true
+	})
+
+	return pending
+}
+
 // An account is the core structure for processing
 // transactions from a specific address. The nextNonce
 // field is what separates the enqueued from promoted transactions:
@@ -210,19 +285,47 @@ func (a *account) reset(nonce uint64, promoteCh chan<- promoteRequest) (
 }
 
 // enqueue attempts tp push the transaction onto the enqueued queue.
-func (a *account) enqueue(tx *types.Transaction) error {
+// A pending transaction of the same nonce is replaced instead, if the gas price
+// is bumped by at least priceBump percent, and returned.
+func (a *account) enqueue(tx *types.Transaction, priceBump uint64) (*types.Transaction, error) {
+	a.promoted.lock(true)
+	defer a.promoted.unlock()
+
 	a.enqueued.lock(true)
 	defer a.enqueued.unlock()
 
+	// replace the pending tx of the same nonce
+	if replaced := a.pendingWithNonce(tx.Nonce); replaced != nil {
+		if !isPriceBumped(replaced, tx, priceBump) {
+			return nil, ErrReplacementUnderpriced
+		}
+
+		if a.promoted.replace(tx) == nil {
+			a.enqueued.replace(tx)
+		}
+
+		return replaced, nil
+	}
+
 	// reject low nonce tx
 	if tx.Nonce < a.getNonce() {
-		return ErrNonceTooLow
+		return nil, ErrNonceTooLow
 	}
 
 	// enqueue tx
 	a.enqueued.push(tx)
 
-	return nil
+	return nil, nil
+}
+
+// pendingWithNonce returns the promoted or enqueued transaction of the given nonce, nil if there is none.
+// The locks of both queues are assumed held.
+func (a *account) pendingWithNonce(nonce uint64) *types.Transaction {
+	if tx := a.promoted.find(nonce); tx != nil {
+		return tx
+	}
+
+	return a.enqueued.find(nonce)
 }
 
 // Promote moves eligible transactions from enqueued to promoted.
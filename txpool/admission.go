@@ -0,0 +1,200 @@
+package txpool
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// defaultReorderWindow is the time a local transaction arriving ahead of the next nonce
+	// of its sender is held, waiting for the missing transactions of its burst
+	defaultReorderWindow = 100 * time.Millisecond
+
+	// maxReorderedTxs is the maximum number of transactions held per sender,
+	// the following ones are admitted right away
+	maxReorderedTxs = 64
+
+	// senderAdmissionTTL is the time the next nonce of an idle sender is remembered
+	senderAdmissionTTL = 5 * time.Second
+)
+
+// admission serializes the admission of the local transactions per sender. The enqueueing of the
+// admitted transactions is asynchronous, so the transactions of a burst submitted in order can
+// reach the pool out of order. A transaction ahead of the next nonce of its sender is held for a
+// short window, and admitted in nonce order once the missing transactions are
+type admission struct {
+	lock    sync.Mutex
+	senders map[types.Address]*senderAdmission
+
+	// window is the time a transaction ahead of the next nonce is held, zero disables the reordering
+	window time.Duration
+
+	// add admits the transaction to the pool
+	add func(*types.Transaction) error
+}
+
+// senderAdmission is the admission state of a sender
+type senderAdmission struct {
+	// lock serializes the admission of the transactions of the sender
+	lock sync.Mutex
+
+	// next is the nonce following the last one admitted
+	next uint64
+
+	// held are the transactions waiting for the missing nonces, by nonce
+	held map[uint64]*heldAdmission
+
+	// refs is the number of transactions of the sender being admitted
+	refs     int
+	lastUsed time.Time
+}
+
+// heldAdmission is a transaction held until its admission
+type heldAdmission struct {
+	tx    *types.Transaction
+	errCh chan error
+}
+
+func newAdmission(window time.Duration, add func(*types.Transaction) error) *admission {
+	return &admission{
+		senders: make(map[types.Address]*senderAdmission),
+		window:  window,
+		add:     add,
+	}
+}
+
+// admit admits the transaction of the given sender, whose next nonce expected by the pool is given.
+// It returns the error of the admission, once the transaction is admitted
+func (a *admission) admit(from types.Address, tx *types.Transaction, expected uint64) error {
+	s := a.acquire(from)
+	defer a.release(from, s)
+
+	s.lock.Lock()
+
+	if expected > s.next {
+		s.next = expected
+	}
+
+	if _, held := s.held[tx.Nonce]; a.window == 0 || tx.Nonce <= s.next || held ||
+		len(s.held) >= maxReorderedTxs {
+		// in order, a replacement of a held transaction, or too many held transactions
+		err := a.addInOrder(s, tx)
+
+		s.lock.Unlock()
+
+		return err
+	}
+
+	h := &heldAdmission{tx: tx, errCh: make(chan error, 1)}
+	s.held[tx.Nonce] = h
+
+	s.lock.Unlock()
+
+	timer := time.NewTimer(a.window)
+	defer timer.Stop()
+
+	select {
+	case err := <-h.errCh:
+		return err
+	case <-timer.C:
+	}
+
+	// the missing transactions didn't arrive in time, the gap is left
+	s.lock.Lock()
+	a.addHeldUpTo(s, tx.Nonce)
+	s.lock.Unlock()
+
+	return <-h.errCh
+}
+
+// addInOrder admits the transaction, then the held transactions following it
+func (a *admission) addInOrder(s *senderAdmission, tx *types.Transaction) error {
+	err := a.add(tx)
+	if err == nil && tx.Nonce >= s.next {
+		s.next = tx.Nonce + 1
+	}
+
+	for {
+		h, ok := s.held[s.next]
+		if !ok {
+			break
+		}
+
+		delete(s.held, s.next)
+
+		heldErr := a.add(h.tx)
+		h.errCh <- heldErr
+
+		if heldErr != nil {
+			break
+		}
+
+		s.next++
+	}
+
+	return err
+}
+
+// addHeldUpTo admits the held transactions up to the given nonce in nonce order,
+// then the held transactions following them
+func (a *admission) addHeldUpTo(s *senderAdmission, nonce uint64) {
+	nonces := make([]uint64, 0, len(s.held))
+
+	for heldNonce := range s.held {
+		if heldNonce <= nonce {
+			nonces = append(nonces, heldNonce)
+		}
+	}
+
+	sort.Slice(nonces, func(i, j int) bool {
+		return nonces[i] < nonces[j]
+	})
+
+	for _, heldNonce := range nonces {
+		h := s.held[heldNonce]
+		delete(s.held, heldNonce)
+
+		h.errCh <- a.addInOrder(s, h.tx)
+	}
+}
+
+// acquire returns the admission state of the sender, created if needed
+func (a *admission) acquire(from types.Address) *senderAdmission {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	s, ok := a.senders[from]
+	if !ok {
+		s = &senderAdmission{held: make(map[uint64]*heldAdmission)}
+		a.senders[from] = s
+	}
+
+	s.refs++
+
+	return s
+}
+
+// release releases the admission state of the sender, it is removed once the sender is idle
+func (a *admission) release(from types.Address, s *senderAdmission) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	s.refs--
+	s.lastUsed = time.Now()
+
+	if s.refs > 0 {
+		return
+	}
+
+	time.AfterFunc(senderAdmissionTTL, func() {
+		a.lock.Lock()
+		defer a.lock.Unlock()
+
+		if s.refs == 0 && time.Since(s.lastUsed) >= senderAdmissionTTL && a.senders[from] == s {
+			delete(a.senders, from)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package txpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordAdmission returns an admission recording the nonces of the admitted transactions
+func recordAdmission(window time.Duration, rejected uint64) (*admission, func() []uint64) {
+	var (
+		lock   sync.Mutex
+		nonces []uint64
+	)
+
+	a := newAdmission(window, func(tx *types.Transaction) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if tx.Nonce == rejected {
+			return errors.New("rejected")
+		}
+
+		nonces = append(nonces, tx.Nonce)
+
+		return nil
+	})
+
+	return a, func() []uint64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]uint64{}, nonces...)
+	}
+}
+
+func TestAdmission_ReordersBurst(t *testing.T) {
+	t.Parallel()
+
+	a, admitted := recordAdmission(time.Second, 100)
+	addr := types.Address{0x1}
+
+	var wg sync.WaitGroup
+
+	// the burst reaches the pool in reverse order
+	for nonce := uint64(4); nonce > 0; nonce-- {
+		wg.Add(1)
+
+		go func(nonce uint64) {
+			defer wg.Done()
+
+			assert.NoError(t, a.admit(addr, newTx(addr, nonce, 1), 0))
+		}(nonce)
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.NoError(t, a.admit(addr, newTx(addr, 0, 1), 0))
+
+	wg.Wait()
+
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, admitted())
+}
+
+func TestAdmission_WindowExpires(t *testing.T) {
+	t.Parallel()
+
+	a, admitted := recordAdmission(50*time.Millisecond, 100)
+	addr := types.Address{0x1}
+
+	// the missing nonce never arrives, the gap is left
+	assert.NoError(t, a.admit(addr, newTx(addr, 3, 1), 0))
+	assert.NoError(t, a.admit(addr, newTx(addr, 4, 1), 0))
+
+	assert.Equal(t, []uint64{3, 4}, admitted())
+}
+
+func TestAdmission_ExpectedNonce(t *testing.T) {
+	t.Parallel()
+
+	a, admitted := recordAdmission(time.Minute, 100)
+	addr := types.Address{0x1}
+
+	// the pool already expects nonce 5
+	assert.NoError(t, a.admit(addr, newTx(addr, 5, 1), 5))
+
+	// a lower nonce is admitted right away, and left to the validation
+	assert.NoError(t, a.admit(addr, newTx(addr, 2, 1), 5))
+
+	assert.Equal(t, []uint64{5, 2}, admitted())
+}
+
+func TestAdmission_RejectedTx(t *testing.T) {
+	t.Parallel()
+
+	a, admitted := recordAdmission(50*time.Millisecond, 1)
+	addr := types.Address{0x1}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- a.admit(addr, newTx(addr, 2, 1), 0)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoError(t, a.admit(addr, newTx(addr, 0, 1), 0))
+	assert.Error(t, a.admit(addr, newTx(addr, 1, 1), 0))
+
+	// the rejected nonce leaves a gap, the held transaction waits for the window
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, []uint64{0, 2}, admitted())
+}
@@ -0,0 +1,233 @@
+package txpool
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// SenderBansFileName is the name of the file the sender bans are persisted to
+const SenderBansFileName = "sender_bans"
+
+// maxSenderBanTTL is the longest duration of an expiring ban
+const maxSenderBanTTL = 100 * 365 * 24 * time.Hour
+
+var (
+	ErrSenderBanned        = errors.New("sender is banned")
+	ErrSenderNotBanned     = errors.New("sender isn't banned")
+	ErrInvalidSenderBanTTL = errors.New("sender ban duration is too long")
+)
+
+// SenderBan is a ban of the transactions of a sender from the pool admission
+type SenderBan struct {
+	Address types.Address `json:"address"`
+	Reason  string        `json:"reason"`
+
+	// ExpiresAt is the time the ban expires at, zero if it doesn't expire
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// expired returns whether the ban is expired at the given time
+func (b *SenderBan) expired(now time.Time) bool {
+	return !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// senderBans are the senders whose transactions are rejected by the pool, e.g. a compromised key
+// spamming the chain. They are managed at runtime by the operator, and persisted so that they
+// survive a restart. The expired bans are ignored, and dropped on the next change of the bans
+type senderBans struct {
+	lock sync.RWMutex
+
+	path string
+	bans map[types.Address]*SenderBan
+
+	// now returns the current time
+	now func() time.Time
+}
+
+// newSenderBans returns the sender bans, loaded from the given path
+func newSenderBans(path string) (*senderBans, error) {
+	s := &senderBans{
+		path: path,
+		bans: map[types.Address]*SenderBan{},
+		now:  time.Now,
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	bans := []*SenderBan{}
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return nil, err
+	}
+
+	for _, ban := range bans {
+		s.bans[ban.Address] = ban
+	}
+
+	return s, nil
+}
+
+// isBanned returns whether the transactions of the sender are rejected
+func (s *senderBans) isBanned(addr types.Address) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ban, ok := s.bans[addr]
+
+	return ok && !ban.expired(s.now())
+}
+
+// ban bans the sender for the given duration, the ban doesn't expire if it is zero.
+// The ban of an already banned sender is replaced
+func (s *senderBans) ban(addr types.Address, ttl time.Duration, reason string) (*SenderBan, error) {
+	if ttl < 0 || ttl > maxSenderBanTTL {
+		return nil, ErrInvalidSenderBanTTL
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+
+	ban := &SenderBan{
+		Address: addr,
+		Reason:  reason,
+	}
+
+	if ttl > 0 {
+		ban.ExpiresAt = now.Add(ttl)
+	}
+
+	bans := s.active(now)
+	bans[addr] = ban
+
+	if err := s.save(bans); err != nil {
+		return nil, err
+	}
+
+	s.bans = bans
+
+	return ban, nil
+}
+
+// unban lifts the ban of the sender
+func (s *senderBans) unban(addr types.Address) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	bans := s.active(s.now())
+	if _, ok := bans[addr]; !ok {
+		return ErrSenderNotBanned
+	}
+
+	delete(bans, addr)
+
+	if err := s.save(bans); err != nil {
+		return err
+	}
+
+	s.bans = bans
+
+	return nil
+}
+
+// list returns the active bans, by address
+func (s *senderBans) list() []*SenderBan {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return sortedSenderBans(s.active(s.now()))
+}
+
+// active returns a copy of the bans not expired at the given time
+func (s *senderBans) active(now time.Time) map[types.Address]*SenderBan {
+	bans := make(map[types.Address]*SenderBan, len(s.bans))
+
+	for addr, ban := range s.bans {
+		if !ban.expired(now) {
+			bans[addr] = ban
+		}
+	}
+
+	return bans
+}
+
+// save writes the bans to a temporary file and renames it,
+// so that a crash in the middle of the write leaves the previous ones
+func (s *senderBans) save(bans map[types.Address]*SenderBan) error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(sortedSenderBans(bans))
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
+
+func sortedSenderBans(bans map[types.Address]*SenderBan) []*SenderBan {
+	sorted := make([]*SenderBan, 0, len(bans))
+	for _, ban := range bans {
+		sorted = append(sorted, ban)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Address.String() < sorted[j].Address.String()
+	})
+
+	return sorted
+}
+
+// AddSenderBan rejects the transactions of the sender until the ban expires, it doesn't if the duration is zero.
+// The transactions of the sender already in the pool are kept
+func (p *TxPool) AddSenderBan(addr types.Address, ttl time.Duration, reason string) (*SenderBan, error) {
+	ban, err := p.bans.ban(addr, ttl, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("banned sender", "address", addr, "ttl", ttl, "reason", reason)
+
+	return ban, nil
+}
+
+// RemoveSenderBan lifts the ban of the sender
+func (p *TxPool) RemoveSenderBan(addr types.Address) error {
+	if err := p.bans.unban(addr); err != nil {
+		return err
+	}
+
+	p.logger.Info("unbanned sender", "address", addr)
+
+	return nil
+}
+
+// SenderBans returns the active sender bans
+func (p *TxPool) SenderBans() []*SenderBan {
+	return p.bans.list()
+}
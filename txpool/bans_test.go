@@ -0,0 +1,64 @@
+package txpool
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderBans(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), SenderBansFileName)
+	now := time.Unix(1000, 0)
+
+	newBans := func() *senderBans {
+		t.Helper()
+
+		bans, err := newSenderBans(path)
+		assert.NoError(t, err)
+
+		bans.now = func() time.Time {
+			return now
+		}
+
+		return bans
+	}
+
+	bans := newBans()
+
+	_, err := bans.ban(addr1, maxSenderBanTTL+time.Second, "")
+	assert.ErrorIs(t, err, ErrInvalidSenderBanTTL)
+
+	expiring, err := bans.ban(addr1, time.Minute, "spam")
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), expiring.ExpiresAt)
+
+	_, err = bans.ban(addr2, 0, "compromised key")
+	assert.NoError(t, err)
+
+	assert.True(t, bans.isBanned(addr1))
+	assert.True(t, bans.isBanned(addr2))
+	assert.False(t, bans.isBanned(addr3))
+
+	// the bans survive a restart
+	bans = newBans()
+	assert.Len(t, bans.list(), 2)
+	assert.True(t, bans.isBanned(addr1))
+
+	// the ban of the first sender expires
+	now = now.Add(time.Minute)
+
+	assert.False(t, bans.isBanned(addr1))
+	assert.True(t, bans.isBanned(addr2))
+	assert.Len(t, bans.list(), 1)
+
+	assert.ErrorIs(t, bans.unban(addr1), ErrSenderNotBanned)
+	assert.NoError(t, bans.unban(addr2))
+	assert.False(t, bans.isBanned(addr2))
+
+	bans = newBans()
+	assert.Empty(t, bans.list())
+}
@@ -0,0 +1,174 @@
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// maxHeldTxs is the maximum number of transactions of future types held at once
+const maxHeldTxs = 1024
+
+var (
+	// ErrNotTypedTx is returned when holding a legacy transaction
+	ErrNotTypedTx = errors.New("not a typed transaction")
+
+	errInvalidFutureTxType = errors.New("invalid future transaction type, expected <type>:<fork block>")
+)
+
+// FutureTxType is a transaction type activating at a fork block. The transactions
+// of the type are held until the fork, instead of being rejected
+type FutureTxType struct {
+	Type  byte
+	Block uint64
+}
+
+// ParseFutureTxTypes parses the future transaction types of the form <type>:<fork block>,
+// the type being a decimal or a 0x prefixed hex number
+func ParseFutureTxTypes(raw []string) ([]*FutureTxType, error) {
+	txTypes := make([]*FutureTxType, 0, len(raw))
+
+	for _, r := range raw {
+		parts := strings.Split(r, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %s", errInvalidFutureTxType, r)
+		}
+
+		txType, err := strconv.ParseUint(parts[0], 0, 8)
+		if err != nil || txType > 0x7f {
+			return nil, fmt.Errorf("%w: %s", errInvalidFutureTxType, r)
+		}
+
+		block, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidFutureTxType, r)
+		}
+
+		txTypes = append(txTypes, &FutureTxType{
+			Type:  byte(txType),
+			Block: block,
+		})
+	}
+
+	return txTypes, nil
+}
+
+// heldTxs are the raw transactions of the future types, held
+// until their fork and published to the network then
+type heldTxs struct {
+	lock  sync.Mutex
+	forks map[byte]uint64
+	txs   map[types.Hash]*heldTx
+}
+
+type heldTx struct {
+	txType byte
+	raw    []byte
+}
+
+func newHeldTxs(futureTypes []*FutureTxType) *heldTxs {
+	forks := make(map[byte]uint64, len(futureTypes))
+
+	for _, futureType := range futureTypes {
+		forks[futureType.Type] = futureType.Block
+	}
+
+	return &heldTxs{
+		forks: forks,
+		txs:   make(map[types.Hash]*heldTx),
+	}
+}
+
+// hold keeps the raw transaction of the given type if its fork isn't active in the block following the head
+func (h *heldTxs) hold(raw []byte, txType byte, head uint64) (types.Hash, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if fork, ok := h.forks[txType]; !ok || head+1 >= fork {
+		// the node doesn't support the type, or no longer holds it past the fork
+		return types.ZeroHash, fmt.Errorf("%w: 0x%x", types.ErrTxTypeNotSupported, txType)
+	}
+
+	hash := types.BytesToHash(keccak.Keccak256(nil, raw))
+
+	if _, ok := h.txs[hash]; ok {
+		return hash, ErrAlreadyKnown
+	}
+
+	if len(h.txs) >= maxHeldTxs {
+		return hash, ErrTxPoolOverflow
+	}
+
+	h.txs[hash] = &heldTx{
+		txType: txType,
+		raw:    append([]byte{}, raw...),
+	}
+
+	return hash, nil
+}
+
+// release removes and returns the held transactions whose fork is active in the block following the head
+func (h *heldTxs) release(head uint64) [][]byte {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	released := [][]byte{}
+
+	for hash, tx := range h.txs {
+		if head+1 >= h.forks[tx.txType] {
+			released = append(released, tx.raw)
+
+			delete(h.txs, hash)
+		}
+	}
+
+	return released
+}
+
+// len returns the number of held transactions
+func (h *heldTxs) len() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return len(h.txs)
+}
+
+// HoldTx holds the raw transaction of a future type until its fork, it's then published to the network.
+// The transactions of the other types are rejected with types.ErrTxTypeNotSupported
+func (p *TxPool) HoldTx(raw []byte) (types.Hash, error) {
+	txType, ok := types.TxEnvelopeType(raw)
+	if !ok {
+		return types.ZeroHash, ErrNotTypedTx
+	}
+
+	return p.held.hold(raw, txType, p.store.Header().Number)
+}
+
+// publishHeldTxs publishes the held transactions whose fork is reached,
+// the peers supporting the new types adding them to their pools
+func (p *TxPool) publishHeldTxs() {
+	released := p.held.release(p.store.Header().Number)
+	if len(released) == 0 {
+		return
+	}
+
+	p.logger.Info("publishing transactions held until their fork", "count", len(released))
+
+	if p.topic == nil {
+		return
+	}
+
+	for _, raw := range released {
+		if err := p.topic.Publish(&proto.Txn{Raw: &any.Any{Value: raw}}); err != nil {
+			p.logger.Error("failed to topic held tx", "err", err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFutureTxTypes(t *testing.T) {
+	t.Parallel()
+
+	txTypes, err := ParseFutureTxTypes([]string{"2:100", "0x7e:200"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*FutureTxType{
+		{Type: 2, Block: 100},
+		{Type: 0x7e, Block: 200},
+	}, txTypes)
+
+	for _, raw := range []string{
+		"2",
+		"2:",
+		"0x80:100",
+		"256:100",
+		"a:100",
+		"2:100:1",
+	} {
+		_, err := ParseFutureTxTypes([]string{raw})
+		assert.ErrorIs(t, err, errInvalidFutureTxType, raw)
+	}
+}
+
+func TestTxPool_HoldTx(t *testing.T) {
+	t.Parallel()
+
+	head := &types.Header{Number: 5}
+
+	pool, err := newTestPool(defaultMockStore{DefaultHeader: head})
+	assert.NoError(t, err)
+
+	pool.held = newHeldTxs([]*FutureTxType{{Type: 2, Block: 10}})
+
+	raw := []byte{0x02, 0xc0}
+
+	hash, err := pool.HoldTx(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, types.BytesToHash(keccak.Keccak256(nil, raw)), hash)
+	assert.Equal(t, 1, pool.held.len())
+
+	_, err = pool.HoldTx(raw)
+	assert.ErrorIs(t, err, ErrAlreadyKnown)
+
+	// unknown types and legacy transactions aren't held
+	_, err = pool.HoldTx([]byte{0x03, 0xc0})
+	assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+
+	_, err = pool.HoldTx([]byte{0xc0})
+	assert.ErrorIs(t, err, ErrNotTypedTx)
+
+	// the transactions are released once the fork is active in the next block
+	pool.publishHeldTxs()
+	assert.Equal(t, 1, pool.held.len())
+
+	head.Number = 9
+	pool.publishHeldTxs()
+	assert.Equal(t, 0, pool.held.len())
+
+	// the type isn't held past its fork
+	_, err = pool.HoldTx(raw)
+	assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+}
@@ -0,0 +1,213 @@
+package txpool
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// TxJournalFileName is the name of the file the pending transactions are journaled to
+	TxJournalFileName = "txpool_journal"
+
+	// DefaultJournalInterval is the default interval the pending transactions are journaled at
+	DefaultJournalInterval = time.Minute
+)
+
+// journalEntry is a transaction of the journal
+type journalEntry struct {
+	// Local is set for the local transactions, which are gossiped again on replay
+	Local bool `json:"local"`
+	// Tx is the RLP encoded transaction
+	Tx string `json:"tx"`
+}
+
+// txJournal persists the pending transactions of the pool, so that a restart doesn't drop them.
+// The pool is written as a whole at each interval and on close, and replayed on start.
+// The transactions written to the chain in between are rejected on replay, as any stale transaction
+type txJournal struct {
+	path     string
+	interval time.Duration
+
+	started bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// newTxJournal returns the journal of the given path, nil if the path is empty
+func newTxJournal(path string, interval time.Duration) *txJournal {
+	if path == "" {
+		return nil
+	}
+
+	if interval <= 0 {
+		interval = DefaultJournalInterval
+	}
+
+	return &txJournal{
+		path:     path,
+		interval: interval,
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// load reads the journaled transactions, none if there is no journal yet
+func (j *txJournal) load() ([]*journalEntry, error) {
+	data, err := ioutil.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*journalEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// save writes the entries to a temporary file and renames it,
+// so that a crash in the middle of the write leaves the previous journal
+func (j *txJournal) save(entries []*journalEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := j.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, j.path)
+}
+
+// runJournal replays the journal, then journals the pending transactions at each interval until the pool closes
+func (p *TxPool) runJournal() {
+	defer close(p.journal.doneCh)
+
+	p.replayJournal()
+
+	ticker := time.NewTicker(p.journal.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.journal.closeCh:
+			if err := p.flushJournal(); err != nil {
+				p.logger.Error("failed to journal the pending transactions", "err", err)
+			}
+
+			return
+		}
+
+		if err := p.flushJournal(); err != nil {
+			p.logger.Error("failed to journal the pending transactions", "err", err)
+		}
+	}
+}
+
+// closeJournal stops the journal, once the pending transactions are journaled a last time
+func (p *TxPool) closeJournal() {
+	if p.journal == nil || !p.journal.started {
+		return
+	}
+
+	close(p.journal.closeCh)
+	<-p.journal.doneCh
+}
+
+// replayJournal adds the journaled transactions back to the pool, in their nonce order.
+// The local transactions are replayed as local, and gossiped again
+func (p *TxPool) replayJournal() {
+	entries, err := p.journal.load()
+	if err != nil {
+		p.logger.Error("failed to load the transaction journal", "err", err)
+
+		return
+	}
+
+	replayed, dropped := 0, 0
+
+	for _, entry := range entries {
+		tx := &types.Transaction{}
+
+		raw, err := hex.DecodeHex(entry.Tx)
+		if err == nil {
+			err = tx.UnmarshalRLP(raw)
+		}
+
+		if err == nil {
+			origin := gossip
+			if entry.Local {
+				origin = local
+			}
+
+			err = p.addTx(origin, tx)
+		}
+
+		if err != nil {
+			p.logger.Debug("dropped journaled tx", "err", err)
+
+			dropped++
+
+			continue
+		}
+
+		if entry.Local {
+			p.publishTx(tx)
+		}
+
+		replayed++
+	}
+
+	if len(entries) > 0 {
+		p.logger.Info("replayed the transaction journal", "replayed", replayed, "dropped", dropped)
+	}
+}
+
+// flushJournal writes the pending transactions to the journal, by sender and nonce
+func (p *TxPool) flushJournal() error {
+	pending := p.accounts.pendingCopy()
+
+	addrs := make([]types.Address, 0, len(pending))
+	for addr := range pending {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+
+	entries := make([]*journalEntry, 0, len(pending))
+
+	for _, addr := range addrs {
+		for _, tx := range pending[addr] {
+			entries = append(entries, &journalEntry{
+				Local: p.IsLocal(tx),
+				Tx:    hex.EncodeToHex(tx.MarshalRLP()),
+			})
+		}
+	}
+
+	if err := p.journal.save(entries); err != nil {
+		return err
+	}
+
+	p.logger.Debug("journaled the pending transactions", "txs", len(entries))
+
+	return nil
+}
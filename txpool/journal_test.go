@@ -0,0 +1,87 @@
+package txpool
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxJournal(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), TxJournalFileName)
+	signer := crypto.NewEIP155Signer(100)
+	key, sender := tests.GenerateKeyAndAddr(t)
+
+	newJournaledPool := func() *TxPool {
+		t.Helper()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+
+		pool.SetSigner(signer)
+		pool.journal = newTxJournal(path, time.Minute)
+
+		return pool
+	}
+
+	signTx := func(nonce uint64) *types.Transaction {
+		t.Helper()
+
+		tx, err := signer.SignTx(newTx(types.ZeroAddress, nonce, 1), key)
+		assert.NoError(t, err)
+
+		return tx
+	}
+
+	// the transactions are enqueued, the first nonce of the sender being missing
+	localTx, remoteTx := signTx(1), signTx(2)
+
+	pool := newJournaledPool()
+
+	go func() {
+		assert.NoError(t, pool.addTx(local, localTx))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	go func() {
+		assert.NoError(t, pool.addTx(gossip, remoteTx))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	assert.NoError(t, pool.flushJournal())
+
+	// the transactions are replayed after a restart
+	pool = newJournaledPool()
+
+	done := make(chan struct{})
+
+	go func() {
+		pool.replayJournal()
+		close(done)
+	}()
+
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	<-done
+
+	assert.Equal(t, uint64(2), pool.accounts.get(sender).enqueued.length())
+	assert.True(t, pool.IsLocal(localTx))
+	assert.False(t, pool.IsLocal(remoteTx))
+
+	// the transactions which left the pool are dropped from the journal
+	pool.accounts.get(sender).enqueued.clear()
+	pool.index.remove(localTx, remoteTx)
+
+	assert.NoError(t, pool.flushJournal())
+
+	entries, err := pool.journal.load()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.False(t, pool.IsLocal(localTx))
+}
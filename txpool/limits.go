@@ -0,0 +1,147 @@
+package txpool
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// DefaultMaxAccountTxs is the default number of pending transactions an account can have in the pool
+	DefaultMaxAccountTxs = 128
+
+	// DefaultMaxEnqueued is the default number of enqueued (future nonce) transactions the pool holds
+	DefaultMaxEnqueued = 1024
+)
+
+var (
+	ErrAccountTxsLimit = errors.New("too many pending transactions from the account")
+	ErrEnqueuedLimit   = errors.New("too many enqueued transactions")
+)
+
+// makeRoom checks the transaction against the limits of the pool. When a limit is reached,
+// the cheapest enqueued transactions are evicted if the transaction pays more for its gas.
+// The transactions of the exempt accounts are neither limited nor evicted,
+// the local transactions are limited but never evicted
+func (p *TxPool) makeRoom(tx *types.Transaction) error {
+	if p.IsExempt(tx.From) {
+		return nil
+	}
+
+	price := tx.MaxGasPrice()
+
+	var (
+		pending   uint64
+		replacing bool
+	)
+
+	if account := p.accounts.get(tx.From); account != nil {
+		account.promoted.lock(false)
+		account.enqueued.lock(false)
+
+		pending = account.promoted.length() + account.enqueued.length()
+		replacing = account.pendingWithNonce(tx.Nonce) != nil
+
+		account.enqueued.unlock()
+		account.promoted.unlock()
+	}
+
+	// a replacement takes the place of a pending tx of the account
+	if !replacing {
+		if p.maxAccountTxs != 0 && pending >= p.maxAccountTxs && !p.evictEnqueued(&tx.From, price) {
+			return ErrAccountTxsLimit
+		}
+
+		if p.maxEnqueued != 0 && tx.Nonce > p.nextNonce(tx.From) &&
+			p.accounts.enqueued() >= p.maxEnqueued && !p.evictEnqueued(nil, price) {
+			return ErrEnqueuedLimit
+		}
+	}
+
+	for p.gauge.read()+slotsRequired(tx) > p.gauge.max {
+		if !p.evictEnqueued(nil, price) {
+			return ErrTxPoolOverflow
+		}
+	}
+
+	return nil
+}
+
+// evictEnqueued drops the cheapest remote enqueued transaction cheaper than the given price,
+// from the given account or from any account if nil. Among the cheapest, the one of the highest nonce
+// is dropped, as it is the furthest from being executable.
+// It returns false if there is no cheaper transaction to drop
+func (p *TxPool) evictEnqueued(addr *types.Address, price *big.Int) bool {
+	var cheapest *types.Transaction
+
+	consider := func(account *account) {
+		account.enqueued.lock(false)
+		defer account.enqueued.unlock()
+
+		for _, tx := range account.enqueued.queue {
+			if tx.MaxGasPrice().Cmp(price) >= 0 || p.IsLocal(tx) {
+				continue
+			}
+
+			if cheapest == nil || isCheaperToEvict(tx, cheapest) {
+				cheapest = tx
+			}
+		}
+	}
+
+	if addr != nil {
+		if account := p.accounts.get(*addr); account != nil {
+			consider(account)
+		}
+	} else {
+		p.accounts.Range(func(key, value interface{}) bool {
+			addr, _ := key.(types.Address)
+
+			if !p.IsExempt(addr) {
+				consider(p.accounts.get(addr))
+			}
+
+			return true
+		})
+	}
+
+	if cheapest == nil {
+		return false
+	}
+
+	account := p.accounts.get(cheapest.From)
+
+	account.enqueued.lock(true)
+	removed := account.enqueued.remove(cheapest)
+	account.enqueued.unlock()
+
+	if !removed {
+		// promoted or pruned in the meantime
+		return p.evictEnqueued(addr, price)
+	}
+
+	p.index.remove(cheapest)
+	p.gauge.decrease(slotsRequired(cheapest))
+
+	p.metrics.EvictedTxs.Add(1)
+	p.eventManager.signalEvent(proto.EventType_DROPPED, cheapest.Hash)
+
+	p.logger.Debug("evicted tx",
+		"hash", cheapest.Hash.String(),
+		"address", cheapest.From.String(),
+		"nonce", cheapest.Nonce,
+	)
+
+	return true
+}
+
+// isCheaperToEvict returns true if the transaction is evicted before the other one
+func isCheaperToEvict(tx, other *types.Transaction) bool {
+	if cmp := tx.MaxGasPrice().Cmp(other.MaxGasPrice()); cmp != 0 {
+		return cmp < 0
+	}
+
+	return tx.Nonce > other.Nonce
+}
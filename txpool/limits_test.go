@@ -0,0 +1,141 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// pendingNonces returns the nonces of the pending transactions of the account, sorted
+func pendingNonces(pool *TxPool, addr types.Address) []uint64 {
+	nonces := []uint64{}
+
+	for _, tx := range pool.accounts.pendingCopy()[addr] {
+		nonces = append(nonces, tx.Nonce)
+	}
+
+	return nonces
+}
+
+func TestMakeRoom(t *testing.T) {
+	t.Parallel()
+
+	newPricedTx := func(addr types.Address, nonce uint64, price int64) *types.Transaction {
+		tx := newTx(addr, nonce, 1)
+		tx.GasPrice = big.NewInt(price)
+
+		return tx
+	}
+
+	addTx := func(t *testing.T, pool *TxPool, origin txOrigin, tx *types.Transaction) {
+		t.Helper()
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- pool.addTx(origin, tx)
+		}()
+
+		select {
+		case req := <-pool.enqueueReqCh:
+			pool.handleEnqueueRequest(req)
+			assert.NoError(t, <-errCh)
+		case err := <-errCh:
+			assert.NoError(t, err)
+		}
+	}
+
+	enqueue := func(t *testing.T, pool *TxPool, tx *types.Transaction) {
+		t.Helper()
+
+		addTx(t, pool, gossip, tx)
+	}
+
+	t.Run("pool slots", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPoolWithSlots(1)
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		evicted := newPricedTx(addr1, 5, 1)
+		enqueue(t, pool, evicted)
+
+		// the cheaper enqueued tx is evicted
+		enqueue(t, pool, newPricedTx(addr2, 5, 2))
+
+		assert.Empty(t, pendingNonces(pool, addr1))
+		assert.Equal(t, []uint64{5}, pendingNonces(pool, addr2))
+		assert.Equal(t, uint64(1), pool.gauge.read())
+
+		_, known := pool.index.get(evicted.Hash)
+		assert.False(t, known)
+
+		// no enqueued tx is cheaper
+		assert.ErrorIs(t, pool.addTx(gossip, newPricedTx(addr3, 5, 2)), ErrTxPoolOverflow)
+	})
+
+	t.Run("account txs", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		pool.maxAccountTxs = 2
+
+		enqueue(t, pool, newPricedTx(addr1, 5, 1))
+		enqueue(t, pool, newPricedTx(addr1, 6, 2))
+
+		assert.ErrorIs(t, pool.addTx(gossip, newPricedTx(addr1, 7, 1)), ErrAccountTxsLimit)
+
+		// the cheapest tx of the account is evicted
+		enqueue(t, pool, newPricedTx(addr1, 7, 3))
+		assert.Equal(t, []uint64{6, 7}, pendingNonces(pool, addr1))
+
+		// the other accounts aren't limited
+		enqueue(t, pool, newPricedTx(addr2, 5, 1))
+		assert.Equal(t, []uint64{5}, pendingNonces(pool, addr2))
+	})
+
+	t.Run("enqueued txs", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		pool.maxEnqueued = 1
+
+		enqueue(t, pool, newPricedTx(addr1, 5, 1))
+
+		assert.ErrorIs(t, pool.addTx(gossip, newPricedTx(addr2, 5, 1)), ErrEnqueuedLimit)
+
+		enqueue(t, pool, newPricedTx(addr2, 5, 2))
+		assert.Empty(t, pendingNonces(pool, addr1))
+		assert.Equal(t, []uint64{5}, pendingNonces(pool, addr2))
+		assert.Equal(t, uint64(1), pool.accounts.enqueued())
+	})
+
+	t.Run("local txs", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPoolWithSlots(2)
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		addTx(t, pool, local, newPricedTx(addr1, 5, 1))
+
+		enqueue(t, pool, newPricedTx(addr2, 5, 1))
+
+		// the local tx evicts a cheaper remote tx, but is never evicted
+		addTx(t, pool, local, newPricedTx(addr3, 5, 2))
+
+		assert.Empty(t, pendingNonces(pool, addr2))
+		assert.ErrorIs(t, pool.addTx(gossip, newPricedTx(addr4, 5, 3)), ErrTxPoolOverflow)
+		assert.Equal(t, []uint64{5}, pendingNonces(pool, addr1))
+		assert.Equal(t, []uint64{5}, pendingNonces(pool, addr3))
+	})
+}
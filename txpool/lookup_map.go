@@ -10,11 +10,21 @@ import (
 type lookupMap struct {
 	sync.RWMutex
 	all map[types.Hash]*types.Transaction
+
+	// locals are the hashes of the local transactions of the map
+	locals map[types.Hash]struct{}
+}
+
+func newLookupMap() lookupMap {
+	return lookupMap{
+		all:    make(map[types.Hash]*types.Transaction),
+		locals: make(map[types.Hash]struct{}),
+	}
 }
 
-// add inserts the given transaction into the map. Returns false
-// if it already exists. [thread-safe]
-func (m *lookupMap) add(tx *types.Transaction) bool {
+// add inserts the given transaction into the map, as a local transaction if isLocal is set.
+// Returns false if it already exists. [thread-safe]
+func (m *lookupMap) add(tx *types.Transaction, isLocal bool) bool {
 	m.Lock()
 	defer m.Unlock()
 
@@ -24,6 +34,10 @@ func (m *lookupMap) add(tx *types.Transaction) bool {
 
 	m.all[tx.Hash] = tx
 
+	if isLocal {
+		m.locals[tx.Hash] = struct{}{}
+	}
+
 	return true
 }
 
@@ -34,6 +48,7 @@ func (m *lookupMap) remove(txs ...*types.Transaction) {
 
 	for _, tx := range txs {
 		delete(m.all, tx.Hash)
+		delete(m.locals, tx.Hash)
 	}
 }
 
@@ -49,3 +64,13 @@ func (m *lookupMap) get(hash types.Hash) (*types.Transaction, bool) {
 
 	return tx, true
 }
+
+// isLocal returns whether the transaction associated with the given hash is local. [thread-safe]
+func (m *lookupMap) isLocal(hash types.Hash) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	_, ok := m.locals[hash]
+
+	return ok
+}
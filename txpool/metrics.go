@@ -11,6 +11,18 @@ import (
 type Metrics struct {
 	// Pending transactions
 	PendingTxs metrics.Gauge
+
+	// Transactions rejected as their sender is banned
+	BannedSenderTxs metrics.Counter
+
+	// Enqueued transactions evicted to make room for better paying ones
+	EvictedTxs metrics.Counter
+
+	// Transactions received from the gossip
+	GossipedTxs metrics.Counter
+
+	// Gossiped transactions dropped as seen already
+	DuplicateGossipedTxs metrics.Counter
 }
 
 // GetPrometheusMetrics return the txpool metrics instance
@@ -28,12 +40,40 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "pending_transactions",
 			Help:      "Pending transactions in the pool",
 		}, labels).With(labelsWithValues...),
+		BannedSenderTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "banned_sender_transactions",
+			Help:      "Transactions rejected as their sender is banned",
+		}, labels).With(labelsWithValues...),
+		EvictedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "evicted_transactions",
+			Help:      "Enqueued transactions evicted to make room for better paying ones",
+		}, labels).With(labelsWithValues...),
+		GossipedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "gossiped_transactions",
+			Help:      "Transactions received from the gossip",
+		}, labels).With(labelsWithValues...),
+		DuplicateGossipedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "duplicate_gossiped_transactions",
+			Help:      "Gossiped transactions dropped as seen already",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
 // NilMetrics will return the non operational txpool metrics
 func NilMetrics() *Metrics {
 	return &Metrics{
-		PendingTxs: discard.NewGauge(),
+		PendingTxs:           discard.NewGauge(),
+		BannedSenderTxs:      discard.NewCounter(),
+		EvictedTxs:           discard.NewCounter(),
+		GossipedTxs:          discard.NewCounter(),
+		DuplicateGossipedTxs: discard.NewCounter(),
 	}
 }
@@ -27,6 +27,10 @@ func (m defaultMockStore) Header() *types.Header {
 	return m.DefaultHeader
 }
 
+func (m defaultMockStore) CalculateBaseFee(parent *types.Header) uint64 {
+	return parent.BaseFee
+}
+
 func (m defaultMockStore) GetNonce(types.Hash, types.Address) uint64 {
 	return 0
 }
@@ -48,6 +52,10 @@ func (fms faultyMockStore) Header() *types.Header {
 	return &types.Header{}
 }
 
+func (fms faultyMockStore) CalculateBaseFee(parent *types.Header) uint64 {
+	return 0
+}
+
 func (fms faultyMockStore) GetNonce(root types.Hash, addr types.Address) uint64 {
 	return 99999
 }
@@ -2,7 +2,9 @@ package txpool
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -26,7 +28,19 @@ func (p *TxPool) AddTxn(ctx context.Context, raw *proto.AddTxnReq) (*proto.AddTx
 
 	txn := new(types.Transaction)
 	if err := txn.UnmarshalRLP(raw.Raw.Value); err != nil {
-		return nil, err
+		if !errors.Is(err, types.ErrTxTypeNotSupported) {
+			return nil, err
+		}
+
+		// the transaction of a future type is held until its fork
+		hash, err := p.HoldTx(raw.Raw.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.AddTxnResp{
+			TxHash: hash.String(),
+		}, nil
 	}
 
 	if raw.From != "" {
@@ -78,3 +92,64 @@ func (p *TxPool) Subscribe(
 		}
 	}
 }
+
+// BanSender implements the operator endpoint. It rejects the transactions of the sender until the ban expires
+func (p *TxPool) BanSender(ctx context.Context, req *proto.BanSenderReq) (*proto.SenderBan, error) {
+	addr := types.Address{}
+	if err := addr.UnmarshalText([]byte(req.Address)); err != nil {
+		return nil, err
+	}
+
+	if req.Ttl > uint64(maxSenderBanTTL/time.Second) {
+		return nil, ErrInvalidSenderBanTTL
+	}
+
+	ban, err := p.AddSenderBan(addr, time.Duration(req.Ttl)*time.Second, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoSenderBan(ban), nil
+}
+
+// UnbanSender implements the operator endpoint. It lifts the ban of the sender
+func (p *TxPool) UnbanSender(ctx context.Context, req *proto.UnbanSenderReq) (*empty.Empty, error) {
+	addr := types.Address{}
+	if err := addr.UnmarshalText([]byte(req.Address)); err != nil {
+		return nil, err
+	}
+
+	if err := p.RemoveSenderBan(addr); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ListSenderBans implements the operator endpoint. It returns the active sender bans
+func (p *TxPool) ListSenderBans(ctx context.Context, req *empty.Empty) (*proto.SenderBansResp, error) {
+	bans := p.SenderBans()
+
+	resp := &proto.SenderBansResp{
+		Bans: make([]*proto.SenderBan, 0, len(bans)),
+	}
+
+	for _, ban := range bans {
+		resp.Bans = append(resp.Bans, toProtoSenderBan(ban))
+	}
+
+	return resp, nil
+}
+
+func toProtoSenderBan(ban *SenderBan) *proto.SenderBan {
+	resp := &proto.SenderBan{
+		Address: ban.Address.String(),
+		Reason:  ban.Reason,
+	}
+
+	if !ban.ExpiresAt.IsZero() {
+		resp.ExpiresAt = ban.ExpiresAt.Unix()
+	}
+
+	return resp
+}
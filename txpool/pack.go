@@ -0,0 +1,81 @@
+package txpool
+
+import (
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// packTransition is the state transition the packed transactions are written to
+type packTransition interface {
+	Write(txn *types.Transaction) error
+}
+
+// Pack dry-runs the block building with the promoted transactions.
+// The transactions are picked in the same order as during block building
+// (exempt accounts first, then local transactions, then highest priced primary first,
+// nonce ordered within an account)
+// and written to the transition until the gas limit is reached.
+// Unlike block building, the pool is not modified:
+// the accounts which would be demoted or dropped are only skipped.
+// It returns the transactions written successfully
+func (p *TxPool) Pack(gasLimit uint64, transition packTransition) []*types.Transaction {
+	promoted := p.accounts.promotedCopy()
+	executables, exemptExecutables, localExecutables := newPricedQueue(), newPricedQueue(), newPricedQueue()
+
+	push := func(tx *types.Transaction) {
+		switch {
+		case p.IsExempt(tx.From):
+			exemptExecutables.push(tx)
+		case p.IsLocal(tx):
+			localExecutables.push(tx)
+		default:
+			executables.push(tx)
+		}
+	}
+
+	// push the primaries, the same as Prepare
+	for addr, txs := range promoted {
+		push(txs[0])
+		promoted[addr] = txs[1:]
+	}
+
+	included := make([]*types.Transaction, 0)
+
+	for {
+		tx := exemptExecutables.pop()
+		if tx == nil {
+			tx = localExecutables.pop()
+		}
+
+		if tx == nil {
+			tx = executables.pop()
+		}
+
+		if tx == nil {
+			return included
+		}
+
+		if tx.ExceedsBlockGasLimit(gasLimit) {
+			// the account would be dropped
+			continue
+		}
+
+		if err := transition.Write(tx); err != nil {
+			if _, ok := err.(*state.GasLimitReachedTransitionApplicationError); ok { //nolint:errorlint
+				// the block is full
+				return included
+			}
+
+			// the account would be demoted or dropped
+			continue
+		}
+
+		included = append(included, tx)
+
+		// push the next primary of the account, the same as Pop
+		if txs := promoted[tx.From]; len(txs) > 0 {
+			push(txs[0])
+			promoted[tx.From] = txs[1:]
+		}
+	}
+}
@@ -25,6 +25,12 @@ type TxnPoolOperatorClient interface {
 	AddTxn(ctx context.Context, in *AddTxnReq, opts ...grpc.CallOption) (*AddTxnResp, error)
 	// Subscribe subscribes for new events in the txpool
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (TxnPoolOperator_SubscribeClient, error)
+	// BanSender rejects the transactions of the sender from the pool until the ban expires
+	BanSender(ctx context.Context, in *BanSenderReq, opts ...grpc.CallOption) (*SenderBan, error)
+	// UnbanSender lifts the ban of the sender
+	UnbanSender(ctx context.Context, in *UnbanSenderReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListSenderBans returns the active sender bans
+	ListSenderBans(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SenderBansResp, error)
 }
 
 type txnPoolOperatorClient struct {
@@ -85,6 +91,33 @@ func (x *txnPoolOperatorSubscribeClient) Recv() (*TxPoolEvent, error) {
 	return m, nil
 }
 
+func (c *txnPoolOperatorClient) BanSender(ctx context.Context, in *BanSenderReq, opts ...grpc.CallOption) (*SenderBan, error) {
+	out := new(SenderBan)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolOperator/BanSender", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txnPoolOperatorClient) UnbanSender(ctx context.Context, in *UnbanSenderReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolOperator/UnbanSender", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txnPoolOperatorClient) ListSenderBans(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SenderBansResp, error) {
+	out := new(SenderBansResp)
+	err := c.cc.Invoke(ctx, "/v1.TxnPoolOperator/ListSenderBans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TxnPoolOperatorServer is the server API for TxnPoolOperator service.
 // All implementations must embed UnimplementedTxnPoolOperatorServer
 // for forward compatibility
@@ -95,6 +128,12 @@ type TxnPoolOperatorServer interface {
 	AddTxn(context.Context, *AddTxnReq) (*AddTxnResp, error)
 	// Subscribe subscribes for new events in the txpool
 	Subscribe(*SubscribeRequest, TxnPoolOperator_SubscribeServer) error
+	// BanSender rejects the transactions of the sender from the pool until the ban expires
+	BanSender(context.Context, *BanSenderReq) (*SenderBan, error)
+	// UnbanSender lifts the ban of the sender
+	UnbanSender(context.Context, *UnbanSenderReq) (*emptypb.Empty, error)
+	// ListSenderBans returns the active sender bans
+	ListSenderBans(context.Context, *emptypb.Empty) (*SenderBansResp, error)
 	mustEmbedUnimplementedTxnPoolOperatorServer()
 }
 
@@ -111,6 +150,15 @@ func (UnimplementedTxnPoolOperatorServer) AddTxn(context.Context, *AddTxnReq) (*
 func (UnimplementedTxnPoolOperatorServer) Subscribe(*SubscribeRequest, TxnPoolOperator_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (UnimplementedTxnPoolOperatorServer) BanSender(context.Context, *BanSenderReq) (*SenderBan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanSender not implemented")
+}
+func (UnimplementedTxnPoolOperatorServer) UnbanSender(context.Context, *UnbanSenderReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnbanSender not implemented")
+}
+func (UnimplementedTxnPoolOperatorServer) ListSenderBans(context.Context, *emptypb.Empty) (*SenderBansResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSenderBans not implemented")
+}
 func (UnimplementedTxnPoolOperatorServer) mustEmbedUnimplementedTxnPoolOperatorServer() {}
 
 // UnsafeTxnPoolOperatorServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +229,60 @@ func (x *txnPoolOperatorSubscribeServer) Send(m *TxPoolEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TxnPoolOperator_BanSender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanSenderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolOperatorServer).BanSender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolOperator/BanSender",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolOperatorServer).BanSender(ctx, req.(*BanSenderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TxnPoolOperator_UnbanSender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbanSenderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolOperatorServer).UnbanSender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolOperator/UnbanSender",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolOperatorServer).UnbanSender(ctx, req.(*UnbanSenderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TxnPoolOperator_ListSenderBans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxnPoolOperatorServer).ListSenderBans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.TxnPoolOperator/ListSenderBans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxnPoolOperatorServer).ListSenderBans(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TxnPoolOperator_ServiceDesc is the grpc.ServiceDesc for TxnPoolOperator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -196,6 +298,18 @@ var TxnPoolOperator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTxn",
 			Handler:    _TxnPoolOperator_AddTxn_Handler,
 		},
+		{
+			MethodName: "BanSender",
+			Handler:    _TxnPoolOperator_BanSender_Handler,
+		},
+		{
+			MethodName: "UnbanSender",
+			Handler:    _TxnPoolOperator_UnbanSender_Handler,
+		},
+		{
+			MethodName: "ListSenderBans",
+			Handler:    _TxnPoolOperator_ListSenderBans_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
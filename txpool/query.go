@@ -10,16 +10,16 @@ import "github.com/0xPolygon/polygon-edge/types"
 // -> Returns the value from the TxPool if the account is initialized in-memory
 //
 // -> Returns the value from the world state otherwise
+//
+// -> Skips the nonces reserved for the operator account
 func (p *TxPool) GetNonce(addr types.Address) uint64 {
-	account := p.accounts.get(addr)
-	if account == nil {
-		stateRoot := p.store.Header().StateRoot
-		stateNonce := p.store.GetNonce(stateRoot, addr)
+	nonce := p.nextNonce(addr)
 
-		return stateNonce
+	if reserved := p.reservedNonce(addr); reserved > nonce {
+		return reserved
 	}
 
-	return account.getNonce()
+	return nonce
 }
 
 // GetCapacity returns the current number of slots
@@ -14,11 +14,19 @@ type accountQueue struct {
 	sync.RWMutex
 	wLock uint32
 	queue minNonceQueue
+
+	// transactions of the queue by nonce
+	nonces map[uint64]*types.Transaction
+
+	// total number of transactions held by this kind of queue
+	// across the accounts (optional)
+	total *uint64
 }
 
 func newAccountQueue() *accountQueue {
 	q := accountQueue{
-		queue: make(minNonceQueue, 0),
+		queue:  make(minNonceQueue, 0),
+		nonces: make(map[uint64]*types.Transaction),
 	}
 
 	heap.Init(&q.queue)
@@ -71,6 +79,9 @@ func (q *accountQueue) clear() (removed []*types.Transaction) {
 
 	// clear the underlying queue
 	q.queue = q.queue[:0]
+	q.nonces = make(map[uint64]*types.Transaction)
+
+	q.count(-len(removed))
 
 	return
 }
@@ -78,6 +89,9 @@ func (q *accountQueue) clear() (removed []*types.Transaction) {
 // push pushes the given transactions onto the queue.
 func (q *accountQueue) push(tx *types.Transaction) {
 	heap.Push(&q.queue, tx)
+	q.nonces[tx.Nonce] = tx
+
+	q.count(1)
 }
 
 // peek returns the first transaction from the queue without removing it.
@@ -100,9 +114,74 @@ func (q *accountQueue) pop() *types.Transaction {
 		return nil
 	}
 
+	q.forget(transaction)
+	q.count(-1)
+
 	return transaction
 }
 
+// find returns the transaction of the given nonce, nil if the queue holds none.
+func (q *accountQueue) find(nonce uint64) *types.Transaction {
+	return q.nonces[nonce]
+}
+
+// replace swaps the transaction of the same nonce as the given one for it,
+// and returns the replaced transaction (nil if the queue holds none of that nonce).
+// The order of the queue is kept, as both transactions have the same nonce.
+func (q *accountQueue) replace(tx *types.Transaction) *types.Transaction {
+	if q.find(tx.Nonce) == nil {
+		return nil
+	}
+
+	for i, queued := range q.queue {
+		if queued.Nonce == tx.Nonce {
+			q.queue[i] = tx
+			q.nonces[tx.Nonce] = tx
+
+			return queued
+		}
+	}
+
+	return nil
+}
+
+// remove removes the given transaction from the queue.
+// Returns false if the queue doesn't hold it.
+func (q *accountQueue) remove(tx *types.Transaction) bool {
+	for i, queued := range q.queue {
+		if queued == tx {
+			heap.Remove(&q.queue, i)
+			q.forget(queued)
+			q.count(-1)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// forget drops the given (removed) transaction from the nonce lookup,
+// unless another transaction of the same nonce took its place.
+func (q *accountQueue) forget(tx *types.Transaction) {
+	if q.nonces[tx.Nonce] == tx {
+		delete(q.nonces, tx.Nonce)
+	}
+}
+
+// count updates the total number of transactions, if the queue keeps one.
+func (q *accountQueue) count(delta int) {
+	if q.total == nil || delta == 0 {
+		return
+	}
+
+	if delta > 0 {
+		atomic.AddUint64(q.total, uint64(delta))
+	} else {
+		atomic.AddUint64(q.total, ^uint64(-delta-1))
+	}
+}
+
 // length returns the number of transactions in the queue.
 func (q *accountQueue) length() uint64 {
 	return uint64(q.queue.Len())
@@ -217,7 +296,7 @@ func (q *maxPriceQueue) Swap(i, j int) {
 }
 
 func (q *maxPriceQueue) Less(i, j int) bool {
-	return (*q)[i].GasPrice.Uint64() > (*q)[j].GasPrice.Uint64()
+	return (*q)[i].MaxGasPrice().Uint64() > (*q)[j].MaxGasPrice().Uint64()
 }
 
 func (q *maxPriceQueue) Push(x interface{}) {
@@ -0,0 +1,66 @@
+package txpool
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// DefaultPriceBump is the default percentage a replacement transaction has to raise the gas price by
+const DefaultPriceBump = 10
+
+var ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+
+// isPriceBumped checks if the gas price of the replacement exceeds the one of the replaced transaction
+// by at least priceBump percent
+func isPriceBumped(replaced, replacement *types.Transaction, priceBump uint64) bool {
+	oldPrice, newPrice := replaced.MaxGasPrice(), replacement.MaxGasPrice()
+
+	if newPrice.Cmp(oldPrice) <= 0 {
+		return false
+	}
+
+	// newPrice * 100 >= oldPrice * (100 + priceBump)
+	threshold := new(big.Int).Mul(oldPrice, new(big.Int).SetUint64(100+priceBump))
+
+	return new(big.Int).Mul(newPrice, big.NewInt(100)).Cmp(threshold) >= 0
+}
+
+// checkReplacement rejects the transaction if it replaces a pending transaction of its sender
+// without bumping its gas price enough. The replacement itself is done when the transaction is enqueued
+func (p *TxPool) checkReplacement(tx *types.Transaction) error {
+	account := p.accounts.get(tx.From)
+	if account == nil {
+		return nil
+	}
+
+	account.promoted.lock(false)
+	defer account.promoted.unlock()
+
+	account.enqueued.lock(false)
+	defer account.enqueued.unlock()
+
+	if replaced := account.pendingWithNonce(tx.Nonce); replaced != nil &&
+		!isPriceBumped(replaced, tx, p.priceBump) {
+		return ErrReplacementUnderpriced
+	}
+
+	return nil
+}
+
+// replaceTx accounts for the transaction replaced in the pool by another of the same nonce
+func (p *TxPool) replaceTx(replaced, replacement *types.Transaction) {
+	p.index.remove(replaced)
+
+	p.gauge.increase(slotsRequired(replacement))
+	p.gauge.decrease(slotsRequired(replaced))
+
+	p.logger.Debug("replaced tx",
+		"hash", replaced.Hash.String(),
+		"replacement", replacement.Hash.String(),
+	)
+
+	p.eventManager.signalEvent(proto.EventType_DROPPED, replaced.Hash)
+}
@@ -0,0 +1,175 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPriceBumped(t *testing.T) {
+	t.Parallel()
+
+	withPrice := func(price int64) *types.Transaction {
+		return &types.Transaction{GasPrice: big.NewInt(price)}
+	}
+
+	assert.False(t, isPriceBumped(withPrice(100), withPrice(109), 10))
+	assert.True(t, isPriceBumped(withPrice(100), withPrice(110), 10))
+
+	// the price has to increase, even without a bump
+	assert.False(t, isPriceBumped(withPrice(100), withPrice(100), 0))
+	assert.True(t, isPriceBumped(withPrice(100), withPrice(101), 0))
+
+	// the fee cap of the dynamic fee transactions is compared
+	assert.True(t, isPriceBumped(withPrice(100), &types.Transaction{
+		Type:      types.DynamicFeeTx,
+		GasPrice:  big.NewInt(0),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(110),
+	}, 10))
+}
+
+func TestReplaceTx(t *testing.T) {
+	t.Parallel()
+
+	newPricedTx := func(nonce uint64, price int64) *types.Transaction {
+		tx := newTx(addr1, nonce, 1)
+		tx.GasPrice = big.NewInt(price)
+
+		return tx
+	}
+
+	testCases := []struct {
+		name    string
+		promote bool
+	}{
+		{"enqueued tx", false},
+		{"promoted tx", true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool, err := newTestPool()
+			assert.NoError(t, err)
+			pool.SetSigner(&mockSigner{})
+
+			pool.priceBump = DefaultPriceBump
+
+			events, unsubscribe := pool.SubscribeTxEvents([]proto.EventType{proto.EventType_DROPPED})
+			defer unsubscribe()
+
+			nonce := uint64(1)
+			if testCase.promote {
+				nonce = 0
+			}
+
+			tx := newPricedTx(nonce, 100)
+
+			go func() {
+				assert.NoError(t, pool.addTx(local, tx))
+			}()
+
+			if testCase.promote {
+				go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+				pool.handlePromoteRequest(<-pool.promoteReqCh)
+			} else {
+				pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+			}
+
+			account := pool.accounts.get(addr1)
+
+			// the gas price isn't bumped enough
+			assert.ErrorIs(t, pool.addTx(local, newPricedTx(nonce, 109)), ErrReplacementUnderpriced)
+
+			replacement := newPricedTx(nonce, 110)
+
+			go func() {
+				assert.NoError(t, pool.addTx(local, replacement))
+			}()
+			pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+			queue, other := account.enqueued, account.promoted
+			if testCase.promote {
+				queue, other = other, queue
+			}
+
+			assert.Equal(t, uint64(1), queue.length())
+			assert.Equal(t, uint64(0), other.length())
+			assert.Equal(t, replacement.Hash, queue.peek().Hash)
+			assert.Equal(t, slotsRequired(replacement), pool.gauge.read())
+
+			_, known := pool.index.get(tx.Hash)
+			assert.False(t, known)
+
+			select {
+			case event := <-events:
+				assert.Equal(t, tx.Hash.String(), event.TxHash)
+			case <-time.After(time.Second):
+				t.Fatal("no dropped event for the replaced tx")
+			}
+		})
+	}
+}
+
+func TestPopReplacedPrimary(t *testing.T) {
+	t.Parallel()
+
+	newPricedTx := func(nonce uint64, price int64) *types.Transaction {
+		tx := newTx(addr1, nonce, 1)
+		tx.GasPrice = big.NewInt(price)
+
+		return tx
+	}
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.priceBump = DefaultPriceBump
+
+	tx := newPricedTx(0, 100)
+
+	go func() {
+		assert.NoError(t, pool.addTx(local, tx))
+	}()
+	go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+	pool.Prepare()
+	assert.Equal(t, tx.Hash, pool.Peek().Hash)
+
+	// the primary is replaced while it is executed
+	replacement := newPricedTx(0, 110)
+
+	go func() {
+		assert.NoError(t, pool.addTx(local, replacement))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	events, unsubscribe := pool.SubscribeTxEvents([]proto.EventType{proto.EventType_DROPPED})
+	defer unsubscribe()
+
+	pool.Pop(tx)
+
+	// the replacement of the spent nonce is discarded
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).promoted.length())
+	assert.Equal(t, uint64(0), pool.gauge.read())
+
+	_, known := pool.index.get(replacement.Hash)
+	assert.False(t, known)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, replacement.Hash.String(), event.TxHash)
+	case <-time.After(time.Second):
+		t.Fatal("no dropped event for the replacement")
+	}
+}
@@ -0,0 +1,237 @@
+package txpool
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// NonceReservationsFileName is the name of the file the nonce reservations of the operator account are persisted to
+const NonceReservationsFileName = "nonce_reservations"
+
+var (
+	ErrNoOperatorAccount      = errors.New("no operator account configured")
+	ErrEmptyNonceReservation  = errors.New("reserved nonce count must be positive")
+	ErrNonceReservationAbsent = errors.New("nonce reservation not found")
+)
+
+// NonceRange is a range of consecutive nonces reserved for the operator account,
+// from From to From+Count excluded
+type NonceRange struct {
+	From  uint64 `json:"from"`
+	Count uint64 `json:"count"`
+}
+
+// End returns the nonce following the range
+func (r NonceRange) End() uint64 {
+	return r.From + r.Count
+}
+
+// nonceReservations are the nonce ranges reserved for the operator account by the relayer embedded
+// with the node. They are persisted, so that the nonces handed out to the relayer before a restart
+// aren't handed out again to the transactions submitted externally from the same account
+type nonceReservations struct {
+	sync.Mutex
+
+	account types.Address
+	path    string
+
+	// ranges are the reserved ranges not consumed yet, in nonce order
+	ranges []NonceRange
+}
+
+// newNonceReservations returns the reservations of the account, loaded from the given path
+func newNonceReservations(account types.Address, path string) (*nonceReservations, error) {
+	r := &nonceReservations{
+		account: account,
+		path:    path,
+		ranges:  []NonceRange{},
+	}
+
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &r.ranges); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// save writes the ranges to a temporary file and renames it,
+// so that a crash in the middle of the write leaves the previous ones
+func (r *nonceReservations) save(ranges []NonceRange) error {
+	if r.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(ranges)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := r.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, r.path)
+}
+
+// end returns the nonce following the reserved ranges, zero if there are none
+func (r *nonceReservations) end() uint64 {
+	if len(r.ranges) == 0 {
+		return 0
+	}
+
+	return r.ranges[len(r.ranges)-1].End()
+}
+
+// prune drops the ranges consumed by the transactions written up to the given state nonce
+func (r *nonceReservations) prune(stateNonce uint64) error {
+	kept := make([]NonceRange, 0, len(r.ranges))
+
+	for _, reserved := range r.ranges {
+		if reserved.End() > stateNonce {
+			kept = append(kept, reserved)
+		}
+	}
+
+	if len(kept) == len(r.ranges) {
+		return nil
+	}
+
+	if err := r.save(kept); err != nil {
+		return err
+	}
+
+	r.ranges = kept
+
+	return nil
+}
+
+// reservations returns the nonce reservations of the operator account, nil if none is configured
+func (p *TxPool) reservations() (*nonceReservations, error) {
+	if p.nonceReservations == nil {
+		return nil, ErrNoOperatorAccount
+	}
+
+	return p.nonceReservations, nil
+}
+
+// ReserveNonces reserves the given number of consecutive nonces for the operator account, following
+// the nonces of its pending transactions and of the previous reservations. The reservation is persisted
+// before it is returned, and the nonces aren't handed out by GetNonce until their transactions are written
+func (p *TxPool) ReserveNonces(count uint64) (NonceRange, error) {
+	if count == 0 {
+		return NonceRange{}, ErrEmptyNonceReservation
+	}
+
+	r, err := p.reservations()
+	if err != nil {
+		return NonceRange{}, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.prune(p.stateNonce(r.account)); err != nil {
+		return NonceRange{}, err
+	}
+
+	reserved := NonceRange{From: p.nextNonce(r.account), Count: count}
+	if end := r.end(); end > reserved.From {
+		reserved.From = end
+	}
+
+	ranges := append(append([]NonceRange{}, r.ranges...), reserved)
+	if err := r.save(ranges); err != nil {
+		return NonceRange{}, err
+	}
+
+	r.ranges = ranges
+
+	p.logger.Debug("reserved operator nonces", "account", r.account, "from", reserved.From, "count", count)
+
+	return reserved, nil
+}
+
+// ReleaseNonces cancels the reservation starting at the given nonce. Its nonces are handed out again
+// only if no later range is reserved, the relayer is expected to fill the gap otherwise
+func (p *TxPool) ReleaseNonces(from uint64) error {
+	r, err := p.reservations()
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for i, reserved := range r.ranges {
+		if reserved.From != from {
+			continue
+		}
+
+		ranges := append(append([]NonceRange{}, r.ranges[:i]...), r.ranges[i+1:]...)
+		if err := r.save(ranges); err != nil {
+			return err
+		}
+
+		r.ranges = ranges
+
+		return nil
+	}
+
+	return ErrNonceReservationAbsent
+}
+
+// NonceReservations returns the ranges reserved for the operator account and not consumed yet,
+// so that the relayer resumes its reservations after a restart
+func (p *TxPool) NonceReservations() ([]NonceRange, error) {
+	r, err := p.reservations()
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.prune(p.stateNonce(r.account)); err != nil {
+		return nil, err
+	}
+
+	return append([]NonceRange{}, r.ranges...), nil
+}
+
+// reservedNonce returns the nonce following the ranges reserved for the account, zero if it isn't the operator
+func (p *TxPool) reservedNonce(addr types.Address) uint64 {
+	r := p.nonceReservations
+	if r == nil || r.account != addr {
+		return 0
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	return r.end()
+}
+
+// stateNonce returns the nonce of the account in the state of the latest block
+func (p *TxPool) stateNonce(addr types.Address) uint64 {
+	return p.store.GetNonce(p.store.Header().StateRoot, addr)
+}
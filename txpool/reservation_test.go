@@ -0,0 +1,96 @@
+package txpool
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// nonceMockStore returns the same state nonce for all the accounts
+type nonceMockStore struct {
+	defaultMockStore
+
+	nonce uint64
+}
+
+func (m *nonceMockStore) GetNonce(types.Hash, types.Address) uint64 {
+	return atomic.LoadUint64(&m.nonce)
+}
+
+func TestNonceReservations(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), NonceReservationsFileName)
+	store := &nonceMockStore{defaultMockStore: NewDefaultMockStore(mockHeader)}
+
+	newPool := func() *TxPool {
+		t.Helper()
+
+		pool, err := newTestPool(store)
+		assert.NoError(t, err)
+
+		pool.nonceReservations, err = newNonceReservations(addr1, path)
+		assert.NoError(t, err)
+
+		return pool
+	}
+
+	pool := newPool()
+
+	_, err := pool.ReserveNonces(0)
+	assert.ErrorIs(t, err, ErrEmptyNonceReservation)
+
+	first, err := pool.ReserveNonces(3)
+	assert.NoError(t, err)
+	assert.Equal(t, NonceRange{From: 0, Count: 3}, first)
+
+	second, err := pool.ReserveNonces(2)
+	assert.NoError(t, err)
+	assert.Equal(t, NonceRange{From: 3, Count: 2}, second)
+
+	// the reserved nonces are skipped for the operator account only
+	assert.Equal(t, uint64(5), pool.GetNonce(addr1))
+	assert.Equal(t, uint64(0), pool.GetNonce(addr2))
+
+	// the reservations survive a restart
+	pool = newPool()
+
+	reserved, err := pool.NonceReservations()
+	assert.NoError(t, err)
+	assert.Equal(t, []NonceRange{first, second}, reserved)
+	assert.Equal(t, uint64(5), pool.GetNonce(addr1))
+
+	// the consumed ranges are dropped
+	atomic.StoreUint64(&store.nonce, 3)
+
+	reserved, err = pool.NonceReservations()
+	assert.NoError(t, err)
+	assert.Equal(t, []NonceRange{second}, reserved)
+
+	// the last range released is handed out again
+	assert.ErrorIs(t, pool.ReleaseNonces(7), ErrNonceReservationAbsent)
+	assert.NoError(t, pool.ReleaseNonces(3))
+	assert.Equal(t, uint64(3), pool.GetNonce(addr1))
+
+	third, err := pool.ReserveNonces(1)
+	assert.NoError(t, err)
+	assert.Equal(t, NonceRange{From: 3, Count: 1}, third)
+}
+
+func TestNonceReservations_NoOperatorAccount(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+
+	_, err = pool.ReserveNonces(1)
+	assert.ErrorIs(t, err, ErrNoOperatorAccount)
+
+	_, err = pool.NonceReservations()
+	assert.ErrorIs(t, err, ErrNoOperatorAccount)
+
+	assert.ErrorIs(t, pool.ReleaseNonces(0), ErrNoOperatorAccount)
+}
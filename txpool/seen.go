@@ -0,0 +1,23 @@
+package txpool
+
+import (
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// seenTxsCacheSize is the number of hashes of the recently seen transactions the pool remembers
+const seenTxsCacheSize = 16384
+
+// markSeen records the hash of a transaction seen by the pool, the least recently seen hash being forgotten
+// once the cache is full. It returns true if the hash was seen already
+func (p *TxPool) markSeen(hash types.Hash) bool {
+	seen, _ := p.seenTxs.ContainsOrAdd(hash, struct{}{})
+
+	return seen
+}
+
+// rawTxHash returns the hash of the transaction of the given encoding, without decoding it.
+// The hash of a transaction is the hash of its encoding, whatever its type
+func rawTxHash(raw []byte) types.Hash {
+	return types.BytesToHash(keccak.Keccak256(nil, raw))
+}
@@ -0,0 +1,104 @@
+package txpool
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCounter counts the additions to a metric
+type countingCounter struct {
+	lock  sync.Mutex
+	count float64
+}
+
+func (c *countingCounter) With(...string) metrics.Counter {
+	return c
+}
+
+func (c *countingCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.count += delta
+}
+
+func TestRawTxHash(t *testing.T) {
+	t.Parallel()
+
+	key, _ := tests.GenerateKeyAndAddr(t)
+
+	legacyTx, err := crypto.NewEIP155Signer(100).SignTx(newTx(types.ZeroAddress, 0, 1), key)
+	assert.NoError(t, err)
+
+	dynamicFeeTx := newTx(types.ZeroAddress, 0, 1)
+	dynamicFeeTx.Type = types.DynamicFeeTx
+	dynamicFeeTx.GasTipCap = big.NewInt(1)
+	dynamicFeeTx.GasFeeCap = big.NewInt(10)
+
+	dynamicFeeTx, err = crypto.NewLondonSigner(100).SignTx(dynamicFeeTx, key)
+	assert.NoError(t, err)
+
+	for _, tx := range []*types.Transaction{legacyTx, dynamicFeeTx} {
+		assert.Equal(t, tx.ComputeHash().Hash, rawTxHash(tx.MarshalRLP()))
+	}
+}
+
+func TestDropSeenGossipTx(t *testing.T) {
+	t.Parallel()
+
+	key, sender := tests.GenerateKeyAndAddr(t)
+	signer := crypto.NewEIP155Signer(100)
+
+	duplicates := &countingCounter{}
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(signer)
+
+	pool.sealing = true
+	pool.metrics = NilMetrics()
+	pool.metrics.DuplicateGossipedTxs = duplicates
+
+	signTx := func(nonce uint64) *types.Transaction {
+		t.Helper()
+
+		tx, err := signer.SignTx(newTx(types.ZeroAddress, nonce, 1), key)
+		assert.NoError(t, err)
+
+		return tx
+	}
+
+	gossipTx := func(tx *types.Transaction) {
+		pool.addGossipTx(&proto.Txn{
+			Raw: &any.Any{
+				Value: tx.MarshalRLP(),
+			},
+		}, "")
+	}
+
+	gossipedTx, localTx := signTx(1), signTx(2)
+
+	go gossipTx(gossipedTx)
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	go func() {
+		assert.NoError(t, pool.addTx(local, localTx))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	// the transactions are seen already, whether gossiped or added
+	gossipTx(gossipedTx)
+	gossipTx(localTx)
+
+	assert.Equal(t, float64(2), duplicates.count)
+	assert.Equal(t, uint64(2), pool.accounts.get(sender).enqueued.length())
+}
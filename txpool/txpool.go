@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"google.golang.org/grpc"
 
@@ -42,6 +45,7 @@ var (
 	ErrInvalidAccountState = errors.New("invalid account state")
 	ErrAlreadyKnown        = errors.New("already known")
 	ErrOversizedData       = errors.New("oversized data")
+	ErrTipAboveFeeCap      = errors.New("max priority fee per gas higher than max fee per gas")
 )
 
 // indicates origin of a transaction
@@ -69,6 +73,7 @@ func (o txOrigin) String() (s string) {
 // store interface defines State helper methods the TxPool should have access to
 type store interface {
 	Header() *types.Header
+	CalculateBaseFee(parent *types.Header) uint64
 	GetNonce(root types.Hash, addr types.Address) uint64
 	GetBalance(root types.Hash, addr types.Address) (*big.Int, error)
 	GetBlockByHash(types.Hash, bool) (*types.Block, bool)
@@ -82,6 +87,44 @@ type Config struct {
 	PriceLimit uint64
 	MaxSlots   uint64
 	Sealing    bool
+
+	// ExemptAddresses are the system senders (bridge relayer, staking manager...)
+	// not subject to the pool limits, their transactions are executed first
+	ExemptAddresses []types.Address
+
+	// LocalAddresses are the senders whose transactions are local, as the ones submitted to the node.
+	// The local transactions are never evicted and are executed before the remote ones
+	LocalAddresses []types.Address
+
+	// FutureTxTypes are the transaction types activating at a fork,
+	// their transactions are held until the fork instead of being rejected
+	FutureTxTypes []*FutureTxType
+
+	// OperatorAccount is the account of the relayer embedded with the node, which nonces are reserved for.
+	// No nonce is reserved if nil
+	OperatorAccount *types.Address
+
+	// NonceReservationsPath is the file the nonce reservations are persisted to, they aren't if empty
+	NonceReservationsPath string
+
+	// SenderBansPath is the file the sender bans are persisted to, they aren't if empty
+	SenderBansPath string
+
+	// JournalPath is the file the pending transactions are journaled to, they aren't if empty
+	JournalPath string
+
+	// JournalInterval is the interval the pending transactions are journaled at
+	JournalInterval time.Duration
+
+	// PriceBump is the percentage a transaction replacing a pending one of the same nonce
+	// has to raise its gas price by
+	PriceBump uint64
+
+	// MaxAccountTxs is the number of pending transactions an account can have, unlimited if zero
+	MaxAccountTxs uint64
+
+	// MaxEnqueued is the number of enqueued (future nonce) transactions the pool holds, unlimited if zero
+	MaxEnqueued uint64
 }
 
 /* All requests are passed to the main loop
@@ -133,19 +176,54 @@ type TxPool struct {
 	// all the primaries sorted by max gas price
 	executables *pricedQueue
 
+	// primaries of the exempt accounts, executed before the other primaries
+	exemptExecutables *pricedQueue
+
+	// primaries of the local transactions, executed before the remote primaries
+	localExecutables *pricedQueue
+
+	// senders not subject to the price limit and the pool capacity
+	exempt map[types.Address]struct{}
+
+	// senders whose transactions are local
+	locals map[types.Address]struct{}
+
+	// transactions of the future types, held until their fork
+	held *heldTxs
+
+	// nonces reserved for the operator account, nil if none is configured
+	nonceReservations *nonceReservations
+
+	// senders whose transactions are rejected
+	bans *senderBans
+
+	// journal of the pending transactions, nil if they aren't journaled
+	journal *txJournal
+
 	// lookup map keeping track of all
 	// transactions present in the pool
 	index lookupMap
 
+	// hashes of the recently seen transactions, gossiped or added,
+	// the gossiped transactions seen already being dropped before their decoding
+	seenTxs *lru.Cache
+
 	// networking stack
 	topic *network.Topic
 
 	// gauge for measuring pool capacity
 	gauge slotGauge
 
-	// priceLimit is a lower threshold for gas price
+	// priceLimit is a lower threshold for gas price, accessed atomically
 	priceLimit uint64
 
+	// priceBump is the percentage a replacement tx has to raise the gas price of the replaced tx by
+	priceBump uint64
+
+	// limits of the pending txs of an account and of the enqueued txs, none if zero
+	maxAccountTxs uint64
+	maxEnqueued   uint64
+
 	// channels on which the pool's event loop
 	// does dispatching/handling requests.
 	enqueueReqCh chan enqueueRequest
@@ -154,6 +232,9 @@ type TxPool struct {
 	// shutdown channel
 	shutdownCh chan struct{}
 
+	// per sender admission of the local transactions
+	admission *admission
+
 	// flag indicating if the current node is a sealer,
 	// and should therefore gossip transactions
 	sealing bool
@@ -179,16 +260,54 @@ func NewTxPool(
 	config *Config,
 ) (*TxPool, error) {
 	pool := &TxPool{
-		logger:      logger.Named("txpool"),
-		forks:       forks,
-		store:       store,
-		metrics:     metrics,
-		accounts:    accountsMap{},
-		executables: newPricedQueue(),
-		index:       lookupMap{all: make(map[types.Hash]*types.Transaction)},
-		gauge:       slotGauge{height: 0, max: config.MaxSlots},
-		priceLimit:  config.PriceLimit,
-		sealing:     config.Sealing,
+		logger:            logger.Named("txpool"),
+		forks:             forks,
+		store:             store,
+		metrics:           metrics,
+		accounts:          accountsMap{},
+		executables:       newPricedQueue(),
+		exemptExecutables: newPricedQueue(),
+		localExecutables:  newPricedQueue(),
+		exempt:            make(map[types.Address]struct{}, len(config.ExemptAddresses)),
+		locals:            make(map[types.Address]struct{}, len(config.LocalAddresses)),
+		held:              newHeldTxs(config.FutureTxTypes),
+		index:             newLookupMap(),
+		gauge:             slotGauge{height: 0, max: config.MaxSlots},
+		priceLimit:        config.PriceLimit,
+		priceBump:         config.PriceBump,
+		maxAccountTxs:     config.MaxAccountTxs,
+		maxEnqueued:       config.MaxEnqueued,
+		sealing:           config.Sealing,
+	}
+
+	for _, addr := range config.ExemptAddresses {
+		pool.exempt[addr] = struct{}{}
+	}
+
+	for _, addr := range config.LocalAddresses {
+		pool.locals[addr] = struct{}{}
+	}
+
+	if config.OperatorAccount != nil {
+		reservations, err := newNonceReservations(*config.OperatorAccount, config.NonceReservationsPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the nonce reservations, %w", err)
+		}
+
+		pool.nonceReservations = reservations
+	}
+
+	bans, err := newSenderBans(config.SenderBansPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the sender bans, %w", err)
+	}
+
+	pool.bans = bans
+	pool.journal = newTxJournal(config.JournalPath, config.JournalInterval)
+
+	pool.seenTxs, err = lru.New(seenTxsCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the seen transactions cache, %w", err)
 	}
 
 	// Attach the event manager
@@ -217,6 +336,10 @@ func NewTxPool(
 	pool.promoteReqCh = make(chan promoteRequest)
 	pool.shutdownCh = make(chan struct{})
 
+	pool.admission = newAdmission(defaultReorderWindow, func(tx *types.Transaction) error {
+		return pool.addTx(local, tx)
+	})
+
 	return pool, nil
 }
 
@@ -239,10 +362,18 @@ func (p *TxPool) Start() {
 			}
 		}
 	}()
+
+	if p.journal != nil {
+		p.journal.started = true
+
+		go p.runJournal()
+	}
 }
 
 // Close shuts down the pool's main loop.
+// The pending transactions are journaled before
 func (p *TxPool) Close() {
+	p.closeJournal()
 	p.eventManager.Close()
 	p.shutdownCh <- struct{}{}
 }
@@ -253,30 +384,92 @@ func (p *TxPool) SetSigner(s signer) {
 	p.signer = s
 }
 
+// SetPriceLimit sets the lowest gas price of the transactions accepted by the pool,
+// the transactions already in the pool are kept
+func (p *TxPool) SetPriceLimit(priceLimit uint64) {
+	atomic.StoreUint64(&p.priceLimit, priceLimit)
+}
+
+// SubscribeTxEvents registers a new in-process listener for the pool events of the given types.
+// The returned function cancels the subscription, which closes the event channel
+func (p *TxPool) SubscribeTxEvents(eventTypes []proto.EventType) (<-chan *proto.TxPoolEvent, func()) {
+	subscription := p.eventManager.subscribe(eventTypes)
+
+	return subscription.subscriptionChannel, func() {
+		p.eventManager.cancelSubscription(subscription.subscriptionID)
+	}
+}
+
 // AddTx adds a new transaction to the pool (sent from json-RPC/gRPC endpoints)
-// and broadcasts it to the network (if enabled).
+// as a local transaction, and broadcasts it to the network (if enabled).
 func (p *TxPool) AddTx(tx *types.Transaction) error {
-	if err := p.addTx(local, tx); err != nil {
+	if err := p.admitLocalTx(tx); err != nil {
 		p.logger.Error("failed to add tx", "err", err)
 
 		return err
 	}
 
-	// broadcast the transaction only if a topic
-	// subscription is present
-	if p.topic != nil {
-		tx := &proto.Txn{
-			Raw: &any.Any{
-				Value: tx.MarshalRLP(),
-			},
-		}
+	p.publishTx(tx)
 
-		if err := p.topic.Publish(tx); err != nil {
-			p.logger.Error("failed to topic tx", "err", err)
-		}
+	return nil
+}
+
+// publishTx broadcasts the transaction to the network,
+// only if a topic subscription is present
+func (p *TxPool) publishTx(tx *types.Transaction) {
+	if p.topic == nil {
+		return
 	}
 
-	return nil
+	msg := &proto.Txn{
+		Raw: &any.Any{
+			Value: tx.MarshalRLP(),
+		},
+	}
+
+	if err := p.topic.Publish(msg); err != nil {
+		p.logger.Error("failed to topic tx", "err", err)
+	}
+}
+
+// admitLocalTx adds the local transaction once the preceding transactions
+// of its sender are admitted, or once the reorder window expires
+func (p *TxPool) admitLocalTx(tx *types.Transaction) error {
+	from, err := p.signer.Sender(tx)
+	if err != nil {
+		// rejected by the validation
+		return p.addTx(local, tx)
+	}
+
+	return p.admission.admit(from, tx, p.nextNonce(from))
+}
+
+// nextNonce returns the nonce of the next transaction of the account
+// expected by the pool
+func (p *TxPool) nextNonce(addr types.Address) uint64 {
+	if account := p.accounts.get(addr); account != nil {
+		return account.getNonce()
+	}
+
+	return p.store.GetNonce(p.store.Header().StateRoot, addr)
+}
+
+// IsExempt returns true if the transactions of the given sender
+// are not subject to the pool limits
+func (p *TxPool) IsExempt(addr types.Address) bool {
+	_, ok := p.exempt[addr]
+
+	return ok
+}
+
+// IsLocal returns true if the transaction of the pool was submitted to the node,
+// or is sent by a local sender
+func (p *TxPool) IsLocal(tx *types.Transaction) bool {
+	if _, ok := p.locals[tx.From]; ok {
+		return true
+	}
+
+	return p.index.isLocal(tx.Hash)
 }
 
 // Prepare generates all the transactions
@@ -287,17 +480,27 @@ func (p *TxPool) Prepare() {
 		p.executables.clear()
 	}
 
+	if p.exemptExecutables.length() != 0 {
+		p.exemptExecutables.clear()
+	}
+
+	if p.localExecutables.length() != 0 {
+		p.localExecutables.clear()
+	}
+
 	// fetch primary from each account
 	primaries := p.accounts.getPrimaries()
 
 	// push primaries to the executables queue
 	for _, tx := range primaries {
-		p.executables.push(tx)
+		p.pushExecutable(tx)
 	}
 }
 
 // Peek returns the best-price selected
 // transaction ready for execution.
+// The transactions of the exempt accounts are returned first,
+// then the local transactions.
 func (p *TxPool) Peek() *types.Transaction {
 	// Popping the executables queue
 	// does not remove the actual tx
@@ -305,9 +508,29 @@ func (p *TxPool) Peek() *types.Transaction {
 	// The executables queue just provides
 	// insight into which account has the
 	// highest priced tx (head of promoted queue)
+	if tx := p.exemptExecutables.pop(); tx != nil {
+		return tx
+	}
+
+	if tx := p.localExecutables.pop(); tx != nil {
+		return tx
+	}
+
 	return p.executables.pop()
 }
 
+// pushExecutable pushes the primary to the executables queue of its kind
+func (p *TxPool) pushExecutable(tx *types.Transaction) {
+	switch {
+	case p.IsExempt(tx.From):
+		p.exemptExecutables.push(tx)
+	case p.IsLocal(tx):
+		p.localExecutables.push(tx)
+	default:
+		p.executables.push(tx)
+	}
+}
+
 // Pop removes the given transaction from the
 // associated promoted queue (account).
 // Will update executables with the next primary
@@ -319,6 +542,16 @@ func (p *TxPool) Pop(tx *types.Transaction) {
 	account.promoted.lock(true)
 	defer account.promoted.unlock()
 
+	// the executed tx could have been replaced after it was prepared:
+	// the nonce is spent now, so the replacement is discarded instead
+	if head := account.promoted.peek(); head != nil &&
+		head.Nonce == tx.Nonce && head.Hash != tx.Hash {
+		p.index.remove(head)
+		p.eventManager.signalEvent(proto.EventType_DROPPED, head.Hash)
+
+		tx = head
+	}
+
 	// pop the top most promoted tx
 	account.promoted.pop()
 
@@ -333,7 +566,7 @@ func (p *TxPool) Pop(tx *types.Transaction) {
 
 	// update executables
 	if tx := account.promoted.peek(); tx != nil {
-		p.executables.push(tx)
+		p.pushExecutable(tx)
 	}
 }
 
@@ -420,6 +653,16 @@ func (p *TxPool) ResetWithHeaders(headers ...*types.Header) {
 	// process the txs in the event
 	// to make sure the pool is up-to-date
 	p.processEvent(e)
+
+	p.publishHeldTxs()
+}
+
+// ResetWithReorg processes a chain reorganization. The transactions of the
+// removed blocks, not included in the new ones, are returned to the pool
+func (p *TxPool) ResetWithReorg(event *blockchain.Event) {
+	p.processEvent(event)
+
+	p.publishHeldTxs()
 }
 
 // processEvent collects the latest nonces for each account containted
@@ -460,6 +703,10 @@ func (p *TxPool) processEvent(event *blockchain.Event) {
 		for _, tx := range block.Transactions {
 			var err error
 
+			// Legacy reorg logic //
+			// Update the addTxns in case of reorgs
+			delete(oldTxs, tx.Hash)
+
 			addr := tx.From
 			if addr == types.ZeroAddress {
 				// From field is not set, extract the signer
@@ -482,10 +729,6 @@ func (p *TxPool) processEvent(event *blockchain.Event) {
 
 			// update the result map
 			stateNonces[addr] = latestNonce
-
-			// Legacy reorg logic //
-			// Update the addTxns in case of reorgs
-			delete(oldTxs, tx.Hash)
 		}
 	}
 
@@ -517,6 +760,20 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 		return ErrNegativeValue
 	}
 
+	// The base fee of the next block, zero before the London fork
+	baseFee := p.store.CalculateBaseFee(p.store.Header())
+
+	// Reject the dynamic fee transactions before the London fork, and the ones tipping more than their fee cap
+	if tx.Type == types.DynamicFeeTx {
+		if baseFee == 0 {
+			return fmt.Errorf("%w: 0x%x", types.ErrTxTypeNotSupported, byte(tx.Type))
+		}
+
+		if tx.GasTipCap.Cmp(tx.GasFeeCap) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
 	// Check if the transaction is signed properly
 
 	// Extract the sender
@@ -537,11 +794,23 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 		tx.From = from
 	}
 
+	// Reject the transactions of the banned senders
+	if p.bans.isBanned(tx.From) {
+		p.metrics.BannedSenderTxs.Add(1)
+
+		return ErrSenderBanned
+	}
+
 	// Reject underpriced transactions
-	if tx.IsUnderpriced(p.priceLimit) {
+	if tx.IsUnderpriced(atomic.LoadUint64(&p.priceLimit)) && !p.IsExempt(tx.From) {
 		return ErrUnderpriced
 	}
 
+	// Reject the transactions which can't pay the base fee of the next block, whether exempt or not
+	if tx.IsUnderpriced(baseFee) {
+		return fmt.Errorf("%w: max fee per gas below the base fee %d", ErrUnderpriced, baseFee)
+	}
+
 	// Grab the state root for the latest block
 	stateRoot := p.store.Header().StateRoot
 
@@ -595,18 +864,34 @@ func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
 		return err
 	}
 
-	// check for overflow
-	if p.gauge.read()+slotsRequired(tx) > p.gauge.max {
-		return ErrTxPoolOverflow
-	}
-
 	tx.ComputeHash()
 
+	// the transactions submitted to the node and the ones of the local senders are local
+	_, isLocal := p.locals[tx.From]
+	isLocal = isLocal || origin == local
+
 	// add to index
-	if ok := p.index.add(tx); !ok {
+	if ok := p.index.add(tx, isLocal); !ok {
 		return ErrAlreadyKnown
 	}
 
+	// the gossiped copies of the transaction are dropped right away
+	p.markSeen(tx.Hash)
+
+	// reject an underpriced replacement of a pending tx
+	if err := p.checkReplacement(tx); err != nil {
+		p.index.remove(tx)
+
+		return err
+	}
+
+	// check the limits, the exempt accounts and the local transactions are never crowded out
+	if err := p.makeRoom(tx); err != nil {
+		p.index.remove(tx)
+
+		return err
+	}
+
 	// initialize account for this address once
 	if !p.accounts.exists(tx.From) {
 		p.createAccountOnce(tx.From)
@@ -631,7 +916,8 @@ func (p *TxPool) handleEnqueueRequest(req enqueueRequest) {
 	account := p.accounts.get(addr)
 
 	// enqueue tx
-	if err := account.enqueue(tx); err != nil {
+	replaced, err := account.enqueue(tx, p.priceBump)
+	if err != nil {
 		p.logger.Error("enqueue request", "err", err)
 
 		p.index.remove(tx)
@@ -639,6 +925,13 @@ func (p *TxPool) handleEnqueueRequest(req enqueueRequest) {
 		return
 	}
 
+	if replaced != nil {
+		// the replacement takes the place of the replaced tx, it isn't enqueued
+		p.replaceTx(replaced, tx)
+
+		return
+	}
+
 	p.logger.Debug("enqueue request", "hash", tx.Hash.String())
 
 	p.gauge.increase(slotsRequired(tx))
@@ -691,11 +984,29 @@ func (p *TxPool) addGossipTx(obj interface{}, _ peer.ID) {
 		return
 	}
 
+	p.metrics.GossipedTxs.Add(1)
+
+	// many peers relay the same transactions, the ones seen already are neither decoded nor validated again
+	if p.markSeen(rawTxHash(raw.Raw.Value)) {
+		p.metrics.DuplicateGossipedTxs.Add(1)
+
+		return
+	}
+
 	tx := new(types.Transaction)
 
 	// decode tx
 	if err := tx.UnmarshalRLP(raw.Raw.Value); err != nil {
-		p.logger.Error("failed to decode broadcast tx", "err", err)
+		if !errors.Is(err, types.ErrTxTypeNotSupported) {
+			p.logger.Error("failed to decode broadcast tx", "err", err)
+
+			return
+		}
+
+		// the peer may support a type this node doesn't yet
+		if _, err := p.HoldTx(raw.Raw.Value); err != nil && !errors.Is(err, ErrAlreadyKnown) {
+			p.logger.Debug("rejecting broadcast tx", "err", err)
+		}
 
 		return
 	}
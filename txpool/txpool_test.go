@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/golang/protobuf/ptypes/any"
@@ -193,6 +195,22 @@ func TestAddTxErrors(t *testing.T) {
 		)
 	})
 
+	t.Run("ErrSenderBanned", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		_, err := pool.AddSenderBan(defaultAddr, 0, "spam")
+		assert.NoError(t, err)
+
+		tx := newTx(defaultAddr, 0, 1)
+		tx = signTx(tx)
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx),
+			ErrSenderBanned,
+		)
+	})
+
 	t.Run("ErrUnderpriced", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
@@ -207,6 +225,49 @@ func TestAddTxErrors(t *testing.T) {
 		)
 	})
 
+	t.Run("dynamic fee transactions", func(t *testing.T) {
+		t.Parallel()
+
+		londonSigner := crypto.NewLondonSigner(100)
+
+		setupLondonPool := func(baseFee uint64) *TxPool {
+			pool := setupPool()
+			pool.SetSigner(londonSigner)
+			pool.store = NewDefaultMockStore(&types.Header{GasLimit: mockHeader.GasLimit, BaseFee: baseFee})
+
+			return pool
+		}
+
+		signDynamicFeeTx := func(tipCap, feeCap uint64) *types.Transaction {
+			tx := newTx(defaultAddr, 0, 1)
+			tx.Type = types.DynamicFeeTx
+			tx.GasTipCap = new(big.Int).SetUint64(tipCap)
+			tx.GasFeeCap = new(big.Int).SetUint64(feeCap)
+
+			signedTx, err := londonSigner.SignTx(tx, defaultKey)
+			assert.NoError(t, err)
+
+			return signedTx
+		}
+
+		// the type isn't supported before the London fork
+		assert.ErrorIs(t,
+			setupLondonPool(0).addTx(local, signDynamicFeeTx(1, 10)),
+			types.ErrTxTypeNotSupported,
+		)
+
+		assert.ErrorIs(t,
+			setupLondonPool(5).addTx(local, signDynamicFeeTx(11, 10)),
+			ErrTipAboveFeeCap,
+		)
+
+		// the fee cap doesn't cover the base fee of the next block
+		assert.ErrorIs(t,
+			setupLondonPool(50).addTx(local, signDynamicFeeTx(1, 10)),
+			ErrUnderpriced,
+		)
+	})
+
 	t.Run("ErrInvalidAccountState", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
@@ -1741,6 +1802,182 @@ func TestExecutablesOrder(t *testing.T) {
 	}
 }
 
+func TestExemptAddresses(t *testing.T) {
+	t.Parallel()
+
+	setupPool := func() *TxPool {
+		pool, err := NewTxPool(
+			hclog.NewNullLogger(),
+			forks.At(0),
+			defaultMockStore{DefaultHeader: mockHeader},
+			nil,
+			nil,
+			nilMetrics,
+			&Config{
+				PriceLimit:      defaultPriceLimit,
+				MaxSlots:        defaultMaxSlots,
+				ExemptAddresses: []types.Address{addr1},
+			},
+		)
+		assert.NoError(t, err)
+
+		pool.SetSigner(&mockSigner{})
+
+		return pool
+	}
+
+	t.Run("exempt sender is not subject to the price limit", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool()
+		pool.priceLimit = 1000000
+
+		go func() {
+			<-pool.enqueueReqCh
+		}()
+
+		assert.NoError(t, pool.addTx(local, newTx(addr1, 0, 1)))
+		assert.ErrorIs(t, pool.addTx(local, newTx(addr2, 0, 1)), ErrUnderpriced)
+	})
+
+	t.Run("exempt sender is not subject to the pool capacity", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool()
+
+		// fill the pool
+		pool.gauge.increase(defaultMaxSlots)
+
+		go func() {
+			<-pool.enqueueReqCh
+		}()
+
+		assert.NoError(t, pool.addTx(local, newTx(addr1, 0, 1)))
+		assert.ErrorIs(t, pool.addTx(local, newTx(addr2, 0, 1)), ErrTxPoolOverflow)
+	})
+
+	t.Run("exempt transactions are executed first", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool()
+
+		pool.Start()
+		defer pool.Close()
+
+		subscription := pool.eventManager.subscribe(
+			[]proto.EventType{proto.EventType_PROMOTED},
+		)
+
+		txs := []*types.Transaction{
+			newTx(addr1, 0, 1),
+			newTx(addr1, 1, 1),
+			newTx(addr2, 0, 1),
+			newTx(addr3, 0, 1),
+		}
+
+		// the exempt sender pays the lowest price
+		txs[2].GasPrice.SetUint64(10)
+		txs[3].GasPrice.SetUint64(20)
+
+		for _, tx := range txs {
+			assert.NoError(t, pool.addTx(local, tx))
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancelFn()
+
+		assert.Len(t, waitForEvents(ctx, subscription, len(txs)), len(txs))
+
+		pool.Prepare()
+
+		var senders []types.Address
+
+		for {
+			tx := pool.Peek()
+			if tx == nil {
+				break
+			}
+
+			pool.Pop(tx)
+			senders = append(senders, tx.From)
+		}
+
+		assert.Equal(t, []types.Address{addr1, addr1, addr3, addr2}, senders)
+	})
+}
+
+func TestLocalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, err := NewTxPool(
+		hclog.NewNullLogger(),
+		forks.At(0),
+		defaultMockStore{DefaultHeader: mockHeader},
+		nil,
+		nil,
+		nilMetrics,
+		&Config{
+			PriceLimit:     defaultPriceLimit,
+			MaxSlots:       defaultMaxSlots,
+			LocalAddresses: []types.Address{addr1},
+		},
+	)
+	assert.NoError(t, err)
+
+	pool.SetSigner(&mockSigner{})
+
+	pool.Start()
+	defer pool.Close()
+
+	subscription := pool.eventManager.subscribe(
+		[]proto.EventType{proto.EventType_PROMOTED},
+	)
+
+	txs := []struct {
+		origin txOrigin
+		tx     *types.Transaction
+	}{
+		// gossiped, but the sender is local
+		{gossip, newTx(addr1, 0, 1)},
+		{local, newTx(addr2, 0, 1)},
+		{gossip, newTx(addr3, 0, 1)},
+	}
+
+	// the remote transaction pays the highest price
+	txs[1].tx.GasPrice.SetUint64(10)
+	txs[2].tx.GasPrice.SetUint64(20)
+
+	for _, tx := range txs {
+		assert.NoError(t, pool.addTx(tx.origin, tx.tx))
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+
+	assert.Len(t, waitForEvents(ctx, subscription, len(txs)), len(txs))
+
+	assert.True(t, pool.IsLocal(txs[0].tx))
+	assert.True(t, pool.IsLocal(txs[1].tx))
+	assert.False(t, pool.IsLocal(txs[2].tx))
+
+	// the local transactions are executed first
+	pool.Prepare()
+
+	var senders []types.Address
+
+	for {
+		tx := pool.Peek()
+		if tx == nil {
+			break
+		}
+
+		pool.Pop(tx)
+		senders = append(senders, tx.From)
+	}
+
+	assert.Equal(t, []types.Address{addr2, addr1, addr3}, senders)
+}
+
 type status int
 
 // Status of a transaction resulted
@@ -2169,3 +2406,108 @@ func TestGetTxs(t *testing.T) {
 		})
 	}
 }
+
+type mockPackTransition struct {
+	// errors returned when writing the transactions, by hash
+	errors map[types.Hash]error
+	// maxWrites is the number of successful writes after which the block is full
+	maxWrites int
+	written   []*types.Transaction
+}
+
+func (m *mockPackTransition) Write(tx *types.Transaction) error {
+	if err, ok := m.errors[tx.Hash]; ok {
+		return err
+	}
+
+	if len(m.written) == m.maxWrites {
+		return state.NewGasLimitReachedTransitionApplicationError(errors.New("gas limit reached"))
+	}
+
+	m.written = append(m.written, tx)
+
+	return nil
+}
+
+func TestPack(t *testing.T) {
+	t.Parallel()
+
+	newPricedTx := func(addr types.Address, nonce, gasPrice uint64) *types.Transaction {
+		tx := newTx(addr, nonce, 1)
+		tx.GasPrice.SetUint64(gasPrice)
+		tx.ComputeHash()
+
+		return tx
+	}
+
+	var (
+		txs1 = []*types.Transaction{
+			newPricedTx(addr1, 0, 3),
+			newPricedTx(addr1, 1, 3),
+			newPricedTx(addr1, 2, 3),
+		}
+		txs2 = []*types.Transaction{
+			newPricedTx(addr2, 0, 5),
+			newPricedTx(addr2, 1, 5),
+			newPricedTx(addr2, 2, 5),
+		}
+		txs3 = []*types.Transaction{
+			newPricedTx(addr3, 0, 4),
+		}
+	)
+
+	// exceeds the block gas limit, the account is skipped
+	txs4 := []*types.Transaction{
+		newPricedTx(addr4, 0, 10),
+	}
+	txs4[0].Gas = validGasLimit + 1
+	txs4[0].ComputeHash()
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.Start()
+	defer pool.Close()
+
+	subscription := pool.eventManager.subscribe(
+		[]proto.EventType{proto.EventType_PROMOTED},
+	)
+
+	for _, txs := range [][]*types.Transaction{txs1, txs2, txs3, txs4} {
+		for _, tx := range txs {
+			assert.NoError(t, pool.addTx(local, tx))
+		}
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+
+	assert.Len(t, waitForEvents(ctx, subscription, 8), 8)
+
+	t.Run("should pack in the block building order", func(t *testing.T) {
+		t.Parallel()
+
+		transition := &mockPackTransition{
+			errors: map[types.Hash]error{
+				// the rest of the account is skipped
+				txs2[1].Hash: state.NewTransitionApplicationError(errors.New("recoverable"), true),
+			},
+			maxWrites: 4,
+		}
+
+		included := pool.Pack(validGasLimit, transition)
+
+		assert.Equal(t, []*types.Transaction{txs2[0], txs3[0], txs1[0], txs1[1]}, included)
+		assert.Equal(t, included, transition.written)
+	})
+
+	t.Run("should not modify the pool", func(t *testing.T) {
+		t.Parallel()
+
+		pool.Pack(validGasLimit, &mockPackTransition{maxWrites: 8})
+
+		assert.Equal(t, uint64(8), pool.accounts.promoted())
+		assert.Equal(t, uint(0), pool.accounts.get(addr2).demotions)
+	})
+}
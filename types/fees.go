@@ -0,0 +1,55 @@
+package types
+
+import "math/big"
+
+// Fees are the transaction fees paid in a block or a range of blocks, and their distribution
+type Fees struct {
+	// Collected are the fees paid by the senders, the gas used times the effective gas price.
+	// The base fee part of it (EIP-1559) is burned
+	Collected *big.Int
+
+	// Validators are the fees credited to the block creators
+	Validators *big.Int
+
+	// ContractCreators are the fees credited to the creators of the called contracts
+	ContractCreators *big.Int
+}
+
+// NewFees returns zero fees
+func NewFees() *Fees {
+	return &Fees{
+		Collected:        new(big.Int),
+		Validators:       new(big.Int),
+		ContractCreators: new(big.Int),
+	}
+}
+
+// Burned returns the fees collected but not credited to any account
+func (f *Fees) Burned() *big.Int {
+	burned := new(big.Int).Sub(f.Collected, f.Validators)
+
+	return burned.Sub(burned, f.ContractCreators)
+}
+
+// Add adds the given fees
+func (f *Fees) Add(o *Fees) {
+	f.Collected.Add(f.Collected, o.Collected)
+	f.Validators.Add(f.Validators, o.Validators)
+	f.ContractCreators.Add(f.ContractCreators, o.ContractCreators)
+}
+
+// Sub subtracts the given fees
+func (f *Fees) Sub(o *Fees) {
+	f.Collected.Sub(f.Collected, o.Collected)
+	f.Validators.Sub(f.Validators, o.Validators)
+	f.ContractCreators.Sub(f.ContractCreators, o.ContractCreators)
+}
+
+// Copy returns a copy of the fees
+func (f *Fees) Copy() *Fees {
+	return &Fees{
+		Collected:        new(big.Int).Set(f.Collected),
+		Validators:       new(big.Int).Set(f.Validators),
+		ContractCreators: new(big.Int).Set(f.ContractCreators),
+	}
+}
@@ -30,6 +30,12 @@ type Header struct {
 	MixHash      Hash    `json:"mixHash"`
 	Nonce        Nonce   `json:"nonce"`
 	Hash         Hash    `json:"hash"`
+
+	// BaseFee is the EIP-1559 base fee of the block, zero before the London fork
+	BaseFee uint64 `json:"baseFeePerGas"`
+
+	// London is whether the block is from the London fork, its base fee being encoded even if zero
+	London bool `json:"-"`
 }
 
 // headerJSON represents a block header used for json calls
@@ -50,6 +56,7 @@ type headerJSON struct {
 	MixHash      Hash    `json:"mixHash"`
 	Nonce        Nonce   `json:"nonce"`
 	Hash         Hash    `json:"hash"`
+	BaseFee      string  `json:"baseFeePerGas,omitempty"`
 }
 
 func (h *Header) MarshalJSON() ([]byte, error) {
@@ -74,6 +81,10 @@ func (h *Header) MarshalJSON() ([]byte, error) {
 	header.Timestamp = hex.EncodeUint64(h.Timestamp)
 	header.ExtraData = hex.EncodeToHex(h.ExtraData)
 
+	if h.London {
+		header.BaseFee = hex.EncodeUint64(h.BaseFee)
+	}
+
 	return json.Marshal(&header)
 }
 
@@ -120,6 +131,14 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		return err
 	}
 
+	if header.BaseFee != "" {
+		if h.BaseFee, err = hex.DecodeUint64(header.BaseFee); err != nil {
+			return err
+		}
+
+		h.London = true
+	}
+
 	return nil
 }
 
@@ -198,6 +217,8 @@ func (h *Header) Copy() *Header {
 		GasLimit:     h.GasLimit,
 		GasUsed:      h.GasUsed,
 		Timestamp:    h.Timestamp,
+		BaseFee:      h.BaseFee,
+		London:       h.London,
 	}
 
 	newHeader.ExtraData = make([]byte, len(h.ExtraData))
@@ -28,6 +28,9 @@ type Receipt struct {
 	Logs              []*Log
 	Status            *ReceiptStatus
 
+	// TransactionType is the type of the transaction, the receipts of the typed transactions having an envelope
+	TransactionType TxType
+
 	// context fields
 	GasUsed         uint64
 	ContractAddress *Address
@@ -126,6 +129,33 @@ func (b *Bloom) setEncode(hasher *keccak.Keccak, h []byte) {
 	}
 }
 
+// BloomBitLength is the number of bits of a bloom filter
+const BloomBitLength = 8 * BloomByteLength
+
+// BloomBits returns the positions of the bits the data sets in a bloom filter
+func BloomBits(data []byte) [3]uint {
+	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
+
+	hasher.Reset()
+	//nolint
+	hasher.Write(data)
+	buf := hasher.Read()
+
+	var bits [3]uint
+
+	for i := 0; i < 6; i += 2 {
+		bits[i/2] = (uint(buf[i+1]) + (uint(buf[i]) << 8)) & (BloomBitLength - 1)
+	}
+
+	return bits
+}
+
+// HasBit checks if the bit at the given position, as returned by BloomBits, is set
+func (b *Bloom) HasBit(bit uint) bool {
+	return b[BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0
+}
+
 // IsLogInBloom checks if the log has a possible presence in the bloom filter
 func (b *Bloom) IsLogInBloom(log *Log) bool {
 	hasher := keccak.DefaultKeccakPool.Get()
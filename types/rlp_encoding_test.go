@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
 )
 
 type codec interface {
@@ -65,6 +66,24 @@ func TestRLPMarshall_And_Unmarshall_Transaction(t *testing.T) {
 	}
 }
 
+func TestRLPUnmarshal_TypedTransaction(t *testing.T) {
+	t.Parallel()
+
+	legacy := (&Transaction{GasPrice: big.NewInt(1), Value: big.NewInt(1)}).MarshalRLP()
+
+	_, ok := TxEnvelopeType(legacy)
+	assert.False(t, ok)
+
+	// an access list transaction envelope (EIP-2930)
+	typed := append([]byte{0x01}, legacy...)
+
+	txType, ok := TxEnvelopeType(typed)
+	assert.True(t, ok)
+	assert.Equal(t, byte(0x01), txType)
+
+	assert.ErrorIs(t, new(Transaction).UnmarshalRLP(typed), ErrTxTypeNotSupported)
+}
+
 func TestRLPStorage_Marshall_And_Unmarshall_Receipt(t *testing.T) {
 	addr := StringToAddress("11")
 	hash := StringToHash("10")
@@ -129,3 +148,134 @@ func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	assert.NoError(t, h2.UnmarshalRLP(data))
 	assert.Equal(t, h.Hash, h2.Hash)
 }
+
+func TestRLPMarshall_And_Unmarshall_DynamicFeeTransaction(t *testing.T) {
+	t.Parallel()
+
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:      DynamicFeeTx,
+		ChainID:   big.NewInt(100),
+		Nonce:     1,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(11),
+		Gas:       11,
+		To:        &addrTo,
+		Value:     big.NewInt(1),
+		Input:     []byte{1, 2},
+		V:         big.NewInt(1),
+		S:         big.NewInt(26),
+		R:         big.NewInt(27),
+	}
+	txn.ComputeHash()
+
+	envelope := txn.MarshalRLP()
+
+	txType, ok := TxEnvelopeType(envelope)
+	assert.True(t, ok)
+	assert.Equal(t, byte(DynamicFeeTx), txType)
+
+	unmarshalledTxn := new(Transaction)
+	assert.NoError(t, unmarshalledTxn.UnmarshalRLP(envelope))
+
+	// the hash is the hash of the envelope, and the fee cap stands for the gas price
+	assert.Equal(t, txn.Hash, unmarshalledTxn.Hash)
+	assert.Equal(t, txn.GasFeeCap, unmarshalledTxn.GasPrice)
+	assert.Equal(t, envelope, unmarshalledTxn.MarshalRLP())
+
+	// the transaction is the bytes of its envelope in the lists
+	block := &Block{
+		Header:       &Header{BaseFee: 7, London: true},
+		Transactions: []*Transaction{txn},
+	}
+
+	unmarshalledBlock := new(Block)
+	assert.NoError(t, unmarshalledBlock.UnmarshalRLP(block.MarshalRLP()))
+	assert.Equal(t, uint64(7), unmarshalledBlock.Header.BaseFee)
+	assert.Equal(t, txn.Hash, unmarshalledBlock.Transactions[0].Hash)
+
+	unmarshalledBody := new(Body)
+	assert.NoError(t, unmarshalledBody.UnmarshalRLP(block.Body().MarshalRLPTo(nil)))
+	assert.Equal(t, envelope, unmarshalledBody.Transactions[0].MarshalRLP())
+}
+
+func TestRLPUnmarshal_DynamicFeeTransaction_AccessList(t *testing.T) {
+	t.Parallel()
+
+	ar := &fastrlp.Arena{}
+
+	// an access list with an address and no storage keys
+	entry := ar.NewArray()
+	entry.Set(ar.NewBytes(StringToAddress("1").Bytes()))
+	entry.Set(ar.NewNullArray())
+
+	accessList := ar.NewArray()
+	accessList.Set(entry)
+
+	vv := ar.NewArray()
+	for i := 0; i < 8; i++ {
+		vv.Set(ar.NewUint(1))
+	}
+
+	vv.Set(accessList)
+
+	for i := 0; i < 3; i++ {
+		vv.Set(ar.NewUint(1))
+	}
+
+	envelope := vv.MarshalTo([]byte{byte(DynamicFeeTx)})
+
+	assert.ErrorIs(t, new(Transaction).UnmarshalRLP(envelope), ErrAccessListNotSupported)
+}
+
+func TestRLPStorage_Marshall_And_Unmarshall_TypedReceipt(t *testing.T) {
+	t.Parallel()
+
+	receipt := &Receipt{
+		CumulativeGasUsed: 10,
+		GasUsed:           100,
+		TxHash:            StringToHash("10"),
+		TransactionType:   DynamicFeeTx,
+	}
+	receipt.SetStatus(ReceiptSuccess)
+
+	envelope := receipt.MarshalRLP()
+	assert.Equal(t, byte(DynamicFeeTx), envelope[0])
+
+	unmarshalledReceipt := new(Receipt)
+	assert.NoError(t, unmarshalledReceipt.UnmarshalStoreRLP(receipt.MarshalStoreRLPTo(nil)))
+	assert.Exactly(t, receipt, unmarshalledReceipt)
+
+	receipts := Receipts{receipt}
+
+	unmarshalledReceipts := Receipts{}
+	assert.NoError(t, unmarshalledReceipts.UnmarshalRLP(receipts.MarshalRLPTo(nil)))
+	assert.Equal(t, envelope, unmarshalledReceipts[0].MarshalRLP())
+}
+
+func TestRLPUnmarshal_Header_BaseFee(t *testing.T) {
+	t.Parallel()
+
+	// the base fee is only encoded from the London fork, even if it is zero
+	legacy := &Header{}
+	london := &Header{BaseFee: 1000000000, London: true}
+	zero := &Header{London: true}
+
+	assert.NotEqual(t, len(legacy.MarshalRLP()), len(london.MarshalRLP()))
+	assert.NotEqual(t, legacy.MarshalRLP(), zero.MarshalRLP())
+
+	h := new(Header)
+	assert.NoError(t, h.UnmarshalRLP(london.MarshalRLP()))
+	assert.Equal(t, london.BaseFee, h.BaseFee)
+	assert.True(t, h.London)
+
+	assert.NoError(t, h.UnmarshalRLP(zero.MarshalRLP()))
+	assert.Zero(t, h.BaseFee)
+	assert.True(t, h.London)
+	assert.Equal(t, zero.MarshalRLP(), h.MarshalRLP())
+
+	assert.NoError(t, h.UnmarshalRLP(legacy.MarshalRLP()))
+	assert.Zero(t, h.BaseFee)
+	assert.False(t, h.London)
+	assert.Equal(t, legacy.MarshalRLP(), h.MarshalRLP())
+}
@@ -83,6 +83,11 @@ func (h *Header) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBytes(h.MixHash.Bytes()))
 	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
 
+	// the base fee is only part of the headers from the London fork
+	if h.London {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
 	return vv
 }
 
@@ -103,12 +108,27 @@ func (r *Receipt) MarshalRLP() []byte {
 	return r.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the receipt to RLP, or to its envelope (EIP-2718) for the typed transactions
 func (r *Receipt) MarshalRLPTo(dst []byte) []byte {
-	return MarshalRLPTo(r.MarshalRLPWith, dst)
+	if r.TransactionType != LegacyTx {
+		dst = append(dst, byte(r.TransactionType))
+	}
+
+	return MarshalRLPTo(r.marshalPayloadWith, dst)
 }
 
-// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena
+// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena.
+// The receipts of the typed transactions are marshaled as the bytes of their envelope
 func (r *Receipt) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
+	if r.TransactionType != LegacyTx {
+		return a.NewCopyBytes(r.MarshalRLP())
+	}
+
+	return r.marshalPayloadWith(a)
+}
+
+// marshalPayloadWith marshals the fields of the receipt, without envelope
+func (r *Receipt) marshalPayloadWith(a *fastrlp.Arena) *fastrlp.Value {
 	vv := a.NewArray()
 	if r.Status != nil {
 		vv.Set(a.NewUint(uint64(*r.Status)))
@@ -158,12 +178,24 @@ func (t *Transaction) MarshalRLP() []byte {
 	return t.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the transaction to RLP, or to its envelope (EIP-2718) for the typed transactions
 func (t *Transaction) MarshalRLPTo(dst []byte) []byte {
+	if t.Type != LegacyTx {
+		dst = append(dst, byte(t.Type))
+
+		return MarshalRLPTo(t.marshalDynamicFeeRLPWith, dst)
+	}
+
 	return MarshalRLPTo(t.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
+// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena.
+// The typed transactions are marshaled as the bytes of their envelope, as in the lists of a block
 func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if t.Type != LegacyTx {
+		return arena.NewCopyBytes(t.MarshalRLP())
+	}
+
 	vv := arena.NewArray()
 
 	vv.Set(arena.NewUint(t.Nonce))
@@ -187,3 +219,47 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	return vv
 }
+
+// marshalDynamicFeeRLPWith marshals the payload of the dynamic fee transaction envelope (EIP-1559)
+func (t *Transaction) marshalDynamicFeeRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := t.marshalDynamicFeeUnsignedWith(arena)
+
+	// signature values
+	vv.Set(arena.NewBigInt(t.V))
+	vv.Set(arena.NewBigInt(t.R))
+	vv.Set(arena.NewBigInt(t.S))
+
+	return vv
+}
+
+// marshalDynamicFeeUnsignedWith marshals the fields of the dynamic fee transaction covered by its signature
+func (t *Transaction) marshalDynamicFeeUnsignedWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBigInt(t.ChainID))
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(t.GasTipCap))
+	vv.Set(arena.NewBigInt(t.GasFeeCap))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+
+	// access list, always empty
+	vv.Set(arena.NewNullArray())
+
+	return vv
+}
+
+// MarshalDynamicFeeSigningRLP returns the payload signed by the sender of the dynamic fee transaction,
+// its envelope without the signature values
+func (t *Transaction) MarshalDynamicFeeSigningRLP() []byte {
+	return MarshalRLPTo(t.marshalDynamicFeeUnsignedWith, []byte{byte(DynamicFeeTx)})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -165,6 +166,16 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	h.SetNonce(nonce)
 
+	// baseFee, from the London fork
+	h.BaseFee = 0
+	h.London = len(elems) > 15
+
+	if h.London {
+		if h.BaseFee, err = elems[15].GetUint64(); err != nil {
+			return err
+		}
+	}
+
 	// compute the hash after the decoding
 	h.ComputeHash()
 
@@ -193,12 +204,38 @@ func (r *Receipts) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Receipt in RLP format, or from its envelope (EIP-2718) for the typed transactions
 func (r *Receipt) UnmarshalRLP(input []byte) error {
+	if txType, ok := TxEnvelopeType(input); ok {
+		if TxType(txType) != DynamicFeeTx {
+			return fmt.Errorf("%w: 0x%x", ErrTxTypeNotSupported, txType)
+		}
+
+		if err := UnmarshalRlp(r.UnmarshalRLPFrom, input[1:]); err != nil {
+			return err
+		}
+
+		r.TransactionType = TxType(txType)
+
+		return nil
+	}
+
 	return UnmarshalRlp(r.UnmarshalRLPFrom, input)
 }
 
-// UnmarshalRLP unmarshals a Receipt in RLP format
+// UnmarshalRLPFrom unmarshals a Receipt in RLP format,
+// the receipts of the typed transactions being the bytes of their envelope
 func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		envelope, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		// the envelope is decoded with its own parser, not to overwrite the values of the enclosing list
+		return r.UnmarshalRLP(append([]byte{}, envelope...))
+	}
+
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -288,17 +325,45 @@ func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a legacy transaction in RLP format, or a typed transaction from its envelope (EIP-2718)
 func (t *Transaction) UnmarshalRLP(input []byte) error {
+	if txType, ok := TxEnvelopeType(input); ok {
+		if TxType(txType) != DynamicFeeTx {
+			return fmt.Errorf("%w: 0x%x", ErrTxTypeNotSupported, txType)
+		}
+
+		if err := UnmarshalRlp(t.unmarshalDynamicFeeRLPFrom, input[1:]); err != nil {
+			return err
+		}
+
+		copy(t.Hash[:], keccak.Keccak256(nil, input))
+
+		return nil
+	}
+
 	return UnmarshalRlp(t.UnmarshalRLPFrom, input)
 }
 
-// UnmarshalRLPFrom unmarshals a Transaction in RLP format
+// UnmarshalRLPFrom unmarshals a Transaction in RLP format,
+// the typed transactions being the bytes of their envelope, as in the lists of a block
 func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		envelope, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		// the envelope is decoded with its own parser, not to overwrite the values of the enclosing list
+		return t.UnmarshalRLP(append([]byte{}, envelope...))
+	}
+
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
 	}
 
+	t.Type = LegacyTx
+
 	if len(elems) < 9 {
 		return fmt.Errorf("incorrect number of elements to decode transaction, expected 9 but found %d", len(elems))
 	}
@@ -356,3 +421,89 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 
 	return nil
 }
+
+// unmarshalDynamicFeeRLPFrom unmarshals the payload of the dynamic fee transaction envelope (EIP-1559)
+func (t *Transaction) unmarshalDynamicFeeRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 12 {
+		return fmt.Errorf("incorrect number of elements to decode transaction, expected 12 but found %d", len(elems))
+	}
+
+	t.Type = DynamicFeeTx
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// gasTipCap
+	t.GasTipCap = new(big.Int)
+	if err := elems[2].GetBigInt(t.GasTipCap); err != nil {
+		return err
+	}
+	// gasFeeCap
+	t.GasFeeCap = new(big.Int)
+	if err := elems[3].GetBigInt(t.GasFeeCap); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[4].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	if vv, _ := elems[5].Bytes(); len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[6].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[7].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// accessList
+	accessList, err := elems[8].GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(accessList) != 0 {
+		return ErrAccessListNotSupported
+	}
+
+	// V
+	t.V = new(big.Int)
+	if err = elems[9].GetBigInt(t.V); err != nil {
+		return err
+	}
+	// R
+	t.R = new(big.Int)
+	if err = elems[10].GetBigInt(t.R); err != nil {
+		return err
+	}
+	// S
+	t.S = new(big.Int)
+	if err = elems[11].GetBigInt(t.S); err != nil {
+		return err
+	}
+
+	// the fee cap stands for the gas price, the highest price the transaction pays
+	t.GasPrice = new(big.Int).Set(t.GasFeeCap)
+
+	return nil
+}
@@ -1,12 +1,46 @@
 package types
 
 import (
+	"errors"
 	"math/big"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
 )
 
+var (
+	// ErrTxTypeNotSupported is returned when decoding a typed transaction envelope (EIP-2718)
+	// of a type other than the dynamic fee one
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+
+	// ErrAccessListNotSupported is returned when decoding a dynamic fee transaction with a non-empty access list
+	ErrAccessListNotSupported = errors.New("access lists are not supported")
+)
+
+// TxType is the type of a transaction, the legacy transactions having no envelope
+type TxType byte
+
+const (
+	LegacyTx TxType = 0x00
+
+	// DynamicFeeTx is the EIP-1559 transaction, paying the base fee of the block and a tip
+	DynamicFeeTx TxType = 0x02
+)
+
+// maxTxEnvelopeType is the highest type of a typed transaction envelope,
+// the legacy transactions being RLP lists, which start with 0xc0 or above
+const maxTxEnvelopeType = 0x7f
+
+// TxEnvelopeType returns the type of the typed transaction envelope,
+// or false if the raw transaction is a legacy one
+func TxEnvelopeType(raw []byte) (byte, bool) {
+	if len(raw) == 0 || raw[0] > maxTxEnvelopeType {
+		return 0, false
+	}
+
+	return raw[0], true
+}
+
 type Transaction struct {
 	Nonce    uint64
 	GasPrice *big.Int
@@ -20,6 +54,13 @@ type Transaction struct {
 	Hash     Hash
 	From     Address
 
+	// The dynamic fee transaction fields (EIP-1559). The gas price of the decoded
+	// dynamic fee transactions is their fee cap, which isn't part of their encoding
+	Type      TxType
+	ChainID   *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
 	// Cache
 	size atomic.Value
 }
@@ -28,8 +69,14 @@ func (t *Transaction) IsContractCreation() bool {
 	return t.To == nil
 }
 
-// ComputeHash computes the hash of the transaction
+// ComputeHash computes the hash of the transaction, the hash of its envelope for the typed ones
 func (t *Transaction) ComputeHash() *Transaction {
+	if t.Type != LegacyTx {
+		copy(t.Hash[:], keccak.Keccak256(nil, t.MarshalRLP()))
+
+		return t
+	}
+
 	ar := marshalArenaPool.Get()
 	hash := keccak.DefaultKeccakPool.Get()
 
@@ -56,6 +103,18 @@ func (t *Transaction) Copy() *Transaction {
 		tt.Value.Set(t.Value)
 	}
 
+	if t.ChainID != nil {
+		tt.ChainID = new(big.Int).Set(t.ChainID)
+	}
+
+	if t.GasTipCap != nil {
+		tt.GasTipCap = new(big.Int).Set(t.GasTipCap)
+	}
+
+	if t.GasFeeCap != nil {
+		tt.GasFeeCap = new(big.Int).Set(t.GasFeeCap)
+	}
+
 	if t.R != nil {
 		tt.R = new(big.Int)
 		tt.R = big.NewInt(0).SetBits(t.R.Bits())
@@ -72,14 +131,45 @@ func (t *Transaction) Copy() *Transaction {
 	return tt
 }
 
-// Cost returns gas * gasPrice + value
+// Cost returns gas * gasPrice + value, the fee cap standing for the gas price of the dynamic fee transactions
 func (t *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(t.GasPrice, new(big.Int).SetUint64(t.Gas))
+	total := new(big.Int).Mul(t.MaxGasPrice(), new(big.Int).SetUint64(t.Gas))
 	total.Add(total, t.Value)
 
 	return total
 }
 
+// MaxGasPrice returns the highest price the transaction pays for its gas,
+// the gas price of the legacy transactions and the fee cap of the dynamic fee ones
+func (t *Transaction) MaxGasPrice() *big.Int {
+	if t.Type == DynamicFeeTx {
+		return t.GasFeeCap
+	}
+
+	return t.GasPrice
+}
+
+// EffectiveGasPrice returns the price the transaction pays for its gas in a block of the given base fee,
+// the base fee plus the tip capped by the fee cap for the dynamic fee transactions
+func (t *Transaction) EffectiveGasPrice(baseFee uint64) *big.Int {
+	if t.Type != DynamicFeeTx {
+		return new(big.Int).Set(t.GasPrice)
+	}
+
+	price := new(big.Int).Add(t.GasTipCap, new(big.Int).SetUint64(baseFee))
+	if price.Cmp(t.GasFeeCap) > 0 {
+		price.Set(t.GasFeeCap)
+	}
+
+	return price
+}
+
+// EffectiveTip returns the tip the transaction pays per gas to the block creator in a block of the given base fee,
+// negative if the transaction can't pay the base fee
+func (t *Transaction) EffectiveTip(baseFee uint64) *big.Int {
+	return new(big.Int).Sub(t.EffectiveGasPrice(baseFee), new(big.Int).SetUint64(baseFee))
+}
+
 func (t *Transaction) Size() uint64 {
 	if size := t.size.Load(); size != nil {
 		sizeVal, ok := size.(uint64)
@@ -101,5 +191,5 @@ func (t *Transaction) ExceedsBlockGasLimit(blockGasLimit uint64) bool {
 }
 
 func (t *Transaction) IsUnderpriced(priceLimit uint64) bool {
-	return t.GasPrice.Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
+	return t.MaxGasPrice().Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
 }